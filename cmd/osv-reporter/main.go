@@ -178,7 +178,7 @@ func run(args []string, stdout, stderr io.Writer) int {
 					}
 					termWidth = 0
 
-					if errPrint := reporter.PrintResult(&diffVulns, format, writer, termWidth, showAllVulns); errPrint != nil {
+					if errPrint := reporter.PrintDiffResult(&newVulns, &diffVulns, format, writer, termWidth, showAllVulns); errPrint != nil {
 						return fmt.Errorf("failed to write output: %w", errPrint)
 					}
 				}
@@ -0,0 +1,183 @@
+// Package db implements the `db` command for osv-scanner, which manages the
+// local OSV databases used by offline scans.
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/localmatcher"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/version"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
+	"github.com/urfave/cli/v3"
+)
+
+const userAgent = "osv-scanner_db/" + version.OSVVersion
+
+func localDBPathFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "local-db-path",
+		Usage: "sets the path that local databases should be stored",
+	}
+}
+
+func Command(stdout, _ io.Writer, _ *http.Client) *cli.Command {
+	return &cli.Command{
+		Name:  "db",
+		Usage: "manages the local OSV databases used by --offline-vulnerabilities",
+		Commands: []*cli.Command{
+			{
+				Name:      "download",
+				Usage:     "downloads the OSV databases of the given ecosystems",
+				ArgsUsage: "<ecosystem> [<ecosystem>...]",
+				Flags:     []cli.Flag{localDBPathFlag()},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if cmd.NArg() == 0 {
+						return errors.New("at least one ecosystem must be given, e.g. `osv-scanner db download npm PyPI`")
+					}
+
+					return download(ctx, stdout, cmd.String("local-db-path"), toEcosystems(cmd.Args().Slice()))
+				},
+			},
+			{
+				Name:      "update",
+				Usage:     "updates the given local OSV databases, or all of them if none are given",
+				ArgsUsage: "[<ecosystem>...]",
+				Flags:     []cli.Flag{localDBPathFlag()},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					ecosystems := toEcosystems(cmd.Args().Slice())
+					if len(ecosystems) == 0 {
+						stored, err := localmatcher.Stored(cmd.String("local-db-path"))
+						if err != nil {
+							return err
+						}
+						if len(stored) == 0 {
+							return errors.New("there are no local databases to update, use `osv-scanner db download` to download some")
+						}
+						for _, db := range stored {
+							ecosystems = append(ecosystems, osvconstants.Ecosystem(db.Ecosystem))
+						}
+					}
+
+					return download(ctx, stdout, cmd.String("local-db-path"), ecosystems)
+				},
+			},
+			{
+				Name:  "status",
+				Usage: "lists the local OSV databases, when they were last updated, and whether they are intact",
+				Flags: []cli.Flag{localDBPathFlag()},
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					stored, err := localmatcher.Stored(cmd.String("local-db-path"))
+					if err != nil {
+						return err
+					}
+
+					return printStatus(stdout, stored, time.Now())
+				},
+			},
+		},
+	}
+}
+
+func toEcosystems(args []string) []osvconstants.Ecosystem {
+	ecosystems := make([]osvconstants.Ecosystem, 0, len(args))
+	for _, arg := range args {
+		ecosystems = append(ecosystems, osvconstants.Ecosystem(arg))
+	}
+
+	return ecosystems
+}
+
+func download(ctx context.Context, stdout io.Writer, localDBPath string, ecosystems []osvconstants.Ecosystem) error {
+	stored, err := localmatcher.Download(ctx, localDBPath, userAgent, ecosystems)
+	for _, db := range stored {
+		cmdlogger.Infof("Stored %s database at %s", db.Ecosystem, db.Path)
+	}
+	if err != nil {
+		return err
+	}
+
+	return printStatus(stdout, stored, time.Now())
+}
+
+// printStatus writes a table of the given databases, returning an error if
+// any of them are no longer intact.
+func printStatus(stdout io.Writer, stored []localmatcher.StoredDB, now time.Time) error {
+	if len(stored) == 0 {
+		fmt.Fprintln(stdout, "No local databases found")
+
+		return nil
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ECOSYSTEM\tUPDATED\tAGE\tSIZE\tSHA256\tSTATUS")
+
+	var corrupted []string
+	for _, db := range stored {
+		status := "ok"
+		switch {
+		case db.SHA256 == "":
+			status = "no checksum"
+		case !db.Intact:
+			status = "checksum mismatch"
+			corrupted = append(corrupted, db.Ecosystem)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			db.Ecosystem,
+			db.UpdatedAt.UTC().Format(time.DateTime),
+			formatAge(now.Sub(db.UpdatedAt)),
+			formatSize(db.Size),
+			shortChecksum(db.SHA256),
+			status,
+		)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(corrupted) > 0 {
+		return fmt.Errorf("local databases do not match their checksums: %v, run `osv-scanner db download` to download them again", corrupted)
+	}
+
+	return nil
+}
+
+func formatAge(age time.Duration) string {
+	days := int(age.Hours() / 24)
+	switch {
+	case days == 1:
+		return "1 day"
+	case days > 1:
+		return fmt.Sprintf("%d days", days)
+	default:
+		return fmt.Sprintf("%d hours", int(age.Hours()))
+	}
+}
+
+func formatSize(size int64) string {
+	const mib = 1 << 20
+	if size < mib {
+		return fmt.Sprintf("%.1f KiB", float64(size)/(1<<10))
+	}
+
+	return fmt.Sprintf("%.1f MiB", float64(size)/mib)
+}
+
+func shortChecksum(checksum string) string {
+	if len(checksum) > 12 {
+		return checksum[:12]
+	}
+	if checksum == "" {
+		return "-"
+	}
+
+	return checksum
+}
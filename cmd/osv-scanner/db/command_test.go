@@ -0,0 +1,51 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/v2/cmd/osv-scanner/internal/testcmd"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+)
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		exit int
+	}{
+		{
+			name: "download_without_ecosystems",
+			args: []string{"download"},
+			exit: 127,
+		},
+		{
+			name: "update_without_databases",
+			args: []string{"update"},
+			exit: 127,
+		},
+		{
+			name: "status_without_databases",
+			args: []string{"status"},
+			exit: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			testDir := testutility.CreateTestDir(t)
+
+			args := append([]string{"", "db"}, tt.args...)
+			args = append(args, "--local-db-path", testDir)
+
+			testcmd.RunAndMatchSnapshots(t, testcmd.Case{
+				Name: tt.name,
+				Args: args,
+				Exit: tt.exit,
+			})
+		})
+	}
+}
@@ -10,15 +10,19 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"deps.dev/util/resolve"
+	"deps.dev/util/semver"
 	"github.com/google/osv-scalibr/inventory/osvecosystem"
 	"github.com/google/osv-scanner/v2/internal/apiconfig"
 	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/localmatcher"
 	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/osvmatcher"
+	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/config"
 	"github.com/google/osv-scanner/v2/internal/depsdev"
 	"github.com/google/osv-scanner/v2/internal/remediation"
 	"github.com/google/osv-scanner/v2/internal/remediation/upgrade"
@@ -28,6 +32,7 @@ import (
 	"github.com/google/osv-scanner/v2/internal/resolution/manifest"
 	"github.com/google/osv-scanner/v2/internal/resolution/util"
 	"github.com/google/osv-scanner/v2/internal/version"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
 	"github.com/urfave/cli/v3"
 	"golang.org/x/term"
 	"osv.dev/bindings/go/osvdev"
@@ -61,6 +66,8 @@ type osvFixOptions struct {
 	OutputJSON  bool
 	Stdout      io.Writer
 	Stderr      io.Writer
+	Patch       io.Writer // if set, changes are written to it as a unified diff instead of to the files
+	DryRun      bool      // if set, the impact of each candidate patch is reported and no files are modified
 }
 
 func Command(stdout, stderr io.Writer, _ *http.Client) *cli.Command {
@@ -164,6 +171,24 @@ func Command(stdout, stderr io.Writer, _ *http.Client) *cli.Command {
 				Usage:    "apply the top N patches",
 				Value:    -1,
 			},
+			&cli.StringFlag{
+				Category: autoModeCategory,
+				Name:     "fix-output",
+				Usage:    "how to apply the changes; value can be: write (modify the files), patch (print the changes to stdout as a unified diff, leaving the files untouched)",
+				Value:    fixOutputWrite,
+				Action: func(_ context.Context, _ *cli.Command, s string) error {
+					if !slices.Contains(fixOutputs, s) {
+						return fmt.Errorf("unsupported fix-output \"%s\" - must be one of: %s", s, strings.Join(fixOutputs, ", "))
+					}
+
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Category: autoModeCategory,
+				Name:     "dry-run",
+				Usage:    "report how many vulnerabilities each candidate patch fixes, how many new packages it adds, and whether it crosses a major version, without modifying any files",
+			},
 			&cli.BoolFlag{
 				Category: autoModeCategory,
 				Name:     "no-introduce",
@@ -176,6 +201,12 @@ func Command(stdout, stderr io.Writer, _ *http.Client) *cli.Command {
 				Usage:       "the allowed package upgrades, in the format `[package-name:]level`. If package-name is omitted, level is applied to all packages. level must be one of (major, minor, patch, none).",
 				DefaultText: "major",
 			},
+			&cli.StringFlag{
+				Category:  upgradeCategory,
+				Name:      "config",
+				Usage:     "set/override config file, whose [[UpgradeConstraints]] restrict the versions packages can be upgraded to",
+				TakesFile: true,
+			},
 			&cli.IntFlag{
 				Category: vulnCategory,
 				Name:     "max-depth",
@@ -251,11 +282,51 @@ func action(ctx context.Context, cmd *cli.Command, stdout, stderr io.Writer) err
 		Manifest:    cmd.String("manifest"),
 		Lockfile:    cmd.String("lockfile"),
 		NoIntroduce: cmd.Bool("no-introduce"),
+		DryRun:      cmd.Bool("dry-run"),
 		OutputJSON:  cmd.String("format") == "json",
 		Stdout:      stdout,
 		Stderr:      stderr,
 	}
 
+	if opts.DryRun {
+		if cmd.Bool("interactive") {
+			return errors.New("--dry-run is not supported in interactive mode")
+		}
+		if cmd.String("fix-output") == fixOutputPatch {
+			return errors.New("--dry-run cannot be used with --fix-output=patch")
+		}
+	}
+
+	if cmd.String("fix-output") == fixOutputPatch {
+		if cmd.Bool("interactive") {
+			return errors.New("--fix-output=patch is not supported in interactive mode")
+		}
+		// The patch is the only thing written to stdout, so that it can be redirected to a file or piped to `git apply`.
+		cmdlogger.SendEverythingToStderr()
+		opts.Patch = stdout
+		opts.Stdout = stderr
+	}
+
+	if filepath.Base(opts.Manifest) == "go.mod" {
+		allowed, err := allowedVersions(cmd, opts, osvconstants.EcosystemGo, semver.Go)
+		if err != nil {
+			return err
+		}
+		opts.AllowedVersions = allowed
+
+		return goModAction(ctx, cmd, opts)
+	}
+
+	gradleFile := isGradleFile(opts.Manifest)
+	if gradleFile {
+		if cmd.IsSet("strategy") {
+			return errors.New("--strategy is not supported for Gradle files, vulnerable versions are always bumped in place")
+		}
+		if cmd.Bool("interactive") {
+			return errors.New("interactive mode is not supported for Gradle files")
+		}
+	}
+
 	system := resolve.UnknownSystem
 	if opts.Lockfile != "" {
 		rw, err := lockfile.GetReadWriter(opts.Lockfile)
@@ -266,7 +337,10 @@ func action(ctx context.Context, cmd *cli.Command, stdout, stderr io.Writer) err
 		system = rw.System()
 	}
 
-	if opts.Manifest != "" {
+	if gradleFile {
+		// Gradle files are not resolved, only the versions they declare are bumped.
+		system = resolve.Maven
+	} else if opts.Manifest != "" {
 		rw, err := manifest.GetReadWriter(opts.Manifest, cmd.String("maven-registry"))
 		if err != nil {
 			return err
@@ -277,14 +351,17 @@ func action(ctx context.Context, cmd *cli.Command, stdout, stderr io.Writer) err
 		system = rw.System()
 	}
 
-	switch cmd.String("data-source") {
-	case "deps.dev":
+	switch {
+	case system == resolve.PyPI:
+		// deps.dev does not provide the dependencies of PyPI packages, always use the registry.
+		opts.Client.DependencyClient = client.NewPyPIRegistryClient()
+	case cmd.String("data-source") == "deps.dev":
 		cl, err := client.NewDepsDevClient(depsdev.DepsdevAPI, "osv-scanner_fix/"+version.OSVVersion)
 		if err != nil {
 			return err
 		}
 		opts.Client.DependencyClient = cl
-	case "native":
+	case cmd.String("data-source") == "native":
 		switch system {
 		case resolve.NPM:
 			var workDir string
@@ -312,38 +389,24 @@ func action(ctx context.Context, cmd *cli.Command, stdout, stderr io.Writer) err
 		}
 	}
 
-	userAgent := "osv-scanner_fix/" + version.OSVVersion
-	if cmd.Bool("offline-vulnerabilities") {
-		matcher, err := localmatcher.NewLocalMatcher(
-			cmd.String("local-db-path"),
-			userAgent,
-			cmd.Bool("download-offline-databases"),
-		)
-		if err != nil {
-			return err
-		}
-
-		eco, ok := util.OSVEcosystem[system]
-		if !ok {
-			// Something's very wrong if we hit this
-			panic("unhandled resolve.Ecosystem: " + system.String())
-		}
-		if err := matcher.LoadEcosystem(ctx, osvecosystem.Parsed{Ecosystem: eco}); err != nil {
-			return err
-		}
+	eco, ok := util.OSVEcosystem[system]
+	if !ok {
+		// Something's very wrong if we hit this
+		panic("unhandled resolve.Ecosystem: " + system.String())
+	}
+	allowed, err := allowedVersions(cmd, opts, eco, system.Semver())
+	if err != nil {
+		return err
+	}
+	opts.AllowedVersions = allowed
+	matcher, err := newVulnerabilityMatcher(ctx, cmd, eco)
+	if err != nil {
+		return err
+	}
+	opts.Client.VulnerabilityMatcher = matcher
 
-		opts.Client.VulnerabilityMatcher = matcher
-	} else {
-		config := osvdev.DefaultConfig()
-		config.UserAgent = userAgent
-		opts.Client.VulnerabilityMatcher = &osvmatcher.CachedOSVMatcher{
-			Client: osvdev.OSVClient{
-				HTTPClient:  http.DefaultClient,
-				Config:      config,
-				BaseHostURL: apiconfig.CodexSecurityBaseURL,
-			},
-			InitialQueryTimeout: 5 * time.Minute,
-		}
+	if gradleFile {
+		return autoGradle(ctx, opts, cmd.Int("apply-top"))
 	}
 
 	if cmd.Bool("interactive") {
@@ -383,3 +446,64 @@ func action(ctx context.Context, cmd *cli.Command, stdout, stderr io.Writer) err
 		panic(fmt.Sprintf("non-interactive mode attempted to run with unhandled strategy: \"%s\"", cmd.String("strategy")))
 	}
 }
+
+// allowedVersions loads the upgrade constraints of the ecosystem from the config file
+// that applies to the manifest or lockfile, and checks that their version ranges are valid.
+func allowedVersions(cmd *cli.Command, opts osvFixOptions, eco osvconstants.Ecosystem, sys semver.System) (map[string]string, error) {
+	manager := config.Manager{
+		DefaultConfig: config.Config{},
+		ConfigMap:     make(map[string]config.Config),
+	}
+	if cmd.IsSet("config") {
+		if err := manager.UseOverride(cmd.String("config")); err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+
+	target := opts.Manifest
+	if target == "" {
+		target = opts.Lockfile
+	}
+	cfg := manager.Get(target)
+	allowed := cfg.AllowedVersions(string(eco))
+	for name, versions := range allowed {
+		if _, err := remediation.ParseAllowedVersion(sys, versions); err != nil {
+			return nil, fmt.Errorf("upgrade constraint for %s: %w", name, err)
+		}
+	}
+
+	return allowed, nil
+}
+
+func newVulnerabilityMatcher(ctx context.Context, cmd *cli.Command, eco osvconstants.Ecosystem) (clientinterfaces.VulnerabilityMatcher, error) {
+	userAgent := "osv-scanner_fix/" + version.OSVVersion
+	if cmd.Bool("offline-vulnerabilities") {
+		matcher, err := localmatcher.NewLocalMatcher(
+			cmd.String("local-db-path"),
+			userAgent,
+			cmd.Bool("download-offline-databases"),
+			0,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := matcher.LoadEcosystem(ctx, osvecosystem.Parsed{Ecosystem: eco}); err != nil {
+			return nil, err
+		}
+
+		return matcher, nil
+	}
+
+	config := osvdev.DefaultConfig()
+	config.UserAgent = userAgent
+
+	return &osvmatcher.CachedOSVMatcher{
+		Client: osvdev.OSVClient{
+			HTTPClient:  http.DefaultClient,
+			Config:      config,
+			BaseHostURL: apiconfig.CodexSecurityBaseURL,
+		},
+		InitialQueryTimeout: 5 * time.Minute,
+	}, nil
+}
@@ -83,6 +83,21 @@ func TestCommand(t *testing.T) {
 			Args: []string{"", "fix", "--format=yaml"},
 			Exit: 127,
 		},
+		{
+			Name: "errors_with_unsupported_fix_output",
+			Args: []string{"", "fix", "--fix-output=pr"},
+			Exit: 127,
+		},
+		{
+			Name: "errors_when_dry_run_used_with_patch_output",
+			Args: []string{"", "fix", "--dry-run", "--fix-output=patch", "-M", "./testdata/relax-npm/package.json"},
+			Exit: 127,
+		},
+		{
+			Name: "errors_when_strategy_used_with_gradle",
+			Args: []string{"", "fix", "--strategy=override", "-M", "./testdata/gradle/libs.versions.toml"},
+			Exit: 127,
+		},
 		{
 			Name: "errors_with_unsupported_strategy",
 			Args: []string{"", "fix", "--strategy=force"},
@@ -0,0 +1,179 @@
+package fix
+
+import (
+	"context"
+	"slices"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/v2/internal/remediation"
+	"github.com/google/osv-scanner/v2/internal/resolution"
+	"golang.org/x/mod/modfile"
+)
+
+// relockImpact describes the impact of each of the relax or override patches.
+func relockImpact(diffs []resolution.Difference) []impactOutput {
+	candidates := make([]impactOutput, 0, len(diffs))
+	for _, diff := range diffs {
+		c := impactOutput{
+			Fixed:       len(diff.RemovedVulns),
+			NewPackages: diff.GraphChanges().Added,
+		}
+		for _, dp := range diff.Deps {
+			direct := slices.ContainsFunc(diff.Original.Manifest.Requirements, func(req resolve.RequirementVersion) bool {
+				return req.PackageKey == dp.Pkg && !req.Type.HasAttr(dep.MavenDependencyOrigin)
+			})
+			c.PackageUpdates = append(c.PackageUpdates, updatePackageOutput{
+				Name:        dp.Pkg.Name,
+				VersionFrom: dp.OrigResolved,
+				VersionTo:   dp.NewResolved,
+				Transitive:  !direct,
+			})
+			c.Major = c.Major || crossesMajor(dp.Pkg.System.Semver(), dp.OrigResolved, dp.NewResolved)
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates
+}
+
+// inPlaceImpact describes the impact of each of the in-place patches.
+// In-place patches are only possible if the existing packages in the lockfile satisfy
+// the dependencies of the new version, so they never add new packages.
+func inPlaceImpact(res remediation.InPlaceResult) []impactOutput {
+	candidates := make([]impactOutput, 0, len(res.Patches))
+	for _, p := range res.Patches {
+		candidates = append(candidates, impactOutput{
+			PackageUpdates: []updatePackageOutput{{
+				Name:        p.Pkg.Name,
+				VersionFrom: p.OrigVersion,
+				VersionTo:   p.NewVersion,
+				Transitive:  true,
+			}},
+			Fixed: len(p.ResolvedVulns),
+			Major: crossesMajor(p.Pkg.System.Semver(), p.OrigVersion, p.NewVersion),
+		})
+	}
+
+	return candidates
+}
+
+// goModImpact describes the impact of each of the go.mod requirement bumps.
+// The new packages are the modules required by the new version that the go.mod does not already require,
+// which `go get` would add to the go.mod.
+func goModImpact(ctx context.Context, src goModuleSource, f *modfile.File, res remediation.GoModResult) ([]impactOutput, error) {
+	required := make(map[string]bool)
+	for _, r := range f.Require {
+		required[r.Mod.Path] = true
+	}
+
+	patches := sortGoModPatches(res.Patches)
+	candidates := make([]impactOutput, 0, len(patches))
+	for _, p := range patches {
+		data, err := src.GoMod(ctx, p.Module, p.NewVersion)
+		if err != nil {
+			return nil, err
+		}
+		modFile, err := modfile.ParseLax(p.Module+"@"+p.NewVersion+"/go.mod", data, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		c := impactOutput{
+			PackageUpdates: []updatePackageOutput{{
+				Name:        p.Module,
+				VersionFrom: p.Version,
+				VersionTo:   p.NewVersion,
+				Transitive:  p.Indirect,
+			}},
+			Fixed: len(p.Fixed),
+			Major: crossesMajor(semver.Go, p.Version, p.NewVersion),
+		}
+		for _, r := range modFile.Require {
+			if !required[r.Mod.Path] && (f.Module == nil || r.Mod.Path != f.Module.Mod.Path) {
+				c.NewPackages++
+			}
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, nil
+}
+
+// gradleImpact describes the impact of each of the Gradle version bumps.
+// The dependency graph of Gradle projects is not resolved, so the new packages are the runtime dependencies
+// of the upgraded dependencies that they did not previously depend on.
+func gradleImpact(ctx context.Context, cl resolve.Client, res remediation.GradleResult) ([]impactOutput, error) {
+	candidates := make([]impactOutput, 0, len(res.Patches))
+	for _, p := range res.Patches {
+		c := impactOutput{
+			Fixed: len(p.Fixed),
+			Major: crossesMajor(semver.Maven, p.Version, p.NewVersion),
+		}
+		for _, name := range p.Dependencies {
+			c.PackageUpdates = append(c.PackageUpdates, updatePackageOutput{
+				Name:        name,
+				VersionFrom: p.Version,
+				VersionTo:   p.NewVersion,
+			})
+
+			before, err := mavenRuntimeDependencies(ctx, cl, name, p.Version)
+			if err != nil {
+				return nil, err
+			}
+			after, err := mavenRuntimeDependencies(ctx, cl, name, p.NewVersion)
+			if err != nil {
+				return nil, err
+			}
+			for _, pk := range after {
+				if !slices.Contains(before, pk) {
+					c.NewPackages++
+				}
+			}
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, nil
+}
+
+// mavenRuntimeDependencies returns the non-optional dependencies of a Maven package that are needed at runtime.
+func mavenRuntimeDependencies(ctx context.Context, cl resolve.Client, name, version string) ([]resolve.PackageKey, error) {
+	reqs, err := cl.Requirements(ctx, resolve.VersionKey{
+		PackageKey:  resolve.PackageKey{System: resolve.Maven, Name: name},
+		Version:     version,
+		VersionType: resolve.Concrete,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pks []resolve.PackageKey
+	for _, r := range reqs {
+		if r.Type.HasAttr(dep.Opt) {
+			continue
+		}
+		if scope, _ := r.Type.GetAttr(dep.Scope); scope == "test" || scope == "provided" || scope == "import" {
+			continue
+		}
+		pks = append(pks, r.PackageKey)
+	}
+
+	return pks, nil
+}
+
+// crossesMajor reports whether upgrading from one version to another changes the major version.
+func crossesMajor(sys semver.System, from, to string) bool {
+	fromVer, err := sys.Parse(from)
+	if err != nil {
+		return false
+	}
+	toVer, err := sys.Parse(to)
+	if err != nil {
+		return false
+	}
+	_, diff := fromVer.Difference(toVer)
+
+	return diff == semver.DiffMajor
+}
@@ -0,0 +1,127 @@
+package fix
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"deps.dev/util/semver"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/remediation"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"golang.org/x/mod/modfile"
+)
+
+// fakeGoModuleSource serves go.mod files keyed by module@version.
+type fakeGoModuleSource map[string]string
+
+func (fakeGoModuleSource) Versions(context.Context, string) ([]string, error) {
+	return nil, nil
+}
+
+func (s fakeGoModuleSource) GoMod(_ context.Context, modulePath, version string) ([]byte, error) {
+	data, ok := s[modulePath+"@"+version]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+
+	return []byte(data), nil
+}
+
+func Test_goModImpact(t *testing.T) {
+	t.Parallel()
+
+	filename := "testdata/go-mod/go.mod"
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("could not read test file: %v", err)
+	}
+	f, err := modfile.Parse(filename, data, nil)
+	if err != nil {
+		t.Fatalf("could not parse test file: %v", err)
+	}
+
+	src := fakeGoModuleSource{
+		"github.com/gin-gonic/gin@v1.9.1": `module github.com/gin-gonic/gin
+
+require (
+	github.com/bytedance/sonic v1.9.1
+	github.com/go-playground/validator/v10 v10.14.0
+	golang.org/x/net v0.10.0
+)
+`,
+		"golang.org/x/crypto@v1.0.0": `module golang.org/x/crypto
+
+require (
+	golang.org/x/sys v0.13.0
+	example.com/app v0.1.0
+)
+`,
+	}
+
+	vuln := func(id string) *osvschema.Vulnerability { return &osvschema.Vulnerability{Id: id} }
+	res := remediation.GoModResult{
+		Patches: []remediation.GoModPatch{
+			{
+				GoModRequirement: remediation.GoModRequirement{Module: "github.com/gin-gonic/gin", Version: "v1.9.0"},
+				NewVersion:       "v1.9.1",
+				Fixed:            []*osvschema.Vulnerability{vuln("GO-2023-1737")},
+			},
+			{
+				GoModRequirement: remediation.GoModRequirement{Module: "golang.org/x/crypto", Version: "v0.14.0", Indirect: true},
+				NewVersion:       "v1.0.0",
+				Fixed:            []*osvschema.Vulnerability{vuln("GO-2023-2402"), vuln("GO-2025-3487")},
+			},
+		},
+	}
+
+	got, err := goModImpact(t.Context(), src, f, res)
+	if err != nil {
+		t.Fatalf("goModImpact() error: %v", err)
+	}
+	want := []impactOutput{
+		{
+			PackageUpdates: []updatePackageOutput{{Name: "golang.org/x/crypto", VersionFrom: "v0.14.0", VersionTo: "v1.0.0", Transitive: true}},
+			Fixed:          2,
+			NewPackages:    0, // the requirement on the main module is not a new package
+			Major:          true,
+		},
+		{
+			PackageUpdates: []updatePackageOutput{{Name: "github.com/gin-gonic/gin", VersionFrom: "v1.9.0", VersionTo: "v1.9.1"}},
+			Fixed:          1,
+			NewPackages:    1,
+			Major:          false,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("goModImpact() mismatch (-want +got):\n%s", diff)
+	}
+
+	res.Patches[0].NewVersion = "v1.9.2"
+	if _, err := goModImpact(t.Context(), src, f, res); err == nil {
+		t.Errorf("goModImpact() with an unknown go.mod returned no error")
+	}
+}
+
+func Test_crossesMajor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		sys      semver.System
+		from, to string
+		want     bool
+	}{
+		{semver.NPM, "1.2.3", "1.3.0", false},
+		{semver.NPM, "1.2.3", "2.0.0", true},
+		{semver.Maven, "2.9.10", "2.12.7.1", false},
+		{semver.Maven, "5.3.31", "6.1.14", true},
+		{semver.Go, "v0.14.0", "v0.17.0", false},
+		{semver.NPM, "", "2.0.0", false}, // unparsable versions are not reported as major
+	}
+	for _, tt := range tests {
+		if got := crossesMajor(tt.sys, tt.from, tt.to); got != tt.want {
+			t.Errorf("crossesMajor(%s, %s, %s) = %v, want %v", tt.sys, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package fix
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/datasource"
+	"github.com/google/osv-scanner/v2/internal/remediation"
+	"github.com/google/osv-scanner/v2/internal/utility/unifieddiff"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/mod/modfile"
+)
+
+// strategyGoGet bumps the vulnerable modules required by a go.mod with `go get`,
+// letting minimal version selection update the rest of the go.mod and the go.sum.
+const strategyGoGet strategy = "go-get"
+
+func goModAction(ctx context.Context, cmd *cli.Command, opts osvFixOptions) error {
+	if cmd.IsSet("strategy") {
+		return errors.New("--strategy is not supported for go.mod files, vulnerable modules are always upgraded with `go get`")
+	}
+	if cmd.Bool("interactive") {
+		return errors.New("interactive mode is not supported for go.mod files")
+	}
+
+	matcher, err := newVulnerabilityMatcher(ctx, cmd, osvconstants.EcosystemGo)
+	if err != nil {
+		return err
+	}
+	opts.Client.VulnerabilityMatcher = matcher
+
+	return autoGoMod(ctx, opts, datasource.NewGoProxyAPIClient(""), cmd.Int("apply-top"))
+}
+
+// goModuleSource lists the versions of Go modules and fetches their go.mod files.
+type goModuleSource interface {
+	remediation.GoModuleVersionLister
+	GoMod(ctx context.Context, modulePath, version string) ([]byte, error)
+}
+
+// goModRequirements lists the modules required by the go.mod of a module version.
+func goModRequirements(src goModuleSource) requirementsFunc {
+	return func(ctx context.Context, modulePath, version string) ([]resolve.RequirementVersion, error) {
+		data, err := src.GoMod(ctx, modulePath, version)
+		if err != nil {
+			return nil, err
+		}
+		f, err := modfile.ParseLax(modulePath+"@"+version+"/go.mod", data, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		reqs := make([]resolve.RequirementVersion, len(f.Require))
+		for i, r := range f.Require {
+			reqs[i] = resolve.RequirementVersion{
+				VersionKey: resolve.VersionKey{
+					PackageKey:  resolve.PackageKey{Name: r.Mod.Path},
+					Version:     r.Mod.Version,
+					VersionType: resolve.Requirement,
+				},
+			}
+		}
+
+		return reqs, nil
+	}
+}
+
+func autoGoMod(ctx context.Context, opts osvFixOptions, src goModuleSource, maxUpgrades int) error {
+	data, err := os.ReadFile(opts.Manifest)
+	if err != nil {
+		return err
+	}
+	f, err := modfile.Parse(opts.Manifest, data, nil)
+	if err != nil {
+		return err
+	}
+
+	cmdlogger.Infof("Scanning %s...", opts.Manifest)
+	res, err := remediation.ComputeGoModPatches(ctx, opts.Client.VulnerabilityMatcher, src, f, opts.Options)
+	if err != nil {
+		return err
+	}
+
+	outputResult := fixOutput{
+		Path:      opts.Manifest,
+		Ecosystem: osvconstants.EcosystemGo,
+		Strategy:  strategyGoGet,
+	}
+	patches := autoChooseGoModPatches(res, maxUpgrades, &outputResult)
+
+	for _, r := range res.Remaining {
+		ids := make([]string, len(r.Vulns))
+		for i, v := range r.Vulns {
+			ids[i] = v.GetId()
+		}
+		cmdlogger.Infof("NO-FIX-AVAILABLE: %s@%s: %s", r.Module, r.Version, strings.Join(ids, ","))
+	}
+	var constrained []string
+	for _, r := range res.Constrained {
+		ids := make([]string, len(r.Vulns))
+		for i, v := range r.Vulns {
+			ids[i] = v.GetId()
+		}
+		constrained = append(constrained, ids...)
+		cmdlogger.Infof("NO-FIX-WITHIN-CONSTRAINTS: %s@%s: %s", r.Module, r.Version, strings.Join(ids, ","))
+	}
+	setConstrainedVulns(&outputResult, constrained)
+
+	if opts.DryRun {
+		outputResult.Candidates, err = goModImpact(ctx, src, f, res)
+		if err != nil {
+			return err
+		}
+	}
+
+	upgradeRisks{
+		sys:          semver.Go,
+		requirements: goModRequirements(src),
+	}.annotate(ctx, &outputResult)
+
+	if err := printResult(outputResult, opts); err != nil {
+		cmdlogger.Errorf("failed writing output")
+		return err
+	}
+
+	if len(patches) == 0 || opts.DryRun {
+		return nil
+	}
+
+	if opts.Patch != nil {
+		cmdlogger.Infof("Run `go mod tidy` after applying the patch to update the rest of %s and its go.sum", opts.Manifest)
+		return writeGoModPatch(opts, f, data, patches)
+	}
+
+	args := []string{"get"}
+	for _, p := range patches {
+		args = append(args, p.Module+"@"+p.NewVersion)
+	}
+	c := exec.CommandContext(ctx, "go", args...)
+	c.Dir = filepath.Dir(opts.Manifest)
+	c.Stdout = opts.Stdout
+	c.Stderr = opts.Stderr
+	cmdlogger.Infof("Executing `%s`...", c)
+
+	return c.Run()
+}
+
+// writeGoModPatch writes the requirement bumps to opts.Patch as a unified diff of the go.mod.
+// Unlike `go get`, this does not raise the other requirements that the new versions need, nor update the go.sum,
+// which `go mod tidy` does once the patch is applied.
+func writeGoModPatch(opts osvFixOptions, f *modfile.File, data []byte, patches []remediation.GoModPatch) error {
+	for _, p := range patches {
+		if err := f.AddRequire(p.Module, p.NewVersion); err != nil {
+			return err
+		}
+	}
+	out, err := f.Format()
+	if err != nil {
+		return err
+	}
+
+	return unifieddiff.Write(opts.Patch, patchPath(opts.Manifest), data, out)
+}
+
+// autoChooseGoModPatches returns the top {maxUpgrades} patches, prioritised by the number of vulnerabilities they fix,
+// and populates outputResult. If maxUpgrades is < 0, all the patches are returned.
+func autoChooseGoModPatches(res remediation.GoModResult, maxUpgrades int, outputResult *fixOutput) []remediation.GoModPatch {
+	vulns := make(map[string]*vulnOutput)
+	var ids []string
+	for _, r := range res.Vulnerable {
+		for _, v := range r.Vulns {
+			out, ok := vulns[v.GetId()]
+			if !ok {
+				out = &vulnOutput{ID: v.GetId(), Unactionable: true}
+				vulns[v.GetId()] = out
+				ids = append(ids, v.GetId())
+			}
+			out.Packages = append(out.Packages, packageOutput{Name: r.Module, Version: r.Version})
+		}
+	}
+
+	patches := sortGoModPatches(res.Patches)
+	if maxUpgrades >= 0 && len(patches) > maxUpgrades {
+		patches = patches[:maxUpgrades]
+	}
+
+	for _, p := range patches {
+		out := patchOutput{
+			PackageUpdates: []updatePackageOutput{{
+				Name:        p.Module,
+				VersionFrom: p.Version,
+				VersionTo:   p.NewVersion,
+				Transitive:  p.Indirect,
+			}},
+		}
+		for _, v := range p.Fixed {
+			out.Fixed = append(out.Fixed, goModVulnOutput(v, p.GoModRequirement))
+		}
+		sortVulns(out.Fixed)
+		outputResult.Patches = append(outputResult.Patches, out)
+	}
+
+	// Vulnerabilities are actionable if any possible patch fixes them, even if it was not chosen.
+	for _, p := range res.Patches {
+		for _, v := range p.Fixed {
+			vulns[v.GetId()].Unactionable = false
+		}
+	}
+
+	outputResult.Vulnerabilities = make([]vulnOutput, 0, len(ids))
+	for _, id := range ids {
+		outputResult.Vulnerabilities = append(outputResult.Vulnerabilities, *vulns[id])
+	}
+	sortVulns(outputResult.Vulnerabilities)
+
+	return patches
+}
+
+// sortGoModPatches returns a copy of the patches, sorted by the number of vulnerabilities they fix.
+func sortGoModPatches(patches []remediation.GoModPatch) []remediation.GoModPatch {
+	patches = slices.Clone(patches)
+	slices.SortStableFunc(patches, func(a, b remediation.GoModPatch) int {
+		if c := cmp.Compare(len(b.Fixed), len(a.Fixed)); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Module, b.Module)
+	})
+
+	return patches
+}
+
+func goModVulnOutput(v *osvschema.Vulnerability, r remediation.GoModRequirement) vulnOutput {
+	return vulnOutput{
+		ID:       v.GetId(),
+		Packages: []packageOutput{{Name: r.Module, Version: r.Version}},
+	}
+}
@@ -0,0 +1,184 @@
+package fix
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/remediation"
+	"github.com/google/osv-scanner/v2/internal/utility/gradle"
+	"github.com/google/osv-scanner/v2/internal/utility/unifieddiff"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// strategyBumpVersion bumps the versions declared in a Gradle version catalog or build script,
+// without resolving the project's dependency graph.
+const strategyBumpVersion strategy = "bump-version"
+
+// isGradleFile reports whether the manifest is a Gradle version catalog or build script.
+func isGradleFile(path string) bool {
+	return gradle.IsVersionCatalog(path) || gradle.IsBuildScript(path)
+}
+
+func autoGradle(ctx context.Context, opts osvFixOptions, maxUpgrades int) error {
+	data, err := os.ReadFile(opts.Manifest)
+	if err != nil {
+		return err
+	}
+	decls, err := gradle.Parse(opts.Manifest, data)
+	if err != nil {
+		return err
+	}
+
+	cmdlogger.Infof("Scanning %s...", opts.Manifest)
+	res, err := remediation.ComputeGradlePatches(ctx, opts.Client, decls, opts.Options)
+	if err != nil {
+		return err
+	}
+
+	outputResult := fixOutput{
+		Path:      opts.Manifest,
+		Ecosystem: osvconstants.EcosystemMaven,
+		Strategy:  strategyBumpVersion,
+	}
+	patches := autoChooseGradlePatches(res, maxUpgrades, &outputResult)
+
+	for _, r := range res.Remaining {
+		ids := make([]string, len(r.Vulns))
+		for i, v := range r.Vulns {
+			ids[i] = v.GetId()
+		}
+		cmdlogger.Infof("NO-FIX-AVAILABLE: %s@%s: %s", gradleVersionName(r), r.Version, strings.Join(ids, ","))
+	}
+	var constrained []string
+	for _, r := range res.Constrained {
+		ids := make([]string, len(r.Vulns))
+		for i, v := range r.Vulns {
+			ids[i] = v.GetId()
+		}
+		constrained = append(constrained, ids...)
+		cmdlogger.Infof("NO-FIX-WITHIN-CONSTRAINTS: %s@%s: %s", gradleVersionName(r), r.Version, strings.Join(ids, ","))
+	}
+	setConstrainedVulns(&outputResult, constrained)
+
+	if opts.DryRun {
+		outputResult.Candidates, err = gradleImpact(ctx, opts.Client, res)
+		if err != nil {
+			return err
+		}
+	}
+
+	upgradeRisks{
+		system:       resolve.Maven,
+		sys:          semver.Maven,
+		requirements: clientRequirements(opts, resolve.Maven),
+	}.annotate(ctx, &outputResult)
+
+	if err := printResult(outputResult, opts); err != nil {
+		cmdlogger.Errorf("failed writing output")
+		return err
+	}
+
+	if len(patches) == 0 || opts.DryRun {
+		return nil
+	}
+
+	edits := make([]gradle.Edit, len(patches))
+	for i, p := range patches {
+		edits[i] = gradle.Edit{Declaration: p.Declaration, NewVersion: p.NewVersion}
+	}
+	out, err := gradle.Rewrite(data, edits)
+	if err != nil {
+		return err
+	}
+
+	if opts.Patch != nil {
+		return unifieddiff.Write(opts.Patch, patchPath(opts.Manifest), data, out)
+	}
+
+	cmdlogger.Infof("Rewriting %s...", opts.Manifest)
+	//nolint:gosec // The file already exists, so the permissions don't matter.
+	return os.WriteFile(opts.Manifest, out, 0644)
+}
+
+// autoChooseGradlePatches returns the top {maxUpgrades} patches, which are already sorted by the number of
+// vulnerabilities they fix, and populates outputResult. If maxUpgrades is < 0, all the patches are returned.
+func autoChooseGradlePatches(res remediation.GradleResult, maxUpgrades int, outputResult *fixOutput) []remediation.GradlePatch {
+	patches := res.Patches
+	if maxUpgrades >= 0 && len(patches) > maxUpgrades {
+		patches = patches[:maxUpgrades]
+	}
+
+	for _, p := range patches {
+		var out patchOutput
+		for _, name := range p.Dependencies {
+			out.PackageUpdates = append(out.PackageUpdates, updatePackageOutput{
+				Name:        name,
+				VersionFrom: p.Version,
+				VersionTo:   p.NewVersion,
+			})
+		}
+		for _, v := range p.Fixed {
+			out.Fixed = append(out.Fixed, gradleVulnOutput(v, p.GradleRequirement))
+		}
+		sortVulns(out.Fixed)
+		outputResult.Patches = append(outputResult.Patches, out)
+	}
+
+	// Vulnerabilities are actionable if any possible patch fixes them, even if it was not chosen.
+	fixable := make(map[string]bool)
+	for _, p := range res.Patches {
+		for _, v := range p.Fixed {
+			fixable[v.GetId()] = true
+		}
+	}
+	vulns := make(map[string]*vulnOutput)
+	var ids []string
+	for _, r := range res.Vulnerable {
+		for _, v := range r.Vulns {
+			out, ok := vulns[v.GetId()]
+			if !ok {
+				out = &vulnOutput{ID: v.GetId(), Unactionable: !fixable[v.GetId()]}
+				vulns[v.GetId()] = out
+				ids = append(ids, v.GetId())
+			}
+			out.Packages = append(out.Packages, gradleVulnOutput(v, r).Packages...)
+		}
+	}
+	outputResult.Vulnerabilities = make([]vulnOutput, 0, len(ids))
+	for _, id := range ids {
+		outputResult.Vulnerabilities = append(outputResult.Vulnerabilities, *vulns[id])
+	}
+	sortVulns(outputResult.Vulnerabilities)
+
+	return patches
+}
+
+// gradleVulnOutput describes a vulnerability affecting the dependencies that use a declared version.
+func gradleVulnOutput(v *osvschema.Vulnerability, r remediation.GradleRequirement) vulnOutput {
+	out := vulnOutput{ID: v.GetId()}
+	for _, name := range r.Dependencies {
+		for _, affected := range v.GetAffected() {
+			if affected.GetPackage().GetName() == name {
+				out.Packages = append(out.Packages, packageOutput{Name: name, Version: r.Version})
+				break
+			}
+		}
+	}
+
+	return out
+}
+
+// gradleVersionName is how a declared version is referred to in the output:
+// by its name, or by the dependency it is written inline in.
+func gradleVersionName(r remediation.GradleRequirement) string {
+	if r.Name != "" {
+		return r.Name
+	}
+
+	return strings.Join(r.Dependencies, ",")
+}
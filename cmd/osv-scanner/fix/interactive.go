@@ -12,11 +12,7 @@ import (
 // Need to think about how to support this
 
 func interactiveMode(ctx context.Context, opts osvFixOptions) error {
-	if !remediation.SupportsRelax(opts.ManifestRW) && !remediation.SupportsInPlace(opts.LockfileRW) {
-		if remediation.SupportsOverride(opts.ManifestRW) {
-			return errors.New("override strategy is not supported in interactive mode")
-		}
-
+	if !remediation.SupportsRelax(opts.ManifestRW) && !remediation.SupportsOverride(opts.ManifestRW) && !remediation.SupportsInPlace(opts.LockfileRW) {
 		return errors.New("no supported remediation strategies found")
 	}
 
@@ -215,16 +215,24 @@ func doRelock(ctx context.Context, cl client.ResolutionClient, m manif.Manifest,
 	return doRelockMsg{res, nil}
 }
 
-func doInitialRelock(ctx context.Context, opts osvFixOptions) tea.Msg {
+func readManifest(opts osvFixOptions) (manif.Manifest, error) {
 	f, err := depfile.OpenLocalDepFile(opts.Manifest)
 	if err != nil {
-		return doRelockMsg{err: err}
+		return manif.Manifest{}, err
 	}
 	defer f.Close()
-	m, err := opts.ManifestRW.Read(f)
+
+	return opts.ManifestRW.Read(f)
+}
+
+func doInitialRelock(ctx context.Context, opts osvFixOptions) tea.Msg {
+	m, err := readManifest(opts)
 	if err != nil {
 		return doRelockMsg{err: err}
 	}
+	if err := addManifestRegistries(opts, m); err != nil {
+		return doRelockMsg{err: err}
+	}
 	client.PreFetch(ctx, opts.Client, m.Requirements, m.FilePath)
 
 	return doRelock(ctx, opts.Client, m, opts.ResolveOpts, opts.MatchVuln)
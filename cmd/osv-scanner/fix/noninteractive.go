@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"os"
+	"path/filepath"
 	"slices"
 
 	"deps.dev/util/resolve"
@@ -50,15 +52,33 @@ func autoInPlace(ctx context.Context, opts osvFixOptions, maxUpgrades int) error
 	}
 
 	patches := autoChooseInPlacePatches(res, maxUpgrades, &outputResult)
+	if err := markConstrainedVulns(ctx, opts, res.Unfixable, &outputResult); err != nil {
+		cmdlogger.Warnf("WARNING: failed to check the upgrade constraints: %v", err)
+	}
+	if opts.DryRun {
+		outputResult.Candidates = inPlaceImpact(res)
+	}
+
+	system := opts.LockfileRW.System()
+	upgradeRisks{
+		system:       system,
+		sys:          system.Semver(),
+		graph:        g,
+		requirements: clientRequirements(opts, system),
+	}.annotate(ctx, &outputResult)
 
 	if err := printResult(outputResult, opts); err != nil {
 		cmdlogger.Errorf("failed writing output")
 		return err
 	}
 
+	if opts.DryRun {
+		return nil
+	}
+
 	cmdlogger.Infof("Rewriting %s...", opts.Lockfile)
 
-	return lf.Overwrite(opts.LockfileRW, opts.Lockfile, patches)
+	return overwriteLockfile(opts, patches)
 }
 
 // returns the top {maxUpgrades} compatible patches, and populates outputResult.
@@ -163,27 +183,54 @@ func autoRelax(ctx context.Context, opts osvFixOptions, maxUpgrades int) error {
 	}
 
 	populateResultVulns(&outputResult, res, allPatches)
+	if err := markConstrainedVulns(ctx, opts, res.Vulns, &outputResult); err != nil {
+		cmdlogger.Warnf("WARNING: failed to check the upgrade constraints: %v", err)
+	}
 
-	if err := opts.Client.WriteCache(manif.FilePath); err != nil {
-		cmdlogger.Warnf("WARNING: failed to write resolution cache: %v", err)
+	if !opts.DryRun {
+		if err := opts.Client.WriteCache(manif.FilePath); err != nil {
+			cmdlogger.Warnf("WARNING: failed to write resolution cache: %v", err)
+		}
 	}
 
 	depPatches := autoChooseRelaxPatches(allPatches, maxUpgrades, &outputResult)
 
+	if err := suggestNpmOverrides(ctx, opts, res, &outputResult); err != nil {
+		cmdlogger.Warnf("WARNING: failed to suggest overrides: %v", err)
+	}
+
+	if opts.DryRun {
+		outputResult.Candidates = relockImpact(allPatches)
+	}
+
+	system := opts.ManifestRW.System()
+	upgradeRisks{
+		system:       system,
+		sys:          system.Semver(),
+		graph:        res.Graph,
+		requirements: clientRequirements(opts, system),
+		resolved:     relaxedVersions(allPatches),
+	}.annotate(ctx, &outputResult)
+
 	if err := printResult(outputResult, opts); err != nil {
 		cmdlogger.Errorf("failed writing output")
 		return err
 	}
 
-	if len(depPatches) == 0 {
+	if len(depPatches) == 0 || opts.DryRun {
 		return nil
 	}
 
 	cmdlogger.Infof("Rewriting %s...", opts.Manifest)
-	if err := manifest.Overwrite(opts.ManifestRW, opts.Manifest, manifest.Patch{Manifest: &manif, Deps: depPatches}); err != nil {
+	if err := overwriteManifest(opts, manifest.Patch{Manifest: &manif, Deps: depPatches}); err != nil {
 		return err
 	}
 
+	if opts.Lockfile != "" && opts.Patch != nil {
+		cmdlogger.Infof("Regenerate %s after applying the patch to %s", opts.Lockfile, opts.Manifest)
+		return nil
+	}
+
 	if opts.Lockfile != "" {
 		// We only recreate the lockfile if we know a lockfile already exists
 		// or we've been given a command to run.
@@ -279,26 +326,8 @@ func autoOverride(ctx context.Context, opts osvFixOptions, maxUpgrades int) erro
 		return err
 	}
 
-	if opts.ManifestRW.System() == resolve.Maven {
-		// Update Maven registries based on the repositories defined in pom.xml,
-		// as well as the repositories merged from parent pom.xml.
-		// TODO: add registries defined in settings.xml
-		// https://github.com/google/osv-scanner/issues/1269
-		specific, ok := manif.EcosystemSpecific.(manifest.MavenManifestSpecific)
-		if ok {
-			registries := make([]client.Registry, len(specific.Repositories))
-			for i, repo := range specific.Repositories {
-				registries[i] = datasource.MavenRegistry{
-					URL:              string(repo.URL),
-					ID:               string(repo.ID),
-					ReleasesEnabled:  repo.Releases.Enabled.Boolean(),
-					SnapshotsEnabled: repo.Snapshots.Enabled.Boolean(),
-				}
-			}
-			if err := opts.Client.AddRegistries(registries); err != nil {
-				return err
-			}
-		}
+	if err := addManifestRegistries(opts, manif); err != nil {
+		return err
 	}
 	client.PreFetch(ctx, opts.Client, manif.Requirements, manif.FilePath)
 	res, err := resolution.Resolve(ctx, opts.Client, manif, opts.ResolveOpts)
@@ -319,30 +348,89 @@ func autoOverride(ctx context.Context, opts osvFixOptions, maxUpgrades int) erro
 	}
 
 	populateResultVulns(&outputResult, res, allPatches)
+	if err := markConstrainedVulns(ctx, opts, res.Vulns, &outputResult); err != nil {
+		cmdlogger.Warnf("WARNING: failed to check the upgrade constraints: %v", err)
+	}
 
-	if err := opts.Client.WriteCache(manif.FilePath); err != nil {
-		cmdlogger.Warnf("WARNING: failed to write resolution cache: %v", err)
+	if !opts.DryRun {
+		if err := opts.Client.WriteCache(manif.FilePath); err != nil {
+			cmdlogger.Warnf("WARNING: failed to write resolution cache: %v", err)
+		}
 	}
 
 	depPatches := autoChooseOverridePatches(allPatches, maxUpgrades, &outputResult)
 
+	// Each patch displaces its vulnerable version on its own, but check that they still do when combined.
+	if len(depPatches) > 0 {
+		undisplaced, err := remediation.UndisplacedOverrides(ctx, opts.Client, res, depPatches, opts.Options)
+		if err != nil {
+			cmdlogger.Warnf("WARNING: failed to validate the chosen patches: %v", err)
+		}
+		for _, p := range undisplaced {
+			cmdlogger.Warnf("WARNING: overriding %s to %s does not remove %s from the dependency graph", p.Pkg.Name, p.NewRequire, p.OrigResolved)
+		}
+	}
+
+	if opts.DryRun {
+		outputResult.Candidates = relockImpact(allPatches)
+	}
+
+	if err := suggestMavenPins(ctx, opts, res, allPatches, &outputResult); err != nil {
+		cmdlogger.Warnf("WARNING: failed to suggest dependencyManagement pins: %v", err)
+	}
+
+	system := opts.ManifestRW.System()
+	upgradeRisks{
+		system:       system,
+		sys:          system.Semver(),
+		graph:        res.Graph,
+		requirements: clientRequirements(opts, system),
+	}.annotate(ctx, &outputResult)
+
 	if err := printResult(outputResult, opts); err != nil {
 		cmdlogger.Errorf("failed writing output")
 		return err
 	}
 
-	if len(depPatches) == 0 {
+	if len(depPatches) == 0 || opts.DryRun {
 		return nil
 	}
 
 	cmdlogger.Infof("Rewriting %s...", opts.Manifest)
-	if err := manifest.Overwrite(opts.ManifestRW, opts.Manifest, manifest.Patch{Manifest: &manif, Deps: depPatches}); err != nil {
+	if err := overwriteManifest(opts, manifest.Patch{Manifest: &manif, Deps: depPatches}); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// addManifestRegistries adds the package registries defined in the manifest to the client.
+func addManifestRegistries(opts osvFixOptions, manif manifest.Manifest) error {
+	if manif.System() != resolve.Maven {
+		return nil
+	}
+
+	// Update Maven registries based on the repositories defined in pom.xml,
+	// as well as the repositories merged from parent pom.xml.
+	// TODO: add registries defined in settings.xml
+	// https://github.com/google/osv-scanner/issues/1269
+	specific, ok := manif.EcosystemSpecific.(manifest.MavenManifestSpecific)
+	if !ok {
+		return nil
+	}
+	registries := make([]client.Registry, len(specific.Repositories))
+	for i, repo := range specific.Repositories {
+		registries[i] = datasource.MavenRegistry{
+			URL:              string(repo.URL),
+			ID:               string(repo.ID),
+			ReleasesEnabled:  repo.Releases.Enabled.Boolean(),
+			SnapshotsEnabled: repo.Snapshots.Enabled.Boolean(),
+		}
+	}
+
+	return opts.Client.AddRegistries(registries)
+}
+
 func autoChooseOverridePatches(diffs []resolution.Difference, maxUpgrades int, outputResult *fixOutput) []manifest.DependencyPatch {
 	if maxUpgrades == 0 {
 		return nil
@@ -476,6 +564,154 @@ func populateResultVulns(outputResult *fixOutput, res *resolution.Result, allPat
 	sortVulns(outputResult.Vulnerabilities)
 }
 
+// markConstrainedVulns marks the unactionable vulnerabilities of outputResult that
+// only an upgrade outside of the allowed version ranges would fix.
+func markConstrainedVulns(ctx context.Context, opts osvFixOptions, vs []resolution.Vulnerability, outputResult *fixOutput) error {
+	if len(opts.AllowedVersions) == 0 {
+		return nil
+	}
+
+	var unactionable []resolution.Vulnerability
+	for _, v := range vs {
+		if slices.ContainsFunc(outputResult.Vulnerabilities, func(vo vulnOutput) bool { return vo.Unactionable && vo.ID == v.OSV.GetId() }) {
+			unactionable = append(unactionable, v)
+		}
+	}
+	constrained, err := remediation.ConstrainedVulns(ctx, opts.Client, unactionable, opts.Options)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(constrained))
+	for i, v := range constrained {
+		ids[i] = v.OSV.GetId()
+	}
+	setConstrainedVulns(outputResult, ids)
+
+	return nil
+}
+
+// setConstrainedVulns marks the unactionable vulnerabilities of outputResult with the given IDs as constrained.
+func setConstrainedVulns(outputResult *fixOutput, ids []string) {
+	for i, v := range outputResult.Vulnerabilities {
+		if v.Unactionable && slices.Contains(ids, v.ID) {
+			outputResult.Vulnerabilities[i].Constrained = true
+		}
+	}
+}
+
 func removeVulnIntroducingPatches(patches []resolution.Difference) []resolution.Difference {
 	return slices.DeleteFunc(patches, func(diff resolution.Difference) bool { return len(diff.AddedVulns) > 0 })
 }
+
+// suggestNpmOverrides populates outputResult with the overrides (or yarn resolutions) that would fix
+// the unactionable vulnerabilities that are only reachable through transitive dependencies.
+func suggestNpmOverrides(ctx context.Context, opts osvFixOptions, res *resolution.Result, outputResult *fixOutput) error {
+	if opts.ManifestRW.System() != resolve.NPM {
+		return nil
+	}
+
+	var unfixable []resolution.Vulnerability
+	for _, v := range res.Vulns {
+		if slices.ContainsFunc(outputResult.Vulnerabilities, func(vo vulnOutput) bool { return vo.Unactionable && vo.ID == v.OSV.GetId() }) {
+			unfixable = append(unfixable, v)
+		}
+	}
+	if len(unfixable) == 0 {
+		return nil
+	}
+
+	overrides, err := remediation.SuggestNpmOverrides(ctx, opts.Client, unfixable, opts.Options)
+	if err != nil || len(overrides) == 0 {
+		return err
+	}
+
+	out := overridesOutput{
+		Field:    "overrides",
+		Packages: make(map[string]string),
+	}
+	if usesYarn(opts) {
+		out.Field = "resolutions"
+	}
+	fixed := make(map[string]vulnOutput)
+	for _, o := range overrides {
+		out.Packages[o.Selector] = o.NewVersion
+		for _, v := range o.Fixed {
+			vo, ok := fixed[v.OSV.GetId()]
+			if !ok {
+				vo = vulnOutput{ID: v.OSV.GetId()}
+			}
+			vo.Packages = append(vo.Packages, packageOutput{Name: o.Name, Version: o.OrigVersion})
+			fixed[v.OSV.GetId()] = vo
+		}
+	}
+	out.Fixed = slices.AppendSeq(make([]vulnOutput, 0, len(fixed)), maps.Values(fixed))
+	sortVulns(out.Fixed)
+	outputResult.Overrides = &out
+
+	return nil
+}
+
+// suggestMavenPins populates outputResult with the <dependencyManagement> entries that would fix
+// the vulnerabilities only reachable through transitive dependencies that are not fixed by the applied patches,
+// which is all of them in a dry run.
+func suggestMavenPins(ctx context.Context, opts osvFixOptions, res *resolution.Result, allPatches []resolution.Difference, outputResult *fixOutput) error {
+	if opts.ManifestRW.System() != resolve.Maven {
+		return nil
+	}
+
+	var unfixed []resolution.Vulnerability
+	for _, v := range res.Vulns {
+		fixed := slices.ContainsFunc(outputResult.Patches, func(p patchOutput) bool {
+			return slices.ContainsFunc(p.Fixed, func(vo vulnOutput) bool { return vo.ID == v.OSV.GetId() })
+		})
+		if opts.DryRun || !fixed {
+			unfixed = append(unfixed, v)
+		}
+	}
+	if len(unfixed) == 0 {
+		return nil
+	}
+
+	pins, err := remediation.SuggestMavenPins(ctx, opts.Client, res, unfixed, allPatches)
+	if err != nil || len(pins) == 0 {
+		return err
+	}
+
+	var out managementOutput
+	fixed := make(map[string]vulnOutput)
+	for _, p := range pins {
+		po := pinOutput{
+			Name:        p.Name,
+			VersionFrom: p.OrigVersion,
+			VersionTo:   p.NewVersion,
+		}
+		for _, c := range p.Conflicts {
+			po.Conflicts = append(po.Conflicts, fmt.Sprintf("%s@%s requires %s@%s", c.Dependent.Name, c.Dependent.Version, p.Name, c.Range))
+		}
+		out.Dependencies = append(out.Dependencies, po)
+		for _, v := range p.Fixed {
+			vo, ok := fixed[v.OSV.GetId()]
+			if !ok {
+				vo = vulnOutput{ID: v.OSV.GetId()}
+			}
+			vo.Packages = append(vo.Packages, packageOutput{Name: p.Name, Version: p.OrigVersion})
+			fixed[v.OSV.GetId()] = vo
+		}
+	}
+	out.Fixed = slices.AppendSeq(make([]vulnOutput, 0, len(fixed)), maps.Values(fixed))
+	sortVulns(out.Fixed)
+	outputResult.Management = &out
+
+	return nil
+}
+
+// usesYarn returns whether the project is managed by yarn, which calls overrides resolutions.
+func usesYarn(opts osvFixOptions) bool {
+	if opts.Lockfile != "" {
+		return filepath.Base(opts.Lockfile) == "yarn.lock"
+	}
+	_, err := os.Stat(filepath.Join(filepath.Dir(opts.Manifest), "yarn.lock"))
+
+	return err == nil
+}
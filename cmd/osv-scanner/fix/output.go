@@ -2,22 +2,58 @@ package fix
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"slices"
 	"strings"
 
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/remediation"
 	"github.com/ossf/osv-schema/bindings/go/osvconstants"
 )
 
 // fixOutput is a description of changes made by guided remediation to a manifest/lockfile.
 type fixOutput struct {
-	Path            string                 `json:"path"`             // path to the manifest/lockfile.
-	Ecosystem       osvconstants.Ecosystem `json:"ecosystem"`        // the OSV ecosystem of the file (npm, Maven)
-	Strategy        strategy               `json:"strategy"`         // the remediation strategy that was used.
-	Vulnerabilities []vulnOutput           `json:"vulnerabilities"`  // vulns detected in the initial manifest/lockfile.
-	Patches         []patchOutput          `json:"patches"`          // list of dependency patches that were applied.
-	Errors          []errorOutput          `json:"errors,omitempty"` // non-fatal errors encountered in initial resolution.
+	Path            string                 `json:"path"`                           // path to the manifest/lockfile.
+	Ecosystem       osvconstants.Ecosystem `json:"ecosystem"`                      // the OSV ecosystem of the file (npm, Maven)
+	Strategy        strategy               `json:"strategy"`                       // the remediation strategy that was used.
+	Vulnerabilities []vulnOutput           `json:"vulnerabilities"`                // vulns detected in the initial manifest/lockfile.
+	Patches         []patchOutput          `json:"patches"`                        // list of dependency patches that were applied.
+	Errors          []errorOutput          `json:"errors,omitempty"`               // non-fatal errors encountered in initial resolution.
+	Overrides       *overridesOutput       `json:"overrides,omitempty"`            // suggested package.json block for vulns only fixable by pinning transitive dependencies.
+	Candidates      []impactOutput         `json:"candidates,omitempty"`           // every possible patch and its impact, only reported in a dry run.
+	Management      *managementOutput      `json:"dependencyManagement,omitempty"` // suggested pom.xml block for transitive vulns the applied patches did not fix.
+}
+
+// impactOutput describes what a candidate patch would change, without it being applied.
+type impactOutput struct {
+	PackageUpdates []updatePackageOutput `json:"packageUpdates"` // dependencies the patch would update, with the versions they resolve to.
+	Fixed          int                   `json:"fixed"`          // number of vulns the patch would fix.
+	NewPackages    int                   `json:"newPackages"`    // number of packages the patch would add to the dependency graph.
+	Major          bool                  `json:"major"`          // true if any of the updates crosses a major version.
+}
+
+// overridesOutput is a block to add to a package.json that pins transitive dependencies to versions that fix vulns,
+// for vulns that cannot be fixed by updating direct dependencies.
+type overridesOutput struct {
+	Field    string            `json:"field"`    // the package.json field to add the packages to: "overrides" (npm) or "resolutions" (yarn).
+	Packages map[string]string `json:"packages"` // the version to pin each package to.
+	Fixed    []vulnOutput      `json:"fixed"`    // vulns fixed by the overrides.
+}
+
+// managementOutput is a <dependencyManagement> block to add to a pom.xml that pins transitive dependencies to versions that fix vulns,
+// for vulns that were not fixed by the applied patches.
+type managementOutput struct {
+	Dependencies []pinOutput  `json:"dependencies"` // the dependencies to pin.
+	Fixed        []vulnOutput `json:"fixed"`        // vulns fixed by the pins.
+}
+
+// pinOutput is a dependency pinned to a version in a <dependencyManagement> block.
+type pinOutput struct {
+	Name        string   `json:"name"`                // Maven name (groupId:artifactId) of the dependency.
+	VersionFrom string   `json:"versionFrom"`         // the version currently in the dependency graph.
+	VersionTo   string   `json:"versionTo"`           // the version to pin the dependency to.
+	Conflicts   []string `json:"conflicts,omitempty"` // dependents requiring a version range that excludes versionTo, e.g. "g:a@1.0 requires g:b@[1.0,2.0)".
 }
 
 // vulnOutput represents a vulnerability that was found in a project.
@@ -25,6 +61,7 @@ type vulnOutput struct {
 	ID           string          `json:"id"`                     // the OSV ID of the vulnerability.
 	Packages     []packageOutput `json:"packages"`               // the list of packages in the dependency graph this vuln affects.
 	Unactionable bool            `json:"unactionable,omitempty"` // true if no fix patch available, or if constraints would prevent one.
+	Constrained  bool            `json:"constrained,omitempty"`  // true if only upgrading outside of the upgrade constraints in osv-scanner.toml would fix the vuln.
 }
 
 // patchOutput represents an isolated patch to one or more dependencies that fixes one or more vulns.
@@ -42,10 +79,11 @@ type packageOutput struct {
 
 // updatePackageOutput represents a package that was updated by a patch.
 type updatePackageOutput struct {
-	Name        string `json:"name"`        // name of dependency being updated.
-	VersionFrom string `json:"versionFrom"` // version of the dependency before the patch.
-	VersionTo   string `json:"versionTo"`   // version of the dependency after the patch.
-	Transitive  bool   `json:"transitive"`  // false if this package is a direct dependency, true if indirect.
+	Name        string      `json:"name"`           // name of dependency being updated.
+	VersionFrom string      `json:"versionFrom"`    // version of the dependency before the patch.
+	VersionTo   string      `json:"versionTo"`      // version of the dependency after the patch.
+	Transitive  bool        `json:"transitive"`     // false if this package is a direct dependency, true if indirect.
+	Risk        *riskOutput `json:"risk,omitempty"` // estimated risk of the update breaking the project.
 }
 
 // errorOutput represents an error encountered during the initial resolution of the dependency graph.
@@ -93,6 +131,8 @@ func outputText(_ io.Writer, out fixOutput) error {
 		cmdlogger.Infof("No dependency patches are possible")
 		cmdlogger.Infof("REMAINING-VULNS: %d", nVulns)
 		cmdlogger.Infof("UNFIXABLE-VULNS: %d", nVulns)
+		printConstrained(out.Vulnerabilities)
+		printCandidates(out.Candidates)
 
 		return nil
 	}
@@ -111,6 +151,7 @@ func outputText(_ io.Writer, out fixOutput) error {
 		for _, patch := range out.Patches {
 			for _, pkg := range patch.PackageUpdates {
 				cmdlogger.Infof("OVERRIDE-PACKAGE: %s,%s", pkg.Name, pkg.VersionTo)
+				printRisk(pkg)
 			}
 		}
 	} else {
@@ -118,6 +159,7 @@ func outputText(_ io.Writer, out fixOutput) error {
 		for _, patch := range out.Patches {
 			for _, pkg := range patch.PackageUpdates {
 				cmdlogger.Infof("UPGRADED-PACKAGE: %s,%s,%s", pkg.Name, pkg.VersionFrom, pkg.VersionTo)
+				printRisk(pkg)
 			}
 		}
 	}
@@ -132,10 +174,112 @@ func outputText(_ io.Writer, out fixOutput) error {
 		}
 	}
 	cmdlogger.Infof("UNFIXABLE-VULNS: %d", nUnfixable)
+	printConstrained(out.Vulnerabilities)
+
+	printOverrides(out.Overrides)
+	printManagement(out.Management)
+	printCandidates(out.Candidates)
 
 	return nil
 }
 
+// printConstrained lists the vulns that the upgrade constraints prevent fixing, if there are any.
+func printConstrained(vulns []vulnOutput) {
+	var ids []string
+	for _, v := range vulns {
+		if v.Constrained {
+			ids = append(ids, v.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	cmdlogger.Infof("CONSTRAINED-VULNS: %d", len(ids))
+	cmdlogger.Infof("CONSTRAINED-VULN-IDS: %s", strings.Join(ids, ","))
+}
+
+func printCandidates(candidates []impactOutput) {
+	if candidates == nil {
+		return
+	}
+
+	cmdlogger.Infof("Dry run, no files were modified. Impact of each of the %d candidate patches:", len(candidates))
+	for _, c := range candidates {
+		updates := make([]string, len(c.PackageUpdates))
+		for i, pkg := range c.PackageUpdates {
+			updates[i] = fmt.Sprintf("%s@%s->%s", pkg.Name, pkg.VersionFrom, pkg.VersionTo)
+		}
+		major := "within major version"
+		if c.Major {
+			major = "crosses major version"
+		}
+		line := fmt.Sprintf("CANDIDATE-PATCH: %s: fixes %d vulnerabilities, adds %d new packages, %s", strings.Join(updates, ","), c.Fixed, c.NewPackages, major)
+		if level := candidateRisk(c); level != "" {
+			line += fmt.Sprintf(", %s risk", level)
+		}
+		cmdlogger.Infof("%s", line)
+	}
+}
+
+// candidateRisk is the highest risk of the updates of a candidate patch, or empty if none were estimated.
+func candidateRisk(c impactOutput) remediation.RiskLevel {
+	var level remediation.RiskLevel
+	for _, pkg := range c.PackageUpdates {
+		switch {
+		case pkg.Risk == nil:
+		case pkg.Risk.Level == remediation.RiskHigh:
+			return remediation.RiskHigh
+		case pkg.Risk.Level == remediation.RiskMedium || level == "":
+			level = pkg.Risk.Level
+		}
+	}
+
+	return level
+}
+
+func printRisk(pkg updatePackageOutput) {
+	if pkg.Risk == nil {
+		return
+	}
+	cmdlogger.Infof("UPGRADE-RISK: %s,%s: %s risk (%d major versions crossed, %d dependencies changed, %d other dependents)",
+		pkg.Name, pkg.VersionTo, pkg.Risk.Level, pkg.Risk.MajorVersions, pkg.Risk.ChangedDependencies, pkg.Risk.Dependents)
+}
+
+func printOverrides(o *overridesOutput) {
+	if o == nil {
+		return
+	}
+
+	block, err := json.MarshalIndent(map[string]map[string]string{o.Field: o.Packages}, "", "  ")
+	if err != nil {
+		return
+	}
+	cmdlogger.Infof("Can fix %d more vulnerabilities by pinning transitive dependencies, add the following to package.json:", len(o.Fixed))
+	cmdlogger.Infof("%s", block)
+}
+
+func printManagement(m *managementOutput) {
+	if m == nil {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<dependencyManagement>\n  <dependencies>\n")
+	for _, d := range m.Dependencies {
+		group, artifact, _ := strings.Cut(d.Name, ":")
+		fmt.Fprintf(&sb, "    <dependency>\n      <groupId>%s</groupId>\n      <artifactId>%s</artifactId>\n      <version>%s</version>\n    </dependency>\n", group, artifact, d.VersionTo)
+	}
+	sb.WriteString("  </dependencies>\n</dependencyManagement>")
+	cmdlogger.Infof("Can fix %d more vulnerabilities by pinning transitive dependencies, add the following to pom.xml:", len(m.Fixed))
+	cmdlogger.Infof("%s", sb.String())
+
+	for _, d := range m.Dependencies {
+		for _, c := range d.Conflicts {
+			cmdlogger.Warnf("WARNING: %s, which excludes the pinned version %s", c, d.VersionTo)
+		}
+	}
+}
+
 func outputJSON(w io.Writer, out fixOutput) error {
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
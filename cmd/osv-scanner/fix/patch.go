@@ -0,0 +1,79 @@
+package fix
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/osv-scanner/v2/internal/resolution/depfile"
+	lf "github.com/google/osv-scanner/v2/internal/resolution/lockfile"
+	"github.com/google/osv-scanner/v2/internal/resolution/manifest"
+	"github.com/google/osv-scanner/v2/internal/utility/unifieddiff"
+)
+
+const (
+	fixOutputWrite = "write" // modify the manifest/lockfile in place
+	fixOutputPatch = "patch" // write the modifications as a unified diff instead
+)
+
+var fixOutputs = []string{fixOutputWrite, fixOutputPatch}
+
+// overwriteManifest applies the patch to the manifest,
+// or writes the changes it would make to opts.Patch if set.
+func overwriteManifest(opts osvFixOptions, p manifest.Patch) error {
+	if opts.Patch == nil {
+		return manifest.Overwrite(opts.ManifestRW, opts.Manifest, p)
+	}
+
+	return writePatch(opts.Patch, opts.Manifest, func(original depfile.DepFile, output io.Writer) error {
+		return opts.ManifestRW.Write(original, output, p)
+	})
+}
+
+// overwriteLockfile applies the patches to the lockfile,
+// or writes the changes they would make to opts.Patch if set.
+func overwriteLockfile(opts osvFixOptions, patches []lf.DependencyPatch) error {
+	if opts.Patch == nil {
+		return lf.Overwrite(opts.LockfileRW, opts.Lockfile, patches)
+	}
+
+	return writePatch(opts.Patch, opts.Lockfile, func(original depfile.DepFile, output io.Writer) error {
+		return opts.LockfileRW.Write(original, output, patches)
+	})
+}
+
+// writePatch writes the changes that write makes to the file at filename to w as a unified diff, leaving the file untouched.
+func writePatch(w io.Writer, filename string, write func(original depfile.DepFile, output io.Writer) error) error {
+	before, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	f, err := depfile.OpenLocalDepFile(filename)
+	if err != nil {
+		return err
+	}
+	var after bytes.Buffer
+	err = write(f, &after)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	return unifieddiff.Write(w, patchPath(filename), before, after.Bytes())
+}
+
+// patchPath is the path of filename in a patch, relative to the working directory
+// so that the patch can be applied from where osv-scanner was run.
+func patchPath(filename string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if abs, err := filepath.Abs(filename); err == nil {
+			if rel, err := filepath.Rel(wd, abs); err == nil {
+				filename = rel
+			}
+		}
+	}
+
+	return filepath.ToSlash(filename)
+}
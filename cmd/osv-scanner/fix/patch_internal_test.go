@@ -0,0 +1,46 @@
+package fix
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/remediation"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+	"golang.org/x/mod/modfile"
+)
+
+func Test_writeGoModPatch(t *testing.T) {
+	t.Parallel()
+
+	filename := "testdata/go-mod/go.mod"
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("could not read test file: %v", err)
+	}
+	f, err := modfile.Parse(filename, data, nil)
+	if err != nil {
+		t.Fatalf("could not parse test file: %v", err)
+	}
+
+	patches := []remediation.GoModPatch{
+		{GoModRequirement: remediation.GoModRequirement{Module: "github.com/gin-gonic/gin", Version: "v1.9.0"}, NewVersion: "v1.9.1"},
+		{GoModRequirement: remediation.GoModRequirement{Module: "golang.org/x/crypto", Version: "v0.14.0", Indirect: true}, NewVersion: "v0.17.0"},
+	}
+
+	var sb strings.Builder
+	if err := writeGoModPatch(osvFixOptions{Manifest: filename, Patch: &sb}, f, data, patches); err != nil {
+		t.Fatalf("writeGoModPatch() error: %v", err)
+	}
+
+	testutility.NewSnapshot().MatchText(t, sb.String())
+
+	// the go.mod itself is left untouched
+	after, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("could not read test file: %v", err)
+	}
+	if string(after) != string(data) {
+		t.Errorf("writeGoModPatch() modified %s", filename)
+	}
+}
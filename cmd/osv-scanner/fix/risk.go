@@ -0,0 +1,122 @@
+package fix
+
+import (
+	"context"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/remediation"
+	"github.com/google/osv-scanner/v2/internal/resolution"
+)
+
+// riskOutput estimates how likely an upgrade is to break the project, so that safer upgrades can be picked first.
+type riskOutput struct {
+	Level               remediation.RiskLevel `json:"level"`               // low, medium, or high.
+	MajorVersions       int                   `json:"majorVersions"`       // number of major versions the upgrade crosses.
+	ChangedDependencies int                   `json:"changedDependencies"` // number of dependencies the new version adds, removes, or requires at a different version.
+	Dependents          int                   `json:"dependents"`          // number of other packages in the dependency graph that require the package.
+}
+
+// requirementsFunc lists the dependencies of a version of a package.
+type requirementsFunc func(ctx context.Context, name, version string) ([]resolve.RequirementVersion, error)
+
+// upgradeRisks estimates the risk of the package updates of a fix.
+type upgradeRisks struct {
+	system resolve.System
+	sys    semver.System
+	graph  *resolve.Graph // the dependency graph before the fix, nil if it is not resolved.
+
+	requirements requirementsFunc
+	// resolved returns the versions an update resolves to, if its versions are requirements rather than versions.
+	resolved func(u updatePackageOutput) (from, to string, ok bool)
+}
+
+// clientRequirements lists the dependencies of package versions from the resolution client.
+func clientRequirements(opts osvFixOptions, system resolve.System) requirementsFunc {
+	return func(ctx context.Context, name, version string) ([]resolve.RequirementVersion, error) {
+		return opts.Client.Requirements(ctx, resolve.VersionKey{
+			PackageKey:  resolve.PackageKey{System: system, Name: name},
+			Version:     version,
+			VersionType: resolve.Concrete,
+		})
+	}
+}
+
+// relaxedVersions looks up the resolved versions of the requirements changed by relax patches.
+func relaxedVersions(diffs []resolution.Difference) func(updatePackageOutput) (string, string, bool) {
+	type update struct{ name, from, to string }
+	versions := make(map[update][2]string)
+	for _, diff := range diffs {
+		for _, dp := range diff.Deps {
+			versions[update{dp.Pkg.Name, dp.OrigRequire, dp.NewRequire}] = [2]string{dp.OrigResolved, dp.NewResolved}
+		}
+	}
+
+	return func(u updatePackageOutput) (string, string, bool) {
+		v, ok := versions[update{u.Name, u.VersionFrom, u.VersionTo}]
+		return v[0], v[1], ok
+	}
+}
+
+// annotate sets the risk of each package update in the patches and candidates of out.
+// Updates whose risk cannot be estimated are left without one.
+func (r upgradeRisks) annotate(ctx context.Context, out *fixOutput) {
+	cache := make(map[updatePackageOutput]*riskOutput)
+	set := func(u *updatePackageOutput) {
+		key := updatePackageOutput{Name: u.Name, VersionFrom: u.VersionFrom, VersionTo: u.VersionTo}
+		risk, ok := cache[key]
+		if !ok {
+			risk = r.assess(ctx, key)
+			cache[key] = risk
+		}
+		u.Risk = risk
+	}
+
+	for i := range out.Patches {
+		for j := range out.Patches[i].PackageUpdates {
+			set(&out.Patches[i].PackageUpdates[j])
+		}
+	}
+	for i := range out.Candidates {
+		for j := range out.Candidates[i].PackageUpdates {
+			set(&out.Candidates[i].PackageUpdates[j])
+		}
+	}
+}
+
+func (r upgradeRisks) assess(ctx context.Context, u updatePackageOutput) *riskOutput {
+	from, to := u.VersionFrom, u.VersionTo
+	if r.resolved != nil {
+		if f, t, ok := r.resolved(u); ok {
+			from, to = f, t
+		}
+	}
+	if from == "" || to == "" {
+		return nil
+	}
+
+	fromReqs, err := r.requirements(ctx, u.Name, from)
+	if err != nil {
+		cmdlogger.Warnf("WARNING: failed to estimate the risk of upgrading %s: %v", u.Name, err)
+		return nil
+	}
+	toReqs, err := r.requirements(ctx, u.Name, to)
+	if err != nil {
+		cmdlogger.Warnf("WARNING: failed to estimate the risk of upgrading %s: %v", u.Name, err)
+		return nil
+	}
+
+	risk := remediation.UpgradeRisk{
+		MajorVersions:       remediation.MajorVersions(r.sys, from, to),
+		ChangedDependencies: remediation.ChangedDependencies(fromReqs, toReqs),
+		Dependents:          remediation.Dependents(r.graph, resolve.PackageKey{System: r.system, Name: u.Name}),
+	}
+
+	return &riskOutput{
+		Level:               risk.Level(),
+		MajorVersions:       risk.MajorVersions,
+		ChangedDependencies: risk.ChangedDependencies,
+		Dependents:          risk.Dependents,
+	}
+}
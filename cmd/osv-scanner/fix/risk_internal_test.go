@@ -0,0 +1,78 @@
+package fix
+
+import (
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/semver"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/remediation"
+	"github.com/google/osv-scanner/v2/internal/resolution"
+	"github.com/google/osv-scanner/v2/internal/resolution/manifest"
+)
+
+func Test_upgradeRisks_annotate(t *testing.T) {
+	t.Parallel()
+
+	src := fakeGoModuleSource{
+		"golang.org/x/net@v0.10.0": "module golang.org/x/net\n\nrequire golang.org/x/text v0.9.0\n",
+		"golang.org/x/net@v0.17.0": "module golang.org/x/net\n\nrequire golang.org/x/text v0.13.0\n",
+		"github.com/a/b@v1.0.0":    "module github.com/a/b\n",
+		"github.com/a/b@v3.0.0":    "module github.com/a/b\n",
+	}
+
+	out := fixOutput{
+		Patches: []patchOutput{{
+			PackageUpdates: []updatePackageOutput{
+				{Name: "golang.org/x/net", VersionFrom: "v0.10.0", VersionTo: "v0.17.0"},
+				{Name: "github.com/a/b", VersionFrom: "v1.0.0", VersionTo: "v3.0.0"},
+			},
+		}},
+		Candidates: []impactOutput{{
+			PackageUpdates: []updatePackageOutput{{Name: "golang.org/x/net", VersionFrom: "v0.10.0", VersionTo: "v0.17.0"}},
+		}},
+	}
+	upgradeRisks{sys: semver.Go, requirements: goModRequirements(src)}.annotate(t.Context(), &out)
+
+	net := &riskOutput{Level: remediation.RiskMedium, ChangedDependencies: 1}
+	want := fixOutput{
+		Patches: []patchOutput{{
+			PackageUpdates: []updatePackageOutput{
+				{Name: "golang.org/x/net", VersionFrom: "v0.10.0", VersionTo: "v0.17.0", Risk: net},
+				{Name: "github.com/a/b", VersionFrom: "v1.0.0", VersionTo: "v3.0.0", Risk: &riskOutput{Level: remediation.RiskHigh, MajorVersions: 2}},
+			},
+		}},
+		Candidates: []impactOutput{{
+			PackageUpdates: []updatePackageOutput{{Name: "golang.org/x/net", VersionFrom: "v0.10.0", VersionTo: "v0.17.0", Risk: net}},
+		}},
+	}
+	if diff := cmp.Diff(want, out); diff != "" {
+		t.Errorf("annotate() mismatch (-want +got):\n%s", diff)
+	}
+	if got := candidateRisk(out.Candidates[0]); got != remediation.RiskMedium {
+		t.Errorf("candidateRisk() = %s, want %s", got, remediation.RiskMedium)
+	}
+}
+
+func Test_relaxedVersions(t *testing.T) {
+	t.Parallel()
+
+	pkg := resolve.PackageKey{System: resolve.NPM, Name: "express"}
+	resolved := relaxedVersions([]resolution.Difference{{
+		Patch: manifest.Patch{Deps: []manifest.DependencyPatch{{
+			Pkg:          pkg,
+			OrigRequire:  "^4.17.0",
+			NewRequire:   "^5.0.0",
+			OrigResolved: "4.17.1",
+			NewResolved:  "5.1.0",
+		}}},
+	}})
+
+	from, to, ok := resolved(updatePackageOutput{Name: "express", VersionFrom: "^4.17.0", VersionTo: "^5.0.0"})
+	if !ok || from != "4.17.1" || to != "5.1.0" {
+		t.Errorf("resolved() = %q, %q, %v, want \"4.17.1\", \"5.1.0\", true", from, to, ok)
+	}
+	if _, _, ok := resolved(updatePackageOutput{Name: "express", VersionFrom: "4.17.1", VersionTo: "5.1.0"}); ok {
+		t.Errorf("resolved() of resolved versions = true, want false")
+	}
+}
@@ -3,6 +3,7 @@ package fix
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -34,6 +35,9 @@ type stateRelockResult struct {
 	resolveErrors tui.ViewModel
 
 	focusedInfo tui.ViewModel // the infoview that is currently focused, nil if not focused
+
+	override  bool                    // whether patches override versions instead of relaxing requirements (e.g. for pom.xml)
+	overrides []manif.DependencyPatch // override patches applied to m.relockBaseRes to produce currRes
 }
 
 const (
@@ -93,6 +97,8 @@ func (st *stateRelockResult) Init(m model) tea.Cmd {
 	st.cursorPos = -1
 	st.selectedPatches = make(map[int]struct{})
 	st.viewWidth = m.mainViewWidth
+	st.override = !remediation.SupportsRelax(m.options.ManifestRW)
+	st.overrides = nil
 
 	// Make the vulnerability list view model
 	vulns := make([]*resolution.Vulnerability, len(st.currRes.Vulns))
@@ -155,6 +161,15 @@ func (st *stateRelockResult) Update(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.relockBaseRes = st.currRes // relockBaseRes must match what is in the package.json
 		m.relockBaseResErrs = m.relockBaseRes.Errors()
 		clear(st.selectedPatches)
+		if len(st.overrides) > 0 {
+			// Further overrides are applied on top of the written ones, so use the manifest as it is now on disk.
+			manifest, err := readManifest(m.options)
+			if err != nil {
+				return errorAndExit(m, err)
+			}
+			m.relockBaseRes.Manifest = manifest
+			st.overrides = nil
+		}
 
 	case tui.ViewModelCloseMsg:
 		// info view wants to quit, just unfocus it
@@ -291,6 +306,22 @@ func (st *stateRelockResult) parseInput(m model) (tea.Model, tea.Cmd) {
 }
 
 func (st *stateRelockResult) relaxChoice(m model) (model, tea.Cmd) {
+	if st.override {
+		// Merge the selected patches into the overrides that have already been applied.
+		for i := range st.selectedPatches {
+			for _, dp := range st.patches[i].Deps {
+				idx := slices.IndexFunc(st.overrides, func(o manif.DependencyPatch) bool { return o.Pkg == dp.Pkg })
+				if idx < 0 {
+					st.overrides = append(st.overrides, dp)
+					continue
+				}
+				// keep the version that was originally resolved
+				st.overrides[idx].NewRequire = dp.NewRequire
+				st.overrides[idx].NewResolved = dp.NewResolved
+			}
+		}
+	}
+
 	if len(st.selectedPatches) == 1 {
 		// If it's just a single patch, we've already computed the relock result
 		for i := range st.selectedPatches { // selectedPatches is a map, iterate for the single key
@@ -310,6 +341,17 @@ func (st *stateRelockResult) relaxChoice(m model) (model, tea.Cmd) {
 		}
 	}
 
+	if st.override {
+		// Re-resolve the graph with all the overrides applied
+		st.currRes = nil
+		overrides := slices.Clone(st.overrides)
+
+		return m, func() tea.Msg {
+			res, err := remediation.ApplyOverrides(m.ctx, m.cl, m.relockBaseRes.Manifest, overrides, m.options.Options)
+			return doRelockMsg{res, err}
+		}
+	}
+
 	// Compute combined changes and re-resolve the graph
 	manifest := st.currRes.Manifest.Clone()
 	for i := range st.selectedPatches {
@@ -336,7 +378,11 @@ func (st *stateRelockResult) View(m model) string {
 		return ""
 	}
 	s := strings.Builder{}
-	s.WriteString("RELOCK\n")
+	if st.override {
+		s.WriteString("OVERRIDE\n")
+	} else {
+		s.WriteString("RELOCK\n")
+	}
 	if st.currRes == nil {
 		s.WriteString("Resolving dependency graph ")
 		s.WriteString(st.spinner.View())
@@ -451,7 +497,11 @@ func diffString(diff resolution.Difference) string {
 	var depStr string
 	if len(diff.Deps) == 1 {
 		dep := diff.Deps[0]
-		depStr = fmt.Sprintf("%s@%s → @%s", dep.Pkg.Name, dep.OrigRequire, dep.NewRequire)
+		if dep.OrigRequire == "" { // override patch
+			depStr = fmt.Sprintf("%s@%s → @%s", dep.Pkg.Name, dep.OrigResolved, dep.NewRequire)
+		} else {
+			depStr = fmt.Sprintf("%s@%s → @%s", dep.Pkg.Name, dep.OrigRequire, dep.NewRequire)
+		}
 	} else {
 		depStr = fmt.Sprintf("%d packages", len(diff.Deps))
 	}
@@ -508,8 +558,11 @@ func (st *stateRelockResult) IsInfoFocused() bool {
 
 // TODO: Work out a better way to output npm commands
 func (st *stateRelockResult) write(m model) tea.Msg {
-	changes := m.relockBaseRes.CalculateDiff(st.currRes)
-	if err := manif.Overwrite(m.options.ManifestRW, m.options.Manifest, changes.Patch); err != nil {
+	patch := manif.Patch{Manifest: &m.relockBaseRes.Manifest, Deps: st.overrides}
+	if !st.override {
+		patch = m.relockBaseRes.CalculateDiff(st.currRes).Patch
+	}
+	if err := manif.Overwrite(m.options.ManifestRW, m.options.Manifest, patch); err != nil {
 		return writeMsg{err}
 	}
 
@@ -547,7 +600,11 @@ type relockPatchMsg struct {
 
 // Find all groups of dependency bumps required to resolve each vulnerability individually
 func doComputeRelockPatches(ctx context.Context, cl client.ResolutionClient, currRes *resolution.Result, opts osvFixOptions) relockPatchMsg {
-	patches, err := remediation.ComputeRelaxPatches(ctx, cl, currRes, opts.Options)
+	compute := remediation.ComputeRelaxPatches
+	if !remediation.SupportsRelax(opts.ManifestRW) {
+		compute = remediation.ComputeOverridePatches
+	}
+	patches, err := compute(ctx, cl, currRes, opts.Options)
 	if err != nil {
 		return relockPatchMsg{err: err}
 	}
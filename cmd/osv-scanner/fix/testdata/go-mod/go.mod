@@ -0,0 +1,15 @@
+module example.com/app
+
+go 1.22
+
+require (
+	github.com/gin-gonic/gin v1.9.0
+	golang.org/x/net v0.17.0
+)
+
+require (
+	github.com/bytedance/sonic v1.9.1 // indirect
+	golang.org/x/crypto v0.14.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.13.0 // indirect
+)
@@ -35,6 +35,9 @@ EXAMPLES:
 	# Fix vulnerabilities in a manifest file and lockfile (non-interactive mode)
 	$ {{.Name}} fix -M <manifest_file> -L <lockfile>
 
+	# Look up the vulnerabilities of a single package version
+	$ {{.Name}} query pypi/django@4.2.1
+
 	For full usage details, please refer to the help command of each subcommand (e.g. {{.Name}} scan --help).
 
 	Alternatively, you can access the detailed documentation here: https://google.github.io/osv-scanner/
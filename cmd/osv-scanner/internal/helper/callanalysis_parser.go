@@ -1,9 +1,12 @@
 package helper
 
 var stableCallAnalysisStates = map[string]bool{
-	"go":   true,
-	"rust": false,
-	"jar":  false,
+	"go":         true,
+	"rust":       false,
+	"jar":        false,
+	"java":       false,
+	"javascript": false,
+	"python":     false,
 }
 
 // CreateCallAnalysisStates creates a map to record if languages are enabled or disabled for call analysis
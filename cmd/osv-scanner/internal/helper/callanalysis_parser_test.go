@@ -16,45 +16,60 @@ func TestCreateCallAnalysisStates(t *testing.T) {
 			enabledCallAnalysis:  []string{"go", "rust"},
 			disabledCallAnalysis: []string{},
 			expectedCallAnalysisStates: map[string]bool{
-				"go":   true,
-				"rust": true,
-				"jar":  false,
+				"go":         true,
+				"rust":       true,
+				"jar":        false,
+				"java":       false,
+				"javascript": false,
+				"python":     false,
 			},
 		},
 		{
 			enabledCallAnalysis:  []string{"all"},
 			disabledCallAnalysis: []string{"rust"},
 			expectedCallAnalysisStates: map[string]bool{
-				"go":   true,
-				"rust": false,
-				"jar":  true,
+				"go":         true,
+				"rust":       false,
+				"jar":        true,
+				"java":       true,
+				"javascript": true,
+				"python":     true,
 			},
 		},
 		{
 			enabledCallAnalysis:  []string{},
 			disabledCallAnalysis: []string{"all"},
 			expectedCallAnalysisStates: map[string]bool{
-				"go":   false,
-				"rust": false,
-				"jar":  false,
+				"go":         false,
+				"rust":       false,
+				"jar":        false,
+				"java":       false,
+				"javascript": false,
+				"python":     false,
 			},
 		},
 		{
 			enabledCallAnalysis:  []string{},
 			disabledCallAnalysis: []string{"rust"},
 			expectedCallAnalysisStates: map[string]bool{
-				"go":   true,
-				"rust": false,
-				"jar":  false,
+				"go":         true,
+				"rust":       false,
+				"jar":        false,
+				"java":       false,
+				"javascript": false,
+				"python":     false,
 			},
 		},
 		{
 			enabledCallAnalysis:  []string{"all", "rust"},
 			disabledCallAnalysis: []string{"go"},
 			expectedCallAnalysisStates: map[string]bool{
-				"go":   false,
-				"rust": true,
-				"jar":  true,
+				"go":         false,
+				"rust":       true,
+				"jar":        true,
+				"java":       true,
+				"javascript": true,
+				"python":     true,
 			},
 		},
 	}
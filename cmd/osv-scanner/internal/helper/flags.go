@@ -2,6 +2,7 @@ package helper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -57,21 +58,47 @@ func BuildCommonScanFlags(defaultExtractors []string) []cli.Flag {
 			Usage:     "set/override config file",
 			TakesFile: true,
 		},
-		&cli.StringFlag{
+		&cli.BoolFlag{
+			Name:  "fail-on-expired-ignores",
+			Usage: "return a failing exit code if any ignored vulnerability in the config files is past its ignoreUntil date",
+		},
+		&cli.BoolFlag{
+			Name:  "require-ignore-reasons",
+			Usage: "fail the scan if any ignored vulnerability in the config files does not give a reason",
+		},
+		&cli.StringSliceFlag{
 			Name:    "format",
 			Aliases: []string{"f"},
-			Usage:   "sets the output format; value can be: " + strings.Join(reporter.Format(), ", "),
-			Value:   "table",
-			Action: func(_ context.Context, _ *cli.Command, s string) error {
-				if slices.Contains(reporter.Format(), s) {
-					if s != "vertical" && s != "table" && s != "markdown" {
-						cmdlogger.SendEverythingToStderr()
-					}
+			Usage: "sets the output format, optionally with a path to write it to instead of --output (--format=[format]:[path]); " +
+				"can be given multiple times to output several formats; value can be: " + strings.Join(reporter.Format(), ", "),
+			Value: []string{"table"},
+			Action: func(_ context.Context, _ *cli.Command, values []string) error {
+				formats, err := ParseOutputFormats(values)
+				if err != nil {
+					return err
+				}
 
-					return nil
+				if s := formats[0].Format; s != "vertical" && s != "table" && s != "markdown" {
+					cmdlogger.SendEverythingToStderr()
 				}
 
-				return fmt.Errorf("unsupported output format \"%s\" - must be one of: %s", s, strings.Join(reporter.Format(), ", "))
+				return nil
+			},
+		},
+		&cli.StringFlag{
+			Name:      "template-file",
+			Usage:     "renders the results through the Go text/template in the given file; requires --format template",
+			TakesFile: true,
+			Action: func(_ context.Context, cmd *cli.Command, _ string) error {
+				formats, err := ParseOutputFormats(cmd.StringSlice("format"))
+				if err != nil {
+					return err
+				}
+				if !slices.ContainsFunc(formats, func(f OutputFormat) bool { return f.Format == "template" }) {
+					return errors.New("--template-file can only be used with --format template")
+				}
+
+				return nil
 			},
 		},
 		&cli.BoolFlag{
@@ -133,6 +160,28 @@ func BuildCommonScanFlags(defaultExtractors []string) []cli.Flag {
 				return nil
 			},
 		},
+		&cli.IntFlag{
+			Name:  "experimental-osv-concurrency",
+			Usage: "maximum number of concurrent requests to the OSV API, 0 for the default",
+			Action: func(_ context.Context, _ *cli.Command, i int) error {
+				if i < 0 {
+					return fmt.Errorf("--experimental-osv-concurrency must not be negative, got %d", i)
+				}
+
+				return nil
+			},
+		},
+		&cli.FloatFlag{
+			Name:  "experimental-osv-rate-limit",
+			Usage: "maximum number of requests made to the OSV API each second, 0 for no limit",
+			Action: func(_ context.Context, _ *cli.Command, f float64) error {
+				if f < 0 {
+					return fmt.Errorf("--experimental-osv-rate-limit must not be negative, got %g", f)
+				}
+
+				return nil
+			},
+		},
 		&cli.BoolFlag{
 			Name:  "offline-vulnerabilities",
 			Usage: "checks for vulnerabilities using local databases that are already cached",
@@ -146,14 +195,29 @@ func BuildCommonScanFlags(defaultExtractors []string) []cli.Flag {
 			Usage:  "sets the path that local databases should be stored",
 			Hidden: true,
 		},
+		&cli.IntFlag{
+			Name:  "local-db-max-age-days",
+			Usage: "warn when checking for vulnerabilities using local databases last updated more than this many days ago, 0 to never warn",
+			Action: func(_ context.Context, _ *cli.Command, i int) error {
+				if i < 0 {
+					return fmt.Errorf("--local-db-max-age-days must not be negative, got %d", i)
+				}
+
+				return nil
+			},
+		},
 		&cli.StringSliceFlag{
 			Name:  "call-analysis",
-			Usage: "Enable call analysis for specific languages (e.g. --call-analysis=go). Supported: go, rust (*). (*) Will run build scripts.",
+			Usage: "Enable call analysis for specific languages (e.g. --call-analysis=go). Supported: go, rust (*), python, java, javascript. (*) Will run build scripts.",
 		},
 		&cli.StringSliceFlag{
 			Name:  "no-call-analysis",
 			Usage: "disables call graph analysis",
 		},
+		&cli.BoolFlag{
+			Name:  "require-reachable",
+			Usage: "leave out vulnerabilities that call analysis found are not called, rather than hiding them",
+		},
 		&cli.BoolFlag{
 			Name:  "no-resolve",
 			Usage: "disable transitive dependency resolution of manifest files",
@@ -170,15 +234,130 @@ func BuildCommonScanFlags(defaultExtractors []string) []cli.Flag {
 			Name:  "all-vulns",
 			Usage: "show all vulnerabilities including unimportant and uncalled ones",
 		},
+		&cli.StringSliceFlag{
+			Name:      "vex",
+			Usage:     "OpenVEX or CSAF VEX document whose not_affected statements are used to ignore vulnerabilities; can be given more than once",
+			TakesFile: true,
+		},
 		&cli.GenericFlag{
 			Name:  "licenses",
 			Usage: "report on licenses based on an allowlist",
 			Value: &allowedLicencesFlag{},
 		},
+		&cli.StringSliceFlag{
+			Name:  "license-allowlist",
+			Usage: "report packages whose licenses are not in this comma-separated list of spdx licenses, without a license summary",
+		},
+		&cli.StringSliceFlag{
+			Name:  "license-denylist",
+			Usage: "report packages that can only be used under one of these comma-separated spdx licenses",
+		},
 		&cli.BoolFlag{
 			Name:  "experimental-flag-deprecated-packages",
 			Usage: "report if package versions are deprecated",
 		},
+		&cli.BoolFlag{
+			Name:  "experimental-deprecation-reasons",
+			Usage: "look up why packages are deprecated from their registries, and report packages from archived repositories; implies --experimental-flag-deprecated-packages",
+		},
+		&cli.BoolFlag{
+			Name:  "experimental-epss",
+			Usage: "look up the EPSS score of each vulnerability from FIRST",
+		},
+		&cli.FloatFlag{
+			Name:  "experimental-min-epss",
+			Usage: "hide vulnerabilities with an EPSS score below this probability (0 to 1); vulnerabilities without a score are kept",
+			Action: func(_ context.Context, _ *cli.Command, f float64) error {
+				if f < 0 || f > 1 {
+					return fmt.Errorf("--experimental-min-epss must be between 0 and 1, got %g", f)
+				}
+
+				return nil
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "experimental-sort-by-epss",
+			Usage: "order packages and their vulnerabilities from the highest EPSS score to the lowest",
+		},
+		&cli.BoolFlag{
+			Name:  "experimental-kev",
+			Usage: "mark vulnerabilities listed in the CISA Known Exploited Vulnerabilities catalog",
+		},
+		&cli.BoolFlag{
+			Name:  "fail-on-kev",
+			Usage: "only return a failing exit code for vulnerabilities listed in the CISA Known Exploited Vulnerabilities catalog",
+		},
+		&cli.BoolFlag{
+			Name:  "experimental-secondary-advisories",
+			Usage: "fill in the severity and description of vulnerabilities missing them from the GitHub Advisory Database and the NVD; set GITHUB_TOKEN and NVD_API_KEY to avoid their rate limits",
+		},
+		&cli.BoolFlag{
+			Name:  "experimental-scorecard",
+			Usage: "look up the OpenSSF Scorecard of the source repository of each package from deps.dev",
+		},
+		&cli.FloatFlag{
+			Name:  "fail-on-scorecard-below",
+			Usage: "return a failing exit code for packages whose OpenSSF Scorecard score is below this threshold (0 to 10); packages without a scorecard pass",
+			Action: func(_ context.Context, _ *cli.Command, f float64) error {
+				if f < 0 || f > 10 {
+					return fmt.Errorf("--fail-on-scorecard-below must be between 0 and 10, got %g", f)
+				}
+
+				return nil
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "experimental-dependents",
+			Usage: "look up how many packages depend on each package version from deps.dev",
+		},
+		&cli.BoolFlag{
+			Name:  "experimental-sort-by-dependents",
+			Usage: "order packages from the one with the most dependents to the one with the fewest",
+		},
+		&cli.BoolFlag{
+			Name:  "experimental-releases",
+			Usage: "look up when each package version was released and the latest release of each package from deps.dev",
+		},
+		&cli.BoolFlag{
+			Name:  "outdated",
+			Usage: "report packages that are behind their latest release by at least --outdated-major-versions major versions or --outdated-months months",
+		},
+		&cli.IntFlag{
+			Name:  "outdated-major-versions",
+			Usage: "number of major versions behind the latest release at which --outdated reports a package, 0 to not check",
+			Value: 1,
+			Action: func(_ context.Context, _ *cli.Command, i int) error {
+				if i < 0 {
+					return fmt.Errorf("--outdated-major-versions must not be negative, got %d", i)
+				}
+
+				return nil
+			},
+		},
+		&cli.IntFlag{
+			Name:  "outdated-months",
+			Usage: "number of months behind the latest release at which --outdated reports a package, 0 to not check",
+			Value: 12,
+			Action: func(_ context.Context, _ *cli.Command, i int) error {
+				if i < 0 {
+					return fmt.Errorf("--outdated-months must not be negative, got %d", i)
+				}
+
+				return nil
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "experimental-typosquats",
+			Usage: "report packages whose names are within a few characters of popular packages in the same ecosystem",
+		},
+		&cli.BoolFlag{
+			Name:  "experimental-provenance",
+			Usage: "look up whether each npm and PyPI package version was published with verified Sigstore provenance from deps.dev",
+		},
+		&cli.BoolFlag{
+			Name:  "fail-on-missing-provenance",
+			Usage: "return a failing exit code for npm and PyPI packages without verified provenance",
+		},
 		&cli.StringSliceFlag{
 			Name:  "experimental-plugins",
 			Usage: "list of specific plugins and presets of plugins to use",
@@ -3,7 +3,9 @@ package helper
 import (
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/google/osv-scanner/v2/internal/spdx"
 	"github.com/google/osv-scanner/v2/pkg/osvscanner"
@@ -11,25 +13,48 @@ import (
 )
 
 func GetScanLicensesAllowlist(cmd *cli.Command) ([]string, error) {
+	allowlist := cmd.StringSlice("license-allowlist")
+	if err := checkSPDXLicenses("license-allowlist", allowlist); err != nil {
+		return nil, err
+	}
+
 	if !cmd.IsSet("licenses") {
-		return []string{}, nil
+		return allowlist, nil
 	}
 
-	allowlist := cmd.Generic("licenses").(*allowedLicencesFlag).allowlist
+	licenses := cmd.Generic("licenses").(*allowedLicencesFlag).allowlist
 
-	if len(allowlist) == 0 {
-		return []string{}, nil
+	if len(licenses) == 0 {
+		return allowlist, nil
 	}
 
-	if unrecognized := spdx.Unrecognized(allowlist); len(unrecognized) > 0 {
-		return nil, fmt.Errorf("--licenses requires comma-separated spdx licenses. The following license(s) are not recognized as spdx: %s", strings.Join(unrecognized, ","))
+	if err := checkSPDXLicenses("licenses", licenses); err != nil {
+		return nil, err
 	}
 
 	if cmd.Bool("offline") {
-		allowlist = []string{}
+		return allowlist, nil
+	}
+
+	return append(allowlist, licenses...), nil
+}
+
+// GetScanLicensesDenylist returns the licenses that packages must not use.
+func GetScanLicensesDenylist(cmd *cli.Command) ([]string, error) {
+	denylist := cmd.StringSlice("license-denylist")
+	if err := checkSPDXLicenses("license-denylist", denylist); err != nil {
+		return nil, err
+	}
+
+	return denylist, nil
+}
+
+func checkSPDXLicenses(flag string, licenses []string) error {
+	if unrecognized := spdx.Unrecognized(licenses); len(unrecognized) > 0 {
+		return fmt.Errorf("--%s requires comma-separated spdx licenses. The following license(s) are not recognized as spdx: %s", flag, strings.Join(unrecognized, ","))
 	}
 
-	return allowlist, nil
+	return nil
 }
 
 func GetCommonScannerActions(cmd *cli.Command, scanLicensesAllowlist []string) osvscanner.ScannerActions {
@@ -38,23 +63,50 @@ func GetCommonScannerActions(cmd *cli.Command, scanLicensesAllowlist []string) o
 	return osvscanner.ScannerActions{
 		IncludeGitRoot:        cmd.Bool("include-git-root"),
 		ConfigOverridePath:    cmd.String("config"),
+		FailOnExpiredIgnores:  cmd.Bool("fail-on-expired-ignores"),
+		RequireIgnoreReasons:  cmd.Bool("require-ignore-reasons"),
 		ShowAllPackages:       cmd.Bool("all-packages"),
 		ShowAllVulns:          cmd.Bool("all-vulns"),
+		VEXPaths:              cmd.StringSlice("vex"),
 		CompareOffline:        cmd.Bool("offline-vulnerabilities"),
 		DownloadDatabases:     cmd.Bool("download-offline-databases"),
 		LocalDBPath:           cmd.String("local-db-path"),
+		LocalDBMaxAge:         time.Duration(cmd.Int("local-db-max-age-days")) * 24 * time.Hour,
 		ScanLicensesSummary:   cmd.IsSet("licenses"),
 		ScanLicensesAllowlist: scanLicensesAllowlist,
 		CallAnalysisStates:    callAnalysisStates,
+		RequireReachable:      cmd.Bool("require-reachable"),
 	}
 }
 
 func GetExperimentalScannerActions(cmd *cli.Command, client *http.Client) osvscanner.ExperimentalScannerActions {
 	return osvscanner.ExperimentalScannerActions{
-		PluginsEnabled:         cmd.StringSlice("experimental-plugins"),
-		PluginsDisabled:        cmd.StringSlice("experimental-disable-plugins"),
-		PluginsNoDefaults:      cmd.Bool("experimental-no-default-plugins"),
-		HTTPClient:             client,
-		FlagDeprecatedPackages: cmd.Bool("experimental-flag-deprecated-packages"),
+		PluginsEnabled:           cmd.StringSlice("experimental-plugins"),
+		PluginsDisabled:          cmd.StringSlice("experimental-disable-plugins"),
+		PluginsNoDefaults:        cmd.Bool("experimental-no-default-plugins"),
+		HTTPClient:               client,
+		FlagDeprecatedPackages:   cmd.Bool("experimental-flag-deprecated-packages") || cmd.Bool("experimental-deprecation-reasons"),
+		DeprecationReasons:       cmd.Bool("experimental-deprecation-reasons"),
+		OSVMaxConcurrentRequests: cmd.Int("experimental-osv-concurrency"),
+		OSVRequestsPerSecond:     cmd.Float("experimental-osv-rate-limit"),
+		EPSS:                     cmd.Bool("experimental-epss"),
+		MinEPSS:                  cmd.Float("experimental-min-epss"),
+		SortByEPSS:               cmd.Bool("experimental-sort-by-epss"),
+		KEV:                      cmd.Bool("experimental-kev"),
+		FailOnKEV:                cmd.Bool("fail-on-kev"),
+		SecondaryAdvisories:      cmd.Bool("experimental-secondary-advisories"),
+		GitHubToken:              os.Getenv("GITHUB_TOKEN"),
+		NVDAPIKey:                os.Getenv("NVD_API_KEY"),
+		Scorecard:                cmd.Bool("experimental-scorecard"),
+		FailOnScorecardBelow:     cmd.Float("fail-on-scorecard-below"),
+		Dependents:               cmd.Bool("experimental-dependents"),
+		SortByDependents:         cmd.Bool("experimental-sort-by-dependents"),
+		ReleaseInfo:              cmd.Bool("experimental-releases"),
+		Outdated:                 cmd.Bool("outdated"),
+		OutdatedMajorVersions:    cmd.Int("outdated-major-versions"),
+		OutdatedMonths:           cmd.Int("outdated-months"),
+		Typosquats:               cmd.Bool("experimental-typosquats"),
+		Provenance:               cmd.Bool("experimental-provenance"),
+		FailOnMissingProvenance:  cmd.Bool("fail-on-missing-provenance"),
 	}
 }
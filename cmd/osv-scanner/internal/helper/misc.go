@@ -6,9 +6,13 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/internal/reporter"
 	"github.com/google/osv-scanner/v2/pkg/models"
 	"golang.org/x/term"
@@ -39,14 +43,53 @@ func ServeHTML(outputPath string) {
 	}
 }
 
-func PrintResult(stdout, stderr io.Writer, outputPath, format string, diffVulns *models.VulnerabilityResults, showAllVulns bool) error {
+// OutputFormat is a format to print the results in, and the path of the file
+// to write them to, which is empty for the format written to --output or
+// stdout
+type OutputFormat struct {
+	Format string
+	Path   string
+}
+
+// ParseOutputFormats parses the values of --format, which are either a format
+// or a "format:path" pair. The format without a path is returned first, and
+// defaults to "table" if every format was given a path.
+func ParseOutputFormats(values []string) ([]OutputFormat, error) {
+	var primary *OutputFormat
+	var formats []OutputFormat
+
+	for _, value := range values {
+		format, path, _ := strings.Cut(value, ":")
+		if !slices.Contains(reporter.Format(), format) {
+			return nil, fmt.Errorf("unsupported output format \"%s\" - must be one of: %s", format, strings.Join(reporter.Format(), ", "))
+		}
+		if path != "" {
+			formats = append(formats, OutputFormat{Format: format, Path: path})
+			continue
+		}
+		if primary != nil {
+			return nil, fmt.Errorf("only one format can be written to --output or stdout, but got both %q and %q - give the others a path using --format=%s:path", primary.Format, format, format)
+		}
+		primary = &OutputFormat{Format: format}
+	}
+
+	if primary == nil {
+		primary = &OutputFormat{Format: "table"}
+	}
+
+	return append([]OutputFormat{*primary}, formats...), nil
+}
+
+func PrintResult(stdout, stderr io.Writer, outputPath, format, templateFile string, diffVulns *models.VulnerabilityResults, showAllVulns bool) error {
 	termWidth := 0
 	var err error
 	if outputPath != "" { // Output is definitely a file
-		stdout, err = os.Create(outputPath)
+		f, err := os.Create(outputPath)
 		if err != nil {
 			return fmt.Errorf("failed to create output file: %w", err)
 		}
+		defer f.Close()
+		stdout = f
 	} else { // Output might be a terminal
 		if stdoutAsFile, ok := stdout.(*os.File); ok {
 			termWidth, _, err = term.GetSize(int(stdoutAsFile.Fd()))
@@ -58,9 +101,65 @@ func PrintResult(stdout, stderr io.Writer, outputPath, format string, diffVulns
 
 	writer := stdout
 
-	if format == "gh-annotations" {
+	// Annotations are picked up by GitHub from stderr, unless they are being
+	// written to a file
+	if format == "gh-annotations" && outputPath == "" {
 		writer = stderr
 	}
 
+	if format == "template" {
+		return reporter.PrintTemplateResult(diffVulns, templateFile, writer)
+	}
+
 	return reporter.PrintResult(diffVulns, format, writer, termWidth, showAllVulns)
 }
+
+// PrintResults prints the results in each of the given formats, writing the
+// one without a path to outputPath or stdout and the others to their own paths.
+func PrintResults(stdout, stderr io.Writer, outputPath string, formats []OutputFormat, templateFile string, diffVulns *models.VulnerabilityResults, showAllVulns bool) error {
+	for _, f := range formats {
+		path := f.Path
+		if path == "" {
+			path = outputPath
+		}
+		if err := PrintResult(stdout, stderr, path, f.Format, templateFile, diffVulns, showAllVulns); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// graphExporters maps the file extensions supported by ExportGraph to the
+// function writing the graph in that format.
+var graphExporters = map[string]func(*models.VulnerabilityResults, io.Writer) error{
+	".dot":     output.PrintDOTGraph,
+	".gv":      output.PrintDOTGraph,
+	".graphml": output.PrintGraphMLGraph,
+}
+
+// ValidateGraphExportPath checks that the format of the dependency graph can
+// be inferred from the extension of path.
+func ValidateGraphExportPath(path string) error {
+	if _, ok := graphExporters[strings.ToLower(filepath.Ext(path))]; !ok {
+		return fmt.Errorf("unsupported graph file \"%s\" - extension must be one of: .dot, .gv, .graphml", path)
+	}
+
+	return nil
+}
+
+// ExportGraph writes the dependency graph of the packages in the results to
+// path, as DOT or GraphML depending on its extension.
+func ExportGraph(path string, vulnResult *models.VulnerabilityResults) error {
+	if err := ValidateGraphExportPath(path); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create graph file: %w", err)
+	}
+	defer f.Close()
+
+	return graphExporters[strings.ToLower(filepath.Ext(path))](vulnResult, f)
+}
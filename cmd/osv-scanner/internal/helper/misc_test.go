@@ -0,0 +1,65 @@
+package helper_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/cmd/osv-scanner/internal/helper"
+)
+
+func TestParseOutputFormats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		values  []string
+		want    []helper.OutputFormat
+		wantErr bool
+	}{
+		{
+			name:   "single format",
+			values: []string{"json"},
+			want:   []helper.OutputFormat{{Format: "json"}},
+		},
+		{
+			name:   "formats with paths",
+			values: []string{"sarif:results.sarif", "json", "cyclonedx-1-5:C:\\results\\bom.json"},
+			want: []helper.OutputFormat{
+				{Format: "json"},
+				{Format: "sarif", Path: "results.sarif"},
+				{Format: "cyclonedx-1-5", Path: "C:\\results\\bom.json"},
+			},
+		},
+		{
+			name:   "every format has a path",
+			values: []string{"sarif:results.sarif"},
+			want: []helper.OutputFormat{
+				{Format: "table"},
+				{Format: "sarif", Path: "results.sarif"},
+			},
+		},
+		{
+			name:    "unknown format",
+			values:  []string{"unknown:results.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "several formats without a path",
+			values:  []string{"json", "table"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := helper.ParseOutputFormats(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOutputFormats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseOutputFormats() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -3,9 +3,11 @@ package main
 import (
 	"os"
 
+	"github.com/google/osv-scanner/v2/cmd/osv-scanner/db"
 	"github.com/google/osv-scanner/v2/cmd/osv-scanner/fix"
 	"github.com/google/osv-scanner/v2/cmd/osv-scanner/internal/cmd"
 	"github.com/google/osv-scanner/v2/cmd/osv-scanner/mcp"
+	"github.com/google/osv-scanner/v2/cmd/osv-scanner/query"
 	"github.com/google/osv-scanner/v2/cmd/osv-scanner/scan"
 	"github.com/google/osv-scanner/v2/cmd/osv-scanner/update"
 )
@@ -15,7 +17,9 @@ func main() {
 		cmd.Run(os.Args, os.Stdout, os.Stderr, nil, []cmd.CommandBuilder{
 			scan.Command,
 			fix.Command,
+			query.Command,
 			update.Command,
+			db.Command,
 			mcp.Command,
 		}),
 	)
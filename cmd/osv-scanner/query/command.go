@@ -0,0 +1,260 @@
+// Package query implements the `query` command for osv-scanner, which looks up
+// what is known about a single package version without scanning a project.
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+
+	depsdevpb "deps.dev/api/v3"
+	"github.com/google/osv-scalibr/clients/datasource"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/sbom/purllist"
+	"github.com/google/osv-scanner/v2/internal/version"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/google/osv-scanner/v2/pkg/osvscanner"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
+	"github.com/urfave/cli/v3"
+)
+
+const userAgent = "osv-scanner_query/" + version.OSVVersion
+
+func Command(stdout, _ io.Writer, client *http.Client) *cli.Command {
+	return &cli.Command{
+		Name:      "query",
+		Usage:     "looks up the vulnerabilities, dependencies and licenses of a single package version",
+		ArgsUsage: "<type>/<name>@<version>",
+		Description: "looks up the vulnerabilities, transitive dependencies, licenses and fixed versions of a package version, " +
+			"given as a package URL with or without the pkg: prefix (e.g. pypi/django@4.2.1).",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "sets the output format; value can be: table, json",
+				Value:   "table",
+				Action: func(_ context.Context, _ *cli.Command, s string) error {
+					if s == "table" || s == "json" {
+						if s == "json" {
+							cmdlogger.SendEverythingToStderr()
+						}
+
+						return nil
+					}
+
+					return fmt.Errorf("unsupported output format \"%s\" - must be one of: table, json", s)
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "offline-vulnerabilities",
+				Usage: "checks for vulnerabilities using local databases that are already cached, without looking up dependencies or licenses",
+			},
+			&cli.StringFlag{
+				Name:  "local-db-path",
+				Usage: "sets the path that local databases should be stored",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.NArg() != 1 {
+				return errors.New("exactly one package must be given, e.g. `osv-scanner query pypi/django@4.2.1`")
+			}
+
+			return action(ctx, cmd, stdout, client)
+		},
+	}
+}
+
+// result is what is known about the queried package.
+type result struct {
+	// Package is the package as it was scanned, with its vulnerabilities and
+	// licenses
+	Package models.PackageVulns `json:"package"`
+	// Dependencies are the counts of the package's dependencies, if they were
+	// looked up and deps.dev knows of the version
+	Dependencies *dependencyCount `json:"dependencies,omitempty"`
+}
+
+// dependencyCount is how many packages a package version depends on.
+type dependencyCount struct {
+	Direct     int `json:"direct"`
+	Transitive int `json:"transitive"`
+}
+
+func action(ctx context.Context, cmd *cli.Command, stdout io.Writer, client *http.Client) error {
+	packageURL := toPackageURL(cmd.Args().First())
+	if _, err := purllist.ToPackage(packageURL); err != nil {
+		return err
+	}
+
+	offline := cmd.Bool("offline-vulnerabilities")
+
+	//nolint:contextcheck // passing the context in would be a breaking change
+	vulnResult, err := osvscanner.DoScan(osvscanner.ScannerActions{
+		PURLs:               []string{packageURL},
+		ShowAllPackages:     true,
+		CompareOffline:      offline,
+		LocalDBPath:         cmd.String("local-db-path"),
+		ScanLicensesSummary: !offline,
+		ExperimentalScannerActions: osvscanner.ExperimentalScannerActions{
+			HTTPClient:       client,
+			RequestUserAgent: userAgent,
+			// the package's dependencies are counted, rather than scanned
+			TransitiveScanning: osvscanner.TransitiveScanningActions{Disabled: true},
+		},
+	})
+	if err != nil && !errors.Is(err, osvscanner.ErrVulnerabilitiesFound) {
+		return err
+	}
+
+	res := result{Package: queriedPackage(vulnResult)}
+
+	if !offline {
+		res.Dependencies = lookUpDependencies(ctx, res.Package.Package)
+	}
+
+	if cmd.String("format") == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if errPrint := encoder.Encode(&res); errPrint != nil {
+			return fmt.Errorf("failed to write output: %w", errPrint)
+		}
+	} else if errPrint := printTable(stdout, res); errPrint != nil {
+		return fmt.Errorf("failed to write output: %w", errPrint)
+	}
+
+	// This may be nil.
+	return err
+}
+
+// toPackageURL returns the package URL of a package given with or without
+// the pkg: prefix.
+func toPackageURL(arg string) string {
+	if strings.HasPrefix(arg, "pkg:") {
+		return arg
+	}
+
+	return "pkg:" + arg
+}
+
+// queriedPackage returns the only package of the results.
+func queriedPackage(vulnResult models.VulnerabilityResults) models.PackageVulns {
+	for _, source := range vulnResult.Results {
+		for _, pkg := range source.Packages {
+			return pkg
+		}
+	}
+
+	return models.PackageVulns{}
+}
+
+// lookUpDependencies counts the dependencies of a package version, returning
+// nil if they could not be looked up.
+func lookUpDependencies(ctx context.Context, pkg models.PackageInfo) *dependencyCount {
+	system, ok := depsdev.System[osvconstants.Ecosystem(pkg.Ecosystem)]
+	if !ok {
+		cmdlogger.Warnf("Dependencies of %s packages cannot be looked up", pkg.Ecosystem)
+
+		return nil
+	}
+
+	client, err := datasource.NewCachedInsightsClient(depsdev.DepsdevAPI, userAgent)
+	if err != nil {
+		cmdlogger.Warnf("Failed to look up dependencies: %v", err)
+
+		return nil
+	}
+
+	count, err := countDependencies(ctx, client, &depsdevpb.VersionKey{
+		System:  system,
+		Name:    pkg.Name,
+		Version: pkg.Version,
+	})
+	if err != nil {
+		cmdlogger.Warnf("Failed to look up the dependencies of %s@%s: %v", pkg.Name, pkg.Version, err)
+
+		return nil
+	}
+
+	return count
+}
+
+// countDependencies counts the distinct package versions in the resolved
+// dependency graph of a package version.
+func countDependencies(ctx context.Context, client depsdevpb.InsightsClient, key *depsdevpb.VersionKey) (*dependencyCount, error) {
+	graph, err := client.GetDependencies(ctx, &depsdevpb.GetDependenciesRequest{VersionKey: key})
+	if err != nil {
+		return nil, err
+	}
+
+	count := &dependencyCount{}
+	seen := make(map[string]bool)
+	for _, node := range graph.GetNodes() {
+		if node.GetRelation() == depsdevpb.DependencyRelation_SELF {
+			continue
+		}
+
+		id := node.GetVersionKey().GetName() + "@" + node.GetVersionKey().GetVersion()
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		count.Transitive++
+		if node.GetRelation() == depsdevpb.DependencyRelation_DIRECT {
+			count.Direct++
+		}
+	}
+
+	return count, nil
+}
+
+// printTable writes a summary of the package, followed by a row for each
+// group of its vulnerabilities.
+func printTable(stdout io.Writer, res result) error {
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+
+	pkg := res.Package.Package
+	fmt.Fprintf(w, "Package:\t%s %s (%s)\n", pkg.Name, pkg.Version, pkg.Ecosystem)
+
+	licenses := "unknown"
+	if len(res.Package.Licenses) > 0 {
+		names := make([]string, 0, len(res.Package.Licenses))
+		for _, license := range res.Package.Licenses {
+			names = append(names, string(license))
+		}
+		licenses = strings.Join(names, ", ")
+	}
+	fmt.Fprintf(w, "Licenses:\t%s\n", licenses)
+
+	dependencies := "unknown"
+	if res.Dependencies != nil {
+		dependencies = fmt.Sprintf("%d (%d direct)", res.Dependencies.Transitive, res.Dependencies.Direct)
+	}
+	fmt.Fprintf(w, "Dependencies:\t%s\n", dependencies)
+	fmt.Fprintf(w, "Vulnerabilities:\t%d\n", len(res.Package.Groups))
+
+	if len(res.Package.Groups) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "ID\tSEVERITY\tFIXED VERSION")
+		for _, group := range res.Package.Groups {
+			severity := group.MaxSeverity
+			if severity == "" {
+				severity = "-"
+			}
+			fixed := group.FixedVersion
+			if fixed == "" {
+				fixed = output.UnfixedDescription
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", strings.Join(group.IDs, ", "), severity, fixed)
+		}
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,103 @@
+package query
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	depsdevpb "deps.dev/api/v3"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"google.golang.org/grpc"
+)
+
+// fakeInsightsClient serves a single dependency graph.
+type fakeInsightsClient struct {
+	depsdevpb.InsightsClient
+
+	graph *depsdevpb.Dependencies
+}
+
+func (c *fakeInsightsClient) GetDependencies(_ context.Context, _ *depsdevpb.GetDependenciesRequest, _ ...grpc.CallOption) (*depsdevpb.Dependencies, error) {
+	return c.graph, nil
+}
+
+func node(name, version string, relation depsdevpb.DependencyRelation) *depsdevpb.Dependencies_Node {
+	return &depsdevpb.Dependencies_Node{
+		VersionKey: &depsdevpb.VersionKey{System: depsdevpb.System_PYPI, Name: name, Version: version},
+		Relation:   relation,
+	}
+}
+
+func Test_countDependencies(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeInsightsClient{graph: &depsdevpb.Dependencies{
+		Nodes: []*depsdevpb.Dependencies_Node{
+			node("django", "4.2.1", depsdevpb.DependencyRelation_SELF),
+			node("asgiref", "3.7.2", depsdevpb.DependencyRelation_DIRECT),
+			node("sqlparse", "0.4.4", depsdevpb.DependencyRelation_DIRECT),
+			node("typing-extensions", "4.8.0", depsdevpb.DependencyRelation_INDIRECT),
+			// a version can be in the graph more than once
+			node("typing-extensions", "4.8.0", depsdevpb.DependencyRelation_INDIRECT),
+		},
+	}}
+
+	got, err := countDependencies(t.Context(), client, &depsdevpb.VersionKey{System: depsdevpb.System_PYPI, Name: "django", Version: "4.2.1"})
+	if err != nil {
+		t.Fatalf("countDependencies() error = %v", err)
+	}
+
+	want := &dependencyCount{Direct: 2, Transitive: 3}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("countDependencies() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_printTable(t *testing.T) {
+	t.Parallel()
+
+	res := result{
+		Package: models.PackageVulns{
+			Package:  models.PackageInfo{Name: "django", Version: "4.2.1", Ecosystem: "PyPI"},
+			Licenses: []models.License{"BSD-3-Clause"},
+			Groups: []models.GroupInfo{
+				{IDs: []string{"GHSA-7h4p-27mh-hmrw", "PYSEC-2023-225"}, MaxSeverity: "7.5", FixedVersion: "4.2.7"},
+				{IDs: []string{"GHSA-xxxx-xxxx-xxxx"}},
+			},
+		},
+		Dependencies: &dependencyCount{Direct: 2, Transitive: 3},
+	}
+
+	var out strings.Builder
+	if err := printTable(&out, res); err != nil {
+		t.Fatalf("printTable() error = %v", err)
+	}
+
+	want := `Package:          django 4.2.1 (PyPI)
+Licenses:         BSD-3-Clause
+Dependencies:     3 (2 direct)
+Vulnerabilities:  2
+
+ID                                   SEVERITY  FIXED VERSION
+GHSA-7h4p-27mh-hmrw, PYSEC-2023-225  7.5       4.2.7
+GHSA-xxxx-xxxx-xxxx                  -         No fix available
+`
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("printTable() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_toPackageURL(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"pypi/django@4.2.1":      "pkg:pypi/django@4.2.1",
+		"pkg:npm/lodash@4.17.20": "pkg:npm/lodash@4.17.20",
+	}
+	for arg, want := range tests {
+		if got := toPackageURL(arg); got != want {
+			t.Errorf("toPackageURL(%q) = %q, want %q", arg, got, want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/v2/cmd/osv-scanner/internal/testcmd"
+)
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []testcmd.Case{
+		{
+			Name: "no_package",
+			Args: []string{"", "query"},
+			Exit: 127,
+		},
+		{
+			Name: "several_packages",
+			Args: []string{"", "query", "pypi/django@4.2.1", "npm/lodash@4.17.20"},
+			Exit: 127,
+		},
+		{
+			Name: "invalid_package_url",
+			Args: []string{"", "query", "django"},
+			Exit: 127,
+		},
+		{
+			Name: "no_version",
+			Args: []string{"", "query", "pypi/django"},
+			Exit: 127,
+		},
+		{
+			Name: "unsupported_format",
+			Args: []string{"", "query", "--format", "sarif", "pypi/django@4.2.1"},
+			Exit: 127,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+
+			testcmd.RunAndMatchSnapshots(t, tt)
+		})
+	}
+}
@@ -29,6 +29,18 @@ func Command(stdout, stderr io.Writer, client *http.Client) *cli.Command {
 				Name:  "archive",
 				Usage: "input a local archive image (e.g. a tar file)",
 			},
+			&cli.BoolFlag{
+				Name:  "remote",
+				Usage: "pull the image straight from its registry rather than through the local docker daemon, using docker's credentials and credential helpers",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-recommend-base-image-upgrade",
+				Usage: "compare the image's base image to its newest tag of the same major version and variant, reporting how many of its vulnerabilities upgrading would eliminate",
+			},
+			&cli.StringFlag{
+				Name:  "experimental-base-image-upgrade",
+				Usage: "compare the image's base image to the given tag of it (e.g. latest) or image reference instead of its newest tag",
+			},
 		}, helper.BuildCommonScanFlags([]string{"artifact"})...),
 		ArgsUsage: "[image imageNameWithTag]",
 		Action: func(ctx context.Context, cmd *cli.Command) error {
@@ -43,16 +55,25 @@ func action(_ context.Context, cmd *cli.Command, stdout, stderr io.Writer, clien
 	}
 
 	isImageArchive := cmd.Bool("archive")
+	if isImageArchive && cmd.Bool("remote") {
+		return errors.New("--archive and --remote cannot be used together")
+	}
+
 	image := cmd.Args().First()
 	if !isImageArchive && !strings.Contains(image, ":") {
 		return fmt.Errorf("%q is not a tagged image name", image)
 	}
 
-	format := cmd.String("format")
+	formats, err := helper.ParseOutputFormats(cmd.StringSlice("format"))
+	if err != nil {
+		return err
+	}
+	format := formats[0].Format
 	outputPath := cmd.String("output")
 	serve := cmd.Bool("serve")
 	if serve {
 		format = "html"
+		formats[0].Format = format
 		if outputPath == "" {
 			// Create a temporary directory
 			tmpDir, err := os.MkdirTemp("", "osv-scanner-result")
@@ -72,12 +93,21 @@ func action(_ context.Context, cmd *cli.Command, stdout, stderr io.Writer, clien
 		return err
 	}
 
+	scanLicensesDenylist, err := helper.GetScanLicensesDenylist(cmd)
+	if err != nil {
+		return err
+	}
+
 	scannerAction := helper.GetCommonScannerActions(cmd, scanLicensesAllowlist)
+	scannerAction.ScanLicensesDenylist = scanLicensesDenylist
 
 	scannerAction.Image = cmd.Args().First()
 	scannerAction.IsImageArchive = cmd.Bool("archive")
+	scannerAction.IsImageRemote = cmd.Bool("remote")
 	scannerAction.ExperimentalScannerActions = helper.GetExperimentalScannerActions(cmd, client)
 	scannerAction.RequestUserAgent = "osv-scanner_scan-image/" + version.OSVVersion
+	scannerAction.RecommendBaseImageUpgrade = cmd.Bool("experimental-recommend-base-image-upgrade")
+	scannerAction.BaseImageUpgrade = cmd.String("experimental-base-image-upgrade")
 	var vulnResult models.VulnerabilityResults
 	//nolint:contextcheck // passing the context in would be a breaking change
 	vulnResult, err = osvscanner.DoContainerScan(scannerAction)
@@ -91,7 +121,7 @@ func action(_ context.Context, cmd *cli.Command, stdout, stderr io.Writer, clien
 		return err
 	}
 
-	if errPrint := helper.PrintResult(stdout, stderr, outputPath, format, &vulnResult, scannerAction.ShowAllVulns); errPrint != nil {
+	if errPrint := helper.PrintResults(stdout, stderr, outputPath, formats, cmd.String("template-file"), &vulnResult, scannerAction.ShowAllVulns); errPrint != nil {
 		return fmt.Errorf("failed to write output: %w", errPrint)
 	}
 
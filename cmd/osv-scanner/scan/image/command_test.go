@@ -389,8 +389,11 @@ func TestCommand_OCIImage_JSONFormat(t *testing.T) {
 				testutility.OnlyIDVulnsRule,
 				testutility.OnlyFirstBaseImage,
 				testutility.AnyDiffID,
+				testutility.AnyOriginDiffID,
 				testutility.NormalizeHistoryCommand,
+				testutility.NormalizeOriginCommand,
 				testutility.ShortenHistoryCommandLength,
+				testutility.ShortenOriginCommandLength,
 			},
 		},
 		{
@@ -402,8 +405,11 @@ func TestCommand_OCIImage_JSONFormat(t *testing.T) {
 				testutility.OnlyIDVulnsRule,
 				testutility.OnlyFirstBaseImage,
 				testutility.AnyDiffID,
+				testutility.AnyOriginDiffID,
 				testutility.NormalizeHistoryCommand,
+				testutility.NormalizeOriginCommand,
 				testutility.ShortenHistoryCommandLength,
+				testutility.ShortenOriginCommandLength,
 			},
 		},
 		{
@@ -415,7 +421,9 @@ func TestCommand_OCIImage_JSONFormat(t *testing.T) {
 				testutility.OnlyIDVulnsRule,
 				testutility.OnlyFirstBaseImage,
 				testutility.AnyDiffID,
+				testutility.AnyOriginDiffID,
 				testutility.NormalizeHistoryCommand,
+				testutility.NormalizeOriginCommand,
 			},
 		},
 		{
@@ -427,6 +435,7 @@ func TestCommand_OCIImage_JSONFormat(t *testing.T) {
 				testutility.OnlyIDVulnsRule,
 				testutility.OnlyFirstBaseImage,
 				testutility.AnyDiffID,
+				testutility.AnyOriginDiffID,
 			},
 		},
 		{
@@ -439,6 +448,7 @@ func TestCommand_OCIImage_JSONFormat(t *testing.T) {
 				testutility.OnlyIDVulnsRule,
 				testutility.OnlyFirstBaseImage,
 				testutility.AnyDiffID,
+				testutility.AnyOriginDiffID,
 			},
 		},
 		{
@@ -455,6 +465,7 @@ func TestCommand_OCIImage_JSONFormat(t *testing.T) {
 				testutility.OnlyIDVulnsRule,
 				testutility.OnlyFirstBaseImage,
 				testutility.AnyDiffID,
+				testutility.AnyOriginDiffID,
 			},
 		},
 		{
@@ -471,6 +482,7 @@ func TestCommand_OCIImage_JSONFormat(t *testing.T) {
 				testutility.OnlyIDVulnsRule,
 				testutility.OnlyFirstBaseImage,
 				testutility.AnyDiffID,
+				testutility.AnyOriginDiffID,
 			},
 		},
 		{
@@ -486,6 +498,7 @@ func TestCommand_OCIImage_JSONFormat(t *testing.T) {
 				testutility.OnlyIDVulnsRule,
 				testutility.OnlyFirstBaseImage,
 				testutility.AnyDiffID,
+				testutility.AnyOriginDiffID,
 			},
 		},
 	}
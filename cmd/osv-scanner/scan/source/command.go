@@ -9,9 +9,16 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/osv-scanner/v2/cmd/osv-scanner/internal/helper"
+	"github.com/google/osv-scanner/v2/internal/ci"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/google/osv-scanner/v2/internal/httpcassette"
+	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/internal/version"
 	"github.com/google/osv-scanner/v2/pkg/models"
 	"github.com/google/osv-scanner/v2/pkg/osvscanner"
@@ -30,6 +37,18 @@ func Command(stdout, stderr io.Writer, client *http.Client) *cli.Command {
 				Usage:     "scan package lockfile on this path",
 				TakesFile: true,
 			},
+			&cli.BoolFlag{
+				Name:  "stdin",
+				Usage: "scan a lockfile or manifest read from stdin, of the type given with --lockfile-type",
+			},
+			&cli.StringFlag{
+				Name:  "lockfile-type",
+				Usage: "the type of the lockfile read with --stdin, named as in the \"<type>:<path>\" form of --lockfile (e.g. requirements.txt)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "purl",
+				Usage: "scan the package with this package URL (e.g. pkg:npm/lodash@4.17.20), for when the inventory is already known",
+			},
 			&cli.StringSliceFlag{
 				Name:    "sbom",
 				Aliases: []string{"S"},
@@ -57,6 +76,32 @@ func Command(stdout, stderr io.Writer, client *http.Client) *cli.Command {
 				Usage: "include scanning git root (non-submoduled) repositories",
 				Value: false,
 			},
+			&cli.StringFlag{
+				Name:  "git-ref",
+				Usage: "scan the given directories, which must be git repositories, at this commit, tag or branch without checking it out",
+			},
+			&cli.StringFlag{
+				Name:      "baseline",
+				Usage:     "only report, and fail because of, the vulnerabilities that are not in this earlier JSON result, such as one of the target branch",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:  "baseline-git-ref",
+				Usage: "only report, and fail because of, the vulnerabilities that are not found when scanning the given git repositories at this commit, tag or branch",
+			},
+			&cli.BoolFlag{
+				Name:  "watch",
+				Usage: "keep running, and scan again whenever the scanned files change, writing the results of each scan as a line of JSON",
+			},
+			&cli.DurationFlag{
+				Name:  "watch-interval",
+				Usage: "how often to check for changes in watch mode",
+				Value: 2 * time.Second,
+			},
+			&cli.StringFlag{
+				Name:  "watch-endpoint",
+				Usage: "URL to also POST the results of each scan to in watch mode",
+			},
 			&cli.StringSliceFlag{
 				Name:  "experimental-exclude",
 				Usage: "exclude directory paths during scanning; use g:pattern for glob, r:pattern for regex, or just dirname for exact match (can be repeated)",
@@ -77,6 +122,146 @@ func Command(stdout, stderr io.Writer, client *http.Client) *cli.Command {
 				Name:  "maven-registry",
 				Usage: "URL of the default registry to fetch Maven metadata",
 			},
+			&cli.IntFlag{
+				Name:  "experimental-resolve-parallelism",
+				Usage: "maximum number of concurrent deps.dev lookups made when resolving a single manifest",
+				Value: depsdev.DefaultParallelism,
+			},
+			&cli.IntFlag{
+				Name:  "experimental-deps-dev-cache-entries",
+				Usage: "maximum number of deps.dev dependency graphs kept in memory",
+				Value: depsdev.DefaultCacheLimits.MaxEntries,
+			},
+			&cli.Int64Flag{
+				Name:  "experimental-deps-dev-cache-bytes",
+				Usage: "approximate maximum memory in bytes used by cached deps.dev dependency graphs",
+				Value: depsdev.DefaultCacheLimits.MaxBytes,
+			},
+			&cli.StringFlag{
+				Name:      "experimental-deps-dev-snapshot",
+				Usage:     "resolve transitive dependencies from a local deps.dev snapshot directory or bundle instead of the live API; also applies in offline mode",
+				TakesFile: true,
+			},
+			&cli.StringSliceFlag{
+				Name:  "experimental-deps-dev-header",
+				Usage: "additional header sent with every deps.dev request, in the format 'Name: value' (can be repeated)",
+				Action: func(_ context.Context, _ *cli.Command, headers []string) error {
+					_, err := parseHeaders(headers)
+					return err
+				},
+			},
+			&cli.StringFlag{
+				Name:      "experimental-http-cassette",
+				Usage:     "record deps.dev and OSV HTTP traffic to, or replay it from, the cassette file at this path (without the .yaml extension)",
+				TakesFile: true,
+			},
+			&cli.StringFlag{
+				Name:  "experimental-http-cassette-mode",
+				Usage: "how to use the cassette given by --experimental-http-cassette; value can be: " + strings.Join(httpcassette.Modes(), ", "),
+				Value: string(httpcassette.ModeReplay),
+				Action: func(_ context.Context, _ *cli.Command, s string) error {
+					if !slices.Contains(httpcassette.Modes(), s) {
+						return fmt.Errorf("unsupported cassette mode \"%s\" - must be one of: %s", s, strings.Join(httpcassette.Modes(), ", "))
+					}
+
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "experimental-deps-dev-transport",
+				Usage: "transport used to query deps.dev for transitive dependencies; value can be: rest, grpc",
+				Value: string(depsdev.TransportREST),
+				Action: func(_ context.Context, _ *cli.Command, s string) error {
+					if s != string(depsdev.TransportREST) && s != string(depsdev.TransportGRPC) {
+						return fmt.Errorf("unsupported deps.dev transport \"%s\" - must be one of: rest, grpc", s)
+					}
+
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "experimental-resolve-conflicts",
+				Usage: "how to choose between the versions of a package that different dependencies of a manifest resolve to; value can be: report-all, highest-wins, nearest-wins",
+				Value: string(depsdev.ConflictReportAll),
+				Action: func(_ context.Context, _ *cli.Command, s string) error {
+					if _, err := depsdev.ParseConflictStrategy(s); err != nil {
+						return err
+					}
+
+					return nil
+				},
+			},
+			&cli.IntFlag{
+				Name:  "experimental-max-transitive-depth",
+				Usage: "maximum depth of transitive dependencies to resolve, where dependencies declared in the manifest are at depth 1; 0 for no limit",
+			},
+			&cli.StringSliceFlag{
+				Name:  "experimental-exclude-scope",
+				Usage: "exclude Maven dependencies declared with this scope, e.g. test or provided (can be repeated)",
+			},
+			&cli.StringFlag{
+				Name:  "experimental-python-version",
+				Usage: "Python version (e.g. 3.11) that environment markers in requirements files are evaluated against",
+			},
+			&cli.StringFlag{
+				Name:  "experimental-python-platform",
+				Usage: "Python platform, as in sys.platform (e.g. linux, darwin or win32), that environment markers in requirements files are evaluated against",
+			},
+			&cli.DurationFlag{
+				Name:  "experimental-enrichment-timeout",
+				Usage: "maximum time spent enriching packages (e.g. resolving transitive dependencies) before continuing with partially enriched results; 0 for no limit",
+			},
+			&cli.StringFlag{
+				Name:      "experimental-scan-cache",
+				Usage:     "cache the packages and vulnerabilities found in each file in this directory, so that files which have not changed since an earlier scan are not extracted and resolved again",
+				TakesFile: true,
+			},
+			&cli.DurationFlag{
+				Name:  "experimental-scan-cache-ttl",
+				Usage: "how long cached results are used for before files are scanned again, so that newly published vulnerabilities are found; 0 to use them until the files change",
+				Value: 24 * time.Hour,
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-dockerfile-base-images",
+				Usage: "scan the base images that Dockerfiles are built on, by pulling each image that a FROM instruction refers to from its registry",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-manifest-images",
+				Usage: "scan the container images that Kubernetes manifests and Compose files run, by pulling each image from its registry",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-helm-charts",
+				Usage: "scan the container images that Helm charts run, by rendering each chart with the helm command and pulling each image from its registry",
+			},
+			&cli.StringSliceFlag{
+				Name:      "experimental-helm-values",
+				Usage:     "render Helm charts with this values file, on top of their default values (can be repeated)",
+				TakesFile: true,
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-sbom-enrichment",
+				Usage: "fill in the dependencies that are missing from the scanned SBOMs using deps.dev; output them with --format cyclonedx-1-6 or spdx-2-3",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-lockfile-drift",
+				Usage: "report the dependencies declared in package.json and pom.xml files that are missing from, or inconsistent with, their lockfiles or resolved versions",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-verify-hashes",
+				Usage: "check the --hash values of requirements files against the files published on PyPI, reporting those that do not match as potential tampering",
+			},
+			&cli.BoolFlag{
+				Name:  "experimental-upgrade-plan",
+				Usage: "report the smallest set of version upgrades that fixes the most of the vulnerabilities found",
+			},
+			&cli.StringFlag{
+				Name:      "export-graph",
+				Usage:     "write the dependency graph, with vulnerable packages highlighted, to this file as DOT (.dot, .gv) or GraphML (.graphml); combine with --all-packages to include packages without vulnerabilities",
+				TakesFile: true,
+				Action: func(_ context.Context, _ *cli.Command, s string) error {
+					return helper.ValidateGraphExportPath(s)
+				},
+			},
 		}, helper.BuildCommonScanFlags([]string{"lockfile", "sbom", "directory"})...),
 		ArgsUsage: "[directory1 directory2...]",
 		Action: func(ctx context.Context, cmd *cli.Command) error {
@@ -85,13 +270,21 @@ func Command(stdout, stderr io.Writer, client *http.Client) *cli.Command {
 	}
 }
 
-func action(_ context.Context, cmd *cli.Command, stdout, stderr io.Writer, client *http.Client) error {
-	format := cmd.String("format")
+func action(ctx context.Context, cmd *cli.Command, stdout, stderr io.Writer, client *http.Client) error {
+	formats, err := helper.ParseOutputFormats(cmd.StringSlice("format"))
+	if err != nil {
+		return err
+	}
+	format := formats[0].Format
 
 	outputPath := cmd.String("output")
 	serve := cmd.Bool("serve")
+	if cmd.Bool("watch") && (serve || cmd.IsSet("format")) {
+		return errors.New("--serve and --format cannot be used with --watch, which writes the results of each scan as a line of JSON")
+	}
 	if serve {
 		format = "html"
+		formats[0].Format = format
 		if outputPath == "" {
 			// Create a temporary directory
 			tmpDir, err := os.MkdirTemp("", "osv-scanner-result")
@@ -111,26 +304,129 @@ func action(_ context.Context, cmd *cli.Command, stdout, stderr io.Writer, clien
 		return err
 	}
 
+	scanLicensesDenylist, err := helper.GetScanLicensesDenylist(cmd)
+	if err != nil {
+		return err
+	}
+
+	if cassettePath := cmd.String("experimental-http-cassette"); cassettePath != "" {
+		var base http.RoundTripper
+		if client != nil {
+			base = client.Transport
+		}
+
+		cassette, err := httpcassette.New(cassettePath, httpcassette.Mode(cmd.String("experimental-http-cassette-mode")), base)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := cassette.Stop(); err != nil {
+				cmdlogger.Errorf("Failed to save HTTP cassette: %v", err)
+			}
+		}()
+
+		client = cassette.Client
+	}
+
 	experimentalScannerActions := helper.GetExperimentalScannerActions(cmd, client)
 	experimentalScannerActions.RequestUserAgent = "osv-scanner_scan-source/" + version.OSVVersion
 	experimentalScannerActions.ExcludePatterns = cmd.StringSlice("experimental-exclude")
+	experimentalScannerActions.EnrichmentTimeout = cmd.Duration("experimental-enrichment-timeout")
+	experimentalScannerActions.ScanCacheDir = cmd.String("experimental-scan-cache")
+	experimentalScannerActions.ScanCacheTTL = cmd.Duration("experimental-scan-cache-ttl")
+	experimentalScannerActions.DockerfileBaseImages = cmd.Bool("experimental-dockerfile-base-images")
+	experimentalScannerActions.ManifestImages = cmd.Bool("experimental-manifest-images")
+	experimentalScannerActions.HelmCharts = cmd.Bool("experimental-helm-charts")
+	experimentalScannerActions.HelmValuesFiles = cmd.StringSlice("experimental-helm-values")
+	experimentalScannerActions.SBOMEnrichment = cmd.Bool("experimental-sbom-enrichment")
+	experimentalScannerActions.LockfileDrift = cmd.Bool("experimental-lockfile-drift")
+	experimentalScannerActions.VerifyHashes = cmd.Bool("experimental-verify-hashes")
+	experimentalScannerActions.UpgradePlan = cmd.Bool("experimental-upgrade-plan")
 	// Add `source` specific experimental configs
+	depsDevHeaders, err := parseHeaders(cmd.StringSlice("experimental-deps-dev-header"))
+	if err != nil {
+		return err
+	}
+
+	depsDevSnapshot := cmd.String("experimental-deps-dev-snapshot")
 	experimentalScannerActions.TransitiveScanning = osvscanner.TransitiveScanningActions{
-		Disabled:         cmd.Bool("no-resolve"),
-		NativeDataSource: cmd.String("data-source") == "native",
-		MavenRegistry:    cmd.String("maven-registry"),
+		// --offline implies --no-resolve, but a local snapshot does not need the network
+		Disabled:            cmd.Bool("no-resolve") && (depsDevSnapshot == "" || !cmd.Bool("offline")),
+		NativeDataSource:    cmd.String("data-source") == "native",
+		MavenRegistry:       cmd.String("maven-registry"),
+		Parallelism:         cmd.Int("experimental-resolve-parallelism"),
+		DepsDevGRPC:         cmd.String("experimental-deps-dev-transport") == string(depsdev.TransportGRPC),
+		DepsDevSnapshotPath: depsDevSnapshot,
+		CacheMaxEntries:     cmd.Int("experimental-deps-dev-cache-entries"),
+		CacheMaxBytes:       cmd.Int64("experimental-deps-dev-cache-bytes"),
+		DepsDevHeaders:      depsDevHeaders,
+		ConflictStrategy:    cmd.String("experimental-resolve-conflicts"),
+		MaxDepth:            cmd.Int("experimental-max-transitive-depth"),
+		MavenExcludedScopes: cmd.StringSlice("experimental-exclude-scope"),
+		PythonVersion:       cmd.String("experimental-python-version"),
+		PythonPlatform:      cmd.String("experimental-python-platform"),
 	}
 
 	scannerAction := helper.GetCommonScannerActions(cmd, scanLicensesAllowlist)
+	scannerAction.ScanLicensesDenylist = scanLicensesDenylist
 
 	scannerAction.LockfilePaths = cmd.StringSlice("lockfile")
+	if err := setStdinLockfile(cmd, &scannerAction); err != nil {
+		return err
+	}
 	//nolint:staticcheck // ignore our own deprecated field
 	scannerAction.SBOMPaths = cmd.StringSlice("sbom")
 	scannerAction.Recursive = cmd.Bool("recursive")
 	scannerAction.NoIgnore = cmd.Bool("no-ignore")
-	scannerAction.DirectoryPaths = cmd.Args().Slice()
+	scannerAction.DirectoryPaths, scannerAction.PURLs = splitPURLArgs(cmd.Args().Slice())
+	scannerAction.PURLs = append(scannerAction.PURLs, cmd.StringSlice("purl")...)
+	scannerAction.GitRef = cmd.String("git-ref")
 	scannerAction.ExperimentalScannerActions = experimentalScannerActions
 
+	if err := setBaseline(cmd, &scannerAction); err != nil {
+		return err
+	}
+
+	if cmd.Bool("watch") {
+		out := stdout
+		if outputPath == "" {
+			// keep the results parseable as newline delimited JSON
+			cmdlogger.SendEverythingToStderr()
+		} else {
+			f, err := os.Create(outputPath)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		return watch(ctx, scannerAction, cmd.Duration("watch-interval"), out, cmd.String("watch-endpoint"), client)
+	}
+
+	printFormats := formats
+	if format == "ndjson" {
+		out := stdout
+		if outputPath != "" {
+			f, err := os.Create(outputPath)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer f.Close()
+			out = f
+		}
+		// write the findings of each source as soon as it is matched, rather
+		// than once the scan has finished
+		scannerAction.OnSourceResults = func(source models.PackageSource) error {
+			if err := output.PrintNDJSONSource(source, out); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+
+			return nil
+		}
+		printFormats = formats[1:]
+	}
+
 	var vulnResult models.VulnerabilityResults
 	//nolint:contextcheck // passing the context in would be a breaking change
 	vulnResult, err = osvscanner.DoScan(scannerAction)
@@ -144,10 +440,16 @@ func action(_ context.Context, cmd *cli.Command, stdout, stderr io.Writer, clien
 		return err
 	}
 
-	if errPrint := helper.PrintResult(stdout, stderr, outputPath, format, &vulnResult, scannerAction.ShowAllVulns); errPrint != nil {
+	if errPrint := helper.PrintResults(stdout, stderr, outputPath, printFormats, cmd.String("template-file"), &vulnResult, scannerAction.ShowAllVulns); errPrint != nil {
 		return fmt.Errorf("failed to write output: %w", errPrint)
 	}
 
+	if graphPath := cmd.String("export-graph"); graphPath != "" {
+		if errGraph := helper.ExportGraph(graphPath, &vulnResult); errGraph != nil {
+			return fmt.Errorf("failed to export dependency graph: %w", errGraph)
+		}
+	}
+
 	// Auto-open outputted HTML file for users.
 	if outputPath != "" {
 		if serve {
@@ -160,3 +462,104 @@ func action(_ context.Context, cmd *cli.Command, stdout, stderr io.Writer, clien
 	// This may be nil.
 	return err
 }
+
+// splitPURLArgs separates the package URLs given as arguments from the
+// directories to scan, unless there is a directory named like one.
+func splitPURLArgs(args []string) ([]string, []string) {
+	var dirs, purls []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "pkg:") {
+			if _, err := os.Stat(arg); err != nil {
+				purls = append(purls, arg)
+				continue
+			}
+		}
+		dirs = append(dirs, arg)
+	}
+
+	return dirs, purls
+}
+
+// setStdinLockfile reads the lockfile to scan from stdin if --stdin is set.
+func setStdinLockfile(cmd *cli.Command, scannerAction *osvscanner.ScannerActions) error {
+	lockfileType := cmd.String("lockfile-type")
+	if !cmd.Bool("stdin") {
+		if lockfileType != "" {
+			return errors.New("--lockfile-type can only be used with --stdin")
+		}
+
+		return nil
+	}
+
+	if lockfileType == "" {
+		return errors.New("--stdin requires --lockfile-type, such as --lockfile-type=requirements.txt")
+	}
+	if cmd.Bool("watch") {
+		return errors.New("--stdin cannot be used with --watch, as stdin can only be read once")
+	}
+
+	content, err := io.ReadAll(cmd.Root().Reader)
+	if err != nil {
+		return fmt.Errorf("failed to read stdin: %w", err)
+	}
+	scannerAction.StdinLockfile = content
+	scannerAction.StdinLockfileType = lockfileType
+
+	return nil
+}
+
+// setBaseline sets the result that the scan is compared against, which is
+// either read from --baseline or found by scanning --baseline-git-ref.
+func setBaseline(cmd *cli.Command, scannerAction *osvscanner.ScannerActions) error {
+	path, ref := cmd.String("baseline"), cmd.String("baseline-git-ref")
+	if path != "" && ref != "" {
+		return errors.New("--baseline and --baseline-git-ref cannot be used together")
+	}
+
+	if path != "" {
+		baseline, err := ci.LoadVulnResults(path)
+		if err != nil {
+			return fmt.Errorf("failed to read baseline: %w", err)
+		}
+		scannerAction.Baseline = &baseline
+
+		return nil
+	}
+
+	if ref == "" {
+		return nil
+	}
+
+	cmdlogger.Infof("Scanning the baseline at %s", ref)
+	baselineAction := *scannerAction
+	baselineAction.GitRef = ref
+	// packages without vulnerabilities are needed to tell which are new
+	baselineAction.ShowAllPackages = true
+
+	//nolint:contextcheck // passing the context in would be a breaking change
+	baseline, err := osvscanner.DoScan(baselineAction)
+	switch {
+	case errors.Is(err, osvscanner.ErrNoPackagesFound):
+		cmdlogger.Warnf("No package sources found at %s, so every package is new", ref)
+	case err != nil && !errors.Is(err, osvscanner.ErrVulnerabilitiesFound):
+		return fmt.Errorf("failed to scan the baseline at %s: %w", ref, err)
+	}
+	scannerAction.Baseline = &baseline
+
+	return nil
+}
+
+// parseHeaders parses headers in the format "Name: value".
+func parseHeaders(headers []string) (map[string]string, error) {
+	parsed := make(map[string]string, len(headers))
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q - must be in the format \"Name: value\"", h)
+		}
+		parsed[name] = strings.TrimSpace(value)
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,232 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/google/osv-scanner/v2/pkg/osvscanner"
+)
+
+// watchEvent is the result of one of the scans of watch mode, which are
+// written as newline delimited JSON.
+type watchEvent struct {
+	Time time.Time `json:"time"`
+	// ChangedPaths are the files whose changes caused the scan, which are
+	// empty for the first one
+	ChangedPaths []string `json:"changed_paths,omitempty"`
+	// VulnerabilitiesFound is whether the scan would have failed outside of
+	// watch mode
+	VulnerabilitiesFound bool                         `json:"vulnerabilities_found"`
+	Error                string                       `json:"error,omitempty"`
+	Results              *models.VulnerabilityResults `json:"results,omitempty"`
+}
+
+// fileState is what is compared to tell whether a file has changed.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// watch scans, and then scans again whenever the files that were scanned
+// change, until interrupted. The deps.dev clients are shared by the scans, so
+// only the dependency graphs of changed requirements are fetched again.
+func watch(ctx context.Context, scannerAction osvscanner.ScannerActions, interval time.Duration, out io.Writer, endpoint string, client *http.Client) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	required := osvscanner.FileRequired(scannerAction)
+	state := watchedFiles(scannerAction, required)
+	var changed []string
+	for {
+		event := watchEvent{Time: time.Now(), ChangedPaths: changed}
+
+		//nolint:contextcheck // passing the context in would be a breaking change
+		results, err := osvscanner.DoScan(scannerAction)
+		switch {
+		case err == nil:
+			event.Results = &results
+		case errors.Is(err, osvscanner.ErrVulnerabilitiesFound):
+			event.Results = &results
+			event.VulnerabilitiesFound = true
+		default:
+			event.Error = err.Error()
+		}
+
+		if err := emitWatchEvent(ctx, event, out, endpoint, client); err != nil {
+			return err
+		}
+
+		cmdlogger.Infof("Watching for changes, press Ctrl+C to stop")
+		changed, state = waitForChanges(ctx, scannerAction, required, state, interval)
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// emitWatchEvent writes the event as a line of JSON, and posts it to the
+// endpoint if there is one. Failing to post is not fatal, so that the
+// endpoint can be restarted without restarting the watch.
+func emitWatchEvent(ctx context.Context, event watchEvent, out io.Writer, endpoint string, client *http.Client) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode scan results: %w", err)
+	}
+	line = append(line, '\n')
+
+	if _, err := out.Write(line); err != nil {
+		return fmt.Errorf("failed to write scan results: %w", err)
+	}
+
+	if endpoint == "" {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(line))
+	if err != nil {
+		return fmt.Errorf("invalid watch endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		cmdlogger.Errorf("Failed to send scan results to %s: %v", endpoint, err)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		cmdlogger.Errorf("Failed to send scan results to %s: %s", endpoint, resp.Status)
+	}
+
+	return nil
+}
+
+// waitForChanges polls the watched files until some of them change, and then
+// until they stop changing, so that a change written in several steps (such as
+// by a package manager) causes a single scan. It returns the paths that
+// changed, and the new state of the files.
+func waitForChanges(ctx context.Context, scannerAction osvscanner.ScannerActions, required fileFilter, state map[string]fileState, interval time.Duration) ([]string, map[string]fileState) {
+	changed := map[string]bool{}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, state
+		case <-ticker.C:
+		}
+
+		next := watchedFiles(scannerAction, required)
+		paths := changedPaths(state, next)
+		state = next
+		if len(paths) == 0 && len(changed) > 0 {
+			return slices.Sorted(maps.Keys(changed)), state
+		}
+		for _, p := range paths {
+			changed[p] = true
+		}
+	}
+}
+
+// fileFilter reports whether a scan would extract packages from a file, given
+// its path relative to the scanned directory.
+type fileFilter = func(path string, entry fs.DirEntry) bool
+
+// watchedFiles returns the state of the files that a scan could read, being
+// the given lockfiles and those in the scanned directories that required
+// reports the extractors of the scan would read, so that files such as
+// sources and build outputs are not polled.
+func watchedFiles(scannerAction osvscanner.ScannerActions, required fileFilter) map[string]fileState {
+	files := map[string]fileState{}
+	add := func(path string, info fs.FileInfo) {
+		files[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+	}
+
+	for _, arg := range scannerAction.LockfilePaths {
+		path := lockfilePath(arg)
+		if info, err := os.Stat(path); err == nil {
+			add(path, info)
+		}
+	}
+
+	for _, dir := range scannerAction.DirectoryPaths {
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// the file might have been removed while walking
+				return nil
+			}
+			if d.IsDir() {
+				if path != dir && (!scannerAction.Recursive || d.Name() == ".git") {
+					return fs.SkipDir
+				}
+
+				return nil
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil || !required(rel, d) {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				add(path, info)
+			}
+
+			return nil
+		})
+	}
+
+	return files
+}
+
+// changedPaths returns the paths of the files that were added, removed or
+// modified between two states, in order.
+func changedPaths(before, after map[string]fileState) []string {
+	var changed []string
+	for path, state := range after {
+		if prev, ok := before[path]; !ok || prev.size != state.size || !prev.modTime.Equal(state.modTime) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	slices.Sort(changed)
+
+	return changed
+}
+
+// lockfilePath returns the path of a --lockfile argument, which might say how
+// to parse it as in "requirements.txt:path/to/file".
+func lockfilePath(arg string) string {
+	if runtime.GOOS == "windows" && filepath.IsAbs(arg) {
+		return arg
+	}
+	if _, path, found := strings.Cut(arg, ":"); found {
+		return path
+	}
+
+	return arg
+}
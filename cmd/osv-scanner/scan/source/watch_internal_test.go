@@ -0,0 +1,164 @@
+package source
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/google/osv-scanner/v2/pkg/osvscanner"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_watchedFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "package-lock.json"), "{}")
+	writeFile(t, filepath.Join(dir, "sub", "go.mod"), "module example.com")
+	writeFile(t, filepath.Join(dir, ".git", "HEAD"), "ref: refs/heads/main")
+	// files that no extractor reads are not watched
+	writeFile(t, filepath.Join(dir, "README.md"), "# example")
+	writeFile(t, filepath.Join(dir, "sub", "main.go"), "package main")
+	other := t.TempDir()
+	writeFile(t, filepath.Join(other, "extra.txt"), "requests==2.0.0")
+
+	tests := []struct {
+		name      string
+		recursive bool
+		want      []string
+	}{
+		{
+			name:      "not recursive",
+			recursive: false,
+			want: []string{
+				filepath.Join(dir, "package-lock.json"),
+				filepath.Join(other, "extra.txt"),
+			},
+		},
+		{
+			name:      "recursive",
+			recursive: true,
+			want: []string{
+				filepath.Join(dir, "package-lock.json"),
+				filepath.Join(dir, "sub", "go.mod"),
+				filepath.Join(other, "extra.txt"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			actions := osvscanner.ScannerActions{
+				DirectoryPaths: []string{dir},
+				LockfilePaths:  []string{"requirements.txt:" + filepath.Join(other, "extra.txt")},
+				Recursive:      tt.recursive,
+			}
+			files := watchedFiles(actions, osvscanner.FileRequired(actions))
+
+			got := slices.Sorted(maps.Keys(files))
+			if diff := cmp.Diff(slices.Sorted(slices.Values(tt.want)), got); diff != "" {
+				t.Errorf("watchedFiles() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_changedPaths(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	before := map[string]fileState{
+		"same":     {size: 1, modTime: now},
+		"resized":  {size: 1, modTime: now},
+		"modified": {size: 1, modTime: now},
+		"removed":  {size: 1, modTime: now},
+	}
+	after := map[string]fileState{
+		"same":     {size: 1, modTime: now},
+		"resized":  {size: 2, modTime: now},
+		"modified": {size: 1, modTime: now.Add(time.Second)},
+		"added":    {size: 1, modTime: now},
+	}
+
+	want := []string{"added", "modified", "removed", "resized"}
+	if diff := cmp.Diff(want, changedPaths(before, after)); diff != "" {
+		t.Errorf("changedPaths() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_emitWatchEvent(t *testing.T) {
+	t.Parallel()
+
+	var posted []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted, _ = io.ReadAll(r.Body)
+		if got := r.Header.Get("Content-Type"); got != "application/x-ndjson" {
+			t.Errorf("Content-Type = %q, want application/x-ndjson", got)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	event := watchEvent{
+		Time:                 time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		ChangedPaths:         []string{"package-lock.json"},
+		VulnerabilitiesFound: true,
+		Results:              &models.VulnerabilityResults{},
+	}
+
+	var out bytes.Buffer
+	if err := emitWatchEvent(t.Context(), event, &out, server.URL, server.Client()); err != nil {
+		t.Fatalf("emitWatchEvent() error = %v", err)
+	}
+
+	if !bytes.Equal(out.Bytes(), posted) {
+		t.Errorf("emitWatchEvent() posted %q, but wrote %q", posted, out.Bytes())
+	}
+	if bytes.Count(out.Bytes(), []byte("\n")) != 1 || !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+		t.Errorf("emitWatchEvent() wrote %q, want a single line", out.Bytes())
+	}
+
+	var got watchEvent
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("emitWatchEvent() wrote invalid JSON: %v", err)
+	}
+	if diff := cmp.Diff(event, got); diff != "" {
+		t.Errorf("emitWatchEvent() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_lockfilePath(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"package-lock.json":                      "package-lock.json",
+		"requirements.txt:path/to/extra.txt":     "path/to/extra.txt",
+		":path/to/my:projects/package-lock.json": "path/to/my:projects/package-lock.json",
+	}
+	for arg, want := range tests {
+		if got := lockfilePath(arg); got != want {
+			t.Errorf("lockfilePath(%q) = %q, want %q", arg, got, want)
+		}
+	}
+}
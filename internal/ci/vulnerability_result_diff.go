@@ -1,17 +1,33 @@
 package ci
 
 import (
+	"slices"
+
 	"github.com/google/osv-scanner/v2/internal/grouper"
 	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
 // DiffVulnerabilityResults will return any new vulnerabilities that are in `newRes`
 // which is not present in `oldRes`, but not the reverse.
 func DiffVulnerabilityResults(oldRes, newRes models.VulnerabilityResults) models.VulnerabilityResults {
+	return diffVulnerabilityResults(oldRes, newRes, false)
+}
+
+// DiffVulnerabilityResultsByAliases is like DiffVulnerabilityResults, except that
+// packages are matched on their ecosystem, name, version and commit alone, and
+// vulnerabilities on any of the aliases of their group, so a vulnerability is
+// still recognized after its ID changes to one of its aliases. The groups of the
+// vulnerabilities that are new are kept as they are.
+func DiffVulnerabilityResultsByAliases(oldRes, newRes models.VulnerabilityResults) models.VulnerabilityResults {
+	return diffVulnerabilityResults(oldRes, newRes, true)
+}
+
+func diffVulnerabilityResults(oldRes, newRes models.VulnerabilityResults, byAliases bool) models.VulnerabilityResults {
 	result := models.VulnerabilityResults{}
 	// Initialize caches for quick lookup
-	sourceToIndex, packageToIndex, vulnToIndex := initializeCaches(oldRes)
+	sourceToIndex, packageToIndex, vulnToIndex := initializeCaches(oldRes, byAliases)
 
 	for _, ps := range newRes.Results {
 		sourceIdx, sourceExists := sourceToIndex[ps.Source]
@@ -26,12 +42,20 @@ func DiffVulnerabilityResults(oldRes, newRes models.VulnerabilityResults) models
 		})
 		resultPS := &result.Results[len(result.Results)-1]
 		for _, pv := range ps.Packages {
-			pkgIdx, packageExists := packageToIndex[sourceIdx][pv.Package]
+			pkgIdx, packageExists := packageToIndex[sourceIdx][packageKey(pv.Package, byAliases)]
 			if !packageExists {
 				// Newly introduced package, so all results for this package are going to be new, add everything for this package
 				resultPS.Packages = append(resultPS.Packages, pv)
 				continue
 			}
+			if byAliases {
+				newPV := withoutKnownGroups(pv, vulnToIndex[sourceIdx][pkgIdx])
+				if len(newPV.Vulnerabilities) > 0 {
+					resultPS.Packages = append(resultPS.Packages, newPV)
+				}
+
+				continue
+			}
 			// Otherwise the old package used to exist, so we need to find the difference in the vulnerabilities
 			// Only copy over packages as vulns and groups might change
 			resultPS.Packages = append(resultPS.Packages, models.PackageVulns{
@@ -54,6 +78,7 @@ func DiffVulnerabilityResults(oldRes, newRes models.VulnerabilityResults) models
 			groups := grouper.Group(grouper.ConvertVulnerabilityToIDAliases(resultPV.Vulnerabilities))
 			for i, group := range groups {
 				groups[i].MaxSeverity = output.MaxSeverity(group, *resultPV)
+				groups[i].FixedVersion = output.FixedVersion(group, *resultPV)
 			}
 			resultPV.Groups = groups
 		}
@@ -67,8 +92,53 @@ func DiffVulnerabilityResults(oldRes, newRes models.VulnerabilityResults) models
 	return result
 }
 
+// packageKey returns the package info that packages are matched on, which
+// leaves out the details that may change between scans when byAliases is set.
+func packageKey(pkg models.PackageInfo, byAliases bool) models.PackageInfo {
+	if !byAliases {
+		return pkg
+	}
+
+	return models.PackageInfo{
+		Name:      pkg.Name,
+		Version:   pkg.Version,
+		Ecosystem: pkg.Ecosystem,
+		Commit:    pkg.Commit,
+	}
+}
+
+// withoutKnownGroups removes the groups of vulnerabilities of which any alias
+// is known, along with their vulnerabilities, preserving order.
+func withoutKnownGroups(pv models.PackageVulns, known map[string]bool) models.PackageVulns {
+	removed := map[string]bool{}
+
+	var newGroups []models.GroupInfo
+	for _, group := range pv.Groups {
+		if !slices.ContainsFunc(group.Aliases, func(id string) bool { return known[id] }) {
+			newGroups = append(newGroups, group)
+			continue
+		}
+		for _, id := range group.Aliases {
+			removed[id] = true
+		}
+	}
+
+	var newVulns []*osvschema.Vulnerability
+	for _, v := range pv.Vulnerabilities {
+		if !removed[v.GetId()] && !known[v.GetId()] {
+			newVulns = append(newVulns, v)
+		}
+	}
+
+	pv.Groups = newGroups
+	pv.Vulnerabilities = newVulns
+
+	return pv
+}
+
 // initializeCaches sets up maps for quick lookup of sources, packages, and vulnerabilities by their indices.
-func initializeCaches(oldRes models.VulnerabilityResults) (map[models.SourceInfo]int, []map[models.PackageInfo]int, [][]map[string]bool) {
+// When byAliases is set, the aliases of the groups of each package are marked as present as well.
+func initializeCaches(oldRes models.VulnerabilityResults, byAliases bool) (map[models.SourceInfo]int, []map[models.PackageInfo]int, [][]map[string]bool) {
 	sourceToIndex := make(map[models.SourceInfo]int, len(oldRes.Results))
 	// The index in the array corresponds to a source index, a query would look like packageToIndex[sourceIndex][packageInfo]
 	packageToIndex := make([]map[models.PackageInfo]int, len(oldRes.Results))
@@ -86,13 +156,20 @@ func initializeCaches(oldRes models.VulnerabilityResults) (map[models.SourceInfo
 			if packageToIndex[sourceIndex] == nil {
 				packageToIndex[sourceIndex] = make(map[models.PackageInfo]int, len(vulnResult.Packages))
 			}
-			packageToIndex[sourceIndex][pkg.Package] = packageIndex
+			packageToIndex[sourceIndex][packageKey(pkg.Package, byAliases)] = packageIndex
 			if vulnToIndex[sourceIndex][packageIndex] == nil {
 				vulnToIndex[sourceIndex][packageIndex] = make(map[string]bool, len(pkg.Vulnerabilities))
 			}
 			for _, vuln := range pkg.Vulnerabilities {
 				vulnToIndex[sourceIndex][packageIndex][vuln.GetId()] = true // Mark the vulnerability as present
 			}
+			if byAliases {
+				for _, group := range pkg.Groups {
+					for _, id := range group.Aliases {
+						vulnToIndex[sourceIndex][packageIndex][id] = true
+					}
+				}
+			}
 		}
 	}
 
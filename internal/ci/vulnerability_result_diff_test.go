@@ -84,6 +84,50 @@ func TestDiffVulnerabilityResults(t *testing.T) {
 	}
 }
 
+func TestDiffVulnerabilityResultsByAliases(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		oldRes models.VulnerabilityResults
+		newRes models.VulnerabilityResults
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{
+			// diff should be empty since the old and new results are the same
+			name: "same_everything",
+			args: args{
+				oldRes: testutility.LoadJSONFixture[models.VulnerabilityResults](t, "testdata/vulns/test-vuln-results-a.json"),
+				newRes: testutility.LoadJSONFixture[models.VulnerabilityResults](t, "testdata/vulns/test-vuln-results-a.json"),
+			},
+		},
+		{
+			// diff should have just the new vuln, with its group as it was
+			name: "same_packages_with_new_vuln",
+			args: args{
+				oldRes: testutility.LoadJSONFixture[models.VulnerabilityResults](t, "testdata/vulns/test-vuln-results-a.json"),
+				newRes: testutility.LoadJSONFixture[models.VulnerabilityResults](t, "testdata/vulns/test-vuln-results-b.json"),
+			},
+		},
+		{
+			// diff should have all the new vulns
+			name: "new_vuln_and_packages",
+			args: args{
+				oldRes: testutility.LoadJSONFixture[models.VulnerabilityResults](t, "testdata/vulns/test-vuln-results-c.json"),
+				newRes: testutility.LoadJSONFixture[models.VulnerabilityResults](t, "testdata/vulns/test-vuln-results-b.json"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := ci.DiffVulnerabilityResultsByAliases(tt.args.oldRes, tt.args.newRes)
+			testutility.NewSnapshot().MatchJSON(t, got)
+		})
+	}
+}
+
 func TestDiffVulnerabilityByUniqueVulnCountResults(t *testing.T) {
 	t.Parallel()
 	type args struct {
@@ -0,0 +1,70 @@
+// Package dependentsmatcher implements a client for looking up how many
+// packages depend on each package version using the deps.dev API.
+package dependentsmatcher
+
+import (
+	"context"
+
+	depsdevalphapb "deps.dev/api/v3alpha"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DepsDevDependentsMatcher implements the DependentsMatcher interface with a
+// deps.dev v3alpha client, as dependent counts are not part of the stable
+// API. Each package version is only looked up once, even if it is found in
+// several sources.
+type DepsDevDependentsMatcher struct {
+	Client depsdevalphapb.InsightsClient
+
+	versions matcherutil.Cache[*models.Dependents]
+}
+
+func (matcher *DepsDevDependentsMatcher) MatchDependents(ctx context.Context, packages []imodels.PackageScanResult) error {
+	return matcherutil.ForEachPackage(ctx, packages, matcherutil.MaxConcurrentRequests, matcherutil.InDepsDev, func(ctx context.Context, psr *imodels.PackageScanResult) error {
+		pkg := psr.PackageInfo
+		key := matcherutil.VersionKey(depsdev.System[pkg.Ecosystem().Ecosystem], pkg.Name(), pkg.Version())
+		dependents, err := matcher.versionDependents(ctx, &depsdevalphapb.GetDependentsRequest{
+			VersionKey: &depsdevalphapb.VersionKey{
+				System:  depsdevalphapb.System(key.GetSystem()),
+				Name:    key.GetName(),
+				Version: key.GetVersion(),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		psr.Dependents = dependents
+
+		return nil
+	})
+}
+
+// versionDependents returns the dependent count of a package version, or nil
+// if deps.dev does not know of the version.
+func (matcher *DepsDevDependentsMatcher) versionDependents(ctx context.Context, query *depsdevalphapb.GetDependentsRequest) (*models.Dependents, error) {
+	key := query.GetVersionKey()
+	id := key.GetSystem().String() + ":" + key.GetName() + "@" + key.GetVersion()
+
+	return matcher.versions.Get(ctx, id, func(ctx context.Context) (*models.Dependents, error) {
+		resp, err := matcher.Client.GetDependents(ctx, query)
+		if err != nil {
+			// A version that is not found may be a private package
+			if status.Code(err) == codes.NotFound {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+
+		return &models.Dependents{
+			Count:    int(resp.GetDependentCount()),
+			Direct:   int(resp.GetDirectDependentCount()),
+			Indirect: int(resp.GetIndirectDependentCount()),
+		}, nil
+	})
+}
@@ -0,0 +1,53 @@
+package dependentsmatcher_test
+
+import (
+	"testing"
+
+	depsdevalphapb "deps.dev/api/v3alpha"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/dependentsmatcher"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil/matchertest"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func TestDepsDevDependentsMatcher_MatchDependents(t *testing.T) {
+	t.Parallel()
+
+	client := &matchertest.AlphaInsightsClient{
+		Dependents: map[string]*depsdevalphapb.Dependents{
+			"NPM:lodash@4.17.21":          {DependentCount: 180000, DirectDependentCount: 60000, IndirectDependentCount: 120000},
+			"GO:golang.org/x/text@v0.3.7": {DependentCount: 5000, DirectDependentCount: 100, IndirectDependentCount: 4900},
+		},
+	}
+
+	packages := []imodels.PackageScanResult{
+		matchertest.ScanResult("npm", "lodash", "4.17.21"),
+		matchertest.ScanResult("golang", "golang.org/x/text", "0.3.7"),
+		matchertest.ScanResult("npm", "private", "1.0.0"),
+		// The same version found in another source
+		matchertest.ScanResult("npm", "lodash", "4.17.21"),
+	}
+
+	matcher := &dependentsmatcher.DepsDevDependentsMatcher{Client: client}
+	if err := matcher.MatchDependents(t.Context(), packages); err != nil {
+		t.Fatalf("MatchDependents() error: %v", err)
+	}
+
+	lodash := &models.Dependents{Count: 180000, Direct: 60000, Indirect: 120000}
+	want := []*models.Dependents{
+		lodash,
+		{Count: 5000, Direct: 100, Indirect: 4900},
+		nil,
+		lodash,
+	}
+	for i, want := range want {
+		if diff := cmp.Diff(want, packages[i].Dependents); diff != "" {
+			t.Errorf("Dependents of %s mismatch (-want +got):\n%s", packages[i].PackageInfo.Name(), diff)
+		}
+	}
+
+	if got := client.DependentsLookups.Load(); got != 3 {
+		t.Errorf("looked up %d versions, want 3", got)
+	}
+}
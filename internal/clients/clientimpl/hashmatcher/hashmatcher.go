@@ -0,0 +1,111 @@
+// Package hashmatcher implements a client for checking the hashes that
+// requirements files pin packages to against the files published on PyPI.
+package hashmatcher
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
+)
+
+const (
+	maxConcurrentRequests = 100
+)
+
+// PyPIRegistry returns the digests of the files published for a PyPI package
+// version.
+type PyPIRegistry interface {
+	Digests(ctx context.Context, name, version string) ([]string, error)
+}
+
+// PyPIHashMatcher implements the HashMatcher interface by comparing the hashes
+// of hash-checking mode requirements with the digests that PyPI publishes for
+// the files of each version. Each package version is only looked up once,
+// even if it is pinned in several requirements files.
+type PyPIHashMatcher struct {
+	Registry PyPIRegistry
+
+	// versions are the digests of the files of each package version, or nil
+	// if PyPI does not know of the version
+	versions matcherutil.Cache[[]string]
+}
+
+// MatchHashes checks the hashes of the packages that are pinned to them. The
+// packages whose files cannot be looked up are left unchecked and returned
+// as matcherutil.PackageErrors, without stopping the others from being
+// checked.
+func (matcher *PyPIHashMatcher) MatchHashes(ctx context.Context, packages []imodels.PackageScanResult) error {
+	return matcherutil.ForEachPackage(ctx, packages, maxConcurrentRequests, isPinned, func(ctx context.Context, psr *imodels.PackageScanResult) error {
+		pkg := psr.PackageInfo
+		published, err := matcher.versionDigests(ctx, pkg.Name(), pkg.Version())
+		if err != nil {
+			return err
+		}
+		if published != nil {
+			psr.HashMismatches = mismatches(pkg.PinnedHashes(), published)
+		}
+
+		return nil
+	})
+}
+
+// isPinned reports whether a package is a PyPI package version that is
+// pinned to hashes.
+func isPinned(pkg imodels.PackageInfo) bool {
+	return len(pkg.PinnedHashes()) > 0 && pkg.Ecosystem().Ecosystem == osvconstants.EcosystemPyPI &&
+		pkg.Name() != "" && pkg.Version() != ""
+}
+
+// versionDigests returns the digests of the files of a package version, or
+// nil if PyPI does not know of the version.
+func (matcher *PyPIHashMatcher) versionDigests(ctx context.Context, name, version string) ([]string, error) {
+	id := depsdev.NormalizePyPIName(name) + "@" + version
+
+	return matcher.versions.Get(ctx, id, func(ctx context.Context) ([]string, error) {
+		digests, err := matcher.Registry.Digests(ctx, name, version)
+		if err != nil {
+			// A version that is not found may come from a private index
+			if errors.Is(err, depsdev.ErrNotOnPyPI) {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+		if digests == nil {
+			digests = []string{}
+		}
+
+		return digests, nil
+	})
+}
+
+// mismatches returns the pinned hashes that none of the published files have.
+// Hashes using an algorithm that PyPI publishes no digests for, such as
+// sha512, cannot be checked, so are never reported.
+func mismatches(pinned, published []string) []string {
+	algorithms := make(map[string]bool)
+	digests := make(map[string]bool)
+	for _, digest := range published {
+		algorithm, _, _ := strings.Cut(digest, ":")
+		algorithms[algorithm] = true
+		digests[digest] = true
+	}
+
+	var result []string
+	for _, hash := range pinned {
+		algorithm, digest, ok := strings.Cut(strings.ToLower(hash), ":")
+		if !ok || !algorithms[algorithm] {
+			continue
+		}
+		if !digests[algorithm+":"+digest] {
+			result = append(result, hash)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,138 @@
+package hashmatcher_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/python/requirements"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/hashmatcher"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+)
+
+const (
+	wheelDigest = "sha256:58cd2187c01e70e6e26505bca751777aa9f2ee0b7f4300988b709f44e013003f"
+	sdistDigest = "sha256:942c5a758f98d790eaed1a29cb6eefc7ffb0d1cf7af05c3d2791656dbd6ad1e1"
+	forgedHash  = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+)
+
+var errUnavailable = errors.New("service unavailable")
+
+// fakePyPIRegistry serves the digests of the versions it knows of, fails for
+// the packages that are unavailable, and reports everything else as not found.
+// Like PyPI, it looks up projects by their normalized names.
+type fakePyPIRegistry struct {
+	digests     map[string][]string
+	unavailable map[string]bool
+	lookups     atomic.Int32
+}
+
+func (r *fakePyPIRegistry) Digests(_ context.Context, name, version string) ([]string, error) {
+	r.lookups.Add(1)
+	name = depsdev.NormalizePyPIName(name)
+	if r.unavailable[name] {
+		return nil, errUnavailable
+	}
+	digests, ok := r.digests[name+"@"+version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", depsdev.ErrNotOnPyPI, name, version)
+	}
+
+	return digests, nil
+}
+
+func scanResult(name, version string, hashes ...string) imodels.PackageScanResult {
+	return imodels.PackageScanResult{
+		PackageInfo: imodels.FromInventory(&extractor.Package{
+			Name:     name,
+			Version:  version,
+			PURLType: purl.TypePyPi,
+			Metadata: &requirements.Metadata{HashCheckingModeValues: hashes},
+		}),
+	}
+}
+
+func TestPyPIHashMatcher_MatchHashes(t *testing.T) {
+	t.Parallel()
+
+	registry := &fakePyPIRegistry{
+		digests: map[string][]string{
+			"requests@2.31.0": {
+				wheelDigest,
+				"md5:c1ee1ec5e4f8a1bd0e6ab6a3e89e1c36",
+				sdistDigest,
+				"md5:3e0ec9a57e36c7c53dd3e8a8cc3bdcb8",
+			},
+		},
+	}
+	matcher := &hashmatcher.PyPIHashMatcher{Registry: registry}
+
+	packages := []imodels.PackageScanResult{
+		scanResult("requests", "2.31.0", wheelDigest, sdistDigest),
+		scanResult("requests", "2.31.0", wheelDigest, forgedHash),
+		// names are compared as PyPI does, so this is the same version
+		scanResult("Requests", "2.31.0", forgedHash),
+		// digests are compared regardless of case
+		scanResult("requests", "2.31.0", "SHA256:58CD2187C01E70E6E26505BCA751777AA9F2EE0B7F4300988B709F44E013003F"),
+		// PyPI publishes no sha512 digests to compare these with
+		scanResult("requests", "2.31.0", "sha512:"+forgedHash[len("sha256:"):]),
+		// versions that PyPI does not know of may come from another index
+		scanResult("internal-lib", "1.0.0", forgedHash),
+		// packages without hashes are not looked up
+		scanResult("idna", "3.6"),
+	}
+
+	if err := matcher.MatchHashes(t.Context(), packages); err != nil {
+		t.Fatalf("MatchHashes() error: %v", err)
+	}
+
+	got := make([][]string, len(packages))
+	for i, psr := range packages {
+		got[i] = psr.HashMismatches
+	}
+	want := [][]string{nil, {forgedHash}, {forgedHash}, nil, nil, nil, nil}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MatchHashes() mismatch (-want +got):\n%s", diff)
+	}
+
+	if lookups := registry.lookups.Load(); lookups != 2 {
+		t.Errorf("MatchHashes() looked up %d versions, want 2", lookups)
+	}
+}
+
+func TestPyPIHashMatcher_MatchHashes_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	registry := &fakePyPIRegistry{
+		digests:     map[string][]string{"requests@2.31.0": {wheelDigest, sdistDigest}},
+		unavailable: map[string]bool{"idna": true},
+	}
+	matcher := &hashmatcher.PyPIHashMatcher{Registry: registry}
+
+	packages := []imodels.PackageScanResult{
+		scanResult("idna", "3.6", forgedHash),
+		scanResult("requests", "2.31.0", forgedHash),
+	}
+
+	// the package that cannot be looked up is reported, while the others are
+	// still checked
+	err := matcher.MatchHashes(t.Context(), packages)
+
+	var errs matcherutil.PackageErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Name != "idna" || !errors.Is(err, errUnavailable) {
+		t.Errorf("MatchHashes() error = %v, want only idna@3.6 to have failed", err)
+	}
+
+	got := [][]string{packages[0].HashMismatches, packages[1].HashMismatches}
+	want := [][]string{nil, {forgedHash}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MatchHashes() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -3,6 +3,7 @@ package licensematcher
 
 import (
 	"context"
+	"slices"
 
 	depsdevpb "deps.dev/api/v3"
 	"github.com/google/osv-scalibr/clients/datasource"
@@ -20,6 +21,8 @@ const (
 
 // DepsDevLicenseMatcher implements the LicenseMatcher interface with a deps.dev client.
 // It sends out requests for every package version and does not perform caching.
+// Packages that deps.dev does not know the license of keep those found for
+// them while scanning, if any.
 type DepsDevLicenseMatcher struct {
 	Client *datasource.CachedInsightsClient
 }
@@ -42,6 +45,11 @@ func (matcher *DepsDevLicenseMatcher) MatchLicenses(ctx context.Context, package
 	}
 
 	for i, license := range licenses {
+		if slices.Equal(license, []models.License{"UNKNOWN"}) {
+			if extracted := packages[i].PackageInfo.ExtractedLicenses(); len(extracted) > 0 {
+				license = extracted
+			}
+		}
 		packages[i].Licenses = license
 	}
 
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"time"
 
 	"github.com/google/osv-scalibr/extractor"
 	"github.com/google/osv-scalibr/inventory/osvecosystem"
@@ -31,9 +32,12 @@ type LocalMatcher struct {
 	failedDBs map[osvconstants.Ecosystem]error
 	// userAgent sets the user agent requests for db zips are made with
 	userAgent string
+	// maxAge is how old databases can be before a warning is logged about
+	// them when they are not being downloaded, or 0 to never warn
+	maxAge time.Duration
 }
 
-func NewLocalMatcher(localDBPath string, userAgent string, downloadDB bool) (*LocalMatcher, error) {
+func NewLocalMatcher(localDBPath string, userAgent string, downloadDB bool, maxAge time.Duration) (*LocalMatcher, error) {
 	dbBasePath, err := setupLocalDBDirectory(localDBPath)
 	if err != nil {
 		return nil, fmt.Errorf("could not create %s: %w", dbBasePath, err)
@@ -44,6 +48,7 @@ func NewLocalMatcher(localDBPath string, userAgent string, downloadDB bool) (*Lo
 		dbs:        make(map[osvconstants.Ecosystem]*ZipDB),
 		downloadDB: downloadDB,
 		userAgent:  userAgent,
+		maxAge:     maxAge,
 		failedDBs:  make(map[osvconstants.Ecosystem]error),
 	}, nil
 }
@@ -141,6 +146,13 @@ func (matcher *LocalMatcher) loadDBFromCache(ctx context.Context, eco osvconstan
 
 	cmdlogger.Infof("Loaded %s local db from %s", db.Name, db.StoredAt)
 
+	if !matcher.downloadDB && matcher.maxAge > 0 && time.Since(db.UpdatedAt) > matcher.maxAge {
+		cmdlogger.Warnf(
+			"The %s local db was last updated %d days ago, run `osv-scanner db update` to get the latest vulnerabilities",
+			db.Name, int(time.Since(db.UpdatedAt).Hours()/24),
+		)
+	}
+
 	matcher.dbs[eco] = db
 
 	return db, nil
@@ -0,0 +1,213 @@
+package localmatcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
+)
+
+// metadataFileName is the name of the file stored next to each database
+// archive, describing where it came from and when.
+const metadataFileName = "metadata.json"
+
+// Metadata describes the local copy of the OSV database of an ecosystem.
+type Metadata struct {
+	Ecosystem string `json:"ecosystem"`
+	URL       string `json:"url"`
+	// SHA256 is the hex encoded checksum of the archive when it was stored
+	SHA256 string `json:"sha256"`
+	// UpdatedAt is when the archive was last downloaded, or last found to be
+	// the same as the remote archive
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// readMetadata reads the metadata stored alongside the archive at storedAt.
+func readMetadata(storedAt string) (Metadata, error) {
+	var metadata Metadata
+
+	content, err := os.ReadFile(path.Join(path.Dir(storedAt), metadataFileName))
+	if err != nil {
+		return metadata, err
+	}
+
+	if err := json.Unmarshal(content, &metadata); err != nil {
+		return metadata, fmt.Errorf("could not parse database metadata: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// writeMetadata records that the archive at storedAt is up to date with the
+// remote archive as of now.
+func (db *ZipDB) writeMetadata(f *os.File) error {
+	checksum, err := sha256Sum(f)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(Metadata{
+		Ecosystem: db.Name,
+		URL:       db.ArchiveURL,
+		SHA256:    checksum,
+		UpdatedAt: time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	//nolint:gosec // being world readable is fine
+	return os.WriteFile(path.Join(path.Dir(db.StoredAt), metadataFileName), content, 0644)
+}
+
+// updatedAt returns when the archive was last known to be up to date,
+// falling back to when it was last modified for archives stored without
+// metadata by older versions of the scanner.
+func (db *ZipDB) updatedAt(f *os.File) time.Time {
+	if metadata, err := readMetadata(db.StoredAt); err == nil {
+		return metadata.UpdatedAt
+	}
+
+	if s, err := f.Stat(); err == nil {
+		return s.ModTime()
+	}
+
+	return time.Time{}
+}
+
+func sha256Sum(f *os.File) (string, error) {
+	h := sha256.New()
+
+	if _, err := io.Copy(h, io.NewSectionReader(f, 0, 1<<63-1)); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// StoredDB is the status of a database stored on disk.
+type StoredDB struct {
+	Metadata
+
+	// Path is where the archive is stored
+	Path string
+	// Size is the size of the archive in bytes
+	Size int64
+	// Intact is whether the archive still matches its stored checksum, which
+	// is false for archives without one
+	Intact bool
+}
+
+// Download stores the latest OSV database of each of the given ecosystems
+// in the local database directory, skipping any that are already up to date.
+func Download(ctx context.Context, localDBPath string, userAgent string, ecosystems []osvconstants.Ecosystem) ([]StoredDB, error) {
+	dbBasePath, err := setupLocalDBDirectory(localDBPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not create %s: %w", dbBasePath, err)
+	}
+
+	stored := make([]StoredDB, 0, len(ecosystems))
+	for _, eco := range ecosystems {
+		db := &ZipDB{
+			Name:       string(eco),
+			ArchiveURL: fmt.Sprintf("%s/%s/all.zip", zippedDBRemoteHost, eco),
+			StoredAt:   path.Join(dbBasePath, string(eco), "all.zip"),
+			UserAgent:  userAgent,
+		}
+
+		f, err := db.fetchZip(ctx)
+		if err != nil {
+			return stored, fmt.Errorf("could not download %s database: %w", eco, err)
+		}
+		f.Close()
+
+		s, err := storedDB(db.StoredAt)
+		if err != nil {
+			return stored, err
+		}
+		stored = append(stored, s)
+	}
+
+	return stored, nil
+}
+
+// Stored returns the databases in the local database directory, ordered by
+// ecosystem.
+func Stored(localDBPath string) ([]StoredDB, error) {
+	dbBasePath, err := setupLocalDBDirectory(localDBPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not create %s: %w", dbBasePath, err)
+	}
+
+	entries, err := os.ReadDir(dbBasePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored []StoredDB
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		s, err := storedDB(path.Join(dbBasePath, entry.Name(), "all.zip"))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if s.Ecosystem == "" {
+			s.Ecosystem = entry.Name()
+		}
+		stored = append(stored, s)
+	}
+
+	slices.SortFunc(stored, func(a, b StoredDB) int {
+		return strings.Compare(a.Ecosystem, b.Ecosystem)
+	})
+
+	return stored, nil
+}
+
+func storedDB(storedAt string) (StoredDB, error) {
+	f, err := os.Open(storedAt)
+	if err != nil {
+		return StoredDB{}, err
+	}
+	defer f.Close()
+
+	s, err := f.Stat()
+	if err != nil {
+		return StoredDB{}, err
+	}
+
+	stored := StoredDB{Path: storedAt, Size: s.Size()}
+
+	metadata, err := readMetadata(storedAt)
+	if err != nil {
+		// archives stored without metadata can still be used
+		stored.UpdatedAt = s.ModTime()
+
+		return stored, nil
+	}
+	stored.Metadata = metadata
+
+	checksum, err := sha256Sum(f)
+	if err != nil {
+		return StoredDB{}, err
+	}
+	stored.Intact = checksum == metadata.SHA256
+
+	return stored, nil
+}
@@ -0,0 +1,100 @@
+package localmatcher_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/localmatcher"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+func TestStored(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+	dbBasePath := path.Join(testDir, "osv-scanner")
+
+	zipped := zipOSVs(t, map[string]*osvschema.Vulnerability{
+		"GHSA-1.json": {Id: "GHSA-1"},
+	})
+	ts := createZipServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = writeOSVsZip(t, w, map[string]*osvschema.Vulnerability{
+			"GHSA-1.json": {Id: "GHSA-1"},
+		})
+	})
+
+	before := time.Now()
+	db, err := localmatcher.NewZippedDB(t.Context(), dbBasePath, "npm", ts.URL, userAgent, false, nil)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+	if db.UpdatedAt.Before(before) {
+		t.Errorf("db.UpdatedAt = %v, want after %v", db.UpdatedAt, before)
+	}
+
+	// databases stored without metadata are still listed
+	cacheWrite(t, path.Join(dbBasePath, "PyPI", "all.zip"), zipped)
+
+	stored, err := localmatcher.Stored(testDir)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+	if len(stored) != 2 {
+		t.Fatalf("expected 2 stored databases, got %d", len(stored))
+	}
+
+	// ordered by ecosystem, which puts uppercase names first
+	pypi, npm := stored[0], stored[1]
+	checksum := sha256.Sum256(zipped)
+	if npm.Ecosystem != "npm" || npm.URL != ts.URL || npm.SHA256 != hex.EncodeToString(checksum[:]) || !npm.Intact {
+		t.Errorf("unexpected npm database status: %+v", npm)
+	}
+	if npm.Size != int64(len(zipped)) {
+		t.Errorf("npm database has size %d, want %d", npm.Size, len(zipped))
+	}
+	if pypi.Ecosystem != "PyPI" || pypi.SHA256 != "" || pypi.Intact || pypi.UpdatedAt.IsZero() {
+		t.Errorf("unexpected PyPI database status: %+v", pypi)
+	}
+
+	// modifying the archive after it was stored should be noticed
+	cacheWriteBad(t, npm.Path, "this is not a zip")
+
+	stored, err = localmatcher.Stored(testDir)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+	if stored[1].Intact {
+		t.Errorf("modified npm database is still marked as intact")
+	}
+}
+
+func TestNewZippedDB_Offline_UpdatedAtWithoutMetadata(t *testing.T) {
+	t.Parallel()
+
+	testDir := testutility.CreateTestDir(t)
+	storedAt := path.Join(testDir, "osv-scanner", "npm", "all.zip")
+	cacheWrite(t, storedAt, zipOSVs(t, map[string]*osvschema.Vulnerability{
+		"GHSA-1.json": {Id: "GHSA-1"},
+	}))
+
+	longAgo := time.Now().Add(-30 * 24 * time.Hour)
+	if err := os.Chtimes(storedAt, longAgo, longAgo); err != nil {
+		t.Fatalf("could not change modification time: %v", err)
+	}
+
+	db, err := localmatcher.NewZippedDB(t.Context(), path.Join(testDir, "osv-scanner"), "npm", "", userAgent, true, nil)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	// without metadata, the modification time is used
+	if age := time.Since(db.UpdatedAt); age < 29*24*time.Hour {
+		t.Errorf("db was last updated %v ago, want about 30 days", age)
+	}
+}
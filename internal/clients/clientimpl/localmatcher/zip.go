@@ -14,6 +14,7 @@ import (
 	"path"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/google/osv-scalibr/extractor"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
@@ -37,6 +38,8 @@ type ZipDB struct {
 	Vulnerabilities []*osvschema.Vulnerability
 	// User agent to query with
 	UserAgent string
+	// when the zip archive was last known to be up to date
+	UpdatedAt time.Time
 
 	// whether this database only has some of the advisories
 	// loaded from the underlying zip file
@@ -114,6 +117,8 @@ func (db *ZipDB) fetchZip(ctx context.Context) (*os.File, error) {
 		}
 
 		if remoteHash == localHash {
+			db.recordMetadata(f)
+
 			return f, nil
 		}
 	}
@@ -158,10 +163,19 @@ func (db *ZipDB) fetchZip(ctx context.Context) (*os.File, error) {
 	}
 
 	_, _ = f.Seek(0, io.SeekStart)
+	db.recordMetadata(f)
 
 	return f, nil
 }
 
+// recordMetadata writes the metadata of an up-to-date archive, which is not
+// needed to use it, so failing to do so is only worth a warning.
+func (db *ZipDB) recordMetadata(f *os.File) {
+	if err := db.writeMetadata(f); err != nil {
+		cmdlogger.Warnf("Could not record metadata of %s database: %v", db.Name, err)
+	}
+}
+
 func mightAffectPackagesBytes(content []byte, names []string) bool {
 	affected := gjson.GetBytes(content, "affected")
 
@@ -241,6 +255,8 @@ func (db *ZipDB) load(ctx context.Context, names []string) error {
 
 	defer f.Close()
 
+	db.UpdatedAt = db.updatedAt(f)
+
 	s, err := f.Stat()
 
 	if err != nil {
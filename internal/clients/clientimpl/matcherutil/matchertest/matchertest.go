@@ -0,0 +1,123 @@
+// Package matchertest provides fakes of the deps.dev API, and the packages to
+// match against them, for the tests of the matchers.
+package matchertest
+
+import (
+	"context"
+	"sync/atomic"
+
+	depsdevpb "deps.dev/api/v3"
+	depsdevalphapb "deps.dev/api/v3alpha"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ScanResult returns the scan result of a package version, with nothing yet
+// matched to it.
+func ScanResult(purlType, name, version string) imodels.PackageScanResult {
+	return imodels.PackageScanResult{
+		PackageInfo: imodels.FromInventory(&extractor.Package{
+			Name:     name,
+			Version:  version,
+			PURLType: purlType,
+		}),
+	}
+}
+
+// InsightsClient is a fake of the deps.dev v3 API, which serves the versions,
+// projects and packages it knows of, reports everything else as not found,
+// and counts how many of each were looked up.
+type InsightsClient struct {
+	depsdevpb.InsightsClient
+
+	// Versions is keyed by system, name and version, e.g. "NPM:lodash@4.17.21"
+	Versions map[string]*depsdevpb.Version
+	// Projects is keyed by ID, e.g. "github.com/lodash/lodash"
+	Projects map[string]*depsdevpb.Project
+	// Packages is keyed by system and name, e.g. "NPM:lodash"
+	Packages map[string]*depsdevpb.Package
+
+	VersionLookups atomic.Int32
+	ProjectLookups atomic.Int32
+	PackageLookups atomic.Int32
+}
+
+func (c *InsightsClient) GetVersion(_ context.Context, in *depsdevpb.GetVersionRequest, _ ...grpc.CallOption) (*depsdevpb.Version, error) {
+	c.VersionLookups.Add(1)
+	key := in.GetVersionKey()
+	version, ok := c.Versions[key.GetSystem().String()+":"+key.GetName()+"@"+key.GetVersion()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "version not found")
+	}
+
+	return version, nil
+}
+
+func (c *InsightsClient) GetProject(_ context.Context, in *depsdevpb.GetProjectRequest, _ ...grpc.CallOption) (*depsdevpb.Project, error) {
+	c.ProjectLookups.Add(1)
+	project, ok := c.Projects[in.GetProjectKey().GetId()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "project not found")
+	}
+
+	return project, nil
+}
+
+func (c *InsightsClient) GetPackage(_ context.Context, in *depsdevpb.GetPackageRequest, _ ...grpc.CallOption) (*depsdevpb.Package, error) {
+	c.PackageLookups.Add(1)
+	key := in.GetPackageKey()
+	pkg, ok := c.Packages[key.GetSystem().String()+":"+key.GetName()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "package not found")
+	}
+
+	return pkg, nil
+}
+
+// AlphaInsightsClient is a fake of the deps.dev v3alpha API, which serves the
+// dependents and similarly named packages of the packages it knows of,
+// reports everything else as not found, and counts how many were looked up.
+type AlphaInsightsClient struct {
+	depsdevalphapb.InsightsClient
+
+	// Dependents is keyed by system, name and version, e.g. "NPM:lodash@4.17.21"
+	Dependents map[string]*depsdevalphapb.Dependents
+	// SimilarlyNamed lists the names of the packages similarly named to
+	// each package, keyed by system and name, e.g. "NPM:lodash"
+	SimilarlyNamed map[string][]string
+
+	DependentsLookups     atomic.Int32
+	SimilarlyNamedLookups atomic.Int32
+}
+
+func (c *AlphaInsightsClient) GetDependents(_ context.Context, in *depsdevalphapb.GetDependentsRequest, _ ...grpc.CallOption) (*depsdevalphapb.Dependents, error) {
+	c.DependentsLookups.Add(1)
+	key := in.GetVersionKey()
+	dependents, ok := c.Dependents[key.GetSystem().String()+":"+key.GetName()+"@"+key.GetVersion()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "version not found")
+	}
+
+	return dependents, nil
+}
+
+func (c *AlphaInsightsClient) GetSimilarlyNamedPackages(_ context.Context, in *depsdevalphapb.GetSimilarlyNamedPackagesRequest, _ ...grpc.CallOption) (*depsdevalphapb.SimilarlyNamedPackages, error) {
+	c.SimilarlyNamedLookups.Add(1)
+	key := in.GetPackageKey()
+	names, ok := c.SimilarlyNamed[key.GetSystem().String()+":"+key.GetName()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "package not found")
+	}
+
+	resp := &depsdevalphapb.SimilarlyNamedPackages{}
+	for _, name := range names {
+		resp.Packages = append(resp.Packages, &depsdevalphapb.SimilarlyNamedPackages_Package{
+			PackageKey: &depsdevalphapb.PackageKey{System: key.GetSystem(), Name: name},
+		})
+	}
+
+	return resp, nil
+}
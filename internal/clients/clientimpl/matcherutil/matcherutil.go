@@ -0,0 +1,195 @@
+// Package matcherutil implements what the matchers that look up details of
+// each package version from deps.dev and the package registries have in
+// common.
+package matcherutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	depsdevpb "deps.dev/api/v3"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
+)
+
+// MaxConcurrentRequests is how many packages are looked up at once by default.
+const MaxConcurrentRequests = 1000
+
+// PackageError is a lookup that failed for a package version.
+type PackageError struct {
+	Name    string
+	Version string
+	Err     error
+}
+
+func (e PackageError) Error() string {
+	return fmt.Sprintf("%s@%s: %v", e.Name, e.Version, e.Err)
+}
+
+func (e PackageError) Unwrap() error {
+	return e.Err
+}
+
+// PackageErrors are the packages whose lookups failed, in the order of the
+// packages that were looked up.
+type PackageErrors []PackageError
+
+// maxListedErrors is how many of the failed lookups PackageErrors describes,
+// as the same failure is usually repeated for every package.
+const maxListedErrors = 3
+
+func (errs PackageErrors) Error() string {
+	listed := make([]string, 0, maxListedErrors)
+	for _, err := range errs[:min(len(errs), maxListedErrors)] {
+		listed = append(listed, err.Error())
+	}
+	msg := fmt.Sprintf("failed for %d of the packages: %s", len(errs), strings.Join(listed, "; "))
+	if len(errs) > maxListedErrors {
+		msg += fmt.Sprintf(" and %d more", len(errs)-maxListedErrors)
+	}
+
+	return msg
+}
+
+func (errs PackageErrors) Unwrap() []error {
+	unwrapped := make([]error, 0, len(errs))
+	for _, err := range errs {
+		unwrapped = append(unwrapped, err)
+	}
+
+	return unwrapped
+}
+
+// ForEachPackage calls lookup for each of packages that include reports
+// should be looked up, with at most limit calls at a time. A lookup that
+// fails does not stop the others, so the packages that could be looked up
+// keep their details, and the failures are returned as PackageErrors.
+func ForEachPackage(
+	ctx context.Context,
+	packages []imodels.PackageScanResult,
+	limit int,
+	include func(pkg imodels.PackageInfo) bool,
+	lookup func(ctx context.Context, psr *imodels.PackageScanResult) error,
+) error {
+	var mu sync.Mutex
+	failed := make(map[int]error)
+
+	var g errgroup.Group
+	g.SetLimit(limit)
+	for i := range packages {
+		if !include(packages[i].PackageInfo) {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			if err := lookup(ctx, &packages[i]); err != nil {
+				mu.Lock()
+				failed[i] = err
+				mu.Unlock()
+			}
+
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	var errs PackageErrors
+	for i := range packages {
+		if err, ok := failed[i]; ok {
+			pkg := packages[i].PackageInfo
+			errs = append(errs, PackageError{Name: pkg.Name(), Version: pkg.Version(), Err: err})
+		}
+	}
+
+	return errs
+}
+
+// Cache holds what has been looked up by key, so each key is only looked up
+// once however many packages share it. The zero value is ready to use.
+type Cache[V any] struct {
+	mu      sync.Mutex
+	results map[string]cacheResult[V]
+	group   singleflight.Group
+}
+
+type cacheResult[V any] struct {
+	value V
+	err   error
+}
+
+// Get returns the value of key, calling fetch to look it up if it has not been
+// already. Concurrent lookups of the same key share a single call of fetch,
+// which is not cancelled when the caller that started it is, so that what is
+// kept for the other callers never comes from a cancelled lookup.
+func (c *Cache[V]) Get(ctx context.Context, key string, fetch func(ctx context.Context) (V, error)) (V, error) {
+	c.mu.Lock()
+	result, ok := c.results[key]
+	c.mu.Unlock()
+	if ok {
+		return result.value, result.err
+	}
+
+	ch := c.group.DoChan(key, func() (any, error) {
+		v, err := fetch(context.WithoutCancel(ctx))
+
+		c.mu.Lock()
+		if c.results == nil {
+			c.results = make(map[string]cacheResult[V])
+		}
+		c.results[key] = cacheResult[V]{value: v, err: err}
+		c.mu.Unlock()
+
+		return v, err
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	case res := <-ch:
+		return res.Val.(V), res.Err
+	}
+}
+
+// InDepsDev reports whether a package is a version of an ecosystem that
+// deps.dev has.
+func InDepsDev(pkg imodels.PackageInfo) bool {
+	_, ok := depsdev.System[pkg.Ecosystem().Ecosystem]
+
+	return ok && pkg.Name() != "" && pkg.Version() != ""
+}
+
+// NativeVersion converts a version to how deps.dev writes it, which for Go
+// includes prepending v for package versions and go for stdlib.
+func NativeVersion(system depsdevpb.System, name string, version string) string {
+	if system != depsdevpb.System_GO {
+		return version
+	}
+	if name == "stdlib" {
+		return "go" + version
+	}
+
+	return "v" + version
+}
+
+// VersionKey returns the deps.dev key of a package version.
+func VersionKey(system depsdevpb.System, name string, version string) *depsdevpb.VersionKey {
+	return &depsdevpb.VersionKey{
+		System:  system,
+		Name:    name,
+		Version: NativeVersion(system, name, version),
+	}
+}
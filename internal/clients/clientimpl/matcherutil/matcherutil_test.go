@@ -0,0 +1,112 @@
+package matcherutil_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+)
+
+func npmPackage(name, version string) imodels.PackageScanResult {
+	return imodels.PackageScanResult{
+		PackageInfo: imodels.FromInventory(&extractor.Package{
+			Name:     name,
+			Version:  version,
+			PURLType: "npm",
+		}),
+	}
+}
+
+func TestForEachPackage(t *testing.T) {
+	t.Parallel()
+
+	errLookup := errors.New("lookup failed")
+	packages := []imodels.PackageScanResult{
+		npmPackage("foo", "1.0.0"),
+		npmPackage("broken", "1.0.0"),
+		npmPackage("bar", "2.0.0"),
+		npmPackage("unversioned", ""),
+	}
+
+	err := matcherutil.ForEachPackage(t.Context(), packages, 1, matcherutil.InDepsDev, func(_ context.Context, psr *imodels.PackageScanResult) error {
+		if psr.PackageInfo.Name() == "broken" {
+			return errLookup
+		}
+		psr.DeprecationReason = "looked up"
+
+		return nil
+	})
+
+	// the failure of one package does not stop the others from being looked up
+	var errs matcherutil.PackageErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("ForEachPackage() error = %v, want PackageErrors", err)
+	}
+	if len(errs) != 1 || errs[0].Name != "broken" || !errors.Is(errs[0], errLookup) {
+		t.Errorf("ForEachPackage() error = %v, want only broken@1.0.0 to have failed", err)
+	}
+
+	got := make([]string, 0, len(packages))
+	for _, psr := range packages {
+		got = append(got, psr.DeprecationReason)
+	}
+	want := []string{"looked up", "", "looked up", ""}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ForEachPackage() lookups mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestForEachPackage_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	packages := []imodels.PackageScanResult{npmPackage("foo", "1.0.0")}
+	err := matcherutil.ForEachPackage(ctx, packages, 1, matcherutil.InDepsDev, func(_ context.Context, _ *imodels.PackageScanResult) error {
+		t.Error("ForEachPackage() looked up a package after being cancelled")
+
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ForEachPackage() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestCache_Get(t *testing.T) {
+	t.Parallel()
+
+	var cache matcherutil.Cache[string]
+	var fetches atomic.Int32
+	fetch := func(context.Context) (string, error) {
+		fetches.Add(1)
+		return "value", nil
+	}
+
+	// a caller that has given up does not stop the lookup, nor have its
+	// error kept for the other callers
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if _, err := cache.Get(ctx, "key", fetch); !errors.Is(err, context.Canceled) && err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	for range 3 {
+		got, err := cache.Get(t.Context(), "key", fetch)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if got != "value" {
+			t.Errorf("Get() = %q, want %q", got, "value")
+		}
+	}
+
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("Get() fetched %d times, want 1", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/google/osv-scalibr/extractor"
@@ -35,13 +36,18 @@ const (
 var goVersionSuffixRegexp = cachedregexp.MustCompile(`^/?(v\d+)`)
 
 // OSVMatcher implements the VulnerabilityMatcher interface with an osv.dev client.
-// It sends out requests for every package version and does not perform caching.
+// It queries packages in batches, and then fetches each vulnerability found once,
+// however many packages it affects. It does not cache results between calls.
 type OSVMatcher struct {
 	Client osvdev.OSVClient
 	// InitialQueryTimeout allows you to set a timeout specifically for the initial paging query
 	// If timeout runs out, whatever pages that has been successfully queried within the timeout will
 	// still return fully hydrated.
 	InitialQueryTimeout time.Duration
+	// MaxConcurrentRequests limits how many vulnerabilities are fetched at once,
+	// defaulting to 1000 if 0. Batch queries are limited separately by the
+	// client config.
+	MaxConcurrentRequests int
 }
 
 func New(initialQueryTimeout time.Duration, userAgent string, httpClient *http.Client) *OSVMatcher {
@@ -98,34 +104,55 @@ func (matcher *OSVMatcher) MatchVulnerabilities(ctx context.Context, pkgs []*ext
 		}
 	}
 
-	vulnerabilities := make([][]*osvschema.Vulnerability, len(batchResp.GetResults()))
+	// Large projects often have the same vulnerability in many packages,
+	// so only fetch each of them once
+	var ids []string
+	seen := make(map[string]bool)
+	for _, resp := range batchResp.GetResults() {
+		for _, vuln := range resp.GetVulns() {
+			if !seen[vuln.GetId()] {
+				seen[vuln.GetId()] = true
+				ids = append(ids, vuln.GetId())
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	hydrated := make(map[string]*osvschema.Vulnerability, len(ids))
 	g, ctx := errgroup.WithContext(ctx)
-	g.SetLimit(maxConcurrentRequests)
+	g.SetLimit(matcher.concurrencyLimit())
+
+	for _, id := range ids {
+		g.Go(func() error {
+			// exit early if another hydration request has already failed
+			// results are thrown away later, so avoid needless work
+			if ctx.Err() != nil {
+				return nil //nolint:nilerr // this value doesn't matter to errgroup.Wait()
+			}
+			vuln, err := matcher.Client.GetVulnByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			hydrated[id] = vuln
+			mu.Unlock()
 
-	for batchIdx, resp := range batchResp.GetResults() {
-		vulnerabilities[batchIdx] = make([]*osvschema.Vulnerability, len(resp.GetVulns()))
-		for resultIdx, vuln := range resp.GetVulns() {
-			g.Go(func() error {
-				// exit early if another hydration request has already failed
-				// results are thrown away later, so avoid needless work
-				if ctx.Err() != nil {
-					return nil //nolint:nilerr // this value doesn't matter to errgroup.Wait()
-				}
-				vuln, err := matcher.Client.GetVulnByID(ctx, vuln.GetId())
-				if err != nil {
-					return err
-				}
-				vulnerabilities[batchIdx][resultIdx] = vuln
-
-				return nil
-			})
-		}
+			return nil
+		})
 	}
 
 	if err := g.Wait(); err != nil {
 		return nil, err
 	}
 
+	vulnerabilities := make([][]*osvschema.Vulnerability, len(batchResp.GetResults()))
+	for batchIdx, resp := range batchResp.GetResults() {
+		vulnerabilities[batchIdx] = make([]*osvschema.Vulnerability, len(resp.GetVulns()))
+		for resultIdx, vuln := range resp.GetVulns() {
+			vulnerabilities[batchIdx][resultIdx] = hydrated[vuln.GetId()]
+		}
+	}
+
 	if deadlineExceeded {
 		return vulnerabilities, context.DeadlineExceeded
 	}
@@ -133,6 +160,14 @@ func (matcher *OSVMatcher) MatchVulnerabilities(ctx context.Context, pkgs []*ext
 	return vulnerabilities, nil
 }
 
+func (matcher *OSVMatcher) concurrencyLimit() int {
+	if matcher.MaxConcurrentRequests > 0 {
+		return matcher.MaxConcurrentRequests
+	}
+
+	return maxConcurrentRequests
+}
+
 func pkgToQuery(pkg imodels.PackageInfo) *api.Query {
 	if pkg.Name() != "" && !pkg.Ecosystem().IsEmpty() && pkg.Version() != "" {
 		name := pkg.Name()
@@ -3,7 +3,11 @@ package osvmatcher
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -93,3 +97,61 @@ func TestOSVMatcher_MatchVulnerabilities(t *testing.T) {
 		})
 	}
 }
+
+func TestOSVMatcher_MatchVulnerabilities_FetchesEachVulnOnce(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	fetched := make(map[string]int)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == osvdev.QueryBatchEndpoint {
+			_, _ = w.Write([]byte(`{"results": [
+				{"vulns": [{"id": "GHSA-1"}, {"id": "GHSA-2"}]},
+				{"vulns": [{"id": "GHSA-1"}]},
+				{}
+			]}`))
+
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, osvdev.GetEndpoint+"/")
+		mu.Lock()
+		fetched[id]++
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"id": "` + id + `"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	matcher := New(0, "osv-scanner-test", srv.Client())
+	matcher.Client.BaseHostURL = srv.URL
+	matcher.MaxConcurrentRequests = 1
+
+	pkgs := []*extractor.Package{
+		{Name: "lodash", Version: "4.17.20", PURLType: purl.TypeNPM},
+		{Name: "lodash-es", Version: "4.17.20", PURLType: purl.TypeNPM},
+		{Name: "left-pad", Version: "1.3.0", PURLType: purl.TypeNPM},
+	}
+
+	got, err := matcher.MatchVulnerabilities(t.Context(), pkgs)
+	if err != nil {
+		t.Fatalf("OSVMatcher.MatchVulnerabilities() error: %v", err)
+	}
+
+	var gotIDs [][]string
+	for _, vulns := range got {
+		ids := []string{}
+		for _, vuln := range vulns {
+			ids = append(ids, vuln.GetId())
+		}
+		gotIDs = append(gotIDs, ids)
+	}
+	wantIDs := [][]string{{"GHSA-1", "GHSA-2"}, {"GHSA-1"}, {}}
+	if !reflect.DeepEqual(gotIDs, wantIDs) {
+		t.Errorf("OSVMatcher.MatchVulnerabilities() = %v, want %v", gotIDs, wantIDs)
+	}
+
+	if want := map[string]int{"GHSA-1": 1, "GHSA-2": 1}; !reflect.DeepEqual(fetched, want) {
+		t.Errorf("vulnerabilities fetched %v times, want %v", fetched, want)
+	}
+}
@@ -0,0 +1,171 @@
+// Package projectstatusmatcher implements a client for finding out whether
+// packages are still maintained upstream, using the deps.dev API and the
+// package registries.
+package projectstatusmatcher
+
+import (
+	"context"
+	"strings"
+
+	depsdevpb "deps.dev/api/v3"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
+	"github.com/tidwall/gjson"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NPMRegistry returns the registry metadata of an npm package version.
+type NPMRegistry interface {
+	FullJSON(ctx context.Context, pkg, version string) (gjson.Result, error)
+}
+
+// PyPIRegistry reports whether a PyPI package version has been yanked.
+type PyPIRegistry interface {
+	Yanked(ctx context.Context, name, version string) (bool, string, error)
+}
+
+// RegistryProjectStatusMatcher implements the ProjectStatusMatcher interface.
+//
+// It uses deps.dev to find packages whose source repository has been
+// archived, and asks the npm and PyPI registries why packages that were
+// already flagged as deprecated were deprecated or yanked. Either registry
+// can be nil, in which case deprecated packages from it have no reason.
+type RegistryProjectStatusMatcher struct {
+	Client depsdevpb.InsightsClient
+	NPM    NPMRegistry
+	PyPI   PyPIRegistry
+
+	// projects are whether each project has been archived, which is looked up
+	// once even if many packages come from the same repository
+	projects matcherutil.Cache[bool]
+}
+
+func (matcher *RegistryProjectStatusMatcher) MatchProjectStatuses(ctx context.Context, packages []imodels.PackageScanResult) error {
+	hasVersion := func(pkg imodels.PackageInfo) bool {
+		return pkg.Name() != "" && pkg.Version() != ""
+	}
+
+	return matcherutil.ForEachPackage(ctx, packages, matcherutil.MaxConcurrentRequests, hasVersion, func(ctx context.Context, psr *imodels.PackageScanResult) error {
+		pkg := psr.PackageInfo
+		var reasons []string
+
+		if pkg.Deprecated {
+			reason, err := matcher.registryReason(ctx, pkg)
+			if err != nil {
+				return err
+			}
+			if reason != "" {
+				reasons = append(reasons, reason)
+			}
+		}
+
+		project, err := matcher.archivedProject(ctx, pkg)
+		if err != nil {
+			return err
+		}
+		if project != "" {
+			reasons = append(reasons, "source repository "+project+" is archived")
+		}
+
+		psr.DeprecationReason = strings.Join(reasons, "; ")
+
+		return nil
+	})
+}
+
+// registryReason returns the reason a deprecated package was deprecated or
+// yanked, as given by its registry.
+func (matcher *RegistryProjectStatusMatcher) registryReason(ctx context.Context, pkg imodels.PackageInfo) (string, error) {
+	switch pkg.Ecosystem().Ecosystem {
+	case osvconstants.EcosystemNPM:
+		if matcher.NPM == nil {
+			return "", nil
+		}
+		metadata, err := matcher.NPM.FullJSON(ctx, pkg.Name(), pkg.Version())
+		if err != nil {
+			// The version may have been unpublished, which is already
+			// covered by it being flagged.
+			return "", nil //nolint:nilerr
+		}
+
+		return strings.TrimSpace(metadata.Get("deprecated").String()), nil
+	case osvconstants.EcosystemPyPI:
+		if matcher.PyPI == nil {
+			return "", nil
+		}
+		yanked, reason, err := matcher.PyPI.Yanked(ctx, pkg.Name(), pkg.Version())
+		if err != nil || !yanked {
+			return "", nil //nolint:nilerr
+		}
+		if reason = strings.TrimSpace(reason); reason == "" {
+			return "yanked", nil
+		}
+
+		return "yanked: " + reason, nil
+	default:
+		return "", nil
+	}
+}
+
+// archivedProject returns the source repository of a package version if it
+// has been archived, or "" otherwise.
+func (matcher *RegistryProjectStatusMatcher) archivedProject(ctx context.Context, pkg imodels.PackageInfo) (string, error) {
+	system, ok := depsdev.System[pkg.Ecosystem().Ecosystem]
+	if !ok {
+		return "", nil
+	}
+
+	resp, err := matcher.Client.GetVersion(ctx, &depsdevpb.GetVersionRequest{
+		VersionKey: matcherutil.VersionKey(system, pkg.Name(), pkg.Version()),
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			// This may be a private package.
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	for _, project := range resp.GetRelatedProjects() {
+		if project.GetRelationType() != depsdevpb.ProjectRelationType_SOURCE_REPO {
+			continue
+		}
+		projectID := project.GetProjectKey().GetId()
+		archived, err := matcher.isArchived(ctx, projectID)
+		if err != nil || !archived {
+			return "", err
+		}
+
+		return projectID, nil
+	}
+
+	return "", nil
+}
+
+// isArchived reports whether a project has been archived, which deps.dev
+// does not expose directly but is given by the Scorecard "Maintained" check.
+func (matcher *RegistryProjectStatusMatcher) isArchived(ctx context.Context, projectID string) (bool, error) {
+	return matcher.projects.Get(ctx, projectID, func(ctx context.Context) (bool, error) {
+		resp, err := matcher.Client.GetProject(ctx, &depsdevpb.GetProjectRequest{
+			ProjectKey: &depsdevpb.ProjectKey{Id: projectID},
+		})
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return false, nil
+			}
+
+			return false, err
+		}
+		for _, check := range resp.GetScorecard().GetChecks() {
+			if check.GetName() == "Maintained" && strings.Contains(check.GetReason(), "archived") {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+}
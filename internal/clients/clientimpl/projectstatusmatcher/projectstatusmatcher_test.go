@@ -0,0 +1,139 @@
+package projectstatusmatcher_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	depsdevpb "deps.dev/api/v3"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil/matchertest"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/projectstatusmatcher"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/tidwall/gjson"
+)
+
+// sourceRepoVersion returns a version whose source repository is project.
+func sourceRepoVersion(project string) *depsdevpb.Version {
+	return &depsdevpb.Version{
+		RelatedProjects: []*depsdevpb.Version_Project{
+			{ProjectKey: &depsdevpb.ProjectKey{Id: project}, RelationType: depsdevpb.ProjectRelationType_SOURCE_REPO},
+		},
+	}
+}
+
+// maintainedProject returns a project whose Maintained check reports whether
+// it is archived.
+func maintainedProject(archived bool) *depsdevpb.Project {
+	reason := "30 commit(s) and 2 issue activity found in the last 90 days -- score normalized to 10"
+	if archived {
+		reason = "project is archived"
+	}
+
+	return &depsdevpb.Project{
+		Scorecard: &depsdevpb.Project_Scorecard{
+			Checks: []*depsdevpb.Project_Scorecard_Check{
+				{Name: "Maintained", Reason: reason},
+			},
+		},
+	}
+}
+
+type fakeNPMRegistry map[string]string
+
+func (r fakeNPMRegistry) FullJSON(_ context.Context, pkg, version string) (gjson.Result, error) {
+	metadata, ok := r[pkg+"@"+version]
+	if !ok {
+		return gjson.Result{}, errors.New("404 Not Found")
+	}
+
+	return gjson.Parse(metadata), nil
+}
+
+type fakePyPIRegistry map[string]string
+
+func (r fakePyPIRegistry) Yanked(_ context.Context, name, version string) (bool, string, error) {
+	reason, ok := r[name+"@"+version]
+
+	return ok, reason, nil
+}
+
+func scanResult(purlType, name, version string, deprecated bool) imodels.PackageScanResult {
+	return imodels.PackageScanResult{
+		PackageInfo: imodels.FromInventory(&extractor.Package{
+			Name:       name,
+			Version:    version,
+			PURLType:   purlType,
+			Deprecated: deprecated,
+		}),
+	}
+}
+
+func TestRegistryProjectStatusMatcher_MatchProjectStatuses(t *testing.T) {
+	t.Parallel()
+
+	client := &matchertest.InsightsClient{
+		Versions: map[string]*depsdevpb.Version{
+			"NPM:left-pad@1.3.0": sourceRepoVersion("github.com/example/left-pad"),
+			"NPM:old-lib@1.0.0":  sourceRepoVersion("github.com/example/old-lib"),
+			"NPM:old-cli@2.0.0":  sourceRepoVersion("github.com/example/old-lib"),
+			"NPM:active@1.0.0":   sourceRepoVersion("github.com/example/active"),
+			"NPM:request@2.88.2": sourceRepoVersion("github.com/example/request"),
+		},
+		Projects: map[string]*depsdevpb.Project{
+			"github.com/example/left-pad": maintainedProject(false),
+			"github.com/example/old-lib":  maintainedProject(true),
+			"github.com/example/active":   maintainedProject(false),
+			"github.com/example/request":  maintainedProject(true),
+		},
+	}
+	matcher := &projectstatusmatcher.RegistryProjectStatusMatcher{
+		Client: client,
+		NPM: fakeNPMRegistry{
+			"left-pad@1.3.0": `{"name": "left-pad", "deprecated": "use String.prototype.padStart()"}`,
+			"request@2.88.2": `{"name": "request", "deprecated": "request has been deprecated, see https://github.com/request/request/issues/3142"}`,
+		},
+		PyPI: fakePyPIRegistry{
+			"broken@0.1.0": "contains a critical bug",
+			"typo@0.2.0":   "",
+		},
+	}
+
+	packages := []imodels.PackageScanResult{
+		scanResult("npm", "left-pad", "1.3.0", true),
+		scanResult("npm", "old-lib", "1.0.0", false),
+		scanResult("npm", "old-cli", "2.0.0", false),
+		scanResult("npm", "active", "1.0.0", false),
+		scanResult("npm", "request", "2.88.2", true),
+		scanResult("npm", "unpublished", "1.0.0", true),
+		scanResult("pypi", "broken", "0.1.0", true),
+		scanResult("pypi", "typo", "0.2.0", true),
+	}
+
+	if err := matcher.MatchProjectStatuses(context.Background(), packages); err != nil {
+		t.Fatalf("MatchProjectStatuses() error: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, psr := range packages {
+		got[psr.PackageInfo.Name()] = psr.DeprecationReason
+	}
+	want := map[string]string{
+		"left-pad":    "use String.prototype.padStart()",
+		"old-lib":     "source repository github.com/example/old-lib is archived",
+		"old-cli":     "source repository github.com/example/old-lib is archived",
+		"active":      "",
+		"request":     "request has been deprecated, see https://github.com/request/request/issues/3142; source repository github.com/example/request is archived",
+		"unpublished": "",
+		"broken":      "yanked: contains a critical bug",
+		"typo":        "yanked",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MatchProjectStatuses() deprecation reasons mismatch (-want +got):\n%s", diff)
+	}
+
+	if lookups := client.ProjectLookups.Load(); lookups != 4 {
+		t.Errorf("GetProject() was called %d times, want once per project (4)", lookups)
+	}
+}
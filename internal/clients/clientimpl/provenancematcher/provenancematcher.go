@@ -0,0 +1,111 @@
+// Package provenancematcher implements a client for finding out whether
+// package versions were published with verifiable build provenance, using
+// the deps.dev API.
+package provenancematcher
+
+import (
+	"context"
+
+	depsdevpb "deps.dev/api/v3"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// systems are the ecosystems whose registries publish Sigstore signed
+// provenance that deps.dev verifies: npm provenance statements and PyPI
+// attestations. Packages from other ecosystems are not checked, as none of
+// their versions could have any.
+var systems = map[osvconstants.Ecosystem]depsdevpb.System{
+	osvconstants.EcosystemNPM:  depsdevpb.System_NPM,
+	osvconstants.EcosystemPyPI: depsdevpb.System_PYPI,
+}
+
+// DepsDevProvenanceMatcher implements the ProvenanceMatcher interface with a
+// deps.dev client. Each package version is only looked up once, even if it is
+// found in several sources.
+type DepsDevProvenanceMatcher struct {
+	Client depsdevpb.InsightsClient
+
+	versions matcherutil.Cache[*models.Provenance]
+}
+
+func (matcher *DepsDevProvenanceMatcher) MatchProvenances(ctx context.Context, packages []imodels.PackageScanResult) error {
+	return matcherutil.ForEachPackage(ctx, packages, matcherutil.MaxConcurrentRequests, hasProvenance, func(ctx context.Context, psr *imodels.PackageScanResult) error {
+		pkg := psr.PackageInfo
+		provenance, err := matcher.versionProvenance(ctx, matcherutil.VersionKey(systems[pkg.Ecosystem().Ecosystem], pkg.Name(), pkg.Version()))
+		if err != nil {
+			return err
+		}
+		psr.Provenance = provenance
+
+		return nil
+	})
+}
+
+// hasProvenance reports whether a package version could have been published
+// with provenance.
+func hasProvenance(pkg imodels.PackageInfo) bool {
+	_, ok := systems[pkg.Ecosystem().Ecosystem]
+
+	return ok && pkg.Name() != "" && pkg.Version() != ""
+}
+
+// versionProvenance returns the provenance of a package version, or nil if
+// deps.dev does not know of the version.
+func (matcher *DepsDevProvenanceMatcher) versionProvenance(ctx context.Context, key *depsdevpb.VersionKey) (*models.Provenance, error) {
+	id := key.GetSystem().String() + ":" + key.GetName() + "@" + key.GetVersion()
+
+	return matcher.versions.Get(ctx, id, func(ctx context.Context) (*models.Provenance, error) {
+		resp, err := matcher.Client.GetVersion(ctx, &depsdevpb.GetVersionRequest{VersionKey: key})
+		if err != nil {
+			// A version that is not found may be a private package
+			if status.Code(err) == codes.NotFound {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+
+		return toProvenance(resp), nil
+	})
+}
+
+// toProvenance picks the attestation that best vouches for a version: the
+// first verified one, or else the first one found, so that the output shows
+// where an attestation that failed verification came from.
+func toProvenance(version *depsdevpb.Version) *models.Provenance {
+	var provenances []models.Provenance
+	for _, attestation := range version.GetAttestations() {
+		provenances = append(provenances, models.Provenance{
+			Verified:         attestation.GetVerified(),
+			Type:             attestation.GetType(),
+			SourceRepository: attestation.GetSourceRepository(),
+			Commit:           attestation.GetCommit(),
+			URL:              attestation.GetUrl(),
+		})
+	}
+	// Older npm provenance statements are only reported separately.
+	for _, slsa := range version.GetSlsaProvenances() {
+		provenances = append(provenances, models.Provenance{
+			Verified:         slsa.GetVerified(),
+			SourceRepository: slsa.GetSourceRepository(),
+			Commit:           slsa.GetCommit(),
+			URL:              slsa.GetUrl(),
+		})
+	}
+
+	for _, provenance := range provenances {
+		if provenance.Verified {
+			return &provenance
+		}
+	}
+	if len(provenances) > 0 {
+		return &provenances[0]
+	}
+
+	return &models.Provenance{}
+}
@@ -0,0 +1,88 @@
+package provenancematcher_test
+
+import (
+	"testing"
+
+	depsdevpb "deps.dev/api/v3"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil/matchertest"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/provenancematcher"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func TestDepsDevProvenanceMatcher_MatchProvenances(t *testing.T) {
+	t.Parallel()
+
+	client := &matchertest.InsightsClient{
+		Versions: map[string]*depsdevpb.Version{
+			"NPM:semver@7.6.3": {
+				Attestations: []*depsdevpb.Attestation{
+					{
+						Type:             "https://slsa.dev/provenance/v1",
+						Url:              "https://registry.npmjs.org/-/npm/v1/attestations/semver@7.6.3",
+						Verified:         true,
+						SourceRepository: "https://github.com/npm/node-semver",
+						Commit:           "0a12d6c7debb1dc82d8645c770e77c47bac5e1ea",
+					},
+				},
+			},
+			"NPM:sigstore@3.0.0": {
+				Attestations: []*depsdevpb.Attestation{
+					{Type: "https://slsa.dev/provenance/v1", SourceRepository: "https://github.com/sigstore/sigstore-js"},
+				},
+				SlsaProvenances: []*depsdevpb.SLSAProvenance{
+					{Verified: true, SourceRepository: "https://github.com/sigstore/sigstore-js", Commit: "1c3a5f1"},
+				},
+			},
+			"NPM:left-pad@1.3.0": {},
+			"PYPI:sampleproject@4.0.0": {
+				Attestations: []*depsdevpb.Attestation{
+					{Type: "https://docs.pypi.org/attestations/publish/v1", SourceRepository: "https://github.com/pypa/sampleproject"},
+				},
+			},
+		},
+	}
+
+	packages := []imodels.PackageScanResult{
+		matchertest.ScanResult("npm", "semver", "7.6.3"),
+		matchertest.ScanResult("npm", "sigstore", "3.0.0"),
+		matchertest.ScanResult("npm", "left-pad", "1.3.0"),
+		matchertest.ScanResult("pypi", "sampleproject", "4.0.0"),
+		matchertest.ScanResult("npm", "private", "1.0.0"),
+		// Provenance is not published for other ecosystems
+		matchertest.ScanResult("golang", "golang.org/x/text", "0.3.7"),
+		// The same version found in another source
+		matchertest.ScanResult("npm", "left-pad", "1.3.0"),
+	}
+
+	matcher := &provenancematcher.DepsDevProvenanceMatcher{Client: client}
+	if err := matcher.MatchProvenances(t.Context(), packages); err != nil {
+		t.Fatalf("MatchProvenances() error: %v", err)
+	}
+
+	want := []*models.Provenance{
+		{
+			Verified:         true,
+			Type:             "https://slsa.dev/provenance/v1",
+			SourceRepository: "https://github.com/npm/node-semver",
+			Commit:           "0a12d6c7debb1dc82d8645c770e77c47bac5e1ea",
+			URL:              "https://registry.npmjs.org/-/npm/v1/attestations/semver@7.6.3",
+		},
+		{Verified: true, SourceRepository: "https://github.com/sigstore/sigstore-js", Commit: "1c3a5f1"},
+		{},
+		{Type: "https://docs.pypi.org/attestations/publish/v1", SourceRepository: "https://github.com/pypa/sampleproject"},
+		nil,
+		nil,
+		{},
+	}
+	for i, want := range want {
+		if diff := cmp.Diff(want, packages[i].Provenance); diff != "" {
+			t.Errorf("Provenance of %s mismatch (-want +got):\n%s", packages[i].PackageInfo.Name(), diff)
+		}
+	}
+
+	if got := client.VersionLookups.Load(); got != 5 {
+		t.Errorf("looked up %d versions, want 5", got)
+	}
+}
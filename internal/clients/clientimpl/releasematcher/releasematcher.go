@@ -0,0 +1,135 @@
+// Package releasematcher implements a client for finding out when packages
+// were released and how far behind their latest release they are, using the
+// deps.dev API.
+package releasematcher
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	depsdevpb "deps.dev/api/v3"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/utility/semverlike"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DepsDevReleaseMatcher implements the ReleaseMatcher interface with a
+// deps.dev client. It looks up all the versions of each package once, and
+// compares the installed version to the one deps.dev marks as the default,
+// which is the latest stable release.
+type DepsDevReleaseMatcher struct {
+	Client depsdevpb.InsightsClient
+
+	// packages are the versions of each package, which are looked up once
+	// even if several versions of the package are found
+	packages matcherutil.Cache[[]*depsdevpb.Package_Version]
+}
+
+func (matcher *DepsDevReleaseMatcher) MatchReleases(ctx context.Context, packages []imodels.PackageScanResult) error {
+	return matcherutil.ForEachPackage(ctx, packages, matcherutil.MaxConcurrentRequests, matcherutil.InDepsDev, func(ctx context.Context, psr *imodels.PackageScanResult) error {
+		pkg := psr.PackageInfo
+		system := depsdev.System[pkg.Ecosystem().Ecosystem]
+		versions, err := matcher.packageVersions(ctx, system, pkg.Name())
+		if err != nil {
+			return err
+		}
+		psr.Release = toRelease(system, pkg.Name(), pkg.Version(), versions)
+
+		return nil
+	})
+}
+
+// packageVersions returns all the versions of a package, or nil if deps.dev
+// does not know of the package.
+func (matcher *DepsDevReleaseMatcher) packageVersions(ctx context.Context, system depsdevpb.System, name string) ([]*depsdevpb.Package_Version, error) {
+	key := system.String() + ":" + name
+
+	return matcher.packages.Get(ctx, key, func(ctx context.Context) ([]*depsdevpb.Package_Version, error) {
+		resp, err := matcher.Client.GetPackage(ctx, &depsdevpb.GetPackageRequest{
+			PackageKey: &depsdevpb.PackageKey{System: system, Name: name},
+		})
+		if err != nil {
+			// A package that is not found may be a private package
+			if status.Code(err) == codes.NotFound {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+
+		return resp.GetVersions(), nil
+	})
+}
+
+// toRelease describes how an installed version compares to the latest
+// release of its package, or returns nil if there is no latest release.
+func toRelease(system depsdevpb.System, name string, version string, versions []*depsdevpb.Package_Version) *models.Release {
+	var installed, latest *depsdevpb.Package_Version
+	for _, v := range versions {
+		if v.GetVersionKey().GetVersion() == matcherutil.NativeVersion(system, name, version) {
+			installed = v
+		}
+		if v.GetIsDefault() {
+			latest = v
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+
+	release := &models.Release{
+		LatestVersion: osvVersion(system, latest.GetVersionKey().GetVersion()),
+	}
+	if latest.GetPublishedAt() != nil {
+		release.LatestPublishedAt = formatDate(latest.GetPublishedAt().AsTime())
+	}
+	if installed == nil {
+		return release
+	}
+	if installed.GetPublishedAt() != nil {
+		release.PublishedAt = formatDate(installed.GetPublishedAt().AsTime())
+	}
+
+	installedVersion := semverlike.ParseSemverLikeVersion(version, 3)
+	latestVersion := semverlike.ParseSemverLikeVersion(release.LatestVersion, 3)
+	if installedVersion.Components.Cmp(latestVersion.Components) >= 0 {
+		// Already on (or past) the latest release
+		return release
+	}
+
+	release.MajorVersionsBehind = int(latestVersion.Components.Fetch(0).Int64() - installedVersion.Components.Fetch(0).Int64())
+	if installed.GetPublishedAt() != nil && latest.GetPublishedAt() != nil {
+		release.MonthsBehind = monthsBetween(installed.GetPublishedAt().AsTime(), latest.GetPublishedAt().AsTime())
+	}
+
+	return release
+}
+
+// monthsBetween returns the number of whole months from one time to a later
+// one, or 0 if the second is not later.
+func monthsBetween(from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+	if to.Day() < from.Day() {
+		months--
+	}
+
+	return max(months, 0)
+}
+
+func formatDate(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// osvVersion is the reverse of matcherutil.NativeVersion.
+func osvVersion(system depsdevpb.System, version string) string {
+	if system != depsdevpb.System_GO {
+		return version
+	}
+
+	return strings.TrimPrefix(strings.TrimPrefix(version, "go"), "v")
+}
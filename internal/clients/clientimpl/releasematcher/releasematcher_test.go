@@ -0,0 +1,100 @@
+package releasematcher_test
+
+import (
+	"testing"
+	"time"
+
+	depsdevpb "deps.dev/api/v3"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil/matchertest"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/releasematcher"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func version(v string, published time.Time, isDefault bool) *depsdevpb.Package_Version {
+	return &depsdevpb.Package_Version{
+		VersionKey:  &depsdevpb.VersionKey{Version: v},
+		PublishedAt: timestamppb.New(published),
+		IsDefault:   isDefault,
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+}
+
+func TestDepsDevReleaseMatcher_MatchReleases(t *testing.T) {
+	t.Parallel()
+
+	client := &matchertest.InsightsClient{
+		Packages: map[string]*depsdevpb.Package{
+			"NPM:express": {Versions: []*depsdevpb.Package_Version{
+				version("3.21.2", date(2015, time.July, 31), false),
+				version("4.17.1", date(2019, time.May, 26), false),
+				version("5.1.0", date(2025, time.March, 31), true),
+				version("6.0.0-beta.1", date(2026, time.January, 2), false),
+			}},
+			"GO:golang.org/x/text": {Versions: []*depsdevpb.Package_Version{
+				version("v0.3.7", date(2021, time.August, 10), false),
+				version("v0.21.0", date(2024, time.December, 4), true),
+			}},
+			"NPM:unreleased": {Versions: []*depsdevpb.Package_Version{
+				version("1.0.0", date(2024, time.January, 1), false),
+			}},
+		},
+	}
+
+	packages := []imodels.PackageScanResult{
+		matchertest.ScanResult("npm", "express", "3.21.2"),
+		matchertest.ScanResult("npm", "express", "5.1.0"),
+		matchertest.ScanResult("npm", "express", "6.0.0-beta.1"),
+		matchertest.ScanResult("golang", "golang.org/x/text", "0.3.7"),
+		matchertest.ScanResult("npm", "unreleased", "1.0.0"),
+		matchertest.ScanResult("npm", "private", "1.0.0"),
+	}
+
+	matcher := &releasematcher.DepsDevReleaseMatcher{Client: client}
+	if err := matcher.MatchReleases(t.Context(), packages); err != nil {
+		t.Fatalf("MatchReleases() error: %v", err)
+	}
+
+	want := []*models.Release{
+		{
+			PublishedAt:         "2015-07-31",
+			LatestVersion:       "5.1.0",
+			LatestPublishedAt:   "2025-03-31",
+			MajorVersionsBehind: 2,
+			MonthsBehind:        116,
+		},
+		{
+			PublishedAt:       "2025-03-31",
+			LatestVersion:     "5.1.0",
+			LatestPublishedAt: "2025-03-31",
+		},
+		{
+			PublishedAt:       "2026-01-02",
+			LatestVersion:     "5.1.0",
+			LatestPublishedAt: "2025-03-31",
+		},
+		{
+			PublishedAt:       "2021-08-10",
+			LatestVersion:     "0.21.0",
+			LatestPublishedAt: "2024-12-04",
+			MonthsBehind:      39,
+		},
+		nil,
+		nil,
+	}
+	for i, want := range want {
+		if diff := cmp.Diff(want, packages[i].Release); diff != "" {
+			t.Errorf("Release of %s@%s mismatch (-want +got):\n%s", packages[i].PackageInfo.Name(), packages[i].PackageInfo.Version(), diff)
+		}
+	}
+
+	// The versions of express are only looked up once
+	if got := client.PackageLookups.Load(); got != 4 {
+		t.Errorf("looked up %d packages, want 4", got)
+	}
+}
@@ -0,0 +1,125 @@
+// Package scorecardmatcher implements a client for looking up the OpenSSF
+// Scorecards of packages using the deps.dev API.
+package scorecardmatcher
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	depsdevpb "deps.dev/api/v3"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SelectedChecks are the Scorecard checks attached to packages alongside
+// their overall score, as those most relevant to the risk of depending on a
+// package.
+var SelectedChecks = []string{
+	"Maintained",
+	"Dangerous-Workflow",
+	"Code-Review",
+	"Branch-Protection",
+	"Vulnerabilities",
+}
+
+// DepsDevScorecardMatcher implements the ScorecardMatcher interface with a
+// deps.dev client. It looks up the source repository of every package
+// version, then the Scorecard of each repository once.
+type DepsDevScorecardMatcher struct {
+	Client depsdevpb.InsightsClient
+
+	// projects are the Scorecards of projects, which are looked up once even
+	// if many packages come from the same repository
+	projects matcherutil.Cache[*models.Scorecard]
+}
+
+func (matcher *DepsDevScorecardMatcher) MatchScorecards(ctx context.Context, packages []imodels.PackageScanResult) error {
+	return matcherutil.ForEachPackage(ctx, packages, matcherutil.MaxConcurrentRequests, matcherutil.InDepsDev, func(ctx context.Context, psr *imodels.PackageScanResult) error {
+		pkg := psr.PackageInfo
+		system := depsdev.System[pkg.Ecosystem().Ecosystem]
+		scorecard, err := matcher.packageScorecard(ctx, &depsdevpb.GetVersionRequest{
+			VersionKey: matcherutil.VersionKey(system, pkg.Name(), pkg.Version()),
+		})
+		if err != nil {
+			return err
+		}
+		psr.Scorecard = scorecard
+
+		return nil
+	})
+}
+
+// packageScorecard returns the Scorecard of the source repository of a
+// package version, or nil if deps.dev does not know of one.
+func (matcher *DepsDevScorecardMatcher) packageScorecard(ctx context.Context, query *depsdevpb.GetVersionRequest) (*models.Scorecard, error) {
+	resp, err := matcher.Client.GetVersion(ctx, query)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			// This may be a private package.
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	for _, project := range resp.GetRelatedProjects() {
+		if project.GetRelationType() == depsdevpb.ProjectRelationType_SOURCE_REPO {
+			return matcher.projectScorecard(ctx, project.GetProjectKey().GetId())
+		}
+	}
+
+	return nil, nil
+}
+
+func (matcher *DepsDevScorecardMatcher) projectScorecard(ctx context.Context, projectID string) (*models.Scorecard, error) {
+	return matcher.projects.Get(ctx, projectID, func(ctx context.Context) (*models.Scorecard, error) {
+		resp, err := matcher.Client.GetProject(ctx, &depsdevpb.GetProjectRequest{
+			ProjectKey: &depsdevpb.ProjectKey{Id: projectID},
+		})
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+
+		return toScorecard(projectID, resp.GetScorecard()), nil
+	})
+}
+
+func toScorecard(projectID string, sc *depsdevpb.Project_Scorecard) *models.Scorecard {
+	if sc == nil {
+		return nil
+	}
+
+	scorecard := &models.Scorecard{
+		Project: projectID,
+		// Scores are given as float32, so are rounded to the one decimal
+		// place they are shown with to not print as e.g. 7.300000190734863
+		OverallScore: math.Round(float64(sc.GetOverallScore())*10) / 10,
+	}
+	if sc.GetDate() != nil {
+		scorecard.Date = sc.GetDate().AsTime().Format("2006-01-02")
+	}
+	for _, check := range sc.GetChecks() {
+		if !slices.Contains(SelectedChecks, check.GetName()) {
+			continue
+		}
+		scorecard.Checks = append(scorecard.Checks, models.ScorecardCheck{
+			Name:   check.GetName(),
+			Score:  int(check.GetScore()),
+			Reason: check.GetReason(),
+		})
+	}
+	slices.SortFunc(scorecard.Checks, func(a, b models.ScorecardCheck) int {
+		return slices.Index(SelectedChecks, a.Name) - slices.Index(SelectedChecks, b.Name)
+	})
+
+	return scorecard
+}
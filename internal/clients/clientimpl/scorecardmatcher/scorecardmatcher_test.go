@@ -0,0 +1,80 @@
+package scorecardmatcher_test
+
+import (
+	"testing"
+	"time"
+
+	depsdevpb "deps.dev/api/v3"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil/matchertest"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/scorecardmatcher"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// sourceRepoVersion returns a version whose source repository is project,
+// listed after a project of another kind.
+func sourceRepoVersion(project string) *depsdevpb.Version {
+	return &depsdevpb.Version{
+		RelatedProjects: []*depsdevpb.Version_Project{
+			{ProjectKey: &depsdevpb.ProjectKey{Id: "github.com/other/issues"}, RelationType: depsdevpb.ProjectRelationType_ISSUE_TRACKER},
+			{ProjectKey: &depsdevpb.ProjectKey{Id: project}, RelationType: depsdevpb.ProjectRelationType_SOURCE_REPO},
+		},
+	}
+}
+
+func TestDepsDevScorecardMatcher_MatchScorecards(t *testing.T) {
+	t.Parallel()
+
+	client := &matchertest.InsightsClient{
+		Versions: map[string]*depsdevpb.Version{
+			"NPM:foo@1.0.0":      sourceRepoVersion("github.com/example/foo"),
+			"NPM:foo-cli@2.0.0":  sourceRepoVersion("github.com/example/foo"),
+			"NPM:unscored@1.0.0": sourceRepoVersion("github.com/example/unscored"),
+		},
+		Projects: map[string]*depsdevpb.Project{
+			"github.com/example/foo": {Scorecard: &depsdevpb.Project_Scorecard{
+				Date:         timestamppb.New(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)),
+				OverallScore: 7.3,
+				Checks: []*depsdevpb.Project_Scorecard_Check{
+					{Name: "Dangerous-Workflow", Score: 10, Reason: "no dangerous workflow patterns detected"},
+					{Name: "License", Score: 10, Reason: "license file detected"},
+					{Name: "Maintained", Score: 0, Reason: "0 commit(s) out of 30 and 0 issue activity out of 30 found in the last 90 days"},
+				},
+			}},
+		},
+	}
+
+	packages := []imodels.PackageScanResult{
+		matchertest.ScanResult("npm", "foo", "1.0.0"),
+		matchertest.ScanResult("npm", "foo-cli", "2.0.0"),
+		matchertest.ScanResult("npm", "unscored", "1.0.0"),
+		matchertest.ScanResult("npm", "private", "1.0.0"),
+	}
+
+	matcher := &scorecardmatcher.DepsDevScorecardMatcher{Client: client}
+	if err := matcher.MatchScorecards(t.Context(), packages); err != nil {
+		t.Fatalf("MatchScorecards() error: %v", err)
+	}
+
+	want := &models.Scorecard{
+		Project:      "github.com/example/foo",
+		Date:         "2026-10-12",
+		OverallScore: 7.3,
+		Checks: []models.ScorecardCheck{
+			{Name: "Maintained", Score: 0, Reason: "0 commit(s) out of 30 and 0 issue activity out of 30 found in the last 90 days"},
+			{Name: "Dangerous-Workflow", Score: 10, Reason: "no dangerous workflow patterns detected"},
+		},
+	}
+	for i, want := range []*models.Scorecard{want, want, nil, nil} {
+		if diff := cmp.Diff(want, packages[i].Scorecard); diff != "" {
+			t.Errorf("Scorecard of %s mismatch (-want +got):\n%s", packages[i].PackageInfo.Name(), diff)
+		}
+	}
+
+	// foo and foo-cli share a repository, which is only looked up once
+	if got := client.ProjectLookups.Load(); got != 2 {
+		t.Errorf("looked up %d projects, want 2", got)
+	}
+}
@@ -0,0 +1,143 @@
+// Package typosquatmatcher implements a client for finding packages whose
+// names are suspiciously close to those of more popular packages, using the
+// deps.dev API.
+package typosquatmatcher
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	depsdevalphapb "deps.dev/api/v3alpha"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DepsDevTyposquatMatcher implements the TyposquatMatcher interface with a
+// deps.dev v3alpha client.
+//
+// deps.dev works out which packages have names similar to each package,
+// taking into account how popular they are, so that a package is only
+// similar to ones more widely used than itself. Of those, only the ones
+// within a small edit distance are kept, as typosquats rely on a mistyped
+// name.
+type DepsDevTyposquatMatcher struct {
+	Client depsdevalphapb.InsightsClient
+
+	// packages are the packages each package could be a typosquat of, which
+	// are looked up once even if several versions of the package are found
+	packages matcherutil.Cache[[]string]
+}
+
+func (matcher *DepsDevTyposquatMatcher) MatchTyposquats(ctx context.Context, packages []imodels.PackageScanResult) error {
+	return matcherutil.ForEachPackage(ctx, packages, matcherutil.MaxConcurrentRequests, inDepsDev, func(ctx context.Context, psr *imodels.PackageScanResult) error {
+		pkg := psr.PackageInfo
+		similarTo, err := matcher.similarPackages(ctx, depsdevalphapb.System(depsdev.System[pkg.Ecosystem().Ecosystem]), pkg.Name())
+		if err != nil {
+			return err
+		}
+		psr.TyposquatOf = similarTo
+
+		return nil
+	})
+}
+
+// inDepsDev reports whether a package is of an ecosystem that deps.dev has,
+// whatever its version, as only its name is looked up.
+func inDepsDev(pkg imodels.PackageInfo) bool {
+	_, ok := depsdev.System[pkg.Ecosystem().Ecosystem]
+
+	return ok && pkg.Name() != ""
+}
+
+// similarPackages returns the names of the packages that the named package
+// is within a small edit distance of.
+func (matcher *DepsDevTyposquatMatcher) similarPackages(ctx context.Context, system depsdevalphapb.System, name string) ([]string, error) {
+	key := system.String() + ":" + name
+
+	return matcher.packages.Get(ctx, key, func(ctx context.Context) ([]string, error) {
+		resp, err := matcher.Client.GetSimilarlyNamedPackages(ctx, &depsdevalphapb.GetSimilarlyNamedPackagesRequest{
+			PackageKey: &depsdevalphapb.PackageKey{System: system, Name: name},
+		})
+		if err != nil {
+			// A package that is not found may be a private package
+			if status.Code(err) == codes.NotFound {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+
+		var similarTo []string
+		normalized := normalizeName(system, name)
+		for _, similar := range resp.GetPackages() {
+			other := similar.GetPackageKey().GetName()
+			distance := editDistance(normalized, normalizeName(system, other))
+			if distance > 0 && distance <= maxEditDistance(normalized) {
+				similarTo = append(similarTo, other)
+			}
+		}
+		slices.Sort(similarTo)
+
+		return slices.Compact(similarTo), nil
+	})
+}
+
+// maxEditDistance is how many edits away from a popular package a name can be
+// while still likely being a typo of it. Short names are only allowed a
+// single edit, as there are too many short names that are two edits apart.
+func maxEditDistance(name string) int {
+	if len(name) < 8 {
+		return 1
+	}
+
+	return 2
+}
+
+// normalizeName folds the differences between package names that their
+// registry treats as the same name.
+func normalizeName(system depsdevalphapb.System, name string) string {
+	name = strings.ToLower(name)
+	if system == depsdevalphapb.System_PYPI {
+		// https://peps.python.org/pep-0503/#normalized-names
+		name = cachedregexp.MustCompile(`[-_.]+`).ReplaceAllString(name, "-")
+	}
+
+	return name
+}
+
+// editDistance is the optimal string alignment distance between two strings:
+// the number of single character insertions, deletions, substitutions and
+// transpositions of adjacent characters needed to turn one into the other.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// Only the last three rows of the table are needed at a time.
+	prevPrev := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prevPrev[j-2]+1)
+			}
+		}
+		prevPrev, prev, curr = prev, curr, prevPrev
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,66 @@
+package typosquatmatcher_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/matcherutil/matchertest"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/typosquatmatcher"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+)
+
+func TestDepsDevTyposquatMatcher_MatchTyposquats(t *testing.T) {
+	t.Parallel()
+
+	client := &matchertest.AlphaInsightsClient{
+		SimilarlyNamed: map[string][]string{
+			// A transposition and a missing character are a single edit each
+			"NPM:requets": {"request", "requests", "react"},
+			"NPM:expres":  {"express", "xpress-core"},
+			// Short names only match a single edit away
+			"NPM:raect": {"react", "redux"},
+			// Two edits are allowed for longer names
+			"NPM:electorn-app": {"electron-app", "electron"},
+			// Names that PyPI treats as the same are not typosquats of each other
+			"PYPI:python-dateutil": {"Python.DateUtil", "python_dateutils"},
+			"NPM:lodash":           {},
+		},
+	}
+
+	packages := []imodels.PackageScanResult{
+		matchertest.ScanResult("npm", "requets", "2.88.2"),
+		matchertest.ScanResult("npm", "expres", "1.0.0"),
+		matchertest.ScanResult("npm", "raect", "0.1.0"),
+		matchertest.ScanResult("npm", "electorn-app", "1.0.0"),
+		matchertest.ScanResult("pypi", "python_dateutil", "2.9.0"),
+		matchertest.ScanResult("npm", "lodash", "4.17.21"),
+		matchertest.ScanResult("npm", "private", "1.0.0"),
+		// Another version of the same package
+		matchertest.ScanResult("npm", "requets", "2.88.0"),
+	}
+
+	matcher := &typosquatmatcher.DepsDevTyposquatMatcher{Client: client}
+	if err := matcher.MatchTyposquats(t.Context(), packages); err != nil {
+		t.Fatalf("MatchTyposquats() error: %v", err)
+	}
+
+	want := [][]string{
+		{"request", "requests"},
+		{"express"},
+		{"react"},
+		{"electron-app"},
+		{"python_dateutils"},
+		nil,
+		nil,
+		{"request", "requests"},
+	}
+	for i, want := range want {
+		if diff := cmp.Diff(want, packages[i].TyposquatOf); diff != "" {
+			t.Errorf("TyposquatOf of %s mismatch (-want +got):\n%s", packages[i].PackageInfo.Name(), diff)
+		}
+	}
+
+	if got := client.SimilarlyNamedLookups.Load(); got != 7 {
+		t.Errorf("looked up %d packages, want 7", got)
+	}
+}
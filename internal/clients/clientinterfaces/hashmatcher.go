@@ -0,0 +1,11 @@
+package clientinterfaces
+
+import (
+	"context"
+
+	"github.com/google/osv-scanner/v2/internal/imodels"
+)
+
+type HashMatcher interface {
+	MatchHashes(ctx context.Context, psr []imodels.PackageScanResult) error
+}
@@ -0,0 +1,11 @@
+package clientinterfaces
+
+import (
+	"context"
+
+	"github.com/google/osv-scanner/v2/internal/imodels"
+)
+
+type ReleaseMatcher interface {
+	MatchReleases(ctx context.Context, psr []imodels.PackageScanResult) error
+}
@@ -17,6 +17,7 @@ func TestParseVerbosityLevel_GivenValidLevels(t *testing.T) {
 		{input: "error", level: slog.LevelError},
 		{input: "warn", level: slog.LevelWarn},
 		{input: "info", level: slog.LevelInfo},
+		{input: "debug", level: slog.LevelDebug},
 	}
 
 	for _, tt := range tests {
@@ -4,6 +4,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -30,6 +31,19 @@ type Config struct {
 	IgnoredVulns      []*IgnoreEntry         `toml:"IgnoredVulns"`
 	PackageOverrides  []PackageOverrideEntry `toml:"PackageOverrides"`
 	GoVersionOverride string                 `toml:"GoVersionOverride"`
+	// Per-ecosystem deps.dev endpoints, keyed by ecosystem (e.g. "PyPI")
+	DepsDev map[string]DepsDevEndpoint `toml:"DepsDev"`
+	// OSV API endpoint to query for vulnerabilities instead of the default
+	OSV OSVEndpoint `toml:"OSV"`
+	// Licenses that the packages this config applies to may or may not use
+	LicensePolicy LicensePolicy `toml:"LicensePolicy"`
+	// Version ranges that guided remediation must keep packages within
+	UpgradeConstraints []UpgradeConstraint `toml:"UpgradeConstraints"`
+	// Enrichers to turn on or off, and their options
+	Enrichers Enrichers `toml:"Enrichers"`
+	// Globs of the paths, relative to the directory being scanned, whose
+	// files are not extracted (e.g. "**/testdata/**")
+	ExcludePaths []string `toml:"ExcludePaths"`
 	// The path to config file that this config was loaded from,
 	// set by the scanner after having successfully parsed the file
 	LoadPath string `toml:"-"`
@@ -55,6 +69,12 @@ type IgnoreEntry struct {
 	Used bool `toml:"-"`
 }
 
+// IsExpired reports whether the entry is past its ignoreUntil date, so that
+// the vulnerability it ignored is reported again.
+func (ie *IgnoreEntry) IsExpired() bool {
+	return !shouldIgnoreTimestamp(ie.IgnoreUntil)
+}
+
 func (ie *IgnoreEntry) MarkAsUsed() {
 	ie.Used = true
 }
@@ -92,6 +112,181 @@ func (e PackageOverrideEntry) matches(pkg imodels.PackageInfo) bool {
 	return true
 }
 
+// DepsDevEcosystems are the ecosystems that a deps.dev endpoint can be
+// configured for, being those whose dependencies are resolved through it.
+var DepsDevEcosystems = []string{"PyPI"}
+
+// DepsDevEndpoint configures the deps.dev API endpoint used to resolve
+// dependencies of a single ecosystem, such as an internal caching mirror.
+type DepsDevEndpoint struct {
+	BaseURL string `toml:"baseURL"`
+	// Headers sent with every request to the endpoint, e.g. for authentication.
+	// Values may reference environment variables as $VAR or ${VAR} to avoid
+	// storing credentials in the config file.
+	Headers map[string]string `toml:"headers"`
+}
+
+// ExpandedHeaders returns the endpoint headers with environment variables expanded.
+func (e DepsDevEndpoint) ExpandedHeaders() map[string]string {
+	return expandHeaders(e.Headers)
+}
+
+// OSVEndpoint configures the OSV API endpoint that vulnerabilities are
+// queried from, such as a self-hosted or proxied instance of the API.
+type OSVEndpoint struct {
+	BaseURL string `toml:"baseURL"`
+	// Headers sent with every request to the endpoint, e.g. an API key.
+	// Values may reference environment variables as $VAR or ${VAR}.
+	Headers map[string]string `toml:"headers"`
+}
+
+// ExpandedHeaders returns the endpoint headers with environment variables expanded.
+func (e OSVEndpoint) ExpandedHeaders() map[string]string {
+	return expandHeaders(e.Headers)
+}
+
+func expandHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	expanded := make(map[string]string, len(headers))
+	for k, v := range headers {
+		expanded[k] = os.ExpandEnv(v)
+	}
+
+	return expanded
+}
+
+// LicensePolicy is the SPDX licenses that packages are allowed or denied
+// from using. A package violates the policy if its license expression cannot
+// be satisfied by the allowlist (when one is given), or only by licenses on
+// the denylist.
+type LicensePolicy struct {
+	Allowlist []string `toml:"allowlist"`
+	Denylist  []string `toml:"denylist"`
+}
+
+// IsEmpty reports whether the policy neither allows nor denies any license.
+func (p LicensePolicy) IsEmpty() bool {
+	return len(p.Allowlist) == 0 && len(p.Denylist) == 0
+}
+
+// Enrichers turns the individual enrichers that add to the results of a scan
+// on or off, and sets their options. Options that are left unset keep the
+// values of the command line flags.
+type Enrichers struct {
+	PyPIDepsDev         PyPIDepsDevEnricher  `toml:"pypiDepsDev"`
+	MavenDepsDev        MavenDepsDevEnricher `toml:"mavenDepsDev"`
+	SBOMDepsDev         Enricher             `toml:"sbomDepsDev"`
+	Scorecard           ScorecardEnricher    `toml:"scorecard"`
+	EPSS                EPSSEnricher         `toml:"epss"`
+	KEV                 KEVEnricher          `toml:"kev"`
+	Deprecation         Enricher             `toml:"deprecation"`
+	SecondaryAdvisories Enricher             `toml:"secondaryAdvisories"`
+	Typosquats          Enricher             `toml:"typosquats"`
+	Provenance          ProvenanceEnricher   `toml:"provenance"`
+	Dependents          DependentsEnricher   `toml:"dependents"`
+	ReleaseInfo         ReleaseInfoEnricher  `toml:"releaseInfo"`
+}
+
+// Enricher turns an enricher on or off.
+type Enricher struct {
+	// Whether the enricher runs, or nil to leave it to the command line flags
+	Enabled *bool `toml:"enabled"`
+}
+
+// IsEnabled reports whether the enricher is turned on.
+func (e Enricher) IsEnabled() bool {
+	return e.Enabled != nil && *e.Enabled
+}
+
+// IsDisabled reports whether the enricher is turned off.
+func (e Enricher) IsDisabled() bool {
+	return e.Enabled != nil && !*e.Enabled
+}
+
+// PyPIDepsDevEnricher resolves the transitive dependencies of Python
+// requirements files through deps.dev.
+type PyPIDepsDevEnricher struct {
+	Enricher
+	MaxDepth    int `toml:"maxDepth"`
+	Parallelism int `toml:"parallelism"`
+	// One of "report-all", "highest-wins" or "nearest-wins"
+	ConflictStrategy string `toml:"conflictStrategy"`
+	PythonVersion    string `toml:"pythonVersion"`
+	PythonPlatform   string `toml:"pythonPlatform"`
+}
+
+// MavenDepsDevEnricher resolves the transitive dependencies of pom.xml files.
+type MavenDepsDevEnricher struct {
+	Enricher
+	Registry       string   `toml:"registry"`
+	ExcludedScopes []string `toml:"excludedScopes"`
+}
+
+type ScorecardEnricher struct {
+	Enricher
+	FailBelow float64 `toml:"failBelow"`
+}
+
+type EPSSEnricher struct {
+	Enricher
+	MinScore float64 `toml:"minScore"`
+	Sort     bool    `toml:"sort"`
+	BaseURL  string  `toml:"baseURL"`
+}
+
+type KEVEnricher struct {
+	Enricher
+	FailOn     bool   `toml:"failOn"`
+	CatalogURL string `toml:"catalogURL"`
+}
+
+type ProvenanceEnricher struct {
+	Enricher
+	FailOnMissing bool `toml:"failOnMissing"`
+}
+
+type DependentsEnricher struct {
+	Enricher
+	Sort bool `toml:"sort"`
+}
+
+type ReleaseInfoEnricher struct {
+	Enricher
+	// Report outdated packages, using the thresholds below when they are set
+	Outdated              bool `toml:"outdated"`
+	OutdatedMajorVersions int  `toml:"outdatedMajorVersions"`
+	OutdatedMonths        int  `toml:"outdatedMonths"`
+}
+
+// UpgradeConstraint restricts the versions guided remediation may upgrade a package to.
+type UpgradeConstraint struct {
+	Name string `toml:"name"`
+	// If the ecosystem is empty, the constraint applies to packages with the name in every ecosystem.
+	Ecosystem string `toml:"ecosystem"`
+	// The allowed versions, in the version range syntax of the ecosystem e.g. "<5" for PyPI or "[1.0,2.0)" for Maven.
+	Versions string `toml:"versions"`
+	Reason   string `toml:"reason"`
+}
+
+// AllowedVersions returns the allowed version ranges of the packages in the ecosystem, keyed by package name.
+// If a package has several constraints, the first one applies.
+func (c *Config) AllowedVersions(ecosystem string) map[string]string {
+	allowed := make(map[string]string)
+	for _, uc := range c.UpgradeConstraints {
+		if uc.Ecosystem != "" && uc.Ecosystem != ecosystem {
+			continue
+		}
+		if _, ok := allowed[uc.Name]; !ok {
+			allowed[uc.Name] = uc.Versions
+		}
+	}
+
+	return allowed
+}
+
 type Vulnerability struct {
 	Ignore bool `toml:"ignore"`
 }
@@ -108,7 +303,7 @@ func (c *Config) ShouldIgnore(vulnID string) (bool, *IgnoreEntry) {
 	}
 	ignoredLine := c.IgnoredVulns[index]
 
-	return shouldIgnoreTimestamp(ignoredLine.IgnoreUntil), ignoredLine
+	return !ignoredLine.IsExpired(), ignoredLine
 }
 
 func (c *Config) filterPackageVersionEntries(pkg imodels.PackageInfo, condition func(PackageOverrideEntry) bool) (bool, PackageOverrideEntry) {
@@ -203,6 +398,76 @@ func (c *Manager) Get(targetPath string) Config {
 	return config
 }
 
+// ExcludePaths returns the globs of the paths to leave out when scanning dir,
+// from the override config or else the config file in dir.
+//
+// The config file is only read for its ExcludePaths rather than loaded, as it
+// is loaded (and any problems with it reported) once the packages in dir are
+// filtered with it.
+func (c *Manager) ExcludePaths(dir string) []string {
+	if c.OverrideConfig != nil {
+		return c.OverrideConfig.ExcludePaths
+	}
+
+	configPath := filepath.Join(dir, OSVScannerConfigName)
+	if config, ok := c.ConfigMap[configPath]; ok {
+		return config.ExcludePaths
+	}
+
+	var config Config
+	if _, err := toml.DecodeFile(configPath, &config); err != nil {
+		return nil
+	}
+
+	return config.ExcludePaths
+}
+
+// HasLicensePolicy reports whether any of the loaded configs has a license
+// policy, in which case the licenses of packages need to be looked up.
+func (c *Manager) HasLicensePolicy() bool {
+	if c.OverrideConfig != nil {
+		return !c.OverrideConfig.LicensePolicy.IsEmpty()
+	}
+
+	for _, config := range c.ConfigMap {
+		if !config.LicensePolicy.IsEmpty() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetExpiredIgnoreEntries returns the ignore entries that have expired,
+// keyed by the path of the config file they are in.
+func (c *Manager) GetExpiredIgnoreEntries() map[string][]*IgnoreEntry {
+	return c.ignoreEntriesWhere(func(e *IgnoreEntry) bool { return e.IsExpired() })
+}
+
+// GetReasonlessIgnoreEntries returns the ignore entries that do not give a
+// reason, keyed by the path of the config file they are in.
+func (c *Manager) GetReasonlessIgnoreEntries() map[string][]*IgnoreEntry {
+	return c.ignoreEntriesWhere(func(e *IgnoreEntry) bool { return strings.TrimSpace(e.Reason) == "" })
+}
+
+func (c *Manager) ignoreEntriesWhere(matches func(*IgnoreEntry) bool) map[string][]*IgnoreEntry {
+	configs := slices.Collect(maps.Values(c.ConfigMap))
+	if c.OverrideConfig != nil {
+		configs = append(configs, *c.OverrideConfig)
+	}
+
+	m := make(map[string][]*IgnoreEntry)
+	for _, config := range configs {
+		for _, entry := range config.IgnoredVulns {
+			if matches(entry) {
+				m[config.LoadPath] = append(m[config.LoadPath], entry)
+			}
+		}
+	}
+
+	return m
+}
+
 func (c *Manager) GetUnusedIgnoreEntries() map[string][]*IgnoreEntry {
 	m := make(map[string][]*IgnoreEntry)
 
@@ -261,6 +526,10 @@ func tryLoadConfig(configPath string) (Config, error) {
 			return Config{}, fmt.Errorf("unknown keys in config file: %s", strings.Join(keys, ", "))
 		}
 
+		if err := config.validateDepsDev(); err != nil {
+			return Config{}, err
+		}
+
 		config.LoadPath = configPath
 		config.warnAboutDuplicates()
 	}
@@ -268,6 +537,27 @@ func tryLoadConfig(configPath string) (Config, error) {
 	return config, err
 }
 
+// validateDepsDev checks that deps.dev endpoints are only configured for
+// ecosystems that are resolved through deps.dev.
+func (c *Config) validateDepsDev() error {
+	var unsupported []string
+	for _, ecosystem := range slices.Sorted(maps.Keys(c.DepsDev)) {
+		if !slices.Contains(DepsDevEcosystems, ecosystem) {
+			unsupported = append(unsupported, ecosystem)
+		}
+	}
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf(
+			"deps.dev endpoints cannot be configured for %s, only for %s",
+			strings.Join(unsupported, ", "),
+			strings.Join(DepsDevEcosystems, ", "),
+		)
+	}
+
+	return nil
+}
+
 func (c *Config) warnAboutDuplicates() {
 	seen := make(map[string]struct{})
 
@@ -107,6 +107,8 @@ func Test_normalizeConfigLoadPath(t *testing.T) {
 func Test_tryLoadConfig(t *testing.T) {
 	t.Parallel()
 
+	enabled, disabled := true, false
+
 	type args struct {
 		configPath string
 	}
@@ -161,6 +163,77 @@ func Test_tryLoadConfig(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "config has per-ecosystem deps.dev endpoints",
+			args: args{
+				configPath: "./testdata/osv-scanner-depsdev.toml",
+			},
+			want: Config{
+				LoadPath: "./testdata/osv-scanner-depsdev.toml",
+				DepsDev: map[string]DepsDevEndpoint{
+					"PyPI": {
+						BaseURL: "https://deps-mirror.example.com/deps",
+						Headers: map[string]string{"Authorization": "Bearer ${DEPS_MIRROR_TOKEN}"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "config has a deps.dev endpoint for an unsupported ecosystem",
+			args: args{
+				configPath: "./testdata/osv-scanner-depsdev-unsupported.toml",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "config has a license policy",
+			args: args{
+				configPath: "./testdata/osv-scanner-license-policy.toml",
+			},
+			want: Config{
+				LoadPath: "./testdata/osv-scanner-license-policy.toml",
+				LicensePolicy: LicensePolicy{
+					Allowlist: []string{"MIT", "Apache-2.0", "BSD-3-Clause"},
+					Denylist:  []string{"GPL-3.0-only", "AGPL-3.0-only"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "config has an OSV endpoint",
+			args: args{
+				configPath: "./testdata/osv-scanner-osv.toml",
+			},
+			want: Config{
+				LoadPath: "./testdata/osv-scanner-osv.toml",
+				OSV: OSVEndpoint{
+					BaseURL: "https://osv-mirror.example.com/api",
+					Headers: map[string]string{"X-Api-Key": "${OSV_MIRROR_KEY}"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "config has enrichers",
+			args: args{
+				configPath: "./testdata/osv-scanner-enrichers.toml",
+			},
+			want: Config{
+				LoadPath: "./testdata/osv-scanner-enrichers.toml",
+				Enrichers: Enrichers{
+					PyPIDepsDev: PyPIDepsDevEnricher{Enricher: Enricher{Enabled: &disabled}},
+					MavenDepsDev: MavenDepsDevEnricher{
+						Registry:       "https://maven-mirror.example.com/maven2",
+						ExcludedScopes: []string{"test"},
+					},
+					Scorecard: ScorecardEnricher{Enricher: Enricher{Enabled: &enabled}, FailBelow: 4.5},
+					EPSS:      EPSSEnricher{MinScore: 0.1},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "load path cannot be overridden via config",
 			args: args{
@@ -1202,3 +1275,99 @@ func TestConfig_ShouldOverridePackageLicense(t *testing.T) {
 		})
 	}
 }
+
+func TestDepsDevEndpoint_ExpandedHeaders(t *testing.T) {
+	t.Setenv("DEPS_MIRROR_TOKEN", "s3cr3t")
+
+	e := DepsDevEndpoint{
+		Headers: map[string]string{
+			"Authorization": "Bearer ${DEPS_MIRROR_TOKEN}",
+			"X-Team":        "security",
+		},
+	}
+
+	want := map[string]string{
+		"Authorization": "Bearer s3cr3t",
+		"X-Team":        "security",
+	}
+	if diff := cmp.Diff(want, e.ExpandedHeaders()); diff != "" {
+		t.Errorf("ExpandedHeaders() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestConfig_AllowedVersions(t *testing.T) {
+	t.Parallel()
+
+	c := Config{
+		UpgradeConstraints: []UpgradeConstraint{
+			{Name: "django", Ecosystem: "PyPI", Versions: "<5", Reason: "the admin theme does not support Django 5"},
+			{Name: "lodash", Versions: "<5"},
+			{Name: "django", Versions: "<6"},
+			{Name: "guava", Ecosystem: "Maven", Versions: "[31.0,33.0)"},
+		},
+	}
+
+	want := map[string]string{
+		"django": "<5",
+		"lodash": "<5",
+	}
+	if diff := cmp.Diff(want, c.AllowedVersions("PyPI")); diff != "" {
+		t.Errorf("AllowedVersions(PyPI) mismatch (-want +got):\n%s", diff)
+	}
+
+	want = map[string]string{
+		"django": "<6",
+		"lodash": "<5",
+		"guava":  "[31.0,33.0)",
+	}
+	if diff := cmp.Diff(want, c.AllowedVersions("Maven")); diff != "" {
+		t.Errorf("AllowedVersions(Maven) mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestManager_GetInvalidIgnoreEntries(t *testing.T) {
+	t.Parallel()
+
+	expired := &IgnoreEntry{ID: "GHSA-1", IgnoreUntil: time.Now().Add(-time.Hour), Reason: "fixed upstream"}
+	reasonless := &IgnoreEntry{ID: "GHSA-2", Reason: " "}
+	valid := &IgnoreEntry{ID: "GHSA-3", IgnoreUntil: time.Now().Add(time.Hour), Reason: "not exploitable"}
+
+	m := Manager{
+		ConfigMap: map[string]Config{
+			"a": {LoadPath: "a/osv-scanner.toml", IgnoredVulns: []*IgnoreEntry{expired, valid}},
+			"b": {LoadPath: "b/osv-scanner.toml", IgnoredVulns: []*IgnoreEntry{reasonless}},
+		},
+	}
+
+	want := map[string][]*IgnoreEntry{"a/osv-scanner.toml": {expired}}
+	if diff := cmp.Diff(want, m.GetExpiredIgnoreEntries()); diff != "" {
+		t.Errorf("GetExpiredIgnoreEntries() mismatch (-want +got):\n%s", diff)
+	}
+	want = map[string][]*IgnoreEntry{"b/osv-scanner.toml": {reasonless}}
+	if diff := cmp.Diff(want, m.GetReasonlessIgnoreEntries()); diff != "" {
+		t.Errorf("GetReasonlessIgnoreEntries() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestManager_ExcludePaths(t *testing.T) {
+	t.Parallel()
+
+	m := Manager{ConfigMap: make(map[string]Config)}
+
+	want := []string{"**/testdata/**", "examples/**"}
+	if diff := cmp.Diff(want, m.ExcludePaths("./testdata/exclude-paths")); diff != "" {
+		t.Errorf("ExcludePaths() mismatch (-want +got):\n%s", diff)
+	}
+	// the config file is left to be loaded when filtering
+	if len(m.ConfigMap) != 0 {
+		t.Errorf("ExcludePaths() loaded %d config files, want 0", len(m.ConfigMap))
+	}
+	if got := m.ExcludePaths("./testdata/does-not-exist"); got != nil {
+		t.Errorf("ExcludePaths() without a config file = %v, want nil", got)
+	}
+
+	m.OverrideConfig = &Config{ExcludePaths: []string{"vendor/**"}}
+	if diff := cmp.Diff([]string{"vendor/**"}, m.ExcludePaths("./testdata/exclude-paths")); diff != "" {
+		t.Errorf("ExcludePaths() with an override mismatch (-want +got):\n%s", diff)
+	}
+}
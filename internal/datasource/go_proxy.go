@@ -0,0 +1,113 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+const goProxyDefault = "https://proxy.golang.org"
+
+// GoProxyAPIClient queries a Go module proxy for information about modules.
+// https://go.dev/ref/mod#goproxy-protocol
+type GoProxyAPIClient struct {
+	proxyURL string
+	versions *RequestCache[string, []string]
+	goMods   *RequestCache[string, []byte]
+}
+
+// NewGoProxyAPIClient returns a client for the Go module proxy at proxyURL.
+// If proxyURL is empty, the first proxy in GOPROXY is used, falling back to proxy.golang.org.
+func NewGoProxyAPIClient(proxyURL string) *GoProxyAPIClient {
+	if proxyURL == "" {
+		proxyURL = goProxyFromEnv()
+	}
+
+	return &GoProxyAPIClient{
+		proxyURL: strings.TrimSuffix(proxyURL, "/"),
+		versions: NewRequestCache[string, []string](),
+		goMods:   NewRequestCache[string, []byte](),
+	}
+}
+
+// goProxyFromEnv returns the first HTTP proxy in GOPROXY, if there is one.
+func goProxyFromEnv() string {
+	for _, p := range strings.FieldsFunc(os.Getenv("GOPROXY"), func(r rune) bool { return r == ',' || r == '|' }) {
+		if strings.HasPrefix(p, "https://") || strings.HasPrefix(p, "http://") {
+			return p
+		}
+	}
+
+	return goProxyDefault
+}
+
+// Versions returns the released versions of a module known to the proxy, in no particular order.
+func (c *GoProxyAPIClient) Versions(ctx context.Context, modulePath string) ([]string, error) {
+	return c.versions.Get(modulePath, func() ([]string, error) {
+		escaped, err := module.EscapePath(modulePath)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := c.get(ctx, escaped, "@v", "list")
+		if err != nil {
+			return nil, err
+		}
+
+		var versions []string
+		for line := range strings.Lines(string(body)) {
+			if v := strings.TrimSpace(line); v != "" {
+				versions = append(versions, v)
+			}
+		}
+
+		return versions, nil
+	})
+}
+
+// GoMod returns the contents of the go.mod file of a version of a module.
+func (c *GoProxyAPIClient) GoMod(ctx context.Context, modulePath, version string) ([]byte, error) {
+	return c.goMods.Get(modulePath+"@"+version, func() ([]byte, error) {
+		escapedPath, err := module.EscapePath(modulePath)
+		if err != nil {
+			return nil, err
+		}
+		escapedVersion, err := module.EscapeVersion(version)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.get(ctx, escapedPath, "@v", escapedVersion+".mod")
+	})
+}
+
+// get fetches the response body of the proxy endpoint at the joined path elements.
+func (c *GoProxyAPIClient) get(ctx context.Context, elem ...string) ([]byte, error) {
+	reqURL, err := url.JoinPath(c.proxyURL, elem...)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
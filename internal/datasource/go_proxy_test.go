@@ -0,0 +1,53 @@
+package datasource_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/datasource"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+)
+
+func TestGoProxyClient_Versions(t *testing.T) {
+	t.Parallel()
+
+	srv := testutility.NewMockHTTPServer(t)
+	srv.SetResponse(t, "github.com/!burnt!sushi/toml/@v/list", []byte("v1.2.0\nv1.0.0\n\nv1.2.1\n"))
+
+	cl := datasource.NewGoProxyAPIClient(srv.URL)
+
+	got, err := cl.Versions(t.Context(), "github.com/BurntSushi/toml")
+	if err != nil {
+		t.Fatalf("failed getting versions: %v", err)
+	}
+	want := []string{"v1.2.0", "v1.0.0", "v1.2.1"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Versions() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := cl.Versions(t.Context(), "example.com/unknown"); err == nil {
+		t.Errorf("Versions() of unknown module returned no error")
+	}
+}
+
+func TestGoProxyClient_GoMod(t *testing.T) {
+	t.Parallel()
+
+	gomod := "module github.com/BurntSushi/toml\n\ngo 1.18\n"
+	srv := testutility.NewMockHTTPServer(t)
+	srv.SetResponse(t, "github.com/!burnt!sushi/toml/@v/v1.2.0.mod", []byte(gomod))
+
+	cl := datasource.NewGoProxyAPIClient(srv.URL)
+
+	got, err := cl.GoMod(t.Context(), "github.com/BurntSushi/toml", "v1.2.0")
+	if err != nil {
+		t.Fatalf("failed getting go.mod: %v", err)
+	}
+	if diff := cmp.Diff(gomod, string(got)); diff != "" {
+		t.Errorf("GoMod() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := cl.GoMod(t.Context(), "github.com/BurntSushi/toml", "v9.9.9"); err == nil {
+		t.Errorf("GoMod() of unknown version returned no error")
+	}
+}
@@ -0,0 +1,169 @@
+package depsdev
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultCircuitBreakerThreshold is the default number of consecutive failed
+// deps.dev lookups after which the remaining lookups for a manifest are skipped.
+const DefaultCircuitBreakerThreshold = 5
+
+// ErrCircuitOpen describes lookups that were skipped because deps.dev has
+// repeatedly failed to respond.
+var ErrCircuitOpen = errors.New("deps.dev lookups skipped after repeated failures")
+
+// circuitBreaker fast-fails lookups once a run of consecutive failures
+// reaches its threshold. It is safe for concurrent use.
+type circuitBreaker struct {
+	threshold int
+
+	mu       sync.Mutex
+	failures int
+	lastErr  error
+}
+
+func newCircuitBreaker(threshold int) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold}
+}
+
+// open reports whether lookups should be skipped.
+func (b *circuitBreaker) open() bool {
+	if b.threshold <= 0 {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.failures >= b.threshold
+}
+
+// record updates the breaker with the outcome of a lookup. Errors saying the
+// package is unknown are not a sign of an outage, so count as a success.
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil || errors.Is(err, ErrNotFound) || errors.Is(err, ErrNotInSnapshot) {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	b.lastErr = err
+}
+
+// cause returns the last failure seen by the breaker.
+func (b *circuitBreaker) cause() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.lastErr
+}
+
+// Degradation describes a manifest whose transitive dependencies could only be
+// partially resolved.
+type Degradation struct {
+	// Path is the manifest the degradation applies to, if it applies to one.
+	Path string
+	// Enricher is the enricher that only partially enriched the inventory,
+	// for degradations that apply to the whole scan rather than a manifest.
+	Enricher string
+	// Reason explains why resolution was degraded.
+	Reason string
+}
+
+// UnresolvedPackage describes a package declared in a manifest whose
+// dependencies could not be resolved, so they are missing from the results.
+type UnresolvedPackage struct {
+	// Path is the manifest that declares the package.
+	Path    string
+	Name    string
+	Version string
+	// Reason explains why the package could not be resolved.
+	Reason string
+}
+
+// Degradations collects the Degradation of each manifest, along with the
+// packages that could not be resolved at all. It is safe for concurrent use.
+type Degradations struct {
+	mu         sync.Mutex
+	items      []Degradation
+	unresolved []UnresolvedPackage
+}
+
+// Record adds a degradation for the manifest at path, unless it has already
+// been recorded.
+func (d *Degradations) Record(path, reason string) {
+	d.record(Degradation{Path: path, Reason: reason})
+}
+
+func (d *Degradations) record(item Degradation) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !slices.Contains(d.items, item) {
+		d.items = append(d.items, item)
+	}
+}
+
+// RecordEnricher adds a degradation for the enricher called name, unless it
+// has already been recorded.
+func (d *Degradations) RecordEnricher(name, reason string) {
+	d.record(Degradation{Enricher: name, Reason: reason})
+}
+
+// List returns the recorded degradations, sorted by path and then enricher.
+func (d *Degradations) List() []Degradation {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	items := slices.Clone(d.items)
+	slices.SortStableFunc(items, func(a, b Degradation) int {
+		return cmp.Or(strings.Compare(a.Path, b.Path), strings.Compare(a.Enricher, b.Enricher))
+	})
+
+	return items
+}
+
+// RecordUnresolved adds a package declared in the manifest at path whose
+// dependencies could not be resolved.
+func (d *Degradations) RecordUnresolved(path, name, version, reason string) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.unresolved = append(d.unresolved, UnresolvedPackage{Path: path, Name: name, Version: version, Reason: reason})
+}
+
+// Unresolved returns the recorded unresolved packages, sorted by path and name.
+func (d *Degradations) Unresolved() []UnresolvedPackage {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	items := slices.Clone(d.unresolved)
+	slices.SortStableFunc(items, func(a, b UnresolvedPackage) int {
+		return cmp.Or(strings.Compare(a.Path, b.Path), strings.Compare(a.Name, b.Name))
+	})
+
+	return items
+}
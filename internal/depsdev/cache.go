@@ -0,0 +1,118 @@
+package depsdev
+
+import (
+	"container/list"
+	"sync"
+	"unsafe"
+)
+
+// DefaultCacheLimits are the cache limits used when none are configured.
+var DefaultCacheLimits = CacheLimits{
+	MaxEntries: 4096,
+	MaxBytes:   256 << 20, // 256 MiB
+}
+
+// CacheLimits bounds the in-memory dependency graph cache of a client.
+// A zero value for either field leaves that dimension unbounded.
+type CacheLimits struct {
+	MaxEntries int
+	MaxBytes   int64
+}
+
+// graphCache is a size-bounded LRU cache of dependency graphs, keyed by name@version.
+type graphCache struct {
+	limits CacheLimits
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front is most recently used
+	bytes   int64
+}
+
+type graphCacheEntry struct {
+	key   string
+	graph *DepsDevDependencyGraph
+	size  int64
+}
+
+func newGraphCache(limits CacheLimits) *graphCache {
+	return &graphCache{
+		limits:  limits,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the cached graph for key, marking it as recently used.
+func (c *graphCache) get(key string) (*DepsDevDependencyGraph, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+
+	return elem.Value.(*graphCacheEntry).graph, true
+}
+
+// add inserts graph into the cache, evicting the least recently used entries
+// until the cache is within its limits again.
+func (c *graphCache) add(key string, graph *DepsDevDependencyGraph) {
+	size := graphSize(graph)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*graphCacheEntry)
+		c.bytes += size - entry.size
+		entry.graph, entry.size = graph, size
+		c.order.MoveToFront(elem)
+	} else {
+		c.entries[key] = c.order.PushFront(&graphCacheEntry{key: key, graph: graph, size: size})
+		c.bytes += size
+	}
+
+	for c.order.Len() > 1 && c.overLimit() {
+		oldest := c.order.Back()
+		entry := oldest.Value.(*graphCacheEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, entry.key)
+		c.bytes -= entry.size
+	}
+}
+
+// len returns the number of cached graphs.
+func (c *graphCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *graphCache) overLimit() bool {
+	if c.limits.MaxEntries > 0 && c.order.Len() > c.limits.MaxEntries {
+		return true
+	}
+
+	return c.limits.MaxBytes > 0 && c.bytes > c.limits.MaxBytes
+}
+
+// graphSize estimates the memory held by a dependency graph.
+func graphSize(graph *DepsDevDependencyGraph) int64 {
+	size := int64(unsafe.Sizeof(*graph))
+	for _, node := range graph.Nodes {
+		size += int64(unsafe.Sizeof(node))
+		size += int64(len(node.VersionKey.System) + len(node.VersionKey.Name) + len(node.VersionKey.Version) + len(node.Relation))
+		for _, e := range node.Errors {
+			size += int64(unsafe.Sizeof(e)) + int64(len(e))
+		}
+	}
+	for _, edge := range graph.Edges {
+		size += int64(unsafe.Sizeof(edge)) + int64(len(edge.Requirement))
+	}
+
+	return size
+}
@@ -0,0 +1,71 @@
+package depsdev
+
+import (
+	"strconv"
+	"testing"
+)
+
+func testGraph(name string) *DepsDevDependencyGraph {
+	return &DepsDevDependencyGraph{
+		Nodes: []DepsDevNode{{VersionKey: DepsDevVersionKey{System: "PYPI", Name: name, Version: "1.0.0"}, Relation: "SELF"}},
+	}
+}
+
+func TestGraphCache_MaxEntries(t *testing.T) {
+	t.Parallel()
+
+	c := newGraphCache(CacheLimits{MaxEntries: 2})
+	c.add("a", testGraph("a"))
+	c.add("b", testGraph("b"))
+
+	// Touch "a" so "b" becomes the least recently used entry.
+	if _, ok := c.get("a"); !ok {
+		t.Fatal("get(a) missing, want present")
+	}
+	c.add("c", testGraph("c"))
+
+	if _, ok := c.get("b"); ok {
+		t.Error("get(b) present, want evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.get(key); !ok {
+			t.Errorf("get(%s) missing, want present", key)
+		}
+	}
+	if got := c.len(); got != 2 {
+		t.Errorf("len() = %d, want 2", got)
+	}
+}
+
+func TestGraphCache_MaxBytes(t *testing.T) {
+	t.Parallel()
+
+	size := graphSize(testGraph("pkg0"))
+	c := newGraphCache(CacheLimits{MaxBytes: 3 * size})
+	for i := range 10 {
+		c.add("pkg"+strconv.Itoa(i), testGraph("pkg"+strconv.Itoa(i)))
+	}
+
+	if got := c.len(); got != 3 {
+		t.Errorf("len() = %d, want 3", got)
+	}
+	if c.bytes > 3*size {
+		t.Errorf("bytes = %d, want <= %d", c.bytes, 3*size)
+	}
+	if _, ok := c.get("pkg9"); !ok {
+		t.Error("get(pkg9) missing, want most recent entry present")
+	}
+}
+
+func TestGraphCache_Unbounded(t *testing.T) {
+	t.Parallel()
+
+	c := newGraphCache(CacheLimits{})
+	for i := range 100 {
+		c.add(strconv.Itoa(i), testGraph(strconv.Itoa(i)))
+	}
+
+	if got := c.len(); got != 100 {
+		t.Errorf("len() = %d, want 100", got)
+	}
+}
@@ -0,0 +1,82 @@
+package depsdev
+
+import (
+	"context"
+	"sync"
+)
+
+// coalesceGroup tracks the calls in flight for coalesce. The zero value is
+// ready to use.
+type coalesceGroup struct {
+	mu    sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+// coalescedCall is a call shared by all the callers waiting on it.
+type coalescedCall struct {
+	done    chan struct{}
+	val     any
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// coalesce runs fn once for all concurrent callers with the same key.
+//
+// fn is not bound to the context of the caller that happens to start it, as
+// the other callers would otherwise fail along with it if it were cancelled.
+// Each caller instead stops waiting as soon as its own context is done, and
+// the context of fn is cancelled once none are left waiting on it.
+func coalesce(ctx context.Context, group *coalesceGroup, key string, fn func(ctx context.Context) (any, error)) (any, error) {
+	group.mu.Lock()
+	call, ok := group.calls[key]
+	if !ok {
+		shared, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &coalescedCall{done: make(chan struct{}), cancel: cancel}
+		if group.calls == nil {
+			group.calls = make(map[string]*coalescedCall)
+		}
+		group.calls[key] = call
+
+		go func() {
+			defer close(call.done)
+			defer cancel()
+
+			call.val, call.err = fn(shared)
+			group.forget(key, call)
+		}()
+	}
+	call.waiters++
+	group.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		group.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// later callers start a call of their own rather than
+			// waiting on one that is being cancelled
+			call.cancel()
+			group.forgetLocked(key, call)
+		}
+		group.mu.Unlock()
+
+		return nil, ctx.Err()
+	case <-call.done:
+		return call.val, call.err
+	}
+}
+
+// forget removes call from the calls in flight, if it is still the one for key.
+func (g *coalesceGroup) forget(key string, call *coalescedCall) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.forgetLocked(key, call)
+}
+
+func (g *coalesceGroup) forgetLocked(key string, call *coalescedCall) {
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
@@ -0,0 +1,114 @@
+package depsdev
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCoalesce_Shared(t *testing.T) {
+	t.Parallel()
+
+	var group coalesceGroup
+	release := make(chan struct{})
+	started := make(chan struct{})
+	calls := 0
+	fn := func(context.Context) (any, error) {
+		calls++
+		close(started)
+		<-release
+
+		return "result", nil
+	}
+
+	first := make(chan any)
+	go func() {
+		v, _ := coalesce(t.Context(), &group, "key", fn)
+		first <- v
+	}()
+	<-started
+
+	second := make(chan any)
+	go func() {
+		v, _ := coalesce(t.Context(), &group, "key", fn)
+		second <- v
+	}()
+	// wait for the second caller to join the call in flight
+	for waiters(&group, "key") < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	if got := <-first; got != "result" {
+		t.Errorf("first coalesce() = %v, want result", got)
+	}
+	if got := <-second; got != "result" {
+		t.Errorf("second coalesce() = %v, want result", got)
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times, want 1", calls)
+	}
+}
+
+func TestCoalesce_CancelledWhenNoneWaiting(t *testing.T) {
+	t.Parallel()
+
+	var group coalesceGroup
+	fnCtx := make(chan context.Context, 1)
+	fn := func(ctx context.Context) (any, error) {
+		fnCtx <- ctx
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+
+	ctx1, cancel1 := context.WithCancel(t.Context())
+	ctx2, cancel2 := context.WithCancel(t.Context())
+	errs := make(chan error, 2)
+	go func() {
+		_, err := coalesce(ctx1, &group, "key", fn)
+		errs <- err
+	}()
+	shared := <-fnCtx
+	go func() {
+		_, err := coalesce(ctx2, &group, "key", fn)
+		errs <- err
+	}()
+	for waiters(&group, "key") < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the call goes on while anyone is still waiting on it
+	cancel1()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("coalesce() error = %v, want %v", err, context.Canceled)
+	}
+	select {
+	case <-shared.Done():
+		t.Fatal("fn cancelled while a caller is still waiting")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	cancel2()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("coalesce() error = %v, want %v", err, context.Canceled)
+	}
+	select {
+	case <-shared.Done():
+	case <-time.After(time.Second):
+		t.Fatal("fn not cancelled once no caller is waiting")
+	}
+}
+
+// waiters returns the number of callers waiting on the call for key.
+func waiters(group *coalesceGroup, key string) int {
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	if call, ok := group.calls[key]; ok {
+		return call.waiters
+	}
+
+	return 0
+}
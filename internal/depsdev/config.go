@@ -0,0 +1,210 @@
+package depsdev
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+)
+
+// Transport selects how the enrichers talk to deps.dev.
+type Transport string
+
+const (
+	// TransportREST uses the deps.dev REST API over HTTP.
+	TransportREST Transport = "rest"
+	// TransportGRPC uses the deps.dev gRPC API.
+	TransportGRPC Transport = "grpc"
+)
+
+// DefaultParallelism is the default number of concurrent deps.dev requests
+// made while resolving a single manifest.
+const DefaultParallelism = 8
+
+// Config configures the deps.dev backed enrichers.
+type Config struct {
+	// BaseURL is the deps.dev REST API endpoint, e.g. "https://api.deps.dev"
+	// or a proxy such as apiconfig.DepsDevAPIURL.
+	BaseURL string
+
+	// Transport selects the deps.dev API transport. Defaults to TransportREST.
+	Transport Transport
+
+	// GRPCAddr is the deps.dev gRPC endpoint used with TransportGRPC.
+	// Defaults to DepsdevAPI.
+	GRPCAddr string
+
+	// SnapshotPath, if set, reads dependency graphs from a local snapshot
+	// (see SnapshotClient) instead of the live API. Takes precedence over Transport.
+	SnapshotPath string
+
+	// CacheLimits bounds the in-memory dependency graph cache.
+	// A zero value uses DefaultCacheLimits.
+	CacheLimits CacheLimits
+
+	// HTTPClient is used for REST requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// UserAgent is sent with every deps.dev request, if set.
+	UserAgent string
+
+	// Headers are additional headers sent with every deps.dev request.
+	Headers map[string]string
+
+	// Metrics receives instrumentation events from the requests made by the
+	// enricher, if set. Pooled clients are shared, so the events are passed
+	// along with each request rather than being bound to the client.
+	Metrics Metrics
+
+	// Pool is the client pool the enricher takes its client from.
+	// Defaults to DefaultClientPool.
+	Pool *ClientPool
+
+	// Parallelism is the maximum number of concurrent deps.dev requests made
+	// while resolving a single manifest. Values <= 0 use DefaultParallelism.
+	Parallelism int
+
+	// CircuitBreakerThreshold is the number of consecutive failed lookups after
+	// which the remaining lookups for a manifest are skipped. Zero uses
+	// DefaultCircuitBreakerThreshold, and a negative value disables the breaker.
+	CircuitBreakerThreshold int
+
+	// ConflictStrategy selects which versions are kept when the roots of a
+	// manifest resolve a package to different versions. Defaults to ConflictReportAll.
+	ConflictStrategy ConflictStrategy
+
+	// MaxDepth limits resolution to packages at most this many dependencies
+	// away from the manifest, with the packages declared in it being at depth 1.
+	// Values <= 0 resolve the whole graph.
+	MaxDepth int
+
+	// PyPIRegistryURL is the PyPI JSON API used to pick a version for
+	// requirements that are not pinned to one. Defaults to DefaultPyPIRegistryURL.
+	// Unpinned requirements are not resolved when reading from a snapshot.
+	PyPIRegistryURL string
+
+	// PythonEnvironment is the environment that environment markers in
+	// requirements are evaluated against.
+	PythonEnvironment PythonEnvironment
+
+	// Degradations records manifests that could only be partially resolved, if set.
+	Degradations *Degradations
+}
+
+// parallelism returns the effective parallelism for the config.
+func (c Config) parallelism() int {
+	if c.Parallelism <= 0 {
+		return DefaultParallelism
+	}
+
+	return c.Parallelism
+}
+
+// circuitBreakerThreshold returns the effective circuit breaker threshold for the config.
+func (c Config) circuitBreakerThreshold() int {
+	if c.CircuitBreakerThreshold == 0 {
+		return DefaultCircuitBreakerThreshold
+	}
+
+	return c.CircuitBreakerThreshold
+}
+
+// cacheLimits returns the effective cache limits for the config.
+func (c Config) cacheLimits() CacheLimits {
+	if c.CacheLimits == (CacheLimits{}) {
+		return DefaultCacheLimits
+	}
+
+	return c.CacheLimits
+}
+
+// clientOptions returns the options used to construct clients for the config.
+func (c Config) clientOptions() ClientOptions {
+	return ClientOptions{
+		HTTPClient:  c.HTTPClient,
+		CacheLimits: c.cacheLimits(),
+		UserAgent:   c.UserAgent,
+		Headers:     c.Headers,
+		Metrics:     c.Metrics,
+	}
+}
+
+// registry returns the client used to resolve unpinned requirements, or nil
+// if they should not be resolved.
+func (c Config) registry() *PyPIRegistryClient {
+	if c.SnapshotPath != "" {
+		return nil
+	}
+
+	return NewPyPIRegistryClient(c.PyPIRegistryURL, c.clientOptions())
+}
+
+// pool returns the client pool for the config.
+func (c Config) pool() *ClientPool {
+	if c.Pool == nil {
+		return DefaultClientPool
+	}
+
+	return c.Pool
+}
+
+// poolKey identifies the endpoint a client created from the config talks to,
+// along with the identity (user agent and headers) it presents to it.
+func (c Config) poolKey() string {
+	if c.SnapshotPath != "" {
+		return "snapshot|" + c.SnapshotPath
+	}
+
+	var key string
+	switch c.Transport {
+	case TransportGRPC:
+		addr := c.GRPCAddr
+		if addr == "" {
+			addr = DepsdevAPI
+		}
+		key = string(TransportGRPC) + "|" + addr
+	default:
+		key = string(TransportREST) + "|" + c.BaseURL
+		if c.HTTPClient != nil {
+			key += fmt.Sprintf("|%p", c.HTTPClient)
+		}
+	}
+
+	key += "|" + c.UserAgent
+	for _, k := range slices.Sorted(maps.Keys(c.Headers)) {
+		key += "|" + k + "=" + c.Headers[k]
+	}
+
+	return key
+}
+
+// newClient constructs the dependency graph client for the configured transport.
+func (c Config) newClient() (DependencyGraphClient, error) {
+	if c.SnapshotPath != "" {
+		client, err := NewSnapshotClient(c.SnapshotPath)
+		if err != nil {
+			return nil, err
+		}
+
+		return client, nil
+	}
+
+	switch c.Transport {
+	case "", TransportREST:
+		return NewPyPIDepsDevClient(c.BaseURL, c.clientOptions()), nil
+	case TransportGRPC:
+		addr := c.GRPCAddr
+		if addr == "" {
+			addr = DepsdevAPI
+		}
+
+		client, err := NewPyPIDepsDevGRPCClient(addr, c.clientOptions())
+		if err != nil {
+			return nil, err
+		}
+
+		return client, nil
+	default:
+		return nil, fmt.Errorf("unsupported deps.dev transport %q", c.Transport)
+	}
+}
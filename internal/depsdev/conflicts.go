@@ -0,0 +1,105 @@
+package depsdev
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/semantic"
+)
+
+// ConflictStrategy selects which version of a package is kept when the roots
+// of a manifest resolve it to different versions.
+type ConflictStrategy string
+
+const (
+	// ConflictReportAll keeps every resolved version of a package.
+	ConflictReportAll ConflictStrategy = "report-all"
+	// ConflictHighestWins keeps only the highest resolved version of a package.
+	ConflictHighestWins ConflictStrategy = "highest-wins"
+	// ConflictNearestWins keeps only the version closest to the manifest, as
+	// Maven does. Ties go to the version reached from the first root by name.
+	ConflictNearestWins ConflictStrategy = "nearest-wins"
+)
+
+// ConflictStrategies returns the supported conflict strategies.
+func ConflictStrategies() []ConflictStrategy {
+	return []ConflictStrategy{ConflictReportAll, ConflictHighestWins, ConflictNearestWins}
+}
+
+// ParseConflictStrategy parses s as a ConflictStrategy, with an empty string
+// being ConflictReportAll.
+func ParseConflictStrategy(s string) (ConflictStrategy, error) {
+	if s == "" {
+		return ConflictReportAll, nil
+	}
+	if !slices.Contains(ConflictStrategies(), ConflictStrategy(s)) {
+		return "", fmt.Errorf("unsupported version conflict strategy %q", s)
+	}
+
+	return ConflictStrategy(s), nil
+}
+
+// resolveConflicts removes the versions of each package in pkgs not selected
+// by the strategy, keeping the order of the remaining packages.
+func (s ConflictStrategy) resolveConflicts(pkgs []*extractor.Package, ecosystem string) []*extractor.Package {
+	if s == "" || s == ConflictReportAll {
+		return pkgs
+	}
+
+	chosen := make(map[string]*extractor.Package)
+	for _, pkg := range pkgs {
+		current, ok := chosen[pkg.Name]
+		if !ok || s.prefer(pkg, current, ecosystem) {
+			chosen[pkg.Name] = pkg
+		}
+	}
+
+	return slices.DeleteFunc(pkgs, func(pkg *extractor.Package) bool {
+		return chosen[pkg.Name] != pkg
+	})
+}
+
+// prefer reports whether candidate should replace current.
+func (s ConflictStrategy) prefer(candidate, current *extractor.Package, ecosystem string) bool {
+	switch s {
+	case ConflictHighestWins:
+		return compareVersions(candidate.Version, current.Version, ecosystem) > 0
+	case ConflictNearestWins:
+		return depth(candidate) < depth(current)
+	default:
+		return false
+	}
+}
+
+// depth returns the distance of a resolved package from the manifest, with
+// the packages declared in the manifest being at depth 0. Packages that are
+// not reachable from the manifest are at depth math.MaxInt.
+func depth(pkg *extractor.Package) int {
+	md, ok := pkg.Metadata.(*Metadata)
+	if !ok || len(md.IntroducedBy) == 0 {
+		return math.MaxInt
+	}
+
+	d := math.MaxInt
+	for _, chain := range md.IntroducedBy {
+		d = min(d, len(chain))
+	}
+
+	return d
+}
+
+// compareVersions compares two versions of a package, falling back to
+// comparing them as strings if they cannot be parsed.
+func compareVersions(a, b, ecosystem string) int {
+	v, err := semantic.Parse(a, ecosystem)
+	if err == nil {
+		if c, err := v.CompareStr(b); err == nil {
+			return c
+		}
+	}
+
+	return cmp.Compare(a, b)
+}
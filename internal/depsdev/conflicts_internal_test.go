@@ -0,0 +1,57 @@
+package depsdev
+
+import (
+	"testing"
+
+	"github.com/google/osv-scalibr/extractor"
+)
+
+func TestConflictStrategy_resolveConflicts(t *testing.T) {
+	t.Parallel()
+
+	newPkgs := func() []*extractor.Package {
+		return []*extractor.Package{
+			{Name: "shared", Version: "1.10.0", Metadata: &Metadata{IntroducedBy: [][]string{{"a@1", "b@1"}}}},
+			{Name: "other", Version: "1.0.0", Metadata: &Metadata{IntroducedBy: [][]string{{"a@1"}}}},
+			{Name: "shared", Version: "1.9.0", Metadata: &Metadata{IntroducedBy: [][]string{{"c@1"}}}},
+			{Name: "shared", Version: "1.2.0", Metadata: &Metadata{IntroducedBy: [][]string{{"d@1"}}}},
+		}
+	}
+
+	tests := []struct {
+		strategy ConflictStrategy
+		want     []string
+	}{
+		{strategy: "", want: []string{"shared@1.10.0", "other@1.0.0", "shared@1.9.0", "shared@1.2.0"}},
+		{strategy: ConflictReportAll, want: []string{"shared@1.10.0", "other@1.0.0", "shared@1.9.0", "shared@1.2.0"}},
+		{strategy: ConflictHighestWins, want: []string{"shared@1.10.0", "other@1.0.0"}},
+		{strategy: ConflictNearestWins, want: []string{"other@1.0.0", "shared@1.9.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.strategy.resolveConflicts(newPkgs(), "PyPI")
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("resolveConflicts() returned %d packages, want %d", len(got), len(tt.want))
+			}
+			for i, pkg := range got {
+				if s := pkg.Name + "@" + pkg.Version; s != tt.want[i] {
+					t.Errorf("resolveConflicts()[%d] = %s, want %s", i, s, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseConflictStrategy(t *testing.T) {
+	t.Parallel()
+
+	if got, err := ParseConflictStrategy(""); err != nil || got != ConflictReportAll {
+		t.Errorf("ParseConflictStrategy(\"\") = %q, %v, want %q", got, err, ConflictReportAll)
+	}
+	if _, err := ParseConflictStrategy("lowest-wins"); err == nil {
+		t.Errorf("ParseConflictStrategy(\"lowest-wins\") did not return an error")
+	}
+}
@@ -4,11 +4,12 @@ package depsdev
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"sync"
+	"time"
 )
 
 // DepsDevDependencyGraph is the response from the deps.dev dependencies API.
@@ -39,20 +40,77 @@ type DepsDevEdge struct {
 	Requirement string `json:"requirement"`
 }
 
+// ErrNotFound is returned when deps.dev does not know about a package version.
+var ErrNotFound = errors.New("package version not found on deps.dev")
+
+// pypiSystem is the deps.dev system that the clients look up packages in by default.
+const pypiSystem = "pypi"
+
+// DependencyGraphClient fetches pre-computed dependency graphs from deps.dev.
+// It is implemented by each of the supported transports.
+type DependencyGraphClient interface {
+	GetDependencies(ctx context.Context, name, version string) (*DepsDevDependencyGraph, error)
+}
+
+// SystemDependencyGraphClient is a DependencyGraphClient that can also fetch
+// the dependency graphs of packages from systems other than PyPI, such as
+// "npm" or "maven".
+type SystemDependencyGraphClient interface {
+	DependencyGraphClient
+	GetSystemDependencies(ctx context.Context, system, name, version string) (*DepsDevDependencyGraph, error)
+}
+
+// ClientOptions holds the options shared by the deps.dev clients.
+type ClientOptions struct {
+	// HTTPClient is used for REST requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// CacheLimits bounds the in-memory dependency graph cache.
+	CacheLimits CacheLimits
+	// UserAgent is sent with every request, if set.
+	UserAgent string
+	// Headers are additional headers (or gRPC metadata) sent with every request,
+	// e.g. for authenticating against an internal deps.dev mirror.
+	Headers map[string]string
+	// Metrics receives instrumentation events, if set.
+	Metrics Metrics
+}
+
+// metrics returns the configured Metrics, or one that discards all events.
+func (o ClientOptions) metrics() Metrics {
+	if o.Metrics == nil {
+		return noopMetrics{}
+	}
+
+	return o.Metrics
+}
+
 // PyPIDepsDevClient fetches pre-computed dependency graphs from the deps.dev REST API.
 type PyPIDepsDevClient struct {
-	baseURL string
-	mu      sync.Mutex
-	cache   map[string]*DepsDevDependencyGraph
+	client    *http.Client
+	baseURL   string
+	userAgent string
+	headers   map[string]string
+	metrics   Metrics
+	cache     *graphCache
+	group     coalesceGroup
 }
 
 // NewPyPIDepsDevClient creates a new client for the deps.dev REST API.
 // baseURL should be the deps.dev API endpoint, e.g. "https://api.deps.dev"
 // or a proxy like "https://data-api.codexsecurity.io/deps".
-func NewPyPIDepsDevClient(baseURL string) *PyPIDepsDevClient {
+func NewPyPIDepsDevClient(baseURL string, opts ClientOptions) *PyPIDepsDevClient {
+	client := opts.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
 	return &PyPIDepsDevClient{
-		baseURL: baseURL,
-		cache:   make(map[string]*DepsDevDependencyGraph),
+		client:    client,
+		baseURL:   baseURL,
+		userAgent: opts.UserAgent,
+		headers:   opts.Headers,
+		metrics:   opts.metrics(),
+		cache:     newGraphCache(opts.CacheLimits),
 	}
 }
 
@@ -60,18 +118,46 @@ func NewPyPIDepsDevClient(baseURL string) *PyPIDepsDevClient {
 // This is a single HTTP GET that returns the full transitive dependency tree —
 // no package downloads required.
 func (c *PyPIDepsDevClient) GetDependencies(ctx context.Context, name, version string) (*DepsDevDependencyGraph, error) {
-	cacheKey := name + "@" + version
+	return c.GetSystemDependencies(ctx, pypiSystem, name, version)
+}
+
+// GetSystemDependencies fetches the pre-computed dependency graph for a
+// package version of the given deps.dev system.
+func (c *PyPIDepsDevClient) GetSystemDependencies(ctx context.Context, system, name, version string) (*DepsDevDependencyGraph, error) {
+	cacheKey := graphCacheKey(system, name, version)
+	metrics := metricsFrom(ctx, c.metrics)
 
-	c.mu.Lock()
-	if cached, ok := c.cache[cacheKey]; ok {
-		c.mu.Unlock()
+	if cached, ok := c.cache.get(cacheKey); ok {
+		metrics.CacheHit(system)
 		return cached, nil
 	}
-	c.mu.Unlock()
+	metrics.CacheMiss(system)
+
+	// Coalesce concurrent lookups of the same package version into a single request.
+	v, err := coalesce(ctx, &c.group, cacheKey, func(ctx context.Context) (any, error) {
+		start := time.Now()
+		graph, err := c.fetch(ctx, system, name, version)
+		metrics.Request(system, time.Since(start), err)
+		if err != nil {
+			return nil, err
+		}
+		c.cache.add(cacheKey, graph)
+
+		return graph, nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	// Build URL: {baseURL}/v3/systems/pypi/packages/{name}/versions/{version}:dependencies
-	reqURL := fmt.Sprintf("%s/v3/systems/pypi/packages/%s/versions/%s:dependencies",
+	return v.(*DepsDevDependencyGraph), nil
+}
+
+// fetch performs the HTTP request for a package version's dependency graph.
+func (c *PyPIDepsDevClient) fetch(ctx context.Context, system, name, version string) (*DepsDevDependencyGraph, error) {
+	// Build URL: {baseURL}/v3/systems/{system}/packages/{name}/versions/{version}:dependencies
+	reqURL := fmt.Sprintf("%s/v3/systems/%s/packages/%s/versions/%s:dependencies",
 		c.baseURL,
+		url.PathEscape(system),
 		url.PathEscape(name),
 		url.PathEscape(version),
 	)
@@ -81,13 +167,22 @@ func (c *PyPIDepsDevClient) GetDependencies(ctx context.Context, name, version s
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Accept", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("deps.dev API request failed for %s@%s: %w", name, version, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s@%s", ErrNotFound, name, version)
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("deps.dev API returned %d for %s@%s: %s", resp.StatusCode, name, version, string(body))
@@ -98,9 +193,15 @@ func (c *PyPIDepsDevClient) GetDependencies(ctx context.Context, name, version s
 		return nil, fmt.Errorf("failed to decode deps.dev response for %s@%s: %w", name, version, err)
 	}
 
-	c.mu.Lock()
-	c.cache[cacheKey] = &graph
-	c.mu.Unlock()
-
 	return &graph, nil
 }
+
+// graphCacheKey identifies a package version in the dependency graph caches.
+// PyPI packages are keyed by their normalized name and version alone.
+func graphCacheKey(system, name, version string) string {
+	if system == pypiSystem {
+		return NormalizePyPIName(name) + "@" + version
+	}
+
+	return system + ":" + name + "@" + version
+}
@@ -0,0 +1,157 @@
+package depsdev_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+)
+
+func TestPyPIDepsDevClient_GetDependencies_Coalesced(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		// Hold the request open so the concurrent callers overlap.
+		time.Sleep(50 * time.Millisecond)
+		_ = json.NewEncoder(w).Encode(depsdev.DepsDevDependencyGraph{
+			Nodes: []depsdev.DepsDevNode{
+				{VersionKey: depsdev.DepsDevVersionKey{System: "PYPI", Name: "requests", Version: "2.31.0"}, Relation: "SELF"},
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	c := depsdev.NewPyPIDepsDevClient(srv.URL, depsdev.ClientOptions{})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			if _, err := c.GetDependencies(t.Context(), "requests", "2.31.0"); err != nil {
+				t.Errorf("GetDependencies() error: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server received %d requests, want 1", got)
+	}
+}
+
+func TestPyPIDepsDevClient_GetDependencies_CancelledCaller(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		_ = json.NewEncoder(w).Encode(depsdev.DepsDevDependencyGraph{})
+	}))
+	t.Cleanup(srv.Close)
+
+	c := depsdev.NewPyPIDepsDevClient(srv.URL, depsdev.ClientOptions{})
+
+	// The first caller starts the request and then gives up on it.
+	ctx, cancel := context.WithCancel(t.Context())
+	leader := make(chan error)
+	go func() {
+		_, err := c.GetDependencies(ctx, "requests", "2.31.0")
+		leader <- err
+	}()
+	<-started
+
+	follower := make(chan error)
+	go func() {
+		_, err := c.GetDependencies(t.Context(), "requests", "2.31.0")
+		follower <- err
+	}()
+	// The request is cancelled if no caller is left waiting on it, so give
+	// the second caller time to join it.
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	if err := <-leader; !errors.Is(err, context.Canceled) {
+		t.Errorf("GetDependencies() error = %v, want %v", err, context.Canceled)
+	}
+
+	// The request carries on for the callers that are still waiting on it.
+	close(release)
+	if err := <-follower; err != nil {
+		t.Errorf("GetDependencies() error = %v, want nil", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server received %d requests, want 1", got)
+	}
+}
+
+func TestPyPIDepsDevClient_GetDependencies_Headers(t *testing.T) {
+	t.Parallel()
+
+	var gotUserAgent, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.Header.Get("User-Agent")
+		gotAuth = r.Header.Get("X-Mirror-Token")
+		_ = json.NewEncoder(w).Encode(depsdev.DepsDevDependencyGraph{})
+	}))
+	t.Cleanup(srv.Close)
+
+	c := depsdev.NewPyPIDepsDevClient(srv.URL, depsdev.ClientOptions{
+		UserAgent: "osv-scanner_test/1.0.0",
+		Headers:   map[string]string{"X-Mirror-Token": "secret"},
+	})
+	if _, err := c.GetDependencies(t.Context(), "requests", "2.31.0"); err != nil {
+		t.Fatalf("GetDependencies() error: %v", err)
+	}
+
+	if gotUserAgent != "osv-scanner_test/1.0.0" {
+		t.Errorf("User-Agent = %q, want %q", gotUserAgent, "osv-scanner_test/1.0.0")
+	}
+	if gotAuth != "secret" {
+		t.Errorf("X-Mirror-Token = %q, want %q", gotAuth, "secret")
+	}
+}
+
+func TestPyPIDepsDevClient_GetDependencies_Metrics(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/missing/") {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(depsdev.DepsDevDependencyGraph{})
+	}))
+	t.Cleanup(srv.Close)
+
+	stats := &depsdev.Stats{}
+	c := depsdev.NewPyPIDepsDevClient(srv.URL, depsdev.ClientOptions{Metrics: stats})
+
+	// both names are the same project once normalized, so share a cache entry
+	for _, name := range []string{"typing-extensions", "Typing_Extensions"} {
+		if _, err := c.GetDependencies(t.Context(), name, "4.9.0"); err != nil {
+			t.Fatalf("GetDependencies() error: %v", err)
+		}
+	}
+	if _, err := c.GetDependencies(t.Context(), "missing", "1.0.0"); err == nil {
+		t.Fatal("GetDependencies() error = nil, want error for missing package")
+	}
+
+	got := stats.Snapshot()["pypi"]
+	if got.Requests != 2 || got.Errors != 1 || got.CacheHits != 1 || got.CacheMisses != 2 {
+		t.Errorf("stats = %+v, want 2 requests, 1 error, 1 cache hit and 2 cache misses", got)
+	}
+}
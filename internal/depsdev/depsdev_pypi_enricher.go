@@ -1,11 +1,17 @@
 package depsdev
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"slices"
 	"strings"
+	"sync/atomic"
 
+	"deps.dev/util/pypi"
 	"github.com/google/osv-scalibr/enricher"
 	"github.com/google/osv-scalibr/extractor"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/python/requirements"
@@ -13,6 +19,9 @@ import (
 	"github.com/google/osv-scalibr/log"
 	"github.com/google/osv-scalibr/plugin"
 	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/distribution"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -20,16 +29,41 @@ const (
 	PyPIDepsDevEnricherName = "transitivedependency/requirements/depsdev"
 )
 
-// PyPIDepsDevEnricher performs dependency resolution for requirements.txt
-// using the deps.dev REST API for pre-computed dependency graphs.
+// PyPIDepsDevEnricher performs dependency resolution for requirements.txt,
+// and the requirements of wheels and source distributions, using the deps.dev
+// API for pre-computed dependency graphs.
 type PyPIDepsDevEnricher struct {
-	client *PyPIDepsDevClient
+	client           DependencyGraphClient
+	registry         *PyPIRegistryClient
+	environment      map[string]string
+	parallelism      int
+	breakerThreshold int
+	conflicts        ConflictStrategy
+	maxDepth         int
+	degradations     *Degradations
+	metrics          Metrics
+	offline          bool
 }
 
-// NewPyPIDepsDevEnricher creates a new enricher that uses deps.dev REST API.
-func NewPyPIDepsDevEnricher(depsDevBaseURL string) (enricher.Enricher, error) {
+// NewPyPIDepsDevEnricher creates a new enricher that uses the deps.dev API
+// over the transport selected in cfg.
+func NewPyPIDepsDevEnricher(cfg Config) (enricher.Enricher, error) {
+	client, err := cfg.pool().Get(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return &PyPIDepsDevEnricher{
-		client: NewPyPIDepsDevClient(depsDevBaseURL),
+		client:           client,
+		registry:         cfg.registry(),
+		environment:      cfg.PythonEnvironment.variables(),
+		parallelism:      cfg.parallelism(),
+		breakerThreshold: cfg.circuitBreakerThreshold(),
+		conflicts:        cfg.ConflictStrategy,
+		maxDepth:         cfg.MaxDepth,
+		degradations:     cfg.Degradations,
+		metrics:          cfg.Metrics,
+		offline:          cfg.SnapshotPath != "",
 	}, nil
 }
 
@@ -45,6 +79,13 @@ func (e *PyPIDepsDevEnricher) Version() int {
 
 // Requirements returns the requirements of the enricher.
 func (e *PyPIDepsDevEnricher) Requirements() *plugin.Capabilities {
+	if e.offline {
+		// Snapshots are read from disk, so the enricher can run in offline scans too.
+		return &plugin.Capabilities{
+			Network: plugin.NetworkAny,
+		}
+	}
+
 	return &plugin.Capabilities{
 		Network: plugin.NetworkOnline,
 	}
@@ -58,26 +99,48 @@ func (e *PyPIDepsDevEnricher) RequiredPlugins() []string {
 // Enrich enriches the inventory from requirements.txt with transitive dependencies
 // fetched from the deps.dev REST API.
 func (e *PyPIDepsDevEnricher) Enrich(ctx context.Context, input *enricher.ScanInput, inv *inventory.Inventory) error {
+	ctx = withMetrics(ctx, e.metrics)
+
+	// Requirements files included with -r are extracted a second time as part
+	// of every file that includes them, so they are resolved together with
+	// that file rather than on their own.
+	included := includedRequirementsFiles(inv.Packages)
+	drop := make(map[*extractor.Package]bool)
+
+	var fsys fs.FS
+	if input != nil && input.ScanRoot != nil {
+		fsys = input.ScanRoot.FS
+	}
+
 	// Group packages by location (requirements.txt path) and plugin name.
 	// This is equivalent to internal.GroupPackagesFromPlugin but inlined to
 	// avoid importing the internal package from osv-scalibr.
 	pkgGroups := make(map[string]map[string]packageWithIndex)
 	for i, pkg := range inv.Packages {
-		if !slices.Contains(pkg.Plugins, requirements.Name) {
+		if !isRequirement(pkg) {
 			continue
 		}
 		if len(pkg.Locations) == 0 {
 			continue
 		}
 		path := pkg.Locations[0]
+		if included[path] || !e.inEnvironment(pkg) {
+			drop[pkg] = true
+			continue
+		}
 		if _, ok := pkgGroups[path]; !ok {
 			pkgGroups[path] = make(map[string]packageWithIndex)
 		}
-		pkgGroups[path][pkg.Name] = packageWithIndex{pkg, i}
+		pkgGroups[path][NormalizePyPIName(pkg.Name)] = packageWithIndex{pkg, i}
 	}
 
 	for path, pkgMap := range pkgGroups {
-		pkgs, err := e.resolveGroup(ctx, path, pkgMap)
+		// Give up on the remaining manifests once cancelled, keeping those already resolved.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		pkgs, err := e.resolveGroup(ctx, path, pkgMap, requirementsConstraints(fsys, path))
 		if err != nil {
 			log.Warnf("deps.dev resolution failed for %s: %v", path, err)
 			continue
@@ -87,8 +150,9 @@ func (e *PyPIDepsDevEnricher) Enrich(ctx context.Context, input *enricher.ScanIn
 		for _, pkg := range pkgs {
 			if indexPkg, ok := pkgMap[pkg.Name]; ok {
 				// This dependency is in the manifest, update version and plugins.
-				inv.Packages[indexPkg.index].Version = pkg.Version
-				inv.Packages[indexPkg.index].Plugins = append(inv.Packages[indexPkg.index].Plugins, PyPIDepsDevEnricherName)
+				declared := inv.Packages[indexPkg.index]
+				setResolvedVersion(declared, pkg.Version)
+				declared.Plugins = append(declared.Plugins, PyPIDepsDevEnricherName)
 			} else {
 				// Transitive dependency not in the manifest.
 				inv.Packages = append(inv.Packages, pkg)
@@ -96,62 +160,587 @@ func (e *PyPIDepsDevEnricher) Enrich(ctx context.Context, input *enricher.ScanIn
 		}
 	}
 
+	attributeIncludedPackages(inv.Packages, drop)
+	inv.Packages = slices.DeleteFunc(inv.Packages, func(pkg *extractor.Package) bool {
+		return drop[pkg]
+	})
+
 	return nil
 }
 
+// isRequirement reports whether a package is declared in a requirements file,
+// or as a requirement of a wheel or source distribution.
+func isRequirement(pkg *extractor.Package) bool {
+	return slices.Contains(pkg.Plugins, requirements.Name) || slices.Contains(pkg.Plugins, distribution.Name)
+}
+
+// includedRequirementsFiles returns the requirements files that are included
+// by another requirements file. The extractor locates packages from an
+// included file at both the file that was scanned and the included file.
+func includedRequirementsFiles(pkgs []*extractor.Package) map[string]bool {
+	included := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if !isRequirement(pkg) {
+			continue
+		}
+		for _, loc := range pkg.Locations[min(1, len(pkg.Locations)):] {
+			included[loc] = true
+		}
+	}
+
+	return included
+}
+
+// attributeIncludedPackages attributes packages declared in an included
+// requirements file to that file rather than the file including it. A file
+// included from several places is only reported once, so the duplicates are
+// added to drop.
+func attributeIncludedPackages(pkgs []*extractor.Package, drop map[*extractor.Package]bool) {
+	type declaration struct{ path, name, version string }
+	seen := make(map[declaration]bool)
+	for _, pkg := range pkgs {
+		if drop[pkg] || len(pkg.Locations) < 2 || !isRequirement(pkg) {
+			continue
+		}
+		pkg.Locations = pkg.Locations[len(pkg.Locations)-1:]
+
+		key := declaration{pkg.Locations[0], NormalizePyPIName(pkg.Name), pkg.Version}
+		if seen[key] {
+			drop[pkg] = true
+		}
+		seen[key] = true
+	}
+}
+
+// setResolvedVersion updates a package declared in the manifest to the version
+// it was resolved to, recording what was declared if that differs.
+func setResolvedVersion(pkg *extractor.Package, version string) {
+	if pkg.Version == version {
+		return
+	}
+
+	if _, ok := pkg.Metadata.(*Metadata); !ok {
+		declared := &Declaration{Version: pkg.Version}
+		if md, ok := pkg.Metadata.(*requirements.Metadata); ok {
+			declared.Requirement = md.Requirement
+		}
+		// Any hashes in the manifest's metadata are for the declared version,
+		// so the metadata is replaced rather than kept alongside.
+		pkg.Metadata = &Metadata{Declared: declared}
+	}
+	pkg.Version = version
+}
+
 // packageWithIndex tracks a package along with its index in the inventory slice.
 type packageWithIndex struct {
 	pkg   *extractor.Package
 	index int
 }
 
-// resolveGroup resolves transitive dependencies for all packages in a single requirements.txt.
-func (e *PyPIDepsDevEnricher) resolveGroup(ctx context.Context, path string, pkgMap map[string]packageWithIndex) ([]*extractor.Package, error) {
-	// Collect all transitive packages, deduplicating by name+version
-	seen := make(map[string]bool)
-	var result []*extractor.Package
+// resolveGroup resolves transitive dependencies for all packages in a single requirements.txt,
+// including those declared in the files it includes, within the constraints of the files
+// it gives with -c.
+// Dependency graphs are fetched concurrently, bounded by the enricher's parallelism,
+// and merged in a deterministic order once all lookups have completed.
+// If deps.dev keeps failing, the remaining lookups are skipped and the
+// manifest is recorded as degraded.
+func (e *PyPIDepsDevEnricher) resolveGroup(ctx context.Context, path string, pkgMap map[string]packageWithIndex, constraints map[string]string) ([]*extractor.Package, error) {
+	e.pinVersions(ctx, pkgMap, constraints)
 
+	var roots []*extractor.Package
 	for _, indexPkg := range pkgMap {
-		pkg := indexPkg.pkg
-		if pkg.Version == "" {
+		if indexPkg.pkg.Version == "" {
 			// Cannot look up packages without a pinned version
+			e.degradations.RecordUnresolved(path, indexPkg.pkg.Name, "", "no version could be determined from the requirement")
 			continue
 		}
+		roots = append(roots, indexPkg.pkg)
+	}
+	slices.SortFunc(roots, func(a, b *extractor.Package) int {
+		return strings.Compare(a.Name, b.Name)
+	})
 
-		graph, err := e.client.GetDependencies(ctx, pkg.Name, pkg.Version)
-		if err != nil {
-			log.Warnf("deps.dev: failed to get dependencies for %s@%s: %v", pkg.Name, pkg.Version, err)
+	graphs := make([]*DepsDevDependencyGraph, len(roots))
+	errs := make([]error, len(roots))
+	logs := make([]lookupLog, len(roots))
+	breaker := newCircuitBreaker(e.breakerThreshold)
+	var skipped atomic.Int32
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(e.parallelism)
+	for i, pkg := range roots {
+		g.Go(func() error {
+			if breaker.open() {
+				skipped.Add(1)
+				errs[i] = ErrCircuitOpen
+
+				return nil
+			}
+			graph, err := e.client.GetDependencies(gctx, pkg.Name, pkg.Version)
+			breaker.record(err)
+			if errors.Is(err, ErrNotFound) && e.registry != nil {
+				// deps.dev may lag behind new releases, or not know about the
+				// package at all, so fall back to what PyPI says it depends on.
+				logs[i].Infof("deps.dev: %s@%s not found, resolving its dependencies from PyPI", pkg.Name, pkg.Version)
+				graph, err = e.registryGraph(gctx, &logs[i], path, pkg.Name, pkg.Version)
+			}
+			if err != nil {
+				errs[i] = err
+				return nil
+			}
+			graphs[i] = e.withExtras(gctx, &logs[i], pkg, graph)
+
+			return nil
+		})
+	}
+	// Lookup failures are kept rather than returned, so Wait never reports
+	// an error.
+	_ = g.Wait()
+
+	// Loggers can be tied to the goroutine running the enricher, so what
+	// the lookups found is only logged once they are all done.
+	for i, pkg := range roots {
+		logs[i].flush()
+		switch {
+		case errors.Is(errs[i], ErrCircuitOpen):
+			e.degradations.RecordUnresolved(path, pkg.Name, pkg.Version, ErrCircuitOpen.Error())
+		case errs[i] != nil:
+			log.Warnf("deps.dev: failed to get dependencies for %s@%s: %v", pkg.Name, pkg.Version, errs[i])
+			e.degradations.RecordUnresolved(path, pkg.Name, pkg.Version, errs[i].Error())
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if n := skipped.Load(); n > 0 {
+		log.Warnf("deps.dev: skipped %d lookups for %s: %v", n, path, ErrCircuitOpen)
+		e.degradations.Record(path, fmt.Sprintf("%v (%d of %d packages skipped): %v", ErrCircuitOpen, n, len(roots), breaker.cause()))
+	}
+
+	// Collect all transitive packages, deduplicating by name+version
+	byKey := make(map[string]*extractor.Package)
+	var result []*extractor.Package
+
+	for _, graph := range graphs {
+		if graph == nil {
 			continue
 		}
 
 		for _, node := range graph.Nodes {
+			for _, nodeErr := range node.Errors {
+				// deps.dev could not fully resolve the graph at this node
+				e.degradations.Record(path, fmt.Sprintf("deps.dev reported an error resolving %s: %s", nodeKey(node), nodeErr))
+			}
+
 			// Skip the SELF node
 			if node.Relation == "SELF" {
 				continue
 			}
 
-			// Normalize name to lowercase (PyPI is case-insensitive)
-			name := strings.ToLower(node.VersionKey.Name)
-			key := name + "@" + node.VersionKey.Version
+			key := nodeKey(node)
+			if pkg, ok := byKey[key]; ok {
+				md := pkg.Metadata.(*Metadata)
+				md.Bundled = md.Bundled && node.Bundled
 
-			if seen[key] {
 				continue
 			}
-			seen[key] = true
 
-			result = append(result, &extractor.Package{
-				Name:      name,
+			pkg := &extractor.Package{
+				Name:      pypiName(node),
 				Version:   node.VersionKey.Version,
 				PURLType:  purl.TypePyPi,
 				Locations: []string{path},
 				Plugins:   []string{PyPIDepsDevEnricherName},
+				Metadata: &Metadata{
+					IsTransitive: !isDeclared(pkgMap, node),
+					Bundled:      node.Bundled,
+				},
+			}
+			byKey[key] = pkg
+			result = append(result, pkg)
+		}
+
+		for i, chain := range introductionChains(graph, nodeKey) {
+			pkg, ok := byKey[nodeKey(graph.Nodes[i])]
+			if !ok || chain == nil {
+				continue
+			}
+			md := pkg.Metadata.(*Metadata)
+			md.IntroducedBy = append(md.IntroducedBy, chain)
+		}
+
+		for _, edge := range graph.Edges {
+			if !validEdge(graph, edge) {
+				continue
+			}
+			child, ok := byKey[nodeKey(graph.Nodes[edge.ToNode])]
+			if !ok {
+				// Edges into the SELF node
+				continue
+			}
+
+			from := graph.Nodes[edge.FromNode]
+			md := child.Metadata.(*Metadata)
+			md.Parents = append(md.Parents, Parent{
+				Name:        pypiName(from),
+				Version:     from.VersionKey.Version,
+				Requirement: edge.Requirement,
 			})
 		}
 	}
 
+	for _, pkg := range result {
+		md := pkg.Metadata.(*Metadata)
+		slices.SortFunc(md.Parents, compareParents)
+		md.Parents = slices.Compact(md.Parents)
+		slices.SortFunc(md.IntroducedBy, slices.Compare)
+		md.IntroducedBy = slices.CompactFunc(md.IntroducedBy, slices.Equal)
+	}
+
+	if e.maxDepth > 0 {
+		// MaxDepth counts the packages declared in the manifest as depth 1,
+		// where depth counts them as depth 0.
+		result = slices.DeleteFunc(result, func(pkg *extractor.Package) bool {
+			return depth(pkg) >= e.maxDepth
+		})
+	}
+	result = e.conflicts.resolveConflicts(result, "PyPI")
+
 	if len(result) == 0 && len(pkgMap) > 0 {
-		return nil, fmt.Errorf("no dependencies resolved from deps.dev")
+		return nil, errors.New("no dependencies resolved from deps.dev")
 	}
 
 	return result, nil
 }
+
+// pinVersions sets the version of the packages in pkgMap that are not pinned to
+// a version to the highest release on PyPI satisfying their requirement and
+// any constraint on them, so that their dependencies can be looked up on
+// deps.dev. constraints is keyed by the same normalized names as pkgMap.
+func (e *PyPIDepsDevEnricher) pinVersions(ctx context.Context, pkgMap map[string]packageWithIndex, constraints map[string]string) {
+	if e.registry == nil {
+		return
+	}
+
+	names := slices.Sorted(maps.Keys(pkgMap))
+	logs := make([]lookupLog, len(names))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(e.parallelism)
+	for i, name := range names {
+		pkg := pkgMap[name].pkg
+		constraint, ok := unpinnedConstraint(pkg)
+		if !ok {
+			continue
+		}
+		constraint = joinConstraints(constraint, constraints[name])
+		g.Go(func() error {
+			if version, ok := e.bestRelease(gctx, &logs[i], pkg.Name, constraint); ok {
+				setResolvedVersion(pkg, version)
+			}
+
+			return nil
+		})
+	}
+	// Failures are logged and the package is left as declared, so Wait
+	// never reports an error.
+	_ = g.Wait()
+
+	for _, l := range logs {
+		l.flush()
+	}
+}
+
+// bestRelease returns the highest release of a package on PyPI satisfying
+// constraint, adding why to logs if there is none.
+func (e *PyPIDepsDevEnricher) bestRelease(ctx context.Context, logs *lookupLog, name, constraint string) (string, bool) {
+	releases, err := e.registry.Releases(ctx, name)
+	if err != nil {
+		logs.Warnf("PyPI: failed to list releases of %s: %v", name, err)
+		return "", false
+	}
+	version, err := bestVersion(releases, constraint)
+	if err != nil {
+		logs.Warnf("PyPI: failed to parse the requirement of %s %q: %v", name, constraint, err)
+		return "", false
+	}
+	if version == "" {
+		logs.Warnf("PyPI: no release of %s satisfies %q", name, constraint)
+		return "", false
+	}
+
+	return version, true
+}
+
+// lookupLog holds the messages of a lookup run in its own goroutine, to be
+// logged by flush from the goroutine that started it.
+type lookupLog []lookupMessage
+
+type lookupMessage struct {
+	warning bool
+	text    string
+}
+
+func (l *lookupLog) Infof(format string, args ...any) {
+	*l = append(*l, lookupMessage{text: fmt.Sprintf(format, args...)})
+}
+
+func (l *lookupLog) Warnf(format string, args ...any) {
+	*l = append(*l, lookupMessage{warning: true, text: fmt.Sprintf(format, args...)})
+}
+
+// flush logs the messages, in the order they were added.
+func (l lookupLog) flush() {
+	for _, m := range l {
+		if m.warning {
+			log.Warn(m.text)
+		} else {
+			log.Info(m.text)
+		}
+	}
+}
+
+// registryGraph builds the dependency graph of a package version deps.dev does
+// not know about from the requirements it declares on PyPI. Each requirement
+// is resolved to its highest matching release, whose own dependencies are
+// taken from deps.dev.
+func (e *PyPIDepsDevEnricher) registryGraph(ctx context.Context, logs *lookupLog, path, name, version string) (*DepsDevDependencyGraph, error) {
+	requiresDist, err := e.registry.RequiresDist(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &DepsDevDependencyGraph{
+		Nodes: []DepsDevNode{{
+			VersionKey: DepsDevVersionKey{System: "PYPI", Name: name, Version: version},
+			Relation:   "SELF",
+		}},
+	}
+	for _, requirement := range requiresDist {
+		dep, err := pypi.ParseDependency(requirement)
+		if err != nil {
+			continue
+		}
+		if dep.Environment != "" {
+			m, err := parseMarker(dep.Environment)
+			if err != nil || !m.eval(e.environment, nil) {
+				continue
+			}
+		}
+
+		depVersion, ok := e.bestRelease(ctx, logs, dep.Name, dep.Constraint)
+		if !ok {
+			continue
+		}
+		depGraph, err := e.client.GetDependencies(ctx, dep.Name, depVersion)
+		if err != nil {
+			logs.Warnf("deps.dev: failed to get dependencies for %s@%s: %v", dep.Name, depVersion, err)
+			e.degradations.Record(path, fmt.Sprintf("dependencies of %s@%s could not be resolved: %v", dep.Name, depVersion, err))
+			depGraph = &DepsDevDependencyGraph{
+				Nodes: []DepsDevNode{{
+					VersionKey: DepsDevVersionKey{System: "PYPI", Name: dep.Name, Version: depVersion},
+					Relation:   "SELF",
+				}},
+			}
+		}
+		graph = mergeDependencyGraph(graph, depGraph, dep.Constraint)
+	}
+
+	return graph, nil
+}
+
+// inEnvironment reports whether the environment marker of a package declared
+// in a requirements file, if any, holds in the target environment.
+func (e *PyPIDepsDevEnricher) inEnvironment(pkg *extractor.Package) bool {
+	dep, err := pypi.ParseDependency(declaredRequirement(pkg))
+	if err != nil || dep.Environment == "" {
+		return true
+	}
+	m, err := parseMarker(dep.Environment)
+	if err != nil {
+		log.Warnf("%s: %v", pkg.Name, err)
+		return true
+	}
+
+	return m.eval(e.environment, nil)
+}
+
+// withExtras adds the dependencies that a package only has when some of its
+// extras are requested, as declared in the manifest, to its dependency graph.
+// The graph deps.dev returns is shared through its cache, so a copy is
+// returned rather than modifying it.
+func (e *PyPIDepsDevEnricher) withExtras(ctx context.Context, logs *lookupLog, pkg *extractor.Package, graph *DepsDevDependencyGraph) *DepsDevDependencyGraph {
+	extras := requestedExtras(pkg)
+	if len(extras) == 0 || e.registry == nil {
+		return graph
+	}
+
+	requiresDist, err := e.registry.RequiresDist(ctx, pkg.Name, pkg.Version)
+	if err != nil {
+		logs.Warnf("PyPI: failed to get the requirements of %s@%s: %v", pkg.Name, pkg.Version, err)
+		return graph
+	}
+
+	for _, requirement := range requiresDist {
+		dep, err := pypi.ParseDependency(requirement)
+		if err != nil || dep.Environment == "" {
+			continue
+		}
+		m, err := parseMarker(dep.Environment)
+		// Requirements that hold without any extra are already in the graph.
+		if err != nil || !m.eval(e.environment, extras) || m.eval(e.environment, nil) {
+			continue
+		}
+
+		version, ok := e.bestRelease(ctx, logs, dep.Name, dep.Constraint)
+		if !ok {
+			continue
+		}
+		extraGraph, err := e.client.GetDependencies(ctx, dep.Name, version)
+		if err != nil {
+			logs.Warnf("deps.dev: failed to get dependencies for %s@%s: %v", dep.Name, version, err)
+			continue
+		}
+		graph = mergeDependencyGraph(graph, extraGraph, dep.Constraint)
+	}
+
+	return graph
+}
+
+// mergeDependencyGraph returns a copy of graph with dep, along with its own
+// dependencies, added as a direct dependency of the SELF node of graph.
+func mergeDependencyGraph(graph, dep *DepsDevDependencyGraph, requirement string) *DepsDevDependencyGraph {
+	self := slices.IndexFunc(graph.Nodes, func(n DepsDevNode) bool { return n.Relation == "SELF" })
+	depSelf := slices.IndexFunc(dep.Nodes, func(n DepsDevNode) bool { return n.Relation == "SELF" })
+	if self < 0 || depSelf < 0 {
+		return graph
+	}
+
+	offset := len(graph.Nodes)
+	merged := &DepsDevDependencyGraph{
+		Nodes: slices.Concat(graph.Nodes, dep.Nodes),
+		Edges: slices.Clone(graph.Edges),
+	}
+	merged.Nodes[offset+depSelf].Relation = "DIRECT"
+	for _, edge := range dep.Edges {
+		edge.FromNode += offset
+		edge.ToNode += offset
+		merged.Edges = append(merged.Edges, edge)
+	}
+	merged.Edges = append(merged.Edges, DepsDevEdge{
+		FromNode:    self,
+		ToNode:      offset + depSelf,
+		Requirement: requirement,
+	})
+
+	return merged
+}
+
+// declaredRequirement returns the requirement a package was declared with in
+// a requirements file, e.g. `requests[security]>=2; python_version < "3.10"`.
+func declaredRequirement(pkg *extractor.Package) string {
+	switch md := pkg.Metadata.(type) {
+	case *requirements.Metadata:
+		return md.Requirement
+	case *Metadata:
+		if md.Declared != nil {
+			return md.Declared.Requirement
+		}
+	}
+
+	return ""
+}
+
+// requestedExtras returns the normalized names of the extras requested for a
+// package declared in a requirements file.
+func requestedExtras(pkg *extractor.Package) map[string]bool {
+	dep, err := pypi.ParseDependency(declaredRequirement(pkg))
+	if err != nil || dep.Extras == "" {
+		return nil
+	}
+
+	extras := make(map[string]bool)
+	for extra := range strings.SplitSeq(dep.Extras, ",") {
+		if extra = strings.TrimSpace(extra); extra != "" {
+			extras[NormalizePyPIName(extra)] = true
+		}
+	}
+
+	return extras
+}
+
+// isDeclared reports whether node is one of the packages declared in the manifest.
+func isDeclared(pkgMap map[string]packageWithIndex, node DepsDevNode) bool {
+	_, ok := pkgMap[pypiName(node)]
+	return ok
+}
+
+// pypiName returns the normalized name of a PyPI package node.
+func pypiName(node DepsDevNode) string {
+	return NormalizePyPIName(node.VersionKey.Name)
+}
+
+// NormalizePyPIName normalizes a PyPI project name as described in PEP 503,
+// so that e.g. "Foo_Bar", "foo-bar" and "foo.bar" are the same project.
+func NormalizePyPIName(name string) string {
+	return strings.ToLower(cachedregexp.MustCompile(`[-_.]+`).ReplaceAllLiteralString(name, "-"))
+}
+
+// nodeKey identifies a package version across graphs.
+func nodeKey(node DepsDevNode) string {
+	return pypiName(node) + "@" + node.VersionKey.Version
+}
+
+// validEdge reports whether both ends of edge are nodes of graph.
+func validEdge(graph *DepsDevDependencyGraph, edge DepsDevEdge) bool {
+	return edge.FromNode >= 0 && edge.FromNode < len(graph.Nodes) &&
+		edge.ToNode >= 0 && edge.ToNode < len(graph.Nodes)
+}
+
+// introductionChains returns, for each node of graph, the chain of packages on
+// the shortest path from the SELF node to the node's parent, each given by
+// label. Nodes that are not reachable from the SELF node have a nil chain.
+func introductionChains(graph *DepsDevDependencyGraph, label func(DepsDevNode) string) [][]string {
+	children := make([][]int, len(graph.Nodes))
+	for _, edge := range graph.Edges {
+		if validEdge(graph, edge) {
+			children[edge.FromNode] = append(children[edge.FromNode], edge.ToNode)
+		}
+	}
+
+	chains := make([][]string, len(graph.Nodes))
+	visited := make([]bool, len(graph.Nodes))
+	var queue []int
+	for i, node := range graph.Nodes {
+		if node.Relation == "SELF" {
+			visited[i] = true
+			queue = append(queue, i)
+		}
+	}
+
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+
+		// The chain leading to a child is the one leading to its parent, plus the parent itself.
+		chain := append(slices.Clip(chains[from]), label(graph.Nodes[from]))
+		for _, to := range children[from] {
+			if visited[to] {
+				continue
+			}
+			visited[to] = true
+			chains[to] = chain
+			queue = append(queue, to)
+		}
+	}
+
+	return chains
+}
+
+func compareParents(a, b Parent) int {
+	return cmp.Or(
+		strings.Compare(a.Name, b.Name),
+		strings.Compare(a.Version, b.Version),
+		strings.Compare(a.Requirement, b.Requirement),
+	)
+}
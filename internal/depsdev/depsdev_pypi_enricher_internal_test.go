@@ -0,0 +1,70 @@
+package depsdev
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_introductionChains(t *testing.T) {
+	t.Parallel()
+
+	node := func(name, relation string) DepsDevNode {
+		return DepsDevNode{
+			VersionKey: DepsDevVersionKey{System: "PYPI", Name: name, Version: "1.0"},
+			Relation:   relation,
+		}
+	}
+
+	// root -> a -> b -> c, root -> b, with d unreachable
+	graph := &DepsDevDependencyGraph{
+		Nodes: []DepsDevNode{
+			node("Root", "SELF"),
+			node("a", "DIRECT"),
+			node("b", "DIRECT"),
+			node("c", "INDIRECT"),
+			node("d", "INDIRECT"),
+		},
+		Edges: []DepsDevEdge{
+			{FromNode: 0, ToNode: 1},
+			{FromNode: 1, ToNode: 2},
+			{FromNode: 0, ToNode: 2},
+			{FromNode: 2, ToNode: 3},
+			{FromNode: 3, ToNode: 99},
+		},
+	}
+
+	want := [][]string{
+		nil,
+		{"root@1.0"},
+		{"root@1.0"},
+		{"root@1.0", "b@1.0"},
+		nil,
+	}
+	if diff := cmp.Diff(want, introductionChains(graph, nodeKey)); diff != "" {
+		t.Errorf("introductionChains() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_normalizePyPIName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "requests", want: "requests"},
+		{name: "Flask_SQLAlchemy", want: "flask-sqlalchemy"},
+		{name: "zope.interface", want: "zope-interface"},
+		{name: "Foo-_.Bar", want: "foo-bar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := NormalizePyPIName(tt.name); got != tt.want {
+				t.Errorf("NormalizePyPIName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,660 @@
+package depsdev_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/enricher"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/python/requirements"
+	scalibrfs "github.com/google/osv-scalibr/fs"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+)
+
+// newFakeDepsDevServer returns a server that answers every :dependencies
+// request with a graph containing the requested package as SELF and a single
+// "shared" dependency, recording the peak number of in-flight requests.
+func newFakeDepsDevServer(t *testing.T, peak *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		inFlight int32
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > peak.Load() {
+			peak.Store(inFlight)
+		}
+		mu.Unlock()
+
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		// Give other workers a chance to start so concurrency is observable.
+		time.Sleep(20 * time.Millisecond)
+
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v3/systems/pypi/packages/"), "/")
+		name := parts[0]
+
+		graph := depsdev.DepsDevDependencyGraph{
+			Nodes: []depsdev.DepsDevNode{
+				{VersionKey: depsdev.DepsDevVersionKey{System: "PYPI", Name: name, Version: "1.0.0"}, Relation: "SELF"},
+				{VersionKey: depsdev.DepsDevVersionKey{System: "PYPI", Name: "Shared", Version: "2.0.0"}, Relation: "DIRECT"},
+			},
+			Edges: []depsdev.DepsDevEdge{{FromNode: 0, ToNode: 1, Requirement: ">=2"}},
+		}
+		_ = json.NewEncoder(w).Encode(graph)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestPyPIDepsDevEnricher_Enrich_Parallelism(t *testing.T) {
+	t.Parallel()
+
+	var peak atomic.Int32
+	srv := newFakeDepsDevServer(t, &peak)
+
+	enr, err := depsdev.NewPyPIDepsDevEnricher(depsdev.Config{
+		BaseURL:     srv.URL,
+		Parallelism: 3,
+	})
+	if err != nil {
+		t.Fatalf("NewPyPIDepsDevEnricher() error: %v", err)
+	}
+
+	inv := &inventory.Inventory{}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		inv.Packages = append(inv.Packages, &extractor.Package{
+			Name:      name,
+			Version:   "1.0.0",
+			Locations: []string{"requirements.txt"},
+			Plugins:   []string{requirements.Name},
+		})
+	}
+
+	if err := enr.Enrich(t.Context(), nil, inv); err != nil {
+		t.Fatalf("Enrich() error: %v", err)
+	}
+
+	if got := peak.Load(); got > 3 {
+		t.Errorf("peak concurrent requests = %d, want <= 3", got)
+	}
+	if got := peak.Load(); got < 2 {
+		t.Errorf("peak concurrent requests = %d, want requests to run concurrently", got)
+	}
+
+	// 8 direct packages, plus the deduplicated "shared" transitive dependency.
+	if got, want := len(inv.Packages), 9; got != want {
+		t.Fatalf("len(inv.Packages) = %d, want %d", got, want)
+	}
+	if got := inv.Packages[8].Name; got != "shared" {
+		t.Errorf("inv.Packages[8].Name = %q, want %q", got, "shared")
+	}
+}
+
+func TestPyPIDepsDevEnricher_Enrich_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	degradations := &depsdev.Degradations{}
+	enr, err := depsdev.NewPyPIDepsDevEnricher(depsdev.Config{
+		BaseURL:                 srv.URL,
+		Pool:                    depsdev.NewClientPool(),
+		Parallelism:             1,
+		CircuitBreakerThreshold: 2,
+		Degradations:            degradations,
+	})
+	if err != nil {
+		t.Fatalf("NewPyPIDepsDevEnricher() error: %v", err)
+	}
+
+	inv := &inventory.Inventory{}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		inv.Packages = append(inv.Packages, &extractor.Package{
+			Name:      name,
+			Version:   "1.0.0",
+			Locations: []string{"requirements.txt"},
+			Plugins:   []string{requirements.Name},
+		})
+	}
+
+	if err := enr.Enrich(t.Context(), nil, inv); err != nil {
+		t.Fatalf("Enrich() error: %v", err)
+	}
+
+	if got, want := requests.Load(), int32(2); got != want {
+		t.Errorf("requests = %d, want %d", got, want)
+	}
+
+	got := degradations.List()
+	if len(got) != 1 {
+		t.Fatalf("len(degradations) = %d, want 1", len(got))
+	}
+	if got[0].Path != "requirements.txt" {
+		t.Errorf("degradations[0].Path = %q, want %q", got[0].Path, "requirements.txt")
+	}
+	if !strings.Contains(got[0].Reason, "3 of 5 packages skipped") {
+		t.Errorf("degradations[0].Reason = %q, want it to mention the skipped packages", got[0].Reason)
+	}
+
+	// Every package is reported as unresolved, whether its lookup failed or was skipped.
+	unresolved := degradations.Unresolved()
+	var names []string
+	for _, u := range unresolved {
+		names = append(names, u.Name)
+	}
+	if diff := cmp.Diff([]string{"a", "b", "c", "d", "e"}, names); diff != "" {
+		t.Errorf("unresolved packages mismatch (-want +got):\n%s", diff)
+	}
+	if len(unresolved) > 0 && !strings.Contains(unresolved[0].Reason, "503") {
+		t.Errorf("unresolved[0].Reason = %q, want the lookup error", unresolved[0].Reason)
+	}
+}
+
+func TestPyPIDepsDevEnricher_Enrich_Edges(t *testing.T) {
+	t.Parallel()
+
+	var peak atomic.Int32
+	srv := newFakeDepsDevServer(t, &peak)
+
+	enr, err := depsdev.NewPyPIDepsDevEnricher(depsdev.Config{
+		BaseURL: srv.URL,
+		Pool:    depsdev.NewClientPool(),
+	})
+	if err != nil {
+		t.Fatalf("NewPyPIDepsDevEnricher() error: %v", err)
+	}
+
+	inv := &inventory.Inventory{}
+	for _, name := range []string{"b", "a"} {
+		inv.Packages = append(inv.Packages, &extractor.Package{
+			Name:      name,
+			Version:   "1.0.0",
+			Locations: []string{"requirements.txt"},
+			Plugins:   []string{requirements.Name},
+		})
+	}
+
+	if err := enr.Enrich(t.Context(), nil, inv); err != nil {
+		t.Fatalf("Enrich() error: %v", err)
+	}
+
+	if got, want := len(inv.Packages), 3; got != want {
+		t.Fatalf("len(inv.Packages) = %d, want %d", got, want)
+	}
+
+	want := &depsdev.Metadata{
+		Parents: []depsdev.Parent{
+			{Name: "a", Version: "1.0.0", Requirement: ">=2"},
+			{Name: "b", Version: "1.0.0", Requirement: ">=2"},
+		},
+		IsTransitive: true,
+		IntroducedBy: [][]string{{"a@1.0.0"}, {"b@1.0.0"}},
+	}
+	if diff := cmp.Diff(want, inv.Packages[2].Metadata); diff != "" {
+		t.Errorf("inv.Packages[2].Metadata mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPyPIDepsDevEnricher_Enrich_NodeErrors(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		graph := depsdev.DepsDevDependencyGraph{
+			Nodes: []depsdev.DepsDevNode{
+				{VersionKey: depsdev.DepsDevVersionKey{System: "PYPI", Name: "a", Version: "1.0.0"}, Relation: "SELF"},
+				{
+					VersionKey: depsdev.DepsDevVersionKey{System: "PYPI", Name: "b", Version: "2.0.0"},
+					Relation:   "DIRECT",
+					Errors:     []string{"could not resolve requirement c>=3"},
+				},
+			},
+			Edges: []depsdev.DepsDevEdge{{FromNode: 0, ToNode: 1, Requirement: ">=2"}},
+		}
+		_ = json.NewEncoder(w).Encode(graph)
+	}))
+	t.Cleanup(srv.Close)
+
+	degradations := &depsdev.Degradations{}
+	enr, err := depsdev.NewPyPIDepsDevEnricher(depsdev.Config{
+		BaseURL:      srv.URL,
+		Pool:         depsdev.NewClientPool(),
+		Degradations: degradations,
+	})
+	if err != nil {
+		t.Fatalf("NewPyPIDepsDevEnricher() error: %v", err)
+	}
+
+	inv := &inventory.Inventory{
+		Packages: []*extractor.Package{{
+			Name:      "a",
+			Version:   "1.0.0",
+			Locations: []string{"requirements.txt"},
+			Plugins:   []string{requirements.Name},
+		}},
+	}
+	if err := enr.Enrich(t.Context(), nil, inv); err != nil {
+		t.Fatalf("Enrich() error: %v", err)
+	}
+
+	want := []depsdev.Degradation{{
+		Path:   "requirements.txt",
+		Reason: "deps.dev reported an error resolving b@2.0.0: could not resolve requirement c>=3",
+	}}
+	if diff := cmp.Diff(want, degradations.List()); diff != "" {
+		t.Errorf("degradations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPyPIDepsDevEnricher_Enrich_DeclaredVersion(t *testing.T) {
+	t.Parallel()
+
+	var peak atomic.Int32
+	srv := newFakeDepsDevServer(t, &peak)
+
+	enr, err := depsdev.NewPyPIDepsDevEnricher(depsdev.Config{
+		BaseURL: srv.URL,
+		Pool:    depsdev.NewClientPool(),
+	})
+	if err != nil {
+		t.Fatalf("NewPyPIDepsDevEnricher() error: %v", err)
+	}
+
+	inv := &inventory.Inventory{
+		Packages: []*extractor.Package{
+			{
+				Name:      "a",
+				Version:   "1.0.0",
+				Locations: []string{"requirements.txt"},
+				Plugins:   []string{requirements.Name},
+				Metadata:  &requirements.Metadata{Requirement: "a==1.0.0", VersionComparator: "=="},
+			},
+			{
+				Name:      "shared",
+				Version:   "1.0.0",
+				Locations: []string{"requirements.txt"},
+				Plugins:   []string{requirements.Name},
+				Metadata:  &requirements.Metadata{Requirement: "shared==1.0.0", VersionComparator: "=="},
+			},
+		},
+	}
+
+	if err := enr.Enrich(t.Context(), nil, inv); err != nil {
+		t.Fatalf("Enrich() error: %v", err)
+	}
+
+	if got, want := len(inv.Packages), 2; got != want {
+		t.Fatalf("len(inv.Packages) = %d, want %d", got, want)
+	}
+
+	shared := inv.Packages[1]
+	if shared.Version != "2.0.0" {
+		t.Errorf("shared.Version = %q, want the resolved version %q", shared.Version, "2.0.0")
+	}
+	want := &depsdev.Declaration{Version: "1.0.0", Requirement: "shared==1.0.0"}
+	md, ok := shared.Metadata.(*depsdev.Metadata)
+	if !ok {
+		t.Fatalf("shared.Metadata = %T, want *depsdev.Metadata", shared.Metadata)
+	}
+	if diff := cmp.Diff(want, md.Declared); diff != "" {
+		t.Errorf("shared declaration mismatch (-want +got):\n%s", diff)
+	}
+
+	// The version of "a" was not changed, so what was declared is kept as is.
+	if _, ok := inv.Packages[0].Metadata.(*requirements.Metadata); !ok {
+		t.Errorf("a.Metadata = %T, want it to be unchanged", inv.Packages[0].Metadata)
+	}
+}
+
+func TestPyPIDepsDevEnricher_Enrich_MaxDepth(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		node := func(name, relation string) depsdev.DepsDevNode {
+			return depsdev.DepsDevNode{
+				VersionKey: depsdev.DepsDevVersionKey{System: "PYPI", Name: name, Version: "1.0.0"},
+				Relation:   relation,
+			}
+		}
+		// root -> a -> b -> c
+		graph := depsdev.DepsDevDependencyGraph{
+			Nodes: []depsdev.DepsDevNode{node("root", "SELF"), node("a", "DIRECT"), node("b", "INDIRECT"), node("c", "INDIRECT")},
+			Edges: []depsdev.DepsDevEdge{{FromNode: 0, ToNode: 1}, {FromNode: 1, ToNode: 2}, {FromNode: 2, ToNode: 3}},
+		}
+		_ = json.NewEncoder(w).Encode(graph)
+	}))
+	t.Cleanup(srv.Close)
+
+	enr, err := depsdev.NewPyPIDepsDevEnricher(depsdev.Config{
+		BaseURL:  srv.URL,
+		Pool:     depsdev.NewClientPool(),
+		MaxDepth: 2,
+	})
+	if err != nil {
+		t.Fatalf("NewPyPIDepsDevEnricher() error: %v", err)
+	}
+
+	inv := &inventory.Inventory{
+		Packages: []*extractor.Package{{
+			Name:      "root",
+			Version:   "1.0.0",
+			Locations: []string{"requirements.txt"},
+			Plugins:   []string{requirements.Name},
+		}},
+	}
+	if err := enr.Enrich(t.Context(), nil, inv); err != nil {
+		t.Fatalf("Enrich() error: %v", err)
+	}
+
+	var got []string
+	for _, pkg := range inv.Packages {
+		got = append(got, pkg.Name)
+	}
+	if diff := cmp.Diff([]string{"root", "a"}, got); diff != "" {
+		t.Errorf("inventory packages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPyPIDepsDevEnricher_Enrich_Includes(t *testing.T) {
+	t.Parallel()
+
+	var peak atomic.Int32
+	srv := newFakeDepsDevServer(t, &peak)
+
+	enr, err := depsdev.NewPyPIDepsDevEnricher(depsdev.Config{
+		BaseURL: srv.URL,
+		Pool:    depsdev.NewClientPool(),
+	})
+	if err != nil {
+		t.Fatalf("NewPyPIDepsDevEnricher() error: %v", err)
+	}
+
+	// requirements.txt declares "a" and includes base.txt with -r, which is
+	// also scanned on its own.
+	pkg := func(name string, locations ...string) *extractor.Package {
+		return &extractor.Package{
+			Name:      name,
+			Version:   "1.0.0",
+			Locations: locations,
+			Plugins:   []string{requirements.Name},
+		}
+	}
+	inv := &inventory.Inventory{
+		Packages: []*extractor.Package{
+			pkg("a", "requirements.txt"),
+			pkg("b", "requirements.txt", "base.txt"),
+			pkg("b", "base.txt"),
+		},
+	}
+	if err := enr.Enrich(t.Context(), nil, inv); err != nil {
+		t.Fatalf("Enrich() error: %v", err)
+	}
+
+	got := make([]string, 0, len(inv.Packages))
+	for _, pkg := range inv.Packages {
+		got = append(got, pkg.Name+" "+strings.Join(pkg.Locations, ","))
+	}
+	want := []string{"a requirements.txt", "b base.txt", "shared requirements.txt"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("inventory packages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPyPIDepsDevEnricher_Enrich_UnpinnedVersions(t *testing.T) {
+	t.Parallel()
+
+	var peak atomic.Int32
+	depsDev := newFakeDepsDevServer(t, &peak)
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/a/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"releases": {
+			"1.0.0": [{"yanked": false}],
+			"1.4.0": [{"yanked": false}],
+			"1.5.0": [{"yanked": true}],
+			"2.0.0rc1": [{"yanked": false}],
+			"2.0.0": [{"yanked": false}]
+		}}`))
+	}))
+	t.Cleanup(registry.Close)
+
+	enr, err := depsdev.NewPyPIDepsDevEnricher(depsdev.Config{
+		BaseURL:         depsDev.URL,
+		PyPIRegistryURL: registry.URL,
+		Pool:            depsdev.NewClientPool(),
+	})
+	if err != nil {
+		t.Fatalf("NewPyPIDepsDevEnricher() error: %v", err)
+	}
+
+	inv := &inventory.Inventory{
+		Packages: []*extractor.Package{{
+			Name:      "a",
+			Version:   "1.0",
+			Locations: []string{"requirements.txt"},
+			Plugins:   []string{requirements.Name},
+			Metadata:  &requirements.Metadata{Requirement: "a>=1.0,<2", VersionComparator: ">="},
+		}},
+	}
+	if err := enr.Enrich(t.Context(), nil, inv); err != nil {
+		t.Fatalf("Enrich() error: %v", err)
+	}
+
+	if got := inv.Packages[0].Version; got != "1.4.0" {
+		t.Errorf("a.Version = %q, want the highest matching release %q", got, "1.4.0")
+	}
+	if got, want := len(inv.Packages), 2; got != want {
+		t.Errorf("len(inv.Packages) = %d, want the dependencies of a to be resolved", got)
+	}
+}
+
+func TestPyPIDepsDevEnricher_Enrich_Constraints(t *testing.T) {
+	t.Parallel()
+
+	var peak atomic.Int32
+	depsDev := newFakeDepsDevServer(t, &peak)
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/a/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"releases": {
+			"1.0.0": [{"yanked": false}],
+			"1.4.0": [{"yanked": false}],
+			"2.0.0": [{"yanked": false}]
+		}}`))
+	}))
+	t.Cleanup(registry.Close)
+
+	enr, err := depsdev.NewPyPIDepsDevEnricher(depsdev.Config{
+		BaseURL:         depsDev.URL,
+		PyPIRegistryURL: registry.URL,
+		Pool:            depsdev.NewClientPool(),
+	})
+	if err != nil {
+		t.Fatalf("NewPyPIDepsDevEnricher() error: %v", err)
+	}
+
+	// the constraints file is given by base.txt, which requirements.txt includes
+	fsys := fstest.MapFS{
+		"requirements.txt":     {Data: []byte("-r base.txt\na>=1.0\n")},
+		"base.txt":             {Data: []byte("-c constraints/pins.txt\n")},
+		"constraints/pins.txt": {Data: []byte("# keep a below 1.4\nA < 1.4\nunused==3.0\n")},
+	}
+	inv := &inventory.Inventory{
+		Packages: []*extractor.Package{{
+			Name:      "a",
+			Version:   "1.0",
+			Locations: []string{"requirements.txt"},
+			Plugins:   []string{requirements.Name},
+			Metadata:  &requirements.Metadata{Requirement: "a>=1.0", VersionComparator: ">="},
+		}},
+	}
+	input := &enricher.ScanInput{ScanRoot: &scalibrfs.ScanRoot{FS: fsys}}
+	if err := enr.Enrich(t.Context(), input, inv); err != nil {
+		t.Fatalf("Enrich() error: %v", err)
+	}
+
+	if got := inv.Packages[0].Version; got != "1.0.0" {
+		t.Errorf("a.Version = %q, want the highest release within the constraints %q", got, "1.0.0")
+	}
+}
+
+func TestPyPIDepsDevEnricher_Enrich_ExtrasAndMarkers(t *testing.T) {
+	t.Parallel()
+
+	var peak atomic.Int32
+	depsDev := newFakeDepsDevServer(t, &peak)
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a/1.0.0/json":
+			_, _ = w.Write([]byte(`{"info": {"requires_dist": [
+				"shared>=2",
+				"pysocks>=1.5; extra == \"socks\"",
+				"win-only; extra == \"socks\" and sys_platform == \"win32\"",
+				"other; extra == \"other\""
+			]}}`))
+		case "/pysocks/json":
+			_, _ = w.Write([]byte(`{"releases": {"1.7.1": [{"yanked": false}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(registry.Close)
+
+	enr, err := depsdev.NewPyPIDepsDevEnricher(depsdev.Config{
+		BaseURL:           depsDev.URL,
+		PyPIRegistryURL:   registry.URL,
+		Pool:              depsdev.NewClientPool(),
+		PythonEnvironment: depsdev.PythonEnvironment{Version: "3.11", Platform: "linux"},
+	})
+	if err != nil {
+		t.Fatalf("NewPyPIDepsDevEnricher() error: %v", err)
+	}
+
+	inv := &inventory.Inventory{
+		Packages: []*extractor.Package{
+			{
+				Name:      "a",
+				Version:   "1.0.0",
+				Locations: []string{"requirements.txt"},
+				Plugins:   []string{requirements.Name},
+				Metadata:  &requirements.Metadata{Requirement: "a[socks]==1.0.0", VersionComparator: "=="},
+			},
+			{
+				Name:      "b",
+				Version:   "1.0.0",
+				Locations: []string{"requirements.txt"},
+				Plugins:   []string{requirements.Name},
+				Metadata:  &requirements.Metadata{Requirement: `b==1.0.0; sys_platform == "win32"`, VersionComparator: "=="},
+			},
+		},
+	}
+	if err := enr.Enrich(t.Context(), nil, inv); err != nil {
+		t.Fatalf("Enrich() error: %v", err)
+	}
+
+	got := make(map[string][]depsdev.Parent)
+	for _, pkg := range inv.Packages {
+		var parents []depsdev.Parent
+		if md, ok := pkg.Metadata.(*depsdev.Metadata); ok {
+			parents = md.Parents
+		}
+		got[pkg.Name] = parents
+	}
+	want := map[string][]depsdev.Parent{
+		"a": nil,
+		"shared": {
+			{Name: "a", Version: "1.0.0", Requirement: ">=2"},
+			{Name: "pysocks", Version: "1.0.0", Requirement: ">=2"},
+		},
+		"pysocks": {{Name: "a", Version: "1.0.0", Requirement: ">=1.5"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("inventory packages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPyPIDepsDevEnricher_Enrich_RegistryFallback(t *testing.T) {
+	t.Parallel()
+
+	var peak atomic.Int32
+	fake := newFakeDepsDevServer(t, &peak)
+	depsDev := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// deps.dev has not seen the internal fork yet
+		if strings.Contains(r.URL.Path, "/packages/internal/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fake.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(depsDev.Close)
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/internal/1.0.0/json":
+			_, _ = w.Write([]byte(`{"info": {"requires_dist": ["requests>=2", "pywin32; sys_platform == \"win32\""]}}`))
+		case "/requests/json":
+			_, _ = w.Write([]byte(`{"releases": {"2.31.0": [{"yanked": false}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(registry.Close)
+
+	enr, err := depsdev.NewPyPIDepsDevEnricher(depsdev.Config{
+		BaseURL:           depsDev.URL,
+		PyPIRegistryURL:   registry.URL,
+		Pool:              depsdev.NewClientPool(),
+		PythonEnvironment: depsdev.PythonEnvironment{Platform: "linux"},
+	})
+	if err != nil {
+		t.Fatalf("NewPyPIDepsDevEnricher() error: %v", err)
+	}
+
+	inv := &inventory.Inventory{
+		Packages: []*extractor.Package{{
+			Name:      "internal",
+			Version:   "1.0.0",
+			Locations: []string{"requirements.txt"},
+			Plugins:   []string{requirements.Name},
+		}},
+	}
+	if err := enr.Enrich(t.Context(), nil, inv); err != nil {
+		t.Fatalf("Enrich() error: %v", err)
+	}
+
+	got := make([]string, 0, len(inv.Packages))
+	for _, pkg := range inv.Packages {
+		got = append(got, pkg.Name)
+	}
+	// requests comes from PyPI, and its own dependency on shared from deps.dev.
+	if diff := cmp.Diff([]string{"internal", "requests", "shared"}, got); diff != "" {
+		t.Errorf("inventory packages mismatch (-want +got):\n%s", diff)
+	}
+}
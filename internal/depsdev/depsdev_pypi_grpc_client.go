@@ -0,0 +1,139 @@
+package depsdev
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	depsdevpb "deps.dev/api/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// PyPIDepsDevGRPCClient fetches pre-computed dependency graphs from the deps.dev gRPC API.
+// It returns the same graph representation as PyPIDepsDevClient so the two can be
+// used interchangeably.
+type PyPIDepsDevGRPCClient struct {
+	client  depsdevpb.InsightsClient
+	md      metadata.MD
+	metrics Metrics
+	cache   *graphCache
+	group   coalesceGroup
+}
+
+// NewPyPIDepsDevGRPCClient creates a new client for the deps.dev gRPC API.
+// addr should be a gRPC endpoint, e.g. DepsdevAPI.
+func NewPyPIDepsDevGRPCClient(addr string, opts ClientOptions) (*PyPIDepsDevGRPCClient, error) {
+	certPool, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, fmt.Errorf("getting system cert pool: %w", err)
+	}
+	creds := credentials.NewClientTLSFromCert(certPool, "")
+
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if opts.UserAgent != "" {
+		dialOpts = append(dialOpts, grpc.WithUserAgent(opts.UserAgent))
+	}
+
+	conn, err := grpc.NewClient(addr, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("dialling %q: %w", addr, err)
+	}
+
+	return &PyPIDepsDevGRPCClient{
+		client:  depsdevpb.NewInsightsClient(conn),
+		md:      metadata.New(opts.Headers),
+		metrics: opts.metrics(),
+		cache:   newGraphCache(opts.CacheLimits),
+	}, nil
+}
+
+// GetDependencies fetches the pre-computed dependency graph for a PyPI package version.
+func (c *PyPIDepsDevGRPCClient) GetDependencies(ctx context.Context, name, version string) (*DepsDevDependencyGraph, error) {
+	return c.GetSystemDependencies(ctx, pypiSystem, name, version)
+}
+
+// GetSystemDependencies fetches the pre-computed dependency graph for a
+// package version of the given deps.dev system.
+func (c *PyPIDepsDevGRPCClient) GetSystemDependencies(ctx context.Context, system, name, version string) (*DepsDevDependencyGraph, error) {
+	pbSystem, ok := depsdevpb.System_value[strings.ToUpper(system)]
+	if !ok {
+		return nil, fmt.Errorf("unknown deps.dev system %q", system)
+	}
+
+	cacheKey := graphCacheKey(system, name, version)
+	metrics := metricsFrom(ctx, c.metrics)
+
+	if cached, ok := c.cache.get(cacheKey); ok {
+		metrics.CacheHit(system)
+		return cached, nil
+	}
+	metrics.CacheMiss(system)
+
+	// Coalesce concurrent lookups of the same package version into a single request.
+	v, err := coalesce(ctx, &c.group, cacheKey, func(ctx context.Context) (any, error) {
+		ctx = metadata.NewOutgoingContext(ctx, c.md)
+		start := time.Now()
+		resp, err := c.client.GetDependencies(ctx, &depsdevpb.GetDependenciesRequest{
+			VersionKey: &depsdevpb.VersionKey{
+				System:  depsdevpb.System(pbSystem),
+				Name:    name,
+				Version: version,
+			},
+		})
+		metrics.Request(system, time.Since(start), err)
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("%w: %s@%s", ErrNotFound, name, version)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("deps.dev gRPC request failed for %s@%s: %w", name, version, err)
+		}
+
+		graph := graphFromProto(resp)
+		c.cache.add(cacheKey, graph)
+
+		return graph, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*DepsDevDependencyGraph), nil
+}
+
+// graphFromProto converts a deps.dev gRPC dependency graph into the REST representation.
+func graphFromProto(deps *depsdevpb.Dependencies) *DepsDevDependencyGraph {
+	graph := &DepsDevDependencyGraph{
+		Nodes: make([]DepsDevNode, 0, len(deps.GetNodes())),
+		Edges: make([]DepsDevEdge, 0, len(deps.GetEdges())),
+	}
+
+	for _, node := range deps.GetNodes() {
+		vk := node.GetVersionKey()
+		graph.Nodes = append(graph.Nodes, DepsDevNode{
+			VersionKey: DepsDevVersionKey{
+				System:  vk.GetSystem().String(),
+				Name:    vk.GetName(),
+				Version: vk.GetVersion(),
+			},
+			Bundled:  node.GetBundled(),
+			Relation: node.GetRelation().String(),
+			Errors:   node.GetErrors(),
+		})
+	}
+
+	for _, edge := range deps.GetEdges() {
+		graph.Edges = append(graph.Edges, DepsDevEdge{
+			FromNode:    int(edge.GetFromNode()),
+			ToNode:      int(edge.GetToNode()),
+			Requirement: edge.GetRequirement(),
+		})
+	}
+
+	return graph
+}
@@ -0,0 +1,163 @@
+package depsdev
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	depsdevpb "deps.dev/api/v3"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGraphFromProto(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		deps *depsdevpb.Dependencies
+		want *DepsDevDependencyGraph
+	}{
+		{
+			name: "nil",
+			deps: nil,
+			want: &DepsDevDependencyGraph{Nodes: []DepsDevNode{}, Edges: []DepsDevEdge{}},
+		},
+		{
+			name: "self_only",
+			deps: &depsdevpb.Dependencies{
+				Nodes: []*depsdevpb.Dependencies_Node{{
+					VersionKey: &depsdevpb.VersionKey{System: depsdevpb.System_PYPI, Name: "a", Version: "1.0.0"},
+					Relation:   depsdevpb.DependencyRelation_SELF,
+				}},
+			},
+			want: &DepsDevDependencyGraph{
+				Nodes: []DepsDevNode{{
+					VersionKey: DepsDevVersionKey{System: "PYPI", Name: "a", Version: "1.0.0"},
+					Relation:   "SELF",
+				}},
+				Edges: []DepsDevEdge{},
+			},
+		},
+		{
+			name: "nodes_and_edges",
+			deps: &depsdevpb.Dependencies{
+				Nodes: []*depsdevpb.Dependencies_Node{
+					{
+						VersionKey: &depsdevpb.VersionKey{System: depsdevpb.System_PYPI, Name: "a", Version: "1.0.0"},
+						Relation:   depsdevpb.DependencyRelation_SELF,
+					},
+					{
+						VersionKey: &depsdevpb.VersionKey{System: depsdevpb.System_PYPI, Name: "b", Version: "2.0.0"},
+						Relation:   depsdevpb.DependencyRelation_DIRECT,
+						Bundled:    true,
+					},
+					{
+						VersionKey: &depsdevpb.VersionKey{System: depsdevpb.System_PYPI, Name: "c", Version: "3.0.0"},
+						Relation:   depsdevpb.DependencyRelation_INDIRECT,
+						Errors:     []string{"could not resolve c"},
+					},
+				},
+				Edges: []*depsdevpb.Dependencies_Edge{
+					{FromNode: 0, ToNode: 1, Requirement: ">=2"},
+					{FromNode: 1, ToNode: 2, Requirement: "~=3.0"},
+				},
+			},
+			want: &DepsDevDependencyGraph{
+				Nodes: []DepsDevNode{
+					{VersionKey: DepsDevVersionKey{System: "PYPI", Name: "a", Version: "1.0.0"}, Relation: "SELF"},
+					{VersionKey: DepsDevVersionKey{System: "PYPI", Name: "b", Version: "2.0.0"}, Relation: "DIRECT", Bundled: true},
+					{VersionKey: DepsDevVersionKey{System: "PYPI", Name: "c", Version: "3.0.0"}, Relation: "INDIRECT", Errors: []string{"could not resolve c"}},
+				},
+				Edges: []DepsDevEdge{
+					{FromNode: 0, ToNode: 1, Requirement: ">=2"},
+					{FromNode: 1, ToNode: 2, Requirement: "~=3.0"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := graphFromProto(tt.deps)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("graphFromProto() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// fakeInsightsClient answers GetDependencies with err, or with a graph of
+// only the requested package if err is nil.
+type fakeInsightsClient struct {
+	depsdevpb.InsightsClient
+
+	err error
+}
+
+func (c fakeInsightsClient) GetDependencies(_ context.Context, req *depsdevpb.GetDependenciesRequest, _ ...grpc.CallOption) (*depsdevpb.Dependencies, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return &depsdevpb.Dependencies{
+		Nodes: []*depsdevpb.Dependencies_Node{{
+			VersionKey: req.GetVersionKey(),
+			Relation:   depsdevpb.DependencyRelation_SELF,
+		}},
+	}, nil
+}
+
+func TestPyPIDepsDevGRPCClient_GetDependencies_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		err          error
+		wantErr      bool
+		wantNotFound bool
+	}{
+		{
+			name: "found",
+		},
+		{
+			name:         "not_found",
+			err:          status.Error(codes.NotFound, "no such version"),
+			wantErr:      true,
+			wantNotFound: true,
+		},
+		{
+			name:    "unavailable",
+			err:     status.Error(codes.Unavailable, "try again later"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &PyPIDepsDevGRPCClient{
+				client:  fakeInsightsClient{err: tt.err},
+				md:      metadata.MD{},
+				metrics: noopMetrics{},
+				cache:   newGraphCache(CacheLimits{}),
+			}
+			got, err := c.GetDependencies(t.Context(), "a", "1.0.0")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetDependencies() error = %v, want error %t", err, tt.wantErr)
+			}
+			if gotNotFound := errors.Is(err, ErrNotFound); gotNotFound != tt.wantNotFound {
+				t.Errorf("GetDependencies() error = %v, want ErrNotFound %t", err, tt.wantNotFound)
+			}
+			if err == nil && (len(got.Nodes) != 1 || got.Nodes[0].VersionKey.Name != "a") {
+				t.Errorf("GetDependencies() = %v, want the graph of a", got)
+			}
+		})
+	}
+}
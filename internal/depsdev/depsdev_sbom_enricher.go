@@ -0,0 +1,367 @@
+package depsdev
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/google/osv-scalibr/converter"
+	"github.com/google/osv-scalibr/enricher"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/sbom/cdx"
+	"github.com/google/osv-scalibr/extractor/filesystem/sbom/spdx"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/log"
+	"github.com/google/osv-scalibr/plugin"
+	scalibrpurl "github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/utility/purl"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// SBOMDepsDevEnricherName is the unique name of this enricher.
+	SBOMDepsDevEnricherName = "transitivedependency/sbom/depsdev"
+)
+
+// sbomSystems maps the package URL types of the SBOM components that deps.dev
+// has dependency graphs for to their deps.dev system.
+var sbomSystems = map[string]string{
+	scalibrpurl.TypeCargo: "cargo",
+	scalibrpurl.TypeMaven: "maven",
+	scalibrpurl.TypeNPM:   "npm",
+	scalibrpurl.TypePyPi:  pypiSystem,
+}
+
+// SBOMDepsDevEnricher fills in the dependencies that are missing from
+// CycloneDX and SPDX documents, by looking up the dependency graph of each of
+// their components on deps.dev.
+//
+// The dependencies are added as transitive packages of the SBOM, along with
+// the components that depend on them, so that the dependency graph can be
+// output with them.
+type SBOMDepsDevEnricher struct {
+	client           SystemDependencyGraphClient
+	parallelism      int
+	breakerThreshold int
+	maxDepth         int
+	degradations     *Degradations
+	metrics          Metrics
+	offline          bool
+}
+
+// NewSBOMDepsDevEnricher creates a new enricher that uses the deps.dev API
+// over the transport selected in cfg.
+func NewSBOMDepsDevEnricher(cfg Config) (enricher.Enricher, error) {
+	client, err := cfg.pool().Get(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	systemClient, ok := client.(SystemDependencyGraphClient)
+	if !ok {
+		return nil, fmt.Errorf("deps.dev client %T cannot look up packages outside of PyPI", client)
+	}
+
+	return &SBOMDepsDevEnricher{
+		client:           systemClient,
+		parallelism:      cfg.parallelism(),
+		breakerThreshold: cfg.circuitBreakerThreshold(),
+		maxDepth:         cfg.MaxDepth,
+		degradations:     cfg.Degradations,
+		metrics:          cfg.Metrics,
+		offline:          cfg.SnapshotPath != "",
+	}, nil
+}
+
+// Name returns the name of the enricher.
+func (e *SBOMDepsDevEnricher) Name() string {
+	return SBOMDepsDevEnricherName
+}
+
+// Version returns the version of the enricher.
+func (e *SBOMDepsDevEnricher) Version() int {
+	return 0
+}
+
+// Requirements returns the requirements of the enricher.
+func (e *SBOMDepsDevEnricher) Requirements() *plugin.Capabilities {
+	if e.offline {
+		return &plugin.Capabilities{
+			Network: plugin.NetworkAny,
+		}
+	}
+
+	return &plugin.Capabilities{
+		Network: plugin.NetworkOnline,
+	}
+}
+
+// RequiredPlugins returns the names of the plugins required by the enricher.
+// Either of the SBOM extractors is enough, so neither is required.
+func (e *SBOMDepsDevEnricher) RequiredPlugins() []string {
+	return []string{}
+}
+
+// Enrich adds the dependencies of the components of each SBOM in the
+// inventory that are not already in it.
+func (e *SBOMDepsDevEnricher) Enrich(ctx context.Context, _ *enricher.ScanInput, inv *inventory.Inventory) error {
+	ctx = withMetrics(ctx, e.metrics)
+
+	components := make(map[string][]*extractor.Package)
+	for _, pkg := range inv.Packages {
+		if sbomPlugin(pkg) == "" || len(pkg.Locations) == 0 {
+			continue
+		}
+		components[pkg.Locations[0]] = append(components[pkg.Locations[0]], pkg)
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(components)) {
+		// Give up on the remaining SBOMs once cancelled, keeping those already enriched.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		inv.Packages = append(inv.Packages, e.enrichSBOM(ctx, path, components[path])...)
+	}
+
+	return nil
+}
+
+// sbomPlugin returns the name of the SBOM extractor that found pkg, or "" if
+// it is not an SBOM component.
+func sbomPlugin(pkg *extractor.Package) string {
+	for _, name := range pkg.Plugins {
+		if name == cdx.Name || name == spdx.Name {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// sbomComponent is a component of an SBOM that deps.dev can be asked about.
+type sbomComponent struct {
+	system, name, version string
+}
+
+// key identifies the component across dependency graphs.
+func (c sbomComponent) key() string {
+	return sbomNodeKey(DepsDevNode{VersionKey: DepsDevVersionKey{System: c.system, Name: c.name, Version: c.version}})
+}
+
+// toSBOMComponent returns the component for pkg, if it is a package of a
+// system that deps.dev has dependency graphs for.
+func toSBOMComponent(pkg *extractor.Package) (sbomComponent, bool) {
+	system, ok := sbomSystems[pkg.PURLType]
+	if !ok {
+		return sbomComponent{}, false
+	}
+
+	// Components are named and versioned by their package URL, as when
+	// they are matched against vulnerabilities
+	packageURL := converter.ToPURL(pkg)
+	if packageURL == nil {
+		return sbomComponent{}, false
+	}
+	info, err := purl.ToPackage(packageURL.String())
+	if err != nil || info.Version == "" {
+		return sbomComponent{}, false
+	}
+
+	return sbomComponent{system: system, name: info.Name, version: info.Version}, true
+}
+
+// enrichSBOM returns the dependencies of the components of the SBOM at path
+// that are not in it, resolved through deps.dev. Lookups that fail are
+// logged and recorded as degradations, leaving those components as they are.
+func (e *SBOMDepsDevEnricher) enrichSBOM(ctx context.Context, path string, pkgs []*extractor.Package) []*extractor.Package {
+	known := make(map[string]bool)
+	var roots []sbomComponent
+	extractorName := ""
+	for _, pkg := range pkgs {
+		extractorName = sbomPlugin(pkg)
+
+		component, ok := toSBOMComponent(pkg)
+		if !ok || known[component.key()] {
+			continue
+		}
+		known[component.key()] = true
+		roots = append(roots, component)
+	}
+	slices.SortFunc(roots, func(a, b sbomComponent) int {
+		return strings.Compare(a.key(), b.key())
+	})
+
+	graphs := make([]*DepsDevDependencyGraph, len(roots))
+	errs := make([]error, len(roots))
+	breaker := newCircuitBreaker(e.breakerThreshold)
+	var skipped atomic.Int32
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(e.parallelism)
+	for i, root := range roots {
+		g.Go(func() error {
+			if breaker.open() {
+				skipped.Add(1)
+				errs[i] = ErrCircuitOpen
+
+				return nil
+			}
+			graph, err := e.client.GetSystemDependencies(gctx, root.system, root.name, root.version)
+			breaker.record(err)
+			if err != nil {
+				errs[i] = err
+				return nil
+			}
+			graphs[i] = graph
+
+			return nil
+		})
+	}
+	// Lookup failures are kept rather than returned, so Wait never reports
+	// an error.
+	_ = g.Wait()
+
+	// Loggers can be tied to the goroutine running the enricher, so the
+	// failures are only logged once all the lookups are done.
+	for i, root := range roots {
+		switch {
+		case errors.Is(errs[i], ErrCircuitOpen):
+			e.degradations.RecordUnresolved(path, root.name, root.version, ErrCircuitOpen.Error())
+		case errs[i] != nil:
+			log.Warnf("deps.dev: failed to get dependencies for %s@%s: %v", root.name, root.version, errs[i])
+			e.degradations.RecordUnresolved(path, root.name, root.version, errs[i].Error())
+		}
+	}
+
+	if n := skipped.Load(); n > 0 {
+		log.Warnf("deps.dev: skipped %d lookups for %s: %v", n, path, ErrCircuitOpen)
+		e.degradations.Record(path, fmt.Sprintf("%v (%d of %d components skipped): %v", ErrCircuitOpen, n, len(roots), breaker.cause()))
+	}
+
+	byKey := make(map[string]*extractor.Package)
+	var result []*extractor.Package
+	for i, graph := range graphs {
+		if graph == nil {
+			continue
+		}
+
+		for _, node := range graph.Nodes {
+			for _, nodeErr := range node.Errors {
+				e.degradations.Record(path, fmt.Sprintf("deps.dev reported an error resolving %s: %s", sbomNodeKey(node), nodeErr))
+			}
+
+			key := sbomNodeKey(withSystem(node, roots[i].system))
+			if node.Relation == "SELF" || known[key] {
+				continue
+			}
+
+			if pkg, ok := byKey[key]; ok {
+				md := pkg.Metadata.(*Metadata)
+				md.Bundled = md.Bundled && node.Bundled
+
+				continue
+			}
+
+			pkg := &extractor.Package{
+				Name:      node.VersionKey.Name,
+				Version:   node.VersionKey.Version,
+				PURLType:  purlTypeOfSystem(roots[i].system),
+				Locations: []string{path},
+				// Attributed to the SBOM, so that the package is reported
+				// along with its components
+				Plugins: []string{extractorName, SBOMDepsDevEnricherName},
+				Metadata: &Metadata{
+					IsTransitive: true,
+					Bundled:      node.Bundled,
+				},
+			}
+			byKey[key] = pkg
+			result = append(result, pkg)
+		}
+
+		for j, chain := range introductionChains(graph, sbomNodeLabel) {
+			pkg, ok := byKey[sbomNodeKey(withSystem(graph.Nodes[j], roots[i].system))]
+			if !ok || chain == nil {
+				continue
+			}
+			md := pkg.Metadata.(*Metadata)
+			md.IntroducedBy = append(md.IntroducedBy, chain)
+		}
+
+		for _, edge := range graph.Edges {
+			if !validEdge(graph, edge) {
+				continue
+			}
+			child, ok := byKey[sbomNodeKey(withSystem(graph.Nodes[edge.ToNode], roots[i].system))]
+			if !ok {
+				// Edges into the components of the SBOM
+				continue
+			}
+
+			from := graph.Nodes[edge.FromNode]
+			md := child.Metadata.(*Metadata)
+			md.Parents = append(md.Parents, Parent{
+				Name:        from.VersionKey.Name,
+				Version:     from.VersionKey.Version,
+				Requirement: edge.Requirement,
+			})
+		}
+	}
+
+	for _, pkg := range result {
+		md := pkg.Metadata.(*Metadata)
+		slices.SortFunc(md.Parents, compareParents)
+		md.Parents = slices.Compact(md.Parents)
+		slices.SortFunc(md.IntroducedBy, slices.Compare)
+		md.IntroducedBy = slices.CompactFunc(md.IntroducedBy, slices.Equal)
+	}
+
+	if e.maxDepth > 0 {
+		// The components of the SBOM are at depth 1, as the packages declared
+		// in a manifest are
+		result = slices.DeleteFunc(result, func(pkg *extractor.Package) bool {
+			return depth(pkg) >= e.maxDepth
+		})
+	}
+
+	return result
+}
+
+// withSystem returns node with its system set to the one it was looked up in,
+// as deps.dev reports systems in upper case.
+func withSystem(node DepsDevNode, system string) DepsDevNode {
+	node.VersionKey.System = system
+
+	return node
+}
+
+// sbomNodeKey identifies a package version of any system across graphs.
+func sbomNodeKey(node DepsDevNode) string {
+	name := node.VersionKey.Name
+	if strings.EqualFold(node.VersionKey.System, pypiSystem) {
+		name = NormalizePyPIName(name)
+	}
+
+	return strings.ToLower(node.VersionKey.System) + ":" + name + "@" + node.VersionKey.Version
+}
+
+// sbomNodeLabel is how a node is shown in the chains of packages that
+// introduce a dependency.
+func sbomNodeLabel(node DepsDevNode) string {
+	return node.VersionKey.Name + "@" + node.VersionKey.Version
+}
+
+// purlTypeOfSystem returns the package URL type of the packages of a deps.dev system.
+func purlTypeOfSystem(system string) string {
+	for purlType, s := range sbomSystems {
+		if s == system {
+			return purlType
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,191 @@
+package depsdev_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/sbom/spdx"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+)
+
+func node(system, name, version, relation string) depsdev.DepsDevNode {
+	return depsdev.DepsDevNode{
+		VersionKey: depsdev.DepsDevVersionKey{System: system, Name: name, Version: version},
+		Relation:   relation,
+	}
+}
+
+func TestSBOMDepsDevEnricher_Enrich(t *testing.T) {
+	t.Parallel()
+
+	graphs := map[string]depsdev.DepsDevDependencyGraph{
+		"/v3/systems/npm/packages/express/versions/4.18.2:dependencies": {
+			Nodes: []depsdev.DepsDevNode{
+				node("NPM", "express", "4.18.2", "SELF"),
+				node("NPM", "body-parser", "1.20.1", "DIRECT"),
+				node("NPM", "debug", "2.6.9", "DIRECT"),
+				node("NPM", "bytes", "3.1.2", "INDIRECT"),
+			},
+			Edges: []depsdev.DepsDevEdge{
+				{FromNode: 0, ToNode: 1, Requirement: "1.20.1"},
+				{FromNode: 0, ToNode: 2, Requirement: "2.6.9"},
+				{FromNode: 1, ToNode: 2, Requirement: "2.6.9"},
+				{FromNode: 1, ToNode: 3, Requirement: "3.1.2"},
+			},
+		},
+		"/v3/systems/npm/packages/debug/versions/2.6.9:dependencies": {
+			Nodes: []depsdev.DepsDevNode{
+				node("NPM", "debug", "2.6.9", "SELF"),
+				node("NPM", "ms", "2.0.0", "DIRECT"),
+			},
+			Edges: []depsdev.DepsDevEdge{{FromNode: 0, ToNode: 1, Requirement: "2.0.0"}},
+		},
+		"/v3/systems/maven/packages/com.example:app/versions/1.0.0:dependencies": {
+			Nodes: []depsdev.DepsDevNode{
+				node("MAVEN", "com.example:app", "1.0.0", "SELF"),
+				node("MAVEN", "org.slf4j:slf4j-api", "2.0.9", "DIRECT"),
+			},
+			Edges: []depsdev.DepsDevEdge{{FromNode: 0, ToNode: 1, Requirement: "2.0.9"}},
+		},
+	}
+
+	var (
+		mu        sync.Mutex
+		requested []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+
+		graph, ok := graphs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(graph)
+	}))
+	t.Cleanup(srv.Close)
+
+	enr, err := depsdev.NewSBOMDepsDevEnricher(depsdev.Config{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewSBOMDepsDevEnricher() error: %v", err)
+	}
+
+	component := func(purlType, name, version string) *extractor.Package {
+		return &extractor.Package{
+			Name:      name,
+			Version:   version,
+			PURLType:  purlType,
+			Locations: []string{"bom.spdx.json"},
+			Plugins:   []string{spdx.Name},
+		}
+	}
+	inv := &inventory.Inventory{
+		Packages: []*extractor.Package{
+			component(purl.TypeNPM, "express", "4.18.2"),
+			component(purl.TypeNPM, "debug", "2.6.9"),
+			component(purl.TypeMaven, "com.example:app", "1.0.0"),
+			// deps.dev has no dependency graphs for Go modules
+			component(purl.TypeGolang, "github.com/example/lib", "1.2.3"),
+			// not from an SBOM
+			{Name: "left-pad", Version: "1.3.0", PURLType: purl.TypeNPM, Locations: []string{"package-lock.json"}},
+		},
+	}
+
+	if err := enr.Enrich(t.Context(), nil, inv); err != nil {
+		t.Fatalf("Enrich() error: %v", err)
+	}
+
+	added := func(purlType, name, version string, md *depsdev.Metadata) *extractor.Package {
+		md.IsTransitive = true
+
+		return &extractor.Package{
+			Name:      name,
+			Version:   version,
+			PURLType:  purlType,
+			Locations: []string{"bom.spdx.json"},
+			Plugins:   []string{spdx.Name, depsdev.SBOMDepsDevEnricherName},
+			Metadata:  md,
+		}
+	}
+	want := []*extractor.Package{
+		added(purl.TypeNPM, "body-parser", "1.20.1", &depsdev.Metadata{
+			Parents:      []depsdev.Parent{{Name: "express", Version: "4.18.2", Requirement: "1.20.1"}},
+			IntroducedBy: [][]string{{"express@4.18.2"}},
+		}),
+		added(purl.TypeNPM, "bytes", "3.1.2", &depsdev.Metadata{
+			Parents:      []depsdev.Parent{{Name: "body-parser", Version: "1.20.1", Requirement: "3.1.2"}},
+			IntroducedBy: [][]string{{"express@4.18.2", "body-parser@1.20.1"}},
+		}),
+		added(purl.TypeNPM, "ms", "2.0.0", &depsdev.Metadata{
+			Parents:      []depsdev.Parent{{Name: "debug", Version: "2.6.9", Requirement: "2.0.0"}},
+			IntroducedBy: [][]string{{"debug@2.6.9"}},
+		}),
+		added(purl.TypeMaven, "org.slf4j:slf4j-api", "2.0.9", &depsdev.Metadata{
+			Parents:      []depsdev.Parent{{Name: "com.example:app", Version: "1.0.0", Requirement: "2.0.9"}},
+			IntroducedBy: [][]string{{"com.example:app@1.0.0"}},
+		}),
+	}
+
+	less := func(a, b *extractor.Package) bool { return a.Name < b.Name }
+	if diff := cmp.Diff(want, inv.Packages[5:], cmpopts.SortSlices(less)); diff != "" {
+		t.Errorf("Enrich() added packages mismatch (-want +got):\n%s", diff)
+	}
+
+	slices.Sort(requested)
+	wantRequested := []string{
+		"/v3/systems/maven/packages/com.example:app/versions/1.0.0:dependencies",
+		"/v3/systems/npm/packages/debug/versions/2.6.9:dependencies",
+		"/v3/systems/npm/packages/express/versions/4.18.2:dependencies",
+	}
+	if diff := cmp.Diff(wantRequested, requested); diff != "" {
+		t.Errorf("requested graphs mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSBOMDepsDevEnricher_Enrich_Unresolved(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+
+	degradations := &depsdev.Degradations{}
+	enr, err := depsdev.NewSBOMDepsDevEnricher(depsdev.Config{BaseURL: srv.URL, Degradations: degradations})
+	if err != nil {
+		t.Fatalf("NewSBOMDepsDevEnricher() error: %v", err)
+	}
+
+	inv := &inventory.Inventory{
+		Packages: []*extractor.Package{{
+			Name:      "express",
+			Version:   "4.18.2",
+			PURLType:  purl.TypeNPM,
+			Locations: []string{"bom.spdx.json"},
+			Plugins:   []string{spdx.Name},
+		}},
+	}
+
+	if err := enr.Enrich(t.Context(), nil, inv); err != nil {
+		t.Fatalf("Enrich() error: %v", err)
+	}
+
+	if got := len(inv.Packages); got != 1 {
+		t.Errorf("len(inv.Packages) = %d, want 1", got)
+	}
+	unresolved := degradations.Unresolved()
+	if len(unresolved) != 1 || unresolved[0].Name != "express" {
+		t.Errorf("degradations.Unresolved() = %+v, want express to be unresolved", unresolved)
+	}
+}
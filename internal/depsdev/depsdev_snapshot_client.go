@@ -0,0 +1,139 @@
+package depsdev
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotInSnapshot is returned when a package version is missing from an offline snapshot.
+var ErrNotInSnapshot = errors.New("package version not found in deps.dev snapshot")
+
+// snapshotRecord is a single line of a deps.dev snapshot bundle.
+type snapshotRecord struct {
+	System  string `json:"system"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	DepsDevDependencyGraph
+}
+
+// SnapshotClient reads pre-computed dependency graphs from a local snapshot
+// instead of the live deps.dev API, for use in air-gapped environments.
+//
+// The snapshot can either be a directory laid out as
+//
+//	<root>/<system>/<name>/<version>.json
+//
+// where each file holds a deps.dev :dependencies REST response, or a single
+// newline-delimited JSON bundle where each line is a graph annotated with its
+// "system", "name" and "version". Names are matched case-insensitively, and
+// those of PyPI packages normalized as described in PEP 503, so a directory
+// snapshot has to use the lowercase, normalized names.
+type SnapshotClient struct {
+	dir    string
+	bundle map[string]*DepsDevDependencyGraph
+}
+
+// NewSnapshotClient creates a client reading from the snapshot at path.
+func NewSnapshotClient(path string) (*SnapshotClient, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open deps.dev snapshot: %w", err)
+	}
+
+	if info.IsDir() {
+		return &SnapshotClient{dir: path}, nil
+	}
+
+	bundle, err := loadSnapshotBundle(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SnapshotClient{bundle: bundle}, nil
+}
+
+// GetDependencies returns the dependency graph of a PyPI package version from the snapshot.
+func (c *SnapshotClient) GetDependencies(ctx context.Context, name, version string) (*DepsDevDependencyGraph, error) {
+	return c.GetSystemDependencies(ctx, pypiSystem, name, version)
+}
+
+// GetSystemDependencies returns the dependency graph of a package version of
+// the given deps.dev system from the snapshot.
+func (c *SnapshotClient) GetSystemDependencies(_ context.Context, system, name, version string) (*DepsDevDependencyGraph, error) {
+	name = snapshotName(system, name)
+
+	if c.bundle != nil {
+		graph, ok := c.bundle[snapshotKey(system, name, version)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s@%s", ErrNotInSnapshot, name, version)
+		}
+
+		return graph, nil
+	}
+
+	f, err := os.Open(filepath.Join(c.dir, strings.ToLower(system), name, version+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s@%s", ErrNotInSnapshot, name, version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deps.dev snapshot for %s@%s: %w", name, version, err)
+	}
+	defer f.Close()
+
+	var graph DepsDevDependencyGraph
+	if err := json.NewDecoder(f).Decode(&graph); err != nil {
+		return nil, fmt.Errorf("failed to decode deps.dev snapshot for %s@%s: %w", name, version, err)
+	}
+
+	return &graph, nil
+}
+
+func loadSnapshotBundle(path string) (map[string]*DepsDevDependencyGraph, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open deps.dev snapshot: %w", err)
+	}
+	defer f.Close()
+
+	bundle := make(map[string]*DepsDevDependencyGraph)
+	scanner := bufio.NewScanner(f)
+	// Graphs for large packages can easily exceed the default 64KiB line limit.
+	scanner.Buffer(nil, 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		var rec snapshotRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("failed to decode deps.dev snapshot line %d: %w", line, err)
+		}
+		graph := rec.DepsDevDependencyGraph
+		bundle[snapshotKey(rec.System, snapshotName(rec.System, rec.Name), rec.Version)] = &graph
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read deps.dev snapshot: %w", err)
+	}
+
+	return bundle, nil
+}
+
+func snapshotKey(system, name, version string) string {
+	return strings.ToLower(system) + ":" + name + "@" + version
+}
+
+// snapshotName returns the name a package is found by in a snapshot.
+func snapshotName(system, name string) string {
+	if strings.EqualFold(system, pypiSystem) {
+		return NormalizePyPIName(name)
+	}
+
+	return strings.ToLower(name)
+}
@@ -0,0 +1,83 @@
+package depsdev_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+)
+
+func TestSnapshotClient_Directory(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "pypi", "requests"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "pypi", "typing-extensions"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pypi", "typing-extensions", "4.9.0.json"), []byte(`{"nodes":[]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	graph := `{"nodes":[{"versionKey":{"system":"PYPI","name":"requests","version":"2.31.0"},"relation":"SELF"},` +
+		`{"versionKey":{"system":"PYPI","name":"idna","version":"3.6"},"relation":"DIRECT"}],` +
+		`"edges":[{"fromNode":0,"toNode":1,"requirement":"<4,>=2.5"}]}`
+	if err := os.WriteFile(filepath.Join(dir, "pypi", "requests", "2.31.0.json"), []byte(graph), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := depsdev.NewSnapshotClient(dir)
+	if err != nil {
+		t.Fatalf("NewSnapshotClient() error: %v", err)
+	}
+
+	got, err := c.GetDependencies(t.Context(), "Requests", "2.31.0")
+	if err != nil {
+		t.Fatalf("GetDependencies() error: %v", err)
+	}
+	if len(got.Nodes) != 2 || got.Nodes[1].VersionKey.Name != "idna" {
+		t.Errorf("GetDependencies() = %+v, want graph with idna dependency", got)
+	}
+
+	if _, err := c.GetDependencies(t.Context(), "Typing_Extensions", "4.9.0"); err != nil {
+		t.Errorf("GetDependencies(Typing_Extensions) error: %v", err)
+	}
+
+	_, err = c.GetDependencies(t.Context(), "requests", "0.0.1")
+	if !errors.Is(err, depsdev.ErrNotInSnapshot) {
+		t.Errorf("GetDependencies() error = %v, want %v", err, depsdev.ErrNotInSnapshot)
+	}
+}
+
+func TestSnapshotClient_Bundle(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "snapshot.jsonl")
+	bundle := `{"system":"PYPI","name":"idna","version":"3.6","nodes":[{"versionKey":{"system":"PYPI","name":"idna","version":"3.6"},"relation":"SELF"}]}` + "\n" +
+		"\n" +
+		`{"system":"PYPI","name":"Flask","version":"3.0.0","nodes":[{"versionKey":{"system":"PYPI","name":"flask","version":"3.0.0"},"relation":"SELF"}]}` + "\n" +
+		`{"system":"PYPI","name":"zope.interface","version":"6.1","nodes":[{"versionKey":{"system":"PYPI","name":"zope-interface","version":"6.1"},"relation":"SELF"}]}` + "\n"
+	if err := os.WriteFile(path, []byte(bundle), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := depsdev.NewSnapshotClient(path)
+	if err != nil {
+		t.Fatalf("NewSnapshotClient() error: %v", err)
+	}
+
+	for _, name := range []string{"idna", "flask", "zope_interface", "Zope-Interface"} {
+		version := map[string]string{"idna": "3.6", "flask": "3.0.0", "zope_interface": "6.1", "Zope-Interface": "6.1"}[name]
+		if _, err := c.GetDependencies(t.Context(), name, version); err != nil {
+			t.Errorf("GetDependencies(%s) error: %v", name, err)
+		}
+	}
+
+	_, err = c.GetDependencies(t.Context(), "requests", "2.31.0")
+	if !errors.Is(err, depsdev.ErrNotInSnapshot) {
+		t.Errorf("GetDependencies() error = %v, want %v", err, depsdev.ErrNotInSnapshot)
+	}
+}
@@ -0,0 +1,367 @@
+package depsdev
+
+import (
+	"fmt"
+	"strings"
+
+	"deps.dev/util/semver"
+)
+
+// PythonEnvironment is the Python environment that requirements are resolved
+// for. Environment markers that depend on something left unset are assumed to
+// hold, so that nothing is skipped unless the environment rules it out.
+type PythonEnvironment struct {
+	// Version is the Python version, e.g. "3.11" or "3.11.4".
+	Version string
+	// Platform is the value of sys.platform, e.g. "linux", "darwin" or "win32".
+	Platform string
+}
+
+// variables returns the values of the PEP 508 environment marker variables
+// known for the environment.
+func (env PythonEnvironment) variables() map[string]string {
+	vars := make(map[string]string)
+	if env.Version != "" {
+		parts := strings.SplitN(env.Version, ".", 3)
+		vars["python_version"] = strings.Join(parts[:min(2, len(parts))], ".")
+		if len(parts) == 3 {
+			vars["python_full_version"] = env.Version
+		}
+	}
+	if env.Platform != "" {
+		vars["sys_platform"] = env.Platform
+		switch {
+		case env.Platform == "win32":
+			vars["platform_system"] = "Windows"
+			vars["os_name"] = "nt"
+		case env.Platform == "darwin":
+			vars["platform_system"] = "Darwin"
+			vars["os_name"] = "posix"
+		case strings.HasPrefix(env.Platform, "linux"):
+			vars["platform_system"] = "Linux"
+			vars["os_name"] = "posix"
+		}
+	}
+
+	return vars
+}
+
+// versionMarkers are the marker variables compared as PEP 440 versions.
+var versionMarkers = map[string]bool{
+	"python_version":         true,
+	"python_full_version":    true,
+	"implementation_version": true,
+	"platform_release":       true,
+}
+
+// marker is a parsed PEP 508 environment marker.
+type marker interface {
+	// eval reports whether the marker holds in an environment with the given
+	// variables, when the given extras are requested.
+	eval(vars map[string]string, extras map[string]bool) bool
+}
+
+type markerOr []marker
+
+func (m markerOr) eval(vars map[string]string, extras map[string]bool) bool {
+	for _, sub := range m {
+		if sub.eval(vars, extras) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type markerAnd []marker
+
+func (m markerAnd) eval(vars map[string]string, extras map[string]bool) bool {
+	for _, sub := range m {
+		if !sub.eval(vars, extras) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// markerValue is either an environment variable or a quoted string.
+type markerValue struct {
+	variable string
+	literal  string
+}
+
+// markerExpr compares two values, e.g. `python_version < "3.10"`.
+type markerExpr struct {
+	lhs, rhs markerValue
+	op       string
+}
+
+func (m markerExpr) eval(vars map[string]string, extras map[string]bool) bool {
+	if m.lhs.variable == "extra" || m.rhs.variable == "extra" {
+		return m.evalExtra(extras)
+	}
+
+	lhs, ok := m.lhs.resolve(vars)
+	if !ok {
+		return true
+	}
+	rhs, ok := m.rhs.resolve(vars)
+	if !ok {
+		return true
+	}
+
+	switch m.op {
+	case "in":
+		return strings.Contains(rhs, lhs)
+	case "not in":
+		return !strings.Contains(rhs, lhs)
+	}
+
+	if versionMarkers[m.lhs.variable] {
+		if c, err := semver.PyPI.ParseConstraint(m.op + rhs); err == nil {
+			return c.Match(lhs)
+		}
+	}
+	if versionMarkers[m.rhs.variable] {
+		if c, err := semver.PyPI.ParseConstraint(flipMarkerOp(m.op) + lhs); err == nil {
+			return c.Match(rhs)
+		}
+	}
+
+	return compareMarkerStrings(lhs, m.op, rhs)
+}
+
+// evalExtra evaluates a comparison against the "extra" variable, which holds
+// when any of the requested extras matches.
+func (m markerExpr) evalExtra(extras map[string]bool) bool {
+	name := m.rhs.literal
+	if m.rhs.variable == "extra" {
+		name = m.lhs.literal
+	}
+	requested := extras[NormalizePyPIName(name)]
+
+	switch m.op {
+	case "==", "===":
+		return requested
+	case "!=":
+		return !requested
+	default:
+		return false
+	}
+}
+
+// resolve returns the value, reporting false for variables that are not known.
+func (v markerValue) resolve(vars map[string]string) (string, bool) {
+	if v.variable == "" {
+		return v.literal, true
+	}
+	value, ok := vars[v.variable]
+
+	return value, ok
+}
+
+// flipMarkerOp returns the operator that gives the same result once the
+// operands of op are swapped.
+func flipMarkerOp(op string) string {
+	switch op {
+	case "<":
+		return ">"
+	case "<=":
+		return ">="
+	case ">":
+		return "<"
+	case ">=":
+		return "<="
+	default:
+		return op
+	}
+}
+
+// compareMarkerStrings compares values that are not versions as strings.
+func compareMarkerStrings(lhs, op, rhs string) bool {
+	c := strings.Compare(lhs, rhs)
+	switch op {
+	case "==", "===":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	default:
+		return false
+	}
+}
+
+// parseMarker parses a PEP 508 environment marker, e.g.
+// `python_version < "3.10" and extra == "security"`.
+func parseMarker(raw string) (marker, error) {
+	p := &markerParser{input: raw}
+	m, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("invalid environment marker %q: unexpected %q", raw, p.input[p.pos:])
+	}
+
+	return m, nil
+}
+
+// markerParser is a recursive descent parser for environment markers.
+type markerParser struct {
+	input string
+	pos   int
+}
+
+func (p *markerParser) parseOr() (marker, error) {
+	var or markerOr
+	for {
+		m, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, m)
+		if !p.consumeWord("or") {
+			break
+		}
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+
+	return or, nil
+}
+
+func (p *markerParser) parseAnd() (marker, error) {
+	var and markerAnd
+	for {
+		m, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, m)
+		if !p.consumeWord("and") {
+			break
+		}
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+
+	return and, nil
+}
+
+func (p *markerParser) parseExpr() (marker, error) {
+	p.skipSpace()
+	if strings.HasPrefix(p.input[p.pos:], "(") {
+		p.pos++
+		m, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !strings.HasPrefix(p.input[p.pos:], ")") {
+			return nil, fmt.Errorf("invalid environment marker %q: missing )", p.input)
+		}
+		p.pos++
+
+		return m, nil
+	}
+
+	lhs, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	op, err := p.parseOp()
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	return markerExpr{lhs: lhs, op: op, rhs: rhs}, nil
+}
+
+func (p *markerParser) parseValue() (markerValue, error) {
+	p.skipSpace()
+	rest := p.input[p.pos:]
+	if rest == "" {
+		return markerValue{}, fmt.Errorf("invalid environment marker %q: unexpected end", p.input)
+	}
+
+	if quote := rest[0]; quote == '"' || quote == '\'' {
+		end := strings.IndexByte(rest[1:], quote)
+		if end < 0 {
+			return markerValue{}, fmt.Errorf("invalid environment marker %q: unterminated string", p.input)
+		}
+		p.pos += end + 2
+
+		return markerValue{literal: rest[1 : end+1]}, nil
+	}
+
+	end := strings.IndexFunc(rest, func(r rune) bool {
+		return r != '_' && r != '.' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+	})
+	if end < 0 {
+		end = len(rest)
+	}
+	if end == 0 {
+		return markerValue{}, fmt.Errorf("invalid environment marker %q: unexpected %q", p.input, rest)
+	}
+	p.pos += end
+	// Some old packages still use the pre-PEP 508 dotted names, e.g. "sys.platform"
+	variable := strings.ReplaceAll(rest[:end], ".", "_")
+
+	return markerValue{variable: variable}, nil
+}
+
+func (p *markerParser) parseOp() (string, error) {
+	p.skipSpace()
+	rest := p.input[p.pos:]
+	for _, op := range []string{"===", "==", "!=", "<=", ">=", "~=", "<", ">"} {
+		if strings.HasPrefix(rest, op) {
+			p.pos += len(op)
+			return op, nil
+		}
+	}
+	if p.consumeWord("in") {
+		return "in", nil
+	}
+	if p.consumeWord("not") && p.consumeWord("in") {
+		return "not in", nil
+	}
+
+	return "", fmt.Errorf("invalid environment marker %q: expected an operator at %q", p.input, rest)
+}
+
+// consumeWord consumes word if it is next in the input, followed by a space,
+// quote or parenthesis.
+func (p *markerParser) consumeWord(word string) bool {
+	p.skipSpace()
+	rest := p.input[p.pos:]
+	if !strings.HasPrefix(rest, word) {
+		return false
+	}
+	if len(rest) > len(word) && !strings.ContainsRune(" \t\"'(", rune(rest[len(word)])) {
+		return false
+	}
+	p.pos += len(word)
+
+	return true
+}
+
+func (p *markerParser) skipSpace() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
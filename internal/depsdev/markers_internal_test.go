@@ -0,0 +1,64 @@
+package depsdev
+
+import (
+	"testing"
+)
+
+func Test_parseMarker(t *testing.T) {
+	t.Parallel()
+
+	env := PythonEnvironment{Version: "3.9.2", Platform: "linux"}.variables()
+	extras := map[string]bool{"security": true}
+
+	tests := []struct {
+		marker string
+		want   bool
+	}{
+		{marker: `python_version < "3.10"`, want: true},
+		{marker: `python_version >= "3.10"`, want: false},
+		{marker: `"3.10" > python_version`, want: true},
+		{marker: `python_full_version == "3.9.2"`, want: true},
+		{marker: `sys_platform == "win32"`, want: false},
+		{marker: `platform_system != 'Windows' and os_name == "posix"`, want: true},
+		{marker: `sys_platform == "win32" or python_version < "3.10"`, want: true},
+		{marker: `(sys_platform == "win32" or sys_platform == "darwin") and python_version < "3.10"`, want: false},
+		{marker: `"linux" in sys_platform`, want: true},
+		{marker: `sys_platform not in "win32 cygwin"`, want: true},
+		{marker: `sys.platform == "linux"`, want: true},
+		{marker: `extra == "security"`, want: true},
+		{marker: `extra == "socks"`, want: false},
+		{marker: `extra == "Security" and python_version < "3.10"`, want: true},
+		// The environment does not say what the machine is, so this is assumed to hold.
+		{marker: `platform_machine == "arm64"`, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.marker, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := parseMarker(tt.marker)
+			if err != nil {
+				t.Fatalf("parseMarker() error: %v", err)
+			}
+			if got := m.eval(env, extras); got != tt.want {
+				t.Errorf("parseMarker(%q).eval() = %t, want %t", tt.marker, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseMarker_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, marker := range []string{
+		``,
+		`python_version`,
+		`python_version < "3.10`,
+		`(python_version < "3.10"`,
+		`python_version < "3.10" and`,
+		`python_version < "3.10" extra`,
+	} {
+		if _, err := parseMarker(marker); err == nil {
+			t.Errorf("parseMarker(%q) error = nil, want an error", marker)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package depsdev
+
+// Metadata is attached to the packages added to the inventory by the deps.dev
+// enrichers, recording where they sit in the resolved dependency graph.
+type Metadata struct {
+	// Parents are the packages that directly depend on this package, sorted by
+	// name and version. They are merged across the graphs of every root of the
+	// manifest that reaches the package.
+	Parents []Parent
+
+	// IsTransitive is true if the package is only reached through the
+	// dependencies of other packages, rather than being declared in the manifest.
+	IsTransitive bool
+
+	// IntroducedBy holds a chain of packages for each root of the manifest the
+	// package is reached from. Each chain lists the "name@version" of the
+	// packages on the shortest path from the root, which comes first, to a
+	// direct parent of the package.
+	IntroducedBy [][]string
+
+	// Declared is what the manifest declared for the package, if it is declared
+	// there at a different version than the one it was resolved to.
+	Declared *Declaration
+
+	// Bundled is true if every resolved occurrence of the package is bundled
+	// (vendored) inside another package, so it cannot be upgraded on its own.
+	Bundled bool
+}
+
+// BundledGroup is the dependency group of bundled packages, which can be
+// used to treat them differently in overrides.
+const BundledGroup = "bundled"
+
+// DepGroups returns the dependency groups of the package.
+func (m *Metadata) DepGroups() []string {
+	if m.Bundled {
+		return []string{BundledGroup}
+	}
+
+	return nil
+}
+
+// Declaration describes how a package was declared in a manifest.
+type Declaration struct {
+	Version string
+	// Requirement is the requirement as written in the manifest, e.g. "foo>=1.2".
+	Requirement string
+}
+
+// Parent is an edge of the dependency graph, pointing from a package to one of
+// its dependencies.
+type Parent struct {
+	Name    string
+	Version string
+	// Requirement is the version requirement the parent places on the dependency.
+	Requirement string
+}
@@ -0,0 +1,37 @@
+package depsdev_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scanner/v2/internal/config"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+)
+
+func TestMetadata_DepGroups(t *testing.T) {
+	t.Parallel()
+
+	if got := (&depsdev.Metadata{}).DepGroups(); len(got) != 0 {
+		t.Errorf("DepGroups() = %v, want none", got)
+	}
+
+	bundled := imodels.FromInventory(&extractor.Package{
+		Name:     "vendored",
+		Version:  "1.0.0",
+		PURLType: "pypi",
+		Metadata: &depsdev.Metadata{Bundled: true},
+	})
+	if diff := cmp.Diff([]string{"bundled"}, bundled.DepGroups()); diff != "" {
+		t.Errorf("DepGroups() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Bundled packages can be targeted by package overrides through their group
+	cfg := config.Config{
+		PackageOverrides: []config.PackageOverrideEntry{{Group: depsdev.BundledGroup, Ignore: true}},
+	}
+	if ignore, _ := cfg.ShouldIgnorePackage(bundled); !ignore {
+		t.Errorf("ShouldIgnorePackage() = false, want bundled package to be ignored")
+	}
+}
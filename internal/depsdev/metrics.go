@@ -0,0 +1,137 @@
+package depsdev
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics receives instrumentation events from the deps.dev clients.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// CacheHit is called when a dependency graph is served from the in-memory cache.
+	CacheHit(system string)
+	// CacheMiss is called when a dependency graph is not in the in-memory cache.
+	CacheMiss(system string)
+	// Request is called once a request to deps.dev has completed, err being
+	// the error it failed with, if any.
+	Request(system string, latency time.Duration, err error)
+}
+
+// SystemStats are the counters collected by Stats for a single system.
+type SystemStats struct {
+	Requests    int
+	Errors      int
+	CacheHits   int
+	CacheMisses int
+	Latency     time.Duration
+}
+
+// Stats is a Metrics implementation that aggregates events per system.
+type Stats struct {
+	mu      sync.Mutex
+	systems map[string]*SystemStats
+}
+
+var _ Metrics = &Stats{}
+
+func (s *Stats) update(system string, fn func(*SystemStats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.systems == nil {
+		s.systems = make(map[string]*SystemStats)
+	}
+	st, ok := s.systems[system]
+	if !ok {
+		st = &SystemStats{}
+		s.systems[system] = st
+	}
+	fn(st)
+}
+
+// CacheHit implements Metrics.
+func (s *Stats) CacheHit(system string) {
+	s.update(system, func(st *SystemStats) { st.CacheHits++ })
+}
+
+// CacheMiss implements Metrics.
+func (s *Stats) CacheMiss(system string) {
+	s.update(system, func(st *SystemStats) { st.CacheMisses++ })
+}
+
+// Request implements Metrics.
+func (s *Stats) Request(system string, latency time.Duration, err error) {
+	s.update(system, func(st *SystemStats) {
+		st.Requests++
+		st.Latency += latency
+		if err != nil {
+			st.Errors++
+		}
+	})
+}
+
+// Snapshot returns a copy of the counters collected so far, keyed by system.
+func (s *Stats) Snapshot() map[string]SystemStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make(map[string]SystemStats, len(s.systems))
+	for system, st := range s.systems {
+		out[system] = *st
+	}
+
+	return out
+}
+
+// String summarises the collected counters, one line per system.
+func (s *Stats) String() string {
+	snapshot := s.Snapshot()
+
+	lines := make([]string, 0, len(snapshot))
+	for _, system := range slices.Sorted(maps.Keys(snapshot)) {
+		st := snapshot[system]
+		lines = append(lines, fmt.Sprintf(
+			"%s: %d requests (%d failed) taking %s, %d cache hits, %d cache misses",
+			system, st.Requests, st.Errors, st.Latency.Round(time.Millisecond), st.CacheHits, st.CacheMisses,
+		))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// metricsKey is the context key of the Metrics set with withMetrics.
+type metricsKey struct{}
+
+// withMetrics returns a copy of ctx that makes the clients report the events of
+// the requests made with it to m, rather than to the Metrics they were created
+// with. Pooled clients are shared between scans, so this is how the enrichers
+// report to the Metrics of their own scan. A nil m leaves ctx unchanged.
+func withMetrics(ctx context.Context, m Metrics) context.Context {
+	if m == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, metricsKey{}, m)
+}
+
+// metricsFrom returns the Metrics set on ctx with withMetrics, or fallback if
+// there are none.
+func metricsFrom(ctx context.Context, fallback Metrics) Metrics {
+	if m, ok := ctx.Value(metricsKey{}).(Metrics); ok {
+		return m
+	}
+
+	return fallback
+}
+
+// noopMetrics discards all events.
+type noopMetrics struct{}
+
+func (noopMetrics) CacheHit(string)                      {}
+func (noopMetrics) CacheMiss(string)                     {}
+func (noopMetrics) Request(string, time.Duration, error) {}
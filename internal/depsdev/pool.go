@@ -0,0 +1,46 @@
+package depsdev
+
+import "sync"
+
+// DefaultClientPool is the process-wide pool used by enrichers that are not
+// configured with their own.
+var DefaultClientPool = NewClientPool()
+
+// ClientPool shares deps.dev clients between enrichers, so that every enricher
+// talking to the same endpoint reuses its connections and in-memory cache.
+//
+// Clients are keyed by transport and endpoint; the cache limits of the first
+// config requesting a given endpoint are the ones that apply. Pooled clients
+// have no Metrics of their own, as every enricher reports to its own.
+type ClientPool struct {
+	mu      sync.Mutex
+	clients map[string]DependencyGraphClient
+}
+
+// NewClientPool creates an empty client pool.
+func NewClientPool() *ClientPool {
+	return &ClientPool{
+		clients: make(map[string]DependencyGraphClient),
+	}
+}
+
+// Get returns the pooled client for cfg, creating it if necessary.
+func (p *ClientPool) Get(cfg Config) (DependencyGraphClient, error) {
+	key := cfg.poolKey()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if client, ok := p.clients[key]; ok {
+		return client, nil
+	}
+
+	cfg.Metrics = nil
+	client, err := cfg.newClient()
+	if err != nil {
+		return nil, err
+	}
+	p.clients[key] = client
+
+	return client, nil
+}
@@ -0,0 +1,80 @@
+package depsdev_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/python/requirements"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+)
+
+func TestClientPool_Get(t *testing.T) {
+	t.Parallel()
+
+	pool := depsdev.NewClientPool()
+
+	a, err := pool.Get(depsdev.Config{BaseURL: "https://deps.example.com"})
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	b, err := pool.Get(depsdev.Config{BaseURL: "https://deps.example.com", Parallelism: 2})
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if a != b {
+		t.Error("Get() returned different clients for the same endpoint, want shared client")
+	}
+
+	c, err := pool.Get(depsdev.Config{BaseURL: "https://mirror.example.com"})
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if a == c {
+		t.Error("Get() returned the same client for different endpoints, want separate clients")
+	}
+}
+
+func TestClientPool_Get_Metrics(t *testing.T) {
+	t.Parallel()
+
+	var peak atomic.Int32
+	srv := newFakeDepsDevServer(t, &peak)
+	pool := depsdev.NewClientPool()
+
+	// Two scans sharing a pooled client each report their own requests.
+	for i := range 2 {
+		stats := &depsdev.Stats{}
+		enr, err := depsdev.NewPyPIDepsDevEnricher(depsdev.Config{
+			BaseURL: srv.URL,
+			Metrics: stats,
+			Pool:    pool,
+		})
+		if err != nil {
+			t.Fatalf("NewPyPIDepsDevEnricher() error: %v", err)
+		}
+
+		inv := &inventory.Inventory{Packages: []*extractor.Package{{
+			Name:      "requests",
+			Version:   "1.0.0",
+			Locations: []string{"requirements.txt"},
+			Plugins:   []string{requirements.Name},
+		}}}
+		if err := enr.Enrich(t.Context(), nil, inv); err != nil {
+			t.Fatalf("Enrich() error: %v", err)
+		}
+
+		// The first scan fetches the graph, and the second is served from the
+		// cache of the shared client.
+		want := depsdev.SystemStats{Requests: 1, CacheMisses: 1}
+		if i == 1 {
+			want = depsdev.SystemStats{CacheHits: 1}
+		}
+		got := stats.Snapshot()["pypi"]
+		got.Latency = 0
+		if got != want {
+			t.Errorf("scan %d: stats = %+v, want %+v", i, got, want)
+		}
+	}
+}
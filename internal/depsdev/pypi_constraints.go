@@ -0,0 +1,117 @@
+package depsdev
+
+import (
+	"bufio"
+	"io/fs"
+	"path"
+	"strings"
+
+	"deps.dev/util/pypi"
+	"github.com/google/osv-scalibr/log"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+)
+
+// requirementsFile is a file to read constraints from, and whether it is a
+// constraints file given with -c rather than a requirements file.
+type requirementsFile struct {
+	path        string
+	constraints bool
+}
+
+// requirementsConstraints returns the constraints that the requirements file
+// at manifest, or any file it includes with -r, applies with -c, keyed by the
+// PEP 503 normalized name of the package they constrain. Constraints given
+// for the same package more than once are combined.
+//
+// The requirements extractor skips -c, so the files are read again here.
+func requirementsConstraints(fsys fs.FS, manifest string) map[string]string {
+	constraints := make(map[string]string)
+	if fsys == nil {
+		return constraints
+	}
+
+	queue := []requirementsFile{{path: manifest}}
+	seen := map[string]bool{manifest: true}
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+
+		lines, err := readRequirementsLines(fsys, file.path)
+		if err != nil {
+			log.Warnf("PyPI: failed to read constraints from %s: %v", file.path, err)
+			continue
+		}
+		for _, line := range lines {
+			if include, isConstraints, ok := includeOption(line); ok {
+				include = path.Join(path.Dir(file.path), include)
+				if !seen[include] {
+					seen[include] = true
+					queue = append(queue, requirementsFile{path: include, constraints: file.constraints || isConstraints})
+				}
+
+				continue
+			}
+			if !file.constraints || strings.HasPrefix(line, "-") {
+				continue
+			}
+
+			dep, err := pypi.ParseDependency(line)
+			if err != nil || dep.Constraint == "" {
+				continue
+			}
+			name := NormalizePyPIName(dep.Name)
+			constraints[name] = joinConstraints(constraints[name], dep.Constraint)
+		}
+	}
+
+	return constraints
+}
+
+// readRequirementsLines returns the lines of a requirements or constraints
+// file, with comments removed and continued lines joined.
+func readRequirementsLines(fsys fs.FS, name string) ([]string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	var continued strings.Builder
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := cachedregexp.MustCompile(`(^|\s+)#.*$`).ReplaceAllString(s.Text(), "")
+		if before, ok := strings.CutSuffix(line, `\`); ok {
+			continued.WriteString(before)
+			continue
+		}
+		continued.WriteString(line)
+		if line := strings.TrimSpace(continued.String()); line != "" {
+			lines = append(lines, line)
+		}
+		continued.Reset()
+	}
+
+	return lines, s.Err()
+}
+
+// includeOption returns the file given by a -r or -c option, and whether it
+// is a constraints file.
+func includeOption(line string) (string, bool, bool) {
+	m := cachedregexp.MustCompile(`^(-r|--requirement|-c|--constraint)(?:\s*=\s*|\s*)(\S+)$`).FindStringSubmatch(line)
+	if m == nil {
+		return "", false, false
+	}
+
+	return m[2], m[1] == "-c" || m[1] == "--constraint", true
+}
+
+// joinConstraints combines two PEP 440 version constraints into one that is
+// only satisfied by versions satisfying both.
+func joinConstraints(a, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+
+	return a + "," + b
+}
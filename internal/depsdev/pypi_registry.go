@@ -0,0 +1,241 @@
+package depsdev
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"deps.dev/util/pypi"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/python/requirements"
+)
+
+// DefaultPyPIRegistryURL is the PyPI JSON API used to resolve requirements
+// that are not pinned to a single version.
+const DefaultPyPIRegistryURL = "https://pypi.org/pypi"
+
+// ErrNotOnPyPI is returned when PyPI does not know about a package or version,
+// e.g. because it is published on a private index.
+var ErrNotOnPyPI = errors.New("package not found on PyPI")
+
+// PyPIRegistryClient fetches package metadata from the PyPI JSON API.
+type PyPIRegistryClient struct {
+	client    *http.Client
+	baseURL   string
+	userAgent string
+
+	mu       sync.Mutex
+	projects map[string]*pypiProject
+	group    coalesceGroup
+}
+
+// NewPyPIRegistryClient creates a new client for the PyPI JSON API at baseURL,
+// defaulting to DefaultPyPIRegistryURL. The headers in opts are meant for
+// deps.dev, so they are not sent to the registry.
+func NewPyPIRegistryClient(baseURL string, opts ClientOptions) *PyPIRegistryClient {
+	if baseURL == "" {
+		baseURL = DefaultPyPIRegistryURL
+	}
+	client := opts.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &PyPIRegistryClient{
+		client:    client,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		userAgent: opts.UserAgent,
+		projects:  make(map[string]*pypiProject),
+	}
+}
+
+// pypiProject is the subset of the PyPI JSON API project and release
+// responses used here.
+type pypiProject struct {
+	Info struct {
+		RequiresDist []string `json:"requires_dist"`
+		Yanked       bool     `json:"yanked"`
+		YankedReason string   `json:"yanked_reason"`
+	} `json:"info"`
+	Releases map[string][]struct {
+		Yanked bool `json:"yanked"`
+	} `json:"releases"`
+	// URLs are the files of a release, in the responses for a version.
+	URLs []struct {
+		Digests map[string]string `json:"digests"`
+	} `json:"urls"`
+}
+
+// Releases returns the versions of a package that have at least one file
+// that has not been yanked.
+func (c *PyPIRegistryClient) Releases(ctx context.Context, name string) ([]string, error) {
+	project, err := c.get(ctx, url.PathEscape(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []string
+	for version, files := range project.Releases {
+		for _, file := range files {
+			if !file.Yanked {
+				releases = append(releases, version)
+				break
+			}
+		}
+	}
+
+	return releases, nil
+}
+
+// RequiresDist returns the requirements declared by a package version,
+// including those that only apply to some extras or environments.
+func (c *PyPIRegistryClient) RequiresDist(ctx context.Context, name, version string) ([]string, error) {
+	project, err := c.get(ctx, url.PathEscape(name)+"/"+url.PathEscape(version))
+	if err != nil {
+		return nil, err
+	}
+
+	return project.Info.RequiresDist, nil
+}
+
+// Yanked reports whether a package version has been yanked, along with the
+// reason given for it, if any.
+func (c *PyPIRegistryClient) Yanked(ctx context.Context, name, version string) (bool, string, error) {
+	project, err := c.get(ctx, url.PathEscape(name)+"/"+url.PathEscape(version))
+	if err != nil {
+		return false, "", err
+	}
+
+	return project.Info.Yanked, project.Info.YankedReason, nil
+}
+
+// Digests returns the digests of the files published for a package version,
+// in the "<algorithm>:<hex digest>" form that requirements files pin them with.
+func (c *PyPIRegistryClient) Digests(ctx context.Context, name, version string) ([]string, error) {
+	project, err := c.get(ctx, url.PathEscape(name)+"/"+url.PathEscape(version))
+	if err != nil {
+		return nil, err
+	}
+
+	var digests []string
+	for _, file := range project.URLs {
+		for algorithm, digest := range file.Digests {
+			digests = append(digests, algorithm+":"+strings.ToLower(digest))
+		}
+	}
+
+	return digests, nil
+}
+
+// get returns the JSON API response for path, caching it for later calls.
+func (c *PyPIRegistryClient) get(ctx context.Context, path string) (*pypiProject, error) {
+	c.mu.Lock()
+	project, ok := c.projects[path]
+	c.mu.Unlock()
+	if ok {
+		return project, nil
+	}
+
+	v, err := coalesce(ctx, &c.group, path, func(ctx context.Context) (any, error) {
+		project, err := c.fetch(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.projects[path] = project
+		c.mu.Unlock()
+
+		return project, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*pypiProject), nil
+}
+
+// fetch performs the HTTP request for a JSON API path.
+func (c *PyPIRegistryClient) fetch(ctx context.Context, path string) (*pypiProject, error) {
+	reqURL := fmt.Sprintf("%s/%s/json", c.baseURL, path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("PyPI request failed for %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrNotOnPyPI, path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("PyPI returned %d for %s: %s", resp.StatusCode, path, string(body))
+	}
+
+	var project pypiProject
+	if err := json.NewDecoder(resp.Body).Decode(&project); err != nil {
+		return nil, fmt.Errorf("failed to decode PyPI response for %s: %w", path, err)
+	}
+
+	return &project, nil
+}
+
+// unpinnedConstraint returns the version constraint of a package declared in a
+// requirements file, if it does not pin the package to a single version.
+func unpinnedConstraint(pkg *extractor.Package) (string, bool) {
+	md, ok := pkg.Metadata.(*requirements.Metadata)
+	if !ok || md.Requirement == "" {
+		return "", false
+	}
+	if (md.VersionComparator == "==" || md.VersionComparator == "===") &&
+		!strings.ContainsAny(pkg.Version, "*,") {
+		return "", false
+	}
+
+	dep, err := pypi.ParseDependency(md.Requirement)
+	if err != nil {
+		return "", false
+	}
+
+	return dep.Constraint, true
+}
+
+// bestVersion returns the highest of versions satisfying the PEP 440
+// constraint, or "" if none do. Pre-releases are only picked if the
+// constraint asks for them.
+func bestVersion(versions []string, constraint string) (string, error) {
+	c, err := semver.PyPI.ParseConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+
+	var best string
+	var bestParsed *semver.Version
+	for _, version := range versions {
+		v, err := semver.PyPI.Parse(version)
+		if err != nil || !c.MatchVersion(v) {
+			continue
+		}
+		if bestParsed == nil || v.Compare(bestParsed) > 0 {
+			best, bestParsed = version, v
+		}
+	}
+
+	return best, nil
+}
@@ -0,0 +1,152 @@
+package depsdev
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/python/requirements"
+)
+
+func Test_bestVersion(t *testing.T) {
+	t.Parallel()
+
+	versions := []string{"1.0", "1.2.3", "1.10", "2.0a1", "2.0"}
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{constraint: "", want: "2.0"},
+		{constraint: ">=1.0", want: "2.0"},
+		{constraint: ">=1.0,<2", want: "1.10"},
+		{constraint: "~=1.2", want: "1.10"},
+		{constraint: "==1.2.*", want: "1.2.3"},
+		{constraint: ">=2.0a1,<2.0", want: "2.0a1"},
+		{constraint: ">3", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := bestVersion(versions, tt.constraint)
+			if err != nil {
+				t.Fatalf("bestVersion() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("bestVersion(%q) = %q, want %q", tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_unpinnedConstraint(t *testing.T) {
+	t.Parallel()
+
+	pkg := func(version, requirement, comparator string) *extractor.Package {
+		return &extractor.Package{
+			Name:     "foo",
+			Version:  version,
+			Metadata: &requirements.Metadata{Requirement: requirement, VersionComparator: comparator},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		pkg    *extractor.Package
+		want   string
+		wantOK bool
+	}{
+		{name: "pinned", pkg: pkg("1.0", "foo==1.0", "==")},
+		{name: "wildcard", pkg: pkg("1.*", "foo==1.*", "=="), want: "==1.*", wantOK: true},
+		{name: "lower_bound", pkg: pkg("1.0", "foo>=1.0", ">="), want: ">=1.0", wantOK: true},
+		{name: "no_version", pkg: pkg("", "foo", ""), want: "", wantOK: true},
+		{name: "marker", pkg: pkg("1.0", `foo~=1.0; python_version < "3.10"`, "~="), want: "~=1.0", wantOK: true},
+		{name: "not_from_requirements", pkg: &extractor.Package{Name: "foo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := unpinnedConstraint(tt.pkg)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("unpinnedConstraint() = %q, %t, want %q, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPyPIRegistryClient_Digests(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/requests/2.31.0/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"info": {"requires_dist": []},
+			"urls": [
+				{"filename": "requests-2.31.0-py3-none-any.whl", "digests": {"sha256": "58CD2187"}},
+				{"filename": "requests-2.31.0.tar.gz", "digests": {"md5": "3e0ec9a5", "sha256": "942c5a75"}}
+			]
+		}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewPyPIRegistryClient(srv.URL, ClientOptions{})
+
+	got, err := client.Digests(t.Context(), "requests", "2.31.0")
+	if err != nil {
+		t.Fatalf("Digests() error: %v", err)
+	}
+	slices.Sort(got)
+	want := []string{"md5:3e0ec9a5", "sha256:58cd2187", "sha256:942c5a75"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Digests() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := client.Digests(t.Context(), "internal-lib", "1.0.0"); !errors.Is(err, ErrNotOnPyPI) {
+		t.Errorf("Digests() of an unknown package error = %v, want %v", err, ErrNotOnPyPI)
+	}
+}
+
+func TestPyPIRegistryClient_CancelledCaller(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	aborted := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+		close(aborted)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewPyPIRegistryClient(srv.URL, ClientOptions{})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	errs := make(chan error)
+	go func() {
+		_, err := client.Releases(ctx, "requests")
+		errs <- err
+	}()
+	<-started
+
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("Releases() error = %v, want %v", err, context.Canceled)
+	}
+
+	// The lookup is abandoned once no caller is waiting on it.
+	select {
+	case <-aborted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request not cancelled once its only caller gave up")
+	}
+}
@@ -0,0 +1,124 @@
+// Package epss looks up Exploit Prediction Scoring System (EPSS) scores of
+// CVEs from the FIRST EPSS API.
+package epss
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// DefaultBaseURL is the FIRST EPSS API.
+const DefaultBaseURL = "https://api.first.org/data/v1/epss"
+
+// batchSize is the number of CVEs looked up per request, which is also the
+// default page size of the API.
+const batchSize = 100
+
+// Client fetches EPSS scores from the FIRST EPSS API.
+type Client struct {
+	client    *http.Client
+	baseURL   string
+	userAgent string
+}
+
+// NewClient creates a new client for the EPSS API at baseURL, defaulting to
+// DefaultBaseURL.
+func NewClient(baseURL string, httpClient *http.Client, userAgent string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		client:    httpClient,
+		baseURL:   baseURL,
+		userAgent: userAgent,
+	}
+}
+
+// epssResponse is the subset of the EPSS API response used here. Scores are
+// given as decimal strings.
+type epssResponse struct {
+	Data []struct {
+		CVE        string `json:"cve"`
+		EPSS       string `json:"epss"`
+		Percentile string `json:"percentile"`
+		Date       string `json:"date"`
+	} `json:"data"`
+}
+
+// Scores returns the EPSS score of each of the given CVEs, keyed by CVE ID.
+// CVEs the API has no score for are left out.
+func (c *Client) Scores(ctx context.Context, cves []string) (map[string]models.EPSSScore, error) {
+	scores := make(map[string]models.EPSSScore, len(cves))
+
+	for start := 0; start < len(cves); start += batchSize {
+		batch := cves[start:min(start+batchSize, len(cves))]
+		resp, err := c.fetch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range resp.Data {
+			probability, err := strconv.ParseFloat(d.EPSS, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid EPSS score %q for %s: %w", d.EPSS, d.CVE, err)
+			}
+			percentile, err := strconv.ParseFloat(d.Percentile, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid EPSS percentile %q for %s: %w", d.Percentile, d.CVE, err)
+			}
+			scores[d.CVE] = models.EPSSScore{
+				Probability: probability,
+				Percentile:  percentile,
+				Date:        d.Date,
+			}
+		}
+	}
+
+	return scores, nil
+}
+
+// fetch performs the HTTP request for a batch of CVEs.
+func (c *Client) fetch(ctx context.Context, cves []string) (*epssResponse, error) {
+	query := url.Values{}
+	query.Set("cve", strings.Join(cves, ","))
+	reqURL := c.baseURL + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("EPSS request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("EPSS API returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result epssResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode EPSS response: %w", err)
+	}
+
+	return &result, nil
+}
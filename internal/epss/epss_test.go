@@ -0,0 +1,90 @@
+package epss_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/epss"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func TestClient_Scores(t *testing.T) {
+	t.Parallel()
+
+	var gotQuery, gotUserAgent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query().Get("cve")
+		gotUserAgent = r.Header.Get("User-Agent")
+		fmt.Fprint(w, `{"status":"OK","data":[
+			{"cve":"CVE-2021-44228","epss":"0.944240000","percentile":"0.999950000","date":"2026-10-15"},
+			{"cve":"CVE-2020-0001","epss":"0.000430000","percentile":"0.120000000","date":"2026-10-15"}
+		]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := epss.NewClient(srv.URL, nil, "osv-scanner-test")
+	got, err := c.Scores(t.Context(), []string{"CVE-2021-44228", "CVE-2020-0001", "CVE-2099-0001"})
+	if err != nil {
+		t.Fatalf("Scores() error: %v", err)
+	}
+
+	want := map[string]models.EPSSScore{
+		"CVE-2021-44228": {Probability: 0.94424, Percentile: 0.99995, Date: "2026-10-15"},
+		"CVE-2020-0001":  {Probability: 0.00043, Percentile: 0.12, Date: "2026-10-15"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Scores() mismatch (-want +got):\n%s", diff)
+	}
+	if gotQuery != "CVE-2021-44228,CVE-2020-0001,CVE-2099-0001" {
+		t.Errorf("cve query = %q", gotQuery)
+	}
+	if gotUserAgent != "osv-scanner-test" {
+		t.Errorf("User-Agent = %q, want %q", gotUserAgent, "osv-scanner-test")
+	}
+}
+
+func TestClient_Scores_Batched(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if n := len(strings.Split(r.URL.Query().Get("cve"), ",")); n > 100 {
+			t.Errorf("request asked for %d CVEs, want at most 100", n)
+		}
+		fmt.Fprint(w, `{"data":[]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	cves := make([]string, 250)
+	for i := range cves {
+		cves[i] = fmt.Sprintf("CVE-2026-%04d", i)
+	}
+
+	c := epss.NewClient(srv.URL, nil, "")
+	if _, err := c.Scores(t.Context(), cves); err != nil {
+		t.Fatalf("Scores() error: %v", err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("server received %d requests, want 3", got)
+	}
+}
+
+func TestClient_Scores_Error(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := epss.NewClient(srv.URL, nil, "")
+	if _, err := c.Scores(t.Context(), []string{"CVE-2021-44228"}); err == nil {
+		t.Errorf("Scores() error = nil, want an error")
+	}
+}
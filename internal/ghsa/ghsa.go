@@ -0,0 +1,156 @@
+// Package ghsa looks up advisories from the GitHub Advisory Database API.
+package ghsa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// DefaultBaseURL is the GitHub REST API.
+const DefaultBaseURL = "https://api.github.com"
+
+// Client fetches advisories from the GitHub Advisory Database.
+type Client struct {
+	client    *http.Client
+	baseURL   string
+	userAgent string
+	token     string
+}
+
+// NewClient creates a new client for the GitHub API at baseURL, defaulting to
+// DefaultBaseURL. The token is optional, but without one GitHub only allows
+// a handful of requests an hour.
+func NewClient(baseURL string, httpClient *http.Client, userAgent string, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		client:    httpClient,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		userAgent: userAgent,
+		token:     token,
+	}
+}
+
+// cvss is a CVSS vector of an advisory, which is empty if it has none.
+type cvss struct {
+	VectorString string `json:"vector_string"`
+}
+
+// advisory is the subset of a global security advisory used here.
+type advisory struct {
+	GHSAID         string `json:"ghsa_id"`
+	Summary        string `json:"summary"`
+	Severity       string `json:"severity"`
+	CVSSSeverities struct {
+		CVSSV3 cvss `json:"cvss_v3"`
+		CVSSV4 cvss `json:"cvss_v4"`
+	} `json:"cvss_severities"`
+}
+
+// Advisory returns the GitHub advisory with the given GHSA ID, or the one for
+// the given CVE ID, as an OSV record with only the ID, summary and severity
+// set. It returns nil if GitHub has no such advisory, or the ID is neither a
+// GHSA nor a CVE ID.
+func (c *Client) Advisory(ctx context.Context, id string) (*osvschema.Vulnerability, error) {
+	var reqURL string
+	switch {
+	case strings.HasPrefix(id, "GHSA-"):
+		reqURL = c.baseURL + "/advisories/" + url.PathEscape(id)
+	case strings.HasPrefix(id, "CVE-"):
+		reqURL = c.baseURL + "/advisories?" + url.Values{"cve_id": {id}}.Encode()
+	default:
+		return nil, nil
+	}
+
+	body, err := c.get(ctx, reqURL)
+	if err != nil || body == nil {
+		return nil, err
+	}
+
+	var adv advisory
+	if strings.HasPrefix(id, "GHSA-") {
+		err = json.Unmarshal(body, &adv)
+	} else {
+		var advs []advisory
+		err = json.Unmarshal(body, &advs)
+		if len(advs) == 0 {
+			return nil, err
+		}
+		adv = advs[0]
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode GitHub advisory %s: %w", id, err)
+	}
+
+	return toOSV(adv), nil
+}
+
+// get performs a request to the API, returning nil if nothing was found.
+func (c *Client) get(ctx context.Context, reqURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GitHub advisory request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GitHub advisory response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
+func toOSV(adv advisory) *osvschema.Vulnerability {
+	vuln := &osvschema.Vulnerability{
+		Id:      adv.GHSAID,
+		Summary: adv.Summary,
+	}
+	if v := adv.CVSSSeverities.CVSSV4.VectorString; v != "" {
+		vuln.Severity = append(vuln.Severity, &osvschema.Severity{Type: osvschema.Severity_CVSS_V4, Score: v})
+	}
+	if v := adv.CVSSSeverities.CVSSV3.VectorString; v != "" {
+		vuln.Severity = append(vuln.Severity, &osvschema.Severity{Type: osvschema.Severity_CVSS_V3, Score: v})
+	}
+	// GitHub gives a severity to advisories it has not scored, which is
+	// "unknown" if it could not be determined at all.
+	if adv.Severity != "" && adv.Severity != "unknown" {
+		vuln.DatabaseSpecific = &structpb.Struct{Fields: map[string]*structpb.Value{
+			"severity": structpb.NewStringValue(strings.ToUpper(adv.Severity)),
+		}}
+	}
+
+	return vuln
+}
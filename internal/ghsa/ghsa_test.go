@@ -0,0 +1,108 @@
+package ghsa_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/ghsa"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestClient_Advisory(t *testing.T) {
+	t.Parallel()
+
+	var gotAuthorization string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthorization = r.Header.Get("Authorization")
+		switch {
+		case r.URL.Path == "/advisories/GHSA-jfh8-c2jp-5v3q":
+			fmt.Fprint(w, `{
+				"ghsa_id": "GHSA-jfh8-c2jp-5v3q",
+				"summary": "Remote code injection in Log4j",
+				"severity": "critical",
+				"cvss_severities": {
+					"cvss_v3": {"vector_string": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", "score": 10.0},
+					"cvss_v4": {"vector_string": null, "score": 0.0}
+				}
+			}`)
+		case r.URL.Path == "/advisories" && r.URL.Query().Get("cve_id") == "CVE-2024-0001":
+			fmt.Fprint(w, `[{
+				"ghsa_id": "GHSA-aaaa-bbbb-cccc",
+				"summary": "Denial of service",
+				"severity": "moderate",
+				"cvss_severities": {"cvss_v3": {"vector_string": null}, "cvss_v4": {"vector_string": null}}
+			}]`)
+		case r.URL.Path == "/advisories":
+			fmt.Fprint(w, `[]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c := ghsa.NewClient(srv.URL, nil, "osv-scanner-test", "secret")
+
+	tests := []struct {
+		id   string
+		want *osvschema.Vulnerability
+	}{
+		{
+			id: "GHSA-jfh8-c2jp-5v3q",
+			want: &osvschema.Vulnerability{
+				Id:      "GHSA-jfh8-c2jp-5v3q",
+				Summary: "Remote code injection in Log4j",
+				Severity: []*osvschema.Severity{
+					{Type: osvschema.Severity_CVSS_V3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H"},
+				},
+				DatabaseSpecific: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"severity": structpb.NewStringValue("CRITICAL"),
+				}},
+			},
+		},
+		{
+			id: "CVE-2024-0001",
+			want: &osvschema.Vulnerability{
+				Id:      "GHSA-aaaa-bbbb-cccc",
+				Summary: "Denial of service",
+				DatabaseSpecific: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"severity": structpb.NewStringValue("MODERATE"),
+				}},
+			},
+		},
+		{id: "CVE-2024-9999", want: nil},
+		{id: "GHSA-xxxx-xxxx-xxxx", want: nil},
+		{id: "PYSEC-2024-1", want: nil},
+	}
+	for _, tt := range tests {
+		got, err := c.Advisory(t.Context(), tt.id)
+		if err != nil {
+			t.Fatalf("Advisory(%q) error: %v", tt.id, err)
+		}
+		if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("Advisory(%q) mismatch (-want +got):\n%s", tt.id, diff)
+		}
+	}
+
+	if gotAuthorization != "Bearer secret" {
+		t.Errorf("Authorization = %q, want %q", gotAuthorization, "Bearer secret")
+	}
+}
+
+func TestClient_Advisory_Error(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "API rate limit exceeded", http.StatusForbidden)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := ghsa.NewClient(srv.URL, nil, "", "")
+	if _, err := c.Advisory(t.Context(), "GHSA-jfh8-c2jp-5v3q"); err == nil {
+		t.Errorf("Advisory() error = nil, want an error")
+	}
+}
@@ -0,0 +1,258 @@
+// Package gitfs provides a read-only filesystem of the files at a commit of a
+// git repository, so they can be scanned without being checked out.
+package gitfs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	scalibrfs "github.com/google/osv-scalibr/fs"
+)
+
+// FS is the tree of a commit, in which the files have the time of the commit.
+type FS struct {
+	tree    *object.Tree
+	modTime time.Time
+}
+
+// Open opens the repository at repoPath, which may be bare, and returns the
+// filesystem of the commit that ref resolves to, along with the commit hash.
+// ref can be anything that git rev-parse accepts, such as a branch, a tag or
+// a commit hash.
+func Open(repoPath string, ref string) (*FS, string, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open git repository %s: %w", repoPath, err)
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve %q in %s: %w", ref, repoPath, err)
+	}
+
+	// annotated tags are peeled by ResolveRevision, so this is always a commit
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read commit %s in %s: %w", hash, repoPath, err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read the tree of commit %s in %s: %w", hash, repoPath, err)
+	}
+
+	return &FS{tree: tree, modTime: commit.Committer.When}, hash.String(), nil
+}
+
+// Open opens the named file or directory.
+func (f *FS) Open(name string) (fs.File, error) {
+	entry, err := f.entry("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Mode == filemode.Dir {
+		tree, err := f.subtree(name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+
+		return &dir{info: f.info(entry), fs: f, name: name, tree: tree}, nil
+	}
+
+	blob, err := f.tree.TreeEntryFile(&entry)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	r, err := blob.Reader()
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return &file{Reader: bytes.NewReader(content), info: f.info(entry)}, nil
+}
+
+// ReadDir reads the named directory, returning its entries sorted by name.
+// Submodules are left out, as their files are not in the repository.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entry, err := f.entry("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Mode != filemode.Dir {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+
+	tree, err := f.subtree(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+
+	return f.dirEntries(tree), nil
+}
+
+// Stat returns the FileInfo of the named file or directory.
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	entry, err := f.entry("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.info(entry), nil
+}
+
+// entry returns the tree entry of the named file or directory, with the root
+// being a directory entry named ".".
+func (f *FS) entry(op string, name string) (object.TreeEntry, error) {
+	if !fs.ValidPath(name) {
+		return object.TreeEntry{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return object.TreeEntry{Name: ".", Mode: filemode.Dir, Hash: f.tree.Hash}, nil
+	}
+
+	entry, err := f.tree.FindEntry(name)
+	if err != nil || entry.Mode == filemode.Submodule {
+		return object.TreeEntry{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	return *entry, nil
+}
+
+func (f *FS) subtree(name string) (*object.Tree, error) {
+	if name == "." {
+		return f.tree, nil
+	}
+
+	return f.tree.Tree(name)
+}
+
+func (f *FS) dirEntries(tree *object.Tree) []fs.DirEntry {
+	entries := make([]fs.DirEntry, 0, len(tree.Entries))
+	for _, e := range tree.Entries {
+		if e.Mode == filemode.Submodule {
+			continue
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(f.infoIn(tree, e)))
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return entries
+}
+
+func (f *FS) info(entry object.TreeEntry) *fileInfo {
+	return f.infoIn(f.tree, entry)
+}
+
+// infoIn returns the FileInfo of an entry of tree, reading the size of files
+// from their blob.
+func (f *FS) infoIn(tree *object.Tree, entry object.TreeEntry) *fileInfo {
+	info := &fileInfo{name: path.Base(entry.Name), modTime: f.modTime}
+
+	switch entry.Mode {
+	case filemode.Dir:
+		info.mode = fs.ModeDir | 0o555
+	case filemode.Symlink:
+		info.mode = fs.ModeSymlink | 0o777
+	case filemode.Executable:
+		info.mode = 0o555
+	default:
+		info.mode = 0o444
+	}
+
+	if !info.mode.IsDir() {
+		if blob, err := tree.TreeEntryFile(&entry); err == nil {
+			info.size = blob.Size
+		}
+	}
+
+	return info
+}
+
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) Mode() fs.FileMode  { return i.mode }
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *fileInfo) Sys() any           { return nil }
+
+// file is an opened file, whose content has been read so that it can be read
+// at any offset, as scalibr requires.
+type file struct {
+	*bytes.Reader
+
+	info *fileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *file) Close() error               { return nil }
+
+type dir struct {
+	info *fileInfo
+	fs   *FS
+	name string
+	tree *object.Tree
+
+	entries []fs.DirEntry
+	read    bool
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *dir) Close() error               { return nil }
+
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir reads the entries of the directory as fs.ReadDirFile specifies.
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		d.entries = d.fs.dirEntries(d.tree)
+		d.read = true
+	}
+
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+
+	return entries, nil
+}
+
+var _ scalibrfs.FS = &FS{}
+var _ fs.ReadDirFile = &dir{}
@@ -0,0 +1,122 @@
+package gitfs_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/osv-scanner/v2/internal/gitfs"
+)
+
+// commit writes files to the worktree of repo and commits them.
+func commit(t *testing.T, repo *git.Repository, dir string, files map[string]string) {
+	t.Helper()
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = wt.Commit("commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(0, 0)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setupRepo creates a repository with a "v1" tag, followed by a commit that
+// changes its files.
+func setupRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit(t, repo, dir, map[string]string{
+		"go.mod":                    "module example.com/v1\n",
+		"web/package-lock.json":     "{}\n",
+		"web/nested/poetry.lock":    "",
+		"services/api/Gemfile.lock": "",
+	})
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CreateTag("v1", head.Hash(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	commit(t, repo, dir, map[string]string{
+		"go.mod":     "module example.com/v2\n",
+		"Cargo.lock": "",
+	})
+
+	return dir
+}
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	dir := setupRepo(t)
+
+	bare := t.TempDir()
+	if _, err := git.PlainClone(bare, true, &git.CloneOptions{URL: dir, Tags: git.AllTags}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, repo := range []string{dir, bare} {
+		gitFS, _, err := gitfs.Open(repo, "v1")
+		if err != nil {
+			t.Fatalf("Open(%q) error = %v", repo, err)
+		}
+
+		if err := fstest.TestFS(gitFS, "go.mod", "web/package-lock.json", "web/nested/poetry.lock", "services/api/Gemfile.lock"); err != nil {
+			t.Errorf("Open(%q) is not a valid filesystem: %v", repo, err)
+		}
+
+		content, err := fs.ReadFile(gitFS, "go.mod")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := string(content), "module example.com/v1\n"; got != want {
+			t.Errorf("go.mod at v1 of %q = %q, want %q", repo, got, want)
+		}
+
+		if _, err := gitFS.Stat("Cargo.lock"); !os.IsNotExist(err) {
+			t.Errorf("Stat(Cargo.lock) at v1 of %q error = %v, want it to not exist", repo, err)
+		}
+	}
+}
+
+func TestOpen_UnknownRef(t *testing.T) {
+	t.Parallel()
+
+	dir := setupRepo(t)
+
+	if _, _, err := gitfs.Open(dir, "v2"); err == nil {
+		t.Errorf("Open() error = nil, want an error for an unknown ref")
+	}
+	if _, _, err := gitfs.Open(t.TempDir(), "v1"); err == nil {
+		t.Errorf("Open() error = nil, want an error for a directory that is not a repository")
+	}
+}
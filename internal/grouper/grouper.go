@@ -21,23 +21,48 @@ func hasAliasIntersection(v1, v2 IDAliases) bool {
 	return slices.Contains(v1.Aliases, v2.ID) || slices.Contains(v2.Aliases, v1.ID)
 }
 
-// Group groups vulnerabilities by aliases.
-func Group(vulns []IDAliases) []models.GroupInfo {
-	// Mapping of `vulns` index to a group ID. A group ID is just another index in the `vulns` slice.
-	groups := make([]int, len(vulns))
+// unionFind tracks which of a set of items have been merged into the same group.
+type unionFind []int
 
-	// Initially make every vulnerability its own group.
-	for i := range vulns {
-		groups[i] = i
+func newUnionFind(n int) unionFind {
+	// Initially make every item its own group.
+	uf := make(unionFind, n)
+	for i := range uf {
+		uf[i] = i
+	}
+
+	return uf
+}
+
+// find returns the representative of the group of item i, which is the
+// smallest index in the group.
+func (uf unionFind) find(i int) int {
+	for uf[i] != i {
+		uf[i] = uf[uf[i]]
+		i = uf[i]
 	}
 
+	return i
+}
+
+func (uf unionFind) union(i, j int) {
+	ri, rj := uf.find(i), uf.find(j)
+	// Use the smaller index as the representative ID.
+	uf[max(ri, rj)] = min(ri, rj)
+}
+
+// Group groups vulnerabilities by aliases.
+//
+// Grouping is transitive: if A is an alias of B, and B of C, then A, B and C
+// are in the same group even though A and C have no aliases in common.
+func Group(vulns []IDAliases) []models.GroupInfo {
+	groups := newUnionFind(len(vulns))
+
 	// Do a pair-wise (n^2) comparison and merge all intersecting vulns.
 	for i := range vulns {
 		for j := i + 1; j < len(vulns); j++ {
 			if hasAliasIntersection(vulns[i], vulns[j]) {
-				// Merge the two groups. Use the smaller index as the representative ID.
-				groups[i] = min(groups[i], groups[j])
-				groups[j] = groups[i]
+				groups.union(i, j)
 			}
 		}
 	}
@@ -45,11 +70,55 @@ func Group(vulns []IDAliases) []models.GroupInfo {
 	// Extract groups into the final result structure.
 	extractedGroups := map[int][]string{}
 	extractedAliases := map[int][]string{}
-	for i, gid := range groups {
+	for i := range vulns {
+		gid := groups.find(i)
 		extractedGroups[gid] = append(extractedGroups[gid], vulns[i].ID)
+		// Add IDs to aliases
+		extractedAliases[gid] = append(extractedAliases[gid], vulns[i].ID)
 		extractedAliases[gid] = append(extractedAliases[gid], vulns[i].Aliases...)
 	}
 
+	return buildGroups(extractedGroups, extractedAliases)
+}
+
+// Merge merges groups of vulnerabilities that have an ID or alias in common,
+// such as the groups found for the same vulnerability in different packages,
+// which can be known by different IDs in each ecosystem.
+//
+// Each merged group has all the IDs and aliases of the groups it is made of.
+func Merge(groups []models.GroupInfo) []models.GroupInfo {
+	merged := newUnionFind(len(groups))
+
+	for i := range groups {
+		for j := i + 1; j < len(groups); j++ {
+			if shareAnID(groups[i], groups[j]) {
+				merged.union(i, j)
+			}
+		}
+	}
+
+	extractedGroups := map[int][]string{}
+	extractedAliases := map[int][]string{}
+	for i, group := range groups {
+		gid := merged.find(i)
+		extractedGroups[gid] = append(extractedGroups[gid], group.IDs...)
+		extractedAliases[gid] = append(extractedAliases[gid], group.Aliases...)
+	}
+
+	return buildGroups(extractedGroups, extractedAliases)
+}
+
+func shareAnID(g1, g2 models.GroupInfo) bool {
+	for _, id := range slices.Concat(g1.IDs, g1.Aliases) {
+		if slices.Contains(g2.IDs, id) || slices.Contains(g2.Aliases, id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func buildGroups(extractedGroups, extractedAliases map[int][]string) []models.GroupInfo {
 	// Sort by group ID to maintain stable order for tests.
 	sortedKeys := slices.AppendSeq(make([]int, 0, len(extractedGroups)), maps.Keys(extractedGroups))
 	sort.Ints(sortedKeys)
@@ -58,9 +127,7 @@ func Group(vulns []IDAliases) []models.GroupInfo {
 	for _, key := range sortedKeys {
 		// Sort the strings so they are always in the same order
 		slices.SortFunc(extractedGroups[key], identifiers.IDSortFunc)
-
-		// Add IDs to aliases
-		extractedAliases[key] = append(extractedAliases[key], extractedGroups[key]...)
+		extractedGroups[key] = slices.Compact(extractedGroups[key])
 
 		// Dedup entries
 		sort.Strings(extractedAliases[key])
@@ -152,3 +152,72 @@ func TestGroup(t *testing.T) {
 		}
 	}
 }
+
+func TestGroup_Transitive(t *testing.T) {
+	t.Parallel()
+
+	// FOO-1 and BAR-1 share an alias, as do BAZ-1 and QUX-1, and then BAR-1
+	// and QUX-1, which links all four even though the first two groups are
+	// formed before the link between them is found.
+	vulns := []grouper.IDAliases{
+		{ID: "FOO-1", Aliases: []string{"CVE-1"}},
+		{ID: "BAZ-1", Aliases: []string{"CVE-3"}},
+		{ID: "BAR-1", Aliases: []string{"CVE-1", "CVE-2"}},
+		{ID: "QUX-1", Aliases: []string{"CVE-2", "CVE-3"}},
+	}
+
+	want := []models.GroupInfo{
+		{
+			IDs:     []string{"BAR-1", "BAZ-1", "FOO-1", "QUX-1"},
+			Aliases: []string{"BAR-1", "BAZ-1", "CVE-1", "CVE-2", "CVE-3", "FOO-1", "QUX-1"},
+		},
+	}
+
+	grouped := grouper.Group(vulns)
+	if diff := cmp.Diff(want, grouped); diff != "" {
+		t.Errorf("Group() returned an unexpected result (-want +got):\n%s", diff)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	groups := []models.GroupInfo{
+		{
+			IDs:     []string{"GHSA-1"},
+			Aliases: []string{"CVE-1", "GHSA-1"},
+		},
+		{
+			IDs:     []string{"OSV-1"},
+			Aliases: []string{"OSV-1"},
+		},
+		{
+			IDs:     []string{"PYSEC-1"},
+			Aliases: []string{"CVE-1", "PYSEC-1"},
+		},
+		{
+			IDs:     []string{"GHSA-1"},
+			Aliases: []string{"CVE-1", "GHSA-1"},
+		},
+		{
+			IDs:     []string{"GO-1", "GHSA-2"},
+			Aliases: []string{"GHSA-2", "GO-1", "PYSEC-1"},
+		},
+	}
+
+	want := []models.GroupInfo{
+		{
+			IDs:     []string{"GO-1", "PYSEC-1", "GHSA-1", "GHSA-2"},
+			Aliases: []string{"CVE-1", "GHSA-1", "GHSA-2", "GO-1", "PYSEC-1"},
+		},
+		{
+			IDs:     []string{"OSV-1"},
+			Aliases: []string{"OSV-1"},
+		},
+	}
+
+	merged := grouper.Merge(groups)
+	if diff := cmp.Diff(want, merged); diff != "" {
+		t.Errorf("Merge() returned an unexpected result (-want +got):\n%s", diff)
+	}
+}
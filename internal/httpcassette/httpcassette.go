@@ -0,0 +1,108 @@
+// Package httpcassette records HTTP traffic to, and replays it from, cassette
+// files on disk so that scans can be reproduced without network access.
+package httpcassette
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"gopkg.in/dnaeon/go-vcr.v4/pkg/cassette"
+	"gopkg.in/dnaeon/go-vcr.v4/pkg/recorder"
+)
+
+// Mode is the mode a cassette is used in.
+type Mode string
+
+const (
+	// ModeRecord performs real requests and records them to the cassette,
+	// replacing any interactions already in it.
+	ModeRecord Mode = "record"
+	// ModeReplay serves requests only from the cassette, failing any request
+	// which was not previously recorded.
+	ModeReplay Mode = "replay"
+)
+
+// Modes returns the supported cassette modes.
+func Modes() []string {
+	return []string{string(ModeRecord), string(ModeReplay)}
+}
+
+// Cassette is an http.Client backed by a cassette file.
+type Cassette struct {
+	*http.Client
+
+	recorder *recorder.Recorder
+}
+
+// New opens the cassette at path (with a ".yaml" extension added) in the given mode.
+// Requests are made using base, or http.DefaultTransport if nil, when recording.
+//
+// Stop must be called once all requests have completed for a recording to be saved.
+func New(path string, mode Mode, base http.RoundTripper) (*Cassette, error) {
+	var recorderMode recorder.Mode
+	switch mode {
+	case ModeRecord:
+		recorderMode = recorder.ModeRecordOnly
+	case ModeReplay:
+		recorderMode = recorder.ModeReplayOnly
+	default:
+		return nil, fmt.Errorf("unsupported cassette mode %q", mode)
+	}
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	r, err := recorder.New(
+		path,
+		recorder.WithMode(recorderMode),
+		recorder.WithRealTransport(base),
+		recorder.WithSkipRequestLatency(true),
+		recorder.WithMatcher(matcher),
+		recorder.WithHook(func(i *cassette.Interaction) error {
+			// Only the request line and body are used to match, so drop the
+			// request headers to avoid persisting credentials for private mirrors.
+			i.Request.Headers = nil
+			delete(i.Response.Headers, "Date")
+			delete(i.Response.Headers, "Set-Cookie")
+			i.Response.Duration = 0
+
+			return nil
+		}, recorder.AfterCaptureHook),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette %s: %w", path, err)
+	}
+
+	return &Cassette{
+		Client:   r.GetDefaultClient(),
+		recorder: r,
+	}, nil
+}
+
+// Stop saves any recorded interactions to disk.
+func (c *Cassette) Stop() error {
+	return c.recorder.Stop()
+}
+
+// matcher matches requests on their method, URL and body only, so that
+// cassettes can be replayed by different scanner versions and configurations.
+func matcher(r *http.Request, i cassette.Request) bool {
+	if r.Method != i.Method || r.URL.String() != i.URL {
+		return false
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return i.Body == ""
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	return string(body) == i.Body
+}
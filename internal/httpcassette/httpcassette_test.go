@@ -0,0 +1,85 @@
+package httpcassette_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/httpcassette"
+)
+
+func get(t *testing.T, client *http.Client, url string) (string, error) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+
+	return string(body), err
+}
+
+func TestCassette_RecordThenReplay(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "hello from "+r.URL.Path)
+	}))
+	path := filepath.Join(t.TempDir(), "scan")
+
+	// Record
+	rec, err := httpcassette.New(path, httpcassette.ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if body, err := get(t, rec.Client, srv.URL+"/a"); err != nil || body != "hello from /a" {
+		t.Fatalf("recording get() = %q, %v", body, err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop() error: %v", err)
+	}
+	srv.Close()
+
+	content, err := os.ReadFile(path + ".yaml")
+	if err != nil {
+		t.Fatalf("failed to read cassette: %v", err)
+	}
+	if strings.Contains(string(content), "secret") {
+		t.Error("cassette contains request credentials")
+	}
+
+	// Replay, with the server no longer running
+	replay, err := httpcassette.New(path, httpcassette.ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	t.Cleanup(func() { _ = replay.Stop() })
+
+	if body, err := get(t, replay.Client, srv.URL+"/a"); err != nil || body != "hello from /a" {
+		t.Errorf("replaying get() = %q, %v, want recorded response", body, err)
+	}
+	if _, err := get(t, replay.Client, srv.URL+"/b"); err == nil {
+		t.Error("replaying get() of unrecorded request succeeded, want error")
+	}
+}
+
+func TestNew_InvalidMode(t *testing.T) {
+	t.Parallel()
+
+	if _, err := httpcassette.New(filepath.Join(t.TempDir(), "scan"), "rewind", nil); err == nil {
+		t.Error("New() error = nil, want error for unsupported mode")
+	}
+}
@@ -8,12 +8,15 @@ import (
 	"github.com/google/osv-scalibr/converter"
 	"github.com/google/osv-scalibr/extractor"
 	archivemetadata "github.com/google/osv-scalibr/extractor/filesystem/language/java/archive/metadata"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/javalockfile"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/python/requirements"
 	apkmetadata "github.com/google/osv-scalibr/extractor/filesystem/os/apk/metadata"
 	dpkgmetadata "github.com/google/osv-scalibr/extractor/filesystem/os/dpkg/metadata"
 	rpmmetadata "github.com/google/osv-scalibr/extractor/filesystem/os/rpm/metadata"
 	"github.com/google/osv-scalibr/inventory/osvecosystem"
 	"github.com/google/osv-scanner/v2/internal/cachedregexp"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/osv/osvscannerjson"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/vcs/gitrepo"
 	"github.com/google/osv-scanner/v2/internal/scalibrplugin"
@@ -183,6 +186,73 @@ func (pkg *PackageInfo) DepGroups() []string {
 	return []string{}
 }
 
+// IsTransitive reports whether the package was added by transitive dependency
+// resolution rather than being declared directly in its manifest.
+func (pkg *PackageInfo) IsTransitive() bool {
+	switch metadata := pkg.Metadata.(type) {
+	case *depsdev.Metadata:
+		return metadata.IsTransitive
+	case *javalockfile.Metadata:
+		return metadata.IsTransitive
+	}
+
+	return false
+}
+
+// IntroducedBy returns the chains of packages through which transitive
+// dependency resolution reached the package, each starting at a package
+// declared in the manifest.
+func (pkg *PackageInfo) IntroducedBy() [][]string {
+	if metadata, ok := pkg.Metadata.(*depsdev.Metadata); ok {
+		return metadata.IntroducedBy
+	}
+
+	return nil
+}
+
+// Parents returns the packages that directly depend on the package, as found
+// by transitive dependency resolution.
+func (pkg *PackageInfo) Parents() []depsdev.Parent {
+	if metadata, ok := pkg.Metadata.(*depsdev.Metadata); ok {
+		return metadata.Parents
+	}
+
+	return nil
+}
+
+// Declared returns what the manifest declared for the package, if transitive
+// dependency resolution changed its version.
+func (pkg *PackageInfo) Declared() *depsdev.Declaration {
+	if metadata, ok := pkg.Metadata.(*depsdev.Metadata); ok {
+		return metadata.Declared
+	}
+
+	return nil
+}
+
+// PinnedHashes returns the hashes that a requirements file pins the files of
+// the package to with --hash options, as "<algorithm>:<hex digest>".
+func (pkg *PackageInfo) PinnedHashes() []string {
+	if metadata, ok := pkg.Metadata.(*requirements.Metadata); ok {
+		return metadata.HashCheckingModeValues
+	}
+
+	return nil
+}
+
+// ExtractedLicenses returns the licenses that were found for the package while
+// scanning, such as those in an SBOM or the metadata of an installed package.
+func (pkg *PackageInfo) ExtractedLicenses() []models.License {
+	licenses := make([]models.License, 0, len(pkg.Licenses))
+	for _, license := range pkg.Licenses {
+		if license != "" {
+			licenses = append(licenses, models.License(license))
+		}
+	}
+
+	return licenses
+}
+
 func (pkg *PackageInfo) OSPackageName() string {
 	if metadata, ok := pkg.Metadata.(*apkmetadata.Metadata); ok {
 		return metadata.PackageName
@@ -219,6 +289,22 @@ type PackageScanResult struct {
 	PackageInfo     PackageInfo
 	Vulnerabilities []*osvschema.Vulnerability
 	Licenses        []models.License
+	// Scorecard is the OpenSSF Scorecard of the package's source repository
+	Scorecard *models.Scorecard
+	// Dependents is how many packages depend on the package version
+	Dependents *models.Dependents
+	// Release is how the package version compares to its latest release
+	Release *models.Release
+	// TyposquatOf are the popular packages the package's name is close to
+	TyposquatOf []string
+	// Provenance is the build provenance the package version was published with
+	Provenance *models.Provenance
+	// DeprecationReason explains why the package is deprecated, yanked or no
+	// longer maintained upstream
+	DeprecationReason string
+	// HashMismatches are the hashes the package is pinned to that none of the
+	// files published for its version have
+	HashMismatches []string
 
 	// TODO(v2):
 	// SourceAnalysis *SourceAnalysis
@@ -6,6 +6,8 @@ import (
 	"github.com/google/osv-scalibr/inventory"
 	"github.com/google/osv-scanner/v2/internal/config"
 	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/vexdoc"
+	"github.com/google/osv-scanner/v2/pkg/models"
 )
 
 // ScanResults represents the complete results of a scan.
@@ -25,4 +27,17 @@ type ScanResults struct {
 	ImageMetadata *spb.ContainerImageMetadata
 
 	GenericFindings []*inventory.GenericFinding
+
+	// Sources whose results may be incomplete
+	Degradations []models.Degradation
+
+	// Packages whose dependencies could not be resolved
+	UnresolvedPackages []models.UnresolvedPackage
+
+	// Dependencies declared in manifests that their lockfiles do not agree with
+	Drift []models.Drift
+
+	// Statements from VEX documents that packages are not affected by
+	// vulnerabilities
+	VEXStatements []vexdoc.Statement
 }
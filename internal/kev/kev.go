@@ -0,0 +1,91 @@
+// Package kev fetches the CISA Known Exploited Vulnerabilities (KEV) catalog.
+package kev
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// DefaultCatalogURL is the JSON feed of the CISA KEV catalog.
+const DefaultCatalogURL = "https://www.cisa.gov/sites/default/files/feeds/known_exploited_vulnerabilities.json"
+
+// Client fetches the CISA KEV catalog.
+type Client struct {
+	client     *http.Client
+	catalogURL string
+	userAgent  string
+}
+
+// NewClient creates a new client for the KEV catalog at catalogURL,
+// defaulting to DefaultCatalogURL.
+func NewClient(catalogURL string, httpClient *http.Client, userAgent string) *Client {
+	if catalogURL == "" {
+		catalogURL = DefaultCatalogURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		client:     httpClient,
+		catalogURL: catalogURL,
+		userAgent:  userAgent,
+	}
+}
+
+// kevCatalog is the subset of the KEV catalog feed used here.
+type kevCatalog struct {
+	Vulnerabilities []struct {
+		CVEID                      string `json:"cveID"`
+		DateAdded                  string `json:"dateAdded"`
+		DueDate                    string `json:"dueDate"`
+		RequiredAction             string `json:"requiredAction"`
+		KnownRansomwareCampaignUse string `json:"knownRansomwareCampaignUse"`
+	} `json:"vulnerabilities"`
+}
+
+// Catalog returns the entries of the KEV catalog, keyed by CVE ID.
+func (c *Client) Catalog(ctx context.Context) (map[string]models.KEVEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.catalogURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("KEV catalog request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("KEV catalog returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var catalog kevCatalog
+	if err := json.NewDecoder(resp.Body).Decode(&catalog); err != nil {
+		return nil, fmt.Errorf("failed to decode KEV catalog: %w", err)
+	}
+
+	entries := make(map[string]models.KEVEntry, len(catalog.Vulnerabilities))
+	for _, v := range catalog.Vulnerabilities {
+		entries[v.CVEID] = models.KEVEntry{
+			CVE:                        v.CVEID,
+			DateAdded:                  v.DateAdded,
+			DueDate:                    v.DueDate,
+			RequiredAction:             v.RequiredAction,
+			KnownRansomwareCampaignUse: v.KnownRansomwareCampaignUse == "Known",
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,66 @@
+package kev_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/kev"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func TestClient_Catalog(t *testing.T) {
+	t.Parallel()
+
+	var gotUserAgent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.Header.Get("User-Agent")
+		fmt.Fprint(w, `{"title":"CISA Catalog of Known Exploited Vulnerabilities","count":2,"vulnerabilities":[
+			{"cveID":"CVE-2021-44228","vendorProject":"Apache","product":"Log4j2","dateAdded":"2021-12-10","requiredAction":"Apply updates per vendor instructions.","dueDate":"2021-12-24","knownRansomwareCampaignUse":"Known"},
+			{"cveID":"CVE-2022-22965","vendorProject":"VMware","product":"Spring Framework","dateAdded":"2022-04-04","requiredAction":"Apply updates per vendor instructions.","dueDate":"2022-04-25","knownRansomwareCampaignUse":"Unknown"}
+		]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	got, err := kev.NewClient(srv.URL, nil, "osv-scanner-test").Catalog(t.Context())
+	if err != nil {
+		t.Fatalf("Catalog() error: %v", err)
+	}
+
+	want := map[string]models.KEVEntry{
+		"CVE-2021-44228": {
+			CVE:                        "CVE-2021-44228",
+			DateAdded:                  "2021-12-10",
+			DueDate:                    "2021-12-24",
+			RequiredAction:             "Apply updates per vendor instructions.",
+			KnownRansomwareCampaignUse: true,
+		},
+		"CVE-2022-22965": {
+			CVE:            "CVE-2022-22965",
+			DateAdded:      "2022-04-04",
+			DueDate:        "2022-04-25",
+			RequiredAction: "Apply updates per vendor instructions.",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Catalog() mismatch (-want +got):\n%s", diff)
+	}
+	if gotUserAgent != "osv-scanner-test" {
+		t.Errorf("User-Agent = %q, want %q", gotUserAgent, "osv-scanner-test")
+	}
+}
+
+func TestClient_Catalog_Error(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+
+	if _, err := kev.NewClient(srv.URL, nil, "").Catalog(t.Context()); err == nil {
+		t.Errorf("Catalog() error = nil, want an error")
+	}
+}
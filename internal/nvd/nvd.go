@@ -0,0 +1,193 @@
+// Package nvd looks up CVEs from the National Vulnerability Database API.
+package nvd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// DefaultBaseURL is the NVD CVE API.
+const DefaultBaseURL = "https://services.nvd.nist.gov/rest/json/cves/2.0"
+
+// The NVD asks clients to keep within 5 requests per 30 seconds without an
+// API key, and 50 with one.
+const (
+	requestInterval        = 6 * time.Second
+	requestIntervalWithKey = 600 * time.Millisecond
+)
+
+// Client fetches CVEs from the NVD, spacing out its requests to stay within
+// the NVD rate limits.
+type Client struct {
+	client    *http.Client
+	baseURL   string
+	userAgent string
+	apiKey    string
+	interval  time.Duration
+
+	mu          sync.Mutex
+	lastRequest time.Time
+}
+
+// NewClient creates a new client for the NVD API at baseURL, defaulting to
+// DefaultBaseURL. The API key is optional, but makes requests a lot faster.
+func NewClient(baseURL string, httpClient *http.Client, userAgent string, apiKey string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	interval := requestInterval
+	if apiKey != "" {
+		interval = requestIntervalWithKey
+	}
+
+	return &Client{
+		client:    httpClient,
+		baseURL:   baseURL,
+		userAgent: userAgent,
+		apiKey:    apiKey,
+		interval:  interval,
+	}
+}
+
+// cvssMetric is a CVSS vector given for a CVE, either by the NVD itself
+// ("Primary") or by the CNA that assigned it ("Secondary").
+type cvssMetric struct {
+	Type     string `json:"type"`
+	CVSSData struct {
+		VectorString string `json:"vectorString"`
+	} `json:"cvssData"`
+}
+
+// cveResponse is the subset of the NVD CVE API response used here.
+type cveResponse struct {
+	Vulnerabilities []struct {
+		CVE struct {
+			ID           string `json:"id"`
+			Descriptions []struct {
+				Lang  string `json:"lang"`
+				Value string `json:"value"`
+			} `json:"descriptions"`
+			Metrics struct {
+				CVSSMetricV40 []cvssMetric `json:"cvssMetricV40"`
+				CVSSMetricV31 []cvssMetric `json:"cvssMetricV31"`
+				CVSSMetricV30 []cvssMetric `json:"cvssMetricV30"`
+				CVSSMetricV2  []cvssMetric `json:"cvssMetricV2"`
+			} `json:"metrics"`
+		} `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+// Advisory returns the NVD entry of a CVE as an OSV record with only the ID,
+// details and severity set. It returns nil if the NVD has no such CVE, or the
+// ID is not a CVE ID.
+func (c *Client) Advisory(ctx context.Context, id string) (*osvschema.Vulnerability, error) {
+	if !strings.HasPrefix(id, "CVE-") {
+		return nil, nil
+	}
+
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"?"+url.Values{"cveId": {id}}.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("apiKey", c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("NVD request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("NVD API returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result cveResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode NVD response: %w", err)
+	}
+	if len(result.Vulnerabilities) == 0 {
+		return nil, nil
+	}
+
+	cve := result.Vulnerabilities[0].CVE
+	vuln := &osvschema.Vulnerability{Id: cve.ID}
+	for _, description := range cve.Descriptions {
+		if description.Lang == "en" {
+			vuln.Details = description.Value
+			break
+		}
+	}
+	for _, metric := range []struct {
+		severityType osvschema.Severity_Type
+		metrics      []cvssMetric
+	}{
+		{osvschema.Severity_CVSS_V4, cve.Metrics.CVSSMetricV40},
+		{osvschema.Severity_CVSS_V3, cve.Metrics.CVSSMetricV31},
+		{osvschema.Severity_CVSS_V3, cve.Metrics.CVSSMetricV30},
+		{osvschema.Severity_CVSS_V2, cve.Metrics.CVSSMetricV2},
+	} {
+		if vector := preferredVector(metric.metrics); vector != "" {
+			vuln.Severity = append(vuln.Severity, &osvschema.Severity{Type: metric.severityType, Score: vector})
+		}
+	}
+
+	return vuln, nil
+}
+
+// preferredVector returns the vector given by the NVD itself if there is one,
+// or else the first one given by anyone else.
+func preferredVector(metrics []cvssMetric) string {
+	for _, metric := range metrics {
+		if metric.Type == "Primary" {
+			return metric.CVSSData.VectorString
+		}
+	}
+	if len(metrics) > 0 {
+		return metrics[0].CVSSData.VectorString
+	}
+
+	return ""
+}
+
+// wait blocks until enough time has passed since the last request.
+func (c *Client) wait(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if delay := time.Until(c.lastRequest.Add(c.interval)); delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	c.lastRequest = time.Now()
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package nvd_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/nvd"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestClient_Advisory(t *testing.T) {
+	t.Parallel()
+
+	var gotAPIKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAPIKey = r.Header.Get("apiKey")
+		if r.URL.Query().Get("cveId") != "CVE-2021-44228" {
+			fmt.Fprint(w, `{"totalResults": 0, "vulnerabilities": []}`)
+			return
+		}
+		fmt.Fprint(w, `{"totalResults": 1, "vulnerabilities": [{"cve": {
+			"id": "CVE-2021-44228",
+			"descriptions": [
+				{"lang": "es", "value": "Apache Log4j2 ..."},
+				{"lang": "en", "value": "Apache Log4j2 JNDI features do not protect against attacker controlled LDAP endpoints."}
+			],
+			"metrics": {
+				"cvssMetricV31": [
+					{"type": "Secondary", "cvssData": {"vectorString": "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:C/C:H/I:H/A:H"}},
+					{"type": "Primary", "cvssData": {"vectorString": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H"}}
+				],
+				"cvssMetricV2": [
+					{"type": "Primary", "cvssData": {"vectorString": "AV:N/AC:M/Au:N/C:C/I:C/A:C"}}
+				]
+			}
+		}}]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := nvd.NewClient(srv.URL, nil, "osv-scanner-test", "secret")
+
+	got, err := c.Advisory(t.Context(), "CVE-2021-44228")
+	if err != nil {
+		t.Fatalf("Advisory() error: %v", err)
+	}
+	want := &osvschema.Vulnerability{
+		Id:      "CVE-2021-44228",
+		Details: "Apache Log4j2 JNDI features do not protect against attacker controlled LDAP endpoints.",
+		Severity: []*osvschema.Severity{
+			{Type: osvschema.Severity_CVSS_V3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H"},
+			{Type: osvschema.Severity_CVSS_V2, Score: "AV:N/AC:M/Au:N/C:C/I:C/A:C"},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Advisory() mismatch (-want +got):\n%s", diff)
+	}
+	if gotAPIKey != "secret" {
+		t.Errorf("apiKey = %q, want %q", gotAPIKey, "secret")
+	}
+
+	for _, id := range []string{"CVE-2099-0001", "GHSA-jfh8-c2jp-5v3q"} {
+		got, err := c.Advisory(t.Context(), id)
+		if err != nil || got != nil {
+			t.Errorf("Advisory(%q) = %v, %v, want nil, nil", id, got, err)
+		}
+	}
+}
+
+func TestClient_Advisory_Error(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := nvd.NewClient(srv.URL, nil, "", "secret")
+	if _, err := c.Advisory(t.Context(), "CVE-2021-44228"); err == nil {
+		t.Errorf("Advisory() error = nil, want an error")
+	}
+}
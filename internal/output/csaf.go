@@ -0,0 +1,288 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/utility/purl"
+	"github.com/google/osv-scanner/v2/internal/version"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/google/uuid"
+)
+
+// csafDocument is a CSAF 2.0 document following the VEX profile.
+type csafDocument struct {
+	Document        csafDocumentMetadata `json:"document"`
+	ProductTree     csafProductTree      `json:"product_tree"`
+	Vulnerabilities []csafVulnerability  `json:"vulnerabilities"`
+}
+
+type csafDocumentMetadata struct {
+	Category    string        `json:"category"`
+	CSAFVersion string        `json:"csaf_version"`
+	Notes       []csafNote    `json:"notes"`
+	Publisher   csafPublisher `json:"publisher"`
+	Title       string        `json:"title"`
+	Tracking    csafTracking  `json:"tracking"`
+}
+
+type csafNote struct {
+	Category string `json:"category"`
+	Text     string `json:"text"`
+}
+
+type csafPublisher struct {
+	Category  string `json:"category"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type csafTracking struct {
+	CurrentReleaseDate string         `json:"current_release_date"`
+	Generator          csafGenerator  `json:"generator"`
+	ID                 string         `json:"id"`
+	InitialReleaseDate string         `json:"initial_release_date"`
+	RevisionHistory    []csafRevision `json:"revision_history"`
+	Status             string         `json:"status"`
+	Version            string         `json:"version"`
+}
+
+type csafGenerator struct {
+	Engine struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"engine"`
+}
+
+type csafRevision struct {
+	Date    string `json:"date"`
+	Number  string `json:"number"`
+	Summary string `json:"summary"`
+}
+
+type csafProductTree struct {
+	FullProductNames []csafProduct `json:"full_product_names"`
+}
+
+type csafProduct struct {
+	Name                        string `json:"name"`
+	ProductID                   string `json:"product_id"`
+	ProductIdentificationHelper struct {
+		PURL string `json:"purl"`
+	} `json:"product_identification_helper"`
+}
+
+type csafVulnerability struct {
+	CVE           string            `json:"cve,omitempty"`
+	IDs           []csafID          `json:"ids,omitempty"`
+	Notes         []csafNote        `json:"notes"`
+	ProductStatus csafProductStatus `json:"product_status"`
+	Flags         []csafProductNote `json:"flags,omitempty"`
+	Threats       []csafProductNote `json:"threats,omitempty"`
+	Remediations  []csafProductNote `json:"remediations,omitempty"`
+}
+
+type csafID struct {
+	SystemName string `json:"system_name"`
+	Text       string `json:"text"`
+}
+
+type csafProductStatus struct {
+	KnownAffected    []string `json:"known_affected,omitempty"`
+	KnownNotAffected []string `json:"known_not_affected,omitempty"`
+}
+
+// csafProductNote is a flag, threat or remediation, which all apply a label,
+// category or details to a list of products.
+type csafProductNote struct {
+	Label      string   `json:"label,omitempty"`
+	Category   string   `json:"category,omitempty"`
+	Details    string   `json:"details,omitempty"`
+	ProductIDs []string `json:"product_ids"`
+}
+
+// PrintCSAFResults writes results to the provided writer as a CSAF 2.0 VEX
+// document, listing the packages found as products and stating for each
+// vulnerability which of them it affects, with the same statuses as the
+// OpenVEX output.
+func PrintCSAFResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	summaries := make(map[string]string)
+	for _, vf := range vulnResult.Flatten() {
+		if vf.Vulnerability != nil {
+			summaries[vf.Vulnerability.GetId()] = vf.Vulnerability.GetSummary()
+		}
+	}
+
+	products := []csafProduct{}
+	productIDs := make(map[string]string)
+	vulnerabilities := []csafVulnerability{}
+	vulnIndexes := make(map[string]int)
+
+	for _, finding := range vexFindings(vulnResult) {
+		packageURL, err := purl.FromPackage(finding.Package)
+		if err != nil {
+			continue
+		}
+		p := packageURL.ToString()
+		productID, ok := productIDs[p]
+		if !ok {
+			productID = fmt.Sprintf("CSAFPID-%04d", len(products)+1)
+			productIDs[p] = productID
+			product := csafProduct{
+				Name:      finding.Package.Name + " " + finding.Package.Version,
+				ProductID: productID,
+			}
+			product.ProductIdentificationHelper.PURL = p
+			products = append(products, product)
+		}
+
+		name := finding.Aliases.IDs[0]
+		i, ok := vulnIndexes[name]
+		if !ok {
+			i = len(vulnerabilities)
+			vulnIndexes[name] = i
+			vulnerabilities = append(vulnerabilities, newCSAFVulnerability(finding.Aliases, summaries))
+		}
+		vuln := &vulnerabilities[i]
+
+		switch finding.Status {
+		case vexAffected:
+			vuln.ProductStatus.KnownAffected = append(vuln.ProductStatus.KnownAffected, productID)
+			category := "vendor_fix"
+			if finding.FixedVersion == "" && len(finding.FixedVersions) == 0 {
+				category = "none_available"
+			}
+			vuln.Remediations = addCSAFProduct(vuln.Remediations, csafProductNote{Category: category, Details: vexAction(finding)}, productID)
+		case vexNotCalled:
+			vuln.ProductStatus.KnownNotAffected = append(vuln.ProductStatus.KnownNotAffected, productID)
+			vuln.Flags = addCSAFProduct(vuln.Flags, csafProductNote{Label: openVEXNotInExecutePath}, productID)
+		case vexIgnored:
+			vuln.ProductStatus.KnownNotAffected = append(vuln.ProductStatus.KnownNotAffected, productID)
+			if finding.Justification != "" {
+				vuln.Flags = addCSAFProduct(vuln.Flags, csafProductNote{Label: finding.Justification}, productID)
+			}
+			if finding.Justification == "" || finding.Reason != "" {
+				vuln.Threats = addCSAFProduct(vuln.Threats, csafProductNote{Category: "impact", Details: vexIgnoredReason(finding)}, productID)
+			}
+		}
+	}
+
+	// A package found in several sources is the same product in each of them,
+	// which is affected if it is in any of them
+	for i := range vulnerabilities {
+		vuln := &vulnerabilities[i]
+		status := &vuln.ProductStatus
+		slices.Sort(status.KnownAffected)
+		status.KnownAffected = slices.Compact(status.KnownAffected)
+		slices.Sort(status.KnownNotAffected)
+		status.KnownNotAffected = slices.Compact(status.KnownNotAffected)
+
+		status.KnownNotAffected = slices.DeleteFunc(status.KnownNotAffected, func(productID string) bool {
+			_, affected := slices.BinarySearch(status.KnownAffected, productID)
+			return affected
+		})
+		vuln.Flags = removeCSAFProducts(vuln.Flags, status.KnownAffected)
+		vuln.Threats = removeCSAFProducts(vuln.Threats, status.KnownAffected)
+	}
+
+	doc := csafDocument{
+		Document: csafDocumentMetadata{
+			Category:    "csaf_vex",
+			CSAFVersion: "2.0",
+			Notes: []csafNote{{
+				Category: "summary",
+				Text:     "Vulnerabilities found in the packages scanned by osv-scanner.",
+			}},
+			Publisher: csafPublisher{
+				Category:  "user",
+				Name:      "osv-scanner",
+				Namespace: "https://github.com/google/osv-scanner",
+			},
+			Title: "osv-scanner results",
+			Tracking: csafTracking{
+				CurrentReleaseDate: now,
+				ID:                 "osv-scanner-" + uuid.New().String(),
+				InitialReleaseDate: now,
+				RevisionHistory:    []csafRevision{{Date: now, Number: "1", Summary: "Initial version"}},
+				Status:             "final",
+				Version:            "1",
+			},
+		},
+		ProductTree:     csafProductTree{FullProductNames: products},
+		Vulnerabilities: vulnerabilities,
+	}
+	doc.Document.Tracking.Generator.Engine.Name = "osv-scanner"
+	doc.Document.Tracking.Generator.Engine.Version = version.OSVVersion
+
+	encoder := json.NewEncoder(outputWriter)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(doc)
+}
+
+// newCSAFVulnerability creates the vulnerability for a group of aliases. CSAF
+// only allows one CVE per vulnerability, so any others are listed as IDs.
+func newCSAFVulnerability(group models.GroupInfo, summaries map[string]string) csafVulnerability {
+	var vuln csafVulnerability
+
+	aliases := slices.Clone(group.Aliases)
+	slices.Sort(aliases)
+	for _, alias := range slices.Compact(aliases) {
+		if vuln.CVE == "" && strings.HasPrefix(alias, "CVE-") {
+			vuln.CVE = alias
+			continue
+		}
+		vuln.IDs = append(vuln.IDs, csafID{SystemName: "OSV", Text: alias})
+	}
+
+	summary := ""
+	for _, id := range group.IDs {
+		if summary = summaries[id]; summary != "" {
+			break
+		}
+	}
+	if summary == "" {
+		summary = "See https://osv.dev/" + group.IDs[0]
+	}
+	vuln.Notes = []csafNote{{Category: "summary", Text: summary}}
+
+	return vuln
+}
+
+// addCSAFProduct adds productID to the note in notes with the same label,
+// category and details as note, adding note if there is none.
+func addCSAFProduct(notes []csafProductNote, note csafProductNote, productID string) []csafProductNote {
+	for i := range notes {
+		if notes[i].Label == note.Label && notes[i].Category == note.Category && notes[i].Details == note.Details {
+			if !slices.Contains(notes[i].ProductIDs, productID) {
+				notes[i].ProductIDs = append(notes[i].ProductIDs, productID)
+			}
+
+			return notes
+		}
+	}
+	note.ProductIDs = []string{productID}
+
+	return append(notes, note)
+}
+
+// removeCSAFProducts removes the products in productIDs, which are sorted,
+// from notes, dropping the notes that are left without any products.
+func removeCSAFProducts(notes []csafProductNote, productIDs []string) []csafProductNote {
+	for i := range notes {
+		notes[i].ProductIDs = slices.DeleteFunc(notes[i].ProductIDs, func(productID string) bool {
+			_, ok := slices.BinarySearch(productIDs, productID)
+			return ok
+		})
+	}
+
+	return slices.DeleteFunc(notes, func(note csafProductNote) bool {
+		return len(note.ProductIDs) == 0
+	})
+}
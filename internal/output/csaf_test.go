@@ -0,0 +1,165 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+func normalizeCSAFOutput(t *testing.T, str string) string {
+	t.Helper()
+
+	str = cachedregexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`).ReplaceAllString(str, `<uuid>`)
+	str = cachedregexp.MustCompile(`"\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z"`).ReplaceAllString(str, `"<timestamp>"`)
+
+	return str
+}
+
+func TestPrintCSAFResults_WithVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	testOutputWithVulnerabilities(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		outputWriter := &bytes.Buffer{}
+		err := output.PrintCSAFResults(args.vulnResult, outputWriter)
+
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+
+		testutility.NewSnapshot().MatchText(t, normalizeCSAFOutput(t, outputWriter.String()))
+	})
+}
+
+func TestPrintCSAFResults_Ignored(t *testing.T) {
+	t.Parallel()
+
+	ignored := func(name, reason string, ids ...string) models.IgnoredVulnerability {
+		return models.IgnoredVulnerability{
+			Source:  models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+			Package: models.PackageInfo{Name: name, Version: "1.2.3", Ecosystem: "npm"},
+			Group:   models.GroupInfo{IDs: ids[:1], Aliases: ids},
+			Reason:  reason,
+		}
+	}
+	vulnResult := &models.VulnerabilityResults{
+		ExperimentalIgnored: []models.IgnoredVulnerability{
+			ignored("mine1", "only used in tests", "GHSA-123", "CVE-2024-123"),
+			ignored("mine2", "only used in tests", "GHSA-123", "CVE-2024-123"),
+			ignored("mine1", "", "OSV-2"),
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintCSAFResults(vulnResult, outputWriter); err != nil {
+		t.Errorf("%v", err)
+	}
+	testutility.NewSnapshot().MatchText(t, normalizeCSAFOutput(t, outputWriter.String()))
+}
+
+func TestPrintCSAFResults_NotAffected(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		ExperimentalIgnored: []models.IgnoredVulnerability{
+			{
+				Source:        models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Package:       models.PackageInfo{Name: "mine1", Version: "1.2.3", Ecosystem: "npm"},
+				Group:         models.GroupInfo{IDs: []string{"GHSA-123"}, Aliases: []string{"GHSA-123", "CVE-2024-123"}},
+				Justification: "vulnerable_code_not_present",
+				VEXSource:     "/path/to/vex.json",
+			},
+			{
+				Source:        models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Package:       models.PackageInfo{Name: "mine2", Version: "1.0.0", Ecosystem: "npm"},
+				Group:         models.GroupInfo{IDs: []string{"OSV-2"}, Aliases: []string{"OSV-2"}},
+				Reason:        "the affected function is patched out at build time",
+				Justification: "inline_mitigations_already_exist",
+				VEXSource:     "/path/to/vex.json",
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintCSAFResults(vulnResult, outputWriter); err != nil {
+		t.Errorf("%v", err)
+	}
+	testutility.NewSnapshot().MatchText(t, normalizeCSAFOutput(t, outputWriter.String()))
+}
+
+func TestPrintCSAFResults_SeveralSources(t *testing.T) {
+	t.Parallel()
+
+	pkg := models.PackageInfo{Name: "mine1", Version: "1.2.3", Ecosystem: "npm"}
+	group := models.GroupInfo{IDs: []string{"GHSA-123"}, Aliases: []string{"GHSA-123"}}
+	affected := func(path string) models.PackageSource {
+		return models.PackageSource{
+			Source: models.SourceInfo{Path: path, Type: models.SourceTypeProjectPackage},
+			Packages: []models.PackageVulns{{
+				Package:         pkg,
+				Vulnerabilities: []*osvschema.Vulnerability{{Id: "GHSA-123"}},
+				Groups:          []models.GroupInfo{group},
+			}},
+		}
+	}
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			affected("/path/to/package-lock.json"),
+			affected("/path/to/other/package-lock.json"),
+		},
+		ExperimentalIgnored: []models.IgnoredVulnerability{{
+			Source:  models.SourceInfo{Path: "/path/to/third/package-lock.json", Type: models.SourceTypeProjectPackage},
+			Package: pkg,
+			Group:   group,
+			Reason:  "only used in tests",
+		}},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintCSAFResults(vulnResult, outputWriter); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	var doc struct {
+		Vulnerabilities []struct {
+			ProductStatus struct {
+				KnownAffected    []string `json:"known_affected"`
+				KnownNotAffected []string `json:"known_not_affected"`
+			} `json:"product_status"`
+			Threats      []json.RawMessage `json:"threats"`
+			Remediations []struct {
+				ProductIDs []string `json:"product_ids"`
+			} `json:"remediations"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(outputWriter.Bytes(), &doc); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(doc.Vulnerabilities) != 1 {
+		t.Fatalf("got %d vulnerabilities, want 1", len(doc.Vulnerabilities))
+	}
+	vuln := doc.Vulnerabilities[0]
+
+	// the package is affected in some of the sources, so it is affected and
+	// remediated once, and the ignore in the other source does not count
+	if diff := cmp.Diff([]string{"CSAFPID-0001"}, vuln.ProductStatus.KnownAffected); diff != "" {
+		t.Errorf("known_affected mismatch (-want +got):\n%s", diff)
+	}
+	if len(vuln.ProductStatus.KnownNotAffected) != 0 || len(vuln.Threats) != 0 {
+		t.Errorf("known_not_affected = %v, threats = %d, want none", vuln.ProductStatus.KnownNotAffected, len(vuln.Threats))
+	}
+	if len(vuln.Remediations) != 1 {
+		t.Fatalf("got %d remediations, want 1", len(vuln.Remediations))
+	}
+	if diff := cmp.Diff([]string{"CSAFPID-0001"}, vuln.Remediations[0].ProductIDs); diff != "" {
+		t.Errorf("remediation product_ids mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,114 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+var csvHeader = []string{
+	"Ecosystem",
+	"Package",
+	"Version",
+	"Vulnerability ID",
+	"Aliases",
+	"Severity",
+	"Severity Rating",
+	"Fixed Version",
+	"Status",
+	"Known Exploited",
+	"Source",
+	"Dependency Chain",
+}
+
+// PrintCSVResults writes results to the provided writer as CSV, with a row for
+// each vulnerability found in each package.
+//
+// Packages introduced through several dependency chains are repeated once for
+// each of them, so that every row can be filtered on its own.
+func PrintCSVResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	result := BuildResults(vulnResult)
+	workingDir := mustGetWorkingDirectory()
+
+	w := csv.NewWriter(outputWriter)
+	if err := w.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, eco := range result.Ecosystems {
+		for _, source := range eco.Sources {
+			path := relativeSourcePath(source, workingDir)
+
+			for _, pkg := range source.Packages {
+				version := pkg.InstalledVersion
+				if version == "" {
+					version = pkg.Commit
+				}
+				chains := []string{""}
+				if len(pkg.IntroducedBy) > 0 {
+					chains = chains[:0]
+					for _, chain := range pkg.IntroducedBy {
+						chains = append(chains, strings.Join(chain, " > "))
+					}
+				}
+
+				var vulns []VulnResult
+				vulns = append(vulns, pkg.RegularVulns...)
+				vulns = append(vulns, pkg.HiddenVulns...)
+
+				for _, vuln := range vulns {
+					fixedVersion := ""
+					if vuln.IsFixable {
+						fixedVersion = vuln.FixedVersion
+					}
+					for _, chain := range chains {
+						err := w.Write([]string{
+							eco.Name,
+							pkg.Name,
+							version,
+							vuln.ID,
+							strings.Join(vuln.Aliases, " "),
+							vuln.SeverityScore,
+							string(vuln.SeverityRating),
+							fixedVersion,
+							csvStatus(vuln.VulnAnalysisType),
+							csvKnownExploited(vuln.KEV),
+							path,
+							chain,
+						})
+						if err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+func csvStatus(analysisType VulnAnalysisType) string {
+	switch analysisType {
+	case VulnTypeUncalled:
+		return "uncalled"
+	case VulnTypeUnimportant:
+		return "unimportant"
+	default:
+		return "affected"
+	}
+}
+
+// csvKnownExploited is the date a vulnerability was added to the CISA KEV
+// catalog, if it is listed.
+func csvKnownExploited(entry *models.KEVEntry) string {
+	if entry == nil {
+		return ""
+	}
+
+	return entry.DateAdded
+}
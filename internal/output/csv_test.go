@@ -0,0 +1,86 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+func TestPrintCSVResults_WithVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	testOutputWithVulnerabilities(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		outputWriter := &bytes.Buffer{}
+		err := output.PrintCSVResults(args.vulnResult, outputWriter)
+
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+
+		testutility.NewSnapshot().MatchText(t, outputWriter.String())
+	})
+}
+
+func TestPrintCSVResults_WithMixedIssues(t *testing.T) {
+	t.Parallel()
+
+	testOutputWithMixedIssues(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		outputWriter := &bytes.Buffer{}
+		err := output.PrintCSVResults(args.vulnResult, outputWriter)
+
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+
+		testutility.NewSnapshot().MatchText(t, outputWriter.String())
+	})
+}
+
+func TestPrintCSVResults_KnownExploited(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/pom.xml", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", Ecosystem: "Maven"},
+						Groups: []models.GroupInfo{
+							{
+								IDs:         []string{"GHSA-jfh8-c2jp-5v3q"},
+								Aliases:     []string{"CVE-2021-44228", "GHSA-jfh8-c2jp-5v3q"},
+								MaxSeverity: "10.0",
+								KEV:         &models.KEVEntry{CVE: "CVE-2021-44228", DateAdded: "2021-12-10"},
+							},
+							{
+								IDs:         []string{"GHSA-8489-44mv-ggj8"},
+								Aliases:     []string{"CVE-2021-44832", "GHSA-8489-44mv-ggj8"},
+								MaxSeverity: "6.6",
+							},
+						},
+						Vulnerabilities: []*osvschema.Vulnerability{
+							{Id: "GHSA-jfh8-c2jp-5v3q", Aliases: []string{"CVE-2021-44228"}},
+							{Id: "GHSA-8489-44mv-ggj8", Aliases: []string{"CVE-2021-44832"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintCSVResults(vulnResult, outputWriter); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	testutility.NewSnapshot().MatchText(t, outputWriter.String())
+}
@@ -4,6 +4,9 @@ package output
 import (
 	"errors"
 	"io"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/CycloneDX/cyclonedx-go"
 	"github.com/google/osv-scanner/v2/internal/output/sbom"
@@ -17,6 +20,9 @@ func PrintCycloneDXResults(vulnResult *models.VulnerabilityResults, cycloneDXVer
 	resultsByPurl, errs := purl.Group(vulnResult.Results)
 
 	bom := bomCreator(resultsByPurl)
+	if cycloneDXVersion == models.CycloneDXVersion16 {
+		addCycloneDXAnalysis(bom, vulnResult)
+	}
 	encoder := cyclonedx.NewBOMEncoder(outputWriter, cyclonedx.BOMFileFormatJSON)
 	encoder.SetPretty(true)
 
@@ -24,3 +30,141 @@ func PrintCycloneDXResults(vulnResult *models.VulnerabilityResults, cycloneDXVer
 
 	return errors.Join(err, errors.Join(errs...))
 }
+
+// cycloneDXStatement gathers the findings of a vulnerability, which CycloneDX
+// states once for all the components it is found in.
+type cycloneDXStatement struct {
+	affects map[string]cyclonedx.AffectedVersions
+	// whether the vulnerability affects a component, and whether call
+	// analysis did not run for any of the components it affects
+	affected, unanalysed bool
+	// the findings of the components it does not affect
+	notAffected []vexFinding
+}
+
+// addCycloneDXAnalysis makes each vulnerability a VEX statement about the
+// components it was found in, including those it was ignored for.
+//
+// A vulnerability is only stated to be exploitable or not affecting its
+// components when call analysis ran for it, or it was ignored; otherwise it
+// is left in triage.
+func addCycloneDXAnalysis(bom *cyclonedx.BOM, vulnResult *models.VulnerabilityResults) {
+	statements := make(map[string]*cycloneDXStatement)
+	for _, finding := range vexFindings(vulnResult) {
+		packageURL, err := purl.FromPackage(finding.Package)
+		if err != nil {
+			continue
+		}
+		ref := packageURL.ToString()
+		addCycloneDXComponent(bom, ref, finding.Package)
+
+		for _, id := range finding.Group.IDs {
+			s, ok := statements[id]
+			if !ok {
+				s = &cycloneDXStatement{affects: make(map[string]cyclonedx.AffectedVersions)}
+				statements[id] = s
+			}
+
+			status := cyclonedx.VulnerabilityStatusNotAffected
+			if finding.Status == vexAffected {
+				status = cyclonedx.VulnerabilityStatusAffected
+				s.affected = true
+				s.unanalysed = s.unanalysed || !finding.CallAnalysed
+			} else {
+				s.notAffected = append(s.notAffected, finding)
+			}
+			// a component is affected if any of its findings are
+			if s.affects[ref].Status != cyclonedx.VulnerabilityStatusAffected {
+				s.affects[ref] = cyclonedx.AffectedVersions{Version: finding.Package.Version, Status: status}
+			}
+		}
+	}
+
+	vulnerabilities := *bom.Vulnerabilities
+	for _, id := range slices.Sorted(maps.Keys(statements)) {
+		if !slices.ContainsFunc(vulnerabilities, func(v cyclonedx.Vulnerability) bool { return v.ID == id }) {
+			// ignored vulnerabilities are not in the results
+			vulnerabilities = append(vulnerabilities, cyclonedx.Vulnerability{ID: id})
+		}
+	}
+	slices.SortFunc(vulnerabilities, func(a, b cyclonedx.Vulnerability) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	for i := range vulnerabilities {
+		s, ok := statements[vulnerabilities[i].ID]
+		if !ok {
+			continue
+		}
+
+		affects := make([]cyclonedx.Affects, 0, len(s.affects))
+		for _, ref := range slices.Sorted(maps.Keys(s.affects)) {
+			versions := []cyclonedx.AffectedVersions{s.affects[ref]}
+			affects = append(affects, cyclonedx.Affects{Ref: ref, Range: &versions})
+		}
+		vulnerabilities[i].Affects = &affects
+		vulnerabilities[i].Analysis = cycloneDXAnalysis(s)
+	}
+	bom.Vulnerabilities = &vulnerabilities
+}
+
+// cycloneDXAnalysis returns the analysis of a vulnerability, or nil if it is
+// affecting components without having been analysed.
+func cycloneDXAnalysis(s *cycloneDXStatement) *cyclonedx.VulnerabilityAnalysis {
+	switch {
+	case s.affected && s.unanalysed:
+		return &cyclonedx.VulnerabilityAnalysis{State: cyclonedx.IASInTriage}
+	case s.affected:
+		return &cyclonedx.VulnerabilityAnalysis{State: cyclonedx.IASExploitable}
+	}
+
+	analysis := &cyclonedx.VulnerabilityAnalysis{State: cyclonedx.IASNotAffected}
+	var details []string
+	for _, finding := range s.notAffected {
+		justification := cyclonedx.IAJCodeNotReachable
+		if finding.Status == vexIgnored {
+			justification = cycloneDXJustifications[finding.Justification]
+			details = append(details, vexIgnoredReason(finding))
+		}
+		if analysis.Justification == "" {
+			analysis.Justification = justification
+		} else if analysis.Justification != justification {
+			// the components are not affected for different reasons
+			analysis.Justification = ""
+		}
+	}
+	slices.Sort(details)
+	analysis.Detail = strings.Join(slices.Compact(details), "; ")
+
+	return analysis
+}
+
+// cycloneDXJustifications maps the OpenVEX justifications of VEX documents
+// given to the scan to the closest CycloneDX ones.
+var cycloneDXJustifications = map[string]cyclonedx.ImpactAnalysisJustification{
+	"component_not_present":                             cyclonedx.IAJCodeNotPresent,
+	"vulnerable_code_not_present":                       cyclonedx.IAJCodeNotPresent,
+	"vulnerable_code_not_in_execute_path":               cyclonedx.IAJCodeNotReachable,
+	"vulnerable_code_cannot_be_controlled_by_adversary": cyclonedx.IAJProtectedByMitigatingControl,
+	"inline_mitigations_already_exist":                  cyclonedx.IAJProtectedByMitigatingControl,
+}
+
+// addCycloneDXComponent adds a component for a package that only has ignored
+// vulnerabilities, which is not in the results.
+func addCycloneDXComponent(bom *cyclonedx.BOM, ref string, pkg models.PackageInfo) {
+	components := *bom.Components
+	if slices.ContainsFunc(components, func(c cyclonedx.Component) bool { return c.BOMRef == ref }) {
+		return
+	}
+	components = append(components, cyclonedx.Component{
+		Type:       cyclonedx.ComponentTypeLibrary,
+		BOMRef:     ref,
+		PackageURL: ref,
+		Name:       pkg.Name,
+		Version:    pkg.Version,
+	})
+	slices.SortFunc(components, func(a, b cyclonedx.Component) int {
+		return strings.Compare(a.PackageURL, b.PackageURL)
+	})
+	bom.Components = &components
+}
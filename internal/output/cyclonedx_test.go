@@ -7,6 +7,7 @@ import (
 	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/internal/testutility"
 	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
 func testCycloneDXResults(t *testing.T, version models.CycloneDXVersion, testFunc func(*testing.T, func(*testing.T, outputTestCaseArgs))) {
@@ -47,3 +48,81 @@ func TestPrintCycloneDXResults(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintCycloneDXResults_Dependencies(t *testing.T) {
+	t.Parallel()
+
+	pkg := func(name, version string, parents ...models.PackageParent) models.PackageVulns {
+		return models.PackageVulns{
+			Package: models.PackageInfo{Name: name, Version: version, Ecosystem: "PyPI"},
+			Parents: parents,
+		}
+	}
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/requirements.txt", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					pkg("flask", "3.0.0"),
+					pkg("werkzeug", "3.0.1", models.PackageParent{Name: "flask", Version: "3.0.0", Requirement: ">=3.0.0"}),
+					pkg("markupsafe", "2.1.3",
+						models.PackageParent{Name: "werkzeug", Version: "3.0.1", Requirement: ">=2.1.1"},
+						models.PackageParent{Name: "jinja2", Version: "3.1.2", Requirement: ">=2.0"},
+					),
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintCycloneDXResults(vulnResult, models.CycloneDXVersion16, outputWriter); err != nil {
+		t.Errorf("%v", err)
+	}
+	testutility.NewSnapshot().MatchText(t, outputWriter.String())
+}
+
+func TestPrintCycloneDXResults_Analysis(t *testing.T) {
+	t.Parallel()
+
+	vuln := func(id string) *osvschema.Vulnerability {
+		return &osvschema.Vulnerability{Id: id, Summary: "summary of " + id}
+	}
+	group := func(id string, called *bool) models.GroupInfo {
+		g := models.GroupInfo{IDs: []string{id}, Aliases: []string{id}}
+		if called != nil {
+			g.ExperimentalAnalysis = map[string]models.AnalysisInfo{id: {Called: *called}}
+		}
+
+		return g
+	}
+	called, uncalled := true, false
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/go.mod", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "golang.org/x/net", Version: "0.1.0", Ecosystem: "Go"},
+						Vulnerabilities: []*osvschema.Vulnerability{vuln("GO-1"), vuln("GO-2"), vuln("GO-3")},
+						Groups:          []models.GroupInfo{group("GO-1", &called), group("GO-2", &uncalled), group("GO-3", nil)},
+					},
+				},
+			},
+		},
+		ExperimentalIgnored: []models.IgnoredVulnerability{
+			{
+				Source:  models.SourceInfo{Path: "/path/to/go.mod", Type: models.SourceTypeProjectPackage},
+				Package: models.PackageInfo{Name: "golang.org/x/text", Version: "0.3.0", Ecosystem: "Go"},
+				Group:   models.GroupInfo{IDs: []string{"GO-4"}, Aliases: []string{"GO-4"}},
+				Reason:  "only used in tests",
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintCycloneDXResults(vulnResult, models.CycloneDXVersion16, outputWriter); err != nil {
+		t.Errorf("%v", err)
+	}
+	testutility.NewSnapshot().MatchText(t, outputWriter.String())
+}
@@ -19,11 +19,17 @@ func createSourceRemediationTable(source models.PackageSource, groupedFixedVersi
 	for _, pv := range source.Packages {
 		for _, group := range pv.Groups {
 			fixedVersions := groupedFixedVersions[source.Source.String()+":"+group.IndexString()]
+			if fixedVersion := groupFixedVersion(group, pv); fixedVersion != "" {
+				fixedVersions = []string{fixedVersion}
+			}
 
 			vulnIDs := []string{}
 			for _, id := range group.IDs {
 				vulnIDs = append(vulnIDs, "https://osv.dev/"+id)
 			}
+			if group.KEV != nil {
+				vulnIDs = append(vulnIDs, KEVLabel)
+			}
 			remediationTable.AppendRow(table.Row{
 				pv.Package.Name,
 				strings.Join(vulnIDs, "\n"),
@@ -0,0 +1,206 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// dependencyGraph is the dependency graph of the packages in the results,
+// built from the parents and introduction chains found by transitive
+// dependency resolution.
+type dependencyGraph struct {
+	nodes []*dependencyNode
+	edges [][2]string
+}
+
+type dependencyNode struct {
+	// ID is "ecosystem:name@version", as packages with the same name and
+	// version in different ecosystems are not the same package
+	ID        string
+	Ecosystem string
+	// Label is "name@version", which is how packages are named in
+	// introduction chains
+	Label string
+	// VulnIDs are the first ID of each group of vulnerabilities found in the
+	// package, which is vulnerable if there are any
+	VulnIDs []string
+}
+
+func buildDependencyGraph(vulnResult *models.VulnerabilityResults) dependencyGraph {
+	var graph dependencyGraph
+	nodes := make(map[string]*dependencyNode)
+	edges := make(map[[2]string]bool)
+
+	node := func(ecosystem, label string) *dependencyNode {
+		id := ecosystem + ":" + label
+		n, ok := nodes[id]
+		if !ok {
+			n = &dependencyNode{ID: id, Ecosystem: ecosystem, Label: label}
+			nodes[id] = n
+			graph.nodes = append(graph.nodes, n)
+		}
+
+		return n
+	}
+	edge := func(from, to *dependencyNode) {
+		e := [2]string{from.ID, to.ID}
+		if from != to && !edges[e] {
+			edges[e] = true
+			graph.edges = append(graph.edges, e)
+		}
+	}
+
+	for _, source := range vulnResult.Results {
+		for _, pkg := range source.Packages {
+			ecosystem := pkg.Package.Ecosystem
+			n := node(ecosystem, pkg.Package.Name+"@"+pkg.Package.Version)
+			for _, group := range pkg.Groups {
+				if !slices.Contains(n.VulnIDs, group.IDs[0]) {
+					n.VulnIDs = append(n.VulnIDs, group.IDs[0])
+				}
+			}
+
+			for _, parent := range pkg.Parents {
+				edge(node(ecosystem, parent.Name+"@"+parent.Version), n)
+			}
+			for _, chain := range pkg.IntroducedBy {
+				for i := range chain {
+					to := n
+					if i+1 < len(chain) {
+						to = node(ecosystem, chain[i+1])
+					}
+					edge(node(ecosystem, chain[i]), to)
+				}
+			}
+		}
+	}
+
+	for _, n := range graph.nodes {
+		slices.Sort(n.VulnIDs)
+	}
+	slices.SortFunc(graph.nodes, func(a, b *dependencyNode) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	slices.SortFunc(graph.edges, func(a, b [2]string) int {
+		return strings.Compare(a[0]+"\x00"+a[1], b[0]+"\x00"+b[1])
+	})
+
+	return graph
+}
+
+// dotQuote quotes s as a DOT string.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// PrintDOTGraph writes the dependency graph of the packages in the results
+// in the Graphviz DOT language, highlighting the vulnerable packages.
+func PrintDOTGraph(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	graph := buildDependencyGraph(vulnResult)
+
+	var sb strings.Builder
+	sb.WriteString("digraph dependencies {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+	for _, n := range graph.nodes {
+		if len(n.VulnIDs) == 0 {
+			fmt.Fprintf(&sb, "  %s [label=%s];\n", dotQuote(n.ID), dotQuote(n.Label))
+			continue
+		}
+		fmt.Fprintf(&sb, "  %s [label=%s, color=\"#cc0000\", style=filled, fillcolor=\"#f4cccc\"];\n",
+			dotQuote(n.ID), dotQuote(n.Label+"\n"+strings.Join(n.VulnIDs, "\n")))
+	}
+	for _, e := range graph.edges {
+		fmt.Fprintf(&sb, "  %s -> %s;\n", dotQuote(e[0]), dotQuote(e[1]))
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(outputWriter, sb.String())
+
+	return err
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// PrintGraphMLGraph writes the dependency graph of the packages in the
+// results as GraphML, with the vulnerabilities found in each package as node
+// data.
+func PrintGraphMLGraph(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	graph := buildDependencyGraph(vulnResult)
+
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+			{ID: "ecosystem", For: "node", AttrName: "ecosystem", AttrType: "string"},
+			{ID: "vulnerable", For: "node", AttrName: "vulnerable", AttrType: "boolean"},
+			{ID: "vulnerabilities", For: "node", AttrName: "vulnerabilities", AttrType: "string"},
+		},
+		Graph: graphMLGraph{ID: "dependencies", EdgeDefault: "directed"},
+	}
+	for _, n := range graph.nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: n.ID,
+			Data: []graphMLData{
+				{Key: "label", Value: n.Label},
+				{Key: "ecosystem", Value: n.Ecosystem},
+				{Key: "vulnerable", Value: fmt.Sprint(len(n.VulnIDs) > 0)},
+				{Key: "vulnerabilities", Value: strings.Join(n.VulnIDs, " ")},
+			},
+		})
+	}
+	for _, e := range graph.edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{Source: e[0], Target: e[1]})
+	}
+
+	if _, err := io.WriteString(outputWriter, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(outputWriter)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(outputWriter, "\n")
+
+	return err
+}
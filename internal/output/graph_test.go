@@ -0,0 +1,65 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func graphResult() *models.VulnerabilityResults {
+	source := models.SourceInfo{Path: "/path/to/pom.xml", Type: models.SourceTypeProjectPackage}
+
+	return &models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source: source,
+			Packages: []models.PackageVulns{
+				{
+					Package: models.PackageInfo{Name: "org.example:direct", Version: "1.0.0", Ecosystem: "Maven"},
+					Parents: []models.PackageParent{{Name: "org.example:app", Version: "0.1.0"}},
+				},
+				{
+					Package:      models.PackageInfo{Name: "org.example:middle", Version: "2.0.0", Ecosystem: "Maven"},
+					Parents:      []models.PackageParent{{Name: "org.example:direct", Version: "1.0.0"}},
+					IntroducedBy: [][]string{{"org.example:app@0.1.0", "org.example:direct@1.0.0"}},
+				},
+				{
+					Package: models.PackageInfo{Name: "org.example:vulnerable", Version: "3.0.0", Ecosystem: "Maven"},
+					Parents: []models.PackageParent{
+						{Name: "org.example:middle", Version: "2.0.0"},
+						{Name: "org.example:other", Version: "1.1.0"},
+					},
+					IntroducedBy: [][]string{{"org.example:app@0.1.0", "org.example:direct@1.0.0", "org.example:middle@2.0.0"}},
+					Groups: []models.GroupInfo{
+						{IDs: []string{"GHSA-2", "CVE-2"}},
+						{IDs: []string{"GHSA-1"}},
+					},
+				},
+			},
+		}},
+	}
+}
+
+func TestPrintDOTGraph(t *testing.T) {
+	t.Parallel()
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintDOTGraph(graphResult(), outputWriter); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	testutility.NewSnapshot().MatchText(t, outputWriter.String())
+}
+
+func TestPrintGraphMLGraph(t *testing.T) {
+	t.Parallel()
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintGraphMLGraph(graphResult(), outputWriter); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	testutility.NewSnapshot().MatchText(t, outputWriter.String())
+}
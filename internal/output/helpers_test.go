@@ -32,9 +32,11 @@ type pkginfo struct {
 	Version       string
 	Ecosystem     string
 	Deprecated    bool
-	Commit        string
-	ImageOrigin   *models.ImageOriginDetails
-	Extractor     extractor.Extractor
+	// DeprecationReason is only set by tests that look up project statuses
+	DeprecationReason string
+	Commit            string
+	ImageOrigin       *models.ImageOriginDetails
+	Extractor         extractor.Extractor
 }
 
 func resolvePURLType(eco string) string {
@@ -52,13 +54,14 @@ func resolvePURLType(eco string) string {
 
 func newPackageInfo(source string, pi pkginfo) models.PackageInfo {
 	info := models.PackageInfo{
-		Name:          pi.Name,
-		OSPackageName: pi.OSPackageName,
-		Version:       pi.Version,
-		Ecosystem:     pi.Ecosystem,
-		Commit:        pi.Commit,
-		ImageOrigin:   pi.ImageOrigin,
-		Deprecated:    pi.Deprecated,
+		Name:              pi.Name,
+		OSPackageName:     pi.OSPackageName,
+		Version:           pi.Version,
+		Ecosystem:         pi.Ecosystem,
+		Commit:            pi.Commit,
+		ImageOrigin:       pi.ImageOrigin,
+		Deprecated:        pi.Deprecated,
+		DeprecationReason: pi.DeprecationReason,
 		Inventory: &extractor.Package{
 			Name:      pi.Name,
 			Version:   pi.Version,
@@ -2587,6 +2590,42 @@ func testOutputWithMixedIssues(t *testing.T, run outputTestRunner) {
 				},
 			},
 		},
+		{
+			name: "one_source_with_deprecated_packages_and_reasons",
+			args: outputTestCaseArgs{
+				vulnResult: &models.VulnerabilityResults{
+					Results: []models.PackageSource{
+						{
+							Source: models.SourceInfo{Path: cwd + "/path/to/lockfile", Type: models.SourceTypeProjectPackage},
+							Packages: []models.PackageVulns{
+								{
+									Package: newPackageInfo(cwd+"/path/to/lockfile", pkginfo{
+										Name:              "deprecated-pkg",
+										Version:           "1.0.0",
+										Ecosystem:         "npm",
+										Deprecated:        true,
+										DeprecationReason: "this package is no longer supported, use new-pkg instead",
+										Extractor:         packagelockjson.Extractor{},
+									}),
+									Vulnerabilities: []*osvschema.Vulnerability{},
+								},
+								{
+									Package: newPackageInfo(cwd+"/path/to/lockfile", pkginfo{
+										Name:              "archived-pkg",
+										Version:           "2.0.0",
+										Ecosystem:         "npm",
+										Deprecated:        true,
+										DeprecationReason: "source repository github.com/example/archived-pkg is archived",
+										Extractor:         packagelockjson.Extractor{},
+									}),
+									Vulnerabilities: []*osvschema.Vulnerability{},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
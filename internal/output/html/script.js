@@ -1,5 +1,13 @@
 const selectedTypeFilterValue = new Set(["all"]);
 let selectedLayer = "all";
+const selectedSeverities = new Set([
+  "critical",
+  "high",
+  "medium",
+  "low",
+  "unknown",
+]);
+let selectedEcosystem = "all";
 
 function toggleDetails(summaryID) {
   const detailsElementID = `${summaryID}-details`;
@@ -227,6 +235,8 @@ function applyFilters(selectedTypeFilterValue, selectedLayerFilterValue) {
   showAllVulns();
   applyTypeFilter(selectedTypeFilterValue);
   applyLayerFilter(selectedLayerFilterValue);
+  applySeverityFilter(selectedSeverities);
+  applyEcosystemFilter(selectedEcosystem);
   showAndHideParentSections();
 }
 
@@ -270,6 +280,65 @@ function applyLayerFilter(selectedLayerID) {
   });
 }
 
+function applySeverityFilter(selectedValue) {
+  updateSeverityFilterText();
+  const vulnRows = document.querySelectorAll(".vuln-tr[data-severity]");
+  vulnRows.forEach(row => {
+    if (!selectedValue.has(row.getAttribute("data-severity"))) {
+      row.classList.add("hide-block");
+    }
+  });
+}
+
+function applyEcosystemFilter(selectedValue) {
+  if (selectedValue === "all") {
+    return;
+  }
+
+  const ecosystemElements = document.querySelectorAll(".ecosystem-container");
+  ecosystemElements.forEach(ecosystemElement => {
+    if (ecosystemElement.getAttribute("data-ecosystem") !== selectedValue) {
+      ecosystemElement.querySelectorAll(".vuln-tr").forEach(vuln => {
+        vuln.classList.add("hide-block");
+      });
+    }
+  });
+}
+
+function updateSeverityFilterText() {
+  const severitySelected = document.getElementById("severity-filter-selected");
+  const checkboxes = document.querySelectorAll(".severity-checkbox");
+
+  const selected = Array.from(checkboxes).filter(checkbox => checkbox.checked);
+  if (selected.length === checkboxes.length) {
+    severitySelected.textContent = "All severities";
+  } else if (selected.length === 0) {
+    severitySelected.textContent = "None";
+  } else {
+    severitySelected.textContent = selected
+      .map(
+        checkbox => checkbox.value[0].toUpperCase() + checkbox.value.slice(1)
+      )
+      .join(", ");
+  }
+}
+
+function resetSeverityAndEcosystemFilters() {
+  document.querySelectorAll(".severity-checkbox").forEach(checkbox => {
+    checkbox.checked = true;
+    selectedSeverities.add(checkbox.value);
+  });
+  updateSeverityFilterText();
+
+  selectedEcosystem = "all";
+  const ecosystemSelected = document.getElementById(
+    "ecosystem-filter-selected"
+  );
+  if (ecosystemSelected) {
+    ecosystemSelected.textContent = "All ecosystems";
+  }
+}
+
 function updateTypeFilterText() {
   const typeSelected = document.getElementById("type-filter-selected");
   const selectedVulnCount = document.getElementById("selected-count");
@@ -380,6 +449,7 @@ function resetTypeCheckbox() {
 
 document.addEventListener("DOMContentLoaded", () => {
   resetFilterText();
+  updateSeverityFilterText();
   showAndHideParentSections();
 
   // Implement filter for vulnerability types
@@ -447,6 +517,45 @@ document.addEventListener("DOMContentLoaded", () => {
     });
   }
 
+  // Implement severity filter
+  const severityFilterOptions = document.getElementById(
+    "severity-filter-option-container"
+  );
+
+  severityFilterOptions.addEventListener("change", () => {
+    resetSearchText();
+    selectedSeverities.clear();
+    document.querySelectorAll(".severity-checkbox").forEach(checkbox => {
+      if (checkbox.checked) {
+        selectedSeverities.add(checkbox.value);
+      }
+    });
+
+    applyFilters(selectedTypeFilterValue, selectedLayer);
+  });
+
+  // Implement ecosystem filter
+  const ecosystemFilterOptions = document.getElementById(
+    "ecosystem-filter-option-container"
+  );
+
+  if (ecosystemFilterOptions) {
+    ecosystemFilterOptions.addEventListener("click", event => {
+      const clickedOption = event.target.closest(".ecosystem-filter-option");
+      if (clickedOption) {
+        resetSearchText();
+        selectedEcosystem = clickedOption.getAttribute("data-ecosystem");
+        const selectedDisplay = document.getElementById(
+          "ecosystem-filter-selected"
+        );
+        selectedDisplay.textContent = clickedOption.textContent.trim();
+
+        hideAllFilterOptions();
+        applyFilters(selectedTypeFilterValue, selectedLayer);
+      }
+    });
+  }
+
   // Hide filter options when clicking other parts
   const filterSections = document.querySelectorAll("div.filter");
 
@@ -464,6 +573,7 @@ document.addEventListener("DOMContentLoaded", () => {
     selectedTypeFilterValue.add("all");
     selectedLayer = "all";
     resetTypeCheckbox();
+    resetSeverityAndEcosystemFilters();
 
     const searchTerm = vulnSearchInput.value.trim().toLowerCase();
 
@@ -2,9 +2,13 @@ package output_test
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/packagelockjson"
 	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
 func TestPrintHTMLResults_WithVulnerabilities(t *testing.T) {
@@ -51,3 +55,49 @@ func TestPrintHTMLResults_WithMixedIssues(t *testing.T) {
 		}
 	})
 }
+
+func TestPrintHTMLResults_FiltersAndDependencyChains(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					{
+						Package: newPackageInfo("/path/to/package-lock.json", pkginfo{
+							Name:      "mine1",
+							Version:   "1.2.3",
+							Ecosystem: "npm",
+							Extractor: packagelockjson.Extractor{},
+						}),
+						IntroducedBy: [][]string{{"app@1.0.0", "lib@2.0.0"}},
+						Groups:       []models.GroupInfo{{IDs: []string{"OSV-1"}, MaxSeverity: "9.8"}},
+						Vulnerabilities: []*osvschema.Vulnerability{
+							{Id: "OSV-1", Summary: "Something scary!"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintHTMLResults(vulnResult, outputWriter); err != nil {
+		t.Fatalf("Error writing HTML output: %s", err)
+	}
+	got := outputWriter.String()
+
+	for _, want := range []string{
+		`data-ecosystem="npm"`,
+		`data-severity="critical"`,
+		`id="severity-filter-option-container"`,
+		`Introduced by 1 dependency chain</summary>`,
+		`<li>app@1.0.0 &gt; lib@2.0.0</li>`,
+		`href="https://osv.dev/OSV-1"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected HTML output to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// PrintJUnitResults writes results to the provided writer as a JUnit XML
+// report, so that CI systems can show them alongside test results.
+//
+// Each manifest or lockfile is a test suite, in which every package with
+// vulnerabilities is a failed test case. Packages whose vulnerabilities are
+// all uncalled or unimportant are reported as skipped instead.
+func PrintJUnitResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	result := BuildResults(vulnResult)
+	report := junitTestSuites{Name: "osv-scanner"}
+	workingDir := mustGetWorkingDirectory()
+
+	for _, eco := range result.Ecosystems {
+		for _, source := range eco.Sources {
+			suite := junitTestSuite{Name: relativeSourcePath(source, workingDir)}
+
+			for _, pkg := range source.Packages {
+				if len(pkg.RegularVulns) == 0 && len(pkg.HiddenVulns) == 0 {
+					continue
+				}
+				version := pkg.InstalledVersion
+				if version == "" {
+					version = pkg.Commit
+				}
+				testCase := junitTestCase{
+					Name:      pkg.Name + "@" + version,
+					ClassName: eco.Name,
+				}
+
+				if len(pkg.RegularVulns) > 0 {
+					testCase.Failure = &junitFailure{
+						Message: fmt.Sprintf("%d known %s", len(pkg.RegularVulns), Form(len(pkg.RegularVulns), "vulnerability", "vulnerabilities")),
+						Type:    "vulnerability",
+						Text:    junitFailureText(pkg.RegularVulns),
+					}
+					suite.Failures++
+				} else {
+					testCase.Skipped = &junitSkipped{
+						Message: fmt.Sprintf("%d uncalled or unimportant %s", len(pkg.HiddenVulns), Form(len(pkg.HiddenVulns), "vulnerability", "vulnerabilities")),
+					}
+					suite.Skipped++
+				}
+				suite.TestCases = append(suite.TestCases, testCase)
+			}
+
+			if len(suite.TestCases) == 0 {
+				continue
+			}
+			suite.Tests = len(suite.TestCases)
+			report.Tests += suite.Tests
+			report.Failures += suite.Failures
+			report.Skipped += suite.Skipped
+			report.Suites = append(report.Suites, suite)
+		}
+	}
+
+	if _, err := io.WriteString(outputWriter, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(outputWriter)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(outputWriter, "\n")
+
+	return err
+}
+
+// junitFailureText describes each vulnerability on its own line, with its
+// severity and the version that fixes it.
+func junitFailureText(vulns []VulnResult) string {
+	var sb strings.Builder
+	for _, vuln := range vulns {
+		sb.WriteString("https://osv.dev/" + vuln.ID)
+		if len(vuln.Aliases) > 0 {
+			fmt.Fprintf(&sb, " (%s)", strings.Join(vuln.Aliases, ", "))
+		}
+		if vuln.KEV != nil {
+			sb.WriteString(" " + KEVLabel)
+		}
+		fmt.Fprintf(&sb, " severity: %s", vuln.SeverityScore)
+		if vuln.IsFixable {
+			fmt.Fprintf(&sb, ", fixed in: %s", vuln.FixedVersion)
+		} else {
+			sb.WriteString(", no fix available")
+		}
+		if vuln.Description != "" {
+			fmt.Fprintf(&sb, "\n  %s", vuln.Description)
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,130 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/internal/utility/severity"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+var markdownSummarySeverityIcons = map[severity.Rating]string{
+	severity.CriticalRating: "🔴",
+	severity.HighRating:     "🟠",
+	severity.MediumRating:   "🟡",
+	severity.LowRating:      "🟢",
+	severity.UnknownRating:  "⚪",
+}
+
+// PrintMarkdownSummaryResults prints a compact Markdown summary of the results,
+// grouped by manifest or lockfile, which is meant to be posted as a comment on
+// a pull request.
+//
+// If newResult is not nil, it holds the vulnerabilities that were introduced
+// since a previous scan, and each finding is marked as either new or existing.
+func PrintMarkdownSummaryResults(vulnResult *models.VulnerabilityResults, newResult *models.VulnerabilityResults, outputWriter io.Writer) {
+	result := BuildResults(vulnResult)
+	workingDir := mustGetWorkingDirectory()
+
+	var isNew map[string]bool
+	if newResult != nil {
+		isNew = make(map[string]bool)
+		for _, vf := range newResult.Flatten() {
+			if vf.Vulnerability != nil {
+				isNew[markdownSummaryKey(vf.Source.String(), vf.Package.Name, vf.Package.Version, vf.Vulnerability.GetId())] = true
+			}
+		}
+	}
+
+	var sb strings.Builder
+	findings, newFindings, hidden := 0, 0, 0
+
+	for _, eco := range result.Ecosystems {
+		for _, source := range eco.Sources {
+			var rows []string
+			for _, pkg := range source.Packages {
+				hidden += len(pkg.HiddenVulns)
+				for _, vuln := range pkg.RegularVulns {
+					row := "|"
+					if isNew != nil {
+						marker := "Existing"
+						for _, id := range vuln.GroupIDs {
+							if isNew[markdownSummaryKey(source.Name, pkg.Name, pkg.InstalledVersion, id)] {
+								marker = "🆕 New"
+								newFindings++
+
+								break
+							}
+						}
+						row += " " + marker + " |"
+					}
+
+					fix := "No fix available"
+					if vuln.IsFixable {
+						fix = "Upgrade to `" + vuln.FixedVersion + "`"
+					}
+					score := vuln.SeverityScore
+					if score == VersionUnsupported {
+						score = strings.ToLower(string(severity.UnknownRating))
+					}
+
+					kev := ""
+					if vuln.KEV != nil {
+						kev = " ⚠️ **Known exploited**"
+					}
+
+					row += fmt.Sprintf(" %s | %s | [%s](https://osv.dev/%s)%s | %s %s | %s |",
+						pkg.Name,
+						pkg.InstalledVersion,
+						vuln.ID,
+						vuln.ID,
+						kev,
+						markdownSummarySeverityIcons[vuln.SeverityRating],
+						score,
+						fix,
+					)
+					rows = append(rows, row)
+				}
+			}
+
+			if len(rows) == 0 {
+				continue
+			}
+			findings += len(rows)
+
+			fmt.Fprintf(&sb, "\n### `%s`\n\n", relativeSourcePath(source, workingDir))
+			if isNew != nil {
+				sb.WriteString("| | Package | Version | Vulnerability | Severity | Fix |\n")
+				sb.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+			} else {
+				sb.WriteString("| Package | Version | Vulnerability | Severity | Fix |\n")
+				sb.WriteString("| --- | --- | --- | --- | --- |\n")
+			}
+			sb.WriteString(strings.Join(rows, "\n") + "\n")
+		}
+	}
+
+	fmt.Fprintln(outputWriter, "## OSV-Scanner")
+	fmt.Fprintln(outputWriter)
+
+	if findings == 0 {
+		fmt.Fprintln(outputWriter, "No vulnerabilities found.")
+	} else {
+		summary := fmt.Sprintf("Found %d %s", findings, Form(findings, "vulnerability", "vulnerabilities"))
+		if isNew != nil {
+			summary += fmt.Sprintf(", %d of which %s new", newFindings, Form(newFindings, "is", "are"))
+		}
+		fmt.Fprintln(outputWriter, summary+".")
+		fmt.Fprint(outputWriter, sb.String())
+	}
+
+	if hidden > 0 {
+		fmt.Fprintln(outputWriter)
+		fmt.Fprintf(outputWriter, "%d uncalled or unimportant %s not shown.\n", hidden, Form(hidden, "vulnerability was", "vulnerabilities were"))
+	}
+}
+
+func markdownSummaryKey(source, name, version, id string) string {
+	return source + ":" + name + "@" + version + ":" + id
+}
@@ -0,0 +1,85 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/packagelockjson"
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+func TestPrintMarkdownSummaryResults_WithVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	testOutputWithVulnerabilities(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		outputWriter := &bytes.Buffer{}
+		output.PrintMarkdownSummaryResults(args.vulnResult, nil, outputWriter)
+
+		testutility.NewSnapshot().MatchText(t, outputWriter.String())
+	})
+}
+
+func TestPrintMarkdownSummaryResults_WithMixedIssues(t *testing.T) {
+	t.Parallel()
+
+	testOutputWithMixedIssues(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		outputWriter := &bytes.Buffer{}
+		output.PrintMarkdownSummaryResults(args.vulnResult, nil, outputWriter)
+
+		testutility.NewSnapshot().MatchText(t, outputWriter.String())
+	})
+}
+
+func TestPrintMarkdownSummaryResults_NewFindings(t *testing.T) {
+	t.Parallel()
+
+	source := models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage}
+	vuln := func(id, fixed string) *osvschema.Vulnerability {
+		return &osvschema.Vulnerability{
+			Id:       id,
+			Severity: []*osvschema.Severity{{Type: osvschema.Severity_CVSS_V3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}},
+			Affected: []*osvschema.Affected{{
+				Package: &osvschema.Package{Name: "mine1", Ecosystem: "npm"},
+				Ranges: []*osvschema.Range{{
+					Type:   osvschema.Range_SEMVER,
+					Events: []*osvschema.Event{{Introduced: "0"}, {Fixed: fixed}},
+				}},
+			}},
+		}
+	}
+	pkg := models.PackageVulns{
+		Package: newPackageInfo("/path/to/package-lock.json", pkginfo{
+			Name:      "mine1",
+			Version:   "1.2.3",
+			Ecosystem: "npm",
+			Extractor: packagelockjson.Extractor{},
+		}),
+		Groups: []models.GroupInfo{
+			{IDs: []string{"OSV-1"}, MaxSeverity: "9.8"},
+			{IDs: []string{"OSV-2"}, MaxSeverity: "9.8"},
+		},
+		Vulnerabilities: []*osvschema.Vulnerability{vuln("OSV-1", "1.2.4"), vuln("OSV-2", "2.0.0")},
+	}
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{{Source: source, Packages: []models.PackageVulns{pkg}}},
+	}
+
+	newPkg := pkg
+	newPkg.Groups = pkg.Groups[1:]
+	newPkg.Vulnerabilities = pkg.Vulnerabilities[1:]
+	newResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{{Source: source, Packages: []models.PackageVulns{newPkg}}},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintMarkdownSummaryResults(vulnResult, newResult, outputWriter)
+
+	testutility.NewSnapshot().MatchText(t, outputWriter.String())
+}
@@ -16,6 +16,8 @@ func PrintMarkdownTableResults(vulnResult *models.VulnerabilityResults, outputWr
 
 	outputResult := BuildResults(vulnResult)
 
+	printMaliciousPackages(vulnResult, outputWriter)
+
 	// Add a newline to separate results from logs.
 	fmt.Fprintln(outputWriter)
 	if outputResult.IsContainerScanning {
@@ -43,7 +45,7 @@ func PrintMarkdownTableResults(vulnResult *models.VulnerabilityResults, outputWr
 		}
 	}
 
-	if len(licenseConfig.Allowlist) > 0 {
+	if licenseConfig.HasPolicy() {
 		outputLicenseViolationsTable := table.NewWriter()
 		outputLicenseViolationsTable.SetOutputMirror(outputWriter)
 		outputLicenseViolationsTable = licenseViolationsTableBuilder(outputLicenseViolationsTable, vulnResult)
@@ -61,4 +63,66 @@ func PrintMarkdownTableResults(vulnResult *models.VulnerabilityResults, outputWr
 		printPkgDeprecatedSummary(outputResult, outputWriter)
 		outputDeprecatedPackagesTable.RenderMarkdown()
 	}
+
+	outputScorecardTable := table.NewWriter()
+	outputScorecardTable.SetOutputMirror(outputWriter)
+	outputScorecardTable = scorecardTableBuilder(outputScorecardTable, vulnResult)
+
+	if outputScorecardTable.Length() > 0 {
+		outputScorecardTable.RenderMarkdown()
+	}
+
+	outputOutdatedPackagesTable := table.NewWriter()
+	outputOutdatedPackagesTable.SetOutputMirror(outputWriter)
+	outputOutdatedPackagesTable = outdatedPackagesTableBuilder(outputOutdatedPackagesTable, vulnResult)
+
+	if outputOutdatedPackagesTable.Length() > 0 {
+		outputOutdatedPackagesTable.RenderMarkdown()
+	}
+
+	outputTyposquatsTable := table.NewWriter()
+	outputTyposquatsTable.SetOutputMirror(outputWriter)
+	outputTyposquatsTable = typosquatsTableBuilder(outputTyposquatsTable, vulnResult)
+
+	if outputTyposquatsTable.Length() > 0 {
+		outputTyposquatsTable.RenderMarkdown()
+	}
+
+	outputUnverifiedProvenanceTable := table.NewWriter()
+	outputUnverifiedProvenanceTable.SetOutputMirror(outputWriter)
+	outputUnverifiedProvenanceTable = unverifiedProvenanceTableBuilder(outputUnverifiedProvenanceTable, vulnResult)
+
+	if outputUnverifiedProvenanceTable.Length() > 0 {
+		outputUnverifiedProvenanceTable.RenderMarkdown()
+	}
+
+	outputHashMismatchesTable := table.NewWriter()
+	outputHashMismatchesTable.SetOutputMirror(outputWriter)
+	outputHashMismatchesTable = hashMismatchesTableBuilder(outputHashMismatchesTable, vulnResult)
+
+	if outputHashMismatchesTable.Length() > 0 {
+		outputHashMismatchesTable.RenderMarkdown()
+	}
+
+	outputNotAffectedTable := table.NewWriter()
+	outputNotAffectedTable.SetOutputMirror(outputWriter)
+	outputNotAffectedTable = notAffectedTableBuilder(outputNotAffectedTable, vulnResult)
+
+	if outputNotAffectedTable.Length() > 0 {
+		outputNotAffectedTable.RenderMarkdown()
+	}
+
+	if len(vulnResult.ExperimentalDrift) > 0 {
+		outputDriftTable := table.NewWriter()
+		outputDriftTable.SetOutputMirror(outputWriter)
+		outputDriftTable = driftTableBuilder(outputDriftTable, vulnResult)
+		outputDriftTable.RenderMarkdown()
+	}
+
+	if len(vulnResult.ExperimentalUpgradePlan) > 0 {
+		outputUpgradePlanTable := table.NewWriter()
+		outputUpgradePlanTable.SetOutputMirror(outputWriter)
+		outputUpgradePlanTable = upgradePlanTableBuilder(outputUpgradePlanTable, vulnResult)
+		outputUpgradePlanTable.RenderMarkdown()
+	}
 }
@@ -0,0 +1,96 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+
+	"github.com/google/osv-scanner/v2/internal/utility/vulns"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// ndjsonFinding is a group of aliased vulnerabilities found in a package,
+// written as a single line of NDJSON.
+type ndjsonFinding struct {
+	Source        models.SourceInfo  `json:"source"`
+	Package       models.PackageInfo `json:"package"`
+	ID            string             `json:"id"`
+	Aliases       []string           `json:"aliases,omitempty"`
+	Summary       string             `json:"summary,omitempty"`
+	MaxSeverity   string             `json:"max_severity,omitempty"`
+	FixedVersion  string             `json:"fixed_version,omitempty"`
+	FixedVersions []string           `json:"fixed_versions,omitempty"`
+	Called        bool               `json:"called"`
+	Unimportant   bool               `json:"unimportant,omitempty"`
+	IntroducedBy  [][]string         `json:"introduced_by,omitempty"`
+	KEV           *models.KEVEntry   `json:"kev,omitempty"`
+	Malicious     bool               `json:"malicious,omitempty"`
+}
+
+// PrintNDJSONResults writes results to the provided writer as newline
+// delimited JSON, with each group of aliased vulnerabilities found in a
+// package on its own line.
+func PrintNDJSONResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	for _, source := range vulnResult.Results {
+		if err := PrintNDJSONSource(source, outputWriter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrintNDJSONSource writes the results of a single source to the provided
+// writer as PrintNDJSONResults does, so that the findings can be streamed as
+// each source is matched.
+func PrintNDJSONSource(source models.PackageSource, outputWriter io.Writer) error {
+	encoder := json.NewEncoder(outputWriter)
+
+	for _, pkg := range source.Packages {
+		for _, group := range pkg.Groups {
+			if err := encoder.Encode(newNDJSONFinding(source.Source, pkg, group)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func newNDJSONFinding(source models.SourceInfo, pkg models.PackageVulns, group models.GroupInfo) ndjsonFinding {
+	finding := ndjsonFinding{
+		Source:       source,
+		Package:      pkg.Package,
+		ID:           group.IDs[0],
+		MaxSeverity:  group.MaxSeverity,
+		FixedVersion: groupFixedVersion(group, pkg),
+		Called:       group.IsCalled(),
+		Unimportant:  group.IsGroupUnimportant(),
+		IntroducedBy: pkg.IntroducedBy,
+		KEV:          group.KEV,
+		Malicious:    group.Malicious,
+	}
+
+	for _, alias := range group.Aliases {
+		if alias != finding.ID {
+			finding.Aliases = append(finding.Aliases, alias)
+		}
+	}
+	slices.Sort(finding.Aliases)
+	finding.Aliases = slices.Compact(finding.Aliases)
+
+	key := vulns.PackageKey{Ecosystem: pkg.Package.Ecosystem, Name: pkg.Package.Name}
+	for _, vuln := range pkg.Vulnerabilities {
+		if !slices.Contains(group.IDs, vuln.GetId()) {
+			continue
+		}
+		if finding.Summary == "" {
+			finding.Summary = vuln.GetSummary()
+		}
+		finding.FixedVersions = append(finding.FixedVersions, vulns.GetFixedVersions(vuln)[key]...)
+	}
+	slices.Sort(finding.FixedVersions)
+	finding.FixedVersions = slices.Compact(finding.FixedVersions)
+
+	return finding
+}
@@ -0,0 +1,131 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/utility/purl"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/google/uuid"
+)
+
+const openVEXContext = "https://openvex.dev/ns/v0.2.0"
+
+// OpenVEX statuses and justifications used in the statements.
+const (
+	openVEXAffected    = "affected"
+	openVEXNotAffected = "not_affected"
+
+	openVEXNotInExecutePath = "vulnerable_code_not_in_execute_path"
+)
+
+type openVEXDocument struct {
+	Context    string             `json:"@context"`
+	ID         string             `json:"@id"`
+	Author     string             `json:"author"`
+	Timestamp  string             `json:"timestamp"`
+	Version    int                `json:"version"`
+	Tooling    string             `json:"tooling"`
+	Statements []openVEXStatement `json:"statements"`
+}
+
+type openVEXStatement struct {
+	Vulnerability   openVEXVulnerability `json:"vulnerability"`
+	Products        []openVEXProduct     `json:"products"`
+	Status          string               `json:"status"`
+	Justification   string               `json:"justification,omitempty"`
+	ImpactStatement string               `json:"impact_statement,omitempty"`
+	ActionStatement string               `json:"action_statement,omitempty"`
+}
+
+type openVEXVulnerability struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+type openVEXProduct struct {
+	ID          string            `json:"@id"`
+	Identifiers map[string]string `json:"identifiers,omitempty"`
+}
+
+// PrintOpenVEXResults writes results to the provided writer as an OpenVEX
+// document, with a statement for each group of aliased vulnerabilities found
+// in a package.
+//
+// Vulnerabilities are stated to affect the package unless they were ignored
+// in the config, or call analysis found that their vulnerable code is not
+// called.
+func PrintOpenVEXResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	statements := []openVEXStatement{}
+	for _, finding := range vexFindings(vulnResult) {
+		product, ok := openVEXProductFor(finding.Package)
+		if !ok {
+			continue
+		}
+		statement := openVEXStatement{
+			Vulnerability: openVEXVulnerabilityFor(finding.Aliases),
+			Products:      []openVEXProduct{product},
+		}
+		switch finding.Status {
+		case vexAffected:
+			statement.Status = openVEXAffected
+			statement.ActionStatement = vexAction(finding)
+		case vexNotCalled:
+			statement.Status = openVEXNotAffected
+			statement.Justification = openVEXNotInExecutePath
+		case vexIgnored:
+			statement.Status = openVEXNotAffected
+			if finding.Justification != "" {
+				statement.Justification = finding.Justification
+				statement.ImpactStatement = finding.Reason
+			} else {
+				statement.ImpactStatement = vexIgnoredReason(finding)
+			}
+		}
+		statements = append(statements, statement)
+	}
+
+	doc := openVEXDocument{
+		Context:    openVEXContext,
+		ID:         "https://openvex.dev/docs/public/vex-" + uuid.New().String(),
+		Author:     "osv-scanner",
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Version:    1,
+		Tooling:    "osv-scanner",
+		Statements: statements,
+	}
+
+	encoder := json.NewEncoder(outputWriter)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(doc)
+}
+
+// openVEXProductFor identifies a package by its package URL, reporting false
+// if it does not have one.
+func openVEXProductFor(pkg models.PackageInfo) (openVEXProduct, bool) {
+	packageURL, err := purl.FromPackage(pkg)
+	if err != nil {
+		return openVEXProduct{}, false
+	}
+	p := packageURL.ToString()
+
+	return openVEXProduct{ID: p, Identifiers: map[string]string{"purl": p}}, true
+}
+
+// openVEXVulnerabilityFor names a group of aliased vulnerabilities after its
+// first ID, with its other IDs and aliases as aliases.
+func openVEXVulnerabilityFor(group models.GroupInfo) openVEXVulnerability {
+	name := group.IDs[0]
+	var aliases []string
+	for _, alias := range group.Aliases {
+		if alias != name {
+			aliases = append(aliases, alias)
+		}
+	}
+	slices.Sort(aliases)
+
+	return openVEXVulnerability{Name: name, Aliases: slices.Compact(aliases)}
+}
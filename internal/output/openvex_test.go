@@ -0,0 +1,109 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func normalizeOpenVEXOutput(t *testing.T, str string) string {
+	t.Helper()
+
+	str = cachedregexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`).ReplaceAllString(str, `<uuid>`)
+	str = cachedregexp.MustCompile(`"timestamp": ".+T.+Z"`).ReplaceAllString(str, `"timestamp": "<timestamp>"`)
+
+	return str
+}
+
+func TestPrintOpenVEXResults_WithVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	testOutputWithVulnerabilities(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		outputWriter := &bytes.Buffer{}
+		err := output.PrintOpenVEXResults(args.vulnResult, outputWriter)
+
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+
+		testutility.NewSnapshot().MatchText(t, normalizeOpenVEXOutput(t, outputWriter.String()))
+	})
+}
+
+func TestPrintOpenVEXResults_WithMixedIssues(t *testing.T) {
+	t.Parallel()
+
+	testOutputWithMixedIssues(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		outputWriter := &bytes.Buffer{}
+		err := output.PrintOpenVEXResults(args.vulnResult, outputWriter)
+
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+
+		testutility.NewSnapshot().MatchText(t, normalizeOpenVEXOutput(t, outputWriter.String()))
+	})
+}
+
+func TestPrintOpenVEXResults_Ignored(t *testing.T) {
+	t.Parallel()
+
+	ignored := func(reason string, ids ...string) models.IgnoredVulnerability {
+		return models.IgnoredVulnerability{
+			Source:  models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+			Package: models.PackageInfo{Name: "mine1", Version: "1.2.3", Ecosystem: "npm"},
+			Group:   models.GroupInfo{IDs: ids[:1], Aliases: ids},
+			Reason:  reason,
+		}
+	}
+	vulnResult := &models.VulnerabilityResults{
+		ExperimentalIgnored: []models.IgnoredVulnerability{
+			ignored("only used in tests", "GHSA-123", "CVE-2024-123"),
+			ignored("", "OSV-2"),
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintOpenVEXResults(vulnResult, outputWriter); err != nil {
+		t.Errorf("%v", err)
+	}
+	testutility.NewSnapshot().MatchText(t, normalizeOpenVEXOutput(t, outputWriter.String()))
+}
+
+func TestPrintOpenVEXResults_NotAffected(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		ExperimentalIgnored: []models.IgnoredVulnerability{
+			{
+				Source:        models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Package:       models.PackageInfo{Name: "mine1", Version: "1.2.3", Ecosystem: "npm"},
+				Group:         models.GroupInfo{IDs: []string{"GHSA-123"}, Aliases: []string{"GHSA-123", "CVE-2024-123"}},
+				Justification: "vulnerable_code_not_present",
+				VEXSource:     "/path/to/vex.json",
+			},
+			{
+				Source:        models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Package:       models.PackageInfo{Name: "mine2", Version: "1.0.0", Ecosystem: "npm"},
+				Group:         models.GroupInfo{IDs: []string{"OSV-2"}, Aliases: []string{"OSV-2"}},
+				Reason:        "the affected function is patched out at build time",
+				Justification: "inline_mitigations_already_exist",
+				VEXSource:     "/path/to/vex.json",
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintOpenVEXResults(vulnResult, outputWriter); err != nil {
+		t.Errorf("%v", err)
+	}
+	testutility.NewSnapshot().MatchText(t, normalizeOpenVEXOutput(t, outputWriter.String()))
+}
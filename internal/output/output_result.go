@@ -68,8 +68,11 @@ type PackageResult struct {
 	VulnCount         VulnCount
 	Licenses          []models.License
 	LicenseViolations []models.License
-	DepGroups         []string `json:"-"`
-	Deprecated        bool     `json:",omitempty"`
+	DepGroups         []string           `json:"-"`
+	IntroducedBy      [][]string         `json:"-"`
+	Deprecated        bool               `json:",omitempty"`
+	DeprecationReason string             `json:",omitempty"`
+	Dependents        *models.Dependents `json:",omitempty"`
 }
 
 // VulnResult represents a single vulnerability.
@@ -84,12 +87,16 @@ type VulnResult struct {
 	VulnAnalysisType VulnAnalysisType
 	SeverityRating   severity.Rating
 	SeverityScore    string
+	EPSS             *models.EPSSScore `json:",omitempty"`
+	KEV              *models.KEVEntry  `json:",omitempty"`
+	Malicious        bool              `json:",omitempty"`
 }
 
 type ImageInfo struct {
-	OS            string
-	AllLayers     []LayerInfo
-	AllBaseImages []BaseImageGroupInfo
+	OS               string
+	AllLayers        []LayerInfo
+	AllBaseImages    []BaseImageGroupInfo
+	BaseImageUpgrade *models.BaseImageUpgrade
 }
 
 type LicenseSummary struct {
@@ -263,7 +270,7 @@ func buildResult(ecosystemMap map[string][]SourceResult, resultCount VulnCount,
 		}
 	}
 
-	if len(licenseConfig.Allowlist) != 0 {
+	if licenseConfig.HasPolicy() {
 		result.LicenseSummary.ShowViolations = true
 	}
 
@@ -331,9 +338,10 @@ func populateResultWithImageMetadata(result *Result, imageMetadata models.ImageM
 	})
 
 	result.ImageInfo = ImageInfo{
-		OS:            imageMetadata.OS,
-		AllLayers:     allLayers,
-		AllBaseImages: allBaseImages,
+		OS:               imageMetadata.OS,
+		AllLayers:        allLayers,
+		AllBaseImages:    allBaseImages,
+		BaseImageUpgrade: imageMetadata.BaseImageUpgrade,
 	}
 
 	if len(allLayers) != 0 {
@@ -481,7 +489,10 @@ func processPackage(vulnPkg models.PackageVulns) PackageResult {
 		Licenses:          vulnPkg.Licenses,
 		LicenseViolations: vulnPkg.LicenseViolations,
 		DepGroups:         vulnPkg.DepGroups,
+		IntroducedBy:      vulnPkg.IntroducedBy,
 		Deprecated:        vulnPkg.Package.Deprecated,
+		DeprecationReason: vulnPkg.Package.DeprecationReason,
+		Dependents:        vulnPkg.Dependents,
 	}
 
 	return packageResult
@@ -515,12 +526,21 @@ func processVulnGroups(vulnPkg models.PackageVulns) (map[string]VulnResult, map[
 		}
 
 		vuln.SeverityScore = group.MaxSeverity
+		if group.FixedVersion != "" {
+			vuln.FixedVersion = group.FixedVersion
+			vuln.IsFixable = true
+		}
+		vuln.EPSS = group.EPSS
+		vuln.KEV = group.KEV
+		vuln.Malicious = group.Malicious
 		vuln.SeverityRating, _ = severity.CalculateRating(vuln.SeverityScore)
 		if vuln.SeverityRating == severity.UnknownRating {
 			vuln.SeverityScore = "N/A"
 		}
 
-		if group.IsCalled() && !group.IsGroupUnimportant() {
+		// Malicious packages are always shown, as their code runs as soon
+		// as they are installed
+		if group.Malicious || (group.IsCalled() && !group.IsGroupUnimportant()) {
 			vuln.VulnAnalysisType = VulnTypeRegular
 			regularVulnMap[representID] = vuln
 		} else if group.IsGroupUnimportant() {
@@ -540,8 +560,11 @@ func updateVuln(vulnMap map[string]VulnResult, vulnPkg models.PackageVulns) {
 	for _, vuln := range vulnPkg.Vulnerabilities {
 		fixable, fixedVersion := getNextFixVersion(vuln.GetAffected(), vulnPkg.Package.Version, vulnPkg.Package.Name, vulnPkg.Package.Ecosystem)
 		if outputVuln, exist := vulnMap[vuln.GetId()]; exist {
-			outputVuln.FixedVersion = fixedVersion
-			outputVuln.IsFixable = fixable
+			// Prefer the fixed version of the whole group, if it was computed
+			if !outputVuln.IsFixable {
+				outputVuln.FixedVersion = fixedVersion
+				outputVuln.IsFixable = fixable
+			}
 			outputVuln.Description = vuln.GetSummary()
 			if outputVuln.Description == "" {
 				outputVuln.Description = vuln.GetDetails()
@@ -601,6 +624,47 @@ func getNextFixVersion(allAffected []*osvschema.Affected, installedVersion strin
 	return hasFixedVersion, minFixVersion
 }
 
+// FixedVersion is the first version of a package that fixes a group of
+// aliased vulnerabilities found in it. As each advisory in the group may
+// give a different fixed version, the highest of them is used, so that
+// upgrading to it fixes the vulnerability according to all of them.
+//
+// It is empty if none of the advisories give a fixed version above the
+// installed one, or if the installed version cannot be parsed.
+func FixedVersion(group models.GroupInfo, pkg models.PackageVulns) string {
+	ecosystemPrefix := strings.Split(pkg.Package.Ecosystem, ":")[0]
+	fixedVersion := ""
+	for _, vuln := range pkg.Vulnerabilities {
+		if !slices.Contains(group.IDs, vuln.GetId()) {
+			continue
+		}
+		fixable, version := getNextFixVersion(vuln.GetAffected(), pkg.Package.Version, pkg.Package.Name, pkg.Package.Ecosystem)
+		if !fixable {
+			continue
+		}
+		if fixedVersion == "" {
+			fixedVersion = version
+			continue
+		}
+		if order, _ := semantic.MustParse(version, ecosystemPrefix).CompareStr(fixedVersion); order > 0 {
+			fixedVersion = version
+		}
+	}
+
+	return fixedVersion
+}
+
+// groupFixedVersion is the fixed version of a group in the results, or for
+// results that do not have one, such as those from older versions of
+// osv-scanner, the fixed version computed from the package's vulnerabilities.
+func groupFixedVersion(group models.GroupInfo, pkg models.PackageVulns) string {
+	if group.FixedVersion != "" {
+		return group.FixedVersion
+	}
+
+	return FixedVersion(group, pkg)
+}
+
 // calculatePackageFixedVersion determines the highest version that resolves the most known vulnerabilities for a package.
 func calculatePackageFixedVersion(ecosystem string, allVulns []VulnResult) string {
 	ecosystemPrefix := strings.Split(ecosystem, ":")[0]
@@ -842,6 +906,41 @@ func printPkgDeprecatedSummary(result Result, out io.Writer) {
 	fmt.Fprintln(out, summary)
 }
 
+func printBaseImageUpgradeSummary(upgrade models.BaseImageUpgrade, out io.Writer) {
+	fmt.Fprintln(out, describeBaseImageUpgrade(upgrade)+"\n")
+}
+
+// describeBaseImageUpgrade summarizes how many of the vulnerabilities from the
+// base image upgrading it would eliminate.
+func describeBaseImageUpgrade(upgrade models.BaseImageUpgrade) string {
+	candidate := upgrade.Candidate
+	if upgrade.Digest.Validate() == nil && len(upgrade.Digest.Encoded()) > 12 {
+		candidate += " (" + upgrade.Digest.Algorithm().String() + ":" + upgrade.Digest.Encoded()[:12] + ")"
+	}
+
+	if upgrade.UpToDate {
+		return fmt.Sprintf("The base image %s is already up to date with %s.", upgrade.BaseImage, candidate)
+	}
+
+	vulnerabilityForm := Form(upgrade.Vulnerabilities, "vulnerability", "vulnerabilities")
+	if upgrade.Eliminated == 0 {
+		return fmt.Sprintf(
+			"Upgrading the base image %s to %s would not eliminate any of its %d %s.",
+			upgrade.BaseImage, candidate, upgrade.Vulnerabilities, vulnerabilityForm,
+		)
+	}
+
+	summary := fmt.Sprintf(
+		"Upgrading the base image %s to %s would eliminate %d of its %d %s",
+		upgrade.BaseImage, candidate, upgrade.Eliminated, upgrade.Vulnerabilities, vulnerabilityForm,
+	)
+	if upgrade.Introduced > 0 {
+		summary += fmt.Sprintf(", while introducing %d new %s", upgrade.Introduced, Form(upgrade.Introduced, "one", "ones"))
+	}
+
+	return summary + "."
+}
+
 func getInstalledVersionOrCommit(pkg PackageResult) string {
 	result := pkg.InstalledVersion
 	if result == "" && pkg.Commit != "" {
@@ -0,0 +1,57 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func Test_describeBaseImageUpgrade(t *testing.T) {
+	t.Parallel()
+
+	const imgDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		name    string
+		upgrade models.BaseImageUpgrade
+		want    string
+	}{
+		{
+			name: "up_to_date",
+			upgrade: models.BaseImageUpgrade{
+				BaseImage: "python", Candidate: "python:latest", Digest: imgDigest, UpToDate: true, Vulnerabilities: 4,
+			},
+			want: "The base image python is already up to date with python:latest (sha256:0123456789ab).",
+		},
+		{
+			name: "eliminates_vulnerabilities",
+			upgrade: models.BaseImageUpgrade{
+				BaseImage: "python", Candidate: "python:latest", Digest: imgDigest, Vulnerabilities: 30, Eliminated: 12,
+			},
+			want: "Upgrading the base image python to python:latest (sha256:0123456789ab) would eliminate 12 of its 30 vulnerabilities.",
+		},
+		{
+			name: "eliminates_and_introduces_vulnerabilities",
+			upgrade: models.BaseImageUpgrade{
+				BaseImage: "python", Candidate: "python:latest", Digest: imgDigest, Vulnerabilities: 30, Eliminated: 12, Introduced: 1,
+			},
+			want: "Upgrading the base image python to python:latest (sha256:0123456789ab) would eliminate 12 of its 30 vulnerabilities, while introducing 1 new one.",
+		},
+		{
+			name: "eliminates_nothing",
+			upgrade: models.BaseImageUpgrade{
+				BaseImage: "alpine", Candidate: "alpine:3.20", Vulnerabilities: 1,
+			},
+			want: "Upgrading the base image alpine to alpine:3.20 would not eliminate any of its 1 vulnerability.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := describeBaseImageUpgrade(tt.upgrade); got != tt.want {
+				t.Errorf("describeBaseImageUpgrade() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
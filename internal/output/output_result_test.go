@@ -6,6 +6,8 @@ import (
 
 	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
 func TestPrintOutputResults_WithVulnerabilities(t *testing.T) {
@@ -24,3 +26,70 @@ func TestPrintOutputResults_WithVulnerabilities(t *testing.T) {
 		testutility.NewSnapshot().MatchText(t, outputWriter.String())
 	})
 }
+
+func TestFixedVersion(t *testing.T) {
+	t.Parallel()
+
+	affected := func(fixed ...string) []*osvschema.Affected {
+		events := []*osvschema.Event{{Introduced: "0"}}
+		for _, f := range fixed {
+			events = append(events, &osvschema.Event{Fixed: f})
+		}
+
+		return []*osvschema.Affected{{
+			Package: &osvschema.Package{Name: "foo", Ecosystem: "npm"},
+			Ranges:  []*osvschema.Range{{Type: osvschema.Range_SEMVER, Events: events}},
+		}}
+	}
+
+	pkg := models.PackageVulns{
+		Package: models.PackageInfo{Name: "foo", Version: "1.5.0", Ecosystem: "npm"},
+		Vulnerabilities: []*osvschema.Vulnerability{
+			{Id: "GHSA-1", Affected: affected("1.2.0", "1.6.0", "2.0.0")},
+			{Id: "CVE-1", Affected: affected("1.7.0")},
+			{Id: "GHSA-2", Affected: affected()},
+			{Id: "GHSA-3", Affected: affected("1.4.0")},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		group models.GroupInfo
+		want  string
+	}{
+		{
+			name:  "first fixed version above the installed one",
+			group: models.GroupInfo{IDs: []string{"GHSA-1"}},
+			want:  "1.6.0",
+		},
+		{
+			name:  "highest of the fixed versions of the aliases",
+			group: models.GroupInfo{IDs: []string{"CVE-1", "GHSA-1"}},
+			want:  "1.7.0",
+		},
+		{
+			name:  "aliases without a fix are skipped",
+			group: models.GroupInfo{IDs: []string{"GHSA-1", "GHSA-2"}},
+			want:  "1.6.0",
+		},
+		{
+			name:  "no fix",
+			group: models.GroupInfo{IDs: []string{"GHSA-2"}},
+			want:  "",
+		},
+		{
+			name:  "only fixed below the installed version",
+			group: models.GroupInfo{IDs: []string{"GHSA-3"}},
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := output.FixedVersion(tt.group, pkg); got != tt.want {
+				t.Errorf("FixedVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
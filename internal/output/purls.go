@@ -0,0 +1,30 @@
+package output
+
+import (
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/internal/utility/purl"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// PrintPURLResults writes the package URL of each package in the results to
+// the provided writer, one per line, sorted and without duplicates.
+//
+// Packages whose package URL cannot be determined are left out, and the
+// errors are returned after everything else has been written.
+func PrintPURLResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	resultsByPurl, errs := purl.Group(vulnResult.Results)
+
+	var sb strings.Builder
+	for _, p := range slices.Sorted(maps.Keys(resultsByPurl)) {
+		sb.WriteString(p + "\n")
+	}
+
+	_, err := io.WriteString(outputWriter, sb.String())
+
+	return errors.Join(err, errors.Join(errs...))
+}
@@ -6,9 +6,11 @@ import (
 	"log"
 	"maps"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 
+	"github.com/google/osv-scanner/v2/internal/grouper"
 	"github.com/google/osv-scanner/v2/internal/identifiers"
 	"github.com/google/osv-scanner/v2/internal/utility/vulns"
 	"github.com/google/osv-scanner/v2/pkg/models"
@@ -80,6 +82,15 @@ func mustGetWorkingDirectory() string {
 	return dir
 }
 
+// relativeSourcePath returns the path of a source, relative to workingDir when
+// it is within it
+func relativeSourcePath(source SourceResult, workingDir string) string {
+	p := strings.TrimPrefix(source.Name, string(source.Type)+":")
+	p = strings.TrimPrefix(p, filepath.ToSlash(workingDir))
+
+	return strings.TrimPrefix(p, "/")
+}
+
 // groupFixedVersions builds the fixed versions for each ID Group, with keys formatted like so:
 // `Source:ID`
 func groupFixedVersions(flattened []models.VulnerabilityFlattened) map[string][]string {
@@ -120,6 +131,8 @@ type groupedSARIFFinding struct {
 	// AliasedIDList contains all aliased IDs, including ones that are not OSV (e.g. CVE IDs)
 	// Sorted by idSortFunc, therefore the first element will be the display ID
 	AliasedIDList []string
+	// KEV is the CISA KEV catalog entry of the group, if it is listed
+	KEV *models.KEVEntry `json:",omitempty"`
 }
 
 // UnmarshalJSON implements the json.unmarshaler interface.
@@ -200,30 +213,57 @@ func (g *groupedSARIFFinding) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// aliasGroups maps each ID of the vulnerabilities in the results to the group
+// of all its aliases across every package and source, including the ignored
+// ones, so that a vulnerability known by different IDs in different
+// ecosystems is reported as one.
+func aliasGroups(vulnResults *models.VulnerabilityResults) map[string]models.GroupInfo {
+	var groups []models.GroupInfo
+	for _, res := range vulnResults.Results {
+		for _, pkg := range res.Packages {
+			groups = append(groups, pkg.Groups...)
+		}
+	}
+	for _, ignored := range vulnResults.ExperimentalIgnored {
+		groups = append(groups, ignored.Group)
+	}
+
+	byID := make(map[string]models.GroupInfo)
+	for _, merged := range grouper.Merge(groups) {
+		for _, id := range merged.IDs {
+			byID[id] = merged
+		}
+	}
+
+	return byID
+}
+
 // mapIDsToGroupedSARIFFinding creates a map over all vulnerability IDs, with aliased vuln IDs
-// pointing to the same groupedSARIFFinding object
+// pointing to the same groupedSARIFFinding object, even when found in different packages
 func mapIDsToGroupedSARIFFinding(vulnResults *models.VulnerabilityResults) map[string]*groupedSARIFFinding {
 	// Map of vuln IDs to their respective groupedSARIFFinding
 	results := map[string]*groupedSARIFFinding{}
+	// Map of the first ID of each group of aliases across all packages to its groupedSARIFFinding
+	findings := map[string]*groupedSARIFFinding{}
+	aliases := aliasGroups(vulnResults)
 
 	for _, res := range vulnResults.Results {
 		for _, pkg := range res.Packages {
 			for _, gi := range pkg.Groups {
-				var data *groupedSARIFFinding
-				// See if this vulnerability group already exists (from another package or source)
-				for _, id := range gi.IDs {
-					existingData, ok := results[id]
-					if ok {
-						data = existingData
-						break
-					}
-				}
+				// See if this vulnerability group already exists (from another package or source),
+				// possibly under different IDs which are aliases of these ones
+				key := aliases[gi.IDs[0]].IDs[0]
+				data, ok := findings[key]
 				// If not create this group
-				if data == nil {
+				if !ok {
 					data = &groupedSARIFFinding{
 						PkgSource:    make(pkgSourceSet),
 						AliasedVulns: make(map[string]*osvschema.Vulnerability),
 					}
+					findings[key] = data
+				}
+				if gi.KEV != nil {
+					data.KEV = gi.KEV
 				}
 				// Point all the IDs of the same group to the same data, either newly created or existing
 				for _, id := range gi.IDs {
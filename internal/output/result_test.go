@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/osv-scanner/v2/internal/testutility"
 	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
 func Test_groupFixedVersions(t *testing.T) {
@@ -79,3 +80,52 @@ func Test_mapIDsToGroupedSARIFFinding(t *testing.T) {
 		})
 	}
 }
+
+func Test_mapIDsToGroupedSARIFFinding_AcrossPackages(t *testing.T) {
+	t.Parallel()
+
+	vulnResults := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/requirements.txt", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "foo", Version: "1.0.0", Ecosystem: "PyPI"},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"PYSEC-1"}, Aliases: []string{"CVE-1", "PYSEC-1"}},
+						},
+						Vulnerabilities: []*osvschema.Vulnerability{
+							{Id: "PYSEC-1", Aliases: []string{"CVE-1"}},
+						},
+					},
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "foo", Version: "1.0.0", Ecosystem: "npm"},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-1"}, Aliases: []string{"CVE-1", "GHSA-1"}},
+						},
+						Vulnerabilities: []*osvschema.Vulnerability{
+							{Id: "GHSA-1", Aliases: []string{"CVE-1"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := mapIDsToGroupedSARIFFinding(&vulnResults)
+
+	if got["PYSEC-1"] != got["GHSA-1"] {
+		t.Fatalf("PYSEC-1 and GHSA-1 are aliases of CVE-1 but were not grouped together")
+	}
+	if len(got["PYSEC-1"].PkgSource) != 2 {
+		t.Errorf("expected the finding to be in 2 packages, got %d", len(got["PYSEC-1"].PkgSource))
+	}
+	if want := "CVE-1"; got["PYSEC-1"].DisplayID != want {
+		t.Errorf("expected the finding to be displayed as %q, got %q", want, got["PYSEC-1"].DisplayID)
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"maps"
+	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
@@ -162,6 +165,80 @@ func stripGitHubWorkspace(path string) string {
 	return strings.TrimPrefix(path, "/github/workspace/")
 }
 
+// sarifArtifactPath returns how a path is referred to in a SARIF report, as a
+// file URL if it is absolute.
+func sarifArtifactPath(path string) string {
+	artifactPath := stripGitHubWorkspace(path)
+	if filepath.IsAbs(artifactPath) {
+		// this only errors if the file path is not absolute,
+		// which we've already confirmed is not the case
+		p, err := url.FromFilePath(artifactPath)
+		if err == nil && p != nil {
+			artifactPath = p.String()
+		}
+	}
+
+	return artifactPath
+}
+
+// mapCallStacks maps each package and source to the call stacks of its
+// vulnerabilities, as found by call analysis.
+func mapCallStacks(vulnResults *models.VulnerabilityResults) map[pkgWithSource]map[string][]models.StackFrame {
+	stacks := map[pkgWithSource]map[string][]models.StackFrame{}
+	for _, res := range vulnResults.Results {
+		for _, pkg := range res.Packages {
+			for _, gi := range pkg.Groups {
+				for id, analysis := range gi.ExperimentalAnalysis {
+					if len(analysis.CallStack) == 0 {
+						continue
+					}
+					pws := pkgWithSource{Package: pkg.Package, Source: res.Source}
+					if stacks[pws] == nil {
+						stacks[pws] = map[string][]models.StackFrame{}
+					}
+					stacks[pws][id] = analysis.CallStack
+				}
+			}
+		}
+	}
+
+	return stacks
+}
+
+// sarifCallStack returns the call stack of the first of ids that has one, with
+// the vulnerable symbol as its innermost frame.
+func sarifCallStack(stacks map[string][]models.StackFrame, ids []string) *sarif.Stack {
+	for _, id := range ids {
+		callStack, ok := stacks[id]
+		if !ok {
+			continue
+		}
+
+		stack := sarif.NewStack().
+			WithMessage(sarif.NewTextMessage(fmt.Sprintf("The vulnerable function %s is called", callStack[0])))
+		for _, frame := range callStack {
+			location := sarif.NewLocation().
+				AddLogicalLocation(sarif.NewLogicalLocation().
+					WithFullyQualifiedName(frame.String()).
+					WithKind("function"))
+			if frame.Path != "" {
+				region := sarif.NewRegion().WithStartLine(frame.Line)
+				if frame.Column > 0 {
+					region.WithStartColumn(frame.Column)
+				}
+				location.WithPhysicalLocation(sarif.NewPhysicalLocation().
+					WithArtifactLocation(sarif.NewSimpleArtifactLocation(sarifArtifactPath(frame.Path))).
+					WithRegion(region))
+			}
+			stack.AddFrame(sarif.NewStackFrame().WithLocation(location).WithModule(frame.Module))
+		}
+
+		return stack
+	}
+
+	return nil
+}
+
 // createSARIFFingerprint generates a stable fingerprint for a SARIF result
 // to help GitHub deduplicate findings across scans.
 //
@@ -187,6 +264,101 @@ func createSARIFFingerprint(vulnID string, artifactPath string, pkg models.Packa
 	return hex.EncodeToString(hash[:])
 }
 
+// sarifFingerprintPath returns the path of a source to use in fingerprints.
+// Paths under the working directory are made relative to it, so that
+// fingerprints do not change with where the project was checked out.
+func sarifFingerprintPath(path string) string {
+	path = stripGitHubWorkspace(path)
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(path)
+	}
+	if rel, err := filepath.Rel(mustGetWorkingDirectory(), path); err == nil && filepath.IsLocal(rel) {
+		return filepath.ToSlash(rel)
+	}
+
+	return filepath.ToSlash(path)
+}
+
+// sarifManifests reads the manifests that results point at, caching their
+// lines. Manifests that cannot be read, such as those inside container
+// images, are cached as having no lines.
+type sarifManifests map[string][]string
+
+func (m sarifManifests) lines(path string) []string {
+	lines, ok := m[path]
+	if !ok {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			lines = strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+		}
+		m[path] = lines
+	}
+
+	return lines
+}
+
+// declaration returns the 1-based number and the text of the line of the
+// manifest at path that declares pkg, or 0 if it cannot be found.
+//
+// Manifests are not parsed again here, so the first line mentioning the name
+// of the package is taken to be its declaration. For Maven packages, whose
+// names are "groupId:artifactId", the artifactId is looked for as well.
+func (m sarifManifests) declaration(path string, pkg models.PackageInfo) (int, string) {
+	lines := m.lines(path)
+	if len(lines) == 0 || pkg.Name == "" {
+		return 0, ""
+	}
+
+	names := []string{pkg.Name}
+	if _, artifactID, ok := strings.Cut(pkg.Name, ":"); ok {
+		names = append(names, artifactID)
+	}
+	for _, name := range names {
+		re := regexp.MustCompile(`(?i)(^|[^\w.-])` + regexp.QuoteMeta(name) + `($|[^\w.-])`)
+		for i, line := range lines {
+			if re.MatchString(line) {
+				return i + 1, line
+			}
+		}
+	}
+
+	return 0, ""
+}
+
+// sarifFixedVersion returns the first version of pkg that fixes the group,
+// as FixedVersion works it out for the other output formats.
+func sarifFixedVersion(gv *groupedSARIFFinding, pkg models.PackageInfo) (string, bool) {
+	fixedVersion := FixedVersion(
+		models.GroupInfo{IDs: gv.AliasedIDList},
+		models.PackageVulns{Package: pkg, Vulnerabilities: slices.Collect(maps.Values(gv.AliasedVulns))},
+	)
+
+	return fixedVersion, fixedVersion != ""
+}
+
+// createSARIFFix proposes upgrading pkg to fixedVersion by replacing its
+// version on the line of the manifest declaring it. It returns nil if the
+// version is not written on that line, e.g. because the manifest only
+// declares a range of versions.
+func createSARIFFix(artifactPath string, line int, text string, pkg models.PackageInfo, fixedVersion string) *sarif.Fix {
+	column := strings.Index(text, pkg.Version)
+	if pkg.Version == "" || column < 0 {
+		return nil
+	}
+	region := sarif.NewRegion().
+		WithStartLine(line).
+		WithStartColumn(column + 1).
+		WithEndColumn(column + 1 + len(pkg.Version))
+
+	return sarif.NewFix().
+		WithDescription(sarif.NewTextMessage(fmt.Sprintf("Upgrade '%s' to %s", pkg.Name, fixedVersion))).
+		AddArtifactChange(sarif.NewArtifactChange().
+			WithArtifactLocation(sarif.NewSimpleArtifactLocation(artifactPath)).
+			AddReplacement(sarif.NewReplacement().
+				WithDeletedRegion(region).
+				WithInsertedContent(sarif.NewArtifactContent().WithText(fixedVersion))))
+}
+
 // createSARIFHelpText returns the text for SARIF rule's help field
 func createSARIFHelpText(gv *groupedSARIFFinding) string {
 	backtickSARIFTemplate := strings.ReplaceAll(strings.TrimSpace(SARIFTemplate), `""`, "`")
@@ -257,6 +429,8 @@ func PrintSARIFReport(vulnResult *models.VulnerabilityResults, outputWriter io.W
 	run.Tool.Driver.WithVersion(version.OSVVersion)
 
 	vulnIDMap := mapIDsToGroupedSARIFFinding(vulnResult)
+	callStacks := mapCallStacks(vulnResult)
+	manifests := sarifManifests{}
 	// Sort the IDs to have deterministic loop of vulnIDMap
 	vulnIDs := []string{}
 	for vulnID := range vulnIDMap {
@@ -264,11 +438,14 @@ func PrintSARIFReport(vulnResult *models.VulnerabilityResults, outputWriter io.W
 	}
 	slices.Sort(vulnIDs)
 
+	// Aliased IDs point to the same finding, which must only be reported once
+	reported := map[*groupedSARIFFinding]bool{}
 	for _, vulnID := range vulnIDs {
 		gv := vulnIDMap[vulnID]
-		if gv == nil {
+		if gv == nil || reported[gv] {
 			continue
 		}
+		reported[gv] = true
 
 		helpText := createSARIFHelpText(gv)
 
@@ -311,18 +488,23 @@ func PrintSARIFReport(vulnResult *models.VulnerabilityResults, outputWriter io.W
 		// Find the worst severity score
 		var worstScore float64 = -1
 		for _, v := range gv.AliasedVulns {
-			if v == nil || v.GetSeverity() == nil {
+			if v == nil {
 				continue
 			}
-			score, _, _ := severity.CalculateOverallScore(v.GetSeverity())
+			score, _ := severity.Normalize(v)
 			if score > worstScore {
 				worstScore = score
 			}
 		}
 
-		if worstScore >= 0 {
+		if worstScore >= 0 || gv.KEV != nil {
 			var bag = sarif.NewPropertyBag()
-			bag.Add("security-severity", strconv.FormatFloat(worstScore, 'f', -1, 64))
+			if worstScore >= 0 {
+				bag.Add("security-severity", strconv.FormatFloat(worstScore, 'f', -1, 64))
+			}
+			if gv.KEV != nil {
+				bag.AddTag("known-exploited")
+			}
 			rule.WithProperties(bag)
 		}
 
@@ -332,15 +514,7 @@ func PrintSARIFReport(vulnResult *models.VulnerabilityResults, outputWriter io.W
 		rule.DeprecatedIds = gv.AliasedIDList
 
 		for _, pws := range gv.PkgSource.StableKeys() {
-			artifactPath := stripGitHubWorkspace(pws.Source.Path)
-			if filepath.IsAbs(artifactPath) {
-				// this only errors if the file path is not absolute,
-				// which we've already confirmed is not the case
-				p, err := url.FromFilePath(artifactPath)
-				if err == nil && p != nil {
-					artifactPath = p.String()
-				}
-			}
+			artifactPath := sarifArtifactPath(pws.Source.Path)
 
 			run.AddDistinctArtifact(artifactPath)
 
@@ -350,23 +524,33 @@ func PrintSARIFReport(vulnResult *models.VulnerabilityResults, outputWriter io.W
 			}
 
 			// Generate a stable fingerprint for deduplication
-			fingerprint := createSARIFFingerprint(gv.DisplayID, artifactPath, pws.Package)
+			fingerprint := createSARIFFingerprint(gv.DisplayID, sarifFingerprintPath(pws.Source.Path), pws.Package)
+
+			physicalLocation := sarif.NewPhysicalLocation().
+				WithArtifactLocation(sarif.NewSimpleArtifactLocation(artifactPath))
+			line, text := manifests.declaration(pws.Source.Path, pws.Package)
+			if line > 0 {
+				physicalLocation.WithRegion(sarif.NewRegion().WithStartLine(line))
+			}
+
+			level, knownExploitedStr := "warning", ""
+			if gv.KEV != nil {
+				level = "error"
+				knownExploitedStr = fmt.Sprintf(" It is listed in the CISA Known Exploited Vulnerabilities catalog as %s.", gv.KEV.CVE)
+			}
 
-			run.CreateResultForRule(gv.DisplayID).
-				WithLevel("warning").
+			result := run.CreateResultForRule(gv.DisplayID).
+				WithLevel(level).
 				WithMessage(
 					sarif.NewTextMessage(
 						fmt.Sprintf(
-							"Package '%s' is vulnerable to '%s'%s.",
+							"Package '%s' is vulnerable to '%s'%s.%s",
 							results.PkgToString(pws.Package),
 							gv.DisplayID,
 							alsoKnownAsStr,
+							knownExploitedStr,
 						))).
-				AddLocation(
-					sarif.NewLocationWithPhysicalLocation(
-						sarif.NewPhysicalLocation().
-							WithArtifactLocation(sarif.NewSimpleArtifactLocation(artifactPath)),
-					)).
+				AddLocation(sarif.NewLocationWithPhysicalLocation(physicalLocation)).
 				WithPartialFingerprints(map[string]string{
 					// Use "primaryLocationLineHash" as the key for the fingerprint.
 					// This is the standard key that GitHub Advanced Security uses to deduplicate
@@ -383,6 +567,18 @@ func PrintSARIFReport(vulnResult *models.VulnerabilityResults, outputWriter io.W
 					// combination of vulnerability ID, package, and location rather than source code lines.
 					"primaryLocationLineHash": fingerprint,
 				})
+
+			if stack := sarifCallStack(callStacks[pws], gv.AliasedIDList); stack != nil {
+				result.AddStack(stack)
+			}
+
+			if line > 0 {
+				if fixedVersion, ok := sarifFixedVersion(gv, pws.Package); ok {
+					if fix := createSARIFFix(artifactPath, line, text, pws.Package, fixedVersion); fix != nil {
+						result.AddFixe(fix)
+					}
+				}
+			}
 		}
 	}
 
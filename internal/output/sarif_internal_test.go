@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/pkg/models"
 )
 
 func Test_createSARIFHelpText(t *testing.T) {
@@ -35,3 +36,119 @@ func Test_createSARIFHelpText(t *testing.T) {
 		})
 	}
 }
+
+func Test_sarifManifests_declaration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		path     string
+		pkg      models.PackageInfo
+		wantLine int
+	}{
+		{
+			name:     "go module",
+			path:     "testdata/sarif/go.mod",
+			pkg:      models.PackageInfo{Name: "github.com/gogo/protobuf", Version: "1.3.1", Ecosystem: "Go"},
+			wantLine: 6,
+		},
+		{
+			name:     "name is not matched as part of a longer name",
+			path:     "testdata/sarif/go.mod",
+			pkg:      models.PackageInfo{Name: "github.com/gogo/protobuf-extras", Version: "0.1.0", Ecosystem: "Go"},
+			wantLine: 7,
+		},
+		{
+			name:     "maven artifact id",
+			path:     "testdata/sarif/pom.xml",
+			pkg:      models.PackageInfo{Name: "org.apache.commons:commons-text", Version: "1.9", Ecosystem: "Maven"},
+			wantLine: 7,
+		},
+		{
+			name:     "package not in manifest",
+			path:     "testdata/sarif/go.mod",
+			pkg:      models.PackageInfo{Name: "golang.org/x/net", Version: "0.1.0", Ecosystem: "Go"},
+			wantLine: 0,
+		},
+		{
+			name:     "manifest does not exist",
+			path:     "testdata/sarif/does-not-exist",
+			pkg:      models.PackageInfo{Name: "github.com/gogo/protobuf", Version: "1.3.1", Ecosystem: "Go"},
+			wantLine: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotLine, _ := sarifManifests{}.declaration(tt.path, tt.pkg)
+			if gotLine != tt.wantLine {
+				t.Errorf("declaration() line = %d, want %d", gotLine, tt.wantLine)
+			}
+		})
+	}
+}
+
+func Test_createSARIFFix(t *testing.T) {
+	t.Parallel()
+
+	pkg := models.PackageInfo{Name: "github.com/gogo/protobuf", Version: "1.3.1", Ecosystem: "Go"}
+
+	fix := createSARIFFix("go.mod", 6, "\tgithub.com/gogo/protobuf v1.3.1", pkg, "1.3.2")
+	if fix == nil {
+		t.Fatalf("createSARIFFix() = nil, want a fix")
+	}
+	region := fix.ArtifactChanges[0].Replacements[0].DeletedRegion
+	if *region.StartLine != 6 || *region.StartColumn != 28 || *region.EndColumn != 33 {
+		t.Errorf("createSARIFFix() region = %d:%d-%d, want 6:28-33", *region.StartLine, *region.StartColumn, *region.EndColumn)
+	}
+	if got := *fix.ArtifactChanges[0].Replacements[0].InsertedContent.Text; got != "1.3.2" {
+		t.Errorf("createSARIFFix() inserted %q, want %q", got, "1.3.2")
+	}
+
+	if fix := createSARIFFix("package.json", 5, `"lodash": "^4.17.0",`, models.PackageInfo{Name: "lodash", Version: "4.17.15"}, "4.17.21"); fix != nil {
+		t.Errorf("createSARIFFix() = %v, want nil when the version is not on the line", fix)
+	}
+}
+
+func Test_sarifCallStack(t *testing.T) {
+	t.Parallel()
+
+	stacks := map[string][]models.StackFrame{
+		"GO-2023-1558": {
+			{Module: "github.com/ipfs/go-bitfield", Package: "github.com/ipfs/go-bitfield", Function: "NewBitfield", Path: "/go/pkg/mod/github.com/ipfs/go-bitfield@v1.0.0/bitfield.go", Line: 12, Column: 6},
+			{Module: "example.com/app", Package: "example.com/app", Function: "(*Server).Start", Path: "main.go", Line: 16},
+		},
+	}
+
+	if stack := sarifCallStack(stacks, []string{"CVE-2023-1", "GHSA-1"}); stack != nil {
+		t.Errorf("sarifCallStack() = %v, want nil when no ID has a call stack", stack)
+	}
+
+	stack := sarifCallStack(stacks, []string{"CVE-2023-1", "GO-2023-1558"})
+	if stack == nil {
+		t.Fatalf("sarifCallStack() = nil, want a stack")
+	}
+	if len(stack.Frames) != 2 {
+		t.Fatalf("sarifCallStack() has %d frames, want 2", len(stack.Frames))
+	}
+
+	inner := stack.Frames[0].Location
+	if got := *inner.LogicalLocations[0].FullyQualifiedName; got != "github.com/ipfs/go-bitfield.NewBitfield" {
+		t.Errorf("sarifCallStack() innermost frame = %q, want %q", got, "github.com/ipfs/go-bitfield.NewBitfield")
+	}
+	if got := *inner.PhysicalLocation.Region.StartColumn; got != 6 {
+		t.Errorf("sarifCallStack() innermost frame column = %d, want 6", got)
+	}
+
+	outer := stack.Frames[1].Location
+	if got := *outer.LogicalLocations[0].FullyQualifiedName; got != "example.com/app.(*Server).Start" {
+		t.Errorf("sarifCallStack() outermost frame = %q, want %q", got, "example.com/app.(*Server).Start")
+	}
+	if got := *outer.PhysicalLocation.Region.StartLine; got != 16 {
+		t.Errorf("sarifCallStack() outermost frame line = %d, want 16", got)
+	}
+	if outer.PhysicalLocation.Region.StartColumn != nil {
+		t.Errorf("sarifCallStack() outermost frame column = %d, want none", *outer.PhysicalLocation.Region.StartColumn)
+	}
+}
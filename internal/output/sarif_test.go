@@ -113,6 +113,17 @@ func TestPrintSARIFReport_WithMixedIssues(t *testing.T) {
 	})
 }
 
+func TestPrintSARIFReport_FixesAndRegions(t *testing.T) {
+	t.Parallel()
+
+	res := testutility.LoadJSONFixture[models.VulnerabilityResults](t, "testdata/test-vuln-results-a.json")
+	// Point the Go results at a go.mod that declares the vulnerable package
+	res.Results[0].Source.Path = "testdata/sarif/go.mod"
+	res.Results = res.Results[:1]
+
+	testutility.NewSnapshot().MatchJSON(t, buildJSONSarifReport(t, &res))
+}
+
 func buildJSONSarifReport(t *testing.T, res *models.VulnerabilityResults) map[string]any {
 	t.Helper()
 
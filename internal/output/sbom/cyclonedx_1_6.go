@@ -1,7 +1,11 @@
 package sbom
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/osv-scanner/v2/internal/utility/purl"
 	"github.com/google/osv-scanner/v2/pkg/models"
 )
 
@@ -9,6 +13,56 @@ func ToCycloneDX16Bom(uniquePackages map[string]models.PackageVulns) *cyclonedx.
 	bom := buildCycloneDXBom(uniquePackages)
 	bom.JSONSchema = cycloneDx16Schema
 	bom.SpecVersion = cyclonedx.SpecVersion1_6
+	bom.Dependencies = buildDependencies(uniquePackages)
 
 	return bom
 }
+
+// buildDependencies builds the dependency graph of the components from the
+// parents found by transitive dependency resolution. It returns nil if no
+// component has a known parent, as the graph would then claim that none of
+// them have dependencies.
+func buildDependencies(uniquePackages map[string]models.PackageVulns) *[]cyclonedx.Dependency {
+	dependsOn := make(map[string][]string, len(uniquePackages))
+	known := false
+	for packageURL, packageDetail := range uniquePackages {
+		if _, ok := dependsOn[packageURL]; !ok {
+			dependsOn[packageURL] = []string{}
+		}
+
+		for _, parent := range packageDetail.Parents {
+			parentURL, err := purl.FromPackage(models.PackageInfo{
+				Name:      parent.Name,
+				Version:   parent.Version,
+				Ecosystem: packageDetail.Package.Ecosystem,
+			})
+			if err != nil {
+				continue
+			}
+			ref := parentURL.ToString()
+			if _, ok := uniquePackages[ref]; !ok {
+				continue
+			}
+			dependsOn[ref] = append(dependsOn[ref], packageURL)
+			known = true
+		}
+	}
+	if !known {
+		return nil
+	}
+
+	dependencies := make([]cyclonedx.Dependency, 0, len(dependsOn))
+	for ref, refs := range dependsOn {
+		slices.Sort(refs)
+		refs = slices.Compact(refs)
+		dependencies = append(dependencies, cyclonedx.Dependency{
+			Ref:          ref,
+			Dependencies: &refs,
+		})
+	}
+	slices.SortFunc(dependencies, func(a, b cyclonedx.Dependency) int {
+		return strings.Compare(a.Ref, b.Ref)
+	})
+
+	return &dependencies
+}
@@ -98,6 +98,12 @@ func addDeprecatedProperty(component *cyclonedx.Component, packageDetail models.
 		Name:  "deprecated",
 		Value: "true",
 	})
+	if packageDetail.Package.DeprecationReason != "" {
+		properties = append(properties, cyclonedx.Property{
+			Name:  "deprecation_reason",
+			Value: packageDetail.Package.DeprecationReason,
+		})
+	}
 
 	component.Properties = &properties
 }
@@ -4,26 +4,124 @@ import (
 	"encoding/json"
 	"io"
 
+	"github.com/spdx/tools-golang/spdx/v2/common"
+	"github.com/spdx/tools-golang/spdx/v2/v2_3"
+
 	scalibr "github.com/google/osv-scalibr"
 	"github.com/google/osv-scalibr/converter/spdx"
+	"github.com/google/osv-scalibr/extractor"
 	"github.com/google/osv-scanner/v2/pkg/models"
 )
 
-// PrintSPDXResults writes results to the provided writer in SPDX format
+// PrintSPDXResults writes results to the provided writer in SPDX 2.3 format
 func PrintSPDXResults(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	return encodeSPDX(toSPDX23(vulnResult), outputWriter)
+}
+
+// PrintSPDX30Results writes results to the provided writer in the SPDX 3.0
+// JSON-LD format
+func PrintSPDX30Results(vulnResult *models.VulnerabilityResults, outputWriter io.Writer) error {
+	return encodeSPDX(toSPDX30(toSPDX23(vulnResult)), outputWriter)
+}
+
+func encodeSPDX(doc any, outputWriter io.Writer) error {
+	encoder := json.NewEncoder(outputWriter)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(doc)
+}
+
+func toSPDX23(vulnResult *models.VulnerabilityResults) *v2_3.Document {
 	scanResult := &scalibr.ScanResult{}
 
 	for _, source := range vulnResult.Results {
 		for _, pkg := range source.Packages {
-			scanResult.Inventory.Packages = append(scanResult.Inventory.Packages, pkg.Package.Inventory)
+			scanResult.Inventory.Packages = append(scanResult.Inventory.Packages, spdxPackage(pkg))
 		}
 	}
 
 	// TODO(#1783): Allow user configuration
 	doc := spdx.ToSPDX23(scanResult.Inventory, spdx.Config{})
+	doc.Relationships = append(doc.Relationships, spdxDependsOn(doc, vulnResult)...)
 
-	encoder := json.NewEncoder(outputWriter)
-	encoder.SetIndent("", "  ")
+	return doc
+}
 
-	return encoder.Encode(doc)
+// spdxPackage returns the package to describe in the document, with the
+// licenses that were looked up for it in place of those found while scanning,
+// unless none of them are known.
+func spdxPackage(pkg models.PackageVulns) *extractor.Package {
+	var licenses []string
+	for _, license := range pkg.Licenses {
+		if license != "UNKNOWN" {
+			licenses = append(licenses, string(license))
+		}
+	}
+	if len(licenses) == 0 || pkg.Package.Inventory == nil {
+		return pkg.Package.Inventory
+	}
+
+	inv := *pkg.Package.Inventory
+	inv.Licenses = licenses
+
+	return &inv
+}
+
+// spdxPackageKey identifies a package across the sources of the results.
+type spdxPackageKey struct {
+	ecosystem, name, version string
+}
+
+// spdxDependsOn returns a DEPENDS_ON relationship for each parent found by
+// transitive dependency resolution, between packages that are both in doc.
+func spdxDependsOn(doc *v2_3.Document, vulnResult *models.VulnerabilityResults) []*v2_3.Relationship {
+	idsByPURL := make(map[string]common.ElementID, len(doc.Packages))
+	for _, p := range doc.Packages {
+		for _, ref := range p.PackageExternalReferences {
+			if ref.RefType == "purl" {
+				idsByPURL[ref.Locator] = p.PackageSPDXIdentifier
+			}
+		}
+	}
+
+	ids := make(map[spdxPackageKey]common.ElementID)
+	for _, source := range vulnResult.Results {
+		for _, pkg := range source.Packages {
+			if pkg.Package.Inventory == nil {
+				continue
+			}
+			p := pkg.Package.Inventory.PURL()
+			if p == nil {
+				continue
+			}
+			if id, ok := idsByPURL[p.String()]; ok {
+				ids[spdxPackageKey{pkg.Package.Ecosystem, pkg.Package.Name, pkg.Package.Version}] = id
+			}
+		}
+	}
+
+	var relationships []*v2_3.Relationship
+	seen := make(map[[2]common.ElementID]bool)
+	for _, source := range vulnResult.Results {
+		for _, pkg := range source.Packages {
+			child, ok := ids[spdxPackageKey{pkg.Package.Ecosystem, pkg.Package.Name, pkg.Package.Version}]
+			if !ok {
+				continue
+			}
+			for _, parent := range pkg.Parents {
+				id, ok := ids[spdxPackageKey{pkg.Package.Ecosystem, parent.Name, parent.Version}]
+				if !ok || seen[[2]common.ElementID{id, child}] {
+					continue
+				}
+				seen[[2]common.ElementID{id, child}] = true
+				relationships = append(relationships, &v2_3.Relationship{
+					RefA:         common.DocElementID{ElementRefID: id},
+					RefB:         common.DocElementID{ElementRefID: child},
+					Relationship: common.TypeRelationshipDependsOn,
+				})
+			}
+		}
+	}
+
+	return relationships
 }
@@ -0,0 +1,163 @@
+package output
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/spdx/tools-golang/spdx/v2/common"
+	"github.com/spdx/tools-golang/spdx/v2/v2_3"
+)
+
+const (
+	spdx30Context      = "https://spdx.org/rdf/3.0.1/spdx-context.jsonld"
+	spdx30CreationInfo = "_:creationinfo"
+)
+
+// spdx30Document is an SPDX 3.0 document serialized as JSON-LD, which is a
+// flat graph of elements referring to each other by their spdxId.
+type spdx30Document struct {
+	Context string          `json:"@context"`
+	Graph   []spdx30Element `json:"@graph"`
+}
+
+// spdx30Element holds the properties of every kind of element used here, only
+// some of which are set depending on its type.
+type spdx30Element struct {
+	Type         string `json:"type"`
+	ID           string `json:"@id,omitempty"`
+	SpdxID       string `json:"spdxId,omitempty"`
+	CreationInfo string `json:"creationInfo,omitempty"`
+	Name         string `json:"name,omitempty"`
+
+	// CreationInfo
+	SpecVersion string   `json:"specVersion,omitempty"`
+	Created     string   `json:"created,omitempty"`
+	CreatedBy   []string `json:"createdBy,omitempty"`
+
+	// SpdxDocument
+	DataLicense        string   `json:"dataLicense,omitempty"`
+	ProfileConformance []string `json:"profileConformance,omitempty"`
+	RootElement        []string `json:"rootElement,omitempty"`
+	Element            []string `json:"element,omitempty"`
+
+	// software_Package
+	PackageVersion string `json:"software_packageVersion,omitempty"`
+	PackageURL     string `json:"software_packageUrl,omitempty"`
+	SourceInfo     string `json:"software_sourceInfo,omitempty"`
+
+	// Relationship
+	From             string   `json:"from,omitempty"`
+	To               []string `json:"to,omitempty"`
+	RelationshipType string   `json:"relationshipType,omitempty"`
+}
+
+// toSPDX30 converts an SPDX 2.3 document into SPDX 3.0. The packages keep
+// their identifiers, which are made into IRIs within the document namespace.
+//
+// The 2.3 relationships stating that the contents of a package are unknown
+// are dropped, as that is the default in SPDX 3.0, and the package the
+// document describes becomes its root element.
+func toSPDX30(doc *v2_3.Document) spdx30Document {
+	// Identifiers are written with the "SPDXRef-" prefix in SPDX 2.3 JSON, but
+	// not every one of them is stored with it
+	iri := func(id common.ElementID) string {
+		return doc.DocumentNamespace + "#SPDXRef-" + strings.TrimPrefix(string(id), "SPDXRef-")
+	}
+
+	agents := make([]spdx30Element, 0, len(doc.CreationInfo.Creators))
+	for _, creator := range doc.CreationInfo.Creators {
+		agentType := "SoftwareAgent"
+		switch creator.CreatorType {
+		case "Person":
+			agentType = "Person"
+		case "Organization":
+			agentType = "Organization"
+		}
+		agents = append(agents, spdx30Element{
+			Type:         agentType,
+			SpdxID:       doc.DocumentNamespace + "#SPDXRef-Agent-" + strconv.Itoa(len(agents)),
+			CreationInfo: spdx30CreationInfo,
+			Name:         creator.Creator,
+		})
+	}
+
+	creationInfo := spdx30Element{
+		Type:        "CreationInfo",
+		ID:          spdx30CreationInfo,
+		SpecVersion: "3.0.1",
+		Created:     doc.CreationInfo.Created,
+	}
+	for _, agent := range agents {
+		creationInfo.CreatedBy = append(creationInfo.CreatedBy, agent.SpdxID)
+	}
+
+	graph := append([]spdx30Element{creationInfo}, agents...)
+	document := spdx30Element{
+		Type:               "SpdxDocument",
+		SpdxID:             iri(doc.SPDXIdentifier),
+		CreationInfo:       spdx30CreationInfo,
+		Name:               doc.DocumentName,
+		DataLicense:        "https://spdx.org/licenses/" + doc.DataLicense,
+		ProfileConformance: []string{"core", "software"},
+	}
+	var elements []spdx30Element
+	relationships := 0
+
+	for _, pkg := range doc.Packages {
+		element := spdx30Element{
+			Type:           "software_Package",
+			SpdxID:         iri(pkg.PackageSPDXIdentifier),
+			CreationInfo:   spdx30CreationInfo,
+			Name:           pkg.PackageName,
+			PackageVersion: pkg.PackageVersion,
+			SourceInfo:     pkg.PackageSourceInfo,
+		}
+		for _, ref := range pkg.PackageExternalReferences {
+			if ref.RefType == "purl" {
+				element.PackageURL = ref.Locator
+			}
+		}
+		elements = append(elements, element)
+	}
+
+	for _, rel := range doc.Relationships {
+		if rel.RefB.SpecialID != "" {
+			continue
+		}
+		if rel.Relationship == common.TypeRelationshipDescribe && iri(rel.RefA.ElementRefID) == document.SpdxID {
+			document.RootElement = append(document.RootElement, iri(rel.RefB.ElementRefID))
+			continue
+		}
+		relationships++
+		elements = append(elements, spdx30Element{
+			Type:             "Relationship",
+			SpdxID:           doc.DocumentNamespace + "#SPDXRef-Relationship-" + strconv.Itoa(relationships),
+			CreationInfo:     spdx30CreationInfo,
+			From:             iri(rel.RefA.ElementRefID),
+			To:               []string{iri(rel.RefB.ElementRefID)},
+			RelationshipType: spdx30RelationshipType(rel.Relationship),
+		})
+	}
+
+	for _, element := range elements {
+		document.Element = append(document.Element, element.SpdxID)
+	}
+
+	return spdx30Document{
+		Context: spdx30Context,
+		Graph:   append(append(graph, document), elements...),
+	}
+}
+
+// spdx30RelationshipType converts an SPDX 2.3 relationship type such as
+// "DEPENDS_ON" into the SPDX 3.0 equivalent, "dependsOn".
+func spdx30RelationshipType(relationship string) string {
+	words := strings.Split(strings.ToLower(relationship), "_")
+	for i := 1; i < len(words); i++ {
+		if words[i] != "" {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+
+	return strings.Join(words, "")
+}
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/purl"
 	"github.com/google/osv-scanner/v2/internal/cachedregexp"
 	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/pkg/models"
 	"github.com/jedib0t/go-pretty/v6/text"
 )
 
@@ -70,3 +73,75 @@ func TestPrintSPDXResults_WithMixedIssues(t *testing.T) {
 		testutility.NewSnapshot().MatchText(t, normalizeSPDXOutput(t, outputWriter.String()))
 	})
 }
+
+func spdxDependenciesResult() *models.VulnerabilityResults {
+	pkg := func(name, version string, parents ...models.PackageParent) models.PackageVulns {
+		return models.PackageVulns{
+			Package: models.PackageInfo{
+				Name:      name,
+				Version:   version,
+				Ecosystem: "PyPI",
+				Inventory: &extractor.Package{
+					Name:      name,
+					Version:   version,
+					Locations: []string{"/path/to/requirements.txt"},
+					PURLType:  purl.TypePyPi,
+				},
+			},
+			Parents: parents,
+		}
+	}
+
+	return &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/requirements.txt", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					pkg("flask", "3.0.0"),
+					pkg("werkzeug", "3.0.1", models.PackageParent{Name: "flask", Version: "3.0.0", Requirement: ">=3.0.0"}),
+					pkg("markupsafe", "2.1.3",
+						models.PackageParent{Name: "werkzeug", Version: "3.0.1", Requirement: ">=2.1.1"},
+						models.PackageParent{Name: "jinja2", Version: "3.1.2", Requirement: ">=2.0"},
+					),
+				},
+			},
+		},
+	}
+}
+
+func TestPrintSPDXResults_Dependencies(t *testing.T) {
+	t.Parallel()
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintSPDXResults(spdxDependenciesResult(), outputWriter); err != nil {
+		t.Errorf("%v", err)
+	}
+	testutility.NewSnapshot().MatchText(t, normalizeSPDXOutput(t, outputWriter.String()))
+}
+
+func TestPrintSPDX30Results_Dependencies(t *testing.T) {
+	t.Parallel()
+
+	outputWriter := &bytes.Buffer{}
+	if err := output.PrintSPDX30Results(spdxDependenciesResult(), outputWriter); err != nil {
+		t.Errorf("%v", err)
+	}
+	testutility.NewSnapshot().MatchText(t, normalizeSPDXOutput(t, outputWriter.String()))
+}
+
+func TestPrintSPDX30Results_WithVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	testOutputWithVulnerabilities(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		outputWriter := &bytes.Buffer{}
+		err := output.PrintSPDX30Results(args.vulnResult, outputWriter)
+
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+
+		testutility.NewSnapshot().MatchText(t, normalizeSPDXOutput(t, outputWriter.String()))
+	})
+}
@@ -4,14 +4,15 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/google/osv-scalibr/inventory/osvecosystem"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
 	depgroups "github.com/google/osv-scanner/v2/internal/utility/depgroup"
 	"github.com/google/osv-scanner/v2/internal/utility/results"
 	"github.com/google/osv-scanner/v2/internal/utility/severity"
 	"github.com/google/osv-scanner/v2/pkg/models"
-	"github.com/ossf/osv-schema/bindings/go/osvschema"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
@@ -29,6 +30,9 @@ func PrintTableResults(vulnResult *models.VulnerabilityResults, outputWriter io.
 
 	outputResult := BuildResults(vulnResult)
 
+	// Call out malicious packages before anything else.
+	printMaliciousPackages(vulnResult, outputWriter)
+
 	// Render the vulnerabilities.
 	if containsOSResult(outputResult) {
 		printSummaryResult(outputResult, outputWriter, terminalWidth, showAllVulns)
@@ -48,7 +52,7 @@ func PrintTableResults(vulnResult *models.VulnerabilityResults, outputWriter io.
 		if licenseConfig.Summary {
 			buildLicenseSummaryTable(outputWriter, terminalWidth, vulnResult)
 		}
-		if len(licenseConfig.Allowlist) > 0 {
+		if licenseConfig.HasPolicy() {
 			buildLicenseViolationsTable(outputWriter, terminalWidth, vulnResult)
 		}
 
@@ -57,6 +61,81 @@ func PrintTableResults(vulnResult *models.VulnerabilityResults, outputWriter io.
 			printPkgDeprecatedSummary(outputResult, outputWriter)
 			buildDeprecatedPackagesTable(outputWriter, terminalWidth, vulnResult)
 		}
+
+		// Render the OpenSSF Scorecards if any were looked up.
+		buildScorecardTable(outputWriter, terminalWidth, vulnResult)
+
+		// Render outdated packages if any.
+		buildOutdatedPackagesTable(outputWriter, terminalWidth, vulnResult)
+
+		// Render possible typosquats if any.
+		buildTyposquatsTable(outputWriter, terminalWidth, vulnResult)
+
+		// Render packages without verified provenance if any.
+		buildUnverifiedProvenanceTable(outputWriter, terminalWidth, vulnResult)
+
+		// Render packages pinned to hashes that PyPI does not publish if any.
+		buildHashMismatchesTable(outputWriter, terminalWidth, vulnResult)
+
+		// Render vulnerabilities that VEX documents say do not apply if any.
+		buildNotAffectedTable(outputWriter, terminalWidth, vulnResult)
+
+		// Render dependencies that have drifted from their lockfiles if any.
+		buildDriftTable(outputWriter, terminalWidth, vulnResult)
+
+		// Render the upgrades that fix the vulnerabilities if they were planned.
+		buildUpgradePlanTable(outputWriter, terminalWidth, vulnResult)
+	}
+
+	printDegradations(vulnResult.ExperimentalDegradations, outputWriter)
+	printUnresolvedPackages(vulnResult.ExperimentalUnresolved, outputWriter)
+}
+
+// printDegradations lists, per source or enricher, the reasons its results
+// may be incomplete.
+func printDegradations(degradations []models.Degradation, outputWriter io.Writer) {
+	if len(degradations) == 0 {
+		return
+	}
+
+	fmt.Fprintln(outputWriter)
+	fmt.Fprintln(outputWriter, "Warning: results may be incomplete for the following sources:")
+
+	lastSource := ""
+	for _, d := range degradations {
+		source := d.Source
+		if d.Enricher != "" {
+			source = "enrichment by " + d.Enricher
+		}
+		if source != lastSource {
+			fmt.Fprintf(outputWriter, "  %s:\n", source)
+			lastSource = source
+		}
+		fmt.Fprintf(outputWriter, "    - %s\n", d.Reason)
+	}
+}
+
+// printUnresolvedPackages lists, per source, the packages whose dependencies
+// could not be resolved and why.
+func printUnresolvedPackages(unresolved []models.UnresolvedPackage, outputWriter io.Writer) {
+	if len(unresolved) == 0 {
+		return
+	}
+
+	fmt.Fprintln(outputWriter)
+	fmt.Fprintln(outputWriter, "Warning: the dependencies of the following packages could not be resolved and were not scanned:")
+
+	lastSource := ""
+	for _, u := range unresolved {
+		if u.Source != lastSource {
+			fmt.Fprintf(outputWriter, "  %s:\n", u.Source)
+			lastSource = u.Source
+		}
+		name := u.Name
+		if u.Version != "" {
+			name += "@" + u.Version
+		}
+		fmt.Fprintf(outputWriter, "    - %s: %s\n", name, u.Reason)
 	}
 }
 
@@ -121,6 +200,9 @@ func printSummaryResult(result Result, outputWriter io.Writer, terminalWidth int
 	if result.PkgDeprecatedCount > 0 {
 		printPkgDeprecatedSummary(result, outputWriter)
 	}
+	if result.ImageInfo.BaseImageUpgrade != nil {
+		printBaseImageUpgradeSummary(*result.ImageInfo.BaseImageUpgrade, outputWriter)
+	}
 	// Add a newline
 	fmt.Fprintln(outputWriter)
 
@@ -188,6 +270,9 @@ func printSummaryResult(result Result, outputWriter io.Writer, terminalWidth int
 
 				if result.IsContainerScanning {
 					layer := fmt.Sprintf("# %d Layer", pkg.LayerDetail.LayerIndex)
+					if command := formatLayerCommand(pkg.LayerDetail.LayerInfo.LayerMetadata.Command)[0]; command != "" {
+						layer += ": " + truncate(command, 40)
+					}
 
 					inBaseImage := "--"
 					if pkg.LayerDetail.BaseImageInfo.Index != 0 {
@@ -304,14 +389,23 @@ func tableBuilderInner(result Result, vulnAnalysisType VulnAnalysisType) []tbInn
 						}
 					}
 
+					if vuln.KEV != nil {
+						links = append(links, text.FgRed.Sprint(KEVLabel))
+					}
+					if vuln.Malicious {
+						links = append(links, text.Colors{text.Bold, text.FgRed}.Sprint(MaliciousLabel))
+					}
 					outputRow = append(outputRow, strings.Join(links, "\n"))
 
 					// todo: this is just to make the snapshots pass without change
-					if vuln.SeverityScore == "N/A" {
-						outputRow = append(outputRow, "")
-					} else {
-						outputRow = append(outputRow, vuln.SeverityScore)
+					score := vuln.SeverityScore
+					if score == "N/A" {
+						score = ""
 					}
+					if vuln.EPSS != nil {
+						score = strings.TrimPrefix(score+"\n"+FormatEPSS(*vuln.EPSS), "\n")
+					}
+					outputRow = append(outputRow, score)
 
 					if eco.Name == "" && pkg.Commit != "" {
 						pkgCommitStr := results.PkgToString(models.PackageInfo{
@@ -330,6 +424,15 @@ func tableBuilderInner(result Result, vulnAnalysisType VulnAnalysisType) []tbInn
 						if depgroups.IsDevGroup(osvecosystem.MustParse(eco.Name).Ecosystem, pkg.DepGroups) {
 							name += " (dev)"
 						}
+						if slices.Contains(pkg.DepGroups, depsdev.BundledGroup) {
+							name += " (bundled)"
+						}
+						for _, chain := range pkg.IntroducedBy {
+							name += "\nvia " + strings.Join(chain, " > ")
+						}
+						if pkg.Dependents != nil {
+							name += "\n" + FormatDependents(*pkg.Dependents)
+						}
 						outputRow = append(outputRow, name)
 						outputRow = append(outputRow, pkg.InstalledVersion)
 					}
@@ -361,24 +464,97 @@ func tableBuilderInner(result Result, vulnAnalysisType VulnAnalysisType) []tbInn
 	return allOutputRows
 }
 
+// KEVLabel marks vulnerabilities that are listed in the CISA Known Exploited
+// Vulnerabilities catalog.
+const KEVLabel = "KNOWN EXPLOITED (CISA KEV)"
+
+// MaliciousLabel marks malicious packages, which are flagged by OSV MAL-
+// advisories.
+const MaliciousLabel = "MALICIOUS PACKAGE"
+
+// printMaliciousPackages warns about every malicious package that was found,
+// as these need to be removed rather than upgraded.
+func printMaliciousPackages(vulnResult *models.VulnerabilityResults, out io.Writer) {
+	var found []string
+	workingDir := mustGetWorkingDirectory()
+	for _, pkgSource := range vulnResult.Results {
+		path := pkgSource.Source.Path
+		if simplifiedPath, err := filepath.Rel(workingDir, pkgSource.Source.Path); err == nil {
+			path = simplifiedPath
+		}
+		for _, pkg := range pkgSource.Packages {
+			var ids []string
+			for _, group := range pkg.Groups {
+				if group.Malicious {
+					ids = append(ids, group.IDs...)
+				}
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			found = append(found, fmt.Sprintf(
+				"  %s %s (%s) in %s",
+				pkg.Package.Ecosystem,
+				results.PkgToString(pkg.Package),
+				strings.Join(ids, ", "),
+				path,
+			))
+		}
+	}
+	if len(found) == 0 {
+		return
+	}
+
+	loud := text.Colors{text.Bold, text.FgRed}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, loud.Sprintf(
+		"%s: %d %s known to be malicious and should be removed immediately:",
+		MaliciousLabel,
+		len(found),
+		Form(len(found), "package is", "packages are"),
+	))
+	for _, line := range found {
+		fmt.Fprintln(out, loud.Sprint(line))
+	}
+	fmt.Fprintln(out)
+}
+
+// FormatEPSS describes an EPSS score as the probability of exploitation as a
+// percentage, e.g. "EPSS 94.42%".
+func FormatEPSS(score models.EPSSScore) string {
+	return fmt.Sprintf("EPSS %.2f%%", score.Probability*100)
+}
+
+// FormatDependents describes how many packages depend on a package version,
+// e.g. "used by 1200 packages".
+func FormatDependents(dependents models.Dependents) string {
+	return fmt.Sprintf("used by %d %s", dependents.Count, Form(dependents.Count, "package", "packages"))
+}
+
+// MaxSeverity is the highest severity score of the vulnerabilities in a group,
+// or their highest rating if none of them have a score.
 func MaxSeverity(group models.GroupInfo, pkg models.PackageVulns) string {
 	var maxSeverity float64 = -1
-	for _, vulnID := range group.IDs {
-		var severities []*osvschema.Severity
-		for _, vuln := range pkg.Vulnerabilities {
-			if vuln.GetId() == vulnID {
-				severities = vuln.GetSeverity()
-			}
+	maxRating := severity.UnknownRating
+	for _, vuln := range pkg.Vulnerabilities {
+		if !slices.Contains(group.IDs, vuln.GetId()) {
+			continue
 		}
-		score, _, _ := severity.CalculateOverallScore(severities)
+		score, rating := severity.Normalize(vuln)
 		maxSeverity = max(maxSeverity, score)
+		if severity.Compare(rating, maxRating) > 0 {
+			maxRating = rating
+		}
 	}
 
-	if maxSeverity < 0 {
-		return ""
+	if maxSeverity >= 0 {
+		return fmt.Sprintf("%.1f", maxSeverity)
+	}
+	if maxRating != severity.UnknownRating {
+		return string(maxRating)
 	}
 
-	return fmt.Sprintf("%.1f", maxSeverity)
+	return ""
 }
 
 func buildLicenseSummaryTable(outputWriter io.Writer, terminalWidth int, vulnResult *models.VulnerabilityResults) {
@@ -450,7 +626,23 @@ func buildDeprecatedPackagesTable(outputWriter io.Writer, terminalWidth int, vul
 
 func deprecatedPackagesTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
 	outputTable.SetTitle("Deprecated packages")
-	outputTable.AppendHeader(table.Row{"Ecosystem", "Package", "Version", "Source"})
+
+	// Only show the reasons if any are known, which needs them to be looked up
+	hasReason := false
+	for _, pkgSource := range vulnResult.Results {
+		for _, pkg := range pkgSource.Packages {
+			if pkg.Package.DeprecationReason != "" {
+				hasReason = true
+			}
+		}
+	}
+
+	header := table.Row{"Ecosystem", "Package", "Version", "Source"}
+	if hasReason {
+		header = append(header, "Reason")
+	}
+	outputTable.AppendHeader(header)
+
 	workingDir := mustGetWorkingDirectory()
 	for _, pkgSource := range vulnResult.Results {
 		for _, pkg := range pkgSource.Packages {
@@ -461,10 +653,107 @@ func deprecatedPackagesTableBuilder(outputTable table.Writer, vulnResult *models
 			if simplifiedPath, err := filepath.Rel(workingDir, pkgSource.Source.Path); err == nil {
 				path = simplifiedPath
 			}
+			row := table.Row{
+				pkg.Package.Ecosystem,
+				pkg.Package.Name,
+				pkg.Package.Version,
+				path,
+			}
+			if hasReason {
+				row = append(row, pkg.Package.DeprecationReason)
+			}
+			outputTable.AppendRow(row)
+		}
+	}
+
+	return outputTable
+}
+
+func buildScorecardTable(outputWriter io.Writer, terminalWidth int, vulnResult *models.VulnerabilityResults) {
+	outputTable := newTable(outputWriter, terminalWidth)
+	outputTable = scorecardTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
+
+// scorecardTableBuilder lists the packages that have an OpenSSF Scorecard,
+// with its overall score and the results of the selected checks.
+func scorecardTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	outputTable.SetTitle("OpenSSF Scorecards")
+	outputTable.AppendHeader(table.Row{"Ecosystem", "Package", "Version", "Repository", "Score", "Checks", "Source"})
+	workingDir := mustGetWorkingDirectory()
+	for _, pkgSource := range vulnResult.Results {
+		for _, pkg := range pkgSource.Packages {
+			if pkg.Scorecard == nil {
+				continue
+			}
+			path := pkgSource.Source.Path
+			if simplifiedPath, err := filepath.Rel(workingDir, pkgSource.Source.Path); err == nil {
+				path = simplifiedPath
+			}
+			score := fmt.Sprintf("%.1f", pkg.Scorecard.OverallScore)
+			if pkg.ScorecardViolation {
+				score += "\n(below threshold)"
+			}
+			checks := make([]string, 0, len(pkg.Scorecard.Checks))
+			for _, check := range pkg.Scorecard.Checks {
+				if check.Score < 0 {
+					checks = append(checks, check.Name+": ?")
+					continue
+				}
+				checks = append(checks, fmt.Sprintf("%s: %d", check.Name, check.Score))
+			}
+			outputTable.AppendRow(table.Row{
+				pkg.Package.Ecosystem,
+				pkg.Package.Name,
+				pkg.Package.Version,
+				pkg.Scorecard.Project,
+				score,
+				strings.Join(checks, "\n"),
+				path,
+			})
+		}
+	}
+
+	return outputTable
+}
+
+func buildOutdatedPackagesTable(outputWriter io.Writer, terminalWidth int, vulnResult *models.VulnerabilityResults) {
+	outputTable := newTable(outputWriter, terminalWidth)
+	outputTable = outdatedPackagesTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
+
+// outdatedPackagesTableBuilder lists the packages that are too far behind
+// their latest release, with when each version was released.
+func outdatedPackagesTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	outputTable.SetTitle("Outdated Packages")
+	outputTable.AppendHeader(table.Row{"Ecosystem", "Package", "Version", "Released", "Latest Version", "Latest Released", "Behind", "Source"})
+	workingDir := mustGetWorkingDirectory()
+	for _, pkgSource := range vulnResult.Results {
+		for _, pkg := range pkgSource.Packages {
+			if !pkg.Outdated || pkg.Release == nil {
+				continue
+			}
+			path := pkgSource.Source.Path
+			if simplifiedPath, err := filepath.Rel(workingDir, pkgSource.Source.Path); err == nil {
+				path = simplifiedPath
+			}
 			outputTable.AppendRow(table.Row{
 				pkg.Package.Ecosystem,
 				pkg.Package.Name,
 				pkg.Package.Version,
+				pkg.Release.PublishedAt,
+				pkg.Release.LatestVersion,
+				pkg.Release.LatestPublishedAt,
+				FormatReleaseLag(*pkg.Release),
 				path,
 			})
 		}
@@ -473,6 +762,257 @@ func deprecatedPackagesTableBuilder(outputTable table.Writer, vulnResult *models
 	return outputTable
 }
 
+func buildTyposquatsTable(outputWriter io.Writer, terminalWidth int, vulnResult *models.VulnerabilityResults) {
+	outputTable := newTable(outputWriter, terminalWidth)
+	outputTable = typosquatsTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
+
+// typosquatsTableBuilder lists the packages whose names are close to those of
+// popular packages, which may have been installed by mistake.
+func typosquatsTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	outputTable.SetTitle("Possible Typosquats")
+	outputTable.AppendHeader(table.Row{"Ecosystem", "Package", "Version", "Similar To", "Source"})
+	workingDir := mustGetWorkingDirectory()
+	for _, pkgSource := range vulnResult.Results {
+		for _, pkg := range pkgSource.Packages {
+			if len(pkg.TyposquatOf) == 0 {
+				continue
+			}
+			path := pkgSource.Source.Path
+			if simplifiedPath, err := filepath.Rel(workingDir, pkgSource.Source.Path); err == nil {
+				path = simplifiedPath
+			}
+			outputTable.AppendRow(table.Row{
+				pkg.Package.Ecosystem,
+				pkg.Package.Name,
+				pkg.Package.Version,
+				strings.Join(pkg.TyposquatOf, "\n"),
+				path,
+			})
+		}
+	}
+
+	return outputTable
+}
+
+func buildUnverifiedProvenanceTable(outputWriter io.Writer, terminalWidth int, vulnResult *models.VulnerabilityResults) {
+	outputTable := newTable(outputWriter, terminalWidth)
+	outputTable = unverifiedProvenanceTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
+
+// unverifiedProvenanceTableBuilder lists the packages that were looked up but
+// have no verified build provenance, with any attestation that failed to be
+// verified.
+func unverifiedProvenanceTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	outputTable.SetTitle("Unverified Provenance")
+	outputTable.AppendHeader(table.Row{"Ecosystem", "Package", "Version", "Provenance", "Source"})
+	workingDir := mustGetWorkingDirectory()
+	for _, pkgSource := range vulnResult.Results {
+		for _, pkg := range pkgSource.Packages {
+			if pkg.Provenance == nil || pkg.Provenance.Verified {
+				continue
+			}
+			path := pkgSource.Source.Path
+			if simplifiedPath, err := filepath.Rel(workingDir, pkgSource.Source.Path); err == nil {
+				path = simplifiedPath
+			}
+			provenance := "none"
+			if *pkg.Provenance != (models.Provenance{}) {
+				provenance = "not verified"
+				if pkg.Provenance.SourceRepository != "" {
+					provenance += "\nbuilt from " + pkg.Provenance.SourceRepository
+				}
+			}
+			if pkg.ProvenanceViolation {
+				provenance += "\n(required)"
+			}
+			outputTable.AppendRow(table.Row{
+				pkg.Package.Ecosystem,
+				pkg.Package.Name,
+				pkg.Package.Version,
+				provenance,
+				path,
+			})
+		}
+	}
+
+	return outputTable
+}
+
+func buildHashMismatchesTable(outputWriter io.Writer, terminalWidth int, vulnResult *models.VulnerabilityResults) {
+	outputTable := newTable(outputWriter, terminalWidth)
+	outputTable = hashMismatchesTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
+
+// hashMismatchesTableBuilder lists the packages that a requirements file pins
+// to hashes that none of their published files have, with those hashes.
+func hashMismatchesTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	outputTable.SetTitle("Hash Mismatches (possible tampering)")
+	outputTable.AppendHeader(table.Row{"Ecosystem", "Package", "Version", "Unpublished Hashes", "Source"})
+	workingDir := mustGetWorkingDirectory()
+	for _, pkgSource := range vulnResult.Results {
+		for _, pkg := range pkgSource.Packages {
+			if len(pkg.HashMismatches) == 0 {
+				continue
+			}
+			path := pkgSource.Source.Path
+			if simplifiedPath, err := filepath.Rel(workingDir, pkgSource.Source.Path); err == nil {
+				path = simplifiedPath
+			}
+			outputTable.AppendRow(table.Row{
+				pkg.Package.Ecosystem,
+				pkg.Package.Name,
+				pkg.Package.Version,
+				strings.Join(pkg.HashMismatches, "\n"),
+				path,
+			})
+		}
+	}
+
+	return outputTable
+}
+
+func buildNotAffectedTable(outputWriter io.Writer, terminalWidth int, vulnResult *models.VulnerabilityResults) {
+	outputTable := newTable(outputWriter, terminalWidth)
+	outputTable = notAffectedTableBuilder(outputTable, vulnResult)
+
+	if outputTable.Length() == 0 {
+		return
+	}
+	outputTable.Render()
+}
+
+// notAffectedTableBuilder lists the vulnerabilities that were left out of the
+// results because a VEX document states they do not affect the package, with
+// the justification given for it.
+func notAffectedTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	outputTable.SetTitle("Not Affected")
+	outputTable.AppendHeader(table.Row{"Vulnerability", "Ecosystem", "Package", "Version", "Justification", "VEX Document"})
+	workingDir := mustGetWorkingDirectory()
+	for _, ignored := range vulnResult.ExperimentalIgnored {
+		if ignored.VEXSource == "" {
+			continue
+		}
+		path := ignored.VEXSource
+		if simplifiedPath, err := filepath.Rel(workingDir, ignored.VEXSource); err == nil {
+			path = simplifiedPath
+		}
+		justification := ignored.Justification
+		if ignored.Reason != "" {
+			if justification != "" {
+				justification += "\n"
+			}
+			justification += ignored.Reason
+		}
+		outputTable.AppendRow(table.Row{
+			strings.Join(ignored.Group.IDs, "\n"),
+			ignored.Package.Ecosystem,
+			ignored.Package.Name,
+			ignored.Package.Version,
+			justification,
+			path,
+		})
+	}
+
+	return outputTable
+}
+
+func buildDriftTable(outputWriter io.Writer, terminalWidth int, vulnResult *models.VulnerabilityResults) {
+	if len(vulnResult.ExperimentalDrift) == 0 {
+		return
+	}
+
+	outputTable := newTable(outputWriter, terminalWidth)
+	outputTable = driftTableBuilder(outputTable, vulnResult)
+	outputTable.Render()
+}
+
+// driftTableBuilder lists the dependencies declared in manifests that are
+// missing from, or locked to versions that do not satisfy, their lockfiles.
+func driftTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	outputTable.SetTitle("Manifest/Lockfile Drift")
+	outputTable.AppendHeader(table.Row{"Ecosystem", "Package", "Declared", "Locked", "Issue", "Source"})
+	workingDir := mustGetWorkingDirectory()
+	for _, drift := range vulnResult.ExperimentalDrift {
+		path := drift.Lockfile
+		if simplifiedPath, err := filepath.Rel(workingDir, drift.Lockfile); err == nil {
+			path = simplifiedPath
+		}
+		issue := "missing from lockfile"
+		if drift.Reason == models.DriftInconsistent {
+			issue = "locked version does not satisfy declaration"
+		}
+		outputTable.AppendRow(table.Row{
+			drift.Ecosystem,
+			drift.Name,
+			drift.Declared,
+			strings.Join(drift.Locked, "\n"),
+			issue,
+			path,
+		})
+	}
+
+	return outputTable
+}
+
+func buildUpgradePlanTable(outputWriter io.Writer, terminalWidth int, vulnResult *models.VulnerabilityResults) {
+	if len(vulnResult.ExperimentalUpgradePlan) == 0 {
+		return
+	}
+
+	outputTable := newTable(outputWriter, terminalWidth)
+	outputTable = upgradePlanTableBuilder(outputTable, vulnResult)
+	outputTable.Render()
+}
+
+// upgradePlanTableBuilder lists the minimal upgrade of each vulnerable package,
+// along with the vulnerabilities it fixes and those it leaves behind.
+func upgradePlanTableBuilder(outputTable table.Writer, vulnResult *models.VulnerabilityResults) table.Writer {
+	outputTable.SetTitle("Minimal Upgrades")
+	outputTable.AppendHeader(table.Row{"Ecosystem", "Package", "From", "To", "Fixes", "Still Affected By"})
+	for _, upgrade := range vulnResult.ExperimentalUpgradePlan {
+		outputTable.AppendRow(table.Row{
+			upgrade.Ecosystem,
+			upgrade.Name,
+			upgrade.From,
+			upgrade.To,
+			strings.Join(upgrade.Fixed, "\n"),
+			strings.Join(upgrade.Remaining, "\n"),
+		})
+	}
+
+	return outputTable
+}
+
+// FormatReleaseLag describes how far behind the latest release a package
+// version is, e.g. "2 major versions, 30 months".
+func FormatReleaseLag(release models.Release) string {
+	var lag []string
+	if release.MajorVersionsBehind > 0 {
+		lag = append(lag, fmt.Sprintf("%d major %s", release.MajorVersionsBehind, Form(release.MajorVersionsBehind, "version", "versions")))
+	}
+	if release.MonthsBehind > 0 {
+		lag = append(lag, fmt.Sprintf("%d %s", release.MonthsBehind, Form(release.MonthsBehind, "month", "months")))
+	}
+
+	return strings.Join(lag, ", ")
+}
+
 func formatBinaryPackages(slice []string) string {
 	maxChars := 20
 	result := strings.Join(slice, ", ")
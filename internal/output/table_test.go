@@ -6,7 +6,9 @@ import (
 
 	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/pkg/models"
 	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
 func TestPrintTableResults_StandardTerminalWidth_WithVulnerabilities(t *testing.T) {
@@ -125,3 +127,345 @@ func TestPrintTableResults_NoTerminalWidth_WithMixedIssues(t *testing.T) {
 		testutility.NewSnapshot().MatchText(t, outputWriter.String())
 	})
 }
+
+func TestPrintTableResults_WithScorecards(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "left-pad", Version: "1.3.0", Ecosystem: "npm"},
+						Scorecard: &models.Scorecard{
+							Project:      "github.com/left-pad/left-pad",
+							Date:         "2026-10-12",
+							OverallScore: 3.1,
+							Checks: []models.ScorecardCheck{
+								{Name: "Maintained", Score: 0},
+								{Name: "Dangerous-Workflow", Score: -1},
+							},
+						},
+						ScorecardViolation: true,
+					},
+					{
+						Package: models.PackageInfo{Name: "express", Version: "4.21.2", Ecosystem: "npm"},
+						Scorecard: &models.Scorecard{
+							Project:      "github.com/expressjs/express",
+							Date:         "2026-10-12",
+							OverallScore: 8.2,
+							Checks: []models.ScorecardCheck{
+								{Name: "Maintained", Score: 10},
+								{Name: "Dangerous-Workflow", Score: 10},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0, false)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithDependents(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+						Vulnerabilities: []*osvschema.Vulnerability{{Id: "GHSA-35jh-r3h4-6jhm"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-35jh-r3h4-6jhm"}}},
+						Dependents:      &models.Dependents{Count: 180000, Direct: 60000, Indirect: 120000},
+					},
+					{
+						Package:         models.PackageInfo{Name: "left-pad", Version: "1.3.0", Ecosystem: "npm"},
+						Vulnerabilities: []*osvschema.Vulnerability{{Id: "GHSA-xxxx-xxxx-xxxx"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-xxxx-xxxx-xxxx"}}},
+						Dependents:      &models.Dependents{Count: 1, Direct: 1},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0, false)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithOutdatedPackages(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "express", Version: "3.21.2", Ecosystem: "npm"},
+						Release: &models.Release{
+							PublishedAt:         "2015-07-31",
+							LatestVersion:       "5.1.0",
+							LatestPublishedAt:   "2025-03-31",
+							MajorVersionsBehind: 2,
+							MonthsBehind:        116,
+						},
+						Outdated: true,
+					},
+					{
+						Package: models.PackageInfo{Name: "left-pad", Version: "1.1.3", Ecosystem: "npm"},
+						Release: &models.Release{
+							PublishedAt:       "2016-03-23",
+							LatestVersion:     "1.3.0",
+							LatestPublishedAt: "2018-04-09",
+							MonthsBehind:      24,
+						},
+						Outdated: true,
+					},
+					{
+						Package: models.PackageInfo{Name: "lodash", Version: "4.17.21", Ecosystem: "npm"},
+						Release: &models.Release{
+							PublishedAt:       "2021-02-20",
+							LatestVersion:     "4.17.21",
+							LatestPublishedAt: "2021-02-20",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0, false)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithMaliciousPackages(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					{
+						Package:         models.PackageInfo{Name: "evil-pkg", Version: "1.0.0", Ecosystem: "npm"},
+						Vulnerabilities: []*osvschema.Vulnerability{{Id: "MAL-2024-1234"}},
+						Groups: []models.GroupInfo{{
+							IDs:       []string{"MAL-2024-1234"},
+							Aliases:   []string{"MAL-2024-1234"},
+							Malicious: true,
+						}},
+					},
+					{
+						Package:         models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+						Vulnerabilities: []*osvschema.Vulnerability{{Id: "GHSA-35jh-r3h4-6jhm"}},
+						Groups:          []models.GroupInfo{{IDs: []string{"GHSA-35jh-r3h4-6jhm"}}},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0, false)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithTyposquats(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					{
+						Package:     models.PackageInfo{Name: "expres", Version: "1.0.0", Ecosystem: "npm"},
+						TyposquatOf: []string{"express"},
+					},
+					{
+						Package:     models.PackageInfo{Name: "requets", Version: "2.88.2", Ecosystem: "npm"},
+						TyposquatOf: []string{"request", "requests"},
+					},
+					{
+						Package: models.PackageInfo{Name: "react", Version: "18.2.0", Ecosystem: "npm"},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0, false)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithNotAffected(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		ExperimentalIgnored: []models.IgnoredVulnerability{
+			{
+				Source:        models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Package:       models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+				Group:         models.GroupInfo{IDs: []string{"GHSA-35jh-r3h4-6jhm"}, Aliases: []string{"GHSA-35jh-r3h4-6jhm", "CVE-2021-23337"}},
+				Justification: "vulnerable_code_not_in_execute_path",
+				Reason:        "template() is never called",
+				VEXSource:     "/path/to/vex.json",
+			},
+			{
+				Source:  models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Package: models.PackageInfo{Name: "minimist", Version: "1.2.5", Ecosystem: "npm"},
+				Group:   models.GroupInfo{IDs: []string{"GHSA-xvch-5gv4-984h"}, Aliases: []string{"GHSA-xvch-5gv4-984h"}},
+				Reason:  "ignored in the config, so not listed",
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0, false)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithHashMismatches(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/requirements.txt", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "requests", Version: "2.31.0", Ecosystem: "PyPI"},
+						HashMismatches: []string{
+							"sha256:0000000000000000000000000000000000000000000000000000000000000000",
+						},
+					},
+					{
+						Package: models.PackageInfo{Name: "idna", Version: "3.6", Ecosystem: "PyPI"},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0, false)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithDrift(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		ExperimentalDrift: []models.Drift{
+			{
+				Manifest:  "/path/to/package.json",
+				Lockfile:  "/path/to/package-lock.json",
+				Ecosystem: "npm",
+				Name:      "express",
+				Declared:  "^4.19.0",
+				Locked:    []string{"4.18.2"},
+				Reason:    models.DriftInconsistent,
+			},
+			{
+				Manifest:  "/path/to/package.json",
+				Lockfile:  "/path/to/package-lock.json",
+				Ecosystem: "npm",
+				Name:      "lodash",
+				Declared:  "^4.17.21",
+				Reason:    models.DriftMissing,
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0, false)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithUpgradePlan(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		ExperimentalUpgradePlan: []models.Upgrade{
+			{
+				Ecosystem: "npm",
+				Name:      "lodash",
+				From:      "4.17.15",
+				To:        "4.17.21",
+				Fixed:     []string{"GHSA-29mw-wpgm-hmr9", "GHSA-35jh-r3h4-6jhm", "GHSA-p6mc-m468-83gw"},
+			},
+			{
+				Ecosystem: "PyPI",
+				Name:      "jinja2",
+				From:      "2.4.1",
+				To:        "2.11.3",
+				Fixed:     []string{"PYSEC-2019-217"},
+				Remaining: []string{"GHSA-h5c8-rqwp-cp95"},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0, false)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
+
+func TestPrintTableResults_WithUnverifiedProvenance(t *testing.T) {
+	t.Parallel()
+
+	vulnResult := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					{
+						Package:             models.PackageInfo{Name: "left-pad", Version: "1.3.0", Ecosystem: "npm"},
+						Provenance:          &models.Provenance{},
+						ProvenanceViolation: true,
+					},
+					{
+						Package: models.PackageInfo{Name: "sigstore", Version: "3.0.0", Ecosystem: "npm"},
+						Provenance: &models.Provenance{
+							Type:             "https://slsa.dev/provenance/v1",
+							SourceRepository: "https://github.com/sigstore/sigstore-js",
+						},
+						ProvenanceViolation: true,
+					},
+					{
+						Package: models.PackageInfo{Name: "semver", Version: "7.6.3", Ecosystem: "npm"},
+						Provenance: &models.Provenance{
+							Verified:         true,
+							Type:             "https://slsa.dev/provenance/v1",
+							SourceRepository: "https://github.com/npm/node-semver",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	outputWriter := &bytes.Buffer{}
+	output.PrintTableResults(vulnResult, outputWriter, 0, false)
+
+	testutility.NewSnapshot().MatchText(t, text.StripEscape(outputWriter.String()))
+}
@@ -0,0 +1,47 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// defaultTemplate lists each vulnerability found on its own line, and is used
+// when no template is given.
+const defaultTemplate = `{{ range .Flatten }}{{ if .Vulnerability -}}
+{{ .Source.Path }}: {{ .Package.Name }}@{{ .Package.Version }} {{ .Vulnerability.Id }}
+{{ end }}{{ end }}`
+
+// templateFuncs are available to templates in addition to the text/template
+// built-ins.
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"osvURL": func(id string) string {
+		return "https://osv.dev/" + id
+	},
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// PrintTemplateResults renders the results through the text/template in tmpl,
+// with the models.VulnerabilityResults as its data. If tmpl is empty, a
+// template listing each vulnerability found is used.
+func PrintTemplateResults(vulnResult *models.VulnerabilityResults, tmpl string, outputWriter io.Writer) error {
+	if tmpl == "" {
+		tmpl = defaultTemplate
+	}
+
+	t, err := template.New("template").Funcs(templateFuncs).Parse(tmpl)
+	if err != nil {
+		return err
+	}
+
+	return t.Execute(outputWriter, vulnResult)
+}
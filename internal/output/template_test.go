@@ -0,0 +1,62 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+)
+
+func TestPrintTemplateResults_WithVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	testOutputWithVulnerabilities(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		outputWriter := &bytes.Buffer{}
+		err := output.PrintTemplateResults(args.vulnResult, "", outputWriter)
+
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+
+		testutility.NewSnapshot().MatchText(t, outputWriter.String())
+	})
+}
+
+func TestPrintTemplateResults_CustomTemplate(t *testing.T) {
+	t.Parallel()
+
+	tmpl := `{{ range .Results }}{{ .Source.Path }}
+{{ range .Packages }}{{ range .Groups }}  {{ join .IDs ", " | upper }} {{ osvURL (index .IDs 0) }}
+{{ end }}{{ end }}{{ end }}`
+
+	testOutputWithMixedIssues(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		outputWriter := &bytes.Buffer{}
+		err := output.PrintTemplateResults(args.vulnResult, tmpl, outputWriter)
+
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+
+		testutility.NewSnapshot().MatchText(t, outputWriter.String())
+	})
+}
+
+func TestPrintTemplateResults_InvalidTemplate(t *testing.T) {
+	t.Parallel()
+
+	testOutputWithVulnerabilities(t, func(t *testing.T, args outputTestCaseArgs) {
+		t.Helper()
+
+		outputWriter := &bytes.Buffer{}
+		err := output.PrintTemplateResults(args.vulnResult, "{{ range .Results }}", outputWriter)
+
+		if err == nil {
+			t.Errorf("expected an error for an unterminated template")
+		}
+	})
+}
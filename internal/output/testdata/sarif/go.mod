@@ -0,0 +1,8 @@
+module example.com/project
+
+go 1.23
+
+require (
+	github.com/gogo/protobuf v1.3.1
+	github.com/gogo/protobuf-extras v0.1.0
+)
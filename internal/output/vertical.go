@@ -15,6 +15,7 @@ func PrintVerticalResults(vulnResult *models.VulnerabilityResults, outputWriter
 	// Add a newline to separate results from logs.
 	fmt.Fprintln(outputWriter)
 	outputResult := BuildResults(vulnResult)
+	printMaliciousPackages(vulnResult, outputWriter)
 	if outputResult.IsContainerScanning {
 		fmt.Fprintf(outputWriter, "%s:\n", GetContainerScanningHeader(outputResult))
 	}
@@ -113,9 +114,15 @@ func printVerticalPkgDeprecatedSummary(source SourceResult, out io.Writer) {
 			continue
 		}
 
+		reason := ""
+		if pkg.DeprecationReason != "" {
+			reason = " (" + pkg.DeprecationReason + ")"
+		}
+
 		fmt.Fprintf(out,
-			"    %s\n",
+			"    %s%s\n",
 			text.FgYellow.Sprintf("%s@%s", pkg.Name, pkg.InstalledVersion),
+			reason,
 		)
 	}
 }
@@ -238,6 +245,17 @@ func printVerticalVulnerabilitiesForPackages(packages []PackageResult, out io.Wr
 				describe(vulnerability),
 			)
 
+			if vulnerability.KEV != nil {
+				fmt.Fprintf(out,
+					"      %s since %s\n",
+					text.FgRed.Sprint(KEVLabel),
+					vulnerability.KEV.DateAdded,
+				)
+			}
+			if vulnerability.Malicious {
+				fmt.Fprintf(out, "      %s\n", text.Colors{text.Bold, text.FgRed}.Sprint(MaliciousLabel))
+			}
+
 			fmt.Fprintf(out,
 				"      Severity: '%s'; Minimal Fix Version: '%s';\n",
 				vulnerability.SeverityScore,
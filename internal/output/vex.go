@@ -0,0 +1,107 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// vexStatus is what a VEX statement says about a vulnerability in a package.
+type vexStatus int
+
+const (
+	// vexAffected means the vulnerability applies to the package.
+	vexAffected vexStatus = iota
+	// vexNotCalled means call analysis found the vulnerable code is not called.
+	vexNotCalled
+	// vexIgnored means the vulnerability was ignored in the config.
+	vexIgnored
+)
+
+// vexFinding is a group of aliased vulnerabilities found in a package, as
+// stated in the VEX output formats.
+type vexFinding struct {
+	Package models.PackageInfo
+	Group   models.GroupInfo
+	// Aliases is the group of all the aliases of the vulnerability across the
+	// results, so that it is named the same way whichever package it is in.
+	Aliases models.GroupInfo
+	Status  vexStatus
+	// CallAnalysed is whether call analysis ran for the vulnerability, so
+	// that its status says whether the vulnerable code is called.
+	CallAnalysed bool
+	// FixedVersion is the first version of the package above the installed
+	// one that fixes the vulnerability, if it is known.
+	FixedVersion string
+	// FixedVersions are the versions of the package fixing the vulnerability.
+	FixedVersions []string
+	// Reason is the reason given for ignoring the vulnerability, if any.
+	Reason string
+	// Justification is why the package is not affected, when a VEX document
+	// given to the scan says so.
+	Justification string
+}
+
+// vexFindings returns the findings of the results, followed by the
+// vulnerabilities that were ignored.
+func vexFindings(vulnResult *models.VulnerabilityResults) []vexFinding {
+	fixedVersions := groupFixedVersions(vulnResult.Flatten())
+	aliases := aliasGroups(vulnResult)
+
+	var findings []vexFinding
+	for _, source := range vulnResult.Results {
+		for _, pkg := range source.Packages {
+			for _, group := range pkg.Groups {
+				finding := vexFinding{
+					Package:      pkg.Package,
+					Group:        group,
+					Aliases:      aliases[group.IDs[0]],
+					Status:       vexAffected,
+					CallAnalysed: len(group.ExperimentalAnalysis) > 0,
+				}
+				if group.IsCalled() {
+					finding.FixedVersion = groupFixedVersion(group, pkg)
+					finding.FixedVersions = fixedVersions[source.Source.String()+":"+group.IndexString()]
+				} else {
+					finding.Status = vexNotCalled
+				}
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	for _, ignored := range vulnResult.ExperimentalIgnored {
+		findings = append(findings, vexFinding{
+			Package:       ignored.Package,
+			Group:         ignored.Group,
+			Aliases:       aliases[ignored.Group.IDs[0]],
+			Status:        vexIgnored,
+			Reason:        ignored.Reason,
+			Justification: ignored.Justification,
+		})
+	}
+
+	return findings
+}
+
+// vexIgnoredReason returns the reason for ignoring a vulnerability, with a
+// default for when the config does not give one.
+func vexIgnoredReason(finding vexFinding) string {
+	if finding.Reason == "" {
+		return "Ignored in the osv-scanner config"
+	}
+
+	return finding.Reason
+}
+
+// vexAction returns what to do about an affected package.
+func vexAction(finding vexFinding) string {
+	if finding.FixedVersion != "" {
+		return "Upgrade to " + finding.FixedVersion + " or later"
+	}
+	if len(finding.FixedVersions) == 0 {
+		return "No fixed version is available"
+	}
+
+	return "Upgrade to a fixed version: " + strings.Join(finding.FixedVersions, ", ")
+}
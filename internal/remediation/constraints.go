@@ -0,0 +1,114 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/resolution"
+	"github.com/google/osv-scanner/v2/internal/resolution/client"
+	"github.com/google/osv-scanner/v2/internal/resolution/util"
+	"github.com/google/osv-scanner/v2/internal/utility/vulns"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// ParseAllowedVersion parses the allowed version range of a package.
+// Ranges use the syntax of the ecosystem, except for Go modules, which have no range syntax of their own
+// and use npm's (e.g. "<2.0.0") without the "v" prefix.
+func ParseAllowedVersion(sys semver.System, versions string) (*semver.Constraint, error) {
+	if sys == semver.Go {
+		sys = semver.NPM
+	}
+	c, err := sys.ParseConstraint(versions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allowed versions %q: %w", versions, err)
+	}
+
+	return c, nil
+}
+
+// allowsVersion reports whether a package may be upgraded to version, according to its allowed version range.
+// Packages without an allowed range allow every version.
+func (opts Options) allowsVersion(sys semver.System, name, version string) bool {
+	versions, ok := opts.AllowedVersions[name]
+	if !ok {
+		return true
+	}
+	c, err := ParseAllowedVersion(sys, versions)
+	if err != nil {
+		// Ranges are validated when they are loaded.
+		return true
+	}
+	if sys == semver.Go {
+		version = strings.TrimPrefix(version, "v")
+	}
+
+	return c.Match(version)
+}
+
+// withinAllowedVersions reports whether the package versions in res that are not in orig are within their allowed ranges.
+func withinAllowedVersions(orig, res *resolution.Result, opts Options) bool {
+	if len(opts.AllowedVersions) == 0 {
+		return true
+	}
+	existing := make(map[resolve.VersionKey]bool)
+	for _, n := range orig.Graph.Nodes {
+		existing[n.Version] = true
+	}
+	for _, n := range res.Graph.Nodes {
+		if !existing[n.Version] && !opts.allowsVersion(n.Version.Semver(), n.Version.Name, n.Version.Version) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fixedOutsideAllowedVersions reports whether a version of a package outside of its allowed version range is not affected by vuln.
+// versions are the versions the package can be upgraded to, and pkg describes the package at a given version.
+func (opts Options) fixedOutsideAllowedVersions(sys semver.System, name string, versions []string, vuln *osvschema.Vulnerability, pkg func(version string) imodels.PackageInfo) bool {
+	if _, ok := opts.AllowedVersions[name]; !ok {
+		return false
+	}
+	for _, v := range versions {
+		if !opts.allowsVersion(sys, name, v) && !vulns.IsAffected(vuln, pkg(v)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ConstrainedVulns returns the vulnerabilities in vs that the allowed version ranges prevent fixing:
+// those where a newer version of an affected package, outside of its allowed range, is not vulnerable.
+func ConstrainedVulns(ctx context.Context, cl client.DependencyClient, vs []resolution.Vulnerability, opts Options) ([]resolution.Vulnerability, error) {
+	var constrained []resolution.Vulnerability
+	for _, v := range vs {
+		for _, sg := range v.Subgraphs {
+			vk := sg.Nodes[sg.Dependency].Version
+			if _, ok := opts.AllowedVersions[vk.Name]; !ok {
+				continue
+			}
+			newer, err := getVersionsGreater(ctx, cl, vk)
+			if err != nil {
+				return nil, err
+			}
+			versions := make([]string, len(newer))
+			for i, ver := range newer {
+				versions[i] = ver.Version
+			}
+			pkg := func(version string) imodels.PackageInfo {
+				return util.VKToPackageInfo(resolve.VersionKey{PackageKey: vk.PackageKey, Version: version, VersionType: resolve.Concrete})
+			}
+			if opts.fixedOutsideAllowedVersions(vk.Semver(), vk.Name, versions, v.OSV, pkg) {
+				constrained = append(constrained, v)
+				break
+			}
+		}
+	}
+
+	return constrained, nil
+}
@@ -0,0 +1,90 @@
+package remediation_test
+
+import (
+	"slices"
+	"testing"
+
+	"deps.dev/util/semver"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/remediation"
+	"github.com/google/osv-scanner/v2/internal/remediation/upgrade"
+)
+
+func TestParseAllowedVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		sys      semver.System
+		versions string
+		match    string
+		want     bool
+		wantErr  bool
+	}{
+		{sys: semver.PyPI, versions: "<5", match: "4.2.11", want: true},
+		{sys: semver.PyPI, versions: "<5", match: "5.0", want: false},
+		{sys: semver.Maven, versions: "[31.0,33.0)", match: "32.1.3-jre", want: true},
+		// Go modules use npm's syntax
+		{sys: semver.Go, versions: "<0.17.0", match: "0.16.0", want: true},
+		{sys: semver.Go, versions: "<0.17.0", match: "0.17.0", want: false},
+		{sys: semver.Maven, versions: "[1.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		c, err := remediation.ParseAllowedVersion(tt.sys, tt.versions)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAllowedVersion(%v, %q) error = %v, wantErr %v", tt.sys, tt.versions, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := c.Match(tt.match); got != tt.want {
+			t.Errorf("ParseAllowedVersion(%v, %q).Match(%q) = %v, want %v", tt.sys, tt.versions, tt.match, got, tt.want)
+		}
+	}
+}
+
+func TestConstrainedVulns(t *testing.T) {
+	t.Parallel()
+
+	opts := remediation.Options{
+		DevDeps:       true,
+		MaxDepth:      -1,
+		UpgradeConfig: upgrade.NewConfig(),
+		AllowedVersions: map[string]string{
+			"com.example:direct": "[1.0.0,2.0.0)",
+			"com.example:lib":    "[1.0.0,1.0.1)",
+		},
+	}
+
+	res, cl := parseRemediationFixture(t, "./testdata/maven-pins/universe.yaml", "./testdata/maven-pins/vulns.json", "./testdata/maven-pins/pom.xml", opts.ResolveOpts)
+	res.FilterVulns(opts.MatchVuln)
+	patches, err := remediation.ComputeOverridePatches(t.Context(), cl, res, opts)
+	if err != nil {
+		t.Fatalf("Failed to compute override patches: %v", err)
+	}
+
+	var fixed []string
+	for _, p := range patches {
+		for _, v := range p.RemovedVulns {
+			fixed = append(fixed, v.OSV.GetId())
+		}
+	}
+	slices.Sort(fixed)
+	// only the unconstrained package is overridden
+	if diff := cmp.Diff([]string{"GHSA-0002"}, slices.Compact(fixed)); diff != "" {
+		t.Errorf("ComputeOverridePatches() fixed vulns mismatch (-want +got):\n%s", diff)
+	}
+
+	constrained, err := remediation.ConstrainedVulns(t.Context(), cl, res.Vulns, opts)
+	if err != nil {
+		t.Fatalf("ConstrainedVulns() error = %v", err)
+	}
+	var got []string
+	for _, v := range constrained {
+		got = append(got, v.OSV.GetId())
+	}
+	slices.Sort(got)
+	if diff := cmp.Diff([]string{"GHSA-0001", "GHSA-0003"}, got); diff != "" {
+		t.Errorf("ConstrainedVulns() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,224 @@
+package remediation
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"deps.dev/util/semver"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/remediation/minimalupgrade"
+	"github.com/google/osv-scanner/v2/internal/resolution"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"golang.org/x/mod/modfile"
+)
+
+// GoModuleVersionLister lists the known versions of Go modules.
+type GoModuleVersionLister interface {
+	Versions(ctx context.Context, modulePath string) ([]string, error)
+}
+
+// GoModRequirement is a module required by a go.mod, along with the vulnerabilities affecting it.
+type GoModRequirement struct {
+	Module   string
+	Version  string
+	Indirect bool
+	Vulns    []*osvschema.Vulnerability
+}
+
+// GoModPatch bumps a module required by a go.mod to the minimal version that fixes its vulnerabilities.
+type GoModPatch struct {
+	GoModRequirement
+
+	NewVersion string
+	Fixed      []*osvschema.Vulnerability
+}
+
+// GoModResult is the outcome of remediating a go.mod.
+type GoModResult struct {
+	Vulnerable []GoModRequirement // the vulnerable modules required by the go.mod
+	Patches    []GoModPatch       // the version bumps that fix vulnerabilities
+	Remaining  []GoModRequirement // the vulnerabilities that no allowed version of their module fixes
+	// Constrained are the vulnerabilities that only a version outside of the allowed version range of their module fixes.
+	// They are not included in Remaining.
+	Constrained []GoModRequirement
+}
+
+// ComputeGoModPatches finds the vulnerable modules required by a go.mod and the minimal
+// version of each that fixes as many of their vulnerabilities as possible.
+//
+// Under minimal version selection the go.mod lists the selected version of every module in the build,
+// and raising a requirement can only ever raise the selected version, so each module is only
+// considered for upgrades from the version that is currently selected.
+func ComputeGoModPatches(ctx context.Context, matcher clientinterfaces.VulnerabilityMatcher, lister GoModuleVersionLister, f *modfile.File, opts Options) (GoModResult, error) {
+	replaced := make(map[string]bool)
+	for _, r := range f.Replace {
+		replaced[r.Old.Path] = true
+	}
+
+	var reqs []GoModRequirement
+	var pkgs []*extractor.Package
+	for _, r := range f.Require {
+		// The version of a replaced module is not the code that is built, so bumping it fixes nothing.
+		if replaced[r.Mod.Path] {
+			continue
+		}
+		// go.mod files do not distinguish how deep indirect dependencies are.
+		if r.Indirect && opts.MaxDepth == 1 {
+			continue
+		}
+		reqs = append(reqs, GoModRequirement{
+			Module:   r.Mod.Path,
+			Version:  r.Mod.Version,
+			Indirect: r.Indirect,
+		})
+		pkgs = append(pkgs, goPackage(r.Mod.Path, r.Mod.Version))
+	}
+
+	if len(pkgs) == 0 {
+		return GoModResult{}, nil
+	}
+
+	matched, err := matcher.MatchVulnerabilities(ctx, pkgs)
+	if err != nil {
+		return GoModResult{}, err
+	}
+
+	// Depth was already accounted for when reading the requirements.
+	opts.MaxDepth = 0
+
+	var result GoModResult
+	var findings []minimalupgrade.Finding
+	for i, req := range reqs {
+		for _, v := range matched[i] {
+			if opts.MatchVuln(resolution.Vulnerability{OSV: v}) {
+				req.Vulns = append(req.Vulns, v)
+			}
+		}
+		if len(req.Vulns) == 0 {
+			continue
+		}
+		result.Vulnerable = append(result.Vulnerable, req)
+		findings = append(findings, minimalupgrade.Finding{
+			Package: imodels.FromInventory(pkgs[i]),
+			Vulns:   req.Vulns,
+		})
+	}
+
+	plan, err := minimalupgrade.Compute(ctx, findings, minimalupgrade.Options{
+		Versions: func(ctx context.Context, pkg imodels.PackageInfo) ([]string, error) {
+			return goModuleVersions(ctx, lister, pkg)
+		},
+		Allows: func(pkg imodels.PackageInfo, version string) bool {
+			_, diff, _ := semver.Go.Difference("v"+pkg.Version(), "v"+version)
+			return opts.UpgradeConfig.Get(pkg.Name()).Allows(diff) && opts.allowsVersion(semver.Go, pkg.Name(), version)
+		},
+	})
+	if err != nil {
+		return GoModResult{}, err
+	}
+
+	requirement := func(modulePath string) GoModRequirement {
+		i := slices.IndexFunc(result.Vulnerable, func(r GoModRequirement) bool { return r.Module == modulePath })
+		return result.Vulnerable[i]
+	}
+	for _, u := range plan.Upgrades {
+		req := requirement(u.Package.Name())
+		result.Patches = append(result.Patches, GoModPatch{
+			GoModRequirement: req,
+			NewVersion:       "v" + u.Version,
+			Fixed:            u.Fixed,
+		})
+		if len(u.Remaining) > 0 {
+			req.Vulns = u.Remaining
+			result.Remaining = append(result.Remaining, req)
+		}
+	}
+	for _, f := range plan.Unfixable {
+		result.Remaining = append(result.Remaining, requirement(f.Package.Name()))
+	}
+	if len(opts.AllowedVersions) > 0 {
+		result.Remaining, result.Constrained = constrainedGoModRequirements(ctx, lister, result.Remaining, opts)
+	}
+
+	return result, nil
+}
+
+// constrainedGoModRequirements splits the vulnerabilities of the remaining requirements into the ones
+// a newer version outside of the module's allowed version range fixes, and the rest.
+func constrainedGoModRequirements(ctx context.Context, lister GoModuleVersionLister, reqs []GoModRequirement, opts Options) ([]GoModRequirement, []GoModRequirement) {
+	var remaining, constrained []GoModRequirement
+	for _, r := range reqs {
+		if _, ok := opts.AllowedVersions[r.Module]; !ok {
+			remaining = append(remaining, r)
+			continue
+		}
+		versions, _ := goModuleVersions(ctx, lister, imodels.FromInventory(goPackage(r.Module, r.Version)))
+		versions = slices.DeleteFunc(versions, func(v string) bool { return semver.Go.Compare("v"+v, r.Version) <= 0 })
+		pkg := func(version string) imodels.PackageInfo { return imodels.FromInventory(goPackage(r.Module, version)) }
+
+		c, rest := r, r
+		c.Vulns, rest.Vulns = nil, nil
+		for _, v := range r.Vulns {
+			if opts.fixedOutsideAllowedVersions(semver.Go, r.Module, versions, v, pkg) {
+				c.Vulns = append(c.Vulns, v)
+			} else {
+				rest.Vulns = append(rest.Vulns, v)
+			}
+		}
+		if len(c.Vulns) > 0 {
+			constrained = append(constrained, c)
+		}
+		if len(rest.Vulns) > 0 {
+			remaining = append(remaining, rest)
+		}
+	}
+
+	return remaining, constrained
+}
+
+// goModuleVersions lists the versions a module can be upgraded to, without the "v" prefix of Go module versions.
+// Pre-releases are only included if the module is already on one.
+func goModuleVersions(ctx context.Context, lister GoModuleVersionLister, pkg imodels.PackageInfo) ([]string, error) {
+	versions, err := lister.Versions(ctx, pkg.Name())
+	if err != nil {
+		// Treat modules that cannot be listed (e.g. private modules) as having no fix.
+		cmdlogger.Warnf("Failed to list the versions of %s: %v", pkg.Name(), err)
+		return nil, nil
+	}
+
+	current, err := semver.Go.Parse("v" + pkg.Version())
+	if err != nil {
+		return nil, nil //nolint:nilerr // versions that cannot be compared cannot be upgraded
+	}
+
+	var candidates []string
+	for _, v := range versions {
+		parsed, err := semver.Go.Parse(v)
+		if err != nil {
+			continue
+		}
+		if parsed.IsPrerelease() && !current.IsPrerelease() {
+			continue
+		}
+		// +incompatible versions are a different module to the ones with a go.mod.
+		if strings.HasSuffix(v, "+incompatible") != strings.HasSuffix(pkg.Version(), "+incompatible") {
+			continue
+		}
+		candidates = append(candidates, strings.TrimPrefix(v, "v"))
+	}
+
+	return candidates, nil
+}
+
+func goPackage(modulePath, version string) *extractor.Package {
+	return &extractor.Package{
+		Name:     modulePath,
+		Version:  strings.TrimPrefix(version, "v"),
+		PURLType: purl.TypeGolang,
+	}
+}
@@ -0,0 +1,174 @@
+package remediation_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/remediation"
+	"github.com/google/osv-scanner/v2/internal/remediation/upgrade"
+	"github.com/google/osv-scanner/v2/internal/resolution/clienttest"
+	"golang.org/x/mod/modfile"
+)
+
+type mockGoModuleVersionLister map[string][]string
+
+func (l mockGoModuleVersionLister) Versions(_ context.Context, modulePath string) ([]string, error) {
+	return l[modulePath], nil
+}
+
+func TestComputeGoModPatches(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile("./testdata/gomod/go.mod")
+	if err != nil {
+		t.Fatalf("Failed to read go.mod: %v", err)
+	}
+	f, err := modfile.Parse("go.mod", data, nil)
+	if err != nil {
+		t.Fatalf("Failed to parse go.mod: %v", err)
+	}
+
+	vulnData, err := os.ReadFile("./testdata/gomod/vulns.json")
+	if err != nil {
+		t.Fatalf("Failed to read vulns: %v", err)
+	}
+	var matcher clienttest.VulnerabilityMatcher
+	if err := json.Unmarshal(vulnData, &matcher); err != nil {
+		t.Fatalf("Failed to parse vulns: %v", err)
+	}
+
+	lister := mockGoModuleVersionLister{
+		"example.com/foo":      {"v1.0.0", "v1.3.0", "v1.1.0", "v1.2.0", "v1.4.0-rc.1", "v1.4.0"},
+		"example.com/bar":      {"v0.1.0", "v0.3.0", "v0.4.0"},
+		"example.com/baz":      {"v1.4.0", "v1.5.0", "v1.5.1"},
+		"example.com/replaced": {"v1.0.0", "v1.1.0"},
+	}
+
+	// minimal summary of the result, as module@version: vuln IDs
+	type summary struct {
+		Patches     map[string][]string
+		Remaining   map[string][]string
+		Constrained map[string][]string
+	}
+
+	tests := []struct {
+		name string
+		opts remediation.Options
+		want summary
+	}{
+		{
+			name: "all",
+			opts: remediation.Options{UpgradeConfig: upgrade.NewConfig()},
+			want: summary{
+				Patches: map[string][]string{
+					"example.com/foo@v1.1.0->v1.3.0": {"GO-2024-0001", "GO-2024-0002"},
+					"example.com/baz@v1.4.0->v1.5.0": {"GO-2024-0004"},
+				},
+				Remaining: map[string][]string{
+					"example.com/bar@v0.3.0": {"GO-2024-0003"},
+				},
+			},
+		},
+		{
+			name: "direct_only",
+			opts: remediation.Options{MaxDepth: 1, UpgradeConfig: upgrade.NewConfig()},
+			want: summary{
+				Patches: map[string][]string{
+					"example.com/foo@v1.1.0->v1.3.0": {"GO-2024-0001", "GO-2024-0002"},
+				},
+				Remaining: map[string][]string{
+					"example.com/bar@v0.3.0": {"GO-2024-0003"},
+				},
+			},
+		},
+		{
+			name: "patch_upgrades_only",
+			opts: remediation.Options{UpgradeConfig: upgrade.ParseUpgradeConfig([]string{"patch"})},
+			want: summary{
+				Patches: map[string][]string{},
+				Remaining: map[string][]string{
+					"example.com/foo@v1.1.0": {"GO-2024-0001", "GO-2024-0002"},
+					"example.com/bar@v0.3.0": {"GO-2024-0003"},
+					"example.com/baz@v1.4.0": {"GO-2024-0004"},
+				},
+			},
+		},
+		{
+			name: "ignored_vulns",
+			opts: remediation.Options{IgnoreVulns: []string{"GO-2024-0002", "GO-2024-0003"}, UpgradeConfig: upgrade.NewConfig()},
+			want: summary{
+				Patches: map[string][]string{
+					"example.com/foo@v1.1.0->v1.2.0": {"GO-2024-0001"},
+					"example.com/baz@v1.4.0->v1.5.0": {"GO-2024-0004"},
+				},
+				Remaining: map[string][]string{},
+			},
+		},
+		{
+			name: "allowed_versions",
+			opts: remediation.Options{
+				UpgradeConfig: upgrade.NewConfig(),
+				AllowedVersions: map[string]string{
+					"example.com/foo": "<1.3.0",
+					"example.com/baz": "<1.5.0",
+				},
+			},
+			want: summary{
+				Patches: map[string][]string{
+					"example.com/foo@v1.1.0->v1.2.0": {"GO-2024-0001"},
+				},
+				Remaining: map[string][]string{
+					"example.com/bar@v0.3.0": {"GO-2024-0003"},
+				},
+				Constrained: map[string][]string{
+					"example.com/foo@v1.1.0": {"GO-2024-0002"},
+					"example.com/baz@v1.4.0": {"GO-2024-0004"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := remediation.ComputeGoModPatches(t.Context(), &matcher, lister, f, tt.opts)
+			if err != nil {
+				t.Fatalf("ComputeGoModPatches() error = %v", err)
+			}
+
+			got := summary{
+				Patches:   make(map[string][]string),
+				Remaining: make(map[string][]string),
+			}
+			for _, p := range res.Patches {
+				key := p.Module + "@" + p.Version + "->" + p.NewVersion
+				for _, v := range p.Fixed {
+					got.Patches[key] = append(got.Patches[key], v.GetId())
+				}
+			}
+			for _, r := range res.Remaining {
+				key := r.Module + "@" + r.Version
+				for _, v := range r.Vulns {
+					got.Remaining[key] = append(got.Remaining[key], v.GetId())
+				}
+			}
+			for _, r := range res.Constrained {
+				if got.Constrained == nil {
+					got.Constrained = make(map[string][]string)
+				}
+				key := r.Module + "@" + r.Version
+				for _, v := range r.Vulns {
+					got.Constrained[key] = append(got.Constrained[key], v.GetId())
+				}
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ComputeGoModPatches() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
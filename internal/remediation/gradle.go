@@ -0,0 +1,253 @@
+package remediation
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/remediation/minimalupgrade"
+	"github.com/google/osv-scanner/v2/internal/resolution"
+	"github.com/google/osv-scanner/v2/internal/resolution/client"
+	"github.com/google/osv-scanner/v2/internal/utility/gradle"
+	"github.com/google/osv-scanner/v2/internal/utility/vulns"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// GradleRequirement is a version declared in a Gradle version catalog or build script,
+// along with the vulnerabilities affecting the dependencies that use it.
+type GradleRequirement struct {
+	gradle.Declaration
+
+	Vulns []*osvschema.Vulnerability
+}
+
+// GradlePatch bumps a declared version to the minimal version that fixes the vulnerabilities of its dependencies.
+type GradlePatch struct {
+	GradleRequirement
+
+	NewVersion string
+	Fixed      []*osvschema.Vulnerability
+}
+
+// GradleResult is the outcome of remediating a Gradle version catalog or build script.
+type GradleResult struct {
+	Vulnerable []GradleRequirement // the declared versions used by vulnerable dependencies
+	Patches    []GradlePatch       // the version bumps that fix vulnerabilities
+	Remaining  []GradleRequirement // the vulnerabilities that no allowed version fixes
+	// Constrained are the vulnerabilities that only a version outside of the allowed version range of their dependency fixes.
+	// They are not included in Remaining.
+	Constrained []GradleRequirement
+}
+
+// ComputeGradlePatches finds the declared versions that vulnerable dependencies use,
+// and the minimal version of each that fixes as many of their vulnerabilities as possible.
+//
+// A version shared by several dependencies (e.g. a [versions] entry of a catalog referenced by several libraries)
+// is bumped to the highest of the minimal versions of its dependencies, as long as every one of them has it.
+// The dependencies of the declared dependencies are not resolved, only the declared versions are checked.
+func ComputeGradlePatches(ctx context.Context, cl client.ResolutionClient, decls []gradle.Declaration, opts Options) (GradleResult, error) {
+	var pkgs []*extractor.Package
+	for _, v := range decls {
+		for _, name := range v.Dependencies {
+			pkgs = append(pkgs, mavenPackage(name, v.Version))
+		}
+	}
+	if len(pkgs) == 0 {
+		return GradleResult{}, nil
+	}
+
+	matched, err := cl.MatchVulnerabilities(ctx, pkgs)
+	if err != nil {
+		return GradleResult{}, err
+	}
+
+	// All the declared dependencies are direct dependencies.
+	opts.MaxDepth = 0
+
+	var result GradleResult
+	var findings []minimalupgrade.Finding
+	depVulns := make(map[string][]*osvschema.Vulnerability) // the vulns of each dependency, keyed by name@version
+	i := 0
+	for _, v := range decls {
+		req := GradleRequirement{Declaration: v}
+		for _, name := range v.Dependencies {
+			var vs []*osvschema.Vulnerability
+			for _, vuln := range matched[i] {
+				if opts.MatchVuln(resolution.Vulnerability{OSV: vuln}) {
+					vs = append(vs, vuln)
+				}
+			}
+			i++
+			if len(vs) == 0 {
+				continue
+			}
+			depVulns[name+"@"+v.Version] = vs
+			findings = append(findings, minimalupgrade.Finding{
+				Package: imodels.FromInventory(mavenPackage(name, v.Version)),
+				Vulns:   vs,
+			})
+			for _, vuln := range vs {
+				if !vulns.Include(req.Vulns, vuln) {
+					req.Vulns = append(req.Vulns, vuln)
+				}
+			}
+		}
+		if len(req.Vulns) > 0 {
+			result.Vulnerable = append(result.Vulnerable, req)
+		}
+	}
+
+	listed := make(map[string][]string) // the versions of each dependency
+	listVersions := func(ctx context.Context, name string) []string {
+		if vers, ok := listed[name]; ok {
+			return vers
+		}
+		vers, err := mavenVersions(ctx, cl, name)
+		if err != nil {
+			// Treat packages that cannot be listed as having no fix.
+			cmdlogger.Warnf("Failed to list the versions of %s: %v", name, err)
+		}
+		listed[name] = vers
+
+		return vers
+	}
+	allows := func(name, from, to string) bool {
+		_, diff, err := semver.Maven.Difference(from, to)
+		return err == nil && opts.UpgradeConfig.Get(name).Allows(diff) && opts.allowsVersion(semver.Maven, name, to)
+	}
+
+	plan, err := minimalupgrade.Compute(ctx, findings, minimalupgrade.Options{
+		Versions: func(ctx context.Context, pkg imodels.PackageInfo) ([]string, error) {
+			return listVersions(ctx, pkg.Name()), nil
+		},
+		Allows: func(pkg imodels.PackageInfo, version string) bool {
+			return allows(pkg.Name(), pkg.Version(), version)
+		},
+	})
+	if err != nil {
+		return GradleResult{}, err
+	}
+	upgrades := make(map[string]string) // the minimal upgrade of each dependency, keyed by name@version
+	for _, u := range plan.Upgrades {
+		upgrades[u.Package.Name()+"@"+u.Package.Version()] = u.Version
+	}
+
+	for _, req := range result.Vulnerable {
+		newVersion := ""
+		for _, name := range req.Dependencies {
+			if u, ok := upgrades[name+"@"+req.Version]; ok && (newVersion == "" || semver.Maven.Compare(u, newVersion) > 0) {
+				newVersion = u
+			}
+		}
+		patch, ok := gradlePatch(req, newVersion, depVulns, func(name string) bool {
+			return slices.Contains(listVersions(ctx, name), newVersion) && allows(name, req.Version, newVersion)
+		})
+		if !ok {
+			result.Remaining = append(result.Remaining, req)
+			continue
+		}
+		result.Patches = append(result.Patches, patch)
+		if remaining := slices.DeleteFunc(slices.Clone(req.Vulns), func(v *osvschema.Vulnerability) bool { return vulns.Include(patch.Fixed, v) }); len(remaining) > 0 {
+			req.Vulns = remaining
+			result.Remaining = append(result.Remaining, req)
+		}
+	}
+
+	if len(opts.AllowedVersions) > 0 {
+		result.Remaining, result.Constrained = constrainedGradleRequirements(ctx, result.Remaining, depVulns, listVersions, opts)
+	}
+
+	slices.SortStableFunc(result.Patches, func(a, b GradlePatch) int {
+		return cmp.Compare(len(b.Fixed), len(a.Fixed))
+	})
+
+	return result, nil
+}
+
+// gradlePatch bumps the declared version to newVersion, if every dependency using it can be upgraded to it.
+func gradlePatch(req GradleRequirement, newVersion string, depVulns map[string][]*osvschema.Vulnerability, available func(name string) bool) (GradlePatch, bool) {
+	if newVersion == "" {
+		return GradlePatch{}, false
+	}
+
+	patch := GradlePatch{GradleRequirement: req, NewVersion: newVersion}
+	for _, name := range req.Dependencies {
+		if !available(name) {
+			return GradlePatch{}, false
+		}
+		upgraded := imodels.FromInventory(mavenPackage(name, newVersion))
+		for _, v := range depVulns[name+"@"+req.Version] {
+			if !vulns.IsAffected(v, upgraded) && !vulns.Include(patch.Fixed, v) {
+				patch.Fixed = append(patch.Fixed, v)
+			}
+		}
+	}
+
+	return patch, len(patch.Fixed) > 0
+}
+
+// constrainedGradleRequirements splits the vulnerabilities of the remaining requirements into the ones
+// a newer version outside of the allowed version range of the affected dependency fixes, and the rest.
+func constrainedGradleRequirements(ctx context.Context, reqs []GradleRequirement, depVulns map[string][]*osvschema.Vulnerability, listVersions func(context.Context, string) []string, opts Options) ([]GradleRequirement, []GradleRequirement) {
+	var remaining, constrained []GradleRequirement
+	for _, r := range reqs {
+		c, rest := r, r
+		c.Vulns, rest.Vulns = nil, nil
+		for _, v := range r.Vulns {
+			fixable := slices.ContainsFunc(r.Dependencies, func(name string) bool {
+				if !vulns.Include(depVulns[name+"@"+r.Version], v) {
+					return false
+				}
+				versions := slices.DeleteFunc(slices.Clone(listVersions(ctx, name)), func(ver string) bool { return semver.Maven.Compare(ver, r.Version) <= 0 })
+				pkg := func(version string) imodels.PackageInfo { return imodels.FromInventory(mavenPackage(name, version)) }
+
+				return opts.fixedOutsideAllowedVersions(semver.Maven, name, versions, v, pkg)
+			})
+			if fixable {
+				c.Vulns = append(c.Vulns, v)
+			} else {
+				rest.Vulns = append(rest.Vulns, v)
+			}
+		}
+		if len(c.Vulns) > 0 {
+			constrained = append(constrained, c)
+		}
+		if len(rest.Vulns) > 0 {
+			remaining = append(remaining, rest)
+		}
+	}
+
+	return remaining, constrained
+}
+
+// mavenVersions lists the released versions of a Maven package.
+func mavenVersions(ctx context.Context, cl resolve.Client, name string) ([]string, error) {
+	vks, err := cl.Versions(ctx, resolve.PackageKey{System: resolve.Maven, Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, vk := range vks {
+		if vk.VersionType != resolve.Concrete {
+			continue
+		}
+		versions = append(versions, vk.Version)
+	}
+
+	return versions, nil
+}
+
+func mavenPackage(name, version string) *extractor.Package {
+	return &extractor.Package{
+		Name:     name,
+		Version:  version,
+		PURLType: purl.TypeMaven,
+	}
+}
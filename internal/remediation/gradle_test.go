@@ -0,0 +1,158 @@
+package remediation_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/remediation"
+	"github.com/google/osv-scanner/v2/internal/remediation/upgrade"
+	"github.com/google/osv-scanner/v2/internal/resolution/clienttest"
+	"github.com/google/osv-scanner/v2/internal/utility/gradle"
+)
+
+func TestComputeGradlePatches(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile("./testdata/gradle/libs.versions.toml")
+	if err != nil {
+		t.Fatalf("Failed to read libs.versions.toml: %v", err)
+	}
+	decls, err := gradle.ParseVersionCatalog(data)
+	if err != nil {
+		t.Fatalf("Failed to parse libs.versions.toml: %v", err)
+	}
+
+	cl := clienttest.NewMockResolutionClient(t, "./testdata/gradle/universe.yaml", "./testdata/gradle/vulns.json")
+
+	// minimal summary of the result, as name@version: vuln IDs
+	// where name is the name of the version, or the dependency using an inline version
+	type summary struct {
+		Patches     map[string][]string
+		Remaining   map[string][]string
+		Constrained map[string][]string
+	}
+
+	tests := []struct {
+		name string
+		opts remediation.Options
+		want summary
+	}{
+		{
+			name: "all",
+			opts: remediation.Options{UpgradeConfig: upgrade.NewConfig()},
+			want: summary{
+				Patches: map[string][]string{
+					// the shared version is bumped enough to fix both dependencies
+					"jackson@1.0.0->1.2.0":         {"GHSA-0002", "GHSA-0001"},
+					"com.example:foo@1.0.0->2.0.0": {"GHSA-0004"},
+				},
+				Remaining: map[string][]string{
+					// netty-handler does not have the version that fixes netty-codec
+					"netty@2.0.0":           {"GHSA-0003"},
+					"com.example:bar@3.0.0": {"GHSA-0005"},
+				},
+			},
+		},
+		{
+			name: "minor_upgrades_only",
+			opts: remediation.Options{UpgradeConfig: upgrade.ParseUpgradeConfig([]string{"minor"})},
+			want: summary{
+				Patches: map[string][]string{
+					"jackson@1.0.0->1.2.0": {"GHSA-0002", "GHSA-0001"},
+				},
+				Remaining: map[string][]string{
+					"netty@2.0.0":           {"GHSA-0003"},
+					"com.example:foo@1.0.0": {"GHSA-0004"},
+					"com.example:bar@3.0.0": {"GHSA-0005"},
+				},
+			},
+		},
+		{
+			name: "ignored_vulns",
+			opts: remediation.Options{IgnoreVulns: []string{"GHSA-0001"}, UpgradeConfig: upgrade.NewConfig()},
+			want: summary{
+				Patches: map[string][]string{
+					"jackson@1.0.0->1.1.0":         {"GHSA-0002"},
+					"com.example:foo@1.0.0->2.0.0": {"GHSA-0004"},
+				},
+				Remaining: map[string][]string{
+					"netty@2.0.0":           {"GHSA-0003"},
+					"com.example:bar@3.0.0": {"GHSA-0005"},
+				},
+			},
+		},
+		{
+			name: "allowed_versions",
+			opts: remediation.Options{
+				UpgradeConfig: upgrade.NewConfig(),
+				AllowedVersions: map[string]string{
+					"com.example:jackson-databind": "[1.0.0,1.2.0)",
+					"com.example:foo":              "[1.0.0,2.0.0)",
+				},
+			},
+			want: summary{
+				Patches: map[string][]string{
+					// jackson-databind cannot reach the version that fixes it, but jackson-core can still be fixed
+					"jackson@1.0.0->1.1.0": {"GHSA-0002"},
+				},
+				Remaining: map[string][]string{
+					"netty@2.0.0":           {"GHSA-0003"},
+					"com.example:bar@3.0.0": {"GHSA-0005"},
+				},
+				Constrained: map[string][]string{
+					"jackson@1.0.0":         {"GHSA-0001"},
+					"com.example:foo@1.0.0": {"GHSA-0004"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := remediation.ComputeGradlePatches(t.Context(), cl, decls, tt.opts)
+			if err != nil {
+				t.Fatalf("ComputeGradlePatches() error = %v", err)
+			}
+
+			name := func(r remediation.GradleRequirement) string {
+				if r.Name != "" {
+					return r.Name
+				}
+
+				return r.Dependencies[0]
+			}
+			got := summary{
+				Patches:   make(map[string][]string),
+				Remaining: make(map[string][]string),
+			}
+			for _, p := range res.Patches {
+				key := name(p.GradleRequirement) + "@" + p.Version + "->" + p.NewVersion
+				for _, v := range p.Fixed {
+					got.Patches[key] = append(got.Patches[key], v.GetId())
+				}
+			}
+			for _, r := range res.Remaining {
+				key := name(r) + "@" + r.Version
+				for _, v := range r.Vulns {
+					got.Remaining[key] = append(got.Remaining[key], v.GetId())
+				}
+			}
+			for _, r := range res.Constrained {
+				if got.Constrained == nil {
+					got.Constrained = make(map[string][]string)
+				}
+				key := name(r) + "@" + r.Version
+				for _, v := range r.Vulns {
+					got.Constrained[key] = append(got.Constrained[key], v.GetId())
+				}
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ComputeGradlePatches() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
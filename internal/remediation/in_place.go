@@ -148,7 +148,7 @@ func ComputeInPlacePatches(ctx context.Context, cl client.ResolutionClient, grap
 			newVK, err := findFixedVersion(ctx, cl, vk.PackageKey, func(newVK resolve.VersionKey) bool {
 				// Check if this is a disallowed version bump
 				_, diff, err := vk.Semver().Difference(vk.Version, newVK.Version)
-				if err != nil || !opts.UpgradeConfig.Get(vk.Name).Allows(diff) {
+				if err != nil || !opts.UpgradeConfig.Get(vk.Name).Allows(diff) || !opts.allowsVersion(vk.Semver(), vk.Name, newVK.Version) {
 					return false
 				}
 				// Check if dependent packages are still satisfied by new version
@@ -0,0 +1,129 @@
+package remediation
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/v2/internal/resolution"
+	"github.com/google/osv-scanner/v2/internal/resolution/client"
+	"github.com/google/osv-scanner/v2/internal/resolution/manifest"
+	mavenutil "github.com/google/osv-scanner/v2/internal/utility/maven"
+)
+
+// MavenPin is a <dependencyManagement> entry that forces a transitive Maven dependency
+// to a version that fixes its vulnerabilities.
+type MavenPin struct {
+	resolve.PackageKey
+
+	OrigVersion string
+	NewVersion  string
+	Fixed       []resolution.Vulnerability
+
+	// Conflicts are the dependencies that require a version range which excludes NewVersion.
+	// Maven still uses the pinned version, but the dependencies may not work with it.
+	Conflicts []MavenPinConflict
+}
+
+// MavenPinConflict is a dependency requiring a version range that does not contain the pinned version.
+type MavenPinConflict struct {
+	Dependent resolve.VersionKey
+	Range     string
+}
+
+// SuggestMavenPins finds the <dependencyManagement> entries from the override patches that fix the vulnerabilities in vulns
+// that only affect transitive dependencies of res.
+// The pinned versions are checked against the version ranges the dependents of each pinned package require.
+func SuggestMavenPins(ctx context.Context, cl client.DependencyClient, res *resolution.Result, vulns []resolution.Vulnerability, patches []resolution.Difference) ([]MavenPin, error) {
+	direct := make(map[string]bool)
+	for _, e := range res.Graph.Edges {
+		if e.From == 0 {
+			direct[res.Graph.Nodes[e.To].Version.Name] = true
+		}
+	}
+
+	pins := make(map[string]*MavenPin)
+	for _, v := range vulns {
+		// A vulnerable direct dependency is fixed by changing its own version, not by pinning it.
+		if slices.ContainsFunc(v.Subgraphs, func(sg *resolution.DependencySubgraph) bool { return sg.Nodes[0].Distance <= 1 }) {
+			continue
+		}
+		// Use the first (i.e. best) patch that fixes the vuln by pinning only transitive dependencies.
+		idx := slices.IndexFunc(patches, func(p resolution.Difference) bool {
+			return slices.ContainsFunc(p.RemovedVulns, func(rv resolution.Vulnerability) bool { return rv.OSV.GetId() == v.OSV.GetId() }) &&
+				!slices.ContainsFunc(p.Deps, func(dp manifest.DependencyPatch) bool { return direct[dp.Pkg.Name] })
+		})
+		if idx < 0 {
+			continue
+		}
+		for _, dp := range patches[idx].Deps {
+			pin, ok := pins[dp.Pkg.Name]
+			if !ok {
+				pin = &MavenPin{PackageKey: dp.Pkg, OrigVersion: dp.OrigResolved}
+				pins[dp.Pkg.Name] = pin
+			}
+			if pin.NewVersion == "" || semver.Maven.Compare(dp.NewRequire, pin.NewVersion) > 0 {
+				pin.NewVersion = dp.NewRequire
+			}
+			if !slices.ContainsFunc(pin.Fixed, func(f resolution.Vulnerability) bool { return f.OSV.GetId() == v.OSV.GetId() }) {
+				pin.Fixed = append(pin.Fixed, v)
+			}
+		}
+	}
+
+	result := make([]MavenPin, 0, len(pins))
+	for _, pin := range pins {
+		conflicts, err := mavenPinConflicts(ctx, cl, res.Graph, pin.PackageKey, pin.NewVersion)
+		if err != nil {
+			return nil, err
+		}
+		pin.Conflicts = conflicts
+		result = append(result, *pin)
+	}
+	slices.SortFunc(result, func(a, b MavenPin) int { return cmp.Compare(a.Name, b.Name) })
+
+	return result, nil
+}
+
+// mavenPinConflicts returns the dependents of pkg in the graph that require a version range excluding version,
+// according to the requirements reported by the client rather than the ones left in the graph after management.
+func mavenPinConflicts(ctx context.Context, cl client.DependencyClient, g *resolve.Graph, pkg resolve.PackageKey, version string) ([]MavenPinConflict, error) {
+	var conflicts []MavenPinConflict
+	seen := make(map[resolve.NodeID]bool)
+	for _, e := range g.Edges {
+		// The root's requirements are the manifest, which the pin is added to.
+		if e.From == 0 || seen[e.From] || g.Nodes[e.To].Version.PackageKey != pkg {
+			continue
+		}
+		seen[e.From] = true
+
+		dependent := g.Nodes[e.From].Version
+		reqs, err := cl.Requirements(ctx, dependent)
+		if err != nil {
+			return nil, err
+		}
+		for _, req := range reqs {
+			if req.PackageKey != pkg {
+				continue
+			}
+			if origin, _ := req.Type.GetAttr(dep.MavenDependencyOrigin); origin == mavenutil.OriginManagement {
+				continue
+			}
+			// Soft requirements (e.g. "1.0") are only recommendations, Maven only enforces ranges.
+			if !strings.HasPrefix(req.Version, "[") && !strings.HasPrefix(req.Version, "(") {
+				continue
+			}
+			c, err := semver.Maven.ParseConstraint(req.Version)
+			if err != nil || c.Match(version) {
+				continue
+			}
+			conflicts = append(conflicts, MavenPinConflict{Dependent: dependent, Range: req.Version})
+		}
+	}
+
+	return conflicts, nil
+}
@@ -0,0 +1,74 @@
+package remediation_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/remediation"
+	"github.com/google/osv-scanner/v2/internal/remediation/upgrade"
+)
+
+func TestSuggestMavenPins(t *testing.T) {
+	t.Parallel()
+
+	opts := remediation.Options{
+		DevDeps:       true,
+		MaxDepth:      -1,
+		UpgradeConfig: upgrade.NewConfig(),
+	}
+
+	res, cl := parseRemediationFixture(t, "./testdata/maven-pins/universe.yaml", "./testdata/maven-pins/vulns.json", "./testdata/maven-pins/pom.xml", opts.ResolveOpts)
+	res.FilterVulns(opts.MatchVuln)
+	patches, err := remediation.ComputeOverridePatches(t.Context(), cl, res, opts)
+	if err != nil {
+		t.Fatalf("Failed to compute override patches: %v", err)
+	}
+
+	pins, err := remediation.SuggestMavenPins(t.Context(), cl, res, res.Vulns, patches)
+	if err != nil {
+		t.Fatalf("Failed to suggest Maven pins: %v", err)
+	}
+
+	type minimalPin struct {
+		Name        string
+		OrigVersion string
+		NewVersion  string
+		Fixed       []string
+		Conflicts   []string
+	}
+
+	got := make([]minimalPin, len(pins))
+	for i, p := range pins {
+		got[i] = minimalPin{
+			Name:        p.Name,
+			OrigVersion: p.OrigVersion,
+			NewVersion:  p.NewVersion,
+		}
+		for _, v := range p.Fixed {
+			got[i].Fixed = append(got[i].Fixed, v.OSV.GetId())
+		}
+		for _, c := range p.Conflicts {
+			got[i].Conflicts = append(got[i].Conflicts, c.Dependent.Name+"@"+c.Dependent.Version+" requires "+c.Range)
+		}
+	}
+
+	// The vulnerable direct dependency is not pinned.
+	want := []minimalPin{
+		{
+			Name:        "com.example:lib",
+			OrigVersion: "1.0.0",
+			NewVersion:  "1.0.1",
+			Fixed:       []string{"GHSA-0001"},
+		},
+		{
+			Name:        "com.example:strict",
+			OrigVersion: "1.0.1",
+			NewVersion:  "1.1.0",
+			Fixed:       []string{"GHSA-0002"},
+			Conflicts:   []string{"com.example:app@1.0.0 requires [1.0.0,1.1.0)"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SuggestMavenPins() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,214 @@
+// Package minimalupgrade computes the smallest set of version upgrades that fixes
+// as many vulnerabilities as possible, independently of any ecosystem's resolver.
+//
+// Each package is upgraded on its own, without regard to the dependency graph:
+// an upgrade may not be allowed by the requirements of the packages depending
+// on it, may itself require other packages to be upgraded, and cannot be
+// applied directly to a transitive dependency. Callers that need upgrades that
+// hold together should resolve them, as guided remediation does.
+package minimalupgrade
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/google/osv-scalibr/semantic"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/utility/vulns"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// Finding is a package in the dependency graph, along with the vulnerabilities affecting it.
+type Finding struct {
+	Package imodels.PackageInfo
+	Vulns   []*osvschema.Vulnerability
+}
+
+// Upgrade is an upgrade of a package to the minimal version that fixes as many of its vulnerabilities as possible.
+type Upgrade struct {
+	Package   imodels.PackageInfo        // the package, at the version being upgraded from
+	Version   string                     // the version to upgrade to
+	Fixed     []*osvschema.Vulnerability // the vulnerabilities the upgrade fixes
+	Remaining []*osvschema.Vulnerability // the vulnerabilities that still affect the upgraded version
+}
+
+// Plan is the set of upgrades that fixes the most vulnerabilities.
+type Plan struct {
+	Upgrades  []Upgrade // sorted by the number of vulnerabilities they fix
+	Unfixable []Finding // the findings that no allowed version fixes any of the vulnerabilities of
+}
+
+// Options configures which versions packages can be upgraded to.
+type Options struct {
+	// Versions lists the versions a package can be upgraded to, in any order.
+	// If nil, the versions that the vulnerabilities are fixed in are used.
+	Versions func(ctx context.Context, pkg imodels.PackageInfo) ([]string, error)
+	// Allows reports whether a package can be upgraded to a version.
+	// It is expected that if a version is not allowed, no greater version is either.
+	// If nil, all upgrades are allowed.
+	Allows func(pkg imodels.PackageInfo, version string) bool
+}
+
+// Compute finds the minimal upgrade of each vulnerable package that fixes as many of its vulnerabilities as possible.
+// Findings for the same version of a package (e.g. from several lockfiles) share a single upgrade,
+// so that the plan is the smallest set of version bumps that fixes them. Packages are upgraded
+// independently of each other, as explained in the package documentation.
+func Compute(ctx context.Context, findings []Finding, opts Options) (Plan, error) {
+	type key struct {
+		ecosystem string
+		name      string
+		version   string
+	}
+	var keys []key
+	merged := make(map[key]*Finding)
+	for _, f := range findings {
+		if len(f.Vulns) == 0 {
+			continue
+		}
+		k := key{f.Package.Ecosystem().String(), f.Package.Name(), f.Package.Version()}
+		if m, ok := merged[k]; ok {
+			for _, v := range f.Vulns {
+				if !slices.ContainsFunc(m.Vulns, func(mv *osvschema.Vulnerability) bool { return mv.GetId() == v.GetId() }) {
+					m.Vulns = append(m.Vulns, v)
+				}
+			}
+
+			continue
+		}
+		keys = append(keys, k)
+		merged[k] = &Finding{Package: f.Package, Vulns: slices.Clone(f.Vulns)}
+	}
+
+	var plan Plan
+	for _, k := range keys {
+		f := *merged[k]
+		u, ok, err := minimalUpgrade(ctx, f, opts)
+		if err != nil {
+			return Plan{}, err
+		}
+		if !ok {
+			plan.Unfixable = append(plan.Unfixable, f)
+			continue
+		}
+		plan.Upgrades = append(plan.Upgrades, u)
+	}
+
+	slices.SortStableFunc(plan.Upgrades, func(a, b Upgrade) int {
+		if c := cmp.Compare(len(b.Fixed), len(a.Fixed)); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Package.Name(), b.Package.Name())
+	})
+
+	return plan, nil
+}
+
+// minimalUpgrade finds the minimal greater version of the package in f that fixes as many of its vulnerabilities as possible.
+func minimalUpgrade(ctx context.Context, f Finding, opts Options) (Upgrade, bool, error) {
+	eco := f.Package.Ecosystem().String()
+	current, err := semantic.Parse(f.Package.Version(), eco)
+	if err != nil {
+		return Upgrade{}, false, nil //nolint:nilerr // versions that cannot be compared cannot be upgraded
+	}
+
+	var versions []string
+	if opts.Versions != nil {
+		versions, err = opts.Versions(ctx, f.Package)
+		if err != nil {
+			return Upgrade{}, false, err
+		}
+	} else {
+		versions = fixedVersions(f)
+	}
+
+	type candidate struct {
+		version string
+		parsed  semantic.Version
+	}
+	var candidates []candidate
+	for _, v := range versions {
+		if order, err := current.CompareStr(v); err != nil || order >= 0 {
+			continue
+		}
+		if parsed, err := semantic.Parse(v, eco); err == nil {
+			candidates = append(candidates, candidate{v, parsed})
+		}
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		c, _ := a.parsed.CompareStr(b.version)
+		return c
+	})
+
+	best := ""
+	bestCount := len(f.Vulns)
+	for _, c := range candidates {
+		v := c.version
+		if opts.Allows != nil && !opts.Allows(f.Package, v) {
+			break
+		}
+
+		count := 0
+		for _, vuln := range f.Vulns {
+			if vulns.IsAffected(vuln, withVersion(f.Package, v)) {
+				count++
+			}
+		}
+		if count < bestCount {
+			bestCount = count
+			best = v
+			if count == 0 {
+				break
+			}
+		}
+	}
+
+	if best == "" {
+		return Upgrade{}, false, nil
+	}
+
+	u := Upgrade{Package: f.Package, Version: best}
+	upgraded := withVersion(f.Package, best)
+	for _, vuln := range f.Vulns {
+		if vulns.IsAffected(vuln, upgraded) {
+			u.Remaining = append(u.Remaining, vuln)
+		} else {
+			u.Fixed = append(u.Fixed, vuln)
+		}
+	}
+
+	return u, true, nil
+}
+
+// fixedVersions returns the versions that the vulnerabilities in f are fixed in, according to their advisories.
+func fixedVersions(f Finding) []string {
+	var versions []string
+	for _, v := range f.Vulns {
+		for _, affected := range v.GetAffected() {
+			if !vulns.IsSamePackageName(affected.GetPackage().GetName(), f.Package) {
+				continue
+			}
+			for _, r := range affected.GetRanges() {
+				if r.GetType() != osvschema.Range_ECOSYSTEM && r.GetType() != osvschema.Range_SEMVER {
+					continue
+				}
+				for _, e := range r.GetEvents() {
+					if e.GetFixed() != "" && !slices.Contains(versions, e.GetFixed()) {
+						versions = append(versions, e.GetFixed())
+					}
+				}
+			}
+		}
+	}
+
+	return versions
+}
+
+// withVersion returns the package at a different version.
+func withVersion(pkg imodels.PackageInfo, version string) imodels.PackageInfo {
+	inv := *pkg.Package
+	inv.Version = version
+
+	return imodels.FromInventory(&inv)
+}
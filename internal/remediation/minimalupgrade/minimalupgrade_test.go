@@ -0,0 +1,180 @@
+package minimalupgrade_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/remediation/minimalupgrade"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+func npmPackage(name, version string) imodels.PackageInfo {
+	return imodels.FromInventory(&extractor.Package{
+		Name:     name,
+		Version:  version,
+		PURLType: purl.TypeNPM,
+	})
+}
+
+// npmVuln is a vulnerability affecting the npm package name from introduced until fixed.
+func npmVuln(id, name, introduced, fixed string) *osvschema.Vulnerability {
+	events := []*osvschema.Event{{Introduced: introduced}}
+	if fixed != "" {
+		events = append(events, &osvschema.Event{Fixed: fixed})
+	}
+
+	return &osvschema.Vulnerability{
+		Id: id,
+		Affected: []*osvschema.Affected{{
+			Package: &osvschema.Package{Ecosystem: "npm", Name: name},
+			Ranges:  []*osvschema.Range{{Type: osvschema.Range_SEMVER, Events: events}},
+		}},
+	}
+}
+
+func TestCompute(t *testing.T) {
+	t.Parallel()
+
+	vuln1 := npmVuln("VULN-1", "foo", "0", "1.2.0")
+	vuln2 := npmVuln("VULN-2", "foo", "1.0.0", "1.3.0")
+	vuln3 := npmVuln("VULN-3", "foo", "0", "")
+	vuln4 := npmVuln("VULN-4", "bar", "0", "2.0.0")
+	vuln5 := npmVuln("VULN-5", "baz", "0", "")
+
+	findings := []minimalupgrade.Finding{
+		{Package: npmPackage("foo", "1.1.0"), Vulns: []*osvschema.Vulnerability{vuln1, vuln3}},
+		// the same package found in another lockfile
+		{Package: npmPackage("foo", "1.1.0"), Vulns: []*osvschema.Vulnerability{vuln1, vuln2}},
+		{Package: npmPackage("bar", "1.0.0"), Vulns: []*osvschema.Vulnerability{vuln4}},
+		{Package: npmPackage("baz", "1.0.0"), Vulns: []*osvschema.Vulnerability{vuln5}},
+		{Package: npmPackage("qux", "1.0.0")},
+	}
+
+	// minimal summary of an upgrade, as name@from->to: fixed IDs / remaining IDs
+	summarize := func(plan minimalupgrade.Plan) map[string][2][]string {
+		got := make(map[string][2][]string)
+		for _, u := range plan.Upgrades {
+			var s [2][]string
+			for _, v := range u.Fixed {
+				s[0] = append(s[0], v.GetId())
+			}
+			for _, v := range u.Remaining {
+				s[1] = append(s[1], v.GetId())
+			}
+			got[u.Package.Name()+"@"+u.Package.Version()+"->"+u.Version] = s
+		}
+		for _, f := range plan.Unfixable {
+			got[f.Package.Name()+"@"+f.Package.Version()] = [2][]string{}
+		}
+
+		return got
+	}
+
+	tests := []struct {
+		name string
+		opts minimalupgrade.Options
+		want map[string][2][]string
+	}{
+		{
+			name: "fixed_versions",
+			opts: minimalupgrade.Options{},
+			want: map[string][2][]string{
+				"foo@1.1.0->1.3.0": {{"VULN-1", "VULN-2"}, {"VULN-3"}},
+				"bar@1.0.0->2.0.0": {{"VULN-4"}},
+				"baz@1.0.0":        {},
+			},
+		},
+		{
+			name: "listed_versions",
+			opts: minimalupgrade.Options{
+				Versions: func(_ context.Context, pkg imodels.PackageInfo) ([]string, error) {
+					return map[string][]string{
+						"foo": {"1.0.0", "1.4.0", "1.3.1", "1.2.5"},
+						"bar": {"1.0.0", "3.0.0"},
+					}[pkg.Name()], nil
+				},
+			},
+			want: map[string][2][]string{
+				"foo@1.1.0->1.3.1": {{"VULN-1", "VULN-2"}, {"VULN-3"}},
+				"bar@1.0.0->3.0.0": {{"VULN-4"}},
+				"baz@1.0.0":        {},
+			},
+		},
+		{
+			name: "disallowed_major",
+			opts: minimalupgrade.Options{
+				Allows: func(_ imodels.PackageInfo, version string) bool {
+					return version < "2"
+				},
+			},
+			want: map[string][2][]string{
+				"foo@1.1.0->1.3.0": {{"VULN-1", "VULN-2"}, {"VULN-3"}},
+				"bar@1.0.0":        {},
+				"baz@1.0.0":        {},
+			},
+		},
+		{
+			name: "disallowed_minor",
+			opts: minimalupgrade.Options{
+				Allows: func(_ imodels.PackageInfo, version string) bool {
+					return version < "1.3"
+				},
+			},
+			want: map[string][2][]string{
+				"foo@1.1.0->1.2.0": {{"VULN-1"}, {"VULN-3", "VULN-2"}},
+				"bar@1.0.0":        {},
+				"baz@1.0.0":        {},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			plan, err := minimalupgrade.Compute(t.Context(), findings, tt.opts)
+			if err != nil {
+				t.Fatalf("Compute() error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, summarize(plan)); diff != "" {
+				t.Errorf("Compute() mismatch (-want +got):\n%s", diff)
+			}
+			if len(plan.Upgrades) > 1 && len(plan.Upgrades[0].Fixed) < len(plan.Upgrades[1].Fixed) {
+				t.Errorf("Compute() upgrades are not sorted by the number of vulnerabilities they fix")
+			}
+		})
+	}
+}
+
+func TestCompute_PyPINames(t *testing.T) {
+	t.Parallel()
+
+	// the advisory names the package differently from the requirements file
+	vuln := &osvschema.Vulnerability{
+		Id: "VULN-1",
+		Affected: []*osvschema.Affected{{
+			Package: &osvschema.Package{Ecosystem: "PyPI", Name: "Flask_Cors"},
+			Ranges: []*osvschema.Range{{
+				Type:   osvschema.Range_ECOSYSTEM,
+				Events: []*osvschema.Event{{Introduced: "0"}, {Fixed: "4.0.1"}},
+			}},
+		}},
+	}
+	pkg := imodels.FromInventory(&extractor.Package{
+		Name:     "flask-cors",
+		Version:  "3.0.10",
+		PURLType: purl.TypePyPi,
+	})
+
+	plan, err := minimalupgrade.Compute(t.Context(), []minimalupgrade.Finding{{Package: pkg, Vulns: []*osvschema.Vulnerability{vuln}}}, minimalupgrade.Options{})
+	if err != nil {
+		t.Fatalf("Compute() error: %v", err)
+	}
+	if len(plan.Upgrades) != 1 || plan.Upgrades[0].Version != "4.0.1" || len(plan.Upgrades[0].Fixed) != 1 {
+		t.Errorf("Compute() = %+v, want an upgrade of flask-cors to 4.0.1 fixing VULN-1", plan)
+	}
+}
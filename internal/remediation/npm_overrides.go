@@ -0,0 +1,101 @@
+package remediation
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/v2/internal/remediation/upgrade"
+	"github.com/google/osv-scanner/v2/internal/resolution"
+	"github.com/google/osv-scanner/v2/internal/resolution/client"
+	"github.com/google/osv-scanner/v2/internal/resolution/util"
+	"github.com/google/osv-scanner/v2/internal/utility/vulns"
+)
+
+// NpmOverride pins a transitive npm dependency to the minimal version that fixes its vulnerabilities,
+// using the `overrides` (npm) or `resolutions` (yarn) field of package.json.
+type NpmOverride struct {
+	resolve.PackageKey
+
+	// Selector is the key of the override: the package name,
+	// qualified with the original version if more than one version of the package is vulnerable.
+	Selector    string
+	OrigVersion string
+	NewVersion  string
+	Fixed       []resolution.Vulnerability
+}
+
+// SuggestNpmOverrides finds the npm overrides that fix the vulnerabilities in unfixable that only affect transitive dependencies.
+// This is intended for vulnerabilities that cannot be fixed by updating the direct dependencies that depend on them.
+func SuggestNpmOverrides(ctx context.Context, cl client.DependencyClient, unfixable []resolution.Vulnerability, opts Options) ([]NpmOverride, error) {
+	vkVulns := make(map[resolve.VersionKey][]*resolution.Vulnerability)
+	for i, v := range unfixable {
+		// An override cannot change the version of a direct dependency,
+		// so only consider vulns where every affected package is reached transitively.
+		if slices.ContainsFunc(v.Subgraphs, func(sg *resolution.DependencySubgraph) bool { return sg.Nodes[0].Distance <= 1 }) {
+			continue
+		}
+		seenVKs := make(map[resolve.VersionKey]bool)
+		for _, sg := range v.Subgraphs {
+			vk := sg.Nodes[sg.Dependency].Version
+			if !seenVKs[vk] {
+				vkVulns[vk] = append(vkVulns[vk], &unfixable[i])
+				seenVKs[vk] = true
+			}
+		}
+	}
+
+	names := make(map[string]int)
+	for vk := range vkVulns {
+		names[vk.Name]++
+	}
+
+	var overrides []NpmOverride
+	for vk, vulnerabilities := range vkVulns {
+		level := opts.UpgradeConfig.Get(vk.Name)
+		if level == upgrade.None {
+			continue
+		}
+
+		versions, err := getVersionsGreater(ctx, cl, vk)
+		if err != nil {
+			return nil, err
+		}
+		versions = slices.DeleteFunc(slices.Clone(versions), func(ver resolve.Version) bool { return !opts.allowsVersion(vk.Semver(), vk.Name, ver.Version) })
+		// Don't suggest pinning to a pre-release unless it is already on one.
+		if orig, err := vk.Semver().Parse(vk.Version); err == nil && !orig.IsPrerelease() {
+			versions = slices.DeleteFunc(versions, func(ver resolve.Version) bool {
+				v, err := vk.Semver().Parse(ver.Version)
+				return err != nil || v.IsPrerelease()
+			})
+		}
+
+		bestVK, bestCount := minimalFixVersion(vk, versions, vulnerabilities, level)
+		if bestCount == len(vulnerabilities) {
+			continue
+		}
+
+		o := NpmOverride{
+			PackageKey:  vk.PackageKey,
+			Selector:    vk.Name,
+			OrigVersion: vk.Version,
+			NewVersion:  bestVK.Version,
+		}
+		if names[vk.Name] > 1 {
+			o.Selector = vk.Name + "@" + vk.Version
+		}
+		for _, v := range vulnerabilities {
+			if !vulns.IsAffected(v.OSV, util.VKToPackageInfo(bestVK)) {
+				o.Fixed = append(o.Fixed, *v)
+			}
+		}
+		overrides = append(overrides, o)
+	}
+
+	slices.SortFunc(overrides, func(a, b NpmOverride) int {
+		return cmp.Compare(a.Selector, b.Selector)
+	})
+
+	return overrides, nil
+}
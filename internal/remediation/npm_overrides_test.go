@@ -0,0 +1,62 @@
+package remediation_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/remediation"
+	"github.com/google/osv-scanner/v2/internal/remediation/upgrade"
+	"github.com/google/osv-scanner/v2/internal/resolution"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+)
+
+func TestSuggestNpmOverrides(t *testing.T) {
+	t.Parallel()
+
+	opts := remediation.Options{
+		DevDeps:       true,
+		MaxDepth:      -1,
+		UpgradeConfig: upgrade.NewConfig(),
+	}
+
+	res, cl := parseRemediationFixture(t, "./testdata/santatracker/universe.yaml", "./testdata/santatracker/vulns.json", "./testdata/santatracker/package.json", opts.ResolveOpts)
+	res.FilterVulns(opts.MatchVuln)
+	patches, err := remediation.ComputeRelaxPatches(t.Context(), cl, res, opts)
+	if err != nil {
+		t.Fatalf("Failed to compute relaxation patches: %v", err)
+	}
+
+	// Only suggest overrides for the vulns that no relaxation patch fixes.
+	unfixable := slices.DeleteFunc(slices.Clone(res.Vulns), func(v resolution.Vulnerability) bool {
+		return slices.ContainsFunc(patches, func(p resolution.Difference) bool {
+			return slices.ContainsFunc(p.RemovedVulns, func(rv resolution.Vulnerability) bool { return rv.OSV.GetId() == v.OSV.GetId() })
+		})
+	})
+
+	overrides, err := remediation.SuggestNpmOverrides(t.Context(), cl, unfixable, opts)
+	if err != nil {
+		t.Fatalf("Failed to suggest npm overrides: %v", err)
+	}
+
+	type minimalOverride struct {
+		Selector    string
+		OrigVersion string
+		NewVersion  string
+		Fixed       []string
+	}
+
+	got := make([]minimalOverride, len(overrides))
+	for i, o := range overrides {
+		got[i] = minimalOverride{
+			Selector:    o.Selector,
+			OrigVersion: o.OrigVersion,
+			NewVersion:  o.NewVersion,
+		}
+		for _, v := range o.Fixed {
+			got[i].Fixed = append(got[i].Fixed, v.OSV.GetId())
+		}
+		slices.Sort(got[i].Fixed)
+	}
+
+	testutility.NewSnapshot().MatchJSON(t, got)
+}
@@ -165,36 +165,12 @@ func overridePatchVulns(ctx context.Context, cl client.ResolutionClient, result
 				continue
 			}
 
-			bestVK := vk
-			bestCount := len(vulnerabilities) // remaining vulns
 			versions, err := getVersionsGreater(ctx, cl, vk)
 			if err != nil {
 				return nil, nil, err
 			}
-
-			// Find the minimal greater version that fixes as many vulnerabilities as possible.
-			for _, ver := range versions {
-				// Break if we've encountered a disallowed version update.
-				if _, diff, _ := vk.System.Semver().Difference(vk.Version, ver.Version); !opts.UpgradeConfig.Get(vk.Name).Allows(diff) {
-					break
-				}
-
-				// Count the remaining known vulns that affect this version.
-				count := 0 // remaining vulns
-				for _, rv := range vulnerabilities {
-					if vulns.IsAffected(rv.OSV, util.VKToPackageInfo(ver.VersionKey)) {
-						count++
-					}
-				}
-				if count < bestCount {
-					// Found a new candidate.
-					bestCount = count
-					bestVK = ver.VersionKey
-					if bestCount == 0 { // stop if there are 0 vulns remaining
-						break
-					}
-				}
-			}
+			versions = slices.DeleteFunc(slices.Clone(versions), func(ver resolve.Version) bool { return !opts.allowsVersion(vk.Semver(), vk.Name, ver.Version) })
+			bestVK, bestCount := minimalFixVersion(vk, versions, vulnerabilities, opts.UpgradeConfig.Get(vk.Name))
 
 			if bestCount < len(vulnerabilities) {
 				// Found a version that fixes some vulns.
@@ -211,17 +187,28 @@ func overridePatchVulns(ctx context.Context, cl client.ResolutionClient, result
 		}
 
 		// Patch and re-resolve manifest
-		newManif, err := patchManifest(newPatches, result.Manifest)
+		prev := result
+		var err error
+		result, err = resolveOverrides(ctx, cl, prev.Manifest, newPatches, opts)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		result, err = resolution.Resolve(ctx, cl, newManif, opts.ResolveOpts)
-		if err != nil {
-			return nil, nil, err
-		}
+		// Overrides can fail to displace the vulnerable version from the graph
+		// (e.g. if it is pinned somewhere the override does not reach).
+		// Drop them, otherwise the same patches would be attempted forever.
+		if slices.ContainsFunc(newPatches, func(p overridePatch) bool { return resolvesTo(result, p.PackageKey, p.OrigVersion) }) {
+			newPatches = slices.DeleteFunc(newPatches, func(p overridePatch) bool { return resolvesTo(result, p.PackageKey, p.OrigVersion) })
+			if len(newPatches) == 0 {
+				result = prev
+				break
+			}
 
-		result.FilterVulns(opts.MatchVuln)
+			result, err = resolveOverrides(ctx, cl, prev.Manifest, newPatches, opts)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
 
 		// If the patch applies to a package that was already patched before, update the effective patch.
 		for _, p := range newPatches {
@@ -250,6 +237,100 @@ func overridePatchVulns(ctx context.Context, cl client.ResolutionClient, result
 	return result, effectivePatches, nil
 }
 
+// UndisplacedOverrides applies the override patches to the manifest of result and re-resolves it,
+// returning the patches whose original versions are still present in the resolved graph.
+// These are the overrides that would not actually replace the vulnerable versions they target.
+func UndisplacedOverrides(ctx context.Context, cl client.ResolutionClient, result *resolution.Result, patches []manifest.DependencyPatch, opts Options) ([]manifest.DependencyPatch, error) {
+	overrides := toOverridePatches(patches)
+	patched, err := resolveOverrides(ctx, cl, result.Manifest, overrides, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var undisplaced []manifest.DependencyPatch
+	for i, p := range overrides {
+		if resolvesTo(patched, p.PackageKey, p.OrigVersion) {
+			undisplaced = append(undisplaced, patches[i])
+		}
+	}
+
+	return undisplaced, nil
+}
+
+// ApplyOverrides applies the override patches (as computed by ComputeOverridePatches) to the manifest m,
+// returning the result of resolving the patched manifest.
+func ApplyOverrides(ctx context.Context, cl client.ResolutionClient, m manifest.Manifest, patches []manifest.DependencyPatch, opts Options) (*resolution.Result, error) {
+	return resolveOverrides(ctx, cl, m, toOverridePatches(patches), opts)
+}
+
+func toOverridePatches(patches []manifest.DependencyPatch) []overridePatch {
+	overrides := make([]overridePatch, len(patches))
+	for i, p := range patches {
+		overrides[i] = overridePatch{
+			PackageKey:  p.Pkg,
+			OrigVersion: p.OrigResolved,
+			NewVersion:  p.NewRequire,
+		}
+	}
+
+	return overrides
+}
+
+// resolveOverrides applies the overridePatches to the manifest and resolves the patched manifest.
+func resolveOverrides(ctx context.Context, cl client.ResolutionClient, m manifest.Manifest, patches []overridePatch, opts Options) (*resolution.Result, error) {
+	newManif, err := patchManifest(patches, m)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := resolution.Resolve(ctx, cl, newManif, opts.ResolveOpts)
+	if err != nil {
+		return nil, err
+	}
+	result.FilterVulns(opts.MatchVuln)
+
+	return result, nil
+}
+
+// resolvesTo returns whether the resolved graph contains the given version of a package.
+func resolvesTo(result *resolution.Result, pk resolve.PackageKey, version string) bool {
+	return slices.ContainsFunc(result.Graph.Nodes, func(n resolve.Node) bool {
+		return n.Version.PackageKey == pk && n.Version.Version == version
+	})
+}
+
+// minimalFixVersion finds the minimal version in versions (sorted in ascending order) that fixes as many of the vulnerabilities affecting vk as possible,
+// returning it along with the number of vulnerabilities that still affect it.
+// If no allowed version fixes any of the vulnerabilities, vk itself is returned.
+func minimalFixVersion(vk resolve.VersionKey, versions []resolve.Version, vulnerabilities []*resolution.Vulnerability, level upgrade.Level) (resolve.VersionKey, int) {
+	bestVK := vk
+	bestCount := len(vulnerabilities) // remaining vulns
+	for _, ver := range versions {
+		// Break if we've encountered a disallowed version update.
+		if _, diff, _ := vk.System.Semver().Difference(vk.Version, ver.Version); !level.Allows(diff) {
+			break
+		}
+
+		// Count the remaining known vulns that affect this version.
+		count := 0 // remaining vulns
+		for _, rv := range vulnerabilities {
+			if vulns.IsAffected(rv.OSV, util.VKToPackageInfo(ver.VersionKey)) {
+				count++
+			}
+		}
+		if count < bestCount {
+			// Found a new candidate.
+			bestCount = count
+			bestVK = ver.VersionKey
+			if bestCount == 0 { // stop if there are 0 vulns remaining
+				break
+			}
+		}
+	}
+
+	return bestVK, bestCount
+}
+
 // getVersionsGreater gets the known versions of a package that are greater than the given version, sorted in ascending order.
 func getVersionsGreater(ctx context.Context, cl client.DependencyClient, vk resolve.VersionKey) ([]resolve.Version, error) {
 	// Get & sort all the valid versions of this package
@@ -259,13 +340,15 @@ func getVersionsGreater(ctx context.Context, cl client.DependencyClient, vk reso
 		return nil, err
 	}
 	semvers := make(map[resolve.VersionKey]*semver.Version)
-	for _, ver := range versions {
-		parsed, err := semver.Maven.Parse(ver.Version)
-		if err != nil {
-			cmdlogger.Warnf("parsing Maven version %s: %v", parsed, err)
-			continue
+	if vk.System == resolve.Maven {
+		for _, ver := range versions {
+			parsed, err := semver.Maven.Parse(ver.Version)
+			if err != nil {
+				cmdlogger.Warnf("parsing Maven version %s: %v", ver.Version, err)
+				continue
+			}
+			semvers[ver.VersionKey] = parsed
 		}
-		semvers[ver.VersionKey] = parsed
 	}
 
 	cmpFunc := func(a, b resolve.Version) int {
@@ -6,6 +6,7 @@ import (
 	"github.com/google/osv-scanner/v2/internal/remediation"
 	"github.com/google/osv-scanner/v2/internal/remediation/upgrade"
 	"github.com/google/osv-scanner/v2/internal/resolution"
+	"github.com/google/osv-scanner/v2/internal/resolution/manifest"
 )
 
 func TestComputeOverridePatches(t *testing.T) {
@@ -95,3 +96,76 @@ func TestComputeOverridePatches(t *testing.T) {
 		})
 	}
 }
+
+func TestUndisplacedOverrides(t *testing.T) {
+	t.Parallel()
+
+	opts := remediation.Options{
+		DevDeps:       true,
+		MaxDepth:      -1,
+		UpgradeConfig: upgrade.NewConfig(),
+	}
+
+	res, cl := parseRemediationFixture(t, "./testdata/zeppelin-server/universe.yaml", "./testdata/zeppelin-server/vulns.json", "./testdata/zeppelin-server/pom.xml", opts.ResolveOpts)
+	res.FilterVulns(opts.MatchVuln)
+	diffs, err := remediation.ComputeOverridePatches(t.Context(), cl, res, opts)
+	if err != nil {
+		t.Fatalf("Failed to compute override patches: %v", err)
+	}
+	if len(diffs) == 0 {
+		t.Fatalf("Expected override patches to be computed")
+	}
+
+	for _, diff := range diffs {
+		undisplaced, err := remediation.UndisplacedOverrides(t.Context(), cl, res, diff.Deps, opts)
+		if err != nil {
+			t.Fatalf("Failed to validate override patches: %v", err)
+		}
+		if len(undisplaced) != 0 {
+			t.Errorf("UndisplacedOverrides() = %v, want none", undisplaced)
+		}
+	}
+
+	// Overriding a package to the version it already resolves to displaces nothing.
+	noop := diffs[0].Deps[0]
+	noop.NewRequire = noop.OrigResolved
+	noop.NewResolved = noop.OrigResolved
+	undisplaced, err := remediation.UndisplacedOverrides(t.Context(), cl, res, []manifest.DependencyPatch{noop}, opts)
+	if err != nil {
+		t.Fatalf("Failed to validate override patches: %v", err)
+	}
+	if len(undisplaced) != 1 || undisplaced[0].Pkg != noop.Pkg {
+		t.Errorf("UndisplacedOverrides() = %v, want [%v]", undisplaced, noop)
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	t.Parallel()
+
+	opts := remediation.Options{
+		DevDeps:       true,
+		MaxDepth:      -1,
+		UpgradeConfig: upgrade.NewConfig(),
+	}
+
+	res, cl := parseRemediationFixture(t, "./testdata/zeppelin-server/universe.yaml", "./testdata/zeppelin-server/vulns.json", "./testdata/zeppelin-server/pom.xml", opts.ResolveOpts)
+	res.FilterVulns(opts.MatchVuln)
+	diffs, err := remediation.ComputeOverridePatches(t.Context(), cl, res, opts)
+	if err != nil {
+		t.Fatalf("Failed to compute override patches: %v", err)
+	}
+	if len(diffs) == 0 {
+		t.Fatalf("Expected override patches to be computed")
+	}
+
+	// Applying the patches of a difference reproduces its result.
+	for _, diff := range diffs {
+		got, err := remediation.ApplyOverrides(t.Context(), cl, res.Manifest, diff.Deps, opts)
+		if err != nil {
+			t.Fatalf("Failed to apply override patches: %v", err)
+		}
+		if got.Graph.String() != diff.New.Graph.String() {
+			t.Errorf("ApplyOverrides(%v) resolved a different graph than ComputeOverridePatches", diff.Deps)
+		}
+	}
+}
@@ -109,6 +109,10 @@ func tryRelaxRemediate(
 		if err != nil {
 			return nil, err
 		}
+		// The relaxed requirements may resolve to versions outside of the allowed ranges.
+		if !withinAllowedVersions(orig, newRes, opts) {
+			return nil, errRelaxRemediateImpossible
+		}
 		toRelax = reqsToRelax(ctx, cl, newRes, vulnIDs, opts)
 	}
 
@@ -0,0 +1,135 @@
+package relax
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/v2/internal/remediation/upgrade"
+)
+
+type PythonRelaxer struct{}
+
+func (r PythonRelaxer) Relax(ctx context.Context, cl resolve.Client, req resolve.RequirementVersion, config upgrade.Config) (resolve.RequirementVersion, bool) {
+	configLevel := config.Get(req.Name)
+	if configLevel == upgrade.None {
+		return req, false
+	}
+
+	c, err := semver.PyPI.ParseConstraint(req.Version)
+	if err != nil {
+		return req, false
+	}
+
+	// Get all the concrete versions of the package
+	allVKs, err := cl.Versions(ctx, req.PackageKey)
+	if err != nil {
+		return req, false
+	}
+	var vers []*semver.Version
+	for _, vk := range allVKs {
+		if vk.VersionType != resolve.Concrete {
+			continue
+		}
+		v, err := semver.PyPI.Parse(vk.Version)
+		if err != nil {
+			continue
+		}
+		vers = append(vers, v)
+	}
+	slices.SortFunc(vers, func(a, b *semver.Version) int { return a.Compare(b) })
+
+	// Find the versions on either side of the upper boundary of the requirement
+	var lastIdx int   // highest version matching constraint
+	nextIdx := -1     // next version outside of range, preferring non-prerelease
+	nextIsPre := true // if the next version is a prerelease version
+	for lastIdx = len(vers) - 1; lastIdx >= 0; lastIdx-- {
+		v := vers[lastIdx]
+		if c.MatchVersion(v) { // found the upper bound, stop iterating
+			break
+		}
+
+		// Want to prefer non-prerelease versions, so only select one if we haven't seen any non-prerelease versions
+		if !v.IsPrerelease() || nextIsPre {
+			nextIdx = lastIdx
+			nextIsPre = v.IsPrerelease()
+		}
+	}
+
+	// Didn't find any higher versions of the package
+	if nextIdx == -1 {
+		return req, false
+	}
+
+	// No versions match the existing constraint, something is wrong
+	if lastIdx == -1 {
+		return req, false
+	}
+
+	// Our desired relaxation ordering is the same as npm's
+	// 1.2.3 -> 1.2.* -> 1.*.* -> 2.*.* -> 3.*.* -> ...
+	// But using PEP 440 version specifiers e.g.
+	// ==1.2.3 -> ~=1.2.4 -> >=1.3.0,<2.0.0 -> >=2.0.0,<3.0.0 -> ...
+	cmpVer := vers[lastIdx]
+	_, diff := cmpVer.Difference(vers[nextIdx])
+	if !configLevel.Allows(diff) {
+		return req, false
+	}
+	if diff == semver.DiffMajor {
+		// Want to step only one major version at a time
+		// Instead of looking for a difference larger than major,
+		// we want to look for a major version bump from the first next version
+		cmpVer = vers[nextIdx]
+		diff = semver.DiffMinor
+	}
+
+	// Find the highest version with the same difference
+	best := vers[nextIdx]
+	for i := nextIdx + 1; i < len(vers); i++ {
+		_, d := cmpVer.Difference(vers[i])
+		// If we've exceeded our allowed upgrade level, stop looking.
+		if !configLevel.Allows(d) {
+			break
+		}
+
+		// DiffMajor < DiffMinor < DiffPatch < DiffPrerelease
+		// So if d is less than the original diff, it represents a larger change
+		if d < diff {
+			break
+		}
+		if !vers[i].IsPrerelease() || nextIsPre {
+			best = vers[i]
+		}
+	}
+
+	// Pinned versions stay pinned, to the best version.
+	if strings.HasPrefix(req.Version, "==") {
+		req.Version = "==" + best.String()
+		return req, true
+	}
+
+	// Otherwise, use the lowest version of the range as the minimum,
+	// preferring a non-prerelease version.
+	next := vers[nextIdx]
+	for i := nextIdx; i < len(vers); i++ {
+		if _, d := cmpVer.Difference(vers[i]); !configLevel.Allows(d) || d < diff {
+			break
+		}
+		if !vers[i].IsPrerelease() {
+			next = vers[i]
+			break
+		}
+	}
+
+	if diff == semver.DiffPatch {
+		req.Version = "~=" + next.String()
+	} else {
+		major, _ := next.Major()
+		req.Version = fmt.Sprintf(">=%s,<%d.0.0", next, major+1)
+	}
+
+	return req, true
+}
@@ -30,6 +30,8 @@ func GetRelaxer(ecosystem resolve.System) (RequirementRelaxer, error) {
 	switch ecosystem {
 	case resolve.NPM:
 		return NpmRelaxer{}, nil
+	case resolve.PyPI:
+		return PythonRelaxer{}, nil
 	default:
 		return nil, errors.New("unsupported ecosystem")
 	}
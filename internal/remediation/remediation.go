@@ -15,7 +15,7 @@ import (
 
 func SupportsRelax(m manifest.ReadWriter) bool {
 	switch m.(type) {
-	case manifest.NpmReadWriter:
+	case manifest.NpmReadWriter, manifest.PythonRequirementsReadWriter:
 		return true
 	default:
 		return false
@@ -50,7 +50,8 @@ type Options struct {
 	MinSeverity float64 // Minimum vulnerability CVSS score to consider
 	MaxDepth    int     // Maximum depth of dependency to consider vulnerabilities for (e.g. 1 for direct only)
 
-	UpgradeConfig upgrade.Config // Allowed upgrade levels per package.
+	UpgradeConfig   upgrade.Config    // Allowed upgrade levels per package.
+	AllowedVersions map[string]string // Version ranges that packages must stay within when upgraded, keyed by package name.
 }
 
 func (opts Options) MatchVuln(v resolution.Vulnerability) bool {
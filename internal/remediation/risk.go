@@ -0,0 +1,116 @@
+package remediation
+
+import (
+	"strconv"
+	"strings"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"deps.dev/util/semver"
+	mavenutil "github.com/google/osv-scanner/v2/internal/utility/maven"
+)
+
+// RiskLevel is a coarse estimate of how likely an upgrade is to break the project.
+type RiskLevel string
+
+const (
+	RiskLow    RiskLevel = "low"
+	RiskMedium RiskLevel = "medium"
+	RiskHigh   RiskLevel = "high"
+)
+
+// UpgradeRisk describes the signals used to estimate how likely upgrading a package is to break the project.
+type UpgradeRisk struct {
+	MajorVersions       int // the number of major versions the upgrade crosses.
+	ChangedDependencies int // the dependencies that are added, removed, or required at a different version by the new version.
+	Dependents          int // the packages in the dependency graph (other than the root) that require the package.
+}
+
+// Level summarizes the risk: crossing a major version is high risk,
+// and changing the package's own dependencies or a package other dependencies constrain is medium risk.
+func (r UpgradeRisk) Level() RiskLevel {
+	switch {
+	case r.MajorVersions > 0:
+		return RiskHigh
+	case r.ChangedDependencies > 0 || r.Dependents > 0:
+		return RiskMedium
+	default:
+		return RiskLow
+	}
+}
+
+// MajorVersions returns the number of major versions between two versions, or 0 if either cannot be parsed.
+func MajorVersions(sys semver.System, from, to string) int {
+	_, diff, err := sys.Difference(from, to)
+	if err != nil || diff != semver.DiffMajor {
+		return 0
+	}
+	fromMajor, fromOK := majorNumber(from)
+	toMajor, toOK := majorNumber(to)
+	if !fromOK || !toOK || toMajor <= fromMajor {
+		// The versions differ in a major way that isn't numbered e.g. Maven qualifiers.
+		return 1
+	}
+
+	return toMajor - fromMajor
+}
+
+// majorNumber returns the leading number of a version e.g. 2 for "v2.1.0" or "2.0-jre".
+func majorNumber(version string) (int, bool) {
+	version = strings.TrimPrefix(version, "v")
+	end := strings.IndexFunc(version, func(r rune) bool { return r < '0' || r > '9' })
+	if end < 0 {
+		end = len(version)
+	}
+	n, err := strconv.Atoi(version[:end])
+
+	return n, err == nil
+}
+
+// ChangedDependencies counts the dependencies that differ between the requirements of two versions of a package:
+// those only required by one of the versions, and those required at a different version.
+// Dependencies that are only declared for dependency management are ignored.
+func ChangedDependencies(from, to []resolve.RequirementVersion) int {
+	fromReqs := dependencyVersions(from)
+	toReqs := dependencyVersions(to)
+	changed := 0
+	for pk, v := range fromReqs {
+		if toV, ok := toReqs[pk]; !ok || toV != v {
+			changed++
+		}
+	}
+	for pk := range toReqs {
+		if _, ok := fromReqs[pk]; !ok {
+			changed++
+		}
+	}
+
+	return changed
+}
+
+func dependencyVersions(reqs []resolve.RequirementVersion) map[resolve.PackageKey]string {
+	m := make(map[resolve.PackageKey]string, len(reqs))
+	for _, r := range reqs {
+		if origin, _ := r.Type.GetAttr(dep.MavenDependencyOrigin); origin == mavenutil.OriginManagement {
+			continue
+		}
+		m[r.PackageKey] = r.Version
+	}
+
+	return m
+}
+
+// Dependents counts the packages in the graph, other than the root, that require any version of pkg.
+func Dependents(g *resolve.Graph, pkg resolve.PackageKey) int {
+	if g == nil {
+		return 0
+	}
+	seen := make(map[resolve.NodeID]bool)
+	for _, e := range g.Edges {
+		if e.From != 0 && g.Nodes[e.To].Version.PackageKey == pkg {
+			seen[e.From] = true
+		}
+	}
+
+	return len(seen)
+}
@@ -0,0 +1,110 @@
+package remediation_test
+
+import (
+	"testing"
+
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/v2/internal/remediation"
+	"github.com/google/osv-scanner/v2/internal/resolution"
+	mavenutil "github.com/google/osv-scanner/v2/internal/utility/maven"
+)
+
+func TestMajorVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		sys      semver.System
+		from, to string
+		want     int
+	}{
+		{semver.NPM, "1.2.3", "1.4.0", 0},
+		{semver.NPM, "1.2.3", "2.0.0", 1},
+		{semver.NPM, "1.2.3", "4.1.0", 3},
+		{semver.Go, "v0.14.0", "v0.17.0", 0},
+		{semver.Go, "v1.9.0", "v2.0.1", 1},
+		{semver.Maven, "31.0-jre", "32.0.0-jre", 1},
+		{semver.Maven, "2.13.0", "2.13.4", 0},
+		{semver.NPM, "not a version", "2.0.0", 0},
+	}
+	for _, tt := range tests {
+		if got := remediation.MajorVersions(tt.sys, tt.from, tt.to); got != tt.want {
+			t.Errorf("MajorVersions(%v, %q, %q) = %d, want %d", tt.sys, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestChangedDependencies(t *testing.T) {
+	t.Parallel()
+
+	req := func(name, version string) resolve.RequirementVersion {
+		return resolve.RequirementVersion{
+			VersionKey: resolve.VersionKey{
+				PackageKey:  resolve.PackageKey{System: resolve.Maven, Name: name},
+				Version:     version,
+				VersionType: resolve.Requirement,
+			},
+		}
+	}
+	managed := req("com.example:managed", "2.0.0")
+	managed.Type.AddAttr(dep.MavenDependencyOrigin, mavenutil.OriginManagement)
+
+	from := []resolve.RequirementVersion{
+		req("com.example:same", "1.0.0"),
+		req("com.example:bumped", "1.0.0"),
+		req("com.example:removed", "1.0.0"),
+	}
+	to := []resolve.RequirementVersion{
+		req("com.example:same", "1.0.0"),
+		req("com.example:bumped", "1.1.0"),
+		req("com.example:added", "1.0.0"),
+		managed,
+	}
+	if got := remediation.ChangedDependencies(from, to); got != 3 {
+		t.Errorf("ChangedDependencies() = %d, want 3", got)
+	}
+	if got := remediation.ChangedDependencies(from, from); got != 0 {
+		t.Errorf("ChangedDependencies() of the same requirements = %d, want 0", got)
+	}
+}
+
+func TestDependents(t *testing.T) {
+	t.Parallel()
+
+	res, _ := parseRemediationFixture(t, "./testdata/maven-pins/universe.yaml", "./testdata/maven-pins/vulns.json", "./testdata/maven-pins/pom.xml", resolution.ResolveOpts{})
+
+	tests := []struct {
+		name string
+		want int
+	}{
+		// direct dependencies are only required by the root
+		{"com.example:app", 0},
+		{"com.example:strict", 1},
+		{"com.example:unknown", 0},
+	}
+	for _, tt := range tests {
+		if got := remediation.Dependents(res.Graph, resolve.PackageKey{System: resolve.Maven, Name: tt.name}); got != tt.want {
+			t.Errorf("Dependents(%s) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUpgradeRisk_Level(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		risk remediation.UpgradeRisk
+		want remediation.RiskLevel
+	}{
+		{remediation.UpgradeRisk{}, remediation.RiskLow},
+		{remediation.UpgradeRisk{Dependents: 2}, remediation.RiskMedium},
+		{remediation.UpgradeRisk{ChangedDependencies: 1}, remediation.RiskMedium},
+		{remediation.UpgradeRisk{MajorVersions: 1}, remediation.RiskHigh},
+	}
+	for _, tt := range tests {
+		if got := tt.risk.Level(); got != tt.want {
+			t.Errorf("%+v.Level() = %s, want %s", tt.risk, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+module example.com/app
+
+go 1.23
+
+require (
+	example.com/bar v0.3.0
+	example.com/foo v1.1.0
+	example.com/replaced v1.0.0
+	example.com/safe v1.0.0
+)
+
+require example.com/baz v1.4.0 // indirect
+
+replace example.com/replaced => ../replaced
@@ -0,0 +1,16 @@
+package reporter
+
+import (
+	"io"
+
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+type csvReporter struct {
+	writer io.Writer
+}
+
+func (r *csvReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	return output.PrintCSVResults(vulnResult, r.writer)
+}
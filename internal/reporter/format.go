@@ -7,7 +7,7 @@ import (
 	"github.com/google/osv-scanner/v2/pkg/models"
 )
 
-var format = []string{"table", "html", "vertical", "json", "markdown", "sarif", "gh-annotations", "cyclonedx-1-4", "cyclonedx-1-5", "spdx-2-3"}
+var format = []string{"table", "html", "vertical", "json", "markdown", "markdown-summary", "sarif", "gh-annotations", "cyclonedx-1-4", "cyclonedx-1-5", "cyclonedx-1-6", "spdx", "spdx-2-3", "spdx-3-0", "openvex", "csaf", "csv", "junit", "template", "ndjson", "purls"}
 
 func Format() []string {
 	return format
@@ -25,6 +25,8 @@ func newResultPrinter(format string, writer io.Writer, terminalWidth int, showAl
 		return &tableReporter{writer, false, terminalWidth, showAllVulns}, nil
 	case "markdown":
 		return &tableReporter{writer, true, terminalWidth, showAllVulns}, nil
+	case "markdown-summary":
+		return &markdownSummaryReporter{writer, nil}, nil
 	case "sarif":
 		return &sarifReporter{writer}, nil
 	case "gh-annotations":
@@ -35,8 +37,24 @@ func newResultPrinter(format string, writer io.Writer, terminalWidth int, showAl
 		return &cycloneDXReporter{writer, models.CycloneDXVersion15}, nil
 	case "cyclonedx-1-6":
 		return &cycloneDXReporter{writer, models.CycloneDXVersion16}, nil
-	case "spdx-2-3":
-		return &spdxReporter{writer}, nil
+	case "spdx", "spdx-2-3":
+		return &spdxReporter{writer, models.SPDXVersion23}, nil
+	case "spdx-3-0":
+		return &spdxReporter{writer, models.SPDXVersion30}, nil
+	case "openvex":
+		return &openVEXReporter{writer}, nil
+	case "csaf":
+		return &csafReporter{writer}, nil
+	case "csv":
+		return &csvReporter{writer}, nil
+	case "junit":
+		return &junitReporter{writer}, nil
+	case "template":
+		return &templateReporter{writer, ""}, nil
+	case "ndjson":
+		return &ndjsonReporter{writer}, nil
+	case "purls":
+		return &purlsReporter{writer}, nil
 	default:
 		return nil, fmt.Errorf("%v is not a valid format", format)
 	}
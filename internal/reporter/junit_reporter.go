@@ -0,0 +1,16 @@
+package reporter
+
+import (
+	"io"
+
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+type junitReporter struct {
+	writer io.Writer
+}
+
+func (r *junitReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	return output.PrintJUnitResults(vulnResult, r.writer)
+}
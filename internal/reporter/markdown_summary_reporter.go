@@ -0,0 +1,21 @@
+package reporter
+
+import (
+	"io"
+
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+type markdownSummaryReporter struct {
+	writer io.Writer
+	// newResult holds the vulnerabilities introduced since a previous scan,
+	// or nil if the results are not being compared to one
+	newResult *models.VulnerabilityResults
+}
+
+func (r *markdownSummaryReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	output.PrintMarkdownSummaryResults(vulnResult, r.newResult, r.writer)
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package reporter
+
+import (
+	"io"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+type purlsReporter struct {
+	writer io.Writer
+}
+
+func (r *purlsReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	errs := output.PrintPURLResults(vulnResult, r.writer)
+	if errs != nil {
+		for err := range strings.SplitSeq(errs.Error(), "\n") {
+			cmdlogger.Warnf("Failed to parse package URL: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -28,3 +28,34 @@ func PrintResult(
 
 	return r.PrintResult(vulnResult)
 }
+
+// PrintTemplateResult prints the results by rendering them through the Go
+// text/template in templateFile, or a default template if it is empty.
+func PrintTemplateResult(vulnResult *models.VulnerabilityResults, templateFile string, writer io.Writer) error {
+	r := &templateReporter{writer, templateFile}
+
+	return r.PrintResult(vulnResult)
+}
+
+// PrintDiffResult prints the results of comparing a scan against a previous
+// one, where diffResult holds only the vulnerabilities that are new in
+// vulnResult.
+//
+// Formats that can mark findings as new are given all of vulnResult, while the
+// others only print diffResult.
+func PrintDiffResult(
+	vulnResult *models.VulnerabilityResults,
+	diffResult *models.VulnerabilityResults,
+	format string,
+	writer io.Writer,
+	terminalWidth int,
+	showAllVulns bool,
+) error {
+	if format == "markdown-summary" {
+		r := &markdownSummaryReporter{writer, diffResult}
+
+		return r.PrintResult(vulnResult)
+	}
+
+	return PrintResult(diffResult, format, writer, terminalWidth, showAllVulns)
+}
@@ -8,9 +8,14 @@ import (
 )
 
 type spdxReporter struct {
-	writer io.Writer
+	writer  io.Writer
+	version models.SPDXVersion
 }
 
 func (r *spdxReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	if r.version == models.SPDXVersion30 {
+		return output.PrintSPDX30Results(vulnResult, r.writer)
+	}
+
 	return output.PrintSPDXResults(vulnResult, r.writer)
 }
@@ -18,7 +18,7 @@ type tableReporter struct {
 }
 
 func (r *tableReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
-	if len(vulnResult.Results) == 0 && vulnResult.LicenseSummary == nil && !cmdlogger.HasErrored() {
+	if len(vulnResult.Results) == 0 && vulnResult.LicenseSummary == nil && len(vulnResult.ExperimentalDrift) == 0 && !cmdlogger.HasErrored() {
 		fmt.Fprintf(r.writer, "No issues found\n")
 		return nil
 	}
@@ -0,0 +1,30 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+type templateReporter struct {
+	writer io.Writer
+	// templateFile is the path to the template to render, or empty to use the
+	// default template
+	templateFile string
+}
+
+func (r *templateReporter) PrintResult(vulnResult *models.VulnerabilityResults) error {
+	tmpl := ""
+	if r.templateFile != "" {
+		b, err := os.ReadFile(r.templateFile)
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+		tmpl = string(b)
+	}
+
+	return output.PrintTemplateResults(vulnResult, tmpl, r.writer)
+}
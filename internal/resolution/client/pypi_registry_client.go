@@ -0,0 +1,21 @@
+package client
+
+import (
+	"github.com/google/osv-scalibr/clients/resolution"
+)
+
+// PyPIRegistryClient is a DependencyClient fetching package data from the PyPI simple API.
+type PyPIRegistryClient struct {
+	*resolution.PyPIRegistryClient
+}
+
+func NewPyPIRegistryClient() *PyPIRegistryClient {
+	return &PyPIRegistryClient{PyPIRegistryClient: resolution.NewPyPIRegistryClient("", "")}
+}
+
+// TODO: support alternative indexes (e.g. --index-url in requirements.txt)
+func (c *PyPIRegistryClient) AddRegistries(_ []Registry) error { return nil }
+
+// Responses are not cached between runs.
+func (c *PyPIRegistryClient) WriteCache(_ string) error { return nil }
+func (c *PyPIRegistryClient) LoadCache(_ string) error  { return nil }
@@ -100,6 +100,8 @@ func GetReadWriter(pathToManifest string, registry string) (ReadWriter, error) {
 		return NewMavenReadWriter(registry)
 	case "package.json":
 		return NpmReadWriter{}, nil
+	case "requirements.txt":
+		return PythonRequirementsReadWriter{}, nil
 	default:
 		return nil, fmt.Errorf("unsupported manifest type: %s", base)
 	}
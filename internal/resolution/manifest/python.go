@@ -0,0 +1,163 @@
+package manifest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"deps.dev/util/pypi"
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/v2/internal/resolution/depfile"
+)
+
+// PythonRequirementsReadWriter reads and writes pip requirements.txt files.
+//
+// Only requirements on packages from the package index are read.
+// Options (e.g. -r, -e, --index-url) and URL requirements are skipped,
+// and files in hash-checking mode are not supported.
+type PythonRequirementsReadWriter struct{}
+
+func (PythonRequirementsReadWriter) System() resolve.System { return resolve.PyPI }
+
+// requirementsLine is a requirement parsed from a line of a requirements.txt file.
+type requirementsLine struct {
+	dep pypi.Dependency
+	// The byte offsets of the version constraint in the line.
+	// Both are -1 if the line has no constraint.
+	constraintStart, constraintEnd int
+}
+
+// parseRequirementsLine parses a line of a requirements.txt file.
+// Returns false if the line is not a requirement on a package from the index.
+func parseRequirementsLine(line string) (requirementsLine, bool, error) {
+	req := stripRequirementsComment(line)
+	trimmed := strings.TrimSpace(req)
+	if trimmed == "" || strings.HasPrefix(trimmed, "-") {
+		// blank, comment, or an option line
+		return requirementsLine{}, false, nil
+	}
+	if strings.Contains(trimmed, "--hash") {
+		return requirementsLine{}, false, errors.New("requirements files in hash-checking mode are not supported")
+	}
+
+	d, err := pypi.ParseDependency(req)
+	if err != nil {
+		return requirementsLine{}, false, err
+	}
+	if strings.HasPrefix(d.Constraint, "@") || strings.Contains(d.Name, "/") {
+		// URL or local path requirement
+		return requirementsLine{}, false, nil
+	}
+
+	parsed := requirementsLine{dep: d, constraintStart: -1, constraintEnd: -1}
+	if d.Constraint != "" {
+		// The constraint appears verbatim after the name (and extras), before any environment marker.
+		end := len(req)
+		if i := strings.IndexByte(req, ';'); i >= 0 {
+			end = i
+		}
+		if i := strings.LastIndex(req[:end], d.Constraint); i >= 0 {
+			parsed.constraintStart = i
+			parsed.constraintEnd = i + len(d.Constraint)
+		}
+	}
+
+	return parsed, true, nil
+}
+
+// stripRequirementsComment removes the comment from a line of a requirements.txt file.
+// Comments start with a # at the start of the line or after whitespace.
+func stripRequirementsComment(line string) string {
+	for i := range len(line) {
+		if line[i] == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return line[:i]
+		}
+	}
+
+	return line
+}
+
+func (rw PythonRequirementsReadWriter) Read(f depfile.DepFile) (Manifest, error) {
+	manif := newManifest()
+	manif.FilePath = f.Path()
+	// requirements.txt files do not name the project they belong to.
+	manif.Root = resolve.Version{
+		VersionKey: resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				Name:   "rootproject",
+				System: resolve.PyPI,
+			},
+			Version:     "1.0.0",
+			VersionType: resolve.Concrete,
+		},
+	}
+
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	var continued string // the preceding lines ending in a backslash
+	for scanner.Scan() {
+		lineNum++
+		text := scanner.Text()
+		if s, ok := strings.CutSuffix(text, `\`); ok {
+			continued += s
+			continue
+		}
+		text, isContinued := continued+text, continued != ""
+		continued = ""
+
+		line, ok, err := parseRequirementsLine(text)
+		if err == nil && ok && isContinued {
+			err = errors.New("requirements split across lines are not supported")
+		}
+		if err != nil {
+			return Manifest{}, fmt.Errorf("%s:%d: %w", f.Path(), lineNum, err)
+		}
+		if !ok {
+			continue
+		}
+		manif.Requirements = append(manif.Requirements, resolve.RequirementVersion{
+			VersionKey: resolve.VersionKey{
+				PackageKey: resolve.PackageKey{
+					Name:   line.dep.Name,
+					System: resolve.PyPI,
+				},
+				Version:     line.dep.Constraint,
+				VersionType: resolve.Requirement,
+			},
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return Manifest{}, err
+	}
+
+	return manif, nil
+}
+
+func (PythonRequirementsReadWriter) Write(r depfile.DepFile, w io.Writer, patch Patch) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		parsed, ok, err := parseRequirementsLine(strings.TrimRight(line, "\r\n"))
+		if err != nil || !ok || parsed.constraintStart < 0 {
+			sb.WriteString(line)
+			continue
+		}
+		for _, dp := range patch.Deps {
+			if dp.Pkg.Name == parsed.dep.Name && dp.OrigRequire == parsed.dep.Constraint {
+				line = line[:parsed.constraintStart] + dp.NewRequire + line[parsed.constraintEnd:]
+				break
+			}
+		}
+		sb.WriteString(line)
+	}
+
+	_, err = io.WriteString(w, sb.String())
+
+	return err
+}
@@ -0,0 +1,127 @@
+package manifest_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"deps.dev/util/resolve"
+	"github.com/google/osv-scanner/v2/internal/resolution/depfile"
+	"github.com/google/osv-scanner/v2/internal/resolution/manifest"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+)
+
+func pypiVK(t *testing.T, name, version string, versionType resolve.VersionType) resolve.VersionKey {
+	t.Helper()
+	return resolve.VersionKey{
+		PackageKey: resolve.PackageKey{
+			System: resolve.PyPI,
+			Name:   name,
+		},
+		Version:     version,
+		VersionType: versionType,
+	}
+}
+
+func TestPythonRequirementsRead(t *testing.T) {
+	t.Parallel()
+
+	df, err := depfile.OpenLocalDepFile("./testdata/python/requirements.txt")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer df.Close()
+
+	pythonRW := manifest.PythonRequirementsReadWriter{}
+	got, err := pythonRW.Read(df)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if !strings.HasSuffix(got.FilePath, "requirements.txt") {
+		t.Errorf("manifest file path %v does not have requirements.txt", got.FilePath)
+	}
+	got.FilePath = ""
+
+	want := manifest.Manifest{
+		Root: resolve.Version{
+			VersionKey: pypiVK(t, "rootproject", "1.0.0", resolve.Concrete),
+		},
+		// option lines, URL requirements and editable installs are skipped
+		Requirements: []resolve.RequirementVersion{
+			{VersionKey: pypiVK(t, "django", "==4.2.1", resolve.Requirement)},
+			{VersionKey: pypiVK(t, "requests", ">= 2.25.0, < 3", resolve.Requirement)},
+			{VersionKey: pypiVK(t, "flask-cors", "~=3.0.9", resolve.Requirement)},
+			{VersionKey: pypiVK(t, "urllib3", ">=1.26.0", resolve.Requirement)},
+			{VersionKey: pypiVK(t, "pyyaml", "", resolve.Requirement)},
+			{VersionKey: pypiVK(t, "click", ">=7.0", resolve.Requirement)},
+		},
+		Groups: map[manifest.RequirementKey][]string{},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("python manifest mismatch:\ngot %v\nwant %v\n", got, want)
+	}
+}
+
+func TestPythonRequirementsRead_HashCheckingMode(t *testing.T) {
+	t.Parallel()
+
+	df, err := depfile.OpenLocalDepFile("./testdata/python/hashes/requirements.txt")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer df.Close()
+
+	pythonRW := manifest.PythonRequirementsReadWriter{}
+	if _, err := pythonRW.Read(df); err == nil {
+		t.Errorf("expected an error reading a requirements file in hash-checking mode")
+	}
+}
+
+func TestPythonRequirementsWrite(t *testing.T) {
+	t.Parallel()
+
+	df, err := depfile.OpenLocalDepFile("./testdata/python/requirements.txt")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer df.Close()
+
+	changes := manifest.Patch{
+		Deps: []manifest.DependencyPatch{
+			{
+				Pkg:         resolve.PackageKey{System: resolve.PyPI, Name: "django"},
+				OrigRequire: "==4.2.1",
+				NewRequire:  "==4.2.11",
+			},
+			{
+				Pkg:         resolve.PackageKey{System: resolve.PyPI, Name: "requests"},
+				OrigRequire: ">= 2.25.0, < 3",
+				NewRequire:  ">=2.32.0,<3.0.0",
+			},
+			{
+				Pkg:         resolve.PackageKey{System: resolve.PyPI, Name: "urllib3"},
+				OrigRequire: ">=1.26.0",
+				NewRequire:  ">=2.2.2,<3.0.0",
+			},
+			{
+				Pkg:         resolve.PackageKey{System: resolve.PyPI, Name: "click"},
+				OrigRequire: ">=7.0",
+				NewRequire:  ">=8.0.0,<9.0.0",
+			},
+			{
+				// does not match the requirement in the file
+				Pkg:         resolve.PackageKey{System: resolve.PyPI, Name: "flask-cors"},
+				OrigRequire: "~=3.0.8",
+				NewRequire:  "~=3.0.10",
+			},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	pythonRW := manifest.PythonRequirementsReadWriter{}
+	if err := pythonRW.Write(df, buf, changes); err != nil {
+		t.Fatalf("unable to update requirements.txt: %v", err)
+	}
+	testutility.NewSnapshot().WithCRLFReplacement().MatchText(t, buf.String())
+}
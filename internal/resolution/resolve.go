@@ -12,6 +12,7 @@ import (
 	"deps.dev/util/resolve/dep"
 	"deps.dev/util/resolve/maven"
 	"deps.dev/util/resolve/npm"
+	"deps.dev/util/resolve/pypi"
 	"github.com/google/osv-scanner/v2/internal/resolution/client"
 	"github.com/google/osv-scanner/v2/internal/resolution/manifest"
 	mavenutil "github.com/google/osv-scanner/v2/internal/utility/maven"
@@ -69,6 +70,8 @@ func getResolver(sys resolve.System, cl resolve.Client) (resolve.Resolver, error
 		return npm.NewResolver(cl), nil
 	case resolve.Maven:
 		return maven.NewResolver(cl), nil
+	case resolve.PyPI:
+		return pypi.NewResolver(cl), nil
 	default:
 		return nil, fmt.Errorf("no resolver for ecosystem %v", sys)
 	}
@@ -334,6 +337,48 @@ func (res *Result) CalculateDiff(other *Result) Difference {
 	return diff
 }
 
+// GraphChanges summarises how the packages in the resolved dependency graph change between two results.
+type GraphChanges struct {
+	Added   int // packages that are only in the new graph
+	Removed int // packages that are no longer in the graph
+	Changed int // packages that resolve to different versions
+}
+
+// GraphChanges computes the changes to the packages in the dependency graph from Original to New.
+func (a Difference) GraphChanges() GraphChanges {
+	versions := func(g *resolve.Graph) map[resolve.PackageKey][]string {
+		vers := make(map[resolve.PackageKey][]string)
+		for _, n := range g.Nodes[1:] { // skip the root node
+			vers[n.Version.PackageKey] = append(vers[n.Version.PackageKey], n.Version.Version)
+		}
+		for _, v := range vers {
+			slices.Sort(v)
+		}
+
+		return vers
+	}
+	oldVers := versions(a.Original.Graph)
+	newVers := versions(a.New.Graph)
+
+	var changes GraphChanges
+	for pk, vers := range newVers {
+		old, ok := oldVers[pk]
+		switch {
+		case !ok:
+			changes.Added++
+		case !slices.Equal(old, vers):
+			changes.Changed++
+		}
+	}
+	for pk := range oldVers {
+		if _, ok := newVers[pk]; !ok {
+			changes.Removed++
+		}
+	}
+
+	return changes
+}
+
 // Compare compares ResolutionDiffs based on 'effectiveness' (best first):
 //
 // Sort order:
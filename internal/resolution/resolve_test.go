@@ -3,6 +3,7 @@ package resolution_test
 import (
 	"cmp"
 	"slices"
+	"strings"
 	"testing"
 
 	"deps.dev/util/resolve"
@@ -246,3 +247,38 @@ func TestResolve(t *testing.T) {
 		})
 	}
 }
+
+func TestDifferenceGraphChanges(t *testing.T) {
+	t.Parallel()
+
+	graph := func(pkgs ...string) *resolve.Graph {
+		g := &resolve.Graph{}
+		root := g.AddNode(resolve.VersionKey{
+			PackageKey:  resolve.PackageKey{System: resolve.NPM, Name: "root"},
+			Version:     "1.0.0",
+			VersionType: resolve.Concrete,
+		})
+		for _, p := range pkgs {
+			name, version, _ := strings.Cut(p, "@")
+			n := g.AddNode(resolve.VersionKey{
+				PackageKey:  resolve.PackageKey{System: resolve.NPM, Name: name},
+				Version:     version,
+				VersionType: resolve.Concrete,
+			})
+			if err := g.AddEdge(root, n, version, dep.NewType()); err != nil {
+				t.Fatalf("failed to add edge: %v", err)
+			}
+		}
+
+		return g
+	}
+
+	diff := resolution.Difference{
+		Original: &resolution.Result{Graph: graph("unchanged@1.0.0", "upgraded@1.0.0", "removed@1.0.0", "dup@1.0.0", "dup@2.0.0")},
+		New:      &resolution.Result{Graph: graph("unchanged@1.0.0", "upgraded@1.1.0", "added@1.0.0", "dup@2.0.0")},
+	}
+	want := resolution.GraphChanges{Added: 1, Removed: 1, Changed: 2}
+	if got := diff.GraphChanges(); got != want {
+		t.Errorf("GraphChanges() = %+v, want %+v", got, want)
+	}
+}
@@ -15,11 +15,13 @@ import (
 var OSVEcosystem = map[resolve.System]osvconstants.Ecosystem{
 	resolve.NPM:   osvconstants.EcosystemNPM,
 	resolve.Maven: osvconstants.EcosystemMaven,
+	resolve.PyPI:  osvconstants.EcosystemPyPI,
 }
 
 var PURLType = map[resolve.System]string{
 	resolve.NPM:   purl.TypeNPM,
 	resolve.Maven: purl.TypeMaven,
+	resolve.PyPI:  purl.TypePyPi,
 }
 
 func VKToPackageInfo(vk resolve.VersionKey) imodels.PackageInfo {
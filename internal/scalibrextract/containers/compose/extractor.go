@@ -0,0 +1,122 @@
+// Package compose extracts the container images that Compose files run, so
+// that they can be scanned before they are deployed.
+package compose
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	cpb "github.com/google/osv-scalibr/binary/proto/config_go_proto"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/imageref"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// Name is the unique name of this extractor.
+	Name = "containers/compose"
+)
+
+// Extractor extracts the image of each service in a Compose file, as a
+// package whose name is the image reference, with imageref.Metadata.
+type Extractor struct{}
+
+// New returns a new instance of the extractor.
+func New(_ *cpb.PluginConfig) (filesystem.Extractor, error) {
+	return Extractor{}, nil
+}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for Compose files, such as "compose.yaml",
+// "docker-compose.yml" and "docker-compose.override.yml".
+func (e Extractor) FileRequired(api filesystem.FileAPI) bool {
+	base := strings.ToLower(path.Base(api.Path()))
+	ext := path.Ext(base)
+	if ext != ".yaml" && ext != ".yml" {
+		return false
+	}
+
+	return strings.HasPrefix(base, "compose.") || strings.HasPrefix(base, "docker-compose.")
+}
+
+// composeFile is the part of a Compose file that refers to images.
+type composeFile struct {
+	Services map[string]yaml.Node `yaml:"services"`
+}
+
+// service is the part of a Compose service that refers to an image.
+type service struct {
+	Image    string    `yaml:"image"`
+	Platform string    `yaml:"platform"`
+	Build    yaml.Node `yaml:"build"`
+}
+
+// Extract extracts the images of the services in a Compose file.
+//
+// Variables are replaced with their default values, as the environment they
+// are deployed with is not known; the services whose image is built from
+// source, rather than pulled, are left out.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) (inventory.Inventory, error) {
+	var file composeFile
+	if err := yaml.NewDecoder(input.Reader).Decode(&file); err != nil {
+		return inventory.Inventory{}, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	packages := []*extractor.Package{}
+	for name, node := range file.Services {
+		var svc service
+		if err := node.Decode(&svc); err != nil {
+			return inventory.Inventory{}, fmt.Errorf("could not extract service %q from %s: %w", name, input.Path, err)
+		}
+
+		ref := imageref.Expand(svc.Image, nil)
+		if !svc.Build.IsZero() || !imageref.IsResolved(ref) {
+			continue
+		}
+
+		platform := imageref.Expand(svc.Platform, nil)
+		if !imageref.IsResolved(platform) {
+			platform = ""
+		}
+
+		packages = append(packages, &extractor.Package{
+			Name:      ref,
+			PURLType:  purl.TypeDocker,
+			Locations: []string{input.Path},
+			Metadata: &imageref.Metadata{
+				Line:     imageLine(&node),
+				Platform: platform,
+				Workload: name,
+			},
+		})
+	}
+
+	return inventory.Inventory{Packages: packages}, nil
+}
+
+// imageLine returns the line of the image of a service.
+func imageLine(node *yaml.Node) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "image" {
+			return node.Content[i+1].Line
+		}
+	}
+
+	return node.Line
+}
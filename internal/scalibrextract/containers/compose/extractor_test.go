@@ -0,0 +1,91 @@
+package compose_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/simplefileapi"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/compose"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/imageref"
+)
+
+func TestExtractor_FileRequired(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "compose.yaml", want: true},
+		{path: "app/docker-compose.yml", want: true},
+		{path: "docker-compose.override.yml", want: true},
+		{path: "compose.prod.yaml", want: true},
+		{path: "compose.json", want: false},
+		{path: "deployment.yaml", want: false},
+	}
+	for _, tt := range tests {
+		got := compose.Extractor{}.FileRequired(simplefileapi.New(tt.path, nil))
+		if got != tt.want {
+			t.Errorf("FileRequired(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "invalid yaml",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/invalid.yaml",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "could not extract from testdata/invalid.yaml"},
+		},
+		{
+			Name: "services",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/compose.yaml",
+			},
+			WantPackages: []*extractor.Package{
+				{
+					Name:      "postgres:16",
+					PURLType:  purl.TypeDocker,
+					Locations: []string{"testdata/compose.yaml"},
+					Metadata:  &imageref.Metadata{Line: 6, Platform: "linux/arm64", Workload: "db"},
+				},
+				{
+					Name:      "redis:7",
+					PURLType:  purl.TypeDocker,
+					Locations: []string{"testdata/compose.yaml"},
+					Metadata:  &imageref.Metadata{Line: 9, Workload: "cache"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := compose.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(t.Context(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantPackages, got.Packages, cmpopts.SortSlices(extracttest.PackageCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+// Package dockerfile extracts the base images that Dockerfiles build on, so
+// that they can be scanned before the images are built.
+package dockerfile
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	cpb "github.com/google/osv-scalibr/binary/proto/config_go_proto"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/imageref"
+)
+
+const (
+	// Name is the unique name of this extractor.
+	Name = "containers/dockerfile"
+)
+
+// Extractor extracts the base image of each stage of a Dockerfile, as a
+// package whose name is the image reference, with imageref.Metadata.
+type Extractor struct{}
+
+// New returns a new instance of the extractor.
+func New(_ *cpb.PluginConfig) (filesystem.Extractor, error) {
+	return Extractor{}, nil
+}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for files named like a Dockerfile or Containerfile,
+// including those with a prefix or suffix such as "app.Dockerfile" and
+// "Dockerfile.dev".
+func (e Extractor) FileRequired(api filesystem.FileAPI) bool {
+	base := strings.ToLower(path.Base(api.Path()))
+
+	for _, name := range []string{"dockerfile", "containerfile"} {
+		if base == name || strings.HasPrefix(base, name+".") || strings.HasSuffix(base, "."+name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Extract extracts the base images from a Dockerfile.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) (inventory.Inventory, error) {
+	instructions, err := parseInstructions(input.Reader)
+	if err != nil {
+		return inventory.Inventory{}, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	packages := []*extractor.Package{}
+	for _, img := range baseImages(instructions) {
+		packages = append(packages, &extractor.Package{
+			Name:      img.ref,
+			PURLType:  purl.TypeDocker,
+			Locations: []string{input.Path},
+			Metadata: &imageref.Metadata{
+				Line:     img.line,
+				Platform: img.platform,
+				Workload: img.stage,
+			},
+		})
+	}
+
+	return inventory.Inventory{Packages: packages}, nil
+}
+
+// instruction is an instruction of a Dockerfile, with its continuation lines
+// joined together.
+type instruction struct {
+	line    int
+	keyword string
+	args    []string
+}
+
+// parseInstructions splits a Dockerfile into its instructions, skipping
+// comments and blank lines.
+func parseInstructions(r io.Reader) ([]instruction, error) {
+	var instructions []instruction
+
+	scanner := bufio.NewScanner(r)
+	var current strings.Builder
+	start := 0
+	// the delimiter of the heredoc being skipped, if any
+	heredoc := ""
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		// heredocs can contain lines that look like instructions
+		if heredoc != "" {
+			if text == heredoc {
+				heredoc = ""
+			}
+
+			continue
+		}
+
+		// comments can be between continuation lines too
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if current.Len() == 0 {
+			start = line
+		}
+
+		if continued, ok := strings.CutSuffix(text, `\`); ok {
+			current.WriteString(continued)
+			current.WriteString(" ")
+
+			continue
+		}
+
+		current.WriteString(text)
+		instructions = appendInstruction(instructions, start, current.String())
+		if match := heredocPattern.FindStringSubmatch(current.String()); match != nil {
+			heredoc = match[1]
+		}
+		current.Reset()
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// the last instruction can end with a continuation
+	return appendInstruction(instructions, start, current.String()), nil
+}
+
+func appendInstruction(instructions []instruction, line int, text string) []instruction {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return instructions
+	}
+
+	return append(instructions, instruction{
+		line:    line,
+		keyword: strings.ToUpper(fields[0]),
+		args:    fields[1:],
+	})
+}
+
+// baseImage is an image that a stage of a Dockerfile is built on.
+type baseImage struct {
+	ref      string
+	line     int
+	platform string
+	stage    string
+}
+
+// baseImages returns the images that the stages of a Dockerfile are built
+// on, leaving out those built on an earlier stage, on "scratch", or on an
+// image whose reference cannot be known without build arguments.
+func baseImages(instructions []instruction) []baseImage {
+	var images []baseImage
+
+	// only the arguments declared before the first FROM can be used in it
+	args := map[string]string{}
+	stages := map[string]bool{}
+	seenFrom := false
+
+	for _, inst := range instructions {
+		switch inst.keyword {
+		case "ARG":
+			if seenFrom {
+				continue
+			}
+			// arguments without a default are only known when building
+			for _, arg := range inst.args {
+				if name, value, ok := strings.Cut(arg, "="); ok {
+					args[name] = strings.Trim(value, `"'`)
+				}
+			}
+		case "FROM":
+			seenFrom = true
+
+			var platform, ref, stage string
+			for i := 0; i < len(inst.args); i++ {
+				arg := inst.args[i]
+				if value, ok := strings.CutPrefix(arg, "--platform="); ok {
+					platform = imageref.Expand(value, args)
+					continue
+				}
+				if strings.HasPrefix(arg, "--") {
+					continue
+				}
+				if ref == "" {
+					ref = imageref.Expand(arg, args)
+					continue
+				}
+				if strings.EqualFold(arg, "AS") && i+1 < len(inst.args) {
+					stage = strings.ToLower(inst.args[i+1])
+					i++
+				}
+			}
+
+			isStage := stages[strings.ToLower(ref)]
+			if stage != "" {
+				stages[stage] = true
+			}
+
+			if !imageref.IsResolved(ref) || ref == "scratch" || isStage {
+				continue
+			}
+
+			// platforms such as $BUILDPLATFORM are only known when building
+			if !imageref.IsResolved(platform) {
+				platform = ""
+			}
+
+			images = append(images, baseImage{ref: ref, line: inst.line, platform: platform, stage: stage})
+		}
+	}
+
+	return images
+}
+
+var heredocPattern = regexp.MustCompile(`<<-?["']?([A-Za-z_][A-Za-z0-9_]*)["']?`)
@@ -0,0 +1,106 @@
+package dockerfile_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/simplefileapi"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/dockerfile"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/imageref"
+)
+
+func TestExtractor_FileRequired(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "Dockerfile", want: true},
+		{path: "docker/dockerfile", want: true},
+		{path: "Containerfile", want: true},
+		{path: "Dockerfile.dev", want: true},
+		{path: "app.Dockerfile", want: true},
+		{path: "Dockerfile-old", want: false},
+		{path: "dockerfiles/README.md", want: false},
+		{path: ".dockerignore", want: false},
+	}
+	for _, tt := range tests {
+		got := dockerfile.Extractor{}.FileRequired(simplefileapi.New(tt.path, nil))
+		if got != tt.want {
+			t.Errorf("FileRequired(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "empty",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/empty.Dockerfile",
+			},
+			WantPackages: []*extractor.Package{},
+		},
+		{
+			Name: "no FROM instructions",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/no-from.Dockerfile",
+			},
+			WantPackages: []*extractor.Package{},
+		},
+		{
+			Name: "multi-stage",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/Dockerfile",
+			},
+			WantPackages: []*extractor.Package{
+				{
+					Name:      "python:3.12-slim",
+					PURLType:  purl.TypeDocker,
+					Locations: []string{"testdata/Dockerfile"},
+					Metadata:  &imageref.Metadata{Line: 5, Platform: "linux/arm64", Workload: "build"},
+				},
+				{
+					Name:      "golang:1.22",
+					PURLType:  purl.TypeDocker,
+					Locations: []string{"testdata/Dockerfile"},
+					Metadata:  &imageref.Metadata{Line: 8, Workload: "tools"},
+				},
+				{
+					Name:      "gcr.io/distroless/python3-debian12@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+					PURLType:  purl.TypeDocker,
+					Locations: []string{"testdata/Dockerfile"},
+					Metadata:  &imageref.Metadata{Line: 20},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := dockerfile.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(t.Context(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantPackages, got.Packages, cmpopts.SortSlices(extracttest.PackageCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+// Package helm extracts the container images that Helm charts run, by
+// rendering them with the helm command, so that they can be scanned before
+// they are deployed.
+package helm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	cpb "github.com/google/osv-scalibr/binary/proto/config_go_proto"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/imageref"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/kubernetes"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// Name is the unique name of this extractor.
+	Name = "containers/helm"
+
+	// releaseName is the name of the release that charts are rendered as.
+	releaseName = "osv-scanner"
+)
+
+// Config is the configuration of the extractor.
+type Config struct {
+	// ValuesFiles are the values files that charts are rendered with, on top
+	// of the chart's default values.
+	ValuesFiles []string
+}
+
+// Extractor extracts the image of each container in the manifests that a
+// Helm chart renders, as a package whose name is the image reference, with
+// imageref.Metadata.
+//
+// The images of the chart's dependencies, which are vendored in its charts
+// directory, are extracted along with it.
+type Extractor struct {
+	ValuesFiles []string
+}
+
+// New returns a new instance of the extractor.
+func New(_ *cpb.PluginConfig) (filesystem.Extractor, error) {
+	return &Extractor{}, nil
+}
+
+// Name of the extractor.
+func (e *Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e *Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e *Extractor) Requirements() *plugin.Capabilities {
+	// the chart is rendered from its directory on disk
+	return &plugin.Capabilities{DirectFS: true}
+}
+
+// FileRequired returns true for the Chart.yaml file of a chart.
+func (e *Extractor) FileRequired(api filesystem.FileAPI) bool {
+	return path.Base(api.Path()) == "Chart.yaml"
+}
+
+// Extract renders the chart of a Chart.yaml file, and extracts the images of
+// the manifests it renders.
+func (e *Extractor) Extract(ctx context.Context, input *filesystem.ScanInput) (inventory.Inventory, error) {
+	chartDir := filepath.Join(input.Root, filepath.FromSlash(path.Dir(input.Path)))
+
+	// dependencies are rendered as part of the chart that depends on them
+	if isDependency(chartDir) {
+		return inventory.Inventory{}, nil
+	}
+
+	if err := checkDependenciesVendored(chartDir); err != nil {
+		return inventory.Inventory{}, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	rendered, err := e.render(ctx, chartDir)
+	if err != nil {
+		return inventory.Inventory{}, fmt.Errorf("could not render chart %s: %w", input.Path, err)
+	}
+
+	return inventory.Inventory{Packages: renderedImages(rendered, path.Dir(input.Path))}, nil
+}
+
+// render renders the chart with the helm command.
+func (e *Extractor) render(ctx context.Context, chartDir string) ([]byte, error) {
+	if _, err := exec.LookPath("helm"); err != nil {
+		return nil, errors.New("helm could not be found, it needs to be installed to render charts")
+	}
+
+	args := []string{"template", releaseName, chartDir}
+	for _, values := range e.ValuesFiles {
+		args = append(args, "--values", values)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "helm", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("helm template failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// renderedImages returns the images of the containers in the manifests that
+// a chart rendered, located at the template that each manifest was rendered
+// from, within chartPath.
+func renderedImages(rendered []byte, chartPath string) []*extractor.Package {
+	packages := []*extractor.Package{}
+
+	for _, doc := range splitDocuments(rendered) {
+		location := chartPath
+		// templates are named after the chart, which need not be its directory
+		if _, template, ok := strings.Cut(doc.source, "/"); ok {
+			location = path.Join(chartPath, template)
+		}
+
+		for _, img := range kubernetes.Images(strings.NewReader(doc.content)) {
+			packages = append(packages, &extractor.Package{
+				Name:      img.Ref,
+				PURLType:  purl.TypeDocker,
+				Locations: []string{location},
+				// the lines of the rendered manifest are not those of the template
+				Metadata: &imageref.Metadata{Workload: img.Workload},
+			})
+		}
+	}
+
+	return packages
+}
+
+// renderedDocument is a manifest rendered from a template.
+type renderedDocument struct {
+	source  string
+	content string
+}
+
+// splitDocuments splits the output of helm template into its documents,
+// along with the templates they were rendered from, which helm gives in a
+// "# Source:" comment at the start of each document.
+func splitDocuments(rendered []byte) []renderedDocument {
+	var docs []renderedDocument
+	var current renderedDocument
+	var content strings.Builder
+
+	flush := func() {
+		current.content = content.String()
+		if strings.TrimSpace(current.content) != "" {
+			docs = append(docs, current)
+		}
+		current = renderedDocument{}
+		content.Reset()
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(rendered))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimRight(line, " ") == "---" {
+			flush()
+			continue
+		}
+		if source, ok := strings.CutPrefix(line, "# Source: "); ok && current.source == "" {
+			current.source = strings.TrimSpace(source)
+		}
+
+		content.WriteString(line)
+		content.WriteString("\n")
+	}
+	flush()
+
+	return docs
+}
+
+// isDependency reports whether the chart is a dependency vendored in the
+// charts directory of another chart.
+func isDependency(chartDir string) bool {
+	parent := filepath.Dir(chartDir)
+	if filepath.Base(parent) != "charts" {
+		return false
+	}
+
+	_, err := os.Stat(filepath.Join(filepath.Dir(parent), "Chart.yaml"))
+
+	return err == nil
+}
+
+// chartLock is the part of a Chart.lock file that lists the dependencies.
+type chartLock struct {
+	Dependencies []struct {
+		Name    string `yaml:"name"`
+		Version string `yaml:"version"`
+	} `yaml:"dependencies"`
+}
+
+// checkDependenciesVendored checks that the dependencies locked in the chart's
+// Chart.lock are in its charts directory, either as an archive or unpacked, as
+// they cannot be rendered otherwise.
+func checkDependenciesVendored(chartDir string) error {
+	data, err := os.ReadFile(filepath.Join(chartDir, "Chart.lock"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var lock chartLock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return fmt.Errorf("could not parse Chart.lock: %w", err)
+	}
+
+	for _, dep := range lock.Dependencies {
+		archive := filepath.Join(chartDir, "charts", fmt.Sprintf("%s-%s.tgz", dep.Name, dep.Version))
+		dir := filepath.Join(chartDir, "charts", dep.Name)
+
+		if _, err := os.Stat(archive); err == nil {
+			continue
+		}
+		if _, err := os.Stat(dir); err == nil {
+			continue
+		}
+
+		return fmt.Errorf("dependency %s@%s in Chart.lock is not in the charts directory, run \"helm dependency build\" to vendor it", dep.Name, dep.Version)
+	}
+
+	return nil
+}
+
+type configurable interface {
+	Configure(config Config)
+}
+
+// Configure sets the values files that charts are rendered with.
+func (e *Extractor) Configure(config Config) {
+	e.ValuesFiles = config.ValuesFiles
+}
+
+var _ configurable = &Extractor{}
+
+// Configure configures the plugin, if it is this extractor.
+func Configure(plug plugin.Plugin, config Config) {
+	us, ok := plug.(configurable)
+
+	if ok {
+		us.Configure(config)
+	}
+}
@@ -0,0 +1,90 @@
+package helm
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/simplefileapi"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/imageref"
+)
+
+func TestExtractor_FileRequired(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "charts/web/Chart.yaml", want: true},
+		{path: "Chart.yaml", want: true},
+		{path: "charts/web/values.yaml", want: false},
+		{path: "charts/web/Chart.lock", want: false},
+	}
+	for _, tt := range tests {
+		got := (&Extractor{}).FileRequired(simplefileapi.New(tt.path, nil))
+		if got != tt.want {
+			t.Errorf("FileRequired(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func Test_renderedImages(t *testing.T) {
+	t.Parallel()
+
+	rendered, err := os.ReadFile("testdata/rendered.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*extractor.Package{
+		{
+			Name:      "docker.io/bitnami/redis:7.2.0-debian-11-r0",
+			PURLType:  purl.TypeDocker,
+			Locations: []string{"deploy/web/charts/redis/templates/master/application.yaml"},
+			Metadata:  &imageref.Metadata{Workload: "StatefulSet/osv-scanner-redis-master (redis)"},
+		},
+		{
+			Name:      "ghcr.io/org/web:1.4.2",
+			PURLType:  purl.TypeDocker,
+			Locations: []string{"deploy/web/templates/deployment.yaml"},
+			Metadata:  &imageref.Metadata{Workload: "Deployment/osv-scanner-web (web)"},
+		},
+	}
+
+	got := renderedImages(rendered, "deploy/web")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("renderedImages() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_checkDependenciesVendored(t *testing.T) {
+	t.Parallel()
+
+	if err := checkDependenciesVendored("testdata/web"); err != nil {
+		t.Errorf("checkDependenciesVendored() = %v, want no error", err)
+	}
+
+	err := checkDependenciesVendored("testdata/unvendored")
+	if err == nil {
+		t.Fatalf("checkDependenciesVendored() = nil, want an error")
+	}
+
+	want := `dependency redis@18.0.0 in Chart.lock is not in the charts directory, run "helm dependency build" to vendor it`
+	if err.Error() != want {
+		t.Errorf("checkDependenciesVendored() = %q, want %q", err, want)
+	}
+}
+
+func Test_isDependency(t *testing.T) {
+	t.Parallel()
+
+	if isDependency("testdata/web") {
+		t.Errorf("isDependency(%q) = true, want false", "testdata/web")
+	}
+	if !isDependency("testdata/web/charts/redis") {
+		t.Errorf("isDependency(%q) = false, want true", "testdata/web/charts/redis")
+	}
+}
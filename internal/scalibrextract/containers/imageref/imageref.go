@@ -0,0 +1,68 @@
+// Package imageref holds what is shared by the extractors of the container
+// images that files such as Dockerfiles and Kubernetes manifests refer to.
+package imageref
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Metadata is the metadata of a package that is a reference to a container
+// image, which is the package's name.
+//
+// These packages are not matched against vulnerabilities themselves; they are
+// replaced by the packages found in the images.
+type Metadata struct {
+	// Line is the line of the file that the image is referred to on, or 0 if
+	// it is not known, such as for a manifest rendered from a template.
+	Line int
+	// Platform is the platform the image is pulled for, if one was given.
+	Platform string
+	// Workload is what runs the image, such as a Dockerfile stage, a
+	// Kubernetes workload or a Compose service, if it is named.
+	Workload string
+}
+
+var variablePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:?[-+][^}]*)?\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Expand replaces the variables in s with their values, as in a shell,
+// supporting the "${VAR:-default}" and "${VAR:+alternative}" forms; variables
+// without a value are left as they are, so that IsResolved is false.
+func Expand(s string, values map[string]string) string {
+	return variablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		groups := variablePattern.FindStringSubmatch(match)
+		name := groups[1] + groups[3]
+		value, ok := values[name]
+		modifier := groups[2]
+
+		// without a colon, only unset variables are replaced by the default
+		set := ok && (value != "" || !strings.HasPrefix(modifier, ":"))
+		modifier = strings.TrimPrefix(modifier, ":")
+
+		switch {
+		case strings.HasPrefix(modifier, "-"):
+			if !set {
+				return modifier[1:]
+			}
+		case strings.HasPrefix(modifier, "+"):
+			if set {
+				return modifier[1:]
+			}
+
+			return ""
+		}
+
+		if !ok {
+			return match
+		}
+
+		return value
+	})
+}
+
+// IsResolved reports whether the image reference has no variables or template
+// actions left in it, as otherwise it is only known when building or
+// deploying.
+func IsResolved(ref string) bool {
+	return ref != "" && !strings.Contains(ref, "$") && !strings.Contains(ref, "{{")
+}
@@ -0,0 +1,37 @@
+package imageref_test
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/imageref"
+)
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]string{
+		"TAG":      "3.12",
+		"REGISTRY": "ghcr.io/org",
+		"EMPTY":    "",
+	}
+
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{s: "python:$TAG", want: "python:3.12"},
+		{s: "${REGISTRY}/app:${TAG}-slim", want: "ghcr.io/org/app:3.12-slim"},
+		{s: "python:${MISSING:-3.11}", want: "python:3.11"},
+		{s: "python:${EMPTY:-3.11}", want: "python:3.11"},
+		{s: "python:${EMPTY-3.11}", want: "python:"},
+		{s: "python${TAG:+:latest}", want: "python:latest"},
+		{s: "python${MISSING:+:latest}", want: "python"},
+		{s: "$MISSING/app", want: "$MISSING/app"},
+		{s: "alpine", want: "alpine"},
+	}
+	for _, tt := range tests {
+		if got := imageref.Expand(tt.s, values); got != tt.want {
+			t.Errorf("Expand(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
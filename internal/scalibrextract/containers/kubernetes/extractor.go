@@ -0,0 +1,232 @@
+// Package kubernetes extracts the container images that Kubernetes manifests
+// run, so that they can be scanned before they are deployed.
+package kubernetes
+
+import (
+	"context"
+	"io"
+	"path"
+	"strings"
+
+	cpb "github.com/google/osv-scalibr/binary/proto/config_go_proto"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/imageref"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// Name is the unique name of this extractor.
+	Name = "containers/kubernetes"
+)
+
+// containerFields are the fields of a pod spec that hold containers.
+var containerFields = map[string]bool{
+	"containers":          true,
+	"initContainers":      true,
+	"ephemeralContainers": true,
+}
+
+// Extractor extracts the image of each container in a Kubernetes manifest, as
+// a package whose name is the image reference, with imageref.Metadata.
+type Extractor struct{}
+
+// New returns a new instance of the extractor.
+func New(_ *cpb.PluginConfig) (filesystem.Extractor, error) {
+	return Extractor{}, nil
+}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for YAML files, as Kubernetes manifests can be
+// named anything.
+func (e Extractor) FileRequired(api filesystem.FileAPI) bool {
+	ext := strings.ToLower(path.Ext(api.Path()))
+
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// Extract extracts the container images from the objects in a manifest.
+//
+// YAML files that are not Kubernetes manifests, or cannot be parsed as YAML,
+// have no images rather than being an error, as any YAML file is read.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) (inventory.Inventory, error) {
+	packages := []*extractor.Package{}
+
+	for _, img := range Images(input.Reader) {
+		packages = append(packages, &extractor.Package{
+			Name:      img.Ref,
+			PURLType:  purl.TypeDocker,
+			Locations: []string{input.Path},
+			Metadata: &imageref.Metadata{
+				Line:     img.Line,
+				Workload: img.Workload,
+			},
+		})
+	}
+
+	return inventory.Inventory{Packages: packages}, nil
+}
+
+// Image is the image of a container in a Kubernetes manifest.
+type Image struct {
+	Ref  string
+	Line int
+	// Workload names the container, e.g. "Deployment/web (app)".
+	Workload string
+}
+
+// Images returns the images of the containers in the objects of a manifest,
+// up to the first document that cannot be parsed.
+func Images(r io.Reader) []Image {
+	var images []Image
+
+	decoder := yaml.NewDecoder(r)
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			return images
+		}
+
+		for _, obj := range objects(&doc) {
+			for _, img := range containerImages(obj.node) {
+				images = append(images, Image{
+					Ref:      img.ref,
+					Line:     img.line,
+					Workload: workload(obj, img.container),
+				})
+			}
+		}
+	}
+}
+
+// object is a Kubernetes object in a manifest.
+type object struct {
+	kind string
+	name string
+	node *yaml.Node
+}
+
+// objects returns the Kubernetes objects in a YAML document, including the
+// items of a List.
+func objects(doc *yaml.Node) []object {
+	node := doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	kind := scalar(field(node, "kind"))
+	if kind == "" || field(node, "apiVersion") == nil {
+		return nil
+	}
+
+	if items := field(node, "items"); strings.HasSuffix(kind, "List") && items != nil {
+		var objs []object
+		for _, item := range items.Content {
+			objs = append(objs, objects(item)...)
+		}
+
+		return objs
+	}
+
+	return []object{{
+		kind: kind,
+		name: scalar(field(field(node, "metadata"), "name")),
+		node: node,
+	}}
+}
+
+// containerImage is the image of a container.
+type containerImage struct {
+	ref       string
+	line      int
+	container string
+}
+
+// containerImages returns the images of the containers anywhere in the node,
+// which covers pods as well as the pod templates of workloads such as
+// Deployments and CronJobs.
+func containerImages(node *yaml.Node) []containerImage {
+	var images []containerImage
+
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if !containerFields[key.Value] || value.Kind != yaml.SequenceNode {
+				continue
+			}
+
+			for _, container := range value.Content {
+				img := field(container, "image")
+				ref := scalar(img)
+				if !imageref.IsResolved(ref) {
+					continue
+				}
+
+				images = append(images, containerImage{
+					ref:       ref,
+					line:      img.Line,
+					container: scalar(field(container, "name")),
+				})
+			}
+		}
+	}
+
+	for _, child := range node.Content {
+		images = append(images, containerImages(child)...)
+	}
+
+	return images
+}
+
+// workload names the container of the object, e.g. "Deployment/web (app)".
+func workload(obj object, container string) string {
+	name := obj.kind
+	if obj.name != "" {
+		name += "/" + obj.name
+	}
+	if container != "" {
+		name += " (" + container + ")"
+	}
+
+	return name
+}
+
+// field returns the value of the key in a mapping node, if there is one.
+func field(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// scalar returns the value of a scalar node, or "" for any other node.
+func scalar(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+
+	return node.Value
+}
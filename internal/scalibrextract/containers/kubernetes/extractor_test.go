@@ -0,0 +1,116 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/simplefileapi"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/imageref"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/kubernetes"
+)
+
+func TestExtractor_FileRequired(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "deploy/web.yaml", want: true},
+		{path: "pod.YML", want: true},
+		{path: "values.json", want: false},
+		{path: "Dockerfile", want: false},
+	}
+	for _, tt := range tests {
+		got := kubernetes.Extractor{}.FileRequired(simplefileapi.New(tt.path, nil))
+		if got != tt.want {
+			t.Errorf("FileRequired(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "not a kubernetes manifest",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/not-kubernetes.yaml",
+			},
+			WantPackages: []*extractor.Package{},
+		},
+		{
+			Name: "invalid yaml",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/invalid.yaml",
+			},
+			WantPackages: []*extractor.Package{},
+		},
+		{
+			Name: "workloads",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/deployment.yaml",
+			},
+			WantPackages: []*extractor.Package{
+				{
+					Name:      "ghcr.io/org/migrate:1.0",
+					PURLType:  purl.TypeDocker,
+					Locations: []string{"testdata/deployment.yaml"},
+					Metadata:  &imageref.Metadata{Line: 10, Workload: "Deployment/web (migrate)"},
+				},
+				{
+					Name:      "nginx:1.25",
+					PURLType:  purl.TypeDocker,
+					Locations: []string{"testdata/deployment.yaml"},
+					Metadata:  &imageref.Metadata{Line: 13, Workload: "Deployment/web (app)"},
+				},
+				{
+					Name:      "busybox@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+					PURLType:  purl.TypeDocker,
+					Locations: []string{"testdata/deployment.yaml"},
+					Metadata:  &imageref.Metadata{Line: 28, Workload: "CronJob/cleanup (cleanup)"},
+				},
+			},
+		},
+		{
+			Name: "list",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/list.yaml",
+			},
+			WantPackages: []*extractor.Package{
+				{
+					Name:      "alpine:3.20",
+					PURLType:  purl.TypeDocker,
+					Locations: []string{"testdata/list.yaml"},
+					Metadata:  &imageref.Metadata{Line: 11, Workload: "Pod/debug (shell)"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := kubernetes.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(t.Context(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantPackages, got.Packages, cmpopts.SortSlices(extracttest.PackageCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,2 @@
+/* bundled copy, identified separately */
+int zstd_version(void) { return 10505; }
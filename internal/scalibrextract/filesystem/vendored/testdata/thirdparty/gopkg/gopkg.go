@@ -0,0 +1,3 @@
+package gopkg
+
+func Hello() string { return "hello" }
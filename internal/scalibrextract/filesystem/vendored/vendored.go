@@ -12,6 +12,7 @@ import (
 	"io"
 	"io/fs"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -41,6 +42,47 @@ var (
 		"vendored":    {},
 	}
 
+	// knownLibraries are C/C++ libraries that are commonly copied into
+	// projects, which are identified wherever they are found in the tree, not
+	// only in directories for vendored code.
+	knownLibraries = map[string]struct{}{
+		"boringssl":     {},
+		"brotli":        {},
+		"bzip2":         {},
+		"c-ares":        {},
+		"curl":          {},
+		"expat":         {},
+		"freetype":      {},
+		"giflib":        {},
+		"harfbuzz":      {},
+		"libarchive":    {},
+		"libexpat":      {},
+		"libjpeg":       {},
+		"libjpeg-turbo": {},
+		"libpng":        {},
+		"libssh2":       {},
+		"libtiff":       {},
+		"libuv":         {},
+		"libwebp":       {},
+		"libxml2":       {},
+		"libyaml":       {},
+		"lz4":           {},
+		"mbedtls":       {},
+		"nghttp2":       {},
+		"openssl":       {},
+		"pcre":          {},
+		"pcre2":         {},
+		"sqlite":        {},
+		"wolfssl":       {},
+		"xz":            {},
+		"zlib":          {},
+		"zstd":          {},
+	}
+
+	// versionSuffix matches the version that copies of a library are often
+	// suffixed with, e.g. "zlib-1.2.11", "openssl-1.1.1w" or "curl-8_4_0".
+	versionSuffix = regexp.MustCompile(`[-_]v?\d+(?:[._]\d+)*[a-z]?$`)
+
 	fileExts = []string{
 		".hpp",
 		".h",
@@ -94,13 +136,15 @@ func (e *Extractor) Requirements() *plugin.Capabilities {
 	}
 }
 
-// FileRequired returns true for likely directories to contain vendored c/c++ code
+// FileRequired returns true for likely directories to contain vendored c/c++ code:
+// those in a directory for vendored code, and copies of well known libraries
 func (e *Extractor) FileRequired(fapi filesystem.FileAPI) bool {
 	// Check if parent directory is one of the vendoredLibName
 	// Clean first before Dir call to avoid trailing slashes causing problems
-	parentDir := filepath.Base(filepath.Dir(filepath.Clean(fapi.Path())))
-	_, ok := vendoredLibNames[parentDir]
-	if !ok {
+	path := filepath.Clean(fapi.Path())
+	parentDir := filepath.Base(filepath.Dir(path))
+	_, vendored := vendoredLibNames[parentDir]
+	if !vendored && !isKnownLibrary(filepath.Base(path)) {
 		return false
 	}
 
@@ -125,9 +169,14 @@ func (e *Extractor) Extract(ctx context.Context, input *filesystem.ScanInput) (i
 
 	if len(results.GetMatches()) > 0 && results.GetMatches()[0].GetScore() > determineVersionThreshold {
 		match := results.GetMatches()[0]
-		// r.Infof("Identified %s as %s at %s.\n", libPath, match.RepoInfo.Address, match.RepoInfo.Commit)
+		// Named after the repository and tag of the commit, so that the library
+		// can be recognized in the results, and be matched by version where
+		// commits are not indexed
 		packages = append(packages, &extractor.Package{
+			Name:    match.GetRepoInfo().GetAddress(),
+			Version: match.GetRepoInfo().GetTag(),
 			SourceCode: &extractor.SourceCodeIdentifier{
+				Repo:   match.GetRepoInfo().GetAddress(),
 				Commit: match.GetRepoInfo().GetCommit(),
 			},
 			Locations: []string{input.Path},
@@ -139,6 +188,20 @@ func (e *Extractor) Extract(ctx context.Context, input *filesystem.ScanInput) (i
 	}, nil
 }
 
+// isKnownLibrary returns whether a directory is named after a well known
+// C/C++ library, with or without a version.
+func isKnownLibrary(dir string) bool {
+	_, ok := knownLibraries[libraryName(dir)]
+
+	return ok
+}
+
+// libraryName returns the name of the library in a directory, without any
+// version that the directory is suffixed with.
+func libraryName(dir string) string {
+	return strings.ToLower(versionSuffix.ReplaceAllString(dir, ""))
+}
+
 // ToPURL converts an inventory created by this extractor into a PURL.
 func (e *Extractor) ToPURL(_ *extractor.Package) *purl.PackageURL {
 	return nil
@@ -167,6 +230,11 @@ func (e *Extractor) queryDetermineVersions(ctx context.Context, repoDir string,
 				return filepath.SkipDir
 			}
 
+			if p != repoDir && isKnownLibrary(d.Name()) {
+				// Known libraries are identified on their own.
+				return filepath.SkipDir
+			}
+
 			return nil
 		}
 
@@ -199,9 +267,15 @@ func (e *Extractor) queryDetermineVersions(ctx context.Context, repoDir string,
 		return nil, fmt.Errorf("failed during hashing: %w", err)
 	}
 
+	if len(hashes) == 0 {
+		// Directories without any C/C++ code, such as vendored Go or PHP
+		// packages, cannot be identified
+		return &api.VersionMatchList{}, nil
+	}
+
 	result, err := e.OSVClient.ExperimentalDetermineVersion(ctx, &api.DetermineVersionParameters{
 		Query: &api.VersionQuery{
-			Name:       filepath.Base(repoDir),
+			Name:       libraryName(filepath.Base(repoDir)),
 			FileHashes: hashes,
 		},
 	})
@@ -1,9 +1,13 @@
 package vendored_test
 
 import (
+	"io"
 	"io/fs"
+	"net/http"
+	"net/http/httptest"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -14,6 +18,8 @@ import (
 	"github.com/google/osv-scalibr/testing/fakefs"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/filesystem/vendored"
 	"github.com/google/osv-scanner/v2/internal/testutility"
+	"google.golang.org/protobuf/encoding/protojson"
+	"osv.dev/bindings/go/api"
 	"osv.dev/bindings/go/osvdev"
 )
 
@@ -68,6 +74,36 @@ func TestExtractor_FileRequired(t *testing.T) {
 			isDir:        true,
 			wantRequired: true,
 		},
+		{
+			name:         "known library outside of a vendored dir should match",
+			path:         filepath.FromSlash("src/openssl/"),
+			isDir:        true,
+			wantRequired: true,
+		},
+		{
+			name:         "known library with a version should match",
+			path:         filepath.FromSlash("src/zlib-1.2.11"),
+			isDir:        true,
+			wantRequired: true,
+		},
+		{
+			name:         "known library with an underscored version should match",
+			path:         filepath.FromSlash("deps-src/curl-8_4_0/"),
+			isDir:        true,
+			wantRequired: true,
+		},
+		{
+			name:         "file named after a known library should not match",
+			path:         filepath.FromSlash("src/zlib"),
+			isDir:        false,
+			wantRequired: false,
+		},
+		{
+			name:         "dir with a name starting with a known library should not match",
+			path:         filepath.FromSlash("src/zlibextras/"),
+			isDir:        true,
+			wantRequired: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -137,9 +173,115 @@ func TestExtractor_Extract(t *testing.T) {
 				return
 			}
 
-			if diff := cmp.Diff(tt.WantPackages, got.Packages, cmpopts.SortSlices(extracttest.PackageCmpLess)); diff != "" {
+			// The repository and tag the commit is found in are up to the API
+			ignoreRepo := cmpopts.IgnoreFields(extractor.Package{}, "Name", "Version")
+			ignoreRepoAddress := cmpopts.IgnoreFields(extractor.SourceCodeIdentifier{}, "Repo")
+			if diff := cmp.Diff(tt.WantPackages, got.Packages, cmpopts.SortSlices(extracttest.PackageCmpLess), ignoreRepo, ignoreRepoAddress); diff != "" {
 				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
 			}
 		})
 	}
 }
+
+func TestExtractor_Extract_KnownLibrary(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		// TODO: Reenable when #657 is resolved.
+		testutility.Skip(t, "Temporarily disabled until #657 is resolved")
+	}
+	cwd := testutility.GetCurrentWorkingDirectory(t)
+
+	var (
+		mu      sync.Mutex
+		queries []*api.VersionQuery
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != osvdev.DetermineVersionEndpoint {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		query := &api.VersionQuery{}
+		if err := protojson.Unmarshal(body, query); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		queries = append(queries, query)
+		mu.Unlock()
+
+		resp, _ := protojson.Marshal(&api.VersionMatchList{
+			Matches: []*api.VersionMatch{{
+				Score: 0.9,
+				RepoInfo: &api.VersionRepositoryInformation{
+					Type:    api.VersionRepositoryInformation_GIT,
+					Address: "https://github.com/madler/zlib.git",
+					Tag:     "v1.3",
+					Commit:  "09155eaa2f9270dc4ed1fa13e2b4b2613e6e4851",
+				},
+			}},
+		})
+		_, _ = w.Write(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := osvdev.DefaultClient()
+	client.BaseHostURL = srv.URL
+	extr := vendored.Extractor{OSVClient: client}
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "versioned zlib copy",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path:         "testdata/src/zlib-1.3",
+				FakeScanRoot: cwd,
+			},
+			WantPackages: []*extractor.Package{
+				{
+					Name:    "https://github.com/madler/zlib.git",
+					Version: "v1.3",
+					SourceCode: &extractor.SourceCodeIdentifier{
+						Repo:   "https://github.com/madler/zlib.git",
+						Commit: "09155eaa2f9270dc4ed1fa13e2b4b2613e6e4851",
+					},
+					Locations: []string{"testdata/src/zlib-1.3"},
+				},
+			},
+		},
+		{
+			Name: "vendored dir without c/c++ code",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path:         "testdata/thirdparty/gopkg",
+				FakeScanRoot: cwd,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+		got, err := extr.Extract(t.Context(), &scanInput)
+		extracttest.CloseTestScanInput(t, scanInput)
+
+		if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+			t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			continue
+		}
+
+		if diff := cmp.Diff(tt.WantPackages, got.Packages, cmpopts.SortSlices(extracttest.PackageCmpLess)); diff != "" {
+			t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+		}
+	}
+
+	// Only the library with c/c++ code is looked up, by its name without the
+	// version, and without the library bundled inside of it
+	if len(queries) != 1 {
+		t.Fatalf("got %d determineversion queries, want 1", len(queries))
+	}
+	if got := queries[0].GetName(); got != "zlib" {
+		t.Errorf("determineversion query name = %q, want %q", got, "zlib")
+	}
+	if got := len(queries[0].GetFileHashes()); got != 2 {
+		t.Errorf("determineversion query has %d file hashes, want 2", got)
+	}
+}
@@ -0,0 +1,116 @@
+// Package terraformlock extracts the providers locked in a Terraform
+// dependency lock file, as the Go modules they are built from.
+package terraformlock
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	cpb "github.com/google/osv-scalibr/binary/proto/config_go_proto"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+)
+
+const (
+	// Name is the unique name of this extractor.
+	Name = "iac/terraformlock"
+)
+
+// registries are the hosts of the public registries, whose providers are
+// published from GitHub repositories named after them.
+var registries = map[string]bool{
+	"registry.terraform.io": true,
+	"registry.opentofu.org": true,
+}
+
+var (
+	providerPattern = regexp.MustCompile(`^provider\s+"([^"]+)"\s*\{`)
+	versionPattern  = regexp.MustCompile(`^version\s*=\s*"([^"]+)"`)
+)
+
+// Extractor extracts the providers locked in a .terraform.lock.hcl file.
+//
+// Providers from the public registries are extracted as the Go module of
+// their repository, github.com/<namespace>/terraform-provider-<type>, which is
+// how advisories for them are published; those from other registries are
+// extracted by their address, without an ecosystem.
+type Extractor struct{}
+
+// New returns a new instance of the extractor.
+func New(_ *cpb.PluginConfig) (filesystem.Extractor, error) {
+	return Extractor{}, nil
+}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for Terraform (and OpenTofu) dependency lock files.
+func (e Extractor) FileRequired(api filesystem.FileAPI) bool {
+	return path.Base(api.Path()) == ".terraform.lock.hcl"
+}
+
+// Extract extracts the locked providers.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) (inventory.Inventory, error) {
+	packages := []*extractor.Package{}
+
+	scanner := bufio.NewScanner(input.Reader)
+	provider := ""
+	depth := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if depth == 0 {
+			if match := providerPattern.FindStringSubmatch(line); match != nil {
+				provider = match[1]
+			}
+		} else if match := versionPattern.FindStringSubmatch(line); depth == 1 && provider != "" && match != nil {
+			packages = append(packages, toPackage(provider, match[1], input.Path))
+			provider = ""
+		}
+
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth <= 0 {
+			depth = 0
+			provider = ""
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return inventory.Inventory{}, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	return inventory.Inventory{Packages: packages}, nil
+}
+
+// toPackage returns the package of the provider with the given address, such
+// as "registry.terraform.io/hashicorp/aws".
+func toPackage(address, version, location string) *extractor.Package {
+	pkg := &extractor.Package{
+		Name:      address,
+		Version:   version,
+		Locations: []string{location},
+	}
+
+	parts := strings.Split(address, "/")
+	if len(parts) == 3 && registries[strings.ToLower(parts[0])] {
+		pkg.Name = fmt.Sprintf("github.com/%s/terraform-provider-%s", strings.ToLower(parts[1]), strings.ToLower(parts[2]))
+		pkg.PURLType = purl.TypeGolang
+	}
+
+	return pkg
+}
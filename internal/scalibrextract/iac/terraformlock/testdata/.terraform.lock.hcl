@@ -0,0 +1,22 @@
+# This file is maintained automatically by "terraform init".
+# Manual edits may be lost in future updates.
+
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = "~> 5.0"
+  hashes = [
+    "h1:ltxyuBWIy9cq0kIKDJH1jeWJy/y7XJLjS4QrsQK4plA=",
+    "zh:0cdb9c2083bf0902442384f7309367791e4640581652dda456f2d6d7abf0de8d",
+  ]
+}
+
+provider "registry.opentofu.org/integrations/github" {
+  version = "5.42.0"
+  hashes = [
+    "h1:vFGMhXcQ5pVtw/1xXZ8fPBk5N1E4qbzzFPZWtRX6v9M=",
+  ]
+}
+
+provider "terraform.example.com/acme/widgets" {
+  version = "1.0.0"
+}
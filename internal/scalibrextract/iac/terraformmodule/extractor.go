@@ -0,0 +1,178 @@
+// Package terraformmodule extracts the modules that Terraform configurations
+// call, along with the versions that they are pinned to.
+package terraformmodule
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	cpb "github.com/google/osv-scalibr/binary/proto/config_go_proto"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/plugin"
+)
+
+const (
+	// Name is the unique name of this extractor.
+	Name = "iac/terraformmodule"
+)
+
+var (
+	modulePattern    = regexp.MustCompile(`^module\s+"([^"]+)"\s*\{`)
+	attributePattern = regexp.MustCompile(`^(source|version)\s*=\s*"([^"]*)"`)
+	commitPattern    = regexp.MustCompile(`^[0-9a-f]{40}$`)
+	// exact versions, as opposed to constraints such as "~> 5.0"
+	versionPattern = regexp.MustCompile(`^=?\s*v?(\d+\.\d+\.\d+\S*)$`)
+	// registry addresses are "[<host>/]<namespace>/<name>/<provider>"
+	registryPattern = regexp.MustCompile(`^([a-z0-9.-]+\.[a-z]+/)?[\w-]+/[\w-]+/[\w-]+$`)
+)
+
+// Extractor extracts the modules called from .tf files.
+//
+// Modules from git repositories that are pinned to a commit are extracted
+// with it, so that they are checked for vulnerabilities by commit; other
+// modules, such as those from a registry, have no ecosystem in OSV, and
+// are only listed.
+type Extractor struct{}
+
+// New returns a new instance of the extractor.
+func New(_ *cpb.PluginConfig) (filesystem.Extractor, error) {
+	return Extractor{}, nil
+}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for Terraform configuration files.
+func (e Extractor) FileRequired(api filesystem.FileAPI) bool {
+	return path.Ext(api.Path()) == ".tf"
+}
+
+// Extract extracts the modules called from a .tf file.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) (inventory.Inventory, error) {
+	packages := []*extractor.Package{}
+
+	scanner := bufio.NewScanner(input.Reader)
+	inModule := false
+	source, version := "", ""
+	depth := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if depth == 0 && modulePattern.MatchString(line) {
+			inModule = true
+			source, version = "", ""
+		} else if match := attributePattern.FindStringSubmatch(line); inModule && depth == 1 && match != nil {
+			if match[1] == "source" {
+				source = match[2]
+			} else {
+				version = match[2]
+			}
+		}
+
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth <= 0 {
+			if inModule {
+				if pkg := toPackage(source, version, input.Path); pkg != nil {
+					packages = append(packages, pkg)
+				}
+			}
+			depth = 0
+			inModule = false
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return inventory.Inventory{}, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	return inventory.Inventory{Packages: packages}, nil
+}
+
+// toPackage returns the package of the module with the given source and
+// version, or nil if it is a local module or is not pinned to a version.
+func toPackage(source, version, location string) *extractor.Package {
+	if source == "" || strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") {
+		return nil
+	}
+
+	if registryPattern.MatchString(source) {
+		match := versionPattern.FindStringSubmatch(strings.TrimSpace(version))
+		if match == nil {
+			return nil
+		}
+
+		return &extractor.Package{
+			Name:      source,
+			Version:   match[1],
+			Locations: []string{location},
+		}
+	}
+
+	repo, ref, ok := gitSource(source)
+	if !ok || ref == "" {
+		return nil
+	}
+
+	pkg := &extractor.Package{
+		Name:      repo,
+		Version:   ref,
+		Locations: []string{location},
+	}
+	if commitPattern.MatchString(ref) {
+		pkg.SourceCode = &extractor.SourceCodeIdentifier{Repo: repo, Commit: ref}
+	}
+
+	return pkg
+}
+
+// gitSource returns the repository and ref of a module source that is a git
+// repository, such as "git::https://example.com/vpc.git//modules/a?ref=v1.2.0"
+// or "github.com/org/vpc?ref=v1.2.0".
+func gitSource(source string) (string, string, bool) {
+	address, query, _ := strings.Cut(source, "?")
+
+	switch {
+	case strings.HasPrefix(address, "git::"):
+		address = strings.TrimPrefix(address, "git::")
+	case strings.HasPrefix(address, "github.com/"):
+		address = "https://" + address
+	case strings.HasPrefix(address, "git@"):
+	default:
+		return "", "", false
+	}
+
+	// a "//" after the scheme separates the repository from a subdirectory
+	scheme, rest, hasScheme := strings.Cut(address, "://")
+	if !hasScheme {
+		scheme, rest = "", address
+	}
+	rest, _, _ = strings.Cut(rest, "//")
+	if hasScheme {
+		address = scheme + "://" + rest
+	} else {
+		address = rest
+	}
+
+	ref := ""
+	for _, param := range strings.Split(query, "&") {
+		if value, ok := strings.CutPrefix(param, "ref="); ok {
+			ref = value
+		}
+	}
+
+	return address, ref, true
+}
@@ -0,0 +1,95 @@
+package terraformmodule_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/simplefileapi"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/iac/terraformmodule"
+)
+
+func TestExtractor_FileRequired(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "main.tf", want: true},
+		{path: "modules/vpc/variables.tf", want: true},
+		{path: "terraform.tfvars", want: false},
+		{path: "terraform.tfstate", want: false},
+		{path: ".terraform.lock.hcl", want: false},
+	}
+	for _, tt := range tests {
+		got := terraformmodule.Extractor{}.FileRequired(simplefileapi.New(tt.path, nil))
+		if got != tt.want {
+			t.Errorf("FileRequired(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "no modules",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/no-modules.tf",
+			},
+			WantPackages: []*extractor.Package{},
+		},
+		{
+			Name: "modules",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/main.tf",
+			},
+			WantPackages: []*extractor.Package{
+				{
+					Name:      "terraform-aws-modules/vpc/aws",
+					Version:   "5.1.2",
+					Locations: []string{"testdata/main.tf"},
+				},
+				{
+					Name:      "https://example.com/infra/dns.git",
+					Version:   "v1.4.0",
+					Locations: []string{"testdata/main.tf"},
+				},
+				{
+					Name:      "https://github.com/hashicorp/example",
+					Version:   "0123456789abcdef0123456789abcdef01234567",
+					Locations: []string{"testdata/main.tf"},
+					SourceCode: &extractor.SourceCodeIdentifier{
+						Repo:   "https://github.com/hashicorp/example",
+						Commit: "0123456789abcdef0123456789abcdef01234567",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := terraformmodule.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(t.Context(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantPackages, got.Packages, cmpopts.SortSlices(extracttest.PackageCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
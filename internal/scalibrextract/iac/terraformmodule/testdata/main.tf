@@ -0,0 +1,43 @@
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.1.2"
+
+  name = "main"
+  tags = {
+    version = "not-a-module-version"
+  }
+}
+
+module "eks" {
+  source  = "terraform-aws-modules/eks/aws"
+  version = "~> 19.0"
+}
+
+module "network" {
+  source = "./modules/network"
+}
+
+module "dns" {
+  source = "git::https://example.com/infra/dns.git//modules/zone?ref=v1.4.0"
+}
+
+module "consul" {
+  source = "github.com/hashicorp/example?ref=0123456789abcdef0123456789abcdef01234567"
+}
+
+module "unpinned" {
+  source = "git::https://example.com/infra/storage.git"
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
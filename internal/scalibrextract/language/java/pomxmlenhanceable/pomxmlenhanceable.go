@@ -3,11 +3,13 @@ package pomxmlenhanceable
 
 import (
 	"context"
+	"slices"
 
 	cpb "github.com/google/osv-scalibr/binary/proto/config_go_proto"
+	"github.com/google/osv-scalibr/extractor"
 	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/javalockfile"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/java/pomxml"
-	"github.com/google/osv-scalibr/extractor/filesystem/language/java/pomxmlnet"
 	"github.com/google/osv-scalibr/inventory"
 	"github.com/google/osv-scalibr/plugin"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
@@ -20,8 +22,17 @@ const (
 
 // Extractor extracts Maven packages from pom.xml files.
 type Extractor struct {
-	offline filesystem.Extractor
-	online  filesystem.Extractor
+	offline        filesystem.Extractor
+	online         filesystem.Extractor
+	excludedScopes []string
+}
+
+// Config configures the extractor.
+type Config struct {
+	// ExcludedScopes are the scopes (e.g. "test" or "provided") whose
+	// dependencies are left out, along with the dependencies that only they
+	// pull in.
+	ExcludedScopes []string
 }
 
 // New returns a new instance of the extractor.
@@ -53,7 +64,7 @@ func (e *Extractor) FileRequired(api filesystem.FileAPI) bool {
 func (e *Extractor) Extract(ctx context.Context, input *filesystem.ScanInput) (inventory.Inventory, error) {
 	inv, err := e.online.Extract(ctx, input)
 	if err == nil {
-		return inv, nil
+		return e.withoutExcludedScopes(inv), nil
 	}
 
 	if e.online.Name() == e.offline.Name() {
@@ -72,7 +83,30 @@ func (e *Extractor) Extract(ctx context.Context, input *filesystem.ScanInput) (i
 	input.Reader = f
 	defer f.Close()
 
-	return e.offline.Extract(ctx, input)
+	inv, err = e.offline.Extract(ctx, input)
+
+	return e.withoutExcludedScopes(inv), err
+}
+
+// withoutExcludedScopes removes the packages declared with an excluded scope.
+//
+// The resolver already leaves them out of the resolved graph, so this only
+// makes a difference to the dependencies declared directly in the pom.xml
+// that are extracted without resolution.
+func (e *Extractor) withoutExcludedScopes(inv inventory.Inventory) inventory.Inventory {
+	if len(e.excludedScopes) == 0 {
+		return inv
+	}
+
+	inv.Packages = slices.DeleteFunc(inv.Packages, func(pkg *extractor.Package) bool {
+		m, ok := pkg.Metadata.(*javalockfile.Metadata)
+
+		return ok && slices.ContainsFunc(m.DepGroupVals, func(group string) bool {
+			return slices.Contains(e.excludedScopes, group)
+		})
+	})
+
+	return inv
 }
 
 var _ filesystem.Extractor = &Extractor{}
@@ -83,9 +117,14 @@ type enhanceable interface {
 
 // Enhance uses the given config to improve the abilities of this extractor,
 // at the cost of additional requirements such as networking and direct fs access
-func (e *Extractor) Enhance(config *cpb.PluginConfig) (err error) {
-	e.online, err = pomxmlnet.New(config)
-	return
+func (e *Extractor) Enhance(config *cpb.PluginConfig) error {
+	r, err := newResolver(config, e.excludedScopes)
+	if err != nil {
+		return err
+	}
+	e.online = r
+
+	return nil
 }
 
 var _ enhanceable = &Extractor{}
@@ -101,3 +140,26 @@ func EnhanceIfPossible(plug plugin.Plugin, config *cpb.PluginConfig) error {
 
 	return nil
 }
+
+type configurable interface {
+	Configure(config Config)
+}
+
+// Configure sets the scopes whose dependencies are left out.
+func (e *Extractor) Configure(config Config) {
+	e.excludedScopes = config.ExcludedScopes
+	if r, ok := e.online.(*resolver); ok {
+		r.excludedScopes = config.ExcludedScopes
+	}
+}
+
+var _ configurable = &Extractor{}
+
+// Configure configures the plugin, if it is this extractor.
+func Configure(plug plugin.Plugin, config Config) {
+	us, ok := plug.(configurable)
+
+	if ok {
+		us.Configure(config)
+	}
+}
@@ -0,0 +1,521 @@
+package pomxmlenhanceable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"deps.dev/util/maven"
+	"deps.dev/util/resolve"
+	"deps.dev/util/resolve/dep"
+	mavenresolve "deps.dev/util/resolve/maven"
+	cpb "github.com/google/osv-scalibr/binary/proto/config_go_proto"
+	"github.com/google/osv-scalibr/clients/datasource"
+	"github.com/google/osv-scalibr/clients/resolution"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/javalockfile"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/pomxmlnet"
+	scalibrfs "github.com/google/osv-scalibr/fs"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	mavenutil "github.com/google/osv-scanner/v2/internal/utility/maven"
+)
+
+// resolver extracts Maven packages from pom.xml files along with their
+// transitive dependencies.
+//
+// It resolves dependencies like the pomxmlnet extractor, but keeps the edges of
+// the resolved graph so that the dependencies only pulled in through an
+// excluded scope can be left out as a whole, and resolves the other modules of
+// a multi-module build from their pom.xml files rather than from a registry.
+type resolver struct {
+	depClient      resolve.Client
+	mavenClient    *datasource.MavenRegistryAPIClient
+	excludedScopes []string
+}
+
+// newResolver creates a resolver with the clients pomxmlnet would use for config.
+func newResolver(config *cpb.PluginConfig, excludedScopes []string) (*resolver, error) {
+	plug, err := pomxmlnet.New(config)
+	if err != nil {
+		return nil, err
+	}
+	net := plug.(*pomxmlnet.Extractor)
+
+	return &resolver{
+		depClient:      net.DepClient,
+		mavenClient:    net.MavenClient,
+		excludedScopes: excludedScopes,
+	}, nil
+}
+
+// Name of the extractor.
+func (r *resolver) Name() string { return pomxmlnet.Name }
+
+// Version of the extractor.
+func (r *resolver) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (r *resolver) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{
+		Network: plugin.NetworkOnline,
+	}
+}
+
+// FileRequired returns true if the specified file is a pom.xml file.
+func (r *resolver) FileRequired(api filesystem.FileAPI) bool {
+	return path.Base(api.Path()) == "pom.xml"
+}
+
+// Extract resolves the dependencies of the pom.xml file passed through the scan input.
+func (r *resolver) Extract(ctx context.Context, input *filesystem.ScanInput) (inventory.Inventory, error) {
+	// Registries declared by a project only apply to that project.
+	client := r.mavenClient.WithoutRegistries()
+
+	project, err := loadProject(ctx, client, input.FS, input.Path, input.Reader, true)
+	if err != nil {
+		return inventory.Inventory{}, err
+	}
+
+	if registries := client.GetRegistries(); len(registries) > 0 {
+		clientRegs := make([]resolution.Registry, len(registries))
+		for i, reg := range registries {
+			clientRegs[i] = reg
+		}
+		if cl, ok := r.depClient.(resolution.ClientWithRegistries); ok {
+			if err := cl.AddRegistries(ctx, clientRegs); err != nil {
+				return inventory.Inventory{}, err
+			}
+		}
+	}
+
+	overrideClient := resolution.NewOverrideClient(r.depClient)
+	modules, err := addModules(ctx, client, overrideClient, input.FS, buildModules(input.FS, input.Path), project.Dependencies)
+	if err != nil {
+		return inventory.Inventory{}, err
+	}
+
+	root := resolve.Version{
+		VersionKey: resolve.VersionKey{
+			PackageKey: resolve.PackageKey{
+				System: resolve.Maven,
+				Name:   project.ProjectKey.Name(),
+			},
+			VersionType: resolve.Concrete,
+			Version:     string(project.Version),
+		},
+	}
+	reqs := requirements(project.Dependencies, "")
+	reqs = append(reqs, requirements(project.DependencyManagement.Dependencies, mavenutil.OriginManagement)...)
+	overrideClient.AddVersion(root, reqs)
+
+	g, err := mavenresolve.NewResolver(overrideClient).Resolve(ctx, root.VersionKey)
+	if err != nil {
+		return inventory.Inventory{}, fmt.Errorf("failed resolving %v: %w", root, err)
+	}
+	if len(g.Nodes) <= 1 && g.Error != "" {
+		// Multi-registry errors may be appended to the resolved graph, so only
+		// fail when nothing could be resolved.
+		return inventory.Inventory{}, fmt.Errorf("failed resolving %v: %s", root, g.Error)
+	}
+
+	included := r.includedNodes(g)
+
+	details := make(map[string]*extractor.Package)
+	for i := 1; i < len(g.Nodes); i++ {
+		node := g.Nodes[i]
+		if !included[resolve.NodeID(i)] {
+			continue
+		}
+		if _, ok := modules[node.Version.Name]; ok {
+			// Modules are built from source rather than pulled from a
+			// registry, so only their dependencies are reported.
+			continue
+		}
+
+		// Scopes are only known for the dependencies declared in the pom.xml,
+		// as the nodes of the resolved graph do not carry them.
+		depGroups := []string{}
+		isDirect := false
+		for _, d := range project.Dependencies {
+			if d.Name() != node.Version.Name {
+				continue
+			}
+			isDirect = true
+			if d.Scope != "" && d.Scope != "compile" {
+				depGroups = append(depGroups, string(d.Scope))
+			}
+
+			break
+		}
+
+		groupID, artifactID, _ := strings.Cut(node.Version.Name, ":")
+		details[node.Version.Name] = &extractor.Package{
+			Name:     node.Version.Name,
+			Version:  node.Version.Version,
+			PURLType: purl.TypeMaven,
+			Metadata: &javalockfile.Metadata{
+				ArtifactID:   artifactID,
+				GroupID:      groupID,
+				DepGroupVals: depGroups,
+				IsTransitive: !isDirect,
+			},
+			Locations: []string{input.Path},
+		}
+	}
+
+	return inventory.Inventory{Packages: slices.Collect(maps.Values(details))}, nil
+}
+
+// pomFile is the part of a pom.xml that identifies the modules of a
+// multi-module build.
+type pomFile struct {
+	maven.Project
+
+	Modules []string `xml:"modules>module"`
+}
+
+// readPOMFile reads the pom.xml at pomPath of fsys.
+func readPOMFile(fsys scalibrfs.FS, pomPath string) (pomFile, error) {
+	f, err := fsys.Open(pomPath)
+	if err != nil {
+		return pomFile{}, err
+	}
+	defer f.Close()
+
+	var pom pomFile
+	if err := datasource.NewMavenDecoder(f).Decode(&pom); err != nil {
+		return pomFile{}, fmt.Errorf("failed to unmarshal project: %w", err)
+	}
+
+	return pom, nil
+}
+
+// buildModules returns the paths of the pom.xml files of the other modules
+// of the multi-module build that the pom.xml at pomPath is part of, keyed by
+// their "groupId:artifactId".
+//
+// The build is made of the modules listed by the pom.xml and its local
+// parents, along with the modules those modules list in turn.
+func buildModules(fsys scalibrfs.FS, pomPath string) map[string]string {
+	modules := make(map[string]string)
+	seen := map[string]bool{pomPath: true}
+
+	var addModules func(aggregatorPath string, pom pomFile)
+	addModules = func(aggregatorPath string, pom pomFile) {
+		for _, module := range pom.Modules {
+			modulePath := path.Join(path.Dir(aggregatorPath), module)
+			if info, err := fsys.Stat(modulePath); err == nil && info.IsDir() {
+				modulePath = path.Join(modulePath, "pom.xml")
+			}
+			if seen[modulePath] {
+				continue
+			}
+			seen[modulePath] = true
+
+			modulePOM, err := readPOMFile(fsys, modulePath)
+			if err != nil {
+				// The module is resolved from the registry instead.
+				continue
+			}
+			modules[mavenutil.ProjectKey(modulePOM.Project).Name()] = modulePath
+			addModules(modulePath, modulePOM)
+		}
+	}
+
+	current := pomPath
+	for range mavenutil.MaxParent {
+		pom, err := readPOMFile(fsys, current)
+		if err != nil {
+			break
+		}
+		addModules(current, pom)
+
+		parentPath := parentPOMPath(fsys, current, string(pom.Parent.RelativePath))
+		if parentPath == "" || pom.Parent.ArtifactID == "" {
+			break
+		}
+		seen[parentPath] = true
+		current = parentPath
+	}
+
+	return modules
+}
+
+// addModules adds the modules of the build that deps depend on, directly or
+// through other modules, to client along with their own dependencies, and
+// returns the versions of the modules it added keyed by their name.
+func addModules(ctx context.Context, mavenClient *datasource.MavenRegistryAPIClient, client *resolution.OverrideClient, fsys scalibrfs.FS, modules map[string]string, deps []maven.Dependency) (map[string]string, error) {
+	added := make(map[string]string)
+	for len(deps) > 0 {
+		d := deps[0]
+		deps = deps[1:]
+
+		modulePath, ok := modules[d.Name()]
+		if _, done := added[d.Name()]; !ok || done {
+			continue
+		}
+
+		f, err := fsys.Open(modulePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open module %s: %w", modulePath, err)
+		}
+		project, err := loadProject(ctx, mavenClient, fsys, modulePath, f, false)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load module %s: %w", modulePath, err)
+		}
+
+		client.AddVersion(resolve.Version{
+			VersionKey: resolve.VersionKey{
+				PackageKey: resolve.PackageKey{
+					System: resolve.Maven,
+					Name:   d.Name(),
+				},
+				VersionType: resolve.Concrete,
+				Version:     string(project.Version),
+			},
+		}, requirements(project.Dependencies, ""))
+		added[d.Name()] = string(project.Version)
+		deps = append(deps, project.Dependencies...)
+	}
+
+	return added, nil
+}
+
+// includedNodes returns the nodes of g that can be reached from its root
+// without going through a dependency of an excluded scope, so that a test
+// dependency is left out along with everything that only it pulls in.
+func (r *resolver) includedNodes(g *resolve.Graph) map[resolve.NodeID]bool {
+	edges := make(map[resolve.NodeID][]resolve.Edge)
+	for _, e := range g.Edges {
+		edges[e.From] = append(edges[e.From], e)
+	}
+
+	included := map[resolve.NodeID]bool{0: true}
+	queue := []resolve.NodeID{0}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, e := range edges[id] {
+			if included[e.To] || slices.Contains(r.excludedScopes, edgeScope(e.Type)) {
+				continue
+			}
+			included[e.To] = true
+			queue = append(queue, e.To)
+		}
+	}
+
+	return included
+}
+
+// edgeScope returns the Maven scope of a dependency of the resolved graph.
+func edgeScope(t dep.Type) string {
+	if t.HasAttr(dep.Test) {
+		return "test"
+	}
+	if scope, ok := t.GetAttr(dep.Scope); ok {
+		return scope
+	}
+
+	return "compile"
+}
+
+// requirements converts Maven dependencies to resolution requirements.
+func requirements(deps []maven.Dependency, origin string) []resolve.RequirementVersion {
+	reqs := make([]resolve.RequirementVersion, 0, len(deps))
+	for _, d := range deps {
+		reqs = append(reqs, resolve.RequirementVersion{
+			VersionKey: resolve.VersionKey{
+				PackageKey: resolve.PackageKey{
+					System: resolve.Maven,
+					Name:   d.Name(),
+				},
+				VersionType: resolve.Requirement,
+				Version:     string(d.Version),
+			},
+			Type: resolve.MavenDepType(d, origin),
+		})
+	}
+
+	return reqs
+}
+
+// loadProject reads the pom.xml at path of fsys from r, merging in its
+// parents and imported dependency management.
+//
+// The registries declared by the project and its parents are added to client
+// if addRegistries is set.
+func loadProject(ctx context.Context, client *datasource.MavenRegistryAPIClient, fsys scalibrfs.FS, pomPath string, r io.Reader, addRegistries bool) (maven.Project, error) {
+	var project maven.Project
+	if err := datasource.NewMavenDecoder(r).Decode(&project); err != nil {
+		return maven.Project{}, fmt.Errorf("could not extract: %w", err)
+	}
+	// Empty JDK and ActivationOS indicates merging the default profiles.
+	if err := project.MergeProfiles("", maven.ActivationOS{}); err != nil {
+		return maven.Project{}, fmt.Errorf("failed to merge profiles: %w", err)
+	}
+	if addRegistries {
+		if err := project.InterpolateRepositories(); err != nil {
+			return maven.Project{}, fmt.Errorf("failed to interpolate project: %w", err)
+		}
+		if err := addRepositories(ctx, client, project.Repositories); err != nil {
+			return maven.Project{}, err
+		}
+	}
+	if err := mergeParents(ctx, client, fsys, pomPath, project.Parent, &project, 1, addRegistries); err != nil {
+		return maven.Project{}, fmt.Errorf("failed to merge parents: %w", err)
+	}
+	project.ProcessDependencies(func(groupID, artifactID, version maven.String) (maven.DependencyManagement, error) {
+		return dependencyManagement(ctx, client, groupID, artifactID, version)
+	})
+
+	return project, nil
+}
+
+// addRepositories adds the registries of the given repositories to client.
+func addRepositories(ctx context.Context, client *datasource.MavenRegistryAPIClient, repos []maven.Repository) error {
+	for _, repo := range repos {
+		if repo.URL.ContainsProperty() {
+			continue
+		}
+		if err := client.AddRegistry(ctx, datasource.MavenRegistry{
+			URL:              string(repo.URL),
+			ID:               string(repo.ID),
+			ReleasesEnabled:  repo.Releases.Enabled.Boolean(),
+			SnapshotsEnabled: repo.Snapshots.Enabled.Boolean(),
+		}); err != nil {
+			return fmt.Errorf("failed to add registry %s: %w", repo.URL, err)
+		}
+	}
+
+	return nil
+}
+
+// mergeParents merges the parents of result into it and interpolates its
+// properties.
+//
+// Parents are read from fsys for as long as they are found next to the
+// project, as the parents of a multi-module build usually are, and are
+// otherwise fetched with client. A nil fsys only fetches them. start is the
+// index of current among the parents, which must have "pom" packaging from
+// the first parent on.
+func mergeParents(ctx context.Context, client *datasource.MavenRegistryAPIClient, fsys scalibrfs.FS, pomPath string, current maven.Parent, result *maven.Project, start int, addRegistries bool) error {
+	allowLocal := fsys != nil
+	visited := make(map[maven.ProjectKey]bool, mavenutil.MaxParent)
+	for n := start; n < mavenutil.MaxParent; n++ {
+		if current.GroupID == "" || current.ArtifactID == "" || current.Version == "" {
+			break
+		}
+		if visited[current.ProjectKey] {
+			return errors.New("a cycle of parents is detected")
+		}
+		visited[current.ProjectKey] = true
+
+		var proj maven.Project
+		found := false
+		if allowLocal {
+			var err error
+			proj, pomPath, found, err = localParent(fsys, pomPath, current)
+			if err != nil {
+				return err
+			}
+		}
+		if !found {
+			// Once a parent is fetched from the registry, its own parents
+			// cannot be next to the project anymore.
+			allowLocal = false
+
+			var err error
+			proj, err = client.GetProject(ctx, string(current.GroupID), string(current.ArtifactID), string(current.Version))
+			if err != nil {
+				return fmt.Errorf("failed to get Maven project %s:%s:%s: %w", current.GroupID, current.ArtifactID, current.Version, err)
+			}
+			if n > 0 && proj.Packaging != "pom" {
+				return fmt.Errorf("invalid packaging for parent project %s", proj.Packaging)
+			}
+			if mavenutil.ProjectKey(proj) != current.ProjectKey {
+				return fmt.Errorf("parent identifiers mismatch: %v, expect %v", proj.ProjectKey, current.ProjectKey)
+			}
+		}
+		if err := result.MergeProfiles("", maven.ActivationOS{}); err != nil {
+			return fmt.Errorf("failed to merge default profiles: %w", err)
+		}
+		if addRegistries {
+			if err := addRepositories(ctx, client, proj.Repositories); err != nil {
+				return err
+			}
+		}
+		result.MergeParent(proj)
+		current = proj.Parent
+	}
+
+	return result.Interpolate()
+}
+
+// localParent reads the parent of the pom.xml at pomPath from fsys, returning
+// its path and whether it is the expected parent.
+func localParent(fsys scalibrfs.FS, pomPath string, parent maven.Parent) (maven.Project, string, bool, error) {
+	parentPath := parentPOMPath(fsys, pomPath, string(parent.RelativePath))
+	if parentPath == "" {
+		return maven.Project{}, pomPath, false, nil
+	}
+
+	f, err := fsys.Open(parentPath)
+	if err != nil {
+		return maven.Project{}, pomPath, false, fmt.Errorf("failed to open parent file %s: %w", parentPath, err)
+	}
+	defer f.Close()
+
+	var proj maven.Project
+	if err := datasource.NewMavenDecoder(f).Decode(&proj); err != nil {
+		return maven.Project{}, pomPath, false, fmt.Errorf("failed to unmarshal project: %w", err)
+	}
+	if mavenutil.ProjectKey(proj) != parent.ProjectKey || proj.Packaging != "pom" {
+		return maven.Project{}, pomPath, false, nil
+	}
+
+	return proj, parentPath, true, nil
+}
+
+// parentPOMPath returns the path of the local parent of the pom.xml at
+// pomPath, or an empty string if there is none.
+func parentPOMPath(fsys scalibrfs.FS, pomPath, relativePath string) string {
+	if relativePath == "" {
+		relativePath = "../pom.xml"
+	}
+
+	p := path.Join(path.Dir(pomPath), relativePath)
+	if info, err := fsys.Stat(p); err == nil {
+		if !info.IsDir() {
+			return p
+		}
+		p = path.Join(p, "pom.xml")
+		if _, err := fsys.Stat(p); err == nil {
+			return p
+		}
+	}
+
+	return ""
+}
+
+// dependencyManagement returns the dependency management of a project that is
+// imported, such as a BOM, with its parents merged in.
+func dependencyManagement(ctx context.Context, client *datasource.MavenRegistryAPIClient, groupID, artifactID, version maven.String) (maven.DependencyManagement, error) {
+	root := maven.Parent{ProjectKey: maven.ProjectKey{GroupID: groupID, ArtifactID: artifactID, Version: version}}
+
+	var result maven.Project
+	if err := mergeParents(ctx, client, nil, "", root, &result, 0, false); err != nil {
+		return maven.DependencyManagement{}, err
+	}
+
+	return result.DependencyManagement, nil
+}
@@ -0,0 +1,206 @@
+package pomxmlenhanceable
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/clients/clienttest"
+	"github.com/google/osv-scalibr/clients/datasource"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/javalockfile"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scalibr/testing/extracttest"
+)
+
+// newTestResolver returns a resolver that resolves dependencies from the
+// mock universe, and fetches projects from srv.
+func newTestResolver(t *testing.T, srv *clienttest.MockHTTPServer, excludedScopes []string) *resolver {
+	t.Helper()
+
+	client, err := datasource.NewDefaultMavenRegistryAPIClient(t.Context(), srv.URL)
+	if err != nil {
+		t.Fatalf("NewDefaultMavenRegistryAPIClient() error: %v", err)
+	}
+
+	return &resolver{
+		depClient:      clienttest.NewMockResolutionClient(t, "testdata/universe.yaml"),
+		mavenClient:    client,
+		excludedScopes: excludedScopes,
+	}
+}
+
+func mavenPackage(name, version, path string, transitive bool, groups ...string) *extractor.Package {
+	groupID, artifactID, _ := strings.Cut(name, ":")
+	if groups == nil {
+		groups = []string{}
+	}
+
+	return &extractor.Package{
+		Name:      name,
+		Version:   version,
+		PURLType:  purl.TypeMaven,
+		Locations: []string{path},
+		Metadata: &javalockfile.Metadata{
+			ArtifactID:   artifactID,
+			GroupID:      groupID,
+			DepGroupVals: groups,
+			IsTransitive: transitive,
+		},
+	}
+}
+
+func TestResolver_Extract_ExcludedScopes(t *testing.T) {
+	t.Parallel()
+
+	const path = "testdata/scopes/pom.xml"
+
+	tests := []struct {
+		name           string
+		excludedScopes []string
+		want           []*extractor.Package
+	}{
+		{
+			name: "no_excluded_scopes",
+			want: []*extractor.Package{
+				mavenPackage("org.direct:alice", "1.0.0", path, false),
+				mavenPackage("org.direct:bob", "2.0.0", path, false, "test"),
+				mavenPackage("org.transitive:chuck", "1.1.1", path, true),
+				mavenPackage("org.transitive:dave", "2.2.2", path, true),
+				mavenPackage("org.transitive:eve", "3.3.3", path, true),
+			},
+		},
+		{
+			// eve is only pulled in by the test dependency, while dave is also
+			// pulled in by a compile dependency
+			name:           "test_scope_excluded",
+			excludedScopes: []string{"test"},
+			want: []*extractor.Package{
+				mavenPackage("org.direct:alice", "1.0.0", path, false),
+				mavenPackage("org.transitive:chuck", "1.1.1", path, true),
+				mavenPackage("org.transitive:dave", "2.2.2", path, true),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newTestResolver(t, clienttest.NewMockHTTPServer(t), tt.excludedScopes)
+
+			input := extracttest.GenerateScanInputMock(t, extracttest.ScanInputMockConfig{Path: path})
+			defer extracttest.CloseTestScanInput(t, input)
+
+			got, err := r.Extract(t.Context(), &input)
+			if err != nil {
+				t.Fatalf("Extract() error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got.Packages, cmpopts.SortSlices(extracttest.PackageCmpLess)); diff != "" {
+				t.Errorf("Extract() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResolver_Extract_Modules(t *testing.T) {
+	t.Parallel()
+
+	// lib is not in any registry, so it can only be resolved from its pom.xml
+	const path = "testdata/multimodule/app/pom.xml"
+
+	r := newTestResolver(t, clienttest.NewMockHTTPServer(t), nil)
+
+	input := extracttest.GenerateScanInputMock(t, extracttest.ScanInputMockConfig{Path: path})
+	defer extracttest.CloseTestScanInput(t, input)
+
+	got, err := r.Extract(t.Context(), &input)
+	if err != nil {
+		t.Fatalf("Extract() error: %v", err)
+	}
+
+	want := []*extractor.Package{
+		mavenPackage("org.direct:alice", "1.0.0", path, true),
+		mavenPackage("org.transitive:chuck", "1.1.1", path, true),
+		mavenPackage("org.transitive:dave", "2.2.2", path, true),
+	}
+	if diff := cmp.Diff(want, got.Packages, cmpopts.SortSlices(extracttest.PackageCmpLess)); diff != "" {
+		t.Errorf("Extract() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestResolver_Extract_Exclusions(t *testing.T) {
+	t.Parallel()
+
+	const path = "testdata/exclusions/pom.xml"
+
+	r := newTestResolver(t, clienttest.NewMockHTTPServer(t), nil)
+
+	input := extracttest.GenerateScanInputMock(t, extracttest.ScanInputMockConfig{Path: path})
+	defer extracttest.CloseTestScanInput(t, input)
+
+	got, err := r.Extract(t.Context(), &input)
+	if err != nil {
+		t.Fatalf("Extract() error: %v", err)
+	}
+
+	// chuck is excluded from alice, and everything from org.transitive from bob,
+	// which leaves dave as it is still pulled in by alice
+	want := []*extractor.Package{
+		mavenPackage("org.direct:alice", "1.0.0", path, false),
+		mavenPackage("org.direct:bob", "2.0.0", path, false),
+		mavenPackage("org.transitive:dave", "2.2.2", path, true),
+	}
+	if diff := cmp.Diff(want, got.Packages, cmpopts.SortSlices(extracttest.PackageCmpLess)); diff != "" {
+		t.Errorf("Extract() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestResolver_Extract_Inheritance(t *testing.T) {
+	t.Parallel()
+
+	// alice's version is managed by the local parent, and bob's by an
+	// imported BOM that is fetched from the registry
+	const path = "testdata/inheritance/child/pom.xml"
+
+	srv := clienttest.NewMockHTTPServer(t)
+	srv.SetResponse(t, "com/example/bom/1.0.0/bom-1.0.0.pom", []byte(`
+	<project>
+	  <groupId>com.example</groupId>
+	  <artifactId>bom</artifactId>
+	  <version>1.0.0</version>
+	  <packaging>pom</packaging>
+	  <dependencyManagement>
+	    <dependencies>
+	      <dependency>
+	        <groupId>org.direct</groupId>
+	        <artifactId>bob</artifactId>
+	        <version>2.0.0</version>
+	      </dependency>
+	    </dependencies>
+	  </dependencyManagement>
+	</project>
+	`))
+	r := newTestResolver(t, srv, nil)
+
+	input := extracttest.GenerateScanInputMock(t, extracttest.ScanInputMockConfig{Path: path})
+	defer extracttest.CloseTestScanInput(t, input)
+
+	got, err := r.Extract(t.Context(), &input)
+	if err != nil {
+		t.Fatalf("Extract() error: %v", err)
+	}
+
+	want := []*extractor.Package{
+		mavenPackage("org.direct:alice", "1.0.0", path, false),
+		mavenPackage("org.direct:bob", "2.0.0", path, false),
+		mavenPackage("org.transitive:chuck", "1.1.1", path, true),
+		mavenPackage("org.transitive:dave", "2.2.2", path, true),
+		mavenPackage("org.transitive:eve", "3.3.3", path, true),
+	}
+	if diff := cmp.Diff(want, got.Packages, cmpopts.SortSlices(extracttest.PackageCmpLess)); diff != "" {
+		t.Errorf("Extract() mismatch (-want +got):\n%s", diff)
+	}
+}
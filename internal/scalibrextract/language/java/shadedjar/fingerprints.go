@@ -0,0 +1,85 @@
+package shadedjar
+
+// fingerprint recognizes a library from its classes, wherever they have been
+// relocated to.
+type fingerprint struct {
+	GroupID    string
+	ArtifactID string
+	// Class is a class that is only in this library, in its internal form and
+	// without its relocation prefix
+	Class string
+	// VersionClass is a class of the library that has its version as a
+	// constant, if any
+	VersionClass string
+	// VersionFile is a properties file, which relocation leaves in place,
+	// that has the version of the library under VersionKey, if any
+	VersionFile string
+	VersionKey  string
+	// VersionAttribute is an attribute of the archive's manifest that has the
+	// version of the library, for libraries whose manifest attributes are
+	// kept when they are shaded, if any
+	VersionAttribute string
+}
+
+var fingerprints = []fingerprint{
+	{
+		GroupID:    "org.apache.logging.log4j",
+		ArtifactID: "log4j-core",
+		Class:      "org/apache/logging/log4j/core/lookup/JndiLookup",
+		// the manifests of Log4j 2 releases, which are usually merged into
+		// that of the shaded jar
+		VersionAttribute: "Log4jReleaseVersion",
+	},
+	{
+		GroupID:      "com.fasterxml.jackson.core",
+		ArtifactID:   "jackson-core",
+		Class:        "com/fasterxml/jackson/core/JsonFactory",
+		VersionClass: "com/fasterxml/jackson/core/json/PackageVersion",
+	},
+	{
+		GroupID:      "com.fasterxml.jackson.core",
+		ArtifactID:   "jackson-databind",
+		Class:        "com/fasterxml/jackson/databind/ObjectMapper",
+		VersionClass: "com/fasterxml/jackson/databind/cfg/PackageVersion",
+	},
+	{
+		GroupID:      "com.fasterxml.jackson.dataformat",
+		ArtifactID:   "jackson-dataformat-yaml",
+		Class:        "com/fasterxml/jackson/dataformat/yaml/YAMLFactory",
+		VersionClass: "com/fasterxml/jackson/dataformat/yaml/PackageVersion",
+	},
+	{
+		GroupID:     "io.netty",
+		ArtifactID:  "netty-codec-http",
+		Class:       "io/netty/handler/codec/http/HttpObjectDecoder",
+		VersionFile: "META-INF/io.netty.versions.properties",
+		VersionKey:  "netty-codec-http.version",
+	},
+	{
+		GroupID:     "io.netty",
+		ArtifactID:  "netty-handler",
+		Class:       "io/netty/handler/ssl/SslHandler",
+		VersionFile: "META-INF/io.netty.versions.properties",
+		VersionKey:  "netty-handler.version",
+	},
+	{
+		GroupID:    "org.yaml",
+		ArtifactID: "snakeyaml",
+		Class:      "org/yaml/snakeyaml/Yaml",
+	},
+	{
+		GroupID:    "org.apache.commons",
+		ArtifactID: "commons-text",
+		Class:      "org/apache/commons/text/StringSubstitutor",
+	},
+	{
+		GroupID:    "commons-collections",
+		ArtifactID: "commons-collections",
+		Class:      "org/apache/commons/collections/functors/InvokerTransformer",
+	},
+	{
+		GroupID:    "com.google.guava",
+		ArtifactID: "guava",
+		Class:      "com/google/common/collect/ImmutableList",
+	},
+}
@@ -0,0 +1,283 @@
+// Package shadedjar provides an extractor for the libraries that uber and
+// shaded Java archives have copied the classes of, without the pom.properties
+// that would otherwise identify them.
+package shadedjar
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	cpb "github.com/google/osv-scalibr/binary/proto/config_go_proto"
+	javareach "github.com/google/osv-scalibr/enricher/reachability/java"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/java/archive"
+	archivemetadata "github.com/google/osv-scalibr/extractor/filesystem/language/java/archive/metadata"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+)
+
+const (
+	// Name is the unique name of this extractor.
+	Name = "java/shadedjar"
+
+	// maxDepth is how many archives deep nested archives are searched, such as
+	// a shaded jar in a war in an ear.
+	maxDepth = 16
+	// maxNestedArchiveBytes is the largest nested archive that is read into
+	// memory to be searched.
+	maxNestedArchiveBytes = 512 << 20
+)
+
+// Extractor finds the libraries whose classes were copied into Java archives,
+// and possibly relocated to another package, by the classes that identify
+// them. It complements the java/archive extractor, which finds the libraries
+// that still have their pom.properties.
+type Extractor struct{}
+
+// New returns a new instance of the extractor.
+func New(_ *cpb.PluginConfig) (filesystem.Extractor, error) {
+	return &Extractor{}, nil
+}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for jar, war, ear and other Java archives.
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	return archive.IsArchive(filepath.ToSlash(fapi.Path()))
+}
+
+// Extract extracts the libraries identified by their classes from a Java
+// archive and the archives nested in it.
+func (e Extractor) Extract(ctx context.Context, input *filesystem.ScanInput) (inventory.Inventory, error) {
+	content, err := io.ReadAll(input.Reader)
+	if err != nil {
+		return inventory.Inventory{}, fmt.Errorf("%s failed to read %s: %w", Name, input.Path, err)
+	}
+
+	pkgs, err := extractArchive(ctx, content, []string{input.Path}, 1)
+	if err != nil {
+		return inventory.Inventory{}, fmt.Errorf("%s failed to extract %s: %w", Name, input.Path, err)
+	}
+
+	return inventory.Inventory{Packages: pkgs}, nil
+}
+
+// extractArchive returns the libraries identified in an archive. paths are
+// the paths of the archives it is nested in, ending with its own, in the same
+// form as those of the java/archive extractor.
+func extractArchive(ctx context.Context, content []byte, paths []string, depth int) ([]*extractor.Package, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	// the artifacts that have a pom.properties, which java/archive reports
+	declared := map[string]bool{}
+	var pkgs []*extractor.Package
+	for _, f := range zr.File {
+		files[f.Name] = f
+
+		// e.g. META-INF/maven/org.apache.logging.log4j/log4j-core/pom.properties
+		if parts := strings.Split(f.Name, "/"); len(parts) == 5 && parts[0] == "META-INF" && parts[1] == "maven" && parts[4] == "pom.properties" {
+			declared[parts[2]+":"+parts[3]] = true
+		}
+
+		if depth < maxDepth && archive.IsArchive(f.Name) && f.UncompressedSize64 <= maxNestedArchiveBytes {
+			path := filepath.Join(paths[len(paths)-1], f.Name)
+			nested, err := readFile(f)
+			if err == nil {
+				var nestedPkgs []*extractor.Package
+				nestedPkgs, err = extractArchive(ctx, nested, slices.Concat(paths, []string{path}), depth+1)
+				pkgs = append(pkgs, nestedPkgs...)
+			}
+			if err != nil {
+				// the archive might not be a zip at all, despite its name
+				cmdlogger.Warnf("%s could not extract %s: %s", Name, path, err)
+			}
+		}
+	}
+
+	for _, fp := range fingerprints {
+		name := fp.GroupID + ":" + fp.ArtifactID
+		if declared[name] {
+			continue
+		}
+
+		found := map[string]bool{}
+		for _, f := range zr.File {
+			class, ok := strings.CutSuffix(f.Name, ".class")
+			if !ok {
+				continue
+			}
+			prefix, ok := strings.CutSuffix(class, fp.Class)
+			if !ok || (prefix != "" && !strings.HasSuffix(prefix, "/")) {
+				continue
+			}
+
+			version := fp.version(files, prefix)
+			location := filepath.Join(paths[len(paths)-1], f.Name)
+			if version == "" {
+				cmdlogger.Warnf("Found the classes of %s at %s, but not its version, so it cannot be checked for vulnerabilities", name, location)
+				continue
+			}
+			if found[version] {
+				continue
+			}
+			found[version] = true
+
+			pkgs = append(pkgs, &extractor.Package{
+				Name:     name,
+				Version:  version,
+				PURLType: purl.TypeMaven,
+				Metadata: &archivemetadata.Metadata{
+					GroupID:    fp.GroupID,
+					ArtifactID: fp.ArtifactID,
+				},
+				Locations: slices.Concat(paths, []string{location}),
+			})
+		}
+	}
+
+	return pkgs, nil
+}
+
+// version returns the version of the library that was relocated under prefix,
+// if it can be found.
+func (fp fingerprint) version(files map[string]*zip.File, prefix string) string {
+	if fp.VersionClass != "" {
+		if f, ok := files[prefix+fp.VersionClass+".class"]; ok {
+			if version := classVersion(f); version != "" {
+				return version
+			}
+		}
+	}
+
+	if fp.VersionFile != "" {
+		if f, ok := files[fp.VersionFile]; ok {
+			return propertiesValue(f, fp.VersionKey)
+		}
+	}
+
+	if fp.VersionAttribute != "" {
+		if f, ok := files["META-INF/MANIFEST.MF"]; ok {
+			return manifestValue(f, fp.VersionAttribute)
+		}
+	}
+
+	return ""
+}
+
+// manifestValue returns the value of an attribute in any section of a
+// manifest, whose long values are continued on lines starting with a space.
+func manifestValue(f *zip.File, attribute string) string {
+	r, err := f.Open()
+	if err != nil {
+		return ""
+	}
+	defer r.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if continued, ok := strings.CutPrefix(line, " "); ok && len(lines) > 0 {
+			lines[len(lines)-1] += continued
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	for _, line := range lines {
+		k, v, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(k), attribute) {
+			return strings.TrimSpace(v)
+		}
+	}
+
+	return ""
+}
+
+// classVersion returns the first version-like string constant in a class.
+func classVersion(f *zip.File) string {
+	r, err := f.Open()
+	if err != nil {
+		return ""
+	}
+	defer r.Close()
+
+	cf, err := javareach.ParseClass(r)
+	if err != nil {
+		return ""
+	}
+
+	for i, entry := range cf.ConstantPool {
+		if entry.Type() != javareach.ConstantKindUtf8 {
+			continue
+		}
+		val, err := cf.ConstantPoolUtf8(i)
+		if err != nil {
+			continue
+		}
+		if cachedregexp.MustCompile(`^\d+\.\d+\.\d+(?:[.-][\w.-]+)?$`).MatchString(val) {
+			return val
+		}
+	}
+
+	return ""
+}
+
+// propertiesValue returns the value of a key in a properties file.
+func propertiesValue(f *zip.File, key string) string {
+	r, err := f.Open()
+	if err != nil {
+		return ""
+	}
+	defer r.Close()
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		k, v, ok := strings.Cut(scanner.Text(), "=")
+		if ok && strings.TrimSpace(k) == key {
+			return strings.TrimSpace(v)
+		}
+	}
+
+	return ""
+}
+
+func readFile(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+var _ filesystem.Extractor = Extractor{}
@@ -0,0 +1,161 @@
+package shadedjar_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	archivemetadata "github.com/google/osv-scalibr/extractor/filesystem/language/java/archive/metadata"
+	"github.com/google/osv-scalibr/extractor/filesystem/simplefileapi"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/shadedjar"
+)
+
+// class builds a class file with the given string constants.
+func class(t *testing.T, name string, constants ...string) []byte {
+	t.Helper()
+
+	var pool bytes.Buffer
+	utf8 := func(s string) {
+		pool.WriteByte(1)
+		_ = binary.Write(&pool, binary.BigEndian, uint16(len(s)))
+		pool.WriteString(s)
+	}
+	utf8(name)
+	pool.WriteByte(7)
+	_ = binary.Write(&pool, binary.BigEndian, uint16(1))
+	for _, c := range constants {
+		utf8(c)
+	}
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, uint32(0xCAFEBABE))
+	_ = binary.Write(&buf, binary.BigEndian, uint16(0))
+	_ = binary.Write(&buf, binary.BigEndian, uint16(52))
+	_ = binary.Write(&buf, binary.BigEndian, uint16(len(constants)+3))
+	buf.Write(pool.Bytes())
+	_ = binary.Write(&buf, binary.BigEndian, uint16(0x0021))
+	_ = binary.Write(&buf, binary.BigEndian, uint16(2))
+
+	return buf.Bytes()
+}
+
+// jar builds an archive with the given files.
+func jar(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestExtractor_FileRequired(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "app.jar", want: true},
+		{path: "lib/app.WAR", want: true},
+		{path: "app.ear", want: true},
+		{path: "pom.xml", want: false},
+		{path: "app.zip", want: false},
+	}
+	for _, tt := range tests {
+		got := shadedjar.Extractor{}.FileRequired(simplefileapi.New(tt.path, nil))
+		if got != tt.want {
+			t.Errorf("FileRequired(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	shaded := jar(t, map[string][]byte{
+		"com/example/App.class": class(t, "com/example/App"),
+		// relocated, with its version in a class
+		"com/example/shaded/com/fasterxml/jackson/databind/ObjectMapper.class":       class(t, "com/example/shaded/com/fasterxml/jackson/databind/ObjectMapper"),
+		"com/example/shaded/com/fasterxml/jackson/databind/cfg/PackageVersion.class": class(t, "com/example/shaded/com/fasterxml/jackson/databind/cfg/PackageVersion", "com.fasterxml.jackson.core", "2.9.8", "jackson-databind"),
+		// relocated, with its version in a properties file
+		"com/example/shaded/io/netty/handler/codec/http/HttpObjectDecoder.class": class(t, "com/example/shaded/io/netty/handler/codec/http/HttpObjectDecoder"),
+		"META-INF/io.netty.versions.properties":                                  []byte("netty-codec-http.version=4.1.42.Final\nnetty-handler.version=4.1.42.Final\n"),
+		// relocated, without a version
+		"com/example/shaded/org/apache/logging/log4j/core/lookup/JndiLookup.class": class(t, "com/example/shaded/org/apache/logging/log4j/core/lookup/JndiLookup"),
+		// not relocated, but with a pom.properties that java/archive reports
+		"org/yaml/snakeyaml/Yaml.class":                    class(t, "org/yaml/snakeyaml/Yaml"),
+		"META-INF/maven/org.yaml/snakeyaml/pom.properties": []byte("groupId=org.yaml\nartifactId=snakeyaml\nversion=1.26\n"),
+		// not the class that guava is identified by
+		"com/example/shaded/com/google/common/collect/Foo.class": class(t, "com/example/shaded/com/google/common/collect/Foo"),
+	})
+	// relocated log4j-core, whose version is in the merged manifest
+	log4shell := jar(t, map[string][]byte{
+		"com/example/tool/Main.class": class(t, "com/example/tool/Main"),
+		"com/example/tool/shaded/org/apache/logging/log4j/core/lookup/JndiLookup.class": class(t, "com/example/tool/shaded/org/apache/logging/log4j/core/lookup/JndiLookup"),
+		"META-INF/MANIFEST.MF": []byte("Manifest-Version: 1.0\r\nMain-Class: com.example.tool.Main\r\nCreated-By: Apache Maven Shade Plug\r\n in\r\nLog4jReleaseVersion: 2.14.1\r\n\r\n"),
+	})
+	war := jar(t, map[string][]byte{
+		"WEB-INF/web.xml":           []byte("<web-app/>"),
+		"WEB-INF/lib/app-1.0.0.jar": shaded,
+		"WEB-INF/lib/tool-2.0.jar":  log4shell,
+	})
+
+	got, err := shadedjar.Extractor{}.Extract(t.Context(), &filesystem.ScanInput{
+		Path:   "app.war",
+		Reader: bytes.NewReader(war),
+	})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	nested := filepath.Join("app.war", "WEB-INF/lib/app-1.0.0.jar")
+	tool := filepath.Join("app.war", "WEB-INF/lib/tool-2.0.jar")
+	want := []*extractor.Package{
+		{
+			Name:      "org.apache.logging.log4j:log4j-core",
+			Version:   "2.14.1",
+			PURLType:  purl.TypeMaven,
+			Metadata:  &archivemetadata.Metadata{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core"},
+			Locations: []string{"app.war", tool, filepath.Join(tool, "com/example/tool/shaded/org/apache/logging/log4j/core/lookup/JndiLookup.class")},
+		},
+		{
+			Name:      "com.fasterxml.jackson.core:jackson-databind",
+			Version:   "2.9.8",
+			PURLType:  purl.TypeMaven,
+			Metadata:  &archivemetadata.Metadata{GroupID: "com.fasterxml.jackson.core", ArtifactID: "jackson-databind"},
+			Locations: []string{"app.war", nested, filepath.Join(nested, "com/example/shaded/com/fasterxml/jackson/databind/ObjectMapper.class")},
+		},
+		{
+			Name:      "io.netty:netty-codec-http",
+			Version:   "4.1.42.Final",
+			PURLType:  purl.TypeMaven,
+			Metadata:  &archivemetadata.Metadata{GroupID: "io.netty", ArtifactID: "netty-codec-http"},
+			Locations: []string{"app.war", nested, filepath.Join(nested, "com/example/shaded/io/netty/handler/codec/http/HttpObjectDecoder.class")},
+		},
+	}
+	// the archives nested in the war are found in no particular order
+	sortPackages := cmpopts.SortSlices(func(a, b *extractor.Package) bool { return a.Name < b.Name })
+	if diff := cmp.Diff(want, got.Packages, sortPackages); diff != "" {
+		t.Errorf("Extract() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,239 @@
+// Package distribution provides an extractor for the dependencies declared by
+// Python wheels and source distributions, so they can be checked before they
+// are published.
+package distribution
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	cpb "github.com/google/osv-scalibr/binary/proto/config_go_proto"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/python/requirements"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+)
+
+const (
+	// Name is the unique name of this extractor.
+	Name = "python/distribution"
+
+	// maxMetadataBytes is the largest metadata file that is read.
+	maxMetadataBytes = 10 << 20
+)
+
+// Extractor extracts the requirements declared in the metadata of wheels and
+// source distributions, in the same form as the python/requirements
+// extractor, so that their transitive dependencies are resolved the same way.
+type Extractor struct {
+	requirements filesystem.Extractor
+}
+
+// New returns a new instance of the extractor.
+func New(cfg *cpb.PluginConfig) (filesystem.Extractor, error) {
+	r, err := requirements.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Extractor{requirements: r}, nil
+}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired returns true for wheels and for tarballs named like source
+// distributions, e.g. "requests-2.31.0.tar.gz".
+func (e Extractor) FileRequired(fapi filesystem.FileAPI) bool {
+	base := filepath.Base(fapi.Path())
+
+	return strings.HasSuffix(base, ".whl") ||
+		cachedregexp.MustCompile(`^[A-Za-z0-9][\w.]*-\d[\w.!+]*\.tar\.gz$`).MatchString(base)
+}
+
+// Extract extracts the requirements of the distribution, leaving out those
+// that are only needed for extras.
+func (e Extractor) Extract(ctx context.Context, input *filesystem.ScanInput) (inventory.Inventory, error) {
+	var requires []string
+	var err error
+	if strings.HasSuffix(input.Path, ".whl") {
+		requires, err = wheelRequirements(input.Reader)
+	} else {
+		requires, err = sdistRequirements(input.Reader)
+	}
+	if err != nil {
+		return inventory.Inventory{}, fmt.Errorf("%s failed to read %s: %w", Name, input.Path, err)
+	}
+
+	var sb strings.Builder
+	for _, r := range requires {
+		// extras are optional, so their requirements are not installed by default
+		if cachedregexp.MustCompile(`\bextra\s*==`).MatchString(r) {
+			continue
+		}
+		sb.WriteString(requirementLine(r))
+		sb.WriteString("\n")
+	}
+
+	return e.requirements.Extract(ctx, &filesystem.ScanInput{
+		FS:     input.FS,
+		Path:   input.Path,
+		Root:   input.Root,
+		Info:   input.Info,
+		Reader: strings.NewReader(sb.String()),
+	})
+}
+
+// requirementLine converts a requirement as written in Requires-Dist, which
+// may have its version specifiers in parentheses as in "requests (>=2.0)", to
+// how it is written in requirements files.
+func requirementLine(requirement string) string {
+	return cachedregexp.MustCompile(`^([^;(]+?)\s*\(([^)]*)\)`).ReplaceAllString(strings.TrimSpace(requirement), "$1$2")
+}
+
+// wheelRequirements returns the Requires-Dist of the METADATA of a wheel.
+func wheelRequirements(r io.Reader) ([]string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range zr.File {
+		dir, name, ok := strings.Cut(f.Name, "/")
+		if !ok || !strings.HasSuffix(dir, ".dist-info") || name != "METADATA" {
+			continue
+		}
+
+		metadata, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer metadata.Close()
+
+		return metadataRequirements(io.LimitReader(metadata, maxMetadataBytes))
+	}
+
+	return nil, errors.New("no .dist-info/METADATA found")
+}
+
+// sdistRequirements returns the Requires-Dist of the PKG-INFO of a source
+// distribution, or the requirements in its .egg-info when the PKG-INFO does
+// not have them, as before metadata version 2.2.
+func sdistRequirements(r io.Reader) ([]string, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var pkgInfo, eggRequires []string
+	foundPkgInfo := false
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// e.g. "requests-2.31.0/PKG-INFO" and
+		// "requests-2.31.0/src/requests.egg-info/requires.txt"
+		parts := strings.Split(hdr.Name, "/")
+		switch {
+		case len(parts) == 2 && parts[1] == "PKG-INFO":
+			foundPkgInfo = true
+			pkgInfo, err = metadataRequirements(io.LimitReader(tr, maxMetadataBytes))
+		case len(parts) <= 4 && len(parts) >= 3 && parts[len(parts)-1] == "requires.txt" && strings.HasSuffix(parts[len(parts)-2], ".egg-info"):
+			eggRequires, err = eggInfoRequirements(io.LimitReader(tr, maxMetadataBytes))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !foundPkgInfo {
+		return nil, errors.New("no PKG-INFO found")
+	}
+	if len(pkgInfo) > 0 {
+		return pkgInfo, nil
+	}
+
+	return eggRequires, nil
+}
+
+// metadataRequirements returns the Requires-Dist fields of a core metadata
+// file, whose headers end at the first empty line.
+func metadataRequirements(r io.Reader) ([]string, error) {
+	var requires []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		if value, ok := strings.CutPrefix(line, "Requires-Dist:"); ok {
+			requires = append(requires, strings.TrimSpace(value))
+		}
+	}
+
+	return requires, scanner.Err()
+}
+
+// eggInfoRequirements returns the requirements in an .egg-info/requires.txt,
+// where those under a "[:marker]" section only hold in some environments and
+// those under an "[extra]" section are only for that extra.
+func eggInfoRequirements(r io.Reader) ([]string, error) {
+	var requires []string
+	marker := ""
+	inExtra := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if section, ok := strings.CutPrefix(line, "["); ok {
+			section = strings.TrimSuffix(section, "]")
+			extra, m, _ := strings.Cut(section, ":")
+			inExtra = extra != ""
+			marker = m
+
+			continue
+		}
+		if inExtra {
+			continue
+		}
+		if marker != "" {
+			line += "; " + marker
+		}
+		requires = append(requires, line)
+	}
+
+	return requires, scanner.Err()
+}
+
+var _ filesystem.Extractor = Extractor{}
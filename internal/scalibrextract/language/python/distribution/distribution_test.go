@@ -0,0 +1,190 @@
+package distribution_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	cpb "github.com/google/osv-scalibr/binary/proto/config_go_proto"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/extractor/filesystem/language/python/requirements"
+	"github.com/google/osv-scalibr/extractor/filesystem/simplefileapi"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/distribution"
+)
+
+const metadata = `Metadata-Version: 2.1
+Name: example
+Version: 1.0.0
+Requires-Dist: requests (>=2.25.0)
+Requires-Dist: idna==3.4
+Requires-Dist: importlib-metadata>=4.0; python_version < "3.8"
+Requires-Dist: pytest>=7.0; extra == "test"
+
+Requires-Dist: not-a-header
+`
+
+func wheel(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func sdist(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func pkg(path string, name string, version string, comparator string, requirement string) *extractor.Package {
+	return &extractor.Package{
+		Name:      name,
+		Version:   version,
+		PURLType:  purl.TypePyPi,
+		Locations: []string{path},
+		Metadata: &requirements.Metadata{
+			HashCheckingModeValues: []string{},
+			VersionComparator:      comparator,
+			Requirement:            requirement,
+		},
+	}
+}
+
+func TestExtractor_FileRequired(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "dist/example-1.0.0-py3-none-any.whl", want: true},
+		{path: "dist/example-1.0.0.tar.gz", want: true},
+		{path: "dist/example_lib-1.0.0rc1.tar.gz", want: true},
+		{path: "backup.tar.gz", want: false},
+		{path: "requirements.txt", want: false},
+	}
+	for _, tt := range tests {
+		e, _ := distribution.New(&cpb.PluginConfig{})
+		if got := e.FileRequired(simplefileapi.New(tt.path, nil)); got != tt.want {
+			t.Errorf("FileRequired(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		path    string
+		content []byte
+		want    []*extractor.Package
+		wantErr bool
+	}{
+		{
+			name: "wheel",
+			path: "example-1.0.0-py3-none-any.whl",
+			content: wheel(t, map[string]string{
+				"example/__init__.py":               "",
+				"example-1.0.0.dist-info/METADATA":  metadata,
+				"example-1.0.0.dist-info/RECORD":    "",
+				"example/vendored.dist-info/README": "",
+			}),
+			want: []*extractor.Package{
+				pkg("example-1.0.0-py3-none-any.whl", "requests", "2.25.0", ">=", "requests>=2.25.0"),
+				pkg("example-1.0.0-py3-none-any.whl", "idna", "3.4", "==", "idna==3.4"),
+				pkg("example-1.0.0-py3-none-any.whl", "importlib-metadata", "4.0", ">=", `importlib-metadata>=4.0; python_version < "3.8"`),
+			},
+		},
+		{
+			name: "source distribution",
+			path: "example-1.0.0.tar.gz",
+			content: sdist(t, map[string]string{
+				"example-1.0.0/setup.py": "",
+				"example-1.0.0/PKG-INFO": metadata,
+			}),
+			want: []*extractor.Package{
+				pkg("example-1.0.0.tar.gz", "requests", "2.25.0", ">=", "requests>=2.25.0"),
+				pkg("example-1.0.0.tar.gz", "idna", "3.4", "==", "idna==3.4"),
+				pkg("example-1.0.0.tar.gz", "importlib-metadata", "4.0", ">=", `importlib-metadata>=4.0; python_version < "3.8"`),
+			},
+		},
+		{
+			name: "source distribution with requirements in its egg-info",
+			path: "example-1.0.0.tar.gz",
+			content: sdist(t, map[string]string{
+				"example-1.0.0/PKG-INFO": "Metadata-Version: 2.1\nName: example\nVersion: 1.0.0\n",
+				"example-1.0.0/src/example.egg-info/requires.txt": "requests>=2.25.0\n\n" +
+					"[:python_version < \"3.8\"]\nimportlib-metadata>=4.0\n\n" +
+					"[test]\npytest>=7.0\n",
+			}),
+			want: []*extractor.Package{
+				pkg("example-1.0.0.tar.gz", "requests", "2.25.0", ">=", "requests>=2.25.0"),
+				pkg("example-1.0.0.tar.gz", "importlib-metadata", "4.0", ">=", `importlib-metadata>=4.0; python_version < "3.8"`),
+			},
+		},
+		{
+			name:    "tarball that is not a source distribution",
+			path:    "example-1.0.0.tar.gz",
+			content: sdist(t, map[string]string{"example/README": ""}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := distribution.New(&cpb.PluginConfig{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := e.Extract(t.Context(), &filesystem.ScanInput{
+				Path:   tt.path,
+				Reader: bytes.NewReader(tt.content),
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Extract() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got.Packages); diff != "" {
+				t.Errorf("Extract() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
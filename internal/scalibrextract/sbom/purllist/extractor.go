@@ -0,0 +1,104 @@
+// Package purllist extracts packages from a newline delimited list of
+// package URLs, for inventories that are already known.
+package purllist
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	cpb "github.com/google/osv-scalibr/binary/proto/config_go_proto"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	cdxmeta "github.com/google/osv-scalibr/extractor/filesystem/sbom/cdx/metadata"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	purlutil "github.com/google/osv-scanner/v2/internal/utility/purl"
+)
+
+const (
+	// Name is the unique name of this extractor.
+	Name = "sbom/purllist"
+)
+
+// Extractor extracts a package from each line of a list of package URLs.
+// Blank lines and those starting with "#" are skipped.
+type Extractor struct{}
+
+// New returns a new instance of the extractor.
+func New(_ *cpb.PluginConfig) (filesystem.Extractor, error) {
+	return Extractor{}, nil
+}
+
+// Name of the extractor.
+func (e Extractor) Name() string { return Name }
+
+// Version of the extractor.
+func (e Extractor) Version() int { return 0 }
+
+// Requirements of the extractor.
+func (e Extractor) Requirements() *plugin.Capabilities {
+	return &plugin.Capabilities{}
+}
+
+// FileRequired always returns false, as there is no naming convention for
+// lists of package URLs, so they are only extracted when asked for.
+func (e Extractor) FileRequired(_ filesystem.FileAPI) bool {
+	return false
+}
+
+// Extract extracts packages from the list of package URLs passed through the
+// scan input.
+func (e Extractor) Extract(_ context.Context, input *filesystem.ScanInput) (inventory.Inventory, error) {
+	packages := []*extractor.Package{}
+
+	scanner := bufio.NewScanner(input.Reader)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		pkg, err := ToPackage(text)
+		if err != nil {
+			return inventory.Inventory{}, fmt.Errorf("%s:%d: %w", input.Path, line, err)
+		}
+		pkg.Locations = []string{input.Path}
+
+		packages = append(packages, pkg)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return inventory.Inventory{}, fmt.Errorf("could not extract from %s: %w", input.Path, err)
+	}
+
+	return inventory.Inventory{Packages: packages}, nil
+}
+
+// ToPackage returns the package identified by a package URL, which must have
+// a version for its vulnerabilities to be known.
+func ToPackage(packageURL string) (*extractor.Package, error) {
+	p, err := purl.FromString(packageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid package URL %q: %w", packageURL, err)
+	}
+	if p.Version == "" {
+		return nil, fmt.Errorf("package URL %q has no version", packageURL)
+	}
+
+	// the name is given as it is in the package's ecosystem, such as
+	// "group:artifact" for Maven, as enrichers expect it to be
+	info, err := purlutil.ToPackage(p.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid package URL %q: %w", packageURL, err)
+	}
+
+	return &extractor.Package{
+		Name:     info.Name,
+		Version:  p.Version,
+		PURLType: p.Type,
+		Metadata: &cdxmeta.Metadata{PURL: &p},
+	}, nil
+}
@@ -0,0 +1,108 @@
+package purllist_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scalibr/extractor"
+	cdxmeta "github.com/google/osv-scalibr/extractor/filesystem/sbom/cdx/metadata"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scalibr/testing/extracttest"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/sbom/purllist"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	tests := []extracttest.TestTableEntry{
+		{
+			Name: "empty",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/empty.txt",
+			},
+			WantPackages: []*extractor.Package{},
+		},
+		{
+			Name: "invalid package url",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/invalid.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "testdata/invalid.txt:2: invalid package URL"},
+		},
+		{
+			Name: "no version",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/no-version.txt",
+			},
+			WantErr: extracttest.ContainsErrStr{Str: "has no version"},
+		},
+		{
+			Name: "package urls",
+			InputConfig: extracttest.ScanInputMockConfig{
+				Path: "testdata/purls.txt",
+			},
+			WantPackages: []*extractor.Package{
+				{
+					Name:      "lodash",
+					Version:   "4.17.20",
+					PURLType:  purl.TypeNPM,
+					Locations: []string{"testdata/purls.txt"},
+					Metadata: &cdxmeta.Metadata{PURL: &purl.PackageURL{
+						Type:       purl.TypeNPM,
+						Name:       "lodash",
+						Version:    "4.17.20",
+						Qualifiers: purl.Qualifiers{},
+					}},
+				},
+				{
+					Name:      "org.apache.logging.log4j:log4j-core",
+					Version:   "2.14.1",
+					PURLType:  purl.TypeMaven,
+					Locations: []string{"testdata/purls.txt"},
+					Metadata: &cdxmeta.Metadata{PURL: &purl.PackageURL{
+						Type:       purl.TypeMaven,
+						Namespace:  "org.apache.logging.log4j",
+						Name:       "log4j-core",
+						Version:    "2.14.1",
+						Qualifiers: purl.Qualifiers{},
+					}},
+				},
+				{
+					Name:      "@babel/core",
+					Version:   "7.0.0",
+					PURLType:  purl.TypeNPM,
+					Locations: []string{"testdata/purls.txt"},
+					Metadata: &cdxmeta.Metadata{PURL: &purl.PackageURL{
+						Type:       purl.TypeNPM,
+						Namespace:  "@babel",
+						Name:       "core",
+						Version:    "7.0.0",
+						Qualifiers: purl.Qualifiers{},
+					}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			t.Parallel()
+			extr := purllist.Extractor{}
+
+			scanInput := extracttest.GenerateScanInputMock(t, tt.InputConfig)
+			defer extracttest.CloseTestScanInput(t, scanInput)
+
+			got, err := extr.Extract(t.Context(), &scanInput)
+
+			if diff := cmp.Diff(tt.WantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s.Extract(%q) error diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+				return
+			}
+
+			if diff := cmp.Diff(tt.WantPackages, got.Packages, cmpopts.SortSlices(extracttest.PackageCmpLess)); diff != "" {
+				t.Errorf("%s.Extract(%q) diff (-want +got):\n%s", extr.Name(), tt.InputConfig.Path, diff)
+			}
+		})
+	}
+}
@@ -48,9 +48,14 @@ import (
 	"github.com/google/osv-scanner/v2/internal/datasource"
 	"github.com/google/osv-scanner/v2/internal/depsdev"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/filesystem/vendored"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/iac/terraformlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/iac/terraformmodule"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/pomxmlenhanceable"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/shadedjar"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/javascript/nodemodules"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/osv/osvscannerjson"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/distribution"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/sbom/purllist"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/vcs/gitrepo"
 	"github.com/google/osv-scanner/v2/internal/version"
 )
@@ -66,6 +71,9 @@ var ExtractorPresets = map[string]extractors.InitMap{
 	"sbom": {
 		spdx.Name: {spdx.New},
 		cdx.Name:  {cdx.New},
+		// only used when asked for, as lists of package URLs are not named
+		// after a convention
+		purllist.Name: {purllist.New},
 	},
 	"lockfile": {
 		// C
@@ -101,6 +109,7 @@ var ExtractorPresets = map[string]extractors.InitMap{
 		pylock.Name:       {pylock.New},
 		requirements.Name: {requirements.New},
 		uvlock.Name:       {uvlock.New},
+		distribution.Name: {distribution.New},
 
 		// R
 		renvlock.Name: {renvlock.New},
@@ -111,6 +120,10 @@ var ExtractorPresets = map[string]extractors.InitMap{
 		// Rust
 		cargolock.Name: {cargolock.New},
 
+		// Terraform
+		terraformlock.Name:   {terraformlock.New},
+		terraformmodule.Name: {terraformmodule.New},
+
 		// NuGet
 		depsjson.Name:         {depsjson.New},
 		packagesconfig.Name:   {packagesconfig.New},
@@ -138,7 +151,8 @@ var ExtractorPresets = map[string]extractors.InitMap{
 		// Python
 		wheelegg.Name: {wheelegg.New},
 		// Java
-		archive.Name: {archive.New},
+		archive.Name:   {archive.New},
+		shadedjar.Name: {shadedjar.New},
 		// Go
 		gobinary.Name: {gobinary.New},
 		// Javascript
@@ -8,10 +8,19 @@ import (
 	"github.com/google/osv-scalibr/plugin"
 	"github.com/google/osv-scalibr/plugin/list"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/compose"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/dockerfile"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/helm"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/kubernetes"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/filesystem/vendored"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/iac/terraformlock"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/iac/terraformmodule"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/pomxmlenhanceable"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/shadedjar"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/javascript/nodemodules"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/osv/osvscannerjson"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/python/distribution"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/sbom/purllist"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/vcs/gitrepo"
 )
 
@@ -26,9 +35,14 @@ func resolveFromName(name string) (plugin.Plugin, error) {
 	// Java
 	case pomxmlenhanceable.Name:
 		return pomxmlenhanceable.New(&cpb.PluginConfig{})
+	case shadedjar.Name:
+		return shadedjar.New(&cpb.PluginConfig{})
 	// Javascript
 	case nodemodules.Name:
 		return nodemodules.New(&cpb.PluginConfig{})
+	// Python
+	case distribution.Name:
+		return distribution.New(&cpb.PluginConfig{})
 	// Directories
 	case vendored.Name:
 		return vendored.New(&cpb.PluginConfig{})
@@ -36,6 +50,23 @@ func resolveFromName(name string) (plugin.Plugin, error) {
 		return gitrepo.New(&cpb.PluginConfig{})
 	case osvscannerjson.Name:
 		return osvscannerjson.New(&cpb.PluginConfig{})
+	// SBOMs
+	case purllist.Name:
+		return purllist.New(&cpb.PluginConfig{})
+	// Containers
+	case dockerfile.Name:
+		return dockerfile.New(&cpb.PluginConfig{})
+	case kubernetes.Name:
+		return kubernetes.New(&cpb.PluginConfig{})
+	case compose.Name:
+		return compose.New(&cpb.PluginConfig{})
+	case helm.Name:
+		return helm.New(&cpb.PluginConfig{})
+	// Terraform
+	case terraformlock.Name:
+		return terraformlock.New(&cpb.PluginConfig{})
+	case terraformmodule.Name:
+		return terraformmodule.New(&cpb.PluginConfig{})
 	default:
 		return nil, fmt.Errorf("not an exact name for a plugin: %q", name)
 	}
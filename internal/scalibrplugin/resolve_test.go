@@ -27,7 +27,9 @@ import (
 	"github.com/google/osv-scalibr/extractor/filesystem/sbom/cdx"
 	"github.com/google/osv-scalibr/extractor/filesystem/sbom/spdx"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/filesystem/vendored"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/shadedjar"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/javascript/nodemodules"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/sbom/purllist"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/vcs/gitrepo"
 	"github.com/google/osv-scanner/v2/internal/scalibrplugin"
 	"github.com/google/osv-scanner/v2/internal/testutility"
@@ -143,6 +145,7 @@ func TestResolve(t *testing.T) {
 				codeserver.Name,
 				etcshadow.Name,
 				filebrowser.Name,
+				purllist.Name,
 				spdx.Name,
 				winlocal.Name,
 			},
@@ -401,6 +404,7 @@ func TestResolve_Extractors(t *testing.T) {
 				dpkg.Name,
 				gobinary.Name,
 				nodemodules.Name,
+				shadedjar.Name,
 				wheelegg.Name,
 				apkanno.Name,
 				dpkganno.Name,
@@ -420,6 +424,7 @@ func TestResolve_Extractors(t *testing.T) {
 				dpkg.Name,
 				gobinary.Name,
 				nodemodules.Name,
+				shadedjar.Name,
 				wheelegg.Name,
 				apkanno.Name,
 				dpkganno.Name,
@@ -445,6 +450,7 @@ func TestResolve_Extractors(t *testing.T) {
 				dpkg.Name,
 				gobinary.Name,
 				nodemodules.Name,
+				shadedjar.Name,
 				apkanno.Name,
 				dpkganno.Name,
 			},
@@ -465,6 +471,7 @@ func TestResolve_Extractors(t *testing.T) {
 				gitrepo.Name,
 				gobinary.Name,
 				nodemodules.Name,
+				shadedjar.Name,
 				vendored.Name,
 				wheelegg.Name,
 				apkanno.Name,
@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
 	"github.com/google/osv-scanner/v2/internal/sourceanalysis/govulncheck"
@@ -63,12 +64,17 @@ func goAnalysis(pkgs []models.PackageVulns, source models.SourceInfo) {
 
 func matchAnalysisWithPackageVulns(pkgs []models.PackageVulns, idToFindings map[string][]*govulncheck.Finding, vulnsByID map[string]*osvschema.Vulnerability) {
 	idToModuleToCalled := map[string]map[string]bool{}
+	idToModuleToCallStack := map[string]map[string][]models.StackFrame{}
 	for id, findings := range idToFindings {
 		idToModuleToCalled[id] = map[string]bool{}
+		idToModuleToCallStack[id] = map[string][]models.StackFrame{}
 		for _, f := range findings {
 			modulePath := f.Trace[0].Module
 			called := f.Trace[0].Function != ""
 			idToModuleToCalled[f.OSV][modulePath] = called
+			if called {
+				idToModuleToCallStack[f.OSV][modulePath] = callStack(f.Trace)
+			}
 		}
 	}
 
@@ -94,13 +100,44 @@ func matchAnalysisWithPackageVulns(pkgs []models.PackageVulns, idToFindings map[
 				}
 
 				(*analysis)[vulnID] = models.AnalysisInfo{
-					Called: moduleToCalled[pv.Package.Name],
+					Called:    moduleToCalled[pv.Package.Name],
+					CallStack: idToModuleToCallStack[vulnID][pv.Package.Name],
 				}
 			}
 		}
 	}
 }
 
+// callStack converts the trace of a govulncheck finding, which starts from the
+// vulnerable symbol, into a call stack.
+func callStack(trace []*govulncheck.Frame) []models.StackFrame {
+	stack := make([]models.StackFrame, 0, len(trace))
+	for _, frame := range trace {
+		function := frame.Function
+		if frame.Receiver != "" {
+			if strings.HasPrefix(frame.Receiver, "*") {
+				function = "(" + frame.Receiver + ")." + function
+			} else {
+				function = frame.Receiver + "." + function
+			}
+		}
+
+		sf := models.StackFrame{
+			Module:   frame.Module,
+			Package:  frame.Package,
+			Function: function,
+		}
+		if frame.Position != nil && frame.Position.Line > 0 {
+			sf.Path = frame.Position.Filename
+			sf.Line = frame.Position.Line
+			sf.Column = frame.Position.Column
+		}
+		stack = append(stack, sf)
+	}
+
+	return stack
+}
+
 func vulnHasImportsField(vuln *osvschema.Vulnerability, pkg *models.PackageInfo) bool {
 	if vuln == nil {
 		return false
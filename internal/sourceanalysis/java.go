@@ -0,0 +1,389 @@
+package sourceanalysis
+
+import (
+	"archive/zip"
+	"bufio"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	javareach "github.com/google/osv-scalibr/enricher/reachability/java"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+const javaServicesDir = "META-INF/services/"
+
+// mavenLocalRepository returns the directory Maven downloads the jars of
+// dependencies to by default.
+func mavenLocalRepository() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".m2", "repository")
+}
+
+// javaAnalysis marks the vulnerabilities of transitive Maven packages as called
+// or not, by following the classes referred to by each reachable class from the
+// project's compiled classes through the jars of the packages in the local Maven
+// repository at repo.
+//
+// Only vulnerabilities that list their vulnerable symbols (see
+// javaVulnerableClasses) are marked as not called, when none of the classes of
+// those symbols can be reached. Classes can also be reached through reflection
+// and dependency injection, which are not followed, so the others are only
+// marked as called when their package can be reached.
+func javaAnalysis(pkgs []models.PackageVulns, source models.SourceInfo, deps []Dependency, repo string) {
+	if !slices.ContainsFunc(deps, func(d Dependency) bool { return d.Transitive }) {
+		// only transitive packages are analyzed
+		return
+	}
+
+	classesDir := filepath.Join(filepath.Dir(source.Path), "target", "classes")
+	if _, err := os.Stat(classesDir); err != nil {
+		cmdlogger.Infof("no compiled classes found in '%s', skipping call analysis; run `mvn compile` first", classesDir)
+		return
+	}
+
+	cp := newJavaClassPath()
+	defer cp.close()
+
+	var missing int
+	for _, d := range deps {
+		if err := cp.addJar(d.Name, mavenJarPath(repo, d.Name, d.Version)); err != nil {
+			cmdlogger.Debugf("could not read the jar of %s@%s: %s", d.Name, d.Version, err)
+			missing++
+		}
+	}
+	if missing > 0 {
+		cmdlogger.Infof("%d packages of '%s' were not found in the local Maven repository, so could not be analyzed; run `mvn dependency:resolve` to download them", missing, source.Path)
+	}
+
+	reachable, err := cp.reachableClasses(classesDir)
+	if err != nil {
+		cmdlogger.Errorf("failed to analyze the compiled classes of '%s': %s", source.Path, err)
+		return
+	}
+	reachableArtifacts := map[string]bool{}
+	for class := range reachable {
+		if artifact, ok := cp.classes[class]; ok {
+			reachableArtifacts[artifact] = true
+		}
+	}
+
+	transitive := map[string]bool{}
+	for _, d := range deps {
+		transitive[d.Name] = transitive[d.Name] || d.Transitive
+	}
+
+	_, vulnsByID := vulnsFromAllPkgs(pkgs)
+	for _, pv := range pkgs {
+		if pv.Package.Ecosystem != string(osvconstants.EcosystemMaven) || !transitive[pv.Package.Name] {
+			continue
+		}
+		if _, analyzed := cp.artifactClasses[pv.Package.Name]; !analyzed {
+			continue
+		}
+
+		for groupIdx := range pv.Groups {
+			for _, vulnID := range pv.Groups[groupIdx].IDs {
+				var called bool
+				if classes := javaVulnerableClasses(vulnsByID[vulnID], pv.Package.Name); len(classes) > 0 {
+					called = slices.ContainsFunc(classes, func(class string) bool { return reachable[class] })
+				} else if called = reachableArtifacts[pv.Package.Name]; !called {
+					// the package could still be reached in ways that are not followed
+					continue
+				}
+
+				analysis := &pv.Groups[groupIdx].ExperimentalAnalysis
+				if *analysis == nil {
+					*analysis = make(map[string]models.AnalysisInfo)
+				}
+				(*analysis)[vulnID] = models.AnalysisInfo{
+					Called: called,
+				}
+			}
+		}
+	}
+}
+
+// javaVulnerableClasses returns the classes, in their internal form, of the
+// vulnerable symbols that an advisory lists for a Maven package in the
+// "affects" field of its ecosystem specific data, like RUSTSEC does for
+// functions:
+//
+//	"affects": {
+//	    "classes": ["org.yaml.snakeyaml.constructor.Constructor"],
+//	    "functions": ["org.yaml.snakeyaml.Yaml.load"]
+//	}
+//
+// Functions, named either "Class.method" or "Class#method", are matched on the
+// class they are in, as calls are not followed.
+func javaVulnerableClasses(vuln *osvschema.Vulnerability, name string) []string {
+	var classes []string
+	for _, affected := range vuln.GetAffected() {
+		if affected.GetPackage().GetName() != name {
+			continue
+		}
+		affects := affected.GetEcosystemSpecific().GetFields()["affects"].GetStructValue()
+		for _, v := range affects.GetFields()["classes"].GetListValue().GetValues() {
+			if class := v.GetStringValue(); class != "" {
+				classes = append(classes, strings.ReplaceAll(class, ".", "/"))
+			}
+		}
+		for _, v := range affects.GetFields()["functions"].GetListValue().GetValues() {
+			function := v.GetStringValue()
+			class, _, ok := strings.Cut(function, "#")
+			if !ok {
+				i := strings.LastIndex(function, ".")
+				if i <= 0 {
+					continue
+				}
+				class = function[:i]
+			}
+			classes = append(classes, strings.ReplaceAll(class, ".", "/"))
+		}
+	}
+
+	return classes
+}
+
+// mavenJarPath returns where the jar of a Maven package is in a local repository.
+func mavenJarPath(repo string, name string, version string) string {
+	groupID, artifactID, _ := strings.Cut(name, ":")
+
+	return filepath.Join(
+		repo,
+		filepath.FromSlash(strings.ReplaceAll(groupID, ".", "/")),
+		artifactID,
+		version,
+		artifactID+"-"+version+".jar",
+	)
+}
+
+// javaClassPath finds the classes of the jars of Maven packages.
+type javaClassPath struct {
+	jars map[string]*zip.ReadCloser
+	// classes maps the classes, in their internal form ("com/example/Foo"),
+	// to the packages whose jars contain them
+	classes map[string]string
+	// artifactClasses are the classes in the jar of each package
+	artifactClasses map[string][]string
+	// services maps service interfaces to the classes that provide them, as
+	// found by java.util.ServiceLoader
+	services map[string][]string
+}
+
+func newJavaClassPath() *javaClassPath {
+	return &javaClassPath{
+		jars:            map[string]*zip.ReadCloser{},
+		classes:         map[string]string{},
+		artifactClasses: map[string][]string{},
+		services:        map[string][]string{},
+	}
+}
+
+func (cp *javaClassPath) close() {
+	for _, jar := range cp.jars {
+		jar.Close()
+	}
+}
+
+// addJar adds the classes and service providers of the jar of a package.
+func (cp *javaClassPath) addJar(artifact string, path string) error {
+	if _, ok := cp.jars[artifact]; ok {
+		return nil
+	}
+
+	jar, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	cp.jars[artifact] = jar
+	cp.artifactClasses[artifact] = []string{}
+
+	for _, f := range jar.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if service, ok := strings.CutPrefix(f.Name, javaServicesDir); ok {
+			r, err := f.Open()
+			if err != nil {
+				continue
+			}
+			cp.addServiceProviders(service, r)
+			r.Close()
+
+			continue
+		}
+		// multi-release jars have other versions of classes under META-INF
+		class, ok := strings.CutSuffix(f.Name, ".class")
+		if !ok || strings.HasPrefix(f.Name, "META-INF/") {
+			continue
+		}
+		if _, ok := cp.classes[class]; !ok {
+			cp.classes[class] = artifact
+		}
+		cp.artifactClasses[artifact] = append(cp.artifactClasses[artifact], class)
+	}
+
+	return nil
+}
+
+// addServiceProviders adds the classes listed in a META-INF/services file.
+func (cp *javaClassPath) addServiceProviders(service string, r io.Reader) {
+	service = strings.ReplaceAll(service, ".", "/")
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		provider, _, _ := strings.Cut(scanner.Text(), "#")
+		provider = strings.TrimSpace(provider)
+		if provider != "" {
+			cp.services[service] = append(cp.services[service], strings.ReplaceAll(provider, ".", "/"))
+		}
+	}
+}
+
+// reachableClasses returns the classes that are reachable from any class in
+// classesDir.
+func (cp *javaClassPath) reachableClasses(classesDir string) (map[string]bool, error) {
+	var queue []string
+	projectClasses := map[string]string{}
+	err := filepath.WalkDir(classesDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(classesDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			return nil
+		}
+		if service, ok := strings.CutPrefix(rel, javaServicesDir); ok {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			cp.addServiceProviders(service, f)
+
+			return nil
+		}
+		if class, ok := strings.CutSuffix(rel, ".class"); ok {
+			projectClasses[class] = path
+			queue = append(queue, class)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reachable := map[string]bool{}
+	loadedEverything := map[string]bool{}
+	for len(queue) > 0 {
+		class := queue[0]
+		queue = queue[1:]
+		if reachable[class] {
+			continue
+		}
+		reachable[class] = true
+
+		artifact, inJar := cp.classes[class]
+
+		// providers of a service can be loaded wherever the service is used
+		queue = append(queue, cp.services[class]...)
+
+		cf, err := cp.openClass(class, projectClasses)
+		if err != nil {
+			// optional dependencies might not be there
+			continue
+		}
+
+		refs, loadsClasses := javaClassReferences(cf)
+		queue = append(queue, refs...)
+
+		// classes loaded by name could be any of the package's own
+		if loadsClasses && inJar && !loadedEverything[artifact] {
+			loadedEverything[artifact] = true
+			queue = append(queue, cp.artifactClasses[artifact]...)
+		}
+	}
+
+	return reachable, nil
+}
+
+// openClass parses a class of the project or of any of the jars.
+func (cp *javaClassPath) openClass(class string, projectClasses map[string]string) (*javareach.ClassFile, error) {
+	var r io.ReadCloser
+	var err error
+	if path, ok := projectClasses[class]; ok {
+		r, err = os.Open(path)
+	} else if artifact, ok := cp.classes[class]; ok {
+		r, err = cp.jars[artifact].Open(class + ".class")
+	} else {
+		return nil, fs.ErrNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return javareach.ParseClass(r)
+}
+
+// javaClassReferences returns the classes outside of the standard library that
+// a class refers to, and whether it loads classes by name.
+func javaClassReferences(cf *javareach.ClassFile) ([]string, bool) {
+	var refs []string
+	loadsClasses := false
+	for i, entry := range cf.ConstantPool {
+		if i == int(cf.ThisClass) {
+			continue
+		}
+
+		switch entry.Type() {
+		case javareach.ConstantKindClass:
+			class, err := cf.ConstantPoolClass(i)
+			if err != nil {
+				continue
+			}
+			// arrays, e.g. "[Lcom/example/Foo;" or "[I"
+			if strings.HasPrefix(class, "[") {
+				class = strings.TrimLeft(class, "[")
+				if !strings.HasPrefix(class, "L") {
+					continue
+				}
+				class = strings.TrimSuffix(class[1:], ";")
+			}
+			refs = append(refs, class)
+		case javareach.ConstantKindUtf8:
+			// descriptors and signatures of fields, methods and annotations
+			val, err := cf.ConstantPoolUtf8(i)
+			if err != nil {
+				continue
+			}
+			for _, m := range cachedregexp.MustCompile(`L([\w/$]+)[;<]`).FindAllStringSubmatch(val, -1) {
+				refs = append(refs, m[1])
+			}
+		case javareach.ConstantKindMethodref:
+			_, method, descriptor, err := cf.ConstantPoolMethodref(i)
+			if err == nil && (method == "forName" || method == "loadClass") && strings.HasSuffix(descriptor, "Ljava/lang/Class;") {
+				loadsClasses = true
+			}
+		default:
+		}
+	}
+
+	return slices.DeleteFunc(refs, javareach.IsStdLib), loadsClasses
+}
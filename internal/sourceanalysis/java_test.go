@@ -0,0 +1,234 @@
+package sourceanalysis
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// javaClass builds a class file that refers to the given classes, and calls
+// Class.forName if loadsClasses is true.
+func javaClass(t *testing.T, name string, loadsClasses bool, refs ...string) []byte {
+	t.Helper()
+
+	var pool bytes.Buffer
+	count := uint16(1)
+	utf8 := func(s string) uint16 {
+		pool.WriteByte(1)
+		_ = binary.Write(&pool, binary.BigEndian, uint16(len(s)))
+		pool.WriteString(s)
+		count++
+
+		return count - 1
+	}
+	class := func(s string) uint16 {
+		nameIdx := utf8(s)
+		pool.WriteByte(7)
+		_ = binary.Write(&pool, binary.BigEndian, nameIdx)
+		count++
+
+		return count - 1
+	}
+
+	thisClass := class(name)
+	for _, ref := range refs {
+		class(ref)
+	}
+	if loadsClasses {
+		classIdx := class("java/lang/Class")
+		nameIdx := utf8("forName")
+		descIdx := utf8("(Ljava/lang/String;)Ljava/lang/Class;")
+		pool.WriteByte(12)
+		_ = binary.Write(&pool, binary.BigEndian, nameIdx)
+		_ = binary.Write(&pool, binary.BigEndian, descIdx)
+		count++
+		pool.WriteByte(10)
+		_ = binary.Write(&pool, binary.BigEndian, classIdx)
+		_ = binary.Write(&pool, binary.BigEndian, count-1)
+		count++
+	}
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, uint32(0xCAFEBABE))
+	_ = binary.Write(&buf, binary.BigEndian, uint16(0))
+	_ = binary.Write(&buf, binary.BigEndian, uint16(52))
+	_ = binary.Write(&buf, binary.BigEndian, count)
+	buf.Write(pool.Bytes())
+	_ = binary.Write(&buf, binary.BigEndian, uint16(0x0021))
+	_ = binary.Write(&buf, binary.BigEndian, thisClass)
+
+	return buf.Bytes()
+}
+
+// writeJar writes a jar with the given files to the local Maven repository.
+func writeJar(t *testing.T, repo string, name string, version string, files map[string][]byte) {
+	t.Helper()
+
+	path := mavenJarPath(repo, name, version)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_javaAnalysis(t *testing.T) {
+	t.Parallel()
+
+	project := t.TempDir()
+	repo := t.TempDir()
+
+	classes := filepath.Join(project, "target", "classes", "com", "example")
+	if err := os.MkdirAll(classes, 0755); err != nil {
+		t.Fatal(err)
+	}
+	app := javaClass(t, "com/example/App", false, "org/lib/Client", "java/util/List")
+	if err := os.WriteFile(filepath.Join(classes, "App.class"), app, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	writeJar(t, repo, "org.lib:lib", "1.0.0", map[string][]byte{
+		"org/lib/Client.class":   javaClass(t, "org/lib/Client", false, "[Lorg/http/Request;", "org/spi/Codec"),
+		"org/lib/Unused.class":   javaClass(t, "org/lib/Unused", false, "org/yaml/Parser"),
+		"META-INF/MANIFEST.MF":   []byte("Manifest-Version: 1.0\n"),
+		"META-INF/versions/11/x": []byte(""),
+	})
+	writeJar(t, repo, "org.http:http", "2.0.0", map[string][]byte{
+		"org/http/Request.class": javaClass(t, "org/http/Request", true),
+		"org/http/Plugin.class":  javaClass(t, "org/http/Plugin", false, "org/logging/Logger"),
+	})
+	writeJar(t, repo, "org.spi:spi", "1.0.0", map[string][]byte{
+		"org/spi/Codec.class": javaClass(t, "org/spi/Codec", false),
+	})
+	writeJar(t, repo, "org.codec:codec", "1.0.0", map[string][]byte{
+		"org/codec/JSONCodec.class":           javaClass(t, "org/codec/JSONCodec", false, "org/spi/Codec"),
+		"org/codec/XMLCodec.class":            javaClass(t, "org/codec/XMLCodec", false),
+		"META-INF/services/org.spi.Codec":     []byte("# the JSON codec\norg.codec.JSONCodec\n"),
+		"META-INF/services/org.other.Service": []byte("org.codec.Other\n"),
+	})
+	writeJar(t, repo, "org.logging:logging", "1.0.0", map[string][]byte{
+		"org/logging/Logger.class": javaClass(t, "org/logging/Logger", false),
+	})
+	writeJar(t, repo, "org.yaml:yaml", "1.0.0", map[string][]byte{
+		"org/yaml/Parser.class": javaClass(t, "org/yaml/Parser", false),
+	})
+
+	// vuln is an advisory for a package, listing its vulnerable functions
+	vuln := func(id string, name string, functions ...any) *osvschema.Vulnerability {
+		affected := &osvschema.Affected{Package: &osvschema.Package{Name: name, Ecosystem: "Maven"}}
+		if len(functions) > 0 {
+			specific, err := structpb.NewStruct(map[string]any{"affects": map[string]any{"functions": functions}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			affected.EcosystemSpecific = specific
+		}
+
+		return &osvschema.Vulnerability{Id: id, Affected: []*osvschema.Affected{affected}}
+	}
+	pkg := func(name string, vulns ...*osvschema.Vulnerability) models.PackageVulns {
+		pv := models.PackageVulns{
+			Package:         models.PackageInfo{Name: name, Version: "1.0.0", Ecosystem: "Maven"},
+			Vulnerabilities: vulns,
+		}
+		for _, v := range vulns {
+			pv.Groups = append(pv.Groups, models.GroupInfo{IDs: []string{v.GetId()}})
+		}
+
+		return pv
+	}
+	pkgs := []models.PackageVulns{
+		pkg("org.lib:lib", vuln("GHSA-1", "org.lib:lib")),
+		pkg("org.http:http", vuln("GHSA-2", "org.http:http")),
+		pkg(
+			"org.codec:codec",
+			vuln("GHSA-3", "org.codec:codec"),
+			vuln("GHSA-8", "org.codec:codec", "org.codec.XMLCodec.decode"),
+		),
+		pkg("org.logging:logging", vuln("GHSA-4", "org.logging:logging", "org.logging.Logger#log")),
+		pkg(
+			"org.yaml:yaml",
+			vuln("GHSA-5", "org.yaml:yaml"),
+			vuln("GHSA-7", "org.yaml:yaml", "org.yaml.Parser.load"),
+		),
+		pkg("org.missing:missing", vuln("GHSA-6", "org.missing:missing")),
+	}
+	deps := []Dependency{
+		{Name: "org.lib:lib", Version: "1.0.0"},
+		{Name: "org.http:http", Version: "2.0.0", Transitive: true},
+		{Name: "org.spi:spi", Version: "1.0.0", Transitive: true},
+		{Name: "org.codec:codec", Version: "1.0.0", Transitive: true},
+		{Name: "org.logging:logging", Version: "1.0.0", Transitive: true},
+		{Name: "org.yaml:yaml", Version: "1.0.0", Transitive: true},
+		{Name: "org.missing:missing", Version: "1.0.0", Transitive: true},
+	}
+
+	javaAnalysis(pkgs, models.SourceInfo{Path: filepath.Join(project, "pom.xml"), Type: "lockfile"}, deps, repo)
+
+	// GHSA-1 is of a package declared in the pom.xml, so is not analyzed, and
+	// GHSA-6 of one without a jar to analyze
+	want := map[string]models.AnalysisInfo{
+		// every class of a package that loads classes by name is reachable
+		"GHSA-2": {Called: true},
+		"GHSA-3": {Called: true},
+		// the package is reachable, but not its vulnerable class
+		"GHSA-8": {Called: false},
+		"GHSA-4": {Called: true},
+		// GHSA-5 lists no vulnerable symbols, so the package is not marked as
+		// not called although it cannot be reached
+		"GHSA-7": {Called: false},
+	}
+	got := map[string]models.AnalysisInfo{}
+	for _, pv := range pkgs {
+		for _, group := range pv.Groups {
+			maps.Copy(got, group.ExperimentalAnalysis)
+		}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("javaAnalysis() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_javaAnalysis_NotCompiled(t *testing.T) {
+	t.Parallel()
+
+	pkgs := []models.PackageVulns{
+		{
+			Package: models.PackageInfo{Name: "org.yaml:yaml", Version: "1.0.0", Ecosystem: "Maven"},
+			Groups:  []models.GroupInfo{{IDs: []string{"GHSA-5"}}},
+		},
+	}
+	deps := []Dependency{{Name: "org.yaml:yaml", Version: "1.0.0", Transitive: true}}
+
+	javaAnalysis(pkgs, models.SourceInfo{Path: filepath.Join(t.TempDir(), "pom.xml"), Type: "lockfile"}, deps, t.TempDir())
+
+	if pkgs[0].Groups[0].ExperimentalAnalysis != nil {
+		t.Errorf("javaAnalysis() = %v, want no analysis", pkgs[0].Groups[0].ExperimentalAnalysis)
+	}
+}
@@ -0,0 +1,280 @@
+package sourceanalysis
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
+)
+
+var javascriptExtensions = []string{".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".mts", ".cts", ".vue", ".svelte"}
+
+// javascriptAnalysis marks the vulnerabilities of npm packages as not called
+// when the application code does not require them, nor any package that
+// depends on them, however indirectly. Tests and the configuration of tools
+// are not application code, so packages only used by dev tooling are not
+// called.
+func javascriptAnalysis(pkgs []models.PackageVulns, source models.SourceInfo, deps []Dependency) {
+	dir := filepath.Dir(source.Path)
+
+	imports, err := javascriptImports(dir)
+	if err != nil {
+		cmdlogger.Errorf("failed to read the JavaScript source code of '%s': %s", source.Path, err)
+		return
+	}
+	if len(imports) == 0 {
+		// without any source code, every package would look unused
+		cmdlogger.Infof("no JavaScript imports found next to '%s', skipping call analysis", source.Path)
+		return
+	}
+
+	var children map[string][]string
+	if filepath.Base(source.Path) == "package-lock.json" {
+		children, err = packageLockDependencies(source.Path)
+	} else {
+		children = installedNpmDependencies(dir, deps)
+	}
+	if err != nil {
+		cmdlogger.Errorf("failed to read the dependencies of '%s': %s", source.Path, err)
+		return
+	}
+	if len(children) == 0 {
+		// packages only required by other packages would all look unused
+		cmdlogger.Infof("no dependencies found for the packages of '%s', skipping call analysis; install them first", source.Path)
+		return
+	}
+
+	reachable := map[string]bool{}
+	queue := make([]string, 0, len(imports))
+	for name := range imports {
+		queue = append(queue, name)
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if reachable[name] {
+			continue
+		}
+		reachable[name] = true
+		queue = append(queue, children[name]...)
+	}
+
+	for _, pv := range pkgs {
+		if pv.Package.Ecosystem != string(osvconstants.EcosystemNPM) {
+			continue
+		}
+
+		called := reachable[pv.Package.Name]
+		for groupIdx := range pv.Groups {
+			analysis := &pv.Groups[groupIdx].ExperimentalAnalysis
+			if *analysis == nil {
+				*analysis = make(map[string]models.AnalysisInfo)
+			}
+			for _, vulnID := range pv.Groups[groupIdx].IDs {
+				(*analysis)[vulnID] = models.AnalysisInfo{
+					Called: called,
+				}
+			}
+		}
+	}
+}
+
+// isJavaScriptDevFile reports whether a file or directory holds tests, the
+// configuration of tools, or code that is not the application's own.
+func isJavaScriptDevFile(name string, isDir bool) bool {
+	if isDir {
+		switch name {
+		case "node_modules", "test", "tests", "__tests__", "__mocks__", "coverage", "dist", "build":
+			return true
+		}
+
+		return strings.HasPrefix(name, ".")
+	}
+
+	// e.g. "app.test.ts", "jest.config.js" and ".eslintrc.js"
+	return cachedregexp.MustCompile(`\.(test|spec|config|stories)\.[cm]?[jt]sx?$`).MatchString(name) ||
+		strings.HasPrefix(name, ".")
+}
+
+// javascriptImports returns the packages required or imported by the
+// application code in dir.
+func javascriptImports(dir string) (map[string]struct{}, error) {
+	imports := map[string]struct{}{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		if isJavaScriptDevFile(d.Name(), d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+		// type declarations are erased at runtime
+		if d.IsDir() || !slices.Contains(javascriptExtensions, filepath.Ext(path)) || strings.HasSuffix(path, ".d.ts") {
+			return nil
+		}
+
+		specifiers, err := javascriptFileImports(path)
+		if err != nil {
+			return err
+		}
+		for _, s := range specifiers {
+			if name := npmPackageName(s); name != "" {
+				imports[name] = struct{}{}
+			}
+		}
+
+		return nil
+	})
+
+	return imports, err
+}
+
+// javascriptFileImports returns the module specifiers of the imports, exports
+// and requires in a file.
+func javascriptFileImports(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var specifiers []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, m := range cachedregexp.MustCompile(`\b(?:from|import|require)\s*\(?\s*['"]([^'"\s]+)['"]`).FindAllStringSubmatch(line, -1) {
+			specifiers = append(specifiers, m[1])
+		}
+	}
+
+	return specifiers, scanner.Err()
+}
+
+// npmPackageName returns the package a module specifier is from, or nothing
+// for relative imports and built-in modules.
+func npmPackageName(specifier string) string {
+	if strings.HasPrefix(specifier, ".") || strings.HasPrefix(specifier, "/") || strings.HasPrefix(specifier, "node:") {
+		return ""
+	}
+
+	parts := strings.Split(specifier, "/")
+	if strings.HasPrefix(specifier, "@") {
+		if len(parts) < 2 {
+			return ""
+		}
+
+		return parts[0] + "/" + parts[1]
+	}
+
+	return parts[0]
+}
+
+// packageLockDependencies returns the names of the packages each package in
+// a package-lock.json depends on.
+func packageLockDependencies(path string) (map[string][]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	type lockPackage struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+	}
+	// lockfileVersion 1 nests the dependencies of packages inside them
+	type lockDependency struct {
+		Requires     map[string]string         `json:"requires"`
+		Dependencies map[string]lockDependency `json:"dependencies"`
+	}
+	var lockfile struct {
+		Packages     map[string]lockPackage    `json:"packages"`
+		Dependencies map[string]lockDependency `json:"dependencies"`
+	}
+	if err := json.Unmarshal(content, &lockfile); err != nil {
+		return nil, err
+	}
+
+	children := map[string][]string{}
+	if len(lockfile.Packages) > 0 {
+		for key, pkg := range lockfile.Packages {
+			_, name, found := strings.Cut(key, "node_modules/")
+			if !found {
+				// the root package and workspaces
+				continue
+			}
+			if i := strings.LastIndex(name, "/node_modules/"); i >= 0 {
+				name = name[i+len("/node_modules/"):]
+			}
+			for _, m := range []map[string]string{pkg.Dependencies, pkg.OptionalDependencies, pkg.PeerDependencies} {
+				for dep := range m {
+					children[name] = append(children[name], dep)
+				}
+			}
+		}
+
+		return children, nil
+	}
+
+	var walk func(deps map[string]lockDependency)
+	walk = func(deps map[string]lockDependency) {
+		for name, dep := range deps {
+			for child := range dep.Requires {
+				children[name] = append(children[name], child)
+			}
+			walk(dep.Dependencies)
+		}
+	}
+	walk(lockfile.Dependencies)
+
+	return children, nil
+}
+
+// installedNpmDependencies returns the names of the packages each of deps
+// depends on, according to their package.json in node_modules, for lockfiles
+// that do not record them.
+func installedNpmDependencies(dir string, deps []Dependency) map[string][]string {
+	children := map[string][]string{}
+	for _, d := range deps {
+		if _, ok := children[d.Name]; ok {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, "node_modules", filepath.FromSlash(d.Name), "package.json"))
+		if err != nil {
+			continue
+		}
+		var manifest struct {
+			Dependencies         map[string]string `json:"dependencies"`
+			OptionalDependencies map[string]string `json:"optionalDependencies"`
+			PeerDependencies     map[string]string `json:"peerDependencies"`
+		}
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			continue
+		}
+		children[d.Name] = []string{}
+		for _, m := range []map[string]string{manifest.Dependencies, manifest.OptionalDependencies, manifest.PeerDependencies} {
+			for dep := range m {
+				children[d.Name] = append(children[d.Name], dep)
+			}
+		}
+	}
+
+	return children
+}
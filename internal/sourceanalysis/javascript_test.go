@@ -0,0 +1,84 @@
+package sourceanalysis
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func Test_javascriptImports(t *testing.T) {
+	t.Parallel()
+
+	got, err := javascriptImports("testdata/javascript")
+	if err != nil {
+		t.Fatalf("javascriptImports() error = %v", err)
+	}
+
+	// tests, the configuration of tools and node_modules are left out
+	want := map[string]struct{}{
+		"express":     {},
+		"@scope/util": {},
+		"lodash":      {},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("javascriptImports() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_javascriptAnalysis(t *testing.T) {
+	t.Parallel()
+
+	pkg := func(name string, id string) models.PackageVulns {
+		return models.PackageVulns{
+			Package: models.PackageInfo{Name: name, Version: "1.0.0", Ecosystem: "npm"},
+			Groups:  []models.GroupInfo{{IDs: []string{id}}},
+		}
+	}
+	pkgs := []models.PackageVulns{
+		pkg("express", "GHSA-1"),
+		pkg("qs", "GHSA-2"),
+		pkg("jest", "GHSA-3"),
+		pkg("semver", "GHSA-4"),
+		pkg("lodash", "GHSA-5"),
+	}
+
+	javascriptAnalysis(pkgs, models.SourceInfo{Path: "testdata/javascript/package-lock.json", Type: "lockfile"}, nil)
+
+	want := map[string]map[string]models.AnalysisInfo{
+		"express": {"GHSA-1": {Called: true}},
+		"qs":      {"GHSA-2": {Called: true}},
+		"jest":    {"GHSA-3": {Called: false}},
+		"semver":  {"GHSA-4": {Called: false}},
+		"lodash":  {"GHSA-5": {Called: true}},
+	}
+	got := map[string]map[string]models.AnalysisInfo{}
+	for _, pv := range pkgs {
+		got[pv.Package.Name] = pv.Groups[0].ExperimentalAnalysis
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("javascriptAnalysis() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_npmPackageName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		specifier string
+		want      string
+	}{
+		{specifier: "express", want: "express"},
+		{specifier: "lodash/get", want: "lodash"},
+		{specifier: "@babel/core", want: "@babel/core"},
+		{specifier: "@babel/core/lib/config", want: "@babel/core"},
+		{specifier: "./helper", want: ""},
+		{specifier: "../lib", want: ""},
+		{specifier: "node:fs", want: ""},
+	}
+	for _, tt := range tests {
+		if got := npmPackageName(tt.specifier); got != tt.want {
+			t.Errorf("npmPackageName(%q) = %q, want %q", tt.specifier, got, tt.want)
+		}
+	}
+}
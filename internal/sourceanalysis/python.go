@@ -0,0 +1,238 @@
+package sourceanalysis
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
+)
+
+// pythonImportNames are the modules of packages whose import names cannot be
+// guessed from their project names.
+var pythonImportNames = map[string][]string{
+	"attrs":                  {"attr", "attrs"},
+	"beautifulsoup4":         {"bs4"},
+	"djangorestframework":    {"rest_framework"},
+	"dnspython":              {"dns"},
+	"grpcio":                 {"grpc"},
+	"msgpack-python":         {"msgpack"},
+	"opencv-python":          {"cv2"},
+	"opencv-python-headless": {"cv2"},
+	"pillow":                 {"pil"},
+	"protobuf":               {"google.protobuf"},
+	"pycryptodome":           {"crypto"},
+	"pycryptodomex":          {"cryptodome"},
+	"pyopenssl":              {"openssl"},
+	"pywin32":                {"win32api", "win32con", "pywintypes"},
+	"pyzmq":                  {"zmq"},
+	"scikit-image":           {"skimage"},
+	"scikit-learn":           {"sklearn"},
+	"setuptools":             {"setuptools", "pkg_resources"},
+}
+
+// pythonAnalysis marks the vulnerabilities of transitive PyPI packages as not
+// called when neither they nor any of the packages depending on them, however
+// indirectly, are imported by the project.
+func pythonAnalysis(pkgs []models.PackageVulns, source models.SourceInfo, deps []Dependency) {
+	if !slices.ContainsFunc(deps, func(d Dependency) bool { return d.Transitive }) {
+		// only transitive packages are analyzed
+		return
+	}
+
+	imports, err := pythonImports(filepath.Dir(source.Path))
+	if err != nil {
+		cmdlogger.Errorf("failed to read the Python source code of '%s': %s", source.Path, err)
+		return
+	}
+	if len(imports) == 0 {
+		// without any source code, every package would look unused
+		cmdlogger.Infof("no Python imports found next to '%s', skipping call analysis", source.Path)
+		return
+	}
+
+	// walk down the graph from every imported package to find those they
+	// depend on
+	children := map[string][]string{}
+	transitive := map[string]bool{}
+	var queue []string
+	for _, d := range deps {
+		name := normalizePythonName(d.Name)
+		queue = append(queue, name)
+		if !d.Transitive {
+			continue
+		}
+		transitive[name] = true
+		for _, p := range d.Parents {
+			p = normalizePythonName(p)
+			children[p] = append(children[p], name)
+			queue = append(queue, p)
+		}
+	}
+	queue = slices.DeleteFunc(queue, func(name string) bool {
+		return !isPythonPackageImported(name, imports)
+	})
+	reachable := map[string]bool{}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if reachable[name] {
+			continue
+		}
+		reachable[name] = true
+		queue = append(queue, children[name]...)
+	}
+
+	for _, pv := range pkgs {
+		name := normalizePythonName(pv.Package.Name)
+		if !transitive[name] || pv.Package.Ecosystem != string(osvconstants.EcosystemPyPI) {
+			continue
+		}
+
+		imported := reachable[name]
+		for groupIdx := range pv.Groups {
+			analysis := &pv.Groups[groupIdx].ExperimentalAnalysis
+			if *analysis == nil {
+				*analysis = make(map[string]models.AnalysisInfo)
+			}
+			for _, vulnID := range pv.Groups[groupIdx].IDs {
+				(*analysis)[vulnID] = models.AnalysisInfo{
+					Called: imported,
+				}
+			}
+		}
+	}
+}
+
+// normalizePythonName normalizes a PyPI project name as described in PEP 503.
+func normalizePythonName(name string) string {
+	return strings.ToLower(cachedregexp.MustCompile(`[-_.]+`).ReplaceAllLiteralString(name, "-"))
+}
+
+// isPythonPackageImported reports whether any of the modules a PyPI package
+// is likely to provide are imported.
+func isPythonPackageImported(name string, imports map[string]struct{}) bool {
+	candidates := append([]string{
+		strings.ReplaceAll(name, "-", "_"),
+		// namespace packages, e.g. "google-cloud-storage"
+		strings.ReplaceAll(name, "-", "."),
+	}, pythonImportNames[name]...)
+	if trimmed, ok := strings.CutPrefix(name, "python-"); ok {
+		candidates = append(candidates, strings.ReplaceAll(trimmed, "-", "_"))
+	}
+	// e.g. "pyyaml" and "pyjwt"
+	if trimmed, ok := strings.CutPrefix(name, "py"); ok {
+		candidates = append(candidates, strings.ReplaceAll(trimmed, "-", "_"))
+	}
+
+	for _, c := range candidates {
+		if _, ok := imports[c]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pythonImports returns every module imported by the Python files in dir,
+// along with each of their parent packages, in lower case.
+func pythonImports(dir string) (map[string]struct{}, error) {
+	imports := map[string]struct{}{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && skipPythonDir(path, d.Name()) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+		if filepath.Ext(path) != ".py" {
+			return nil
+		}
+
+		modules, err := pythonFileImports(path)
+		if err != nil {
+			return err
+		}
+		for _, m := range modules {
+			parts := strings.Split(strings.ToLower(m), ".")
+			for i := range parts {
+				imports[strings.Join(parts[:i+1], ".")] = struct{}{}
+			}
+		}
+
+		return nil
+	})
+
+	return imports, err
+}
+
+// skipPythonDir reports whether a directory holds code other than the
+// project's own, such as an installed virtual environment.
+func skipPythonDir(path string, name string) bool {
+	if strings.HasPrefix(name, ".") || name == "__pycache__" || name == "node_modules" || name == "site-packages" {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(path, "pyvenv.cfg"))
+
+	return err == nil
+}
+
+// pythonFileImports returns the absolute imports of a Python file, including
+// those made dynamically with a literal module name.
+func pythonFileImports(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var modules []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if m := cachedregexp.MustCompile(`^\s*from\s+([\w.]+)\s+import\s*\(?(.*)`).FindStringSubmatch(line); m != nil {
+			// relative imports are of the project's own modules
+			if !strings.HasPrefix(m[1], ".") {
+				modules = append(modules, m[1])
+				// the names imported can be submodules, e.g. of namespace packages
+				for _, name := range importedNames(m[2]) {
+					modules = append(modules, m[1]+"."+name)
+				}
+			}
+		} else if m := cachedregexp.MustCompile(`^\s*import\s+(.+)`).FindStringSubmatch(line); m != nil {
+			modules = append(modules, importedNames(m[1])...)
+		}
+
+		for _, m := range cachedregexp.MustCompile(`(?:import_module|__import__)\(\s*["']([\w.]+)["']`).FindAllStringSubmatch(line, -1) {
+			modules = append(modules, m[1])
+		}
+	}
+
+	return modules, scanner.Err()
+}
+
+// importedNames returns the names in the comma separated list of an import
+// statement, without any aliases they are given.
+func importedNames(list string) []string {
+	var names []string
+	for _, imp := range strings.Split(list, ",") {
+		if name := cachedregexp.MustCompile(`^\s*([\w.]+)`).FindStringSubmatch(imp); name != nil {
+			names = append(names, name[1])
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,86 @@
+package sourceanalysis
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func Test_pythonImports(t *testing.T) {
+	t.Parallel()
+
+	got, err := pythonImports("testdata/python")
+	if err != nil {
+		t.Fatalf("pythonImports() error = %v", err)
+	}
+
+	// the virtual environments are not the project's own code
+	want := map[string]struct{}{
+		"os":                      {},
+		"sys":                     {},
+		"requests":                {},
+		"google":                  {},
+		"google.cloud":            {},
+		"google.cloud.storage":    {},
+		"importlib.import_module": {},
+		"yaml":                    {},
+		"importlib":               {},
+		"pil":                     {},
+		"pil.image":               {},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("pythonImports() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_pythonAnalysis(t *testing.T) {
+	t.Parallel()
+
+	pkg := func(name string, id string) models.PackageVulns {
+		return models.PackageVulns{
+			Package: models.PackageInfo{Name: name, Version: "1.0.0", Ecosystem: "PyPI"},
+			Groups:  []models.GroupInfo{{IDs: []string{id}}},
+		}
+	}
+
+	pkgs := []models.PackageVulns{
+		pkg("flask", "PYSEC-1"),
+		pkg("urllib3", "PYSEC-2"),
+		pkg("MarkupSafe", "PYSEC-3"),
+		pkg("idna", "PYSEC-4"),
+		pkg("google-cloud-core", "PYSEC-5"),
+		pkg("docutils", "PYSEC-6"),
+	}
+	deps := []Dependency{
+		{Name: "requests"},
+		{Name: "flask"},
+		{Name: "sphinx"},
+		{Name: "urllib3", Transitive: true, Parents: []string{"requests"}},
+		{Name: "idna", Transitive: true, Parents: []string{"requests", "sphinx"}},
+		{Name: "jinja2", Transitive: true, Parents: []string{"flask", "sphinx"}},
+		{Name: "markupsafe", Transitive: true, Parents: []string{"jinja2", "werkzeug"}},
+		{Name: "werkzeug", Transitive: true, Parents: []string{"flask", "markupsafe"}},
+		{Name: "google-cloud-core", Transitive: true, Parents: []string{"google-cloud-storage"}},
+		{Name: "docutils", Transitive: true, Parents: []string{"sphinx"}},
+	}
+
+	pythonAnalysis(pkgs, models.SourceInfo{Path: "testdata/python/requirements.txt", Type: "lockfile"}, deps)
+
+	want := map[string]map[string]models.AnalysisInfo{
+		// declared in the manifest, so not analyzed
+		"flask":             nil,
+		"urllib3":           {"PYSEC-2": {Called: true}},
+		"MarkupSafe":        {"PYSEC-3": {Called: false}},
+		"idna":              {"PYSEC-4": {Called: true}},
+		"google-cloud-core": {"PYSEC-5": {Called: true}},
+		"docutils":          {"PYSEC-6": {Called: false}},
+	}
+	got := map[string]map[string]models.AnalysisInfo{}
+	for _, pv := range pkgs {
+		got[pv.Package.Name] = pv.Groups[0].ExperimentalAnalysis
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("pythonAnalysis() mismatch (-want +got):\n%s", diff)
+	}
+}
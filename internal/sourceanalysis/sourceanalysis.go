@@ -2,11 +2,16 @@ package sourceanalysis
 
 import (
 	"path/filepath"
+	"slices"
 
 	"github.com/google/osv-scanner/v2/pkg/models"
 	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
+// npmLockfiles are the lockfiles of npm packages that JavaScript call analysis
+// is done for.
+var npmLockfiles = []string{"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "bun.lock"}
+
 // vulnsFromAllPkgs returns the flattened list of unique vulnerabilities
 func vulnsFromAllPkgs(pkgs []models.PackageVulns) ([]*osvschema.Vulnerability, map[string]*osvschema.Vulnerability) {
 	flatVulns := map[string]*osvschema.Vulnerability{}
@@ -24,8 +29,22 @@ func vulnsFromAllPkgs(pkgs []models.PackageVulns) ([]*osvschema.Vulnerability, m
 	return vulns, flatVulns
 }
 
-// Run runs the language specific analyzers on the code given packages and source info
-func Run(source models.SourceInfo, pkgs []models.PackageVulns, callAnalysis map[string]bool) {
+// Dependency is one of the packages found at a source, whether or not it has
+// any vulnerabilities.
+type Dependency struct {
+	Name    string
+	Version string
+	// Transitive is true if the package is only depended on by other packages,
+	// rather than being declared by the project
+	Transitive bool
+	// Parents are the names of the packages that directly depend on this one,
+	// if transitive dependency resolution found them
+	Parents []string
+}
+
+// Run runs the language specific analyzers on the code given packages and source info.
+// deps are all the packages found at the source.
+func Run(source models.SourceInfo, pkgs []models.PackageVulns, deps []Dependency, callAnalysis map[string]bool) {
 	// GoVulnCheck
 	if source.Type == "lockfile" && filepath.Base(source.Path) == "go.mod" && callAnalysis["go"] {
 		goAnalysis(pkgs, source)
@@ -34,4 +53,16 @@ func Run(source models.SourceInfo, pkgs []models.PackageVulns, callAnalysis map[
 	if source.Type == "lockfile" && filepath.Base(source.Path) == "Cargo.lock" && callAnalysis["rust"] {
 		rustAnalysis(pkgs, source)
 	}
+
+	if source.Type == "lockfile" && filepath.Base(source.Path) == "pom.xml" && callAnalysis["java"] {
+		javaAnalysis(pkgs, source, deps, mavenLocalRepository())
+	}
+
+	if source.Type == "lockfile" && slices.Contains(npmLockfiles, filepath.Base(source.Path)) && callAnalysis["javascript"] {
+		javascriptAnalysis(pkgs, source, deps)
+	}
+
+	if source.Type == "lockfile" && callAnalysis["python"] {
+		pythonAnalysis(pkgs, source, deps)
+	}
 }
@@ -0,0 +1 @@
+module.exports = { preset: require.resolve("ts-jest") };
@@ -0,0 +1,7 @@
+import express from "express";
+import type { Request } from "express";
+import { helper } from "./lib/helper";
+export { format } from '@scope/util/format';
+
+const app = express();
+app.get("/", (req: Request) => helper(req));
@@ -0,0 +1,4 @@
+const _ = require('lodash');
+const fs = require("node:fs");
+
+module.exports.helper = (req) => _.get(req, "query");
@@ -0,0 +1 @@
+const { expect } = require("jest");
@@ -0,0 +1 @@
+import { describe } from "jest";
@@ -0,0 +1 @@
+import jinja2
@@ -0,0 +1,10 @@
+import os, sys
+import requests as rq  # the HTTP client
+from . import views
+from google.cloud import storage
+
+yaml = __import__("yaml")
+
+
+def main():
+    rq.get("https://example.com")
@@ -0,0 +1,4 @@
+from .main import main
+from importlib import import_module
+
+image = import_module("PIL.Image")
@@ -13,6 +13,8 @@ import (
 type node interface {
 	// satisfiedBy checks if the given licenses satisfy the license expression represented by this node
 	satisfiedBy(licenses []string) bool
+	// ids returns the license ids in the license expression represented by this node
+	ids() []string
 }
 
 // nodeBranch represents a node in the tree that has two children, which should be
@@ -34,6 +36,10 @@ func (n nodeBranch) satisfiedBy(licenses []string) bool {
 	return false
 }
 
+func (n nodeBranch) ids() []string {
+	return append(n.left.ids(), n.right.ids()...)
+}
+
 var _ node = nodeBranch{}
 
 // nodeLeaf represents a leaf node in the tree, which holds a single license id
@@ -53,6 +59,10 @@ func (n nodeLeaf) satisfiedBy(licenses []string) bool {
 	return false
 }
 
+func (n nodeLeaf) ids() []string {
+	return []string{n.value}
+}
+
 var _ node = nodeLeaf{}
 
 type tokens struct {
@@ -260,3 +270,29 @@ func Satisfies(license models.License, allowlist []string) (bool, error) {
 
 	return nod.satisfiedBy(allowlist), nil
 }
+
+// Denied checks if the given license expression can only be satisfied by using
+// at least one of the denied licenses. A license with an exception is denied
+// if the license itself is, e.g. "GPL-2.0-only WITH Classpath-exception-2.0"
+// is denied by "GPL-2.0-only".
+func Denied(license models.License, denylist []string) (bool, error) {
+	tokens := tokenise(license)
+	nod, err := parse(&tokens)
+
+	if err != nil {
+		return false, err
+	}
+
+	var remaining []string
+	for _, id := range nod.ids() {
+		base, _, _ := strings.Cut(id, " WITH ")
+		isDenied := slices.ContainsFunc(denylist, func(denied string) bool {
+			return strings.EqualFold(denied, id) || strings.EqualFold(denied, base)
+		})
+		if !isDenied {
+			remaining = append(remaining, id)
+		}
+	}
+
+	return !nod.satisfiedBy(remaining), nil
+}
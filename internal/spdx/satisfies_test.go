@@ -418,3 +418,40 @@ func TestSatisfies_Invalid(t *testing.T) {
 		})
 	}
 }
+
+func TestDenied(t *testing.T) {
+	t.Parallel()
+
+	denylist := []string{"GPL-3.0-only", "agpl-3.0-only"}
+	tests := []struct {
+		license models.License
+		want    bool
+	}{
+		{license: "MIT", want: false},
+		{license: "UNKNOWN", want: false},
+		{license: "GPL-3.0-only", want: true},
+		{license: "AGPL-3.0-only", want: true},
+		{license: "GPL-3.0-or-later", want: false},
+		{license: "MIT OR GPL-3.0-only", want: false},
+		{license: "MIT AND GPL-3.0-only", want: true},
+		{license: "GPL-3.0-only OR AGPL-3.0-only", want: true},
+		{license: "(MIT OR GPL-3.0-only) AND Apache-2.0", want: false},
+		{license: "(MIT AND GPL-3.0-only) OR (Apache-2.0 AND AGPL-3.0-only)", want: true},
+		{license: "GPL-3.0-only WITH GCC-exception-3.1", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.license), func(t *testing.T) {
+			t.Parallel()
+
+			got, err := spdx.Denied(tt.license, denylist)
+
+			if err != nil {
+				t.Errorf("Denied(\"%s\") = %v, want %v", tt.license, err, nil)
+			}
+
+			if got != tt.want {
+				t.Errorf("Denied(\"%s\") = %v, want %v", tt.license, got, tt.want)
+			}
+		})
+	}
+}
@@ -47,39 +47,37 @@ var (
 	}
 	// AnyDiffID truncates diff ids in image layer metadata to just `sha256:...`
 	AnyDiffID = JSONReplaceRule{
-		Path: "image_metadata.layer_metadata.#.diff_id",
-		ReplaceFunc: func(toReplace gjson.Result) any {
-			if len(toReplace.String()) > 7 {
-				return toReplace.String()[:7] + "..."
-			}
-
-			return ""
-		},
+		Path:        "image_metadata.layer_metadata.#.diff_id",
+		ReplaceFunc: anyDiffID,
+	}
+	// AnyOriginDiffID truncates the diff ids of the layers that packages were
+	// introduced in to just `sha256:...`
+	AnyOriginDiffID = JSONReplaceRule{
+		Path:        "results.#.packages.#.package.image_origin_details.diff_id",
+		ReplaceFunc: anyDiffID,
 	}
 	// ShortenHistoryCommandLength truncates COMMAND data to 28 characters
 	ShortenHistoryCommandLength = JSONReplaceRule{
-		Path: "image_metadata.layer_metadata.#.command",
-		ReplaceFunc: func(toReplace gjson.Result) any {
-			if len(toReplace.String()) > 28 {
-				return toReplace.String()[:25] + "..."
-			}
-
-			return toReplace.String()
-		},
+		Path:        "image_metadata.layer_metadata.#.command",
+		ReplaceFunc: shortenHistoryCommand,
+	}
+	// ShortenOriginCommandLength truncates the COMMAND of the layers that
+	// packages were introduced in to 28 characters
+	ShortenOriginCommandLength = JSONReplaceRule{
+		Path:        "results.#.packages.#.package.image_origin_details.command",
+		ReplaceFunc: shortenHistoryCommand,
 	}
 	// NormalizeHistoryCommand replaces COMMAND data to be consistent
 	// across different versions of docker
 	NormalizeHistoryCommand = JSONReplaceRule{
-		Path: "image_metadata.layer_metadata.#.command",
-		ReplaceFunc: func(toReplace gjson.Result) any {
-			str := toReplace.String()
-			nopMatcher := cachedregexp.MustCompile(`^/bin/sh -c #\(nop\)\s+`)
-			runMatcher := cachedregexp.MustCompile(`^/bin/sh -c\s+`)
-			str = nopMatcher.ReplaceAllLiteralString(str, "")
-			str = runMatcher.ReplaceAllString(str, "RUN \\0")
-
-			return str
-		},
+		Path:        "image_metadata.layer_metadata.#.command",
+		ReplaceFunc: normalizeHistoryCommand,
+	}
+	// NormalizeOriginCommand replaces the COMMAND of the layers that packages
+	// were introduced in to be consistent across different versions of docker
+	NormalizeOriginCommand = JSONReplaceRule{
+		Path:        "results.#.packages.#.package.image_origin_details.command",
+		ReplaceFunc: normalizeHistoryCommand,
 	}
 
 	// NormalizeCreateDateSPDX replaces the created date with a placeholder date
@@ -102,6 +100,32 @@ var (
 	}
 )
 
+func anyDiffID(toReplace gjson.Result) any {
+	if len(toReplace.String()) > 7 {
+		return toReplace.String()[:7] + "..."
+	}
+
+	return ""
+}
+
+func shortenHistoryCommand(toReplace gjson.Result) any {
+	if len(toReplace.String()) > 28 {
+		return toReplace.String()[:25] + "..."
+	}
+
+	return toReplace.String()
+}
+
+func normalizeHistoryCommand(toReplace gjson.Result) any {
+	str := toReplace.String()
+	nopMatcher := cachedregexp.MustCompile(`^/bin/sh -c #\(nop\)\s+`)
+	runMatcher := cachedregexp.MustCompile(`^/bin/sh -c\s+`)
+	str = nopMatcher.ReplaceAllLiteralString(str, "")
+	str = runMatcher.ReplaceAllString(str, "RUN \\0")
+
+	return str
+}
+
 func expandArrayPaths(t *testing.T, jsonInput string, path string) []string {
 	t.Helper()
 
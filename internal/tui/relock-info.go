@@ -25,9 +25,18 @@ func NewRelockInfo(change resolution.Difference) *relockInfo {
 	preamble := strings.Builder{}
 	preamble.WriteString("The following upgrades:\n")
 	for _, dep := range change.Deps {
+		if dep.OrigRequire == "" { // override patches do not change a requirement
+			preamble.WriteString(fmt.Sprintf("  %s@%s → @%s\n", dep.Pkg.Name, dep.OrigResolved, dep.NewResolved))
+			continue
+		}
 		preamble.WriteString(fmt.Sprintf("  %s@%s (%s) → @%s (%s)\n", // TODO: styling
 			dep.Pkg.Name, dep.OrigRequire, dep.OrigResolved, dep.NewRequire, dep.NewResolved))
 	}
+	if change.Original != nil && change.New != nil {
+		gc := change.GraphChanges()
+		preamble.WriteString(fmt.Sprintf("Will change %d packages in the dependency graph (%d added, %d removed, %d changed)\n",
+			gc.Added+gc.Removed+gc.Changed, gc.Added, gc.Removed, gc.Changed))
+	}
 	preamble.WriteString("Will resolve the following:")
 	fixedVulns := make([]*resolution.Vulnerability, len(change.RemovedVulns))
 	for i := range change.RemovedVulns {
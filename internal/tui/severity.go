@@ -27,7 +27,10 @@ func RenderSeverity(severities []*osvschema.Severity) string {
 	text := "UNKNOWN"
 	score, rating, _ := severity.CalculateOverallScore(severities)
 	if rating != "UNKNOWN" {
-		text = fmt.Sprintf("%1.1f %s", score, rating)
+		text = rating
+		if score >= 0 {
+			text = fmt.Sprintf("%1.1f %s", score, rating)
+		}
 	}
 
 	return severityStyle.Width(16).Background(severityColor[rating]).Render(text)
@@ -38,6 +41,8 @@ func RenderSeverityShort(severities []*osvschema.Severity) string {
 	scoreStr := fmt.Sprintf("%1.1f", score)
 	if rating == "UNKNOWN" {
 		scoreStr = "???"
+	} else if score < 0 {
+		scoreStr = rating[:1]
 	}
 
 	return severityStyle.Width(5).Background(severityColor[rating]).Render(scoreStr)
@@ -0,0 +1,108 @@
+package gradle
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+)
+
+// ParseBuildScript reads the versions of the dependencies declared in a Groovy or Kotlin Gradle build script
+// (build.gradle or build.gradle.kts) with "group:artifact:version" string notation.
+//
+// Versions are either written inline, or interpolated from a string constant defined in the same script
+// (e.g. def jacksonVersion = '2.13.0', ext.jacksonVersion = '2.13.0', or val jacksonVersion = "2.13.0").
+// Constants defined elsewhere (e.g. in gradle.properties) and dependencies declared in map notation are skipped.
+func ParseBuildScript(data []byte) ([]Declaration, error) {
+	script := string(data)
+	constants := buildScriptConstants(script)
+
+	var inline []Declaration
+	// "group:artifact:version", optionally followed by a classifier and an @extension.
+	depRe := cachedregexp.MustCompile(`(["'])([\w.\-]+):([\w.\-]+):(\$\{?[\w.]+\}?|[^"'\s:@$]+)(?::[\w.\-]+)?(?:@\w+)?(["'])`)
+	for _, m := range depRe.FindAllStringSubmatchIndex(script, -1) {
+		if script[m[2]:m[3]] != script[m[10]:m[11]] || inLineComment(script, m[0]) {
+			// mismatched quotes, or commented out
+			continue
+		}
+		name := script[m[4]:m[5]] + ":" + script[m[6]:m[7]]
+		version := script[m[8]:m[9]]
+
+		if ref, ok := strings.CutPrefix(version, "$"); ok {
+			// Only double-quoted strings are interpolated.
+			if script[m[2]:m[3]] != `"` {
+				continue
+			}
+			ref = strings.Trim(ref, "{}")
+			// e.g. ${rootProject.ext.jacksonVersion}
+			ref = ref[strings.LastIndex(ref, ".")+1:]
+			if c, ok := constants[ref]; ok && !slices.Contains(c.Dependencies, name) {
+				c.Dependencies = append(c.Dependencies, name)
+			}
+
+			continue
+		}
+
+		if !isPlainVersion(version) {
+			continue
+		}
+		inline = append(inline, Declaration{
+			Version:      version,
+			Dependencies: []string{name},
+			Line:         lineNumber(script, m[8]),
+			offset:       m[8],
+		})
+	}
+
+	versions := inline
+	for _, c := range constants {
+		if len(c.Dependencies) > 0 {
+			versions = append(versions, *c)
+		}
+	}
+	slices.SortFunc(versions, func(a, b Declaration) int { return cmp.Compare(a.offset, b.offset) })
+
+	return versions, nil
+}
+
+// buildScriptConstants finds the string constants that are defined in a build script, keyed by name.
+// If a constant is defined more than once, the first definition is used.
+func buildScriptConstants(script string) map[string]*Declaration {
+	constRes := []string{
+		// def foo = '1.0', val foo = "1.0", val foo: String = "1.0", ext.foo = '1.0', project.ext.foo = '1.0',
+		// or foo = '1.0' in an ext { } block
+		`(?m)^[ \t]*(?:(?:def|val|var)[ \t]+|(?:project\.)?ext\.)?(\w+)[ \t]*(?::[ \t]*String[ \t]*)?=[ \t]*(["'])([^"'$\n]*)(["'])`,
+		// val foo by extra("1.0")
+		`(?m)^[ \t]*val[ \t]+(\w+)(?:[ \t]*:[ \t]*String)?[ \t]+by[ \t]+extra\([ \t]*(")([^"$\n]*)(")`,
+		// extra["foo"] = "1.0"
+		`(?m)^[ \t]*extra\[[ \t]*"(\w+)"[ \t]*\][ \t]*=[ \t]*(")([^"$\n]*)(")`,
+	}
+
+	constants := make(map[string]*Declaration)
+	for _, re := range constRes {
+		for _, m := range cachedregexp.MustCompile(re).FindAllStringSubmatchIndex(script, -1) {
+			name, version := script[m[2]:m[3]], script[m[6]:m[7]]
+			if script[m[4]:m[5]] != script[m[8]:m[9]] || !isPlainVersion(version) {
+				continue
+			}
+			if c, ok := constants[name]; ok && c.offset < m[6] {
+				continue
+			}
+			constants[name] = &Declaration{
+				Name:    name,
+				Version: version,
+				Line:    lineNumber(script, m[6]),
+				offset:  m[6],
+			}
+		}
+	}
+
+	return constants
+}
+
+// inLineComment reports whether the byte at offset is after a // on its line.
+func inLineComment(script string, offset int) bool {
+	lineStart := strings.LastIndexByte(script[:offset], '\n') + 1
+	return strings.Contains(script[lineStart:offset], "//")
+}
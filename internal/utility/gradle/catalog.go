@@ -0,0 +1,210 @@
+package gradle
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ParseVersionCatalog reads the versions of the libraries declared in a Gradle version catalog
+// (e.g. gradle/libs.versions.toml), both from the [versions] table and inline in the [libraries] table.
+//
+// Only single versions are read: dynamic versions, ranges, and rich versions
+// that combine several constraints cannot be bumped to a fixed version and are skipped.
+// Entries of the [versions] table that no library uses (e.g. plugin versions) are also skipped.
+func ParseVersionCatalog(data []byte) ([]Declaration, error) {
+	var named []Declaration // the versions declared in the [versions] table
+	var inline []Declaration
+	type versionRef struct {
+		name string
+		dep  string
+	}
+	var refs []versionRef
+
+	section := ""
+	lineStart := 0
+	lineNum := 0
+	for line := range strings.Lines(string(data)) {
+		start := lineStart
+		lineStart += len(line)
+		lineNum++
+
+		content := stripTOMLComment(line)
+		trimmed := strings.TrimSpace(content)
+		if strings.HasPrefix(trimmed, "[") {
+			section = strings.TrimSpace(strings.Trim(trimmed, "[]"))
+			continue
+		}
+		if section != "versions" && section != "libraries" {
+			continue
+		}
+		rawKey, rawValue, ok := strings.Cut(content, "=")
+		if !ok {
+			continue
+		}
+		key := strings.Trim(strings.TrimSpace(rawKey), `"'`)
+		valueStart := start + len(rawKey) + 1
+
+		var entry struct{ V any }
+		if _, err := toml.Decode("V = "+strings.TrimSpace(rawValue), &entry); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		if section == "versions" {
+			v, ok := catalogVersion(entry.V)
+			if !ok {
+				continue
+			}
+			offset := findQuoted(data, valueStart, lineStart, v)
+			if offset < 0 {
+				continue
+			}
+			named = append(named, Declaration{Name: key, Version: v, Line: lineNum, offset: offset})
+
+			continue
+		}
+
+		switch lib := entry.V.(type) {
+		case string:
+			// group:artifact:version
+			parts := strings.Split(lib, ":")
+			if len(parts) != 3 || !isPlainVersion(parts[2]) {
+				continue
+			}
+			offset := findQuoted(data, valueStart, lineStart, lib)
+			if offset < 0 {
+				continue
+			}
+			inline = append(inline, Declaration{
+				Version:      parts[2],
+				Dependencies: []string{parts[0] + ":" + parts[1]},
+				Line:         lineNum,
+				offset:       offset + len(parts[0]) + len(parts[1]) + 2,
+			})
+		case map[string]any:
+			name, ok := catalogModule(lib)
+			if !ok {
+				continue
+			}
+			if ref, ok := catalogVersionRef(lib["version"]); ok {
+				refs = append(refs, versionRef{name: ref, dep: name})
+				continue
+			}
+			v, ok := catalogVersion(lib["version"])
+			if !ok {
+				continue
+			}
+			// The version is the first string with its value after the version key.
+			versionKey := strings.Index(string(data[valueStart:lineStart]), "version")
+			if versionKey < 0 {
+				continue
+			}
+			offset := findQuoted(data, valueStart+versionKey, lineStart, v)
+			if offset < 0 {
+				continue
+			}
+			inline = append(inline, Declaration{
+				Version:      v,
+				Dependencies: []string{name},
+				Line:         lineNum,
+				offset:       offset,
+			})
+		}
+	}
+
+	for _, r := range refs {
+		i := slices.IndexFunc(named, func(v Declaration) bool { return v.Name == r.name })
+		if i >= 0 && !slices.Contains(named[i].Dependencies, r.dep) {
+			named[i].Dependencies = append(named[i].Dependencies, r.dep)
+		}
+	}
+	named = slices.DeleteFunc(named, func(v Declaration) bool { return len(v.Dependencies) == 0 })
+
+	versions := slices.Concat(named, inline)
+	slices.SortFunc(versions, func(a, b Declaration) int { return cmp.Compare(a.offset, b.offset) })
+
+	return versions, nil
+}
+
+// catalogModule returns the Maven name of a library declared with either
+// module = "group:artifact" or group = "group", name = "artifact".
+func catalogModule(lib map[string]any) (string, bool) {
+	if module, ok := lib["module"].(string); ok {
+		return module, strings.Count(module, ":") == 1
+	}
+	group, ok := lib["group"].(string)
+	if !ok {
+		return "", false
+	}
+	name, ok := lib["name"].(string)
+	if !ok {
+		return "", false
+	}
+
+	return group + ":" + name, true
+}
+
+// catalogVersion returns the single version that a version in the catalog declares.
+// Versions are either a string, or a rich version with exactly one required or strict version.
+func catalogVersion(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, isPlainVersion(v)
+	case map[string]any:
+		if len(v) != 1 {
+			return "", false
+		}
+		for _, k := range []string{"require", "strictly"} {
+			if s, ok := v[k].(string); ok {
+				return s, isPlainVersion(s)
+			}
+		}
+	}
+
+	return "", false
+}
+
+// catalogVersionRef returns the name of the [versions] entry a library refers to with version.ref.
+func catalogVersionRef(v any) (string, bool) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	ref, ok := m["ref"].(string)
+
+	return ref, ok
+}
+
+// findQuoted returns the offset of the first quoted string s in data[start:end], or -1 if there is none.
+func findQuoted(data []byte, start, end int, s string) int {
+	region := string(data[start:end])
+	for _, quote := range []string{`"`, `'`} {
+		if i := strings.Index(region, quote+s+quote); i >= 0 {
+			return start + i + 1
+		}
+	}
+
+	return -1
+}
+
+// stripTOMLComment removes the comment from a line of TOML, ignoring #s in strings.
+func stripTOMLComment(line string) string {
+	var quote byte
+	for i := range len(line) {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+
+	return line
+}
@@ -0,0 +1,90 @@
+// Package gradle provides utility functions for reading and rewriting the versions
+// of the Maven dependencies declared in Gradle version catalogs and build scripts.
+package gradle
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Declaration is a version declared in a Gradle file, along with the dependencies that use it.
+type Declaration struct {
+	Name         string   // the name of the version in the catalog or the build script constant, empty if written inline in a dependency.
+	Version      string   // the declared version e.g. "2.14.1".
+	Dependencies []string // the Maven names (groupId:artifactId) of the dependencies that use the version.
+	Line         int      // the line the version is declared on.
+
+	offset int // the byte offset of the version in the file.
+}
+
+// Edit changes a declared version to a new version.
+type Edit struct {
+	Declaration Declaration
+	NewVersion  string
+}
+
+// IsVersionCatalog reports whether the file at path is a Gradle version catalog.
+func IsVersionCatalog(path string) bool {
+	return strings.HasSuffix(filepath.Base(path), ".versions.toml")
+}
+
+// IsBuildScript reports whether the file at path is a Gradle build script, in either Groovy or Kotlin.
+func IsBuildScript(path string) bool {
+	base := filepath.Base(path)
+	return base == "build.gradle" || base == "build.gradle.kts"
+}
+
+// Parse reads the versions declared in a Gradle version catalog or build script.
+func Parse(path string, data []byte) ([]Declaration, error) {
+	switch {
+	case IsVersionCatalog(path):
+		return ParseVersionCatalog(data)
+	case IsBuildScript(path):
+		return ParseBuildScript(data)
+	default:
+		return nil, fmt.Errorf("%s is not a Gradle version catalog or build script", path)
+	}
+}
+
+// Rewrite returns data with the edits applied.
+// The declarations must have been parsed from data.
+func Rewrite(data []byte, edits []Edit) ([]byte, error) {
+	edits = slices.Clone(edits)
+	slices.SortFunc(edits, func(a, b Edit) int { return b.Declaration.offset - a.Declaration.offset })
+
+	out := slices.Clone(data)
+	for _, e := range edits {
+		d := e.Declaration
+		start, end := d.offset, d.offset+len(d.Version)
+		if end > len(out) || string(out[start:end]) != d.Version {
+			return nil, fmt.Errorf("version %q of %s was not found on line %d", d.Version, d.describe(), d.Line)
+		}
+		out = slices.Concat(out[:start], []byte(e.NewVersion), out[end:])
+	}
+
+	return out, nil
+}
+
+func (v Declaration) describe() string {
+	if v.Name != "" {
+		return v.Name
+	}
+	if len(v.Dependencies) > 0 {
+		return v.Dependencies[0]
+	}
+
+	return "dependency"
+}
+
+// isPlainVersion reports whether v is a single version,
+// rather than a dynamic version, a range, or an interpolated string.
+func isPlainVersion(v string) bool {
+	return v != "" && !strings.ContainsAny(v, "[]()+,$ *") && !strings.HasPrefix(v, "latest.")
+}
+
+// lineNumber returns the 1-based line number of the byte at offset.
+func lineNumber(s string, offset int) int {
+	return 1 + strings.Count(s[:offset], "\n")
+}
@@ -0,0 +1,124 @@
+package gradle_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/osv-scanner/v2/internal/testutility"
+	"github.com/google/osv-scanner/v2/internal/utility/gradle"
+)
+
+func parseFile(t *testing.T, path string) ([]byte, []gradle.Declaration) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read test file: %v", err)
+	}
+	versions, err := gradle.Parse(path, data)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", path, err)
+	}
+
+	return data, versions
+}
+
+func TestParseVersionCatalog(t *testing.T) {
+	t.Parallel()
+
+	_, got := parseFile(t, "testdata/libs.versions.toml")
+	want := []gradle.Declaration{
+		{Name: "jackson", Version: "2.13.0", Line: 2, Dependencies: []string{"com.fasterxml.jackson.core:jackson-core", "com.fasterxml.jackson.core:jackson-databind"}},
+		{Name: "log4j", Version: "2.14.1", Line: 3, Dependencies: []string{"org.apache.logging.log4j:log4j-core"}},
+		{Name: "guava", Version: "31.0-jre", Line: 6, Dependencies: []string{"com.google.guava:guava"}},
+		{Version: "1.9", Line: 14, Dependencies: []string{"org.apache.commons:commons-text"}},
+		{Version: "1.33", Line: 15, Dependencies: []string{"org.yaml:snakeyaml"}},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(gradle.Declaration{})); diff != "" {
+		t.Errorf("ParseVersionCatalog() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseBuildScript(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want []gradle.Declaration
+	}{
+		{
+			path: "testdata/build.gradle",
+			want: []gradle.Declaration{
+				{Name: "jacksonVersion", Version: "2.13.0", Line: 7, Dependencies: []string{"com.fasterxml.jackson.core:jackson-core", "com.fasterxml.jackson.core:jackson-databind"}},
+				{Name: "log4jVersion", Version: "2.14.1", Line: 10, Dependencies: []string{"org.apache.logging.log4j:log4j-core"}},
+				{Name: "nettyVersion", Version: "4.1.60.Final", Line: 11, Dependencies: []string{"io.netty:netty-all"}},
+				{Version: "1.9", Line: 19, Dependencies: []string{"org.apache.commons:commons-text"}},
+				{Version: "1.33", Line: 20, Dependencies: []string{"org.yaml:snakeyaml"}},
+			},
+		},
+		{
+			path: "testdata/build.gradle.kts",
+			want: []gradle.Declaration{
+				{Name: "jacksonVersion", Version: "2.13.0", Line: 5, Dependencies: []string{"com.fasterxml.jackson.core:jackson-databind"}},
+				{Name: "log4jVersion", Version: "2.14.1", Line: 6, Dependencies: []string{"org.apache.logging.log4j:log4j-core"}},
+				{Version: "1.9", Line: 13, Dependencies: []string{"org.apache.commons:commons-text"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			_, got := parseFile(t, tt.path)
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(gradle.Declaration{})); diff != "" {
+				t.Errorf("ParseBuildScript() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []string{"testdata/libs.versions.toml", "testdata/build.gradle", "testdata/build.gradle.kts"} {
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			data, versions := parseFile(t, path)
+			newVersions := map[string]string{
+				"2.13.0":   "2.13.4",
+				"2.14.1":   "2.17.1",
+				"1.9":      "1.10.0",
+				"1.33":     "2.0",
+				"31.0-jre": "32.0.0-jre",
+			}
+			var edits []gradle.Edit
+			for _, v := range versions {
+				if nv, ok := newVersions[v.Version]; ok {
+					edits = append(edits, gradle.Edit{Declaration: v, NewVersion: nv})
+				}
+			}
+
+			got, err := gradle.Rewrite(data, edits)
+			if err != nil {
+				t.Fatalf("Rewrite() error: %v", err)
+			}
+			testutility.NewSnapshot().WithCRLFReplacement().MatchText(t, string(got))
+		})
+	}
+}
+
+func TestRewrite_Modified(t *testing.T) {
+	t.Parallel()
+
+	data, versions := parseFile(t, "testdata/build.gradle")
+	edits := []gradle.Edit{{Declaration: versions[0], NewVersion: "2.13.4"}}
+	if _, err := gradle.Rewrite([]byte("// no longer the parsed file\n"), edits); err == nil {
+		t.Errorf("Rewrite() of a different file returned no error")
+	}
+	if _, err := gradle.Rewrite(data, edits); err != nil {
+		t.Errorf("Rewrite() error: %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+plugins {
+    kotlin("jvm") version "1.9.0"
+}
+
+val jacksonVersion = "2.13.0"
+val log4jVersion: String by extra("2.14.1")
+extra["nettyVersion"] = "4.1.60.Final"
+
+dependencies {
+    implementation("com.fasterxml.jackson.core:jackson-databind:$jacksonVersion")
+    implementation("org.apache.logging.log4j:log4j-core:$log4jVersion")
+    implementation("io.netty:netty-all:${extra["nettyVersion"]}")
+    implementation("org.apache.commons:commons-text:1.9")
+}
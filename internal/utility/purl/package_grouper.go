@@ -26,6 +26,11 @@ func Group(packageSources []models.PackageSource) (map[string]models.PackageVuln
 			if packageExists {
 				// Entry already exists, we need to merge slices which are not expected to be the exact same
 				packageVulns.DepGroups = append(packageVulns.DepGroups, pkg.DepGroups...)
+				for _, parent := range pkg.Parents {
+					if !slices.Contains(packageVulns.Parents, parent) {
+						packageVulns.Parents = append(packageVulns.Parents, parent)
+					}
+				}
 
 				uniquePackages[packageURL.ToString()] = packageVulns
 			} else {
@@ -38,6 +43,7 @@ func Group(packageSources []models.PackageSource) (map[string]models.PackageVuln
 						Deprecated: pkg.Package.Deprecated,
 					},
 					DepGroups:         slices.Clone(pkg.DepGroups),
+					Parents:           slices.Clone(pkg.Parents),
 					Vulnerabilities:   slices.Clone(pkg.Vulnerabilities),
 					Groups:            slices.Clone(pkg.Groups),
 					Licenses:          slices.Clone(pkg.Licenses),
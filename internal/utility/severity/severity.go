@@ -2,6 +2,8 @@
 package severity
 
 import (
+	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -10,6 +12,7 @@ import (
 	gocvss30 "github.com/pandatix/go-cvss/30"
 	gocvss31 "github.com/pandatix/go-cvss/31"
 	gocvss40 "github.com/pandatix/go-cvss/40"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // Rating represents the severity level of a vulnerability.
@@ -23,6 +26,11 @@ const (
 	UnknownRating  Rating = "UNKNOWN"
 )
 
+// CalculateScore computes the score and rating of a single severity entry.
+//
+// CVSS vectors are parsed according to the version in their prefix, so that
+// entries whose type does not match their vector are still scored. Ubuntu
+// severities only have a rating, in which case the score is -1.
 func CalculateScore(severity *osvschema.Severity) (float64, string, error) {
 	score := -1.0
 	rating := string(UnknownRating)
@@ -30,67 +38,188 @@ func CalculateScore(severity *osvschema.Severity) (float64, string, error) {
 	switch severity.GetType() {
 	case osvschema.Severity_UNSPECIFIED:
 		// UNSPECIFIED has no score information
-	case osvschema.Severity_CVSS_V2:
-		var vec *gocvss20.CVSS20
-		vec, err = gocvss20.ParseVector(severity.GetScore())
-		if err == nil {
-			score = vec.BaseScore()
-			// CVSS 2.0 does not define a rating, use CVSS 3.0's rating instead
-			rating, err = gocvss30.Rating(score)
-		}
-	case osvschema.Severity_CVSS_V3:
-		switch {
-		case strings.HasPrefix(severity.GetScore(), "CVSS:3.0"):
-			var vec *gocvss30.CVSS30
-			vec, err = gocvss30.ParseVector(severity.GetScore())
-			if err == nil {
-				score = vec.BaseScore()
-				rating, err = gocvss30.Rating(score)
-			}
-		case strings.HasPrefix(severity.GetScore(), "CVSS:3.1"):
-			var vec *gocvss31.CVSS31
-			vec, err = gocvss31.ParseVector(severity.GetScore())
-			if err == nil {
-				score = vec.BaseScore()
-				rating, err = gocvss31.Rating(score)
-			}
-		}
-	case osvschema.Severity_CVSS_V4:
-		var vec *gocvss40.CVSS40
-		vec, err = gocvss40.ParseVector(severity.GetScore())
-		if err == nil {
-			score = vec.Score()
-			rating, err = gocvss40.Rating(score)
-		}
+	case osvschema.Severity_CVSS_V2, osvschema.Severity_CVSS_V3, osvschema.Severity_CVSS_V4:
+		score, rating, err = calculateCVSSScore(severity.GetScore())
 	case osvschema.Severity_Ubuntu:
-		rating = severity.GetScore()
+		rating = string(ParseRating(severity.GetScore()))
 	}
 
 	return score, rating, err
 }
 
+func calculateCVSSScore(vector string) (float64, string, error) {
+	switch {
+	case strings.HasPrefix(vector, "CVSS:4.0/"):
+		vec, err := gocvss40.ParseVector(vector)
+		if err != nil {
+			return -1, string(UnknownRating), err
+		}
+		rating, err := gocvss40.Rating(vec.Score())
+
+		return vec.Score(), rating, err
+	case strings.HasPrefix(vector, "CVSS:3.1/"):
+		vec, err := gocvss31.ParseVector(vector)
+		if err != nil {
+			return -1, string(UnknownRating), err
+		}
+		rating, err := gocvss31.Rating(vec.BaseScore())
+
+		return vec.BaseScore(), rating, err
+	case strings.HasPrefix(vector, "CVSS:3.0/"):
+		vec, err := gocvss30.ParseVector(vector)
+		if err != nil {
+			return -1, string(UnknownRating), err
+		}
+		rating, err := gocvss30.Rating(vec.BaseScore())
+
+		return vec.BaseScore(), rating, err
+	case strings.HasPrefix(vector, "CVSS:"):
+		return -1, string(UnknownRating), fmt.Errorf("unsupported CVSS version in %q", vector)
+	default:
+		vec, err := gocvss20.ParseVector(vector)
+		if err != nil {
+			return -1, string(UnknownRating), err
+		}
+		// CVSS 2.0 does not define a rating, use CVSS 3.0's rating instead
+		rating, err := gocvss30.Rating(vec.BaseScore())
+
+		return vec.BaseScore(), rating, err
+	}
+}
+
+// CalculateOverallScore computes the highest score among the given severity
+// entries, with its rating.
+//
+// Entries that cannot be parsed are skipped, and the error of the first of
+// them is only returned if no other entry could be scored either. If none of
+// the entries have a score, the highest rating among them is returned with a
+// score of -1.
 func CalculateOverallScore(severities []*osvschema.Severity) (float64, string, error) {
 	maxScore := -1.0
 	maxRating := string(UnknownRating)
+	var firstErr error
 
 	for _, severity := range severities {
 		score, rating, err := CalculateScore(severity)
 		if err != nil {
-			return -1, string(UnknownRating), err
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			continue
 		}
-		if score > maxScore {
+		if score > maxScore || (maxScore < 0 && score < 0 && Compare(Rating(rating), Rating(maxRating)) > 0) {
 			maxScore = score
 			maxRating = rating
 		}
 	}
 
+	if maxScore < 0 && maxRating == string(UnknownRating) && firstErr != nil {
+		return -1, string(UnknownRating), firstErr
+	}
+
 	return maxScore, maxRating, nil
 }
 
+// Normalize computes a single severity for a vulnerability from all of its
+// severity data: the CVSS vectors of the vulnerability and of each affected
+// package, whichever version they are, and the ratings given by distributions
+// and advisory databases when there are no vectors.
+//
+// The score is -1 if it is only known as a rating.
+func Normalize(vuln *osvschema.Vulnerability) (float64, Rating) {
+	severities := slices.Clone(vuln.GetSeverity())
+	for _, affected := range vuln.GetAffected() {
+		severities = append(severities, affected.GetSeverity()...)
+	}
+
+	score, rating, _ := CalculateOverallScore(severities)
+	if score >= 0 {
+		return score, Rating(rating)
+	}
+
+	maxRating := Rating(rating)
+	for _, s := range qualitativeSeverities(vuln) {
+		if r := ParseRating(s); Compare(r, maxRating) > 0 {
+			maxRating = r
+		}
+	}
+
+	return -1, maxRating
+}
+
+// qualitativeSeverities returns the severities given as words in the
+// database or ecosystem specific data of a vulnerability, such as GitHub's
+// "severity" or Debian's "urgency".
+func qualitativeSeverities(vuln *osvschema.Vulnerability) []string {
+	var severities []string
+	add := func(s *structpb.Struct) {
+		for _, key := range []string{"severity", "urgency"} {
+			if v := s.GetFields()[key].GetStringValue(); v != "" {
+				severities = append(severities, v)
+			}
+		}
+	}
+
+	add(vuln.GetDatabaseSpecific())
+	for _, affected := range vuln.GetAffected() {
+		add(affected.GetDatabaseSpecific())
+		add(affected.GetEcosystemSpecific())
+	}
+
+	return severities
+}
+
+// ParseRating maps the qualitative severities used by distributions and
+// advisory databases onto a rating, e.g. Red Hat's "important" to HIGH and
+// GitHub's "moderate" to MEDIUM.
+func ParseRating(s string) Rating {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "critical":
+		return CriticalRating
+	case "high", "important":
+		return HighRating
+	case "medium", "moderate":
+		return MediumRating
+	case "low", "negligible", "unimportant":
+		return LowRating
+	default:
+		return UnknownRating
+	}
+}
+
+// Compare orders ratings from UNKNOWN, which is the lowest, to CRITICAL,
+// returning a negative number if a is lower than b, and a positive one if it
+// is higher.
+func Compare(a, b Rating) int {
+	return ratingRank(a) - ratingRank(b)
+}
+
+func ratingRank(r Rating) int {
+	switch r {
+	case CriticalRating:
+		return 4
+	case HighRating:
+		return 3
+	case MediumRating:
+		return 2
+	case LowRating:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// CalculateRating computes the rating of a score, which may also be given as
+// a rating when no numeric score is known.
 func CalculateRating(score string) (Rating, error) {
 	// All CSVs' rating methods are identical.
 	parsedScore, err := strconv.ParseFloat(score, 64)
 	if err != nil {
+		if rating := ParseRating(score); rating != UnknownRating {
+			return rating, nil
+		}
+
 		return UnknownRating, err
 	}
 
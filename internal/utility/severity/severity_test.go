@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/osv-scanner/v2/internal/utility/severity"
 	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func TestSeverity_CalculateScore(t *testing.T) {
@@ -90,3 +91,174 @@ func TestSeverity_CalculateScore(t *testing.T) {
 		})
 	}
 }
+
+func TestSeverity_CalculateOverallScore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		severities []*osvschema.Severity
+		wantScore  float64
+		wantRating string
+		wantErr    bool
+	}{
+		{
+			name: "mixed versions",
+			severities: []*osvschema.Severity{
+				{Type: osvschema.Severity_CVSS_V2, Score: "AV:N/AC:L/Au:N/C:P/I:N/A:N"},
+				{Type: osvschema.Severity_CVSS_V3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"},
+				{Type: osvschema.Severity_CVSS_V4, Score: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:L/VI:N/VA:N/SC:N/SI:N/SA:N"},
+			},
+			wantScore:  9.8,
+			wantRating: "CRITICAL",
+		},
+		{
+			name: "invalid vector is skipped",
+			severities: []*osvschema.Severity{
+				{Type: osvschema.Severity_CVSS_V3, Score: "CVSS:3.1/not-a-vector"},
+				{Type: osvschema.Severity_CVSS_V3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:U/C:L/I:L/A:N"},
+			},
+			wantScore:  5.4,
+			wantRating: "MEDIUM",
+		},
+		{
+			name: "only invalid vectors",
+			severities: []*osvschema.Severity{
+				{Type: osvschema.Severity_CVSS_V3, Score: "CVSS:3.1/not-a-vector"},
+			},
+			wantScore:  -1,
+			wantRating: "UNKNOWN",
+			wantErr:    true,
+		},
+		{
+			name: "CVSS v4 vector with the CVSS v3 type",
+			severities: []*osvschema.Severity{
+				{Type: osvschema.Severity_CVSS_V3, Score: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N"},
+			},
+			wantScore:  9.3,
+			wantRating: "CRITICAL",
+		},
+		{
+			name: "only ratings",
+			severities: []*osvschema.Severity{
+				{Type: osvschema.Severity_Ubuntu, Score: "low"},
+				{Type: osvschema.Severity_Ubuntu, Score: "high"},
+			},
+			wantScore:  -1,
+			wantRating: "HIGH",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotScore, gotRating, err := severity.CalculateOverallScore(tt.severities)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CalculateOverallScore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if math.Round(10*gotScore) != math.Round(10*tt.wantScore) || gotRating != tt.wantRating {
+				t.Errorf("CalculateOverallScore() = (%.1f, %s), want (%.1f, %s)", gotScore, gotRating, tt.wantScore, tt.wantRating)
+			}
+		})
+	}
+}
+
+func TestSeverity_Normalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		vuln       *osvschema.Vulnerability
+		wantScore  float64
+		wantRating severity.Rating
+	}{
+		{
+			name: "affected package severity",
+			vuln: &osvschema.Vulnerability{
+				Affected: []*osvschema.Affected{{
+					Severity: []*osvschema.Severity{
+						{Type: osvschema.Severity_CVSS_V3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N"},
+					},
+				}},
+			},
+			wantScore:  7.5,
+			wantRating: severity.HighRating,
+		},
+		{
+			name: "database specific severity",
+			vuln: &osvschema.Vulnerability{
+				DatabaseSpecific: mustStruct(t, map[string]any{"severity": "MODERATE"}),
+			},
+			wantScore:  -1,
+			wantRating: severity.MediumRating,
+		},
+		{
+			name: "ecosystem specific urgency",
+			vuln: &osvschema.Vulnerability{
+				Affected: []*osvschema.Affected{
+					{EcosystemSpecific: mustStruct(t, map[string]any{"urgency": "unimportant"})},
+					{EcosystemSpecific: mustStruct(t, map[string]any{"urgency": "not yet assigned"})},
+				},
+			},
+			wantScore:  -1,
+			wantRating: severity.LowRating,
+		},
+		{
+			name: "vector takes precedence over ratings",
+			vuln: &osvschema.Vulnerability{
+				Severity: []*osvschema.Severity{
+					{Type: osvschema.Severity_CVSS_V3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:U/C:L/I:L/A:N"},
+				},
+				DatabaseSpecific: mustStruct(t, map[string]any{"severity": "CRITICAL"}),
+			},
+			wantScore:  5.4,
+			wantRating: severity.MediumRating,
+		},
+		{
+			name:       "no severity data",
+			vuln:       &osvschema.Vulnerability{},
+			wantScore:  -1,
+			wantRating: severity.UnknownRating,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotScore, gotRating := severity.Normalize(tt.vuln)
+			if math.Round(10*gotScore) != math.Round(10*tt.wantScore) || gotRating != tt.wantRating {
+				t.Errorf("Normalize() = (%.1f, %s), want (%.1f, %s)", gotScore, gotRating, tt.wantScore, tt.wantRating)
+			}
+		})
+	}
+}
+
+func TestSeverity_CalculateRating(t *testing.T) {
+	t.Parallel()
+
+	for score, want := range map[string]severity.Rating{
+		"9.8":      severity.CriticalRating,
+		"5.0":      severity.MediumRating,
+		"0.0":      severity.UnknownRating,
+		"HIGH":     severity.HighRating,
+		"moderate": severity.MediumRating,
+		"":         severity.UnknownRating,
+	} {
+		if got, _ := severity.CalculateRating(score); got != want {
+			t.Errorf("CalculateRating(%q) = %s, want %s", score, got, want)
+		}
+	}
+}
+
+func mustStruct(t *testing.T, m map[string]any) *structpb.Struct {
+	t.Helper()
+
+	s, err := structpb.NewStruct(m)
+	if err != nil {
+		t.Fatalf("structpb.NewStruct() error: %v", err)
+	}
+
+	return s
+}
@@ -0,0 +1,139 @@
+// Package unifieddiff formats the changes made to a file as a unified diff,
+// which can be applied with `git apply` or `patch -p1`.
+package unifieddiff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// context is the number of unchanged lines shown around each change.
+const context = 3
+
+type line struct {
+	op   diffmatchpatch.Operation
+	text string // including the trailing newline, if any
+}
+
+// Write writes the changes from before to after of the file at path as a unified diff to w.
+// The path is prefixed with a/ and b/ in the headers, as git does.
+// Nothing is written if the contents are the same.
+func Write(w io.Writer, path string, before, after []byte) error {
+	if string(before) == string(after) {
+		return nil
+	}
+
+	lines := diffLines(string(before), string(after))
+
+	if _, err := fmt.Fprintf(w, "--- a/%s\n+++ b/%s\n", path, path); err != nil {
+		return err
+	}
+
+	// The line numbers in before and after at the start of each line of the diff.
+	beforeLine := make([]int, len(lines)+1)
+	afterLine := make([]int, len(lines)+1)
+	beforeLine[0], afterLine[0] = 1, 1
+	for i, l := range lines {
+		beforeLine[i+1], afterLine[i+1] = beforeLine[i], afterLine[i]
+		if l.op != diffmatchpatch.DiffInsert {
+			beforeLine[i+1]++
+		}
+		if l.op != diffmatchpatch.DiffDelete {
+			afterLine[i+1]++
+		}
+	}
+
+	for _, h := range hunks(lines) {
+		start, end := h[0], h[1]
+		beforeStart, beforeCount := beforeLine[start], beforeLine[end]-beforeLine[start]
+		afterStart, afterCount := afterLine[start], afterLine[end]-afterLine[start]
+		// An empty range is numbered by the line before it.
+		if beforeCount == 0 {
+			beforeStart--
+		}
+		if afterCount == 0 {
+			afterStart--
+		}
+		if _, err := fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", beforeStart, beforeCount, afterStart, afterCount); err != nil {
+			return err
+		}
+
+		for _, l := range lines[start:end] {
+			prefix := " "
+			switch l.op {
+			case diffmatchpatch.DiffDelete:
+				prefix = "-"
+			case diffmatchpatch.DiffInsert:
+				prefix = "+"
+			case diffmatchpatch.DiffEqual:
+			}
+			text := l.text
+			if !strings.HasSuffix(text, "\n") {
+				text += "\n\\ No newline at end of file\n"
+			}
+			if _, err := io.WriteString(w, prefix+text); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// diffLines computes the line-by-line differences between before and after.
+func diffLines(before, after string) []line {
+	dmp := diffmatchpatch.New()
+	dmp.DiffTimeout = 0 // always find the minimal diff
+	runes1, runes2, lineArray := dmp.DiffLinesToRunes(before, after)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMainRunes(runes1, runes2, false), lineArray)
+
+	var lines []line
+	for _, d := range diffs {
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text != "" {
+				lines = append(lines, line{op: d.Type, text: text})
+			}
+		}
+	}
+
+	return lines
+}
+
+// hunks groups the changed lines that are close enough together to share their context,
+// returning the [start, end) range of lines in each group, including the context.
+func hunks(lines []line) [][2]int {
+	var ranges [][2]int
+	i := 0
+	for {
+		for i < len(lines) && lines[i].op == diffmatchpatch.DiffEqual {
+			i++
+		}
+		if i == len(lines) {
+			return ranges
+		}
+
+		start := max(i-context, 0)
+		end := i
+		for end < len(lines) {
+			if lines[end].op != diffmatchpatch.DiffEqual {
+				end++
+				continue
+			}
+			next := end
+			for next < len(lines) && lines[next].op == diffmatchpatch.DiffEqual {
+				next++
+			}
+			if next == len(lines) || next-end > 2*context {
+				end = min(end+context, len(lines))
+				break
+			}
+			end = next
+		}
+
+		ranges = append(ranges, [2]int{start, end})
+		i = end
+	}
+}
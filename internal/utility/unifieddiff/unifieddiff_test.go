@@ -0,0 +1,102 @@
+package unifieddiff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/utility/unifieddiff"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	numbered := func(from, to int) string {
+		var sb strings.Builder
+		for i := from; i <= to; i++ {
+			sb.WriteString("line ")
+			sb.WriteString(string(rune('a' + i - 1)))
+			sb.WriteString("\n")
+		}
+
+		return sb.String()
+	}
+
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{
+			name:   "unchanged",
+			before: numbered(1, 5),
+			after:  numbered(1, 5),
+			want:   "",
+		},
+		{
+			name:   "single_change",
+			before: numbered(1, 10),
+			after:  strings.Replace(numbered(1, 10), "line e\n", "line E\n", 1),
+			want: "--- a/dir/file.txt\n+++ b/dir/file.txt\n" +
+				"@@ -2,7 +2,7 @@\n" +
+				" line b\n line c\n line d\n-line e\n+line E\n line f\n line g\n line h\n",
+		},
+		{
+			name:   "separate_hunks",
+			before: numbered(1, 12),
+			after:  strings.Replace(strings.Replace(numbered(1, 12), "line a\n", "line A\n", 1), "line l\n", "line L\n", 1),
+			want: "--- a/dir/file.txt\n+++ b/dir/file.txt\n" +
+				"@@ -1,4 +1,4 @@\n" +
+				"-line a\n+line A\n line b\n line c\n line d\n" +
+				"@@ -9,4 +9,4 @@\n" +
+				" line i\n line j\n line k\n-line l\n+line L\n",
+		},
+		{
+			name:   "merged_hunks",
+			before: numbered(1, 8),
+			after:  strings.Replace(strings.Replace(numbered(1, 8), "line a\n", "line A\n", 1), "line h\n", "line H\n", 1),
+			want: "--- a/dir/file.txt\n+++ b/dir/file.txt\n" +
+				"@@ -1,8 +1,8 @@\n" +
+				"-line a\n+line A\n line b\n line c\n line d\n line e\n line f\n line g\n-line h\n+line H\n",
+		},
+		{
+			name:   "insertion",
+			before: numbered(1, 2),
+			after:  numbered(1, 2) + "line c\n",
+			want: "--- a/dir/file.txt\n+++ b/dir/file.txt\n" +
+				"@@ -1,2 +1,3 @@\n" +
+				" line a\n line b\n+line c\n",
+		},
+		{
+			name:   "empty_before",
+			before: "",
+			after:  numbered(1, 1),
+			want: "--- a/dir/file.txt\n+++ b/dir/file.txt\n" +
+				"@@ -0,0 +1,1 @@\n" +
+				"+line a\n",
+		},
+		{
+			name:   "no_newline_at_end",
+			before: "line a\nline b",
+			after:  "line a\nline B",
+			want: "--- a/dir/file.txt\n+++ b/dir/file.txt\n" +
+				"@@ -1,2 +1,2 @@\n" +
+				" line a\n-line b\n\\ No newline at end of file\n+line B\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var sb strings.Builder
+			if err := unifieddiff.Write(&sb, "dir/file.txt", []byte(tt.before), []byte(tt.after)); err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, sb.String()); diff != "" {
+				t.Errorf("Write() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
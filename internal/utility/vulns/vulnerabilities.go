@@ -1,7 +1,11 @@
 // Package vulns provides utility functions for working with vulnerabilities.
 package vulns
 
-import "github.com/ossf/osv-schema/bindings/go/osvschema"
+import (
+	"strings"
+
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
 
 func Include(vs []*osvschema.Vulnerability, vulnerability *osvschema.Vulnerability) bool {
 	for _, vuln := range vs {
@@ -12,3 +16,10 @@ func Include(vs []*osvschema.Vulnerability, vulnerability *osvschema.Vulnerabili
 
 	return false
 }
+
+// IsMalicious reports whether an OSV ID is that of a malicious package
+// advisory, which flags a package version as malware rather than as having a
+// vulnerability.
+func IsMalicious(id string) bool {
+	return strings.HasPrefix(id, "MAL-")
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/google/osv-scalibr/inventory/osvecosystem"
 	"github.com/google/osv-scalibr/semantic"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
 	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
 	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
@@ -129,6 +131,17 @@ func hasGitRangeForRepo(affected *osvschema.Affected, repo string) bool {
 	return false
 }
 
+// IsSamePackageName reports whether name, as an advisory gives it, names pkg.
+// PyPI names are compared as normalized by PEP 503, so that "Flask_Cors"
+// names the same package as "flask-cors".
+func IsSamePackageName(name string, pkg imodels.PackageInfo) bool {
+	if pkg.Ecosystem().Ecosystem == osvconstants.EcosystemPyPI {
+		return depsdev.NormalizePyPIName(name) == depsdev.NormalizePyPIName(pkg.Name())
+	}
+
+	return name == pkg.Name()
+}
+
 func IsAffected(v *osvschema.Vulnerability, pkg imodels.PackageInfo) bool {
 	for _, affected := range v.GetAffected() {
 		// assume we're dealing with a git-source package whose name is the git repository, and that the version is the tag
@@ -144,7 +157,7 @@ func IsAffected(v *osvschema.Vulnerability, pkg imodels.PackageInfo) bool {
 			continue
 		}
 		if osvecosystem.MustParse(affected.GetPackage().GetEcosystem()).Equal(pkg.Ecosystem()) &&
-			affected.GetPackage().GetName() == pkg.Name() {
+			IsSamePackageName(affected.GetPackage().GetName(), pkg) {
 			if len(affected.GetRanges()) == 0 && len(affected.GetVersions()) == 0 {
 				cmdlogger.Warnf("%s does not have any ranges or versions - this is probably a mistake!", v.GetId())
 
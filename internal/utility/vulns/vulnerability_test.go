@@ -710,6 +710,30 @@ func TestOSV_IsAffected_OnlyVersions(t *testing.T) {
 	expectIsAffected(t, vuln, "", true)
 }
 
+func TestIsSamePackageName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		purlType string
+		pkgName  string
+		want     bool
+	}{
+		{name: "Flask_Cors", purlType: purl.TypePyPi, pkgName: "flask-cors", want: true},
+		{name: "zope.interface", purlType: purl.TypePyPi, pkgName: "Zope-Interface", want: true},
+		{name: "flask", purlType: purl.TypePyPi, pkgName: "flask-cors", want: false},
+		{name: "Lodash", purlType: purl.TypeNPM, pkgName: "lodash", want: false},
+		{name: "lodash", purlType: purl.TypeNPM, pkgName: "lodash", want: true},
+	}
+
+	for _, tt := range tests {
+		pkg := imodels.FromInventory(&extractor.Package{Name: tt.pkgName, Version: "1.0.0", PURLType: tt.purlType})
+		if got := vulns.IsSamePackageName(tt.name, pkg); got != tt.want {
+			t.Errorf("IsSamePackageName(%q, %s/%s) = %v, want %v", tt.name, tt.purlType, tt.pkgName, got, tt.want)
+		}
+	}
+}
+
 func TestOSV_EcosystemsWithSuffix(t *testing.T) {
 	t.Parallel()
 
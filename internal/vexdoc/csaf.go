@@ -0,0 +1,133 @@
+package vexdoc
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+type csafDocument struct {
+	Document struct {
+		Category string `json:"category"`
+	} `json:"document"`
+	ProductTree     csafProductTree     `json:"product_tree"`
+	Vulnerabilities []csafVulnerability `json:"vulnerabilities"`
+}
+
+type csafProductTree struct {
+	Branches         []csafBranch      `json:"branches"`
+	FullProductNames []csafProductName `json:"full_product_names"`
+	Relationships    []struct {
+		ProductReference string          `json:"product_reference"`
+		FullProductName  csafProductName `json:"full_product_name"`
+	} `json:"relationships"`
+}
+
+type csafBranch struct {
+	Product  *csafProductName `json:"product"`
+	Branches []csafBranch     `json:"branches"`
+}
+
+type csafProductName struct {
+	ProductID                   string `json:"product_id"`
+	ProductIdentificationHelper struct {
+		PURL string `json:"purl"`
+	} `json:"product_identification_helper"`
+}
+
+type csafVulnerability struct {
+	CVE string `json:"cve"`
+	IDs []struct {
+		Text string `json:"text"`
+	} `json:"ids"`
+	ProductStatus struct {
+		KnownNotAffected []string `json:"known_not_affected"`
+	} `json:"product_status"`
+	Flags   []csafProductNote `json:"flags"`
+	Threats []csafProductNote `json:"threats"`
+}
+
+// csafProductNote is a flag or threat, which give the justification and
+// impact of products not being affected respectively.
+type csafProductNote struct {
+	Label      string   `json:"label"`
+	Category   string   `json:"category"`
+	Details    string   `json:"details"`
+	ProductIDs []string `json:"product_ids"`
+}
+
+// purls maps the IDs of the products in the tree to their package URLs.
+func (tree csafProductTree) purls() map[string]string {
+	purls := make(map[string]string)
+
+	var walk func(branches []csafBranch)
+	walk = func(branches []csafBranch) {
+		for _, b := range branches {
+			if b.Product != nil {
+				purls[b.Product.ProductID] = b.Product.ProductIdentificationHelper.PURL
+			}
+			walk(b.Branches)
+		}
+	}
+	walk(tree.Branches)
+
+	for _, p := range tree.FullProductNames {
+		purls[p.ProductID] = p.ProductIdentificationHelper.PURL
+	}
+
+	// components that are part of other products are what is not affected
+	for _, r := range tree.Relationships {
+		if p := r.FullProductName.ProductIdentificationHelper.PURL; p != "" {
+			purls[r.FullProductName.ProductID] = p
+		} else {
+			purls[r.FullProductName.ProductID] = purls[r.ProductReference]
+		}
+	}
+
+	return purls
+}
+
+func parseCSAF(content []byte) ([]Statement, error) {
+	var doc csafDocument
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Document.Category != "csaf_vex" {
+		return nil, ErrUnknownFormat
+	}
+
+	purls := doc.ProductTree.purls()
+
+	var statements []Statement
+	for _, v := range doc.Vulnerabilities {
+		var ids []string
+		if v.CVE != "" {
+			ids = append(ids, v.CVE)
+		}
+		for _, id := range v.IDs {
+			ids = append(ids, id.Text)
+		}
+
+		// products can be given different justifications, so there is a
+		// statement for each of them
+		for _, productID := range v.ProductStatus.KnownNotAffected {
+			p := purls[productID]
+			if p == "" {
+				continue
+			}
+			statement := Statement{IDs: ids, Products: []string{p}}
+			for _, flag := range v.Flags {
+				if slices.Contains(flag.ProductIDs, productID) {
+					statement.Justification = flag.Label
+				}
+			}
+			for _, threat := range v.Threats {
+				if threat.Category == "impact" && slices.Contains(threat.ProductIDs, productID) {
+					statement.ImpactStatement = threat.Details
+				}
+			}
+			statements = append(statements, statement)
+		}
+	}
+
+	return statements, nil
+}
@@ -0,0 +1,153 @@
+package vexdoc
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+type openVEXDocument struct {
+	Timestamp  string             `json:"timestamp"`
+	Statements []openVEXStatement `json:"statements"`
+}
+
+type openVEXStatement struct {
+	Vulnerability openVEXVulnerability `json:"vulnerability"`
+	Products      []openVEXProduct     `json:"products"`
+	Status        string               `json:"status"`
+	// Timestamp is when the statement was made, which defaults to the
+	// timestamp of the document
+	Timestamp       string `json:"timestamp"`
+	Justification   string `json:"justification"`
+	ImpactStatement string `json:"impact_statement"`
+}
+
+// openVEXVulnerability is the vulnerability of a statement, which versions
+// of OpenVEX before 0.2.0 gave as just its name.
+type openVEXVulnerability struct {
+	ID      string   `json:"@id"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+func (v *openVEXVulnerability) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &v.Name); err == nil {
+		return nil
+	}
+
+	type plain openVEXVulnerability
+
+	return json.Unmarshal(data, (*plain)(v))
+}
+
+// openVEXProduct is a product a statement applies to, or the subcomponents
+// of it that the statement applies to if there are any.
+type openVEXProduct struct {
+	openVEXComponent
+
+	Subcomponents []openVEXComponent `json:"subcomponents"`
+}
+
+type openVEXComponent struct {
+	ID          string            `json:"@id"`
+	Identifiers map[string]string `json:"identifiers"`
+}
+
+func (c openVEXComponent) purl() string {
+	if p := c.Identifiers["purl"]; p != "" {
+		return p
+	}
+
+	return c.ID
+}
+
+// parseOpenVEX returns the not_affected statements of an OpenVEX document.
+//
+// Only the latest statement about a vulnerability in a product applies, so
+// that a later affected or under_investigation statement overrides an earlier
+// not_affected one. Statements are ordered by their timestamps, and then by
+// their order in the document.
+func parseOpenVEX(content []byte) ([]Statement, error) {
+	var doc openVEXDocument
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+
+	order := make([]int, len(doc.Statements))
+	times := make([]time.Time, len(doc.Statements))
+	for i, s := range doc.Statements {
+		order[i] = i
+		timestamp := s.Timestamp
+		if timestamp == "" {
+			timestamp = doc.Timestamp
+		}
+		// statements without a valid timestamp come first
+		times[i], _ = time.Parse(time.RFC3339, timestamp)
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return times[a].Compare(times[b])
+	})
+
+	// the index of the latest statement about each vulnerability in a product
+	latest := make(map[string]int)
+	for _, i := range order {
+		s := doc.Statements[i]
+		for _, product := range s.products() {
+			latest[s.Vulnerability.name()+"|"+product] = i
+		}
+	}
+
+	var statements []Statement
+	for i, s := range doc.Statements {
+		if s.Status != "not_affected" {
+			continue
+		}
+
+		statement := Statement{
+			Justification:   s.Justification,
+			ImpactStatement: s.ImpactStatement,
+		}
+		for _, id := range append([]string{s.Vulnerability.Name, s.Vulnerability.ID}, s.Vulnerability.Aliases...) {
+			if id != "" {
+				statement.IDs = append(statement.IDs, id)
+			}
+		}
+		for _, product := range s.products() {
+			if latest[s.Vulnerability.name()+"|"+product] == i {
+				statement.Products = append(statement.Products, product)
+			}
+		}
+		if len(statement.Products) > 0 {
+			statements = append(statements, statement)
+		}
+	}
+
+	return statements, nil
+}
+
+// name returns the name that identifies the vulnerability.
+func (v openVEXVulnerability) name() string {
+	if v.Name != "" {
+		return v.Name
+	}
+
+	return v.ID
+}
+
+// products returns the package URLs of the products the statement applies
+// to, which are their subcomponents if they have any.
+func (s openVEXStatement) products() []string {
+	var products []string
+	for _, product := range s.Products {
+		if len(product.Subcomponents) == 0 {
+			products = append(products, product.purl())
+
+			continue
+		}
+		for _, sub := range product.Subcomponents {
+			products = append(products, sub.purl())
+		}
+	}
+
+	return products
+}
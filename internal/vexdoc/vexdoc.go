@@ -0,0 +1,146 @@
+// Package vexdoc reads the statements that vulnerabilities do not affect
+// packages from OpenVEX and CSAF VEX documents.
+package vexdoc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/google/osv-scanner/v2/internal/utility/purl"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
+)
+
+// ErrUnknownFormat is returned for documents that are neither OpenVEX nor
+// CSAF VEX documents.
+var ErrUnknownFormat = errors.New("not an OpenVEX or CSAF VEX document")
+
+// Statement is a statement that a vulnerability does not affect some
+// packages.
+type Statement struct {
+	// Source is the path of the document the statement is from
+	Source string
+	// IDs are the name of the vulnerability and its aliases
+	IDs []string
+	// Products are the package URLs of the packages not affected, which match
+	// every version of the package if they do not have one
+	Products []string
+	// Justification is why the packages are not affected, such as
+	// "vulnerable_code_not_present"
+	Justification string
+	// ImpactStatement is a free form explanation of why the packages are not
+	// affected, if one was given
+	ImpactStatement string
+}
+
+// Reason returns a description of why the packages are not affected, for
+// showing to users.
+func (s Statement) Reason() string {
+	var parts []string
+	if s.Justification != "" {
+		parts = append(parts, s.Justification)
+	}
+	if s.ImpactStatement != "" {
+		parts = append(parts, s.ImpactStatement)
+	}
+	if len(parts) == 0 {
+		return "not affected"
+	}
+
+	return strings.Join(parts, ": ")
+}
+
+// Matches reports whether the statement applies to a package with a
+// vulnerability known by any of the given IDs.
+func (s Statement) Matches(pkg models.PackageInfo, ids []string) bool {
+	if !slices.ContainsFunc(ids, func(id string) bool { return slices.Contains(s.IDs, id) }) {
+		return false
+	}
+
+	return slices.ContainsFunc(s.Products, func(product string) bool {
+		return productMatches(product, pkg)
+	})
+}
+
+func productMatches(product string, pkg models.PackageInfo) bool {
+	stated, err := purl.ToPackage(product)
+	if err != nil || stated.Ecosystem == "" {
+		return false
+	}
+
+	// the ecosystem of a package can include a release, e.g. "Debian:12"
+	ecosystem, _, _ := strings.Cut(pkg.Ecosystem, ":")
+	if stated.Ecosystem != ecosystem {
+		return false
+	}
+
+	if stated.Ecosystem == string(osvconstants.EcosystemPyPI) {
+		if normalizePyPIName(stated.Name) != normalizePyPIName(pkg.Name) {
+			return false
+		}
+	} else if stated.Name != pkg.Name {
+		return false
+	}
+
+	return stated.Version == "" || stated.Version == pkg.Version
+}
+
+// normalizePyPIName normalizes a PyPI project name as described in PEP 503,
+// so that e.g. "Foo_Bar" and "foo-bar" are the same project.
+func normalizePyPIName(name string) string {
+	return strings.ToLower(cachedregexp.MustCompile(`[-_.]+`).ReplaceAllLiteralString(name, "-"))
+}
+
+// LoadAll reads the statements of each of the given documents.
+func LoadAll(paths []string) ([]Statement, error) {
+	var statements []Statement
+	for _, path := range paths {
+		s, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, s...)
+	}
+
+	return statements, nil
+}
+
+// Load reads the statements of an OpenVEX or CSAF VEX document.
+func Load(path string) ([]Statement, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read VEX document: %w", err)
+	}
+
+	var probe struct {
+		Context  string          `json:"@context"`
+		Document json.RawMessage `json:"document"`
+	}
+	if err := json.Unmarshal(content, &probe); err != nil {
+		return nil, fmt.Errorf("could not parse VEX document %s: %w", path, err)
+	}
+
+	var statements []Statement
+	switch {
+	case strings.Contains(probe.Context, "openvex"):
+		statements, err = parseOpenVEX(content)
+	case probe.Document != nil:
+		statements, err = parseCSAF(content)
+	default:
+		err = ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse VEX document %s: %w", path, err)
+	}
+
+	for i := range statements {
+		statements[i].Source = path
+	}
+
+	return statements, nil
+}
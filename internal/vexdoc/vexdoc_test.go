@@ -0,0 +1,170 @@
+package vexdoc_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/vexdoc"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		path    string
+		want    []vexdoc.Statement
+		wantErr error
+	}{
+		{
+			name: "openvex",
+			path: "testdata/openvex.json",
+			want: []vexdoc.Statement{
+				{
+					Source:          "testdata/openvex.json",
+					IDs:             []string{"CVE-2021-23337", "GHSA-35jh-r3h4-6jhm"},
+					Products:        []string{"pkg:npm/lodash@4.17.20"},
+					Justification:   "vulnerable_code_not_in_execute_path",
+					ImpactStatement: "template() is never called",
+				},
+				{
+					Source:        "testdata/openvex.json",
+					IDs:           []string{"GHSA-xvch-5gv4-984h"},
+					Products:      []string{"pkg:npm/minimist"},
+					Justification: "vulnerable_code_cannot_be_controlled_by_adversary",
+				},
+			},
+		},
+		// later statements override earlier ones about the same product
+		{
+			name: "openvex_superseded",
+			path: "testdata/openvex-superseded.json",
+			want: []vexdoc.Statement{
+				{
+					Source:        "testdata/openvex-superseded.json",
+					IDs:           []string{"GHSA-xvch-5gv4-984h"},
+					Products:      []string{"pkg:npm/minimist@1.2.5"},
+					Justification: "vulnerable_code_cannot_be_controlled_by_adversary",
+				},
+				{
+					Source:        "testdata/openvex-superseded.json",
+					IDs:           []string{"CVE-2022-0001"},
+					Products:      []string{"pkg:npm/axios@0.21.1"},
+					Justification: "component_not_present",
+				},
+			},
+		},
+		{
+			name: "csaf",
+			path: "testdata/csaf.json",
+			want: []vexdoc.Statement{
+				{
+					Source:          "testdata/csaf.json",
+					IDs:             []string{"CVE-2020-36518", "GHSA-57j2-w4cx-62h2"},
+					Products:        []string{"pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.9.10"},
+					Justification:   "vulnerable_code_not_in_execute_path",
+					ImpactStatement: "untrusted JSON is never deserialized",
+				},
+				{
+					Source:        "testdata/csaf.json",
+					IDs:           []string{"CVE-2021-33503"},
+					Products:      []string{"pkg:pypi/urllib3@1.26.4"},
+					Justification: "component_not_present",
+				},
+			},
+		},
+		{
+			name:    "not_vex",
+			path:    "testdata/not-vex.json",
+			wantErr: vexdoc.ErrUnknownFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := vexdoc.Load(tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Load() error = %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStatement_Matches(t *testing.T) {
+	t.Parallel()
+
+	statement := vexdoc.Statement{
+		IDs: []string{"CVE-2021-23337", "GHSA-35jh-r3h4-6jhm"},
+		Products: []string{
+			"pkg:npm/lodash@4.17.20",
+			"pkg:maven/com.fasterxml.jackson.core/jackson-databind",
+			"pkg:pypi/PyYAML@5.3",
+			"pkg:pypi/Foo_Bar@1.0",
+		},
+	}
+
+	tests := []struct {
+		name string
+		pkg  models.PackageInfo
+		ids  []string
+		want bool
+	}{
+		{
+			name: "same_version",
+			pkg:  models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+			ids:  []string{"GHSA-35jh-r3h4-6jhm"},
+			want: true,
+		},
+		{
+			name: "other_version",
+			pkg:  models.PackageInfo{Name: "lodash", Version: "4.17.21", Ecosystem: "npm"},
+			ids:  []string{"GHSA-35jh-r3h4-6jhm"},
+			want: false,
+		},
+		{
+			name: "other_vulnerability",
+			pkg:  models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+			ids:  []string{"GHSA-p6mc-m468-83gw"},
+			want: false,
+		},
+		{
+			name: "any_version",
+			pkg:  models.PackageInfo{Name: "com.fasterxml.jackson.core:jackson-databind", Version: "2.9.10", Ecosystem: "Maven"},
+			ids:  []string{"CVE-2021-23337"},
+			want: true,
+		},
+		{
+			name: "other_ecosystem",
+			pkg:  models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "PyPI"},
+			ids:  []string{"CVE-2021-23337"},
+			want: false,
+		},
+		{
+			name: "pypi_names_are_normalized",
+			pkg:  models.PackageInfo{Name: "foo-bar", Version: "1.0", Ecosystem: "PyPI"},
+			ids:  []string{"CVE-2021-23337"},
+			want: true,
+		},
+		{
+			name: "pypi_names_are_case_insensitive",
+			pkg:  models.PackageInfo{Name: "pyyaml", Version: "5.3", Ecosystem: "PyPI"},
+			ids:  []string{"CVE-2021-23337"},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := statement.Matches(tt.pkg, tt.ids); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,14 +2,50 @@ package models
 
 import "github.com/opencontainers/go-digest"
 
+// ImageOriginDetails is the layer of a container image that introduced a
+// package.
 type ImageOriginDetails struct {
-	Index int `json:"index"`
+	// Index of the layer in the image's LayerMetadata
+	Index  int           `json:"index"`
+	DiffID digest.Digest `json:"diff_id,omitempty"`
+	// Command is the instruction that created the layer, as recorded in the
+	// image's history (e.g. "RUN pip install -r requirements.txt")
+	Command string `json:"command,omitempty"`
+	// InBaseImage is whether the layer comes from a base image, rather than
+	// from the image's own instructions
+	InBaseImage bool `json:"in_base_image"`
+	// BaseImage is the name of the base image the layer comes from, if known
+	BaseImage string `json:"base_image,omitempty"`
 }
 
 type ImageMetadata struct {
 	OS            string               `json:"os"`
 	LayerMetadata []LayerMetadata      `json:"layer_metadata"`
 	BaseImages    [][]BaseImageDetails `json:"base_images"`
+	// BaseImageUpgrade is how the image's base image compares to another
+	// version of it, if one was asked to be compared
+	BaseImageUpgrade *BaseImageUpgrade `json:"base_image_upgrade,omitempty"`
+}
+
+// BaseImageUpgrade is the comparison of the base image of a container image to
+// another version of it, such as a newer tag, by their vulnerabilities.
+type BaseImageUpgrade struct {
+	// BaseImage is the name of the base image the image is built on
+	BaseImage string `json:"base_image"`
+	// Candidate is the reference of the version of the base image that was
+	// compared to, and Digest is what it resolved to
+	Candidate string        `json:"candidate"`
+	Digest    digest.Digest `json:"digest"`
+	// UpToDate is whether the image is already built on the candidate
+	UpToDate bool `json:"up_to_date"`
+	// Vulnerabilities is how many vulnerabilities are in the packages of the
+	// image that come from its base image
+	Vulnerabilities int `json:"vulnerabilities"`
+	// Eliminated is how many of those vulnerabilities the candidate does not
+	// have, and Introduced how many vulnerabilities the candidate has that the
+	// base image does not
+	Eliminated int `json:"eliminated"`
+	Introduced int `json:"introduced"`
 }
 
 type BaseImageDetails struct {
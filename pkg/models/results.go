@@ -20,6 +20,86 @@ type VulnerabilityResults struct {
 	ExperimentalGenericFindings []*inventory.GenericFinding `json:"experimental_generic_findings,omitempty"`
 	ImageMetadata               *ImageMetadata              `json:"image_metadata,omitempty"`
 	LicenseSummary              []LicenseCount              `json:"license_summary,omitempty"`
+	ExperimentalDegradations    []Degradation               `json:"experimental_degradations,omitempty"`
+	ExperimentalUnresolved      []UnresolvedPackage         `json:"experimental_unresolved_packages,omitempty"`
+	ExperimentalDrift           []Drift                     `json:"experimental_drift,omitempty"`
+	ExperimentalUpgradePlan     []Upgrade                   `json:"experimental_upgrade_plan,omitempty"`
+	// ExperimentalIgnored are left out of the JSON output, as ignored
+	// vulnerabilities should not show up in it.
+	ExperimentalIgnored []IgnoredVulnerability `json:"-"`
+}
+
+// Degradation records a source whose results may be incomplete, e.g. because
+// its transitive dependencies could only be partially resolved, or an
+// enricher that could only partially enrich the results of every source.
+type Degradation struct {
+	Source   string `json:"source,omitempty"`
+	Enricher string `json:"enricher,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+// UnresolvedPackage records a package whose dependencies could not be
+// resolved, so any vulnerabilities in them are missing from the results.
+type UnresolvedPackage struct {
+	Source  string `json:"source"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// DriftReason is how a dependency declared in a manifest has drifted from
+// its lockfile.
+type DriftReason string
+
+const (
+	// DriftMissing is a dependency that is not in the lockfile at all.
+	DriftMissing DriftReason = "missing"
+	// DriftInconsistent is a dependency whose versions in the lockfile do not
+	// satisfy what the manifest declares.
+	DriftInconsistent DriftReason = "inconsistent"
+)
+
+// Drift records a dependency declared in a manifest that its lockfile, or the
+// effective versions it was resolved to, does not agree with, so that the
+// scanned versions may not be the ones that get installed.
+type Drift struct {
+	Manifest  string      `json:"manifest"`
+	Lockfile  string      `json:"lockfile"`
+	Ecosystem string      `json:"ecosystem"`
+	Name      string      `json:"name"`
+	Declared  string      `json:"declared"`
+	Locked    []string    `json:"locked,omitempty"`
+	Reason    DriftReason `json:"reason"`
+}
+
+// Upgrade is the minimal upgrade of a package that fixes the most of the
+// vulnerabilities found in it. Together, the upgrades in the results are the
+// smallest set of version bumps that fixes the most vulnerabilities.
+type Upgrade struct {
+	Ecosystem string   `json:"ecosystem"`
+	Name      string   `json:"name"`
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	Fixed     []string `json:"fixed"`
+	Remaining []string `json:"remaining,omitempty"`
+}
+
+// IgnoredVulnerability records a group of aliased vulnerabilities found in a
+// package that was filtered out by an ignore entry of the config, or by a VEX
+// statement that the package is not affected.
+type IgnoredVulnerability struct {
+	Source  SourceInfo
+	Package PackageInfo
+	Group   GroupInfo
+	// Reason is the reason given by the ignore entry or the impact statement
+	// of the VEX statement, if any.
+	Reason string
+	// Justification is the VEX justification for the package not being
+	// affected, e.g. "vulnerable_code_not_present".
+	Justification string
+	// VEXSource is the path of the VEX document with the statement, or empty
+	// if the vulnerability was ignored by the config.
+	VEXSource string
 }
 
 type LicenseCount struct {
@@ -30,12 +110,27 @@ type LicenseCount struct {
 // ExperimentalAnalysisConfig is an experimental type intended to contain the
 // types of analysis performed on packages found by the scanner.
 type ExperimentalAnalysisConfig struct {
-	Licenses ExperimentalLicenseConfig `json:"licenses"`
+	Licenses           ExperimentalLicenseConfig             `json:"licenses"`
+	TransitiveScanning *ExperimentalTransitiveScanningConfig `json:"transitive_scanning,omitempty"`
+}
+
+// ExperimentalTransitiveScanningConfig describes how transitive dependencies
+// were resolved, when that differs from the default.
+type ExperimentalTransitiveScanningConfig struct {
+	// VersionConflicts is the strategy used to choose between the versions of
+	// a package that the roots of a manifest resolved it to.
+	VersionConflicts string `json:"version_conflicts"`
 }
 
 type ExperimentalLicenseConfig struct {
 	Summary   bool      `json:"summary"`
 	Allowlist []License `json:"allowlist"`
+	Denylist  []License `json:"denylist,omitempty"`
+}
+
+// HasPolicy reports whether packages were checked for license violations.
+func (c ExperimentalLicenseConfig) HasPolicy() bool {
+	return len(c.Allowlist) > 0 || len(c.Denylist) > 0
 }
 
 // Flatten the grouped/nested vulnerability results into one flat array.
@@ -68,6 +163,27 @@ func (vulns *VulnerabilityResults) Flatten() []VulnerabilityFlattened {
 					Deprecated: pkg.Package.Deprecated,
 				})
 			}
+			if pkg.ScorecardViolation {
+				results = append(results, VulnerabilityFlattened{
+					Source:             res.Source,
+					Package:            pkg.Package,
+					ScorecardViolation: pkg.ScorecardViolation,
+				})
+			}
+			if pkg.ProvenanceViolation {
+				results = append(results, VulnerabilityFlattened{
+					Source:              res.Source,
+					Package:             pkg.Package,
+					ProvenanceViolation: pkg.ProvenanceViolation,
+				})
+			}
+			if len(pkg.HashMismatches) > 0 {
+				results = append(results, VulnerabilityFlattened{
+					Source:         res.Source,
+					Package:        pkg.Package,
+					HashMismatches: pkg.HashMismatches,
+				})
+			}
 		}
 	}
 
@@ -84,14 +200,17 @@ func getGroupInfoForVuln(groups []GroupInfo, vulnID string) GroupInfo {
 // TODO: rename this to IssueFlattened or similar in the next major release as
 // it now contains license violations.
 type VulnerabilityFlattened struct {
-	Source            SourceInfo
-	Package           PackageInfo
-	DepGroups         []string
-	Vulnerability     *osvschema.Vulnerability
-	GroupInfo         GroupInfo
-	Licenses          []License
-	LicenseViolations []License
-	Deprecated        bool
+	Source              SourceInfo
+	Package             PackageInfo
+	DepGroups           []string
+	Vulnerability       *osvschema.Vulnerability
+	GroupInfo           GroupInfo
+	Licenses            []License
+	LicenseViolations   []License
+	Deprecated          bool
+	ScorecardViolation  bool
+	ProvenanceViolation bool
+	HashMismatches      []string
 }
 
 // MarshalJSON implements the json.Marshaler interface.
@@ -206,10 +325,40 @@ type License string
 type PackageVulns struct {
 	Package           PackageInfo                `json:"package"`
 	DepGroups         []string                   `json:"dependency_groups,omitempty"`
+	IntroducedBy      [][]string                 `json:"introduced_by,omitempty"`
+	Parents           []PackageParent            `json:"parents,omitempty"`
 	Vulnerabilities   []*osvschema.Vulnerability `json:"vulnerabilities,omitempty"`
 	Groups            []GroupInfo                `json:"groups,omitempty"`
 	Licenses          []License                  `json:"licenses,omitempty"`
 	LicenseViolations []License                  `json:"license_violations,omitempty"`
+	// Scorecard is the OpenSSF Scorecard of the package's source repository,
+	// if it was looked up and deps.dev has one
+	Scorecard *Scorecard `json:"scorecard,omitempty"`
+	// ScorecardViolation is true if the overall score of the Scorecard is
+	// below the minimum required
+	ScorecardViolation bool `json:"scorecard_violation,omitempty"`
+	// Dependents is how many packages depend on this version of the package,
+	// if it was looked up and deps.dev knows of the version
+	Dependents *Dependents `json:"dependents,omitempty"`
+	// Release is when this version of the package was published and how it
+	// compares to the latest release, if it was looked up
+	Release *Release `json:"release,omitempty"`
+	// Outdated is true if the package is further behind its latest release
+	// than allowed
+	Outdated bool `json:"outdated,omitempty"`
+	// TyposquatOf are popular packages in the same ecosystem whose names are
+	// only a few characters away from this one, if they were looked up
+	TyposquatOf []string `json:"typosquat_of,omitempty"`
+	// Provenance is the build provenance this version of the package was
+	// published with, if it was looked up and deps.dev knows of the version
+	Provenance *Provenance `json:"provenance,omitempty"`
+	// ProvenanceViolation is true if the package has no verified provenance
+	// and this is not allowed
+	ProvenanceViolation bool `json:"provenance_violation,omitempty"`
+	// HashMismatches are the hashes that a requirements file pins this
+	// version of the package to that none of the files published for it have,
+	// which may mean that the pinned files were tampered with
+	HashMismatches []string `json:"hash_mismatches,omitempty"`
 }
 
 // MarshalJSON implements the json.Marshaler interface.
@@ -299,6 +448,114 @@ type GroupInfo struct {
 	// Map of Vulnerability IDs to AnalysisInfo
 	ExperimentalAnalysis map[string]AnalysisInfo `json:"experimental_analysis,omitempty"`
 	MaxSeverity          string                  `json:"max_severity"`
+	// FixedVersion is the first version of the package above the installed
+	// one that fixes the vulnerabilities in the group, if any is known
+	FixedVersion string `json:"fixed_version,omitempty"`
+	// EPSS is the highest score among the CVEs in the group, if scores were
+	// looked up and any were found
+	EPSS *EPSSScore `json:"epss,omitempty"`
+	// SecondarySources link to the advisories in other databases that the
+	// severity or description of the group was filled in from, as it was
+	// missing from OSV
+	SecondarySources []string `json:"secondary_sources,omitempty"`
+	// KEV is the entry of the group's CVEs in the CISA Known Exploited
+	// Vulnerabilities catalog, if the catalog was checked and lists any
+	KEV *KEVEntry `json:"kev,omitempty"`
+	// Malicious is true if the group includes a malicious package (MAL-)
+	// advisory, meaning the package version is malware rather than
+	// vulnerable
+	Malicious bool `json:"malicious,omitempty"`
+}
+
+// KEVEntry is an entry of the CISA Known Exploited Vulnerabilities catalog.
+type KEVEntry struct {
+	CVE string `json:"cve"`
+	// Date the CVE was added to the catalog, as YYYY-MM-DD
+	DateAdded string `json:"date_added"`
+	// Date by which US federal agencies must apply RequiredAction
+	DueDate        string `json:"due_date,omitempty"`
+	RequiredAction string `json:"required_action,omitempty"`
+	// Whether the CVE is known to be used in ransomware campaigns
+	KnownRansomwareCampaignUse bool `json:"known_ransomware_campaign_use,omitempty"`
+}
+
+// EPSSScore is the Exploit Prediction Scoring System score of a CVE, as
+// published by FIRST.
+type EPSSScore struct {
+	// Probability of exploitation activity in the next 30 days, from 0 to 1
+	Probability float64 `json:"probability"`
+	// Percentile of the probability among all scored CVEs, from 0 to 1
+	Percentile float64 `json:"percentile"`
+	// Date the score was published, as YYYY-MM-DD
+	Date string `json:"date,omitempty"`
+}
+
+// Scorecard is the OpenSSF Scorecard of a source repository, as published by
+// deps.dev.
+type Scorecard struct {
+	// Project is the repository the scorecard is for, e.g. "github.com/foo/bar"
+	Project string `json:"project"`
+	// Date the scorecard was produced, as YYYY-MM-DD
+	Date string `json:"date,omitempty"`
+	// OverallScore is the weighted average of the scores of all the checks,
+	// from 0 to 10
+	OverallScore float64 `json:"overall_score"`
+	// Checks are the results of a selection of the checks
+	Checks []ScorecardCheck `json:"checks,omitempty"`
+}
+
+// Dependents is how many packages in the same ecosystem depend on a package
+// version, as counted by deps.dev.
+type Dependents struct {
+	// Count is the number of packages that depend on the version
+	Count int `json:"count"`
+	// Direct is the number of dependents that require the version themselves
+	Direct int `json:"direct"`
+	// Indirect is the number of dependents that only pull the version in
+	// through their own dependencies
+	Indirect int `json:"indirect"`
+}
+
+// Release describes when a package version was published and how far behind
+// the latest release of the package it is, as known to deps.dev.
+type Release struct {
+	// Date the version was published, as YYYY-MM-DD
+	PublishedAt string `json:"published_at,omitempty"`
+	// LatestVersion is the latest stable release of the package
+	LatestVersion string `json:"latest_version"`
+	// Date the latest version was published, as YYYY-MM-DD
+	LatestPublishedAt string `json:"latest_published_at,omitempty"`
+	// MajorVersionsBehind is how many major versions the latest version is
+	// ahead of this one
+	MajorVersionsBehind int `json:"major_versions_behind"`
+	// MonthsBehind is how many whole months passed between this version
+	// and the latest version being published
+	MonthsBehind int `json:"months_behind"`
+}
+
+// Provenance describes the Sigstore signed attestation of how a package
+// version was built, such as npm provenance or a PyPI attestation, as
+// verified by deps.dev. All fields are empty if the version has none.
+type Provenance struct {
+	// Verified is true if the signature of the attestation was verified
+	Verified bool `json:"verified"`
+	// Type is the predicate type of the attestation, e.g.
+	// https://slsa.dev/provenance/v1
+	Type string `json:"type,omitempty"`
+	// SourceRepository is the repository the version was built from
+	SourceRepository string `json:"source_repository,omitempty"`
+	// Commit is the commit of the repository the version was built from
+	Commit string `json:"commit,omitempty"`
+	// URL of the attestation
+	URL string `json:"url,omitempty"`
+}
+
+// ScorecardCheck is the result of one of the checks of an OpenSSF Scorecard.
+type ScorecardCheck struct {
+	Name string `json:"name"`
+	// Score from 0 to 10, or negative if the check could not be run
+	Score  int    `json:"score"`
+	Reason string `json:"reason,omitempty"`
 }
 
 // IsCalled returns true if any analysis performed determines that the vulnerability is being called
@@ -339,15 +596,52 @@ func (groupInfo *GroupInfo) IndexString() string {
 type AnalysisInfo struct {
 	Called      bool `json:"called"`
 	Unimportant bool `json:"unimportant"`
+	// CallStack is how the vulnerable symbol is called, starting from the
+	// vulnerable symbol itself and ending in the scanned code, if known
+	CallStack []StackFrame `json:"call_stack,omitempty"`
+}
+
+// StackFrame is a call to a function in a call stack.
+type StackFrame struct {
+	Module string `json:"module"`
+	// Package is the import path of the package the function is in
+	Package string `json:"package,omitempty"`
+	// Function is the name of the function, prefixed with its receiver for
+	// methods, e.g. "(*Client).Do"
+	Function string `json:"function,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
+
+// String returns the fully qualified name of the function, e.g.
+// "net/http.(*Client).Do".
+func (frame StackFrame) String() string {
+	if frame.Function == "" {
+		return frame.Package
+	}
+
+	return frame.Package + "." + frame.Function
+}
+
+// PackageParent is a package that directly depends on another package, as
+// found by transitive dependency resolution.
+type PackageParent struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Requirement string `json:"requirement,omitempty"`
 }
 
 type PackageInfo struct {
-	Name          string              `json:"name"`
-	OSPackageName string              `json:"os_package_name,omitempty"`
-	Version       string              `json:"version"`
-	Ecosystem     string              `json:"ecosystem"`
-	Commit        string              `json:"commit,omitempty"`
-	Deprecated    bool                `json:"deprecated,omitempty"`
-	ImageOrigin   *ImageOriginDetails `json:"image_origin_details,omitempty"`
-	Inventory     *extractor.Package  `json:"-"`
+	Name                string              `json:"name"`
+	OSPackageName       string              `json:"os_package_name,omitempty"`
+	Version             string              `json:"version"`
+	Ecosystem           string              `json:"ecosystem"`
+	Commit              string              `json:"commit,omitempty"`
+	Deprecated          bool                `json:"deprecated,omitempty"`
+	DeprecationReason   string              `json:"deprecation_reason,omitempty"`
+	DeclaredVersion     string              `json:"declared_version,omitempty"`
+	DeclaredRequirement string              `json:"declared_requirement,omitempty"`
+	ImageOrigin         *ImageOriginDetails `json:"image_origin_details,omitempty"`
+	Inventory           *extractor.Package  `json:"-"`
 }
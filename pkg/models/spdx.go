@@ -0,0 +1,8 @@
+package models
+
+type SPDXVersion int
+
+const (
+	SPDXVersion23 SPDXVersion = iota
+	SPDXVersion30
+)
@@ -0,0 +1,393 @@
+package osvscanner
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/osv-scalibr/artifact/image"
+	"github.com/google/osv-scanner/v2/internal/cachedregexp"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/imagehelpers"
+	"github.com/opencontainers/go-digest"
+)
+
+// recommendBaseImageUpgrade compares the base image of the scanned image to
+// a newer version of it, by scanning that version straight from its registry,
+// returning nil if they could not be compared. The version compared to is the
+// one asked for, or otherwise the newest tag of the same major version and
+// variant as the tag the image was built on.
+func recommendBaseImageUpgrade(ctx context.Context, actions ScannerActions, vulnResults models.VulnerabilityResults) *models.BaseImageUpgrade {
+	baseImage := nearestBaseImage(vulnResults.ImageMetadata)
+	if baseImage == "" {
+		cmdlogger.Warnf("The base image of %q could not be identified, so no upgrade of it can be recommended", actions.Image)
+		return nil
+	}
+
+	opts := imagehelpers.RemoteOptions(ctx, actions.RequestUserAgent, actions.HTTPClient)
+
+	var candidate string
+	if actions.BaseImageUpgrade != "" {
+		candidate = baseImageCandidate(baseImage, actions.BaseImageUpgrade)
+	} else {
+		var err error
+		candidate, err = newestBaseImageCandidate(baseImage, vulnResults.ImageMetadata, opts)
+		if err != nil {
+			cmdlogger.Warnf("No upgrade of the base image %q could be found, use --experimental-base-image-upgrade to compare it to a given tag: %v", baseImage, err)
+			return nil
+		}
+	}
+
+	ref, err := name.ParseReference(candidate)
+	if err != nil {
+		cmdlogger.Warnf("%q is not a valid image reference: %v", candidate, err)
+		return nil
+	}
+
+	cmdlogger.Infof("Comparing the base image %q to %q", baseImage, candidate)
+
+	imgDigest, candidateDiffIDs, err := remoteImageLayers(ref, opts)
+	if err != nil {
+		cmdlogger.Warnf("Failed to pull %q to compare the base image to: %v", candidate, err)
+		return nil
+	}
+
+	upgrade := &models.BaseImageUpgrade{
+		BaseImage: baseImage,
+		Candidate: candidate,
+		Digest:    imgDigest,
+	}
+
+	if isBuiltOn(vulnResults.ImageMetadata.LayerMetadata, candidateDiffIDs) {
+		upgrade.UpToDate = true
+		upgrade.Vulnerabilities = len(imageVulnerabilities(vulnResults, true))
+
+		return upgrade
+	}
+
+	// scan the exact version of the candidate that was compared to
+	candidateActions := actions
+	candidateActions.Image = ref.Context().Digest(imgDigest.String()).String()
+	candidateActions.IsImageArchive = false
+	candidateActions.IsImageRemote = true
+	candidateActions.RecommendBaseImageUpgrade = false
+	candidateActions.BaseImageUpgrade = ""
+	candidateActions.Baseline = nil
+
+	candidateResults, err := DoContainerScan(candidateActions)
+	if err != nil && !errors.Is(err, ErrVulnerabilitiesFound) && !errors.Is(err, ErrNoPackagesFound) {
+		cmdlogger.Warnf("Failed to scan %q to compare the base image to: %v", candidate, err)
+		return nil
+	}
+
+	compareBaseImageVulnerabilities(upgrade, vulnResults, candidateResults)
+
+	return upgrade
+}
+
+// remoteImageLayers pulls the manifest and config of an image from its
+// registry, returning its digest and the diff IDs of its layers.
+func remoteImageLayers(ref name.Reference, opts []remote.Option) (digest.Digest, []digest.Digest, error) {
+	img, err := image.V1ImageFromRemoteName(ref.String(), opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	imgDigest, err := img.Digest()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read the digest: %w", err)
+	}
+
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read the config: %w", err)
+	}
+
+	diffIDs := make([]digest.Digest, 0, len(cfg.RootFS.DiffIDs))
+	for _, diffID := range cfg.RootFS.DiffIDs {
+		diffIDs = append(diffIDs, digest.Digest(diffID.String()))
+	}
+
+	return digest.Digest(imgDigest.String()), diffIDs, nil
+}
+
+// maxTagsCompared is how many tags of the base image are pulled at most to
+// find the one the image was built on.
+const maxTagsCompared = 30
+
+// newestBaseImageCandidate finds the tag of the base image that the image was
+// built on, and returns the newest tag of the same major version and variant,
+// which is that same tag if there is no newer one, as it may still have been
+// rebuilt since.
+func newestBaseImageCandidate(baseImage string, imgMetadata *models.ImageMetadata, opts []remote.Option) (string, error) {
+	repo, err := name.NewRepository(baseImage)
+	if err != nil {
+		return "", err
+	}
+
+	versions := baseImageVersions(imgMetadata)
+	if len(versions) == 0 {
+		return "", errors.New("the version it was built from could not be identified")
+	}
+
+	tags, err := remote.List(repo, opts...)
+	if err != nil {
+		return "", fmt.Errorf("failed to list its tags: %w", err)
+	}
+
+	compared := 0
+	for _, tag := range versionTags(tags, versions) {
+		if compared == maxTagsCompared {
+			break
+		}
+		compared++
+
+		// tags that cannot be pulled, such as those with no image for the
+		// platform, cannot be what the image was built on
+		_, diffIDs, err := remoteImageLayers(repo.Tag(tag.name), opts)
+		if err == nil && isBuiltOn(imgMetadata.LayerMetadata, diffIDs) {
+			return baseImage + ":" + newestImageTag(tag, tags).name, nil
+		}
+	}
+
+	return "", fmt.Errorf("none of the tags of versions %s are what the image was built on", strings.Join(versions, ", "))
+}
+
+// baseImageVersions returns the versions declared by the layers of the base
+// image the image was directly built on, in the order of the layers, as the
+// candidates for which tag of the base image it was built from. These are the
+// versions of the software an image packages, as its Dockerfile usually
+// declares them, e.g. "ENV PYTHON_VERSION=3.12.1" or
+// "LABEL org.opencontainers.image.version=24.04".
+func baseImageVersions(imgMetadata *models.ImageMetadata) []string {
+	re := cachedregexp.MustCompile(`\b(?:[A-Z][A-Z0-9_]*_VERSION|org\.opencontainers\.image\.version)[= ]["']?v?(\d+(?:\.\d+)+)`)
+
+	var versions []string
+	for _, layer := range imgMetadata.LayerMetadata {
+		if layer.BaseImageIndex != 1 {
+			continue
+		}
+		if !strings.Contains(layer.Command, "ENV") && !strings.Contains(layer.Command, "LABEL") {
+			continue
+		}
+		for _, match := range re.FindAllStringSubmatch(layer.Command, -1) {
+			if !slices.Contains(versions, match[1]) {
+				versions = append(versions, match[1])
+			}
+		}
+	}
+
+	return versions
+}
+
+// imageTag is a tag of an image of the form <version>[-<variant>], such as
+// "3.12.1" or "3.12.1-slim-bookworm".
+type imageTag struct {
+	name    string
+	version []int
+	variant string
+}
+
+func parseImageTag(tag string) (imageTag, bool) {
+	match := cachedregexp.MustCompile(`^v?(\d+(?:\.\d+)*)(?:-(.+))?$`).FindStringSubmatch(tag)
+	if match == nil {
+		return imageTag{}, false
+	}
+
+	parts := strings.Split(match[1], ".")
+	version := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return imageTag{}, false
+		}
+		version = append(version, n)
+	}
+
+	return imageTag{name: tag, version: version, variant: match[2]}, true
+}
+
+// versionTags returns the tags of any of the versions, with the most specific
+// variants first, as these keep the most of the base image the same, e.g.
+// "3.12.1-slim-bookworm" rather than "3.12.1-slim" for the same image.
+func versionTags(tags []string, versions []string) []imageTag {
+	var result []imageTag
+	for _, tag := range tags {
+		parsed, ok := parseImageTag(tag)
+		if !ok {
+			continue
+		}
+		for _, version := range versions {
+			if want, _ := parseImageTag(version); slices.Equal(parsed.version, want.version) {
+				result = append(result, parsed)
+				break
+			}
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b imageTag) int {
+		return cmp.Compare(len(b.variant), len(a.variant))
+	})
+
+	return result
+}
+
+// newestImageTag returns the newest of the tags with the same major version
+// and variant as current, which is current itself if there is none newer.
+// Of the tags of the same version, the most precise one is returned, so that
+// e.g. "3.13.2" is picked rather than "3.13", which moves on by itself.
+func newestImageTag(current imageTag, tags []string) imageTag {
+	newest := current
+	for _, tag := range tags {
+		parsed, ok := parseImageTag(tag)
+		if !ok || parsed.variant != current.variant || parsed.version[0] != current.version[0] {
+			continue
+		}
+		if slices.Compare(parsed.version, newest.version) > 0 {
+			newest = parsed
+		}
+	}
+
+	return newest
+}
+
+// nearestBaseImage returns the name of the base image that the image was
+// directly built on, if it could be identified.
+func nearestBaseImage(imgMetadata *models.ImageMetadata) string {
+	// the image itself is the 0th "base image", and the base images are in
+	// order from the newest to the oldest
+	if imgMetadata == nil || len(imgMetadata.BaseImages) < 2 || len(imgMetadata.BaseImages[1]) == 0 {
+		return ""
+	}
+
+	return imgMetadata.BaseImages[1][0].Name
+}
+
+// baseImageCandidate returns the reference of the version of the base image to
+// compare to, which is either a tag of the base image or a reference to
+// another image altogether.
+func baseImageCandidate(baseImage string, upgrade string) string {
+	if strings.ContainsAny(upgrade, ":@/") {
+		return upgrade
+	}
+
+	return baseImage + ":" + upgrade
+}
+
+// isBuiltOn reports whether the image with the given layers is built on the
+// image with the given diff IDs, by them being its first non-empty layers.
+func isBuiltOn(layers []models.LayerMetadata, diffIDs []digest.Digest) bool {
+	if len(diffIDs) == 0 {
+		return false
+	}
+
+	i := 0
+	for _, layer := range layers {
+		if layer.DiffID == "" {
+			continue
+		}
+		if layer.DiffID != diffIDs[i] {
+			return false
+		}
+
+		i++
+		if i == len(diffIDs) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// vulnerabilityKey identifies a vulnerability in a package, regardless of the
+// version of the package it was found in.
+type vulnerabilityKey struct {
+	packageName string
+	id          string
+}
+
+// imageVulnerabilities returns the keys of each group of vulnerabilities in
+// the image, under each of the group's aliases, leaving out those that are
+// not counted as findings; with onlyBaseImage, only the vulnerabilities in the
+// packages that come from the image's base image are returned.
+func imageVulnerabilities(vulnResults models.VulnerabilityResults, onlyBaseImage bool) [][]vulnerabilityKey {
+	var groups [][]vulnerabilityKey
+	seen := make(map[vulnerabilityKey]bool)
+
+	for _, source := range vulnResults.Results {
+		for _, pkg := range source.Packages {
+			origin := pkg.Package.ImageOrigin
+			if onlyBaseImage && (origin == nil || !origin.InBaseImage) {
+				continue
+			}
+
+			for _, group := range pkg.Groups {
+				if !group.IsCalled() || group.IsGroupUnimportant() {
+					continue
+				}
+
+				keys := make([]vulnerabilityKey, 0, len(group.Aliases))
+				duplicate := false
+				for _, alias := range group.Aliases {
+					key := vulnerabilityKey{packageName: pkg.Package.Name, id: alias}
+					duplicate = duplicate || seen[key]
+					seen[key] = true
+					keys = append(keys, key)
+				}
+
+				// the same vulnerability can be in more than one version of a
+				// package, such as in different layers
+				if !duplicate {
+					groups = append(groups, keys)
+				}
+			}
+		}
+	}
+
+	return groups
+}
+
+// compareBaseImageVulnerabilities counts how many of the vulnerabilities from
+// the base image the candidate does not have, and how many it has that the
+// base image does not.
+func compareBaseImageVulnerabilities(upgrade *models.BaseImageUpgrade, vulnResults, candidateResults models.VulnerabilityResults) {
+	baseGroups := imageVulnerabilities(vulnResults, true)
+	candidateGroups := imageVulnerabilities(candidateResults, false)
+
+	upgrade.Vulnerabilities = len(baseGroups)
+	upgrade.Eliminated = countMissing(baseGroups, candidateGroups)
+	upgrade.Introduced = countMissing(candidateGroups, baseGroups)
+}
+
+// countMissing counts the groups that share no key with any of the others.
+func countMissing(groups, others [][]vulnerabilityKey) int {
+	present := make(map[vulnerabilityKey]bool)
+	for _, group := range others {
+		for _, key := range group {
+			present[key] = true
+		}
+	}
+
+	missing := 0
+	for _, group := range groups {
+		found := false
+		for _, key := range group {
+			if present[key] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing++
+		}
+	}
+
+	return missing
+}
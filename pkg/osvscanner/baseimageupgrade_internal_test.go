@@ -0,0 +1,205 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/opencontainers/go-digest"
+)
+
+func Test_baseImageCandidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		upgrade string
+		want    string
+	}{
+		{upgrade: "latest", want: "python:latest"},
+		{upgrade: "3.12-slim", want: "python:3.12-slim"},
+		{upgrade: "python:3.12", want: "python:3.12"},
+		{upgrade: "ghcr.io/org/python", want: "ghcr.io/org/python"},
+		{upgrade: "python@sha256:abc", want: "python@sha256:abc"},
+	}
+	for _, tt := range tests {
+		if got := baseImageCandidate("python", tt.upgrade); got != tt.want {
+			t.Errorf("baseImageCandidate(%q) = %q, want %q", tt.upgrade, got, tt.want)
+		}
+	}
+}
+
+func Test_nearestBaseImage(t *testing.T) {
+	t.Parallel()
+
+	imgMetadata := &models.ImageMetadata{
+		BaseImages: [][]models.BaseImageDetails{
+			{},
+			{{Name: "python"}},
+			{{Name: "debian"}},
+		},
+	}
+	if got := nearestBaseImage(imgMetadata); got != "python" {
+		t.Errorf("nearestBaseImage() = %q, want %q", got, "python")
+	}
+
+	if got := nearestBaseImage(&models.ImageMetadata{BaseImages: [][]models.BaseImageDetails{{}}}); got != "" {
+		t.Errorf("nearestBaseImage() = %q, want no base image", got)
+	}
+}
+
+func Test_baseImageVersions(t *testing.T) {
+	t.Parallel()
+
+	imgMetadata := &models.ImageMetadata{
+		LayerMetadata: []models.LayerMetadata{
+			{Command: "ENV DEBIAN_VERSION=12.5", BaseImageIndex: 2},
+			{Command: "ENV PYTHON_VERSION=3.12.1", BaseImageIndex: 1},
+			{Command: "ENV PYTHON_PIP_VERSION=23.2.1", BaseImageIndex: 1},
+			{Command: "RUN pip install --version=3.0", BaseImageIndex: 1},
+			{Command: "ENV APP_VERSION=1.2.3", BaseImageIndex: 0},
+		},
+	}
+
+	want := []string{"3.12.1", "23.2.1"}
+	if diff := cmp.Diff(want, baseImageVersions(imgMetadata)); diff != "" {
+		t.Errorf("baseImageVersions() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_versionTags(t *testing.T) {
+	t.Parallel()
+
+	tags := []string{"latest", "3.12", "3.12.1", "3.12.1-slim", "3.12.1-slim-bookworm", "3.12.2", "23.2.1-rc"}
+
+	got := make([]string, 0, len(tags))
+	for _, tag := range versionTags(tags, []string{"3.12.1", "23.2.1"}) {
+		got = append(got, tag.name)
+	}
+	want := []string{"3.12.1-slim-bookworm", "3.12.1-slim", "23.2.1-rc", "3.12.1"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("versionTags() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_newestImageTag(t *testing.T) {
+	t.Parallel()
+
+	tags := []string{
+		"latest", "slim", "3", "3.12", "3.12.1", "3.12.1-slim", "3.13", "3.13-slim",
+		"3.13.2", "3.13.2-slim", "3.13.3-alpine", "4.0.0", "4.0.0-slim",
+	}
+
+	tests := []struct {
+		current string
+		want    string
+	}{
+		{current: "3.12.1", want: "3.13.2"},
+		{current: "3.12.1-slim", want: "3.13.2-slim"},
+		// there is nothing newer, so the same tag is compared to
+		{current: "3.13.3-alpine", want: "3.13.3-alpine"},
+		{current: "4.0.0", want: "4.0.0"},
+	}
+	for _, tt := range tests {
+		current, ok := parseImageTag(tt.current)
+		if !ok {
+			t.Fatalf("parseImageTag(%q) failed", tt.current)
+		}
+		if got := newestImageTag(current, tags).name; got != tt.want {
+			t.Errorf("newestImageTag(%q) = %q, want %q", tt.current, got, tt.want)
+		}
+	}
+}
+
+func Test_isBuiltOn(t *testing.T) {
+	t.Parallel()
+
+	layers := []models.LayerMetadata{
+		{DiffID: "sha256:aaa"},
+		{DiffID: "", IsEmpty: true},
+		{DiffID: "sha256:bbb"},
+		{DiffID: "sha256:ccc"},
+	}
+
+	tests := []struct {
+		name    string
+		diffIDs []digest.Digest
+		want    bool
+	}{
+		{name: "same_base_layers", diffIDs: []digest.Digest{"sha256:aaa", "sha256:bbb"}, want: true},
+		{name: "different_base_layers", diffIDs: []digest.Digest{"sha256:aaa", "sha256:ddd"}, want: false},
+		{name: "more_layers_than_the_image", diffIDs: []digest.Digest{"sha256:aaa", "sha256:bbb", "sha256:ccc", "sha256:ddd"}, want: false},
+		{name: "no_layers", diffIDs: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isBuiltOn(layers, tt.diffIDs); got != tt.want {
+				t.Errorf("isBuiltOn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_compareBaseImageVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	fromBase := &models.ImageOriginDetails{Index: 0, InBaseImage: true, BaseImage: "debian"}
+	fromApp := &models.ImageOriginDetails{Index: 3}
+
+	vulnResults := models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Packages: []models.PackageVulns{
+				{
+					Package: models.PackageInfo{Name: "openssl", Version: "1.1.1n", ImageOrigin: fromBase},
+					Groups: []models.GroupInfo{
+						{IDs: []string{"DSA-1"}, Aliases: []string{"CVE-2024-1", "DSA-1"}},
+						{IDs: []string{"DSA-2"}, Aliases: []string{"CVE-2024-2", "DSA-2"}},
+					},
+				},
+				{
+					Package: models.PackageInfo{Name: "zlib", Version: "1.2.13", ImageOrigin: fromBase},
+					Groups: []models.GroupInfo{
+						{IDs: []string{"DSA-3"}, Aliases: []string{"CVE-2024-3", "DSA-3"}},
+					},
+				},
+				{
+					// vulnerabilities from the image's own layers are not counted
+					Package: models.PackageInfo{Name: "curl", Version: "7.88.1", ImageOrigin: fromApp},
+					Groups: []models.GroupInfo{
+						{IDs: []string{"DSA-4"}, Aliases: []string{"CVE-2024-4", "DSA-4"}},
+					},
+				},
+			},
+		}},
+	}
+
+	candidateResults := models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Packages: []models.PackageVulns{
+				{
+					// the same vulnerability, in a newer version of the package
+					Package: models.PackageInfo{Name: "openssl", Version: "3.0.11", ImageOrigin: fromApp},
+					Groups: []models.GroupInfo{
+						{IDs: []string{"CVE-2024-2"}, Aliases: []string{"CVE-2024-2"}},
+						{IDs: []string{"DSA-5"}, Aliases: []string{"CVE-2024-5", "DSA-5"}},
+					},
+				},
+			},
+		}},
+	}
+
+	got := &models.BaseImageUpgrade{BaseImage: "debian", Candidate: "debian:latest"}
+	compareBaseImageVulnerabilities(got, vulnResults, candidateResults)
+
+	want := &models.BaseImageUpgrade{
+		BaseImage:       "debian",
+		Candidate:       "debian:latest",
+		Vulnerabilities: 3,
+		Eliminated:      2,
+		Introduced:      1,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("compareBaseImageVulnerabilities() mismatch (-want +got):\n%s", diff)
+	}
+}
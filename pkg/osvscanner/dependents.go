@@ -0,0 +1,30 @@
+package osvscanner
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// sortByDependents orders the packages of each source from the one with the
+// most dependents to the one with the fewest, with those without a count
+// last, so the vulnerabilities with the widest blast radius come first.
+func sortByDependents(vulnResults *models.VulnerabilityResults) {
+	compare := func(a, b models.PackageVulns) int {
+		switch {
+		case a.Dependents == nil && b.Dependents == nil:
+			return 0
+		case a.Dependents == nil:
+			return 1
+		case b.Dependents == nil:
+			return -1
+		}
+
+		return cmp.Compare(b.Dependents.Count, a.Dependents.Count)
+	}
+
+	for i := range vulnResults.Results {
+		slices.SortStableFunc(vulnResults.Results[i].Packages, compare)
+	}
+}
@@ -0,0 +1,58 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func Test_sortByDependents(t *testing.T) {
+	t.Parallel()
+
+	pkg := func(name string, dependents *models.Dependents) models.PackageVulns {
+		return models.PackageVulns{
+			Package:    models.PackageInfo{Name: name, Version: "1.0.0", Ecosystem: "npm"},
+			Dependents: dependents,
+		}
+	}
+	results := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					pkg("unknown", nil),
+					pkg("obscure", &models.Dependents{Count: 3, Direct: 3}),
+					pkg("popular", &models.Dependents{Count: 180000, Direct: 60000, Indirect: 120000}),
+					pkg("private", nil),
+					pkg("unused", &models.Dependents{}),
+				},
+			},
+			{
+				Source: models.SourceInfo{Path: "/path/to/other/package-lock.json", Type: models.SourceTypeProjectPackage},
+				Packages: []models.PackageVulns{
+					pkg("obscure", &models.Dependents{Count: 3, Direct: 3}),
+					pkg("popular", &models.Dependents{Count: 180000, Direct: 60000, Indirect: 120000}),
+				},
+			},
+		},
+	}
+
+	sortByDependents(results)
+
+	var got [][]string
+	for _, source := range results.Results {
+		var names []string
+		for _, pkg := range source.Packages {
+			names = append(names, pkg.Package.Name)
+		}
+		got = append(got, names)
+	}
+	want := [][]string{
+		{"popular", "obscure", "unused", "unknown", "private"},
+		{"popular", "obscure"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("sortByDependents() order mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,190 @@
+package osvscanner
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"deps.dev/util/maven"
+	"deps.dev/util/semver"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/datasource"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
+)
+
+// npmLockfiles are the lockfiles that pin the dependencies declared in the
+// package.json next to them.
+var npmLockfiles = map[string]bool{
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+	"bun.lock":            true,
+}
+
+// detectDrift finds the dependencies declared in manifests that are missing
+// from, or inconsistent with, the versions that were scanned for them: those
+// in the lockfile next to a package.json, or those that a pom.xml was
+// resolved to.
+func detectDrift(packages []imodels.PackageScanResult) []models.Drift {
+	// the versions of each package found in each lockfile
+	locked := make(map[string]map[string][]string)
+	for _, psr := range packages {
+		pkg := psr.PackageInfo
+		location := pkg.Location()
+		if location == "" || pkg.Version() == "" {
+			continue
+		}
+
+		base := filepath.Base(location)
+		if !npmLockfiles[base] && base != "pom.xml" {
+			continue
+		}
+
+		if locked[location] == nil {
+			locked[location] = make(map[string][]string)
+		}
+		locked[location][pkg.Name()] = append(locked[location][pkg.Name()], pkg.Version())
+	}
+
+	var drift []models.Drift
+	for _, lockfile := range slices.Sorted(maps.Keys(locked)) {
+		if filepath.Base(lockfile) == "pom.xml" {
+			drift = append(drift, mavenDrift(lockfile, locked[lockfile])...)
+		} else {
+			drift = append(drift, npmDrift(filepath.Join(filepath.Dir(lockfile), "package.json"), lockfile, locked[lockfile])...)
+		}
+	}
+
+	return drift
+}
+
+// npmDrift compares the dependencies declared in a package.json with the
+// versions of them in its lockfile.
+func npmDrift(manifest, lockfile string, locked map[string][]string) []models.Drift {
+	content, err := os.ReadFile(manifest)
+	if err != nil {
+		// lockfiles without a package.json next to them have nothing to drift from
+		return nil
+	}
+
+	var packageJSON struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+	}
+	if err := json.Unmarshal(content, &packageJSON); err != nil {
+		cmdlogger.Warnf("Could not check %s for drift from its lockfile: %v", manifest, err)
+		return nil
+	}
+
+	declared := make(map[string]string)
+	for _, deps := range []map[string]string{packageJSON.DevDependencies, packageJSON.OptionalDependencies, packageJSON.Dependencies} {
+		for name, requirement := range deps {
+			declared[name] = requirement
+		}
+	}
+
+	var drift []models.Drift
+	for _, name := range slices.Sorted(maps.Keys(declared)) {
+		requirement := declared[name]
+
+		// local packages, aliases, tags, and git and tarball URLs are not
+		// versions that a lockfile can be checked against
+		constraint, err := semver.NPM.ParseConstraint(requirement)
+		if err != nil || strings.Contains(requirement, ":") {
+			continue
+		}
+
+		d := models.Drift{
+			Manifest:  manifest,
+			Lockfile:  lockfile,
+			Ecosystem: string(osvconstants.EcosystemNPM),
+			Name:      name,
+			Declared:  requirement,
+			Locked:    locked[name],
+		}
+
+		switch {
+		case len(d.Locked) == 0:
+			d.Reason = models.DriftMissing
+		case !slices.ContainsFunc(d.Locked, constraint.Match):
+			d.Reason = models.DriftInconsistent
+		default:
+			continue
+		}
+
+		drift = append(drift, d)
+	}
+
+	return drift
+}
+
+// mavenDrift compares the versions of the dependencies declared in a pom.xml
+// with the effective versions that they were resolved to.
+func mavenDrift(pom string, resolved map[string][]string) []models.Drift {
+	f, err := os.Open(pom)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var project maven.Project
+	if err := datasource.NewMavenDecoder(f).Decode(&project); err != nil {
+		cmdlogger.Warnf("Could not check %s for drift from its effective versions: %v", pom, err)
+		return nil
+	}
+
+	var drift []models.Drift
+	for _, dep := range project.Dependencies {
+		version := strings.TrimSpace(string(dep.Version))
+
+		// versions from properties or dependency management are only known
+		// once the pom is resolved, and test dependencies are not resolved
+		if version == "" || strings.Contains(version, "${") || dep.Optional.Boolean() || dep.Scope == "test" || dep.Scope == "system" {
+			continue
+		}
+
+		name := string(dep.GroupID) + ":" + string(dep.ArtifactID)
+		d := models.Drift{
+			Manifest:  pom,
+			Lockfile:  pom,
+			Ecosystem: string(osvconstants.EcosystemMaven),
+			Name:      name,
+			Declared:  version,
+			Locked:    resolved[name],
+		}
+
+		switch {
+		case len(d.Locked) == 0:
+			d.Reason = models.DriftMissing
+		case !slices.ContainsFunc(d.Locked, mavenMatcher(version)):
+			d.Reason = models.DriftInconsistent
+		default:
+			continue
+		}
+
+		drift = append(drift, d)
+	}
+
+	return drift
+}
+
+// mavenMatcher returns whether versions satisfy a version declared in a
+// pom.xml, which is either a range or the exact version to use.
+func mavenMatcher(declared string) func(string) bool {
+	if strings.ContainsAny(declared, "[(") {
+		if constraint, err := semver.Maven.ParseConstraint(declared); err == nil {
+			return constraint.Match
+		}
+	}
+
+	return func(version string) bool {
+		return version == declared
+	}
+}
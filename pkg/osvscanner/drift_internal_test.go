@@ -0,0 +1,85 @@
+package osvscanner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func Test_detectDrift(t *testing.T) {
+	t.Parallel()
+
+	packageLock := filepath.Join("testdata", "drift", "npm", "package-lock.json")
+	pom := filepath.Join("testdata", "drift", "maven", "pom.xml")
+	newResult := func(name, version, purlType, location string) imodels.PackageScanResult {
+		return imodels.PackageScanResult{
+			PackageInfo: imodels.FromInventory(&extractor.Package{
+				Name:      name,
+				Version:   version,
+				PURLType:  purlType,
+				Locations: []string{location},
+			}),
+		}
+	}
+
+	packages := []imodels.PackageScanResult{
+		newResult("express", "4.18.2", purl.TypeNPM, packageLock),
+		newResult("semver", "7.6.3", purl.TypeNPM, packageLock),
+		newResult("jest", "28.1.3", purl.TypeNPM, packageLock),
+		newResult("jest", "29.7.0", purl.TypeNPM, packageLock),
+		newResult("typescript", "5.4.5", purl.TypeNPM, packageLock),
+		// lockfiles without a package.json cannot drift
+		newResult("lodash", "4.17.20", purl.TypeNPM, filepath.Join("testdata", "drift", "orphan", "package-lock.json")),
+		newResult("org.slf4j:slf4j-api", "2.0.9", purl.TypeMaven, pom),
+		newResult("com.fasterxml.jackson.core:jackson-databind", "2.17.0", purl.TypeMaven, pom),
+		newResult("com.google.guava:guava", "33.0.0-jre", purl.TypeMaven, pom),
+		// only lockfiles are compared with their manifests
+		newResult("requests", "2.31.0", purl.TypePyPi, filepath.Join("testdata", "drift", "requirements.txt")),
+	}
+
+	want := []models.Drift{
+		{
+			Manifest:  pom,
+			Lockfile:  pom,
+			Ecosystem: "Maven",
+			Name:      "com.fasterxml.jackson.core:jackson-databind",
+			Declared:  "[2.15,2.16)",
+			Locked:    []string{"2.17.0"},
+			Reason:    models.DriftInconsistent,
+		},
+		{
+			Manifest:  pom,
+			Lockfile:  pom,
+			Ecosystem: "Maven",
+			Name:      "org.apache.commons:commons-lang3",
+			Declared:  "3.14.0",
+			Reason:    models.DriftMissing,
+		},
+		{
+			Manifest:  filepath.Join("testdata", "drift", "npm", "package.json"),
+			Lockfile:  packageLock,
+			Ecosystem: "npm",
+			Name:      "express",
+			Declared:  "^4.19.0",
+			Locked:    []string{"4.18.2"},
+			Reason:    models.DriftInconsistent,
+		},
+		{
+			Manifest:  filepath.Join("testdata", "drift", "npm", "package.json"),
+			Lockfile:  packageLock,
+			Ecosystem: "npm",
+			Name:      "lodash",
+			Declared:  "^4.17.21",
+			Reason:    models.DriftMissing,
+		},
+	}
+
+	if diff := cmp.Diff(want, detectDrift(packages)); diff != "" {
+		t.Errorf("detectDrift() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,96 @@
+package osvscanner
+
+import (
+	"fmt"
+
+	"github.com/google/osv-scanner/v2/internal/config"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+)
+
+// applyEnrichers turns the enrichers in the config file on or off and sets
+// their options. The command line flags take precedence: enrichers turned on
+// by a flag stay on, and options that were already set are kept. The options
+// of enrichers that are turned off are ignored.
+func applyEnrichers(actions *ExperimentalScannerActions, enrichers config.Enrichers) error {
+	transitive := &actions.TransitiveScanning
+
+	if pypi := enrichers.PyPIDepsDev; pypi.IsDisabled() {
+		transitive.PyPIDisabled = true
+	} else {
+		if _, err := depsdev.ParseConflictStrategy(pypi.ConflictStrategy); err != nil {
+			return fmt.Errorf("invalid pypiDepsDev enricher config: %w", err)
+		}
+		setDefault(&transitive.MaxDepth, pypi.MaxDepth)
+		setDefault(&transitive.Parallelism, pypi.Parallelism)
+		setDefault(&transitive.ConflictStrategy, pypi.ConflictStrategy)
+		setDefault(&transitive.PythonVersion, pypi.PythonVersion)
+		setDefault(&transitive.PythonPlatform, pypi.PythonPlatform)
+	}
+
+	if maven := enrichers.MavenDepsDev; maven.IsDisabled() {
+		transitive.MavenDisabled = true
+	} else {
+		setDefault(&transitive.MavenRegistry, maven.Registry)
+		if len(transitive.MavenExcludedScopes) == 0 {
+			transitive.MavenExcludedScopes = maven.ExcludedScopes
+		}
+	}
+
+	enable(&actions.SBOMEnrichment, enrichers.SBOMDepsDev)
+	enable(&actions.FlagDeprecatedPackages, enrichers.Deprecation)
+	enable(&actions.SecondaryAdvisories, enrichers.SecondaryAdvisories)
+	enable(&actions.Typosquats, enrichers.Typosquats)
+
+	if scorecard := enrichers.Scorecard; !scorecard.IsDisabled() {
+		enable(&actions.Scorecard, scorecard.Enricher)
+		setDefault(&actions.FailOnScorecardBelow, scorecard.FailBelow)
+	}
+
+	if epss := enrichers.EPSS; !epss.IsDisabled() {
+		enable(&actions.EPSS, epss.Enricher)
+		setDefault(&actions.MinEPSS, epss.MinScore)
+		actions.SortByEPSS = actions.SortByEPSS || epss.Sort
+		setDefault(&actions.EPSSBaseURL, epss.BaseURL)
+	}
+
+	if kev := enrichers.KEV; !kev.IsDisabled() {
+		enable(&actions.KEV, kev.Enricher)
+		actions.FailOnKEV = actions.FailOnKEV || kev.FailOn
+		setDefault(&actions.KEVCatalogURL, kev.CatalogURL)
+	}
+
+	if provenance := enrichers.Provenance; !provenance.IsDisabled() {
+		enable(&actions.Provenance, provenance.Enricher)
+		actions.FailOnMissingProvenance = actions.FailOnMissingProvenance || provenance.FailOnMissing
+	}
+
+	if dependents := enrichers.Dependents; !dependents.IsDisabled() {
+		enable(&actions.Dependents, dependents.Enricher)
+		actions.SortByDependents = actions.SortByDependents || dependents.Sort
+	}
+
+	if releases := enrichers.ReleaseInfo; !releases.IsDisabled() {
+		enable(&actions.ReleaseInfo, releases.Enricher)
+		actions.Outdated = actions.Outdated || releases.Outdated
+		setDefault(&actions.OutdatedMajorVersions, releases.OutdatedMajorVersions)
+		setDefault(&actions.OutdatedMonths, releases.OutdatedMonths)
+	}
+
+	return nil
+}
+
+// enable turns on an enricher if the config file turns it on.
+func enable(on *bool, e config.Enricher) {
+	if e.IsEnabled() {
+		*on = true
+	}
+}
+
+// setDefault sets an option to its value in the config file, unless it is
+// already set.
+func setDefault[T comparable](option *T, fromConfig T) {
+	var zero T
+	if *option == zero {
+		*option = fromConfig
+	}
+}
@@ -0,0 +1,77 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/config"
+)
+
+func Test_applyEnrichers(t *testing.T) {
+	t.Parallel()
+
+	on, off := true, false
+	enrichers := config.Enrichers{
+		PyPIDepsDev: config.PyPIDepsDevEnricher{
+			Enricher: config.Enricher{Enabled: &off},
+			MaxDepth: 2,
+		},
+		MavenDepsDev: config.MavenDepsDevEnricher{
+			Registry:       "https://maven-mirror.example.com/maven2",
+			ExcludedScopes: []string{"test"},
+		},
+		Scorecard: config.ScorecardEnricher{
+			Enricher:  config.Enricher{Enabled: &on},
+			FailBelow: 4.5,
+		},
+		EPSS: config.EPSSEnricher{MinScore: 0.1},
+		KEV: config.KEVEnricher{
+			Enricher: config.Enricher{Enabled: &off},
+			FailOn:   true,
+		},
+		Typosquats: config.Enricher{Enabled: &off},
+	}
+
+	actions := ExperimentalScannerActions{
+		TransitiveScanning: TransitiveScanningActions{MavenRegistry: "https://maven.internal"},
+		Typosquats:         true,
+	}
+	if err := applyEnrichers(&actions, enrichers); err != nil {
+		t.Fatalf("applyEnrichers() error = %v", err)
+	}
+
+	// The options of the disabled PyPI enricher are ignored
+	if !actions.TransitiveScanning.PyPIDisabled || actions.TransitiveScanning.MaxDepth != 0 {
+		t.Errorf("PyPIDisabled, MaxDepth = %v, %d, want true, 0", actions.TransitiveScanning.PyPIDisabled, actions.TransitiveScanning.MaxDepth)
+	}
+	if actions.TransitiveScanning.MavenDisabled {
+		t.Errorf("MavenDisabled = true, want false")
+	}
+	// The registry was already set
+	if actions.TransitiveScanning.MavenRegistry != "https://maven.internal" {
+		t.Errorf("MavenRegistry = %q, want %q", actions.TransitiveScanning.MavenRegistry, "https://maven.internal")
+	}
+	if diff := cmp.Diff([]string{"test"}, actions.TransitiveScanning.MavenExcludedScopes); diff != "" {
+		t.Errorf("MavenExcludedScopes mismatch (-want +got):\n%s", diff)
+	}
+	if !actions.Scorecard || actions.FailOnScorecardBelow != 4.5 {
+		t.Errorf("Scorecard, FailOnScorecardBelow = %v, %v, want true, 4.5", actions.Scorecard, actions.FailOnScorecardBelow)
+	}
+	if actions.MinEPSS != 0.1 {
+		t.Errorf("MinEPSS = %v, want 0.1", actions.MinEPSS)
+	}
+	if actions.KEV || actions.FailOnKEV {
+		t.Errorf("KEV, FailOnKEV = %v, %v, want false, false", actions.KEV, actions.FailOnKEV)
+	}
+	// Enrichers turned on by a flag stay on
+	if !actions.Typosquats {
+		t.Errorf("Typosquats = false, want true")
+	}
+
+	enrichers = config.Enrichers{
+		PyPIDepsDev: config.PyPIDepsDevEnricher{ConflictStrategy: "lowest-wins"},
+	}
+	if err := applyEnrichers(&ExperimentalScannerActions{}, enrichers); err == nil {
+		t.Errorf("applyEnrichers() with an unsupported conflict strategy should fail")
+	}
+}
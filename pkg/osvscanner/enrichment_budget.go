@@ -0,0 +1,79 @@
+package osvscanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/osv-scalibr/enricher"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+)
+
+// enrichmentBudget is a time budget shared by every enricher in a scan.
+// The clock starts when the first enricher runs, so that extraction does not
+// count towards it.
+type enrichmentBudget struct {
+	timeout      time.Duration
+	degradations *depsdev.Degradations
+
+	once     sync.Once
+	deadline time.Time
+}
+
+// context returns a context that is cancelled once the budget is spent.
+func (b *enrichmentBudget) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	b.once.Do(func() {
+		b.deadline = time.Now().Add(b.timeout)
+	})
+
+	return context.WithDeadline(ctx, b.deadline)
+}
+
+// budgetedEnricher runs an enricher within an enrichmentBudget.
+type budgetedEnricher struct {
+	enricher.Enricher
+
+	budget *enrichmentBudget
+}
+
+// Enrich runs the wrapped enricher until the budget is spent. Running out of
+// time is not an error: whatever was enriched so far is kept, and the
+// enricher is recorded as having only partially enriched the inventory.
+func (e *budgetedEnricher) Enrich(ctx context.Context, input *enricher.ScanInput, inv *inventory.Inventory) error {
+	ctx, cancel := e.budget.context(ctx)
+	defer cancel()
+
+	err := e.Enricher.Enrich(ctx, input, inv)
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+
+	cmdlogger.Warnf("Enrichment by %s stopped after exceeding the %s time budget, results are partially enriched", e.Name(), e.budget.timeout)
+	e.budget.degradations.RecordEnricher(e.Name(), fmt.Sprintf("enrichment timed out after %s, results are partially enriched", e.budget.timeout))
+
+	return nil
+}
+
+// withEnrichmentBudget wraps every enricher in plugins so that together they
+// run for no longer than timeout.
+func withEnrichmentBudget(plugins []plugin.Plugin, timeout time.Duration, degradations *depsdev.Degradations) []plugin.Plugin {
+	if timeout <= 0 {
+		return plugins
+	}
+
+	budget := &enrichmentBudget{timeout: timeout, degradations: degradations}
+	wrapped := make([]plugin.Plugin, 0, len(plugins))
+	for _, p := range plugins {
+		if e, ok := p.(enricher.Enricher); ok {
+			p = &budgetedEnricher{Enricher: e, budget: budget}
+		}
+		wrapped = append(wrapped, p)
+	}
+
+	return wrapped
+}
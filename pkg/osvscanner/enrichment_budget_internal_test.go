@@ -0,0 +1,58 @@
+package osvscanner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/osv-scalibr/enricher"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+)
+
+// slowEnricher adds a package, then blocks until its context is done.
+type slowEnricher struct{}
+
+func (slowEnricher) Name() string                       { return "test/slow" }
+func (slowEnricher) Version() int                       { return 0 }
+func (slowEnricher) Requirements() *plugin.Capabilities { return &plugin.Capabilities{} }
+func (slowEnricher) RequiredPlugins() []string          { return nil }
+
+func (slowEnricher) Enrich(ctx context.Context, _ *enricher.ScanInput, inv *inventory.Inventory) error {
+	inv.Packages = append(inv.Packages, &extractor.Package{Name: "enriched"})
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func Test_withEnrichmentBudget(t *testing.T) {
+	t.Parallel()
+
+	degradations := &depsdev.Degradations{}
+	plugins := withEnrichmentBudget([]plugin.Plugin{slowEnricher{}}, 10*time.Millisecond, degradations)
+
+	inv := &inventory.Inventory{}
+	if err := plugins[0].(enricher.Enricher).Enrich(t.Context(), nil, inv); err != nil {
+		t.Fatalf("Enrich() error = %v, want nil", err)
+	}
+
+	if len(inv.Packages) != 1 {
+		t.Errorf("len(inv.Packages) = %d, want partially enriched results to be kept", len(inv.Packages))
+	}
+
+	got := degradations.List()
+	if len(got) != 1 || got[0].Enricher != "test/slow" || got[0].Path != "" {
+		t.Errorf("degradations = %v, want the enricher to be recorded as degraded", got)
+	}
+}
+
+func Test_withEnrichmentBudget_NoTimeout(t *testing.T) {
+	t.Parallel()
+
+	plugins := []plugin.Plugin{slowEnricher{}}
+	if got := withEnrichmentBudget(plugins, 0, nil); got[0] != plugins[0] {
+		t.Errorf("withEnrichmentBudget() wrapped the enricher without a timeout")
+	}
+}
@@ -0,0 +1,169 @@
+package osvscanner
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/epss"
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// applyEPSS looks up EPSS scores when they are needed, then filters and
+// sorts the results by them as requested.
+func applyEPSS(vulnResults *models.VulnerabilityResults, actions ScannerActions) {
+	if !actions.EPSS && actions.MinEPSS <= 0 && !actions.SortByEPSS {
+		return
+	}
+	if actions.CompareOffline {
+		return
+	}
+
+	userAgent := "osv-scanner-api"
+	if actions.RequestUserAgent != "" {
+		userAgent = actions.RequestUserAgent
+	}
+	client := epss.NewClient(actions.EPSSBaseURL, actions.HTTPClient, userAgent)
+	if err := enrichEPSS(context.Background(), vulnResults, client); err != nil {
+		cmdlogger.Warnf("Failed to look up EPSS scores: %v", err)
+		return
+	}
+
+	if actions.MinEPSS > 0 {
+		if filtered := filterByEPSS(vulnResults, actions.MinEPSS); filtered > 0 {
+			cmdlogger.Infof(
+				"Filtered %d %s with an EPSS score below %g",
+				filtered,
+				output.Form(filtered, "vulnerability", "vulnerabilities"),
+				actions.MinEPSS,
+			)
+		}
+	}
+	if actions.SortByEPSS {
+		sortByEPSS(vulnResults)
+	}
+}
+
+// enrichEPSS attaches to each group of vulnerabilities the highest EPSS score
+// of the CVEs among its aliases.
+func enrichEPSS(ctx context.Context, vulnResults *models.VulnerabilityResults, client *epss.Client) error {
+	var cves []string
+	for _, source := range vulnResults.Results {
+		for _, pkg := range source.Packages {
+			for _, group := range pkg.Groups {
+				for _, alias := range group.Aliases {
+					if strings.HasPrefix(alias, "CVE-") {
+						cves = append(cves, alias)
+					}
+				}
+			}
+		}
+	}
+	slices.Sort(cves)
+	cves = slices.Compact(cves)
+	if len(cves) == 0 {
+		return nil
+	}
+
+	scores, err := client.Scores(ctx, cves)
+	if err != nil {
+		return err
+	}
+
+	for i := range vulnResults.Results {
+		for j := range vulnResults.Results[i].Packages {
+			groups := vulnResults.Results[i].Packages[j].Groups
+			for k := range groups {
+				for _, alias := range groups[k].Aliases {
+					score, ok := scores[alias]
+					if ok && (groups[k].EPSS == nil || score.Probability > groups[k].EPSS.Probability) {
+						groups[k].EPSS = &score
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// filterByEPSS removes the groups of vulnerabilities with an EPSS score below
+// minEPSS, keeping those without a score and malicious packages. Returns the
+// number of vulnerabilities removed.
+func filterByEPSS(vulnResults *models.VulnerabilityResults, minEPSS float64) int {
+	removedCount := 0
+	for i := range vulnResults.Results {
+		for j := range vulnResults.Results[i].Packages {
+			pkg := &vulnResults.Results[i].Packages[j]
+
+			removed := make(map[string]bool)
+			var groups []models.GroupInfo
+			for _, group := range pkg.Groups {
+				if group.EPSS != nil && group.EPSS.Probability < minEPSS && !group.Malicious {
+					for _, id := range group.IDs {
+						removed[id] = true
+					}
+
+					continue
+				}
+				groups = append(groups, group)
+			}
+			if len(removed) == 0 {
+				continue
+			}
+
+			vulns := slices.DeleteFunc(slices.Clone(pkg.Vulnerabilities), func(v *osvschema.Vulnerability) bool {
+				return removed[v.GetId()]
+			})
+			removedCount += len(pkg.Vulnerabilities) - len(vulns)
+			pkg.Groups = groups
+			pkg.Vulnerabilities = vulns
+		}
+	}
+
+	return removedCount
+}
+
+// sortByEPSS orders the packages of each source, and the groups of
+// vulnerabilities of each package, from the highest EPSS score to the lowest,
+// with those without a score last.
+func sortByEPSS(vulnResults *models.VulnerabilityResults) {
+	compare := func(a, b *models.EPSSScore) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return 1
+		case b == nil:
+			return -1
+		}
+
+		return cmp.Compare(b.Probability, a.Probability)
+	}
+	packageEPSS := func(pkg models.PackageVulns) *models.EPSSScore {
+		var highest *models.EPSSScore
+		for _, group := range pkg.Groups {
+			if compare(group.EPSS, highest) < 0 {
+				highest = group.EPSS
+			}
+		}
+
+		return highest
+	}
+
+	for i := range vulnResults.Results {
+		packages := vulnResults.Results[i].Packages
+		for j := range packages {
+			slices.SortStableFunc(packages[j].Groups, func(a, b models.GroupInfo) int {
+				return compare(a.EPSS, b.EPSS)
+			})
+		}
+		slices.SortStableFunc(packages, func(a, b models.PackageVulns) int {
+			return compare(packageEPSS(a), packageEPSS(b))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package osvscanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/epss"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+func epssTestResults() *models.VulnerabilityResults {
+	return &models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source: models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+			Packages: []models.PackageVulns{
+				{
+					Package: models.PackageInfo{Name: "low", Version: "1.0.0", Ecosystem: "npm"},
+					Vulnerabilities: []*osvschema.Vulnerability{
+						{Id: "GHSA-low"},
+						{Id: "GHSA-none"},
+					},
+					Groups: []models.GroupInfo{
+						{IDs: []string{"GHSA-low"}, Aliases: []string{"CVE-2020-0001", "GHSA-low"}},
+						{IDs: []string{"GHSA-none"}, Aliases: []string{"GHSA-none"}},
+					},
+				},
+				{
+					Package:         models.PackageInfo{Name: "high", Version: "2.0.0", Ecosystem: "npm"},
+					Vulnerabilities: []*osvschema.Vulnerability{{Id: "GHSA-high"}},
+					Groups: []models.GroupInfo{
+						{IDs: []string{"GHSA-high"}, Aliases: []string{"CVE-2021-44228", "CVE-2021-45046", "GHSA-high"}},
+					},
+				},
+			},
+		}},
+	}
+}
+
+func Test_enrichEPSS(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Query().Get("cve"), "CVE-2020-0001,CVE-2021-44228,CVE-2021-45046"; got != want {
+			t.Errorf("cve query = %q, want %q", got, want)
+		}
+		fmt.Fprint(w, `{"data":[
+			{"cve":"CVE-2020-0001","epss":"0.001","percentile":"0.2"},
+			{"cve":"CVE-2021-44228","epss":"0.944","percentile":"0.999"},
+			{"cve":"CVE-2021-45046","epss":"0.5","percentile":"0.9"}
+		]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	results := epssTestResults()
+	if err := enrichEPSS(t.Context(), results, epss.NewClient(srv.URL, nil, "")); err != nil {
+		t.Fatalf("enrichEPSS() error: %v", err)
+	}
+
+	var got []*models.EPSSScore
+	for _, pkg := range results.Results[0].Packages {
+		for _, group := range pkg.Groups {
+			got = append(got, group.EPSS)
+		}
+	}
+	want := []*models.EPSSScore{
+		{Probability: 0.001, Percentile: 0.2},
+		nil,
+		{Probability: 0.944, Percentile: 0.999},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("enrichEPSS() scores mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_filterByEPSS(t *testing.T) {
+	t.Parallel()
+
+	results := epssTestResults()
+	packages := results.Results[0].Packages
+	packages[0].Groups[0].EPSS = &models.EPSSScore{Probability: 0.001}
+	packages[1].Groups[0].EPSS = &models.EPSSScore{Probability: 0.944}
+
+	if got := filterByEPSS(results, 0.1); got != 1 {
+		t.Errorf("filterByEPSS() = %d, want 1", got)
+	}
+
+	var gotIDs []string
+	for _, pkg := range results.Results[0].Packages {
+		for _, vuln := range pkg.Vulnerabilities {
+			gotIDs = append(gotIDs, vuln.GetId())
+		}
+		for _, group := range pkg.Groups {
+			gotIDs = append(gotIDs, "group:"+group.IDs[0])
+		}
+	}
+	wantIDs := []string{"GHSA-none", "group:GHSA-none", "GHSA-high", "group:GHSA-high"}
+	if diff := cmp.Diff(wantIDs, gotIDs); diff != "" {
+		t.Errorf("filterByEPSS() kept (-want +got):\n%s", diff)
+	}
+}
+
+func Test_sortByEPSS(t *testing.T) {
+	t.Parallel()
+
+	results := epssTestResults()
+	packages := results.Results[0].Packages
+	packages[0].Groups[1].EPSS = &models.EPSSScore{Probability: 0.02}
+	packages[1].Groups[0].EPSS = &models.EPSSScore{Probability: 0.944}
+
+	sortByEPSS(results)
+
+	var got []string
+	for _, pkg := range results.Results[0].Packages {
+		for _, group := range pkg.Groups {
+			got = append(got, pkg.Package.Name+":"+group.IDs[0])
+		}
+	}
+	want := []string{"high:GHSA-high", "low:GHSA-none", "low:GHSA-low"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("sortByEPSS() order mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -5,11 +5,15 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 
 	"github.com/gobwas/glob"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	"github.com/google/osv-scalibr/plugin"
 	"github.com/google/osv-scanner/v2/internal/cachedregexp"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/config"
 )
 
 // excludePatterns holds parsed patterns for excluding paths during scanning.
@@ -116,3 +120,87 @@ func parseExcludeArg(arg string) (string, string) {
 	// Return all prefixes (including unknown ones) to let the caller handle validation
 	return patternType, pattern
 }
+
+// pathExclusion leaves out the files under dir whose paths relative to it
+// match an ExcludePaths glob from the config.
+type pathExclusion struct {
+	dir  string
+	glob glob.Glob
+}
+
+// parsePathExclusions parses the ExcludePaths globs that apply to each of the
+// directories being scanned.
+func parsePathExclusions(manager *config.Manager, dirs []string) ([]pathExclusion, error) {
+	var exclusions []pathExclusion
+
+	for _, dir := range dirs {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pattern := range manager.ExcludePaths(absDir) {
+			g, err := glob.Compile(pattern, '/')
+			if err != nil {
+				return nil, fmt.Errorf("invalid ExcludePaths glob %q: %w", pattern, err)
+			}
+			exclusions = append(exclusions, pathExclusion{dir: absDir, glob: g})
+		}
+	}
+
+	return exclusions, nil
+}
+
+// matches reports whether the file at the absolute path is excluded.
+func (e pathExclusion) matches(path string) bool {
+	rel, err := filepath.Rel(e.dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	// the leading slash lets "**/testdata/**" match a testdata directory at the
+	// top of dir too
+	return e.glob.Match(rel) || e.glob.Match("/"+rel)
+}
+
+// withPathExclusions wraps the extractors in plugins so that the files under
+// root that are excluded are not extracted, unless they were given explicitly.
+func withPathExclusions(plugins []plugin.Plugin, exclusions []pathExclusion, root string, specificPaths []string) []plugin.Plugin {
+	if len(exclusions) == 0 {
+		return plugins
+	}
+
+	wrapped := make([]plugin.Plugin, 0, len(plugins))
+	for _, p := range plugins {
+		if ex, ok := p.(filesystem.Extractor); ok {
+			p = &excludedPathsExtractor{Extractor: ex, exclusions: exclusions, root: root, specificPaths: specificPaths}
+		}
+		wrapped = append(wrapped, p)
+	}
+
+	return wrapped
+}
+
+// excludedPathsExtractor does not require the files that are excluded, so
+// that their packages are never extracted.
+type excludedPathsExtractor struct {
+	filesystem.Extractor
+
+	exclusions    []pathExclusion
+	root          string
+	specificPaths []string
+}
+
+func (e *excludedPathsExtractor) FileRequired(api filesystem.FileAPI) bool {
+	path := filepath.Join(e.root, filepath.FromSlash(api.Path()))
+	if !slices.Contains(e.specificPaths, path) {
+		for _, exclusion := range e.exclusions {
+			if exclusion.matches(path) {
+				return false
+			}
+		}
+	}
+
+	return e.Extractor.FileRequired(api)
+}
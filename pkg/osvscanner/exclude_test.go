@@ -1,7 +1,11 @@
 package osvscanner
 
 import (
+	"path/filepath"
+	"slices"
 	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/config"
 )
 
 func Test_parseExcludeArg(t *testing.T) {
@@ -155,3 +159,43 @@ func Test_parseExcludePatterns(t *testing.T) {
 		})
 	}
 }
+
+func Test_pathExclusion_matches(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(string(filepath.Separator), "repo")
+	manager := config.Manager{
+		OverrideConfig: &config.Config{ExcludePaths: []string{"**/testdata/**", "examples/**"}},
+	}
+	exclusions, err := parsePathExclusions(&manager, []string{dir})
+	if err != nil {
+		t.Fatalf("parsePathExclusions() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"testdata/package-lock.json", true},
+		{"cmd/app/testdata/fixtures/go.mod", true},
+		{"examples/web/package-lock.json", true},
+		{"examples/package-lock.json", true},
+		{"package-lock.json", false},
+		{"cmd/examples/package-lock.json", false},
+		{"cmd/testdata.go", false},
+		// files outside of the scanned directory are never excluded
+		{"../other/testdata/go.mod", false},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, filepath.FromSlash(tt.path))
+		got := slices.ContainsFunc(exclusions, func(e pathExclusion) bool { return e.matches(path) })
+		if got != tt.want {
+			t.Errorf("excluded(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	manager.OverrideConfig.ExcludePaths = []string{"examples/[a"}
+	if _, err := parsePathExclusions(&manager, []string{dir}); err == nil {
+		t.Errorf("parsePathExclusions() with an invalid glob should fail")
+	}
+}
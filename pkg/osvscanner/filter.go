@@ -3,7 +3,11 @@ package osvscanner
 
 import (
 	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
 
+	"github.com/google/osv-scanner/v2/internal/ci"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
 	"github.com/google/osv-scanner/v2/internal/config"
 	"github.com/google/osv-scanner/v2/internal/imodels"
@@ -61,11 +65,7 @@ func filterUnscannablePackages(scanResults *results.ScanResults, actions Scanner
 func filterNonContainerRelevantPackages(scanResults *results.ScanResults) {
 	packageResults := make([]imodels.PackageScanResult, 0, len(scanResults.PackageScanResults))
 	for _, psr := range scanResults.PackageScanResults {
-		p := psr.PackageInfo
-
-		// Almost all packages with linux as a SourceName are kernel packages
-		// which does not apply within a container, as containers use the host's kernel
-		if p.Name() == "linux" {
+		if !isContainerRelevant(psr.PackageInfo) {
 			continue
 		}
 
@@ -79,6 +79,14 @@ func filterNonContainerRelevantPackages(scanResults *results.ScanResults) {
 	scanResults.PackageScanResults = packageResults
 }
 
+// isContainerRelevant reports whether a package found in a container image
+// applies to containers run from it.
+func isContainerRelevant(p imodels.PackageInfo) bool {
+	// Almost all packages with linux as a SourceName are kernel packages
+	// which does not apply within a container, as containers use the host's kernel
+	return p.Name() != "linux"
+}
+
 // filterIgnoredPackages removes ignore scanned packages according to config. Returns filtered scanned packages.
 func filterIgnoredPackages(scanResults *results.ScanResults) {
 	configManager := &scanResults.ConfigManager
@@ -117,9 +125,13 @@ func filterResults(vulnResults *models.VulnerabilityResults, configManager *conf
 		configToUse := configManager.Get(pkgSrc.Source.Path)
 		var newPackages []models.PackageVulns
 		for _, pkgVulns := range pkgSrc.Packages {
-			newVulns := filterPackageVulns(pkgVulns, configToUse)
+			newVulns, ignored := filterPackageVulns(pkgVulns, configToUse)
+			for _, ig := range ignored {
+				ig.Source = pkgSrc.Source
+				vulnResults.ExperimentalIgnored = append(vulnResults.ExperimentalIgnored, ig)
+			}
 			removedCount += len(pkgVulns.Vulnerabilities) - len(newVulns.Vulnerabilities)
-			if allPackages || len(newVulns.Vulnerabilities) > 0 || len(pkgVulns.LicenseViolations) > 0 || pkgVulns.Package.Deprecated {
+			if allPackages || hasFindings(newVulns) {
 				newPackages = append(newPackages, newVulns)
 			}
 		}
@@ -134,9 +146,17 @@ func filterResults(vulnResults *models.VulnerabilityResults, configManager *conf
 	return removedCount
 }
 
-// Filters package-grouped vulnerabilities according to config, preserving ordering. Returns filtered package vulnerabilities.
-func filterPackageVulns(pkgVulns models.PackageVulns, configToUse config.Config) models.PackageVulns {
+// hasFindings reports whether there is anything to report about a package
+// once its vulnerabilities have been filtered.
+func hasFindings(pkgVulns models.PackageVulns) bool {
+	return len(pkgVulns.Vulnerabilities) > 0 || len(pkgVulns.LicenseViolations) > 0 || pkgVulns.Package.Deprecated || pkgVulns.ScorecardViolation || pkgVulns.Outdated || len(pkgVulns.TyposquatOf) > 0 || (pkgVulns.Provenance != nil && !pkgVulns.Provenance.Verified)
+}
+
+// Filters package-grouped vulnerabilities according to config, preserving ordering. Returns filtered package vulnerabilities,
+// and the groups of vulnerabilities that were ignored.
+func filterPackageVulns(pkgVulns models.PackageVulns, configToUse config.Config) (models.PackageVulns, []models.IgnoredVulnerability) {
 	ignoredVulns := map[string]struct{}{}
+	var ignoredGroups []models.IgnoredVulnerability
 
 	// Iterate over groups first to remove all aliases of ignored vulnerabilities.
 	var newGroups []models.GroupInfo
@@ -166,6 +186,11 @@ func filterPackageVulns(pkgVulns models.PackageVulns, configToUse config.Config)
 				}
 
 				ignoreLine.MarkAsUsed()
+				ignoredGroups = append(ignoredGroups, models.IgnoredVulnerability{
+					Package: pkgVulns.Package,
+					Group:   group,
+					Reason:  ignoreLine.Reason,
+				})
 
 				break
 			}
@@ -188,5 +213,159 @@ func filterPackageVulns(pkgVulns models.PackageVulns, configToUse config.Config)
 	pkgVulns.Groups = newGroups
 	pkgVulns.Vulnerabilities = newVulns
 
+	return pkgVulns, ignoredGroups
+}
+
+// filterUnreachable removes the vulnerabilities that call analysis found are
+// not called, preserving order. Malicious packages are always kept. Returns the
+// number of vulnerabilities removed.
+func filterUnreachable(vulnResults *models.VulnerabilityResults, allPackages bool) int {
+	removedCount := 0
+	newResults := []models.PackageSource{}
+	for _, pkgSrc := range vulnResults.Results {
+		var newPackages []models.PackageVulns
+		for _, pkgVulns := range pkgSrc.Packages {
+			newVulns := filterUnreachablePackageVulns(pkgVulns)
+			removedCount += len(pkgVulns.Vulnerabilities) - len(newVulns.Vulnerabilities)
+			if allPackages || hasFindings(newVulns) {
+				newPackages = append(newPackages, newVulns)
+			}
+		}
+		if len(newPackages) > 0 {
+			pkgSrc.Packages = newPackages
+			newResults = append(newResults, pkgSrc)
+		}
+	}
+	vulnResults.Results = newResults
+
+	return removedCount
+}
+
+func filterUnreachablePackageVulns(pkgVulns models.PackageVulns) models.PackageVulns {
+	unreachable := map[string]struct{}{}
+
+	var newGroups []models.GroupInfo
+	for _, group := range pkgVulns.Groups {
+		if group.IsCalled() || group.Malicious {
+			newGroups = append(newGroups, group)
+			continue
+		}
+		for _, id := range group.Aliases {
+			unreachable[id] = struct{}{}
+		}
+	}
+
+	var newVulns []*osvschema.Vulnerability
+	if len(newGroups) > 0 {
+		for _, vuln := range pkgVulns.Vulnerabilities {
+			if _, filtered := unreachable[vuln.GetId()]; !filtered {
+				newVulns = append(newVulns, vuln)
+			}
+		}
+	}
+
+	pkgVulns.Groups = newGroups
+	pkgVulns.Vulnerabilities = newVulns
+
 	return pkgVulns
 }
+
+// baselineSource is a source of the results, along with the key it is matched
+// on against the baseline.
+type baselineSource struct {
+	source models.SourceInfo
+	key    models.SourceInfo
+	// rel is the path of the source relative to its scan root, if it is under one
+	rel string
+}
+
+// newBaselineSource keys a source on its path relative to the first of roots
+// that it is under, joined onto that root as it was given, so that the key
+// does not depend on where the roots are checked out.
+func newBaselineSource(source models.SourceInfo, roots []string) baselineSource {
+	bs := baselineSource{source: source, key: source}
+	for _, root := range roots {
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(absRoot, source.Path)
+		if err != nil || !filepath.IsLocal(rel) {
+			continue
+		}
+		bs.rel = filepath.ToSlash(rel)
+		bs.key.Path = path.Join(filepath.ToSlash(filepath.Clean(root)), bs.rel)
+
+		break
+	}
+
+	return bs
+}
+
+// baselineKey returns the key that a source of the baseline is matched on,
+// which for a source that is not under any of roots, such as one scanned from
+// another checkout, is that of the first source of the results whose path
+// relative to its root is a suffix of its path.
+func baselineKey(source models.SourceInfo, roots []string, sources []baselineSource) models.SourceInfo {
+	bs := newBaselineSource(source, roots)
+	if bs.rel != "" {
+		return bs.key
+	}
+
+	p := filepath.ToSlash(source.Path)
+	for _, s := range sources {
+		if s.rel != "" && s.source.Type == source.Type && (p == s.rel || strings.HasSuffix(p, "/"+s.rel)) {
+			return s.key
+		}
+	}
+
+	return source
+}
+
+// filterBaseline removes the vulnerabilities that the baseline already has for
+// the same package of the same source, along with the packages of the baseline
+// that are left without any, using ci.DiffVulnerabilityResultsByAliases. Sources
+// are matched on their paths relative to the scan root they are under, so a
+// baseline scanned from another location still applies. Returns the number of
+// vulnerabilities removed.
+func filterBaseline(vulnResults *models.VulnerabilityResults, baseline *models.VulnerabilityResults, roots []string) int {
+	sources := make([]baselineSource, 0, len(vulnResults.Results))
+	byKey := make(map[models.SourceInfo]models.SourceInfo, len(vulnResults.Results))
+	keyed := models.VulnerabilityResults{Results: make([]models.PackageSource, 0, len(vulnResults.Results))}
+	for _, pkgSrc := range vulnResults.Results {
+		bs := newBaselineSource(pkgSrc.Source, roots)
+		sources = append(sources, bs)
+		byKey[bs.key] = bs.source
+		pkgSrc.Source = bs.key
+		keyed.Results = append(keyed.Results, pkgSrc)
+	}
+
+	keyedBaseline := models.VulnerabilityResults{Results: make([]models.PackageSource, 0, len(baseline.Results))}
+	for _, pkgSrc := range baseline.Results {
+		pkgSrc.Source = baselineKey(pkgSrc.Source, roots, sources)
+		keyedBaseline.Results = append(keyedBaseline.Results, pkgSrc)
+	}
+
+	diff := ci.DiffVulnerabilityResultsByAliases(keyedBaseline, keyed)
+
+	removedCount := countVulns(*vulnResults) - countVulns(diff)
+	newResults := make([]models.PackageSource, 0, len(diff.Results))
+	for _, pkgSrc := range diff.Results {
+		pkgSrc.Source = byKey[pkgSrc.Source]
+		newResults = append(newResults, pkgSrc)
+	}
+	vulnResults.Results = newResults
+
+	return removedCount
+}
+
+func countVulns(vulnResults models.VulnerabilityResults) int {
+	count := 0
+	for _, pkgSrc := range vulnResults.Results {
+		for _, pkgVulns := range pkgSrc.Packages {
+			count += len(pkgVulns.Vulnerabilities)
+		}
+	}
+
+	return count
+}
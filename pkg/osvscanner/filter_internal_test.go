@@ -4,9 +4,11 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/osv-scanner/v2/internal/config"
 	"github.com/google/osv-scanner/v2/internal/testutility"
 	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
 )
 
 func Test_filterResults(t *testing.T) {
@@ -54,3 +56,151 @@ func Test_filterResults(t *testing.T) {
 		})
 	}
 }
+
+func Test_filterUnreachable(t *testing.T) {
+	t.Parallel()
+
+	source := models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage}
+	group := func(id string, called bool) models.GroupInfo {
+		return models.GroupInfo{
+			IDs:                  []string{id},
+			Aliases:              []string{id},
+			ExperimentalAnalysis: map[string]models.AnalysisInfo{id: {Called: called}},
+		}
+	}
+
+	vulnResults := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: source,
+				Packages: []models.PackageVulns{
+					{
+						Package: models.PackageInfo{Name: "express", Version: "4.17.1", Ecosystem: "npm"},
+						Vulnerabilities: []*osvschema.Vulnerability{
+							{Id: "GHSA-1"},
+							{Id: "GHSA-2"},
+							{Id: "GHSA-3"},
+						},
+						Groups: []models.GroupInfo{
+							group("GHSA-1", true),
+							group("GHSA-2", false),
+							// not analyzed
+							{IDs: []string{"GHSA-3"}, Aliases: []string{"GHSA-3"}},
+						},
+					},
+					{
+						Package:         models.PackageInfo{Name: "jest", Version: "29.0.0", Ecosystem: "npm"},
+						Vulnerabilities: []*osvschema.Vulnerability{{Id: "GHSA-4"}},
+						Groups:          []models.GroupInfo{group("GHSA-4", false)},
+					},
+					{
+						Package:         models.PackageInfo{Name: "evil", Version: "1.0.0", Ecosystem: "npm"},
+						Vulnerabilities: []*osvschema.Vulnerability{{Id: "MAL-1"}},
+						Groups: []models.GroupInfo{
+							func() models.GroupInfo {
+								g := group("MAL-1", false)
+								g.Malicious = true
+
+								return g
+							}(),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if removed := filterUnreachable(&vulnResults, false); removed != 2 {
+		t.Errorf("filterUnreachable() = %d, want 2", removed)
+	}
+
+	var got []string
+	for _, pkg := range vulnResults.Results[0].Packages {
+		for _, g := range pkg.Groups {
+			got = append(got, pkg.Package.Name+"/"+g.IDs[0])
+		}
+		if len(pkg.Vulnerabilities) != len(pkg.Groups) {
+			t.Errorf("%s has %d vulnerabilities, want %d", pkg.Package.Name, len(pkg.Vulnerabilities), len(pkg.Groups))
+		}
+	}
+	want := []string{"express/GHSA-1", "express/GHSA-3", "evil/MAL-1"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("filterUnreachable() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_filterBaseline(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "project")
+	source := models.SourceInfo{Path: filepath.Join(root, "package-lock.json"), Type: models.SourceTypeProjectPackage}
+	otherSource := models.SourceInfo{Path: filepath.Join(root, "other", "package-lock.json"), Type: models.SourceTypeProjectPackage}
+	pkgVulns := func(name, version string, ids ...[]string) models.PackageVulns {
+		pv := models.PackageVulns{Package: models.PackageInfo{Name: name, Version: version, Ecosystem: "npm"}}
+		for _, aliases := range ids {
+			pv.Vulnerabilities = append(pv.Vulnerabilities, &osvschema.Vulnerability{Id: aliases[0]})
+			pv.Groups = append(pv.Groups, models.GroupInfo{IDs: aliases[:1], Aliases: aliases})
+		}
+
+		return pv
+	}
+
+	baseline := &models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				// scanned from another checkout of the project
+				Source: models.SourceInfo{
+					Path: filepath.Join(t.TempDir(), "checkout", "package-lock.json"),
+					Type: models.SourceTypeProjectPackage,
+				},
+				Packages: []models.PackageVulns{
+					pkgVulns("express", "4.17.1", []string{"GHSA-1"}, []string{"CVE-2"}),
+					pkgVulns("lodash", "4.17.20", []string{"GHSA-3"}),
+					pkgVulns("jest", "29.0.0"),
+				},
+			},
+		},
+	}
+
+	vulnResults := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: source,
+				Packages: []models.PackageVulns{
+					// a new vulnerability, and one now known by an alias
+					pkgVulns("express", "4.17.1", []string{"GHSA-1"}, []string{"GHSA-2", "CVE-2"}, []string{"GHSA-4"}),
+					// no new vulnerabilities
+					pkgVulns("lodash", "4.17.20", []string{"GHSA-3"}),
+					// upgraded, so all of its vulnerabilities are new
+					pkgVulns("jest", "29.1.0", []string{"GHSA-5"}),
+				},
+			},
+			{
+				// a new source
+				Source:   otherSource,
+				Packages: []models.PackageVulns{pkgVulns("lodash", "4.17.20", []string{"GHSA-3"})},
+			},
+		},
+	}
+
+	if removed := filterBaseline(&vulnResults, baseline, []string{root}); removed != 3 {
+		t.Errorf("filterBaseline() = %d, want 3", removed)
+	}
+
+	var got []string
+	for _, pkgSrc := range vulnResults.Results {
+		for _, pkg := range pkgSrc.Packages {
+			for i, g := range pkg.Groups {
+				got = append(got, pkgSrc.Source.Path+":"+pkg.Package.Name+"/"+g.IDs[0]+"/"+pkg.Vulnerabilities[i].GetId())
+			}
+		}
+	}
+	want := []string{
+		source.Path + ":express/GHSA-4/GHSA-4",
+		source.Path + ":jest/GHSA-5/GHSA-5",
+		otherSource.Path + ":lodash/GHSA-3/GHSA-3",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("filterBaseline() mismatch (-want +got):\n%s", diff)
+	}
+}
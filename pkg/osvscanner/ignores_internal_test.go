@@ -0,0 +1,38 @@
+package osvscanner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/osv-scanner/v2/internal/config"
+)
+
+func Test_checkIgnoreReasons(t *testing.T) {
+	t.Parallel()
+
+	manager := config.Manager{
+		ConfigMap: map[string]config.Config{
+			"a": {
+				LoadPath: "a/osv-scanner.toml",
+				IgnoredVulns: []*config.IgnoreEntry{
+					{ID: "GHSA-1", Reason: "not used"},
+					{ID: "GHSA-2", Reason: " "},
+				},
+			},
+		},
+	}
+
+	err := checkIgnoreReasons(&manager)
+	if !errors.Is(err, ErrIgnoreWithoutReason) {
+		t.Fatalf("checkIgnoreReasons() error = %v, want %v", err, ErrIgnoreWithoutReason)
+	}
+	want := "ignored vulnerabilities must give a reason, but some in a/osv-scanner.toml (GHSA-2) do not"
+	if err.Error() != want {
+		t.Errorf("checkIgnoreReasons() error = %q, want %q", err, want)
+	}
+
+	manager.ConfigMap["a"].IgnoredVulns[1].Reason = "only in tests"
+	if err := checkIgnoreReasons(&manager); err != nil {
+		t.Errorf("checkIgnoreReasons() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,149 @@
+package osvscanner
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	scalibr "github.com/google/osv-scalibr"
+	scalibrimage "github.com/google/osv-scalibr/artifact/image"
+	"github.com/google/osv-scalibr/artifact/image/layerscanning/image"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/compose"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/dockerfile"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/helm"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/imageref"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/kubernetes"
+	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/imagehelpers"
+)
+
+// scanReferencedImages replaces the references to container images found in
+// files such as Dockerfiles and Kubernetes manifests with the packages in the
+// images, by pulling each image from its registry and scanning it, so that the
+// vulnerabilities of an image are found before anything is built on it or it
+// is deployed.
+//
+// The packages of each image are located at the line that refers to it, e.g.
+// "/src/Dockerfile:3" for the FROM instruction of a base image.
+func scanReferencedImages(ctx context.Context, accessors ExternalAccessors, actions ScannerActions, packages []*extractor.Package) []*extractor.Package {
+	result := make([]*extractor.Package, 0, len(packages))
+
+	// the packages of each image, by its reference and platform, as the same
+	// image is often referred to more than once
+	scanned := make(map[string][]*extractor.Package)
+
+	for _, pkg := range packages {
+		metadata, ok := pkg.Metadata.(*imageref.Metadata)
+		if !ok {
+			result = append(result, pkg)
+			continue
+		}
+
+		location := pkg.Locations[0]
+		if metadata.Line > 0 {
+			location = fmt.Sprintf("%s:%d", location, metadata.Line)
+		}
+		referrer := location
+		if metadata.Workload != "" {
+			referrer = fmt.Sprintf("%s (%s)", metadata.Workload, location)
+		}
+
+		key := pkg.Name + " " + metadata.Platform
+		imgPackages, ok := scanned[key]
+		if !ok {
+			var err error
+			imgPackages, err = scanReferencedImage(ctx, accessors, actions, pkg.Name, metadata.Platform)
+			if err != nil {
+				cmdlogger.Errorf("Failed to scan the image %q of %s: %v", pkg.Name, referrer, err)
+			}
+			scanned[key] = imgPackages
+		}
+
+		if len(imgPackages) > 0 {
+			cmdlogger.Infof("Found %d package/s in the image %q of %s", len(imgPackages), pkg.Name, referrer)
+		}
+
+		for _, imgPkg := range imgPackages {
+			p := *imgPkg
+			p.Locations = []string{location}
+			result = append(result, &p)
+		}
+	}
+
+	return result
+}
+
+// scanReferencedImage pulls the image from its registry and returns the
+// packages in it that are relevant to containers run from it.
+func scanReferencedImage(ctx context.Context, accessors ExternalAccessors, actions ScannerActions, ref string, platform string) ([]*extractor.Package, error) {
+	opts := imagehelpers.RemoteOptions(ctx, actions.RequestUserAgent, actions.HTTPClient)
+	if platform != "" {
+		p, err := v1.ParsePlatform(platform)
+		if err != nil {
+			return nil, fmt.Errorf("invalid platform %q: %w", platform, err)
+		}
+		opts = append(opts, remote.WithPlatform(*p))
+	}
+
+	v1Image, err := scalibrimage.V1ImageFromRemoteName(ref, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	imgDigest, err := v1Image.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the digest of the image: %w", err)
+	}
+
+	cmdlogger.Infof("Scanning image %q (%s) from its registry", ref, imgDigest)
+
+	img, err := image.FromV1Image(v1Image, image.DefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := img.CleanUp(); err != nil {
+			cmdlogger.Errorf("Failed to clean up image: %s", err)
+		}
+	}()
+
+	// files within the image are not what is being built or deployed
+	actions.PluginsDisabled = append(actions.PluginsDisabled, dockerfile.Name, kubernetes.Name, compose.Name, helm.Name)
+
+	capabilities := &plugin.Capabilities{
+		DirectFS:      true,
+		RunningSystem: false,
+		Network:       plugin.NetworkOnline,
+		OS:            plugin.OSLinux,
+	}
+	plugins := plugin.FilterByCapabilities(getPlugins([]string{"artifact"}, accessors, actions), capabilities)
+
+	sr, err := scalibr.New().ScanContainer(ctx, img, &scalibr.ScanConfig{
+		Plugins:           plugins,
+		Capabilities:      capabilities,
+		StoreAbsolutePath: true,
+		ExplicitPlugins:   true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan container image: %w", err)
+	}
+
+	packages := make([]*extractor.Package, 0, len(sr.Inventory.Packages))
+	for _, pkg := range sr.Inventory.Packages {
+		if !isContainerRelevant(imodels.FromInventory(pkg)) {
+			continue
+		}
+
+		// the layers are those of the image, not of anything in the scanned
+		// directories
+		pkg.LayerMetadata = nil
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
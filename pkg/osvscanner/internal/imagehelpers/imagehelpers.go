@@ -6,9 +6,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
 	"github.com/google/osv-scanner/v2/internal/imodels/results"
 	"github.com/google/osv-scanner/v2/pkg/models"
@@ -51,6 +54,32 @@ func BuildImageMetadata(scanResults *results.ScanResults) *models.ImageMetadata
 	return &imgMetadata
 }
 
+// BuildImageOrigin returns the details of the layer at the given index of the
+// image, so that a package can be attributed to the instruction that introduced
+// it and to its base image, if it came from one.
+func BuildImageOrigin(imgMetadata *models.ImageMetadata, layerIndex int) *models.ImageOriginDetails {
+	origin := &models.ImageOriginDetails{Index: layerIndex}
+
+	if imgMetadata == nil || layerIndex < 0 || layerIndex >= len(imgMetadata.LayerMetadata) {
+		return origin
+	}
+
+	layer := imgMetadata.LayerMetadata[layerIndex]
+	origin.DiffID = layer.DiffID
+	origin.Command = layer.Command
+
+	// the image itself is the 0th "base image"
+	if layer.BaseImageIndex > 0 {
+		origin.InBaseImage = true
+
+		if layer.BaseImageIndex < len(imgMetadata.BaseImages) && len(imgMetadata.BaseImages[layer.BaseImageIndex]) > 0 {
+			origin.BaseImage = imgMetadata.BaseImages[layer.BaseImageIndex][0].Name
+		}
+	}
+
+	return origin
+}
+
 // ExportDockerImage will execute the docker binary to export an image to a temporary file in the tarball OCI format.
 //
 // If ExportDockerImage does not error, the temporary file needs to be cleaned up by the caller, otherwise, it will be
@@ -97,6 +126,34 @@ func ExportDockerImage(ctx context.Context, dockerImageName string) (string, err
 	return tempImageFile.Name(), nil
 }
 
+// DockerAvailable reports whether the docker binary that ExportDockerImage
+// relies on can be found.
+func DockerAvailable() bool {
+	_, err := exec.LookPath("docker")
+
+	return err == nil
+}
+
+// RemoteOptions returns the options for pulling an image straight from its
+// registry, authenticating with the same credentials that docker would use,
+// including those of the credential helpers set in the docker config file.
+func RemoteOptions(ctx context.Context, userAgent string, client *http.Client) []remote.Option {
+	opts := []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+	}
+
+	if userAgent != "" {
+		opts = append(opts, remote.WithUserAgent(userAgent))
+	}
+
+	if client != nil && client.Transport != nil {
+		opts = append(opts, remote.WithTransport(client.Transport))
+	}
+
+	return opts
+}
+
 func runCommandLogError(ctx context.Context, name string, args ...string) error {
 	cmd := exec.CommandContext(ctx, name, args...)
 
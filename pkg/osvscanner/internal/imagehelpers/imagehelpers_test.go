@@ -0,0 +1,130 @@
+package imagehelpers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/imagehelpers"
+)
+
+func TestBuildImageOrigin(t *testing.T) {
+	t.Parallel()
+
+	imgMetadata := &models.ImageMetadata{
+		LayerMetadata: []models.LayerMetadata{
+			{DiffID: "sha256:aaa", Command: "ADD file:abc in /", BaseImageIndex: 1},
+			{DiffID: "sha256:bbb", Command: "RUN apk add curl", BaseImageIndex: 0},
+		},
+		BaseImages: [][]models.BaseImageDetails{
+			{},
+			{{Name: "alpine"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		layerIndex int
+		want       *models.ImageOriginDetails
+	}{
+		{
+			name:       "layer_from_base_image",
+			layerIndex: 0,
+			want: &models.ImageOriginDetails{
+				Index:       0,
+				DiffID:      "sha256:aaa",
+				Command:     "ADD file:abc in /",
+				InBaseImage: true,
+				BaseImage:   "alpine",
+			},
+		},
+		{
+			name:       "layer_from_the_image_itself",
+			layerIndex: 1,
+			want: &models.ImageOriginDetails{
+				Index:   1,
+				DiffID:  "sha256:bbb",
+				Command: "RUN apk add curl",
+			},
+		},
+		{
+			name:       "unknown_layer",
+			layerIndex: 5,
+			want:       &models.ImageOriginDetails{Index: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := imagehelpers.BuildImageOrigin(imgMetadata, tt.layerIndex)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("BuildImageOrigin() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRemoteOptions(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var userAgents []string
+	reg := registry.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		userAgents = append(userAgents, r.UserAgent())
+		mu.Unlock()
+
+		reg.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	ref, err := name.NewTag(strings.TrimPrefix(srv.URL, "http://") + "/org/app:latest")
+	if err != nil {
+		t.Fatalf("name.NewTag() error = %v", err)
+	}
+
+	want, err := random.Image(64, 2)
+	if err != nil {
+		t.Fatalf("random.Image() error = %v", err)
+	}
+	if err = remote.Write(ref, want); err != nil {
+		t.Fatalf("remote.Write() error = %v", err)
+	}
+
+	mu.Lock()
+	userAgents = nil
+	mu.Unlock()
+
+	got, err := remote.Image(ref, imagehelpers.RemoteOptions(t.Context(), "osv-scanner_test/1.0.0", srv.Client())...)
+	if err != nil {
+		t.Fatalf("remote.Image() error = %v", err)
+	}
+
+	wantDigest, _ := want.Digest()
+	gotDigest, _ := got.Digest()
+	if gotDigest != wantDigest {
+		t.Errorf("pulled image digest = %s, want %s", gotDigest, wantDigest)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(userAgents) == 0 {
+		t.Fatalf("no requests were made to the registry")
+	}
+	for _, ua := range userAgents {
+		if !strings.HasPrefix(ua, "osv-scanner_test/1.0.0") {
+			t.Errorf("request made with user agent %q, want it to start with %q", ua, "osv-scanner_test/1.0.0")
+		}
+	}
+}
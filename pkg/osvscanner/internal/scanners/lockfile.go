@@ -38,8 +38,10 @@ import (
 	"github.com/google/osv-scalibr/extractor/filesystem/os/apk"
 	"github.com/google/osv-scalibr/extractor/filesystem/os/dpkg"
 	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/iac/terraformlock"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/pomxmlenhanceable"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/osv/osvscannerjson"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/sbom/purllist"
 )
 
 // OSV-Scanner and OSV-Scalibr has different plugin/override naming conventions.
@@ -74,6 +76,7 @@ var osvscannerScalibrExtractionMapping = map[string][]string{
 	"gems.locked":                 {gemfilelock.Name},
 	"cabal.project.freeze":        {cabal.Name},
 	"stack.yaml.lock":             {stacklock.Name},
+	".terraform.lock.hcl":         {terraformlock.Name},
 	// "Package.resolved":            {packageresolved.Name},
 }
 
@@ -99,6 +102,8 @@ func ParseAsToPlugin(parseAs string, pluginsToUse []plugin.Plugin) (filesystem.E
 		return nil, errors.New("no parseAs specified")
 	case "osv-scanner":
 		return osvscannerjson.Extractor{}, nil
+	case "purls":
+		return purllist.Extractor{}, nil
 	default:
 		// Find and extract with the extractor of parseAs
 		if names, ok := osvscannerScalibrExtractionMapping[parseAs]; ok && len(names) > 0 {
@@ -0,0 +1,64 @@
+package osvscanner
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/kev"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// applyKEV marks the vulnerabilities listed in the CISA KEV catalog, when
+// this was requested.
+func applyKEV(vulnResults *models.VulnerabilityResults, actions ScannerActions) {
+	if !actions.KEV && !actions.FailOnKEV {
+		return
+	}
+	if actions.CompareOffline {
+		return
+	}
+
+	catalog := actions.kevCatalog
+	if catalog == nil {
+		catalog = newKEVCatalog(actions)
+	}
+	enrichKEV(vulnResults, catalog())
+}
+
+// newKEVCatalog returns a function that fetches the CISA KEV catalog the first
+// time it is called, and returns the same catalog every time after, which is
+// nil if it could not be fetched.
+func newKEVCatalog(actions ScannerActions) func() map[string]models.KEVEntry {
+	return sync.OnceValue(func() map[string]models.KEVEntry {
+		userAgent := "osv-scanner-api"
+		if actions.RequestUserAgent != "" {
+			userAgent = actions.RequestUserAgent
+		}
+		catalog, err := kev.NewClient(actions.KEVCatalogURL, actions.HTTPClient, userAgent).Catalog(context.Background())
+		if err != nil {
+			cmdlogger.Warnf("Failed to fetch the CISA KEV catalog: %v", err)
+			return nil
+		}
+
+		return catalog
+	})
+}
+
+// enrichKEV attaches to each group of vulnerabilities the catalog entry of
+// the first of its aliases that is in the catalog.
+func enrichKEV(vulnResults *models.VulnerabilityResults, catalog map[string]models.KEVEntry) {
+	for i := range vulnResults.Results {
+		for j := range vulnResults.Results[i].Packages {
+			groups := vulnResults.Results[i].Packages[j].Groups
+			for k := range groups {
+				for _, alias := range groups[k].Aliases {
+					if entry, ok := catalog[alias]; ok {
+						groups[k].KEV = &entry
+						break
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+func kevTestResults() models.VulnerabilityResults {
+	return models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source: models.SourceInfo{Path: "/path/to/pom.xml", Type: models.SourceTypeProjectPackage},
+			Packages: []models.PackageVulns{{
+				Package: models.PackageInfo{Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", Ecosystem: "Maven"},
+				Vulnerabilities: []*osvschema.Vulnerability{
+					{Id: "GHSA-jfh8-c2jp-5v3q"},
+					{Id: "GHSA-8489-44mv-ggj8"},
+				},
+				Groups: []models.GroupInfo{
+					{IDs: []string{"GHSA-jfh8-c2jp-5v3q"}, Aliases: []string{"CVE-2021-44228", "GHSA-jfh8-c2jp-5v3q"}},
+					{IDs: []string{"GHSA-8489-44mv-ggj8"}, Aliases: []string{"CVE-2021-44832", "GHSA-8489-44mv-ggj8"}},
+				},
+			}},
+		}},
+	}
+}
+
+func Test_enrichKEV(t *testing.T) {
+	t.Parallel()
+
+	results := kevTestResults()
+	enrichKEV(&results, map[string]models.KEVEntry{
+		"CVE-2021-44228": {CVE: "CVE-2021-44228", DateAdded: "2021-12-10"},
+	})
+
+	groups := results.Results[0].Packages[0].Groups
+	if groups[0].KEV == nil || groups[0].KEV.CVE != "CVE-2021-44228" {
+		t.Errorf("enrichKEV() KEV of %s = %v, want the CVE-2021-44228 entry", groups[0].IDs[0], groups[0].KEV)
+	}
+	if groups[1].KEV != nil {
+		t.Errorf("enrichKEV() KEV of %s = %v, want nil", groups[1].IDs[0], groups[1].KEV)
+	}
+}
@@ -0,0 +1,120 @@
+package osvscanner
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/google/osv-scalibr/clients/datasource"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/licensematcher"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/config"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/google/osv-scanner/v2/internal/imodels/results"
+	"github.com/google/osv-scanner/v2/internal/spdx"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+// matchLicenses looks up the licenses of the scanned packages, if they are
+// needed for the license summary or a license policy.
+//
+// As license policies can be set by the config files found alongside the
+// scanned manifests, the license matcher is only created once these are loaded
+// if the flags did not already call for one. In offline mode, or when the
+// licenses are not looked up, packages keep the licenses found while scanning.
+func matchLicenses(accessors *ExternalAccessors, actions ScannerActions, scanResult *results.ScanResults) error {
+	hasPolicy := len(actions.ScanLicensesAllowlist) > 0 || len(actions.ScanLicensesDenylist) > 0 ||
+		scanResult.ConfigManager.HasLicensePolicy()
+
+	if accessors.LicenseMatcher == nil && hasPolicy {
+		if actions.CompareOffline {
+			cmdlogger.Warnf("Licenses cannot be looked up in offline mode, so the license policy is only checked against licenses found while scanning")
+		} else {
+			userAgent := "osv-scanner-api"
+			if actions.RequestUserAgent != "" {
+				userAgent = actions.RequestUserAgent
+			}
+			client, err := datasource.NewCachedInsightsClient(depsdev.DepsdevAPI, userAgent)
+			if err != nil {
+				return err
+			}
+			accessors.LicenseMatcher = &licensematcher.DepsDevLicenseMatcher{Client: client}
+		}
+	}
+
+	if accessors.LicenseMatcher == nil {
+		for i, psr := range scanResult.PackageScanResults {
+			scanResult.PackageScanResults[i].Licenses = psr.PackageInfo.ExtractedLicenses()
+		}
+
+		return nil
+	}
+
+	return accessors.LicenseMatcher.MatchLicenses(context.Background(), scanResult.PackageScanResults)
+}
+
+// licensePolicy returns the license policy for a package, which is the
+// allowlist given by the flags if any, or otherwise that of its config, and
+// the licenses denied by either.
+func licensePolicy(actions ScannerActions, configToUse config.Config) config.LicensePolicy {
+	policy := config.LicensePolicy{
+		Allowlist: actions.ScanLicensesAllowlist,
+		Denylist:  slices.Concat(actions.ScanLicensesDenylist, configToUse.LicensePolicy.Denylist),
+	}
+	if len(policy.Allowlist) == 0 {
+		policy.Allowlist = configToUse.LicensePolicy.Allowlist
+	}
+
+	return policy
+}
+
+// reportedLicensePolicy combines the license policies of every config that was
+// loaded, to be reported alongside the results.
+func reportedLicensePolicy(actions ScannerActions, configManager *config.Manager) config.LicensePolicy {
+	configs := slices.Collect(maps.Values(configManager.ConfigMap))
+	if configManager.OverrideConfig != nil {
+		configs = []config.Config{*configManager.OverrideConfig}
+	}
+
+	var allowlist []string
+	denylist := slices.Clone(actions.ScanLicensesDenylist)
+	for _, c := range configs {
+		allowlist = append(allowlist, c.LicensePolicy.Allowlist...)
+		denylist = append(denylist, c.LicensePolicy.Denylist...)
+	}
+	slices.Sort(allowlist)
+	slices.Sort(denylist)
+
+	policy := config.LicensePolicy{
+		Allowlist: actions.ScanLicensesAllowlist,
+		Denylist:  slices.Compact(denylist),
+	}
+	if len(policy.Allowlist) == 0 {
+		policy.Allowlist = slices.Compact(allowlist)
+	}
+
+	return policy
+}
+
+// violatesLicensePolicy checks if a license expression cannot be satisfied by
+// the allowlist of the policy, or only by licenses on its denylist. Invalid
+// expressions are always violations.
+func violatesLicensePolicy(license models.License, policy config.LicensePolicy) (bool, error) {
+	if len(policy.Allowlist) > 0 {
+		satisfies, err := spdx.Satisfies(license, policy.Allowlist)
+		if err != nil || !satisfies {
+			return true, err
+		}
+	}
+
+	if len(policy.Denylist) > 0 {
+		denied, err := spdx.Denied(license, policy.Denylist)
+		if err != nil {
+			return true, err
+		}
+
+		return denied, nil
+	}
+
+	return false, nil
+}
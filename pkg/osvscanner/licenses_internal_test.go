@@ -0,0 +1,134 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/config"
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func Test_licensePolicy(t *testing.T) {
+	t.Parallel()
+
+	configToUse := config.Config{
+		LicensePolicy: config.LicensePolicy{
+			Allowlist: []string{"MIT", "Apache-2.0"},
+			Denylist:  []string{"AGPL-3.0-only"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		actions ScannerActions
+		config  config.Config
+		want    config.LicensePolicy
+	}{
+		{
+			name:    "no_policy",
+			actions: ScannerActions{},
+			config:  config.Config{},
+			want:    config.LicensePolicy{},
+		},
+		{
+			name:    "config_only",
+			actions: ScannerActions{},
+			config:  configToUse,
+			want:    configToUse.LicensePolicy,
+		},
+		{
+			name: "flags_replace_the_allowlist_and_add_to_the_denylist",
+			actions: ScannerActions{
+				ScanLicensesAllowlist: []string{"BSD-3-Clause"},
+				ScanLicensesDenylist:  []string{"GPL-3.0-only"},
+			},
+			config: configToUse,
+			want: config.LicensePolicy{
+				Allowlist: []string{"BSD-3-Clause"},
+				Denylist:  []string{"GPL-3.0-only", "AGPL-3.0-only"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := licensePolicy(tt.actions, tt.config)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("licensePolicy() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_violatesLicensePolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		license models.License
+		policy  config.LicensePolicy
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:    "allowed",
+			license: "MIT",
+			policy:  config.LicensePolicy{Allowlist: []string{"MIT"}},
+			want:    false,
+		},
+		{
+			name:    "not_allowed",
+			license: "BSD-3-Clause",
+			policy:  config.LicensePolicy{Allowlist: []string{"MIT"}},
+			want:    true,
+		},
+		{
+			name:    "denied",
+			license: "GPL-3.0-only",
+			policy:  config.LicensePolicy{Denylist: []string{"GPL-3.0-only"}},
+			want:    true,
+		},
+		{
+			name:    "denied_alternative",
+			license: "MIT OR GPL-3.0-only",
+			policy:  config.LicensePolicy{Denylist: []string{"GPL-3.0-only"}},
+			want:    false,
+		},
+		{
+			name:    "allowed_but_denied",
+			license: "MIT AND GPL-3.0-only",
+			policy: config.LicensePolicy{
+				Allowlist: []string{"MIT", "GPL-3.0-only"},
+				Denylist:  []string{"GPL-3.0-only"},
+			},
+			want: true,
+		},
+		{
+			name:    "unknown_is_not_denied",
+			license: "UNKNOWN",
+			policy:  config.LicensePolicy{Denylist: []string{"GPL-3.0-only"}},
+			want:    false,
+		},
+		{
+			name:    "invalid",
+			license: "(MIT",
+			policy:  config.LicensePolicy{Denylist: []string{"GPL-3.0-only"}},
+			want:    true,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := violatesLicensePolicy(tt.license, tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("violatesLicensePolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("violatesLicensePolicy(%q) = %v, want %v", tt.license, got, tt.want)
+			}
+		})
+	}
+}
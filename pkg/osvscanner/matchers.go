@@ -0,0 +1,50 @@
+package osvscanner
+
+import (
+	"context"
+
+	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+)
+
+// matchPackageDetails attaches to the packages the details that were
+// requested besides their vulnerabilities and licenses, such as their
+// OpenSSF Scorecards, releases and provenance.
+func matchPackageDetails(ctx context.Context, packages []imodels.PackageScanResult, accessors ExternalAccessors) {
+	matchPackages(ctx, packages, accessors.ScorecardMatcher, clientinterfaces.ScorecardMatcher.MatchScorecards,
+		"look up OpenSSF Scorecards")
+	matchPackages(ctx, packages, accessors.ProjectStatusMatcher, clientinterfaces.ProjectStatusMatcher.MatchProjectStatuses,
+		"look up the status of upstream projects")
+	matchPackages(ctx, packages, accessors.DependentsMatcher, clientinterfaces.DependentsMatcher.MatchDependents,
+		"look up dependent counts")
+	matchPackages(ctx, packages, accessors.ReleaseMatcher, clientinterfaces.ReleaseMatcher.MatchReleases,
+		"look up package releases")
+	matchPackages(ctx, packages, accessors.TyposquatMatcher, clientinterfaces.TyposquatMatcher.MatchTyposquats,
+		"look up possible typosquats")
+	matchPackages(ctx, packages, accessors.ProvenanceMatcher, clientinterfaces.ProvenanceMatcher.MatchProvenances,
+		"look up package provenance")
+	matchPackages(ctx, packages, accessors.HashMatcher, clientinterfaces.HashMatcher.MatchHashes,
+		"verify requirement hashes")
+}
+
+// matchPackages runs match with matcher over the packages, unless no matcher
+// was created as the details were not requested. The scan carries on if the
+// details of some of the packages cannot be looked up, as the matchers keep
+// those of the others, so no package fails a policy for the details missing.
+func matchPackages[M comparable](
+	ctx context.Context,
+	packages []imodels.PackageScanResult,
+	matcher M,
+	match func(M, context.Context, []imodels.PackageScanResult) error,
+	what string,
+) {
+	var none M
+	if matcher == none {
+		return
+	}
+
+	if err := match(matcher, ctx, packages); err != nil {
+		cmdlogger.Warnf("Failed to %s: %v", what, err)
+	}
+}
@@ -0,0 +1,106 @@
+package osvscanner
+
+import (
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/osv-scanner/v2/internal/config"
+)
+
+// applyOSVEndpoint uses the OSV API endpoint configured in the config file,
+// unless one was already set.
+func applyOSVEndpoint(actions *ExperimentalScannerActions, endpoint config.OSVEndpoint) {
+	if actions.OSVBaseURL == "" {
+		actions.OSVBaseURL = endpoint.BaseURL
+	}
+
+	headers := endpoint.ExpandedHeaders()
+	if len(headers) == 0 {
+		return
+	}
+	// Headers that were already set take precedence over the config file
+	maps.Copy(headers, actions.OSVHeaders)
+	actions.OSVHeaders = headers
+}
+
+// osvAPIHTTPClient returns the client to make OSV API requests with, which
+// sends the given headers with every request, and makes no more than
+// requestsPerSecond requests each second if it is positive.
+func osvAPIHTTPClient(client *http.Client, headers map[string]string, requestsPerSecond float64) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if len(headers) == 0 && requestsPerSecond <= 0 {
+		return client
+	}
+
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if len(headers) > 0 {
+		transport = headerTransport{base: transport, headers: headers}
+	}
+	if requestsPerSecond > 0 {
+		transport = &rateLimitTransport{
+			base:     transport,
+			interval: time.Duration(float64(time.Second) / requestsPerSecond),
+		}
+	}
+	wrapped := *client
+	wrapped.Transport = transport
+
+	return &wrapped
+}
+
+// headerTransport sets headers on every request before sending it.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// rateLimitTransport spaces requests out so that at most one is sent every
+// interval, however many goroutines are sending them. Retries count as
+// requests too, so they cannot be used to get around the limit.
+type rateLimitTransport struct {
+	base     http.RoundTripper
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	now := time.Now()
+	at := t.next
+	if at.Before(now) {
+		at = now
+	}
+	t.next = at.Add(t.interval)
+	t.mu.Unlock()
+
+	if delay := time.Until(at); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,101 @@
+package osvscanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/config"
+)
+
+func Test_applyOSVEndpoint(t *testing.T) {
+	t.Setenv("OSV_MIRROR_KEY", "s3cr3t")
+
+	endpoint := config.OSVEndpoint{
+		BaseURL: "https://osv-mirror.example.com/api",
+		Headers: map[string]string{
+			"X-Api-Key": "${OSV_MIRROR_KEY}",
+			"X-Team":    "security",
+		},
+	}
+
+	actions := ExperimentalScannerActions{}
+	applyOSVEndpoint(&actions, endpoint)
+	if actions.OSVBaseURL != endpoint.BaseURL {
+		t.Errorf("OSVBaseURL = %q, want %q", actions.OSVBaseURL, endpoint.BaseURL)
+	}
+	want := map[string]string{"X-Api-Key": "s3cr3t", "X-Team": "security"}
+	if diff := cmp.Diff(want, actions.OSVHeaders); diff != "" {
+		t.Errorf("OSVHeaders mismatch (-want +got):\n%s", diff)
+	}
+
+	// What was already set takes precedence over the config file
+	actions = ExperimentalScannerActions{
+		OSVBaseURL: "https://osv.internal",
+		OSVHeaders: map[string]string{"X-Team": "platform"},
+	}
+	applyOSVEndpoint(&actions, endpoint)
+	if actions.OSVBaseURL != "https://osv.internal" {
+		t.Errorf("OSVBaseURL = %q, want %q", actions.OSVBaseURL, "https://osv.internal")
+	}
+	want = map[string]string{"X-Api-Key": "s3cr3t", "X-Team": "platform"}
+	if diff := cmp.Diff(want, actions.OSVHeaders); diff != "" {
+		t.Errorf("OSVHeaders with headers already set mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_osvAPIHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	var gotAPIKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		gotAPIKey = r.Header.Get("X-Api-Key")
+	}))
+	t.Cleanup(srv.Close)
+
+	if client := osvAPIHTTPClient(srv.Client(), nil, 0); client != srv.Client() {
+		t.Errorf("osvAPIHTTPClient() without headers should return the client as is")
+	}
+
+	client := osvAPIHTTPClient(srv.Client(), map[string]string{"X-Api-Key": "s3cr3t"}, 0)
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotAPIKey != "s3cr3t" {
+		t.Errorf("X-Api-Key = %q, want %q", gotAPIKey, "s3cr3t")
+	}
+}
+
+func Test_osvAPIHTTPClient_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	t.Cleanup(srv.Close)
+
+	// 4 requests at 20 a second can't all be sent within 150ms
+	client := osvAPIHTTPClient(srv.Client(), nil, 20)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			resp, err := client.Get(srv.URL)
+			if err != nil {
+				t.Errorf("Get() error: %v", err)
+				return
+			}
+			resp.Body.Close()
+		})
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("4 requests took %v, want at least 150ms at 20 requests per second", elapsed)
+	}
+}
@@ -1,6 +1,7 @@
 package osvscanner
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -10,8 +11,11 @@ import (
 	"os"
 	"slices"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	depsdevalphapb "deps.dev/api/v3alpha"
 	scalibr "github.com/google/osv-scalibr"
 	"github.com/google/osv-scalibr/artifact/image/layerscanning/image"
 	"github.com/google/osv-scalibr/binary/proto"
@@ -25,16 +29,25 @@ import (
 	"github.com/google/osv-scalibr/plugin"
 	"github.com/google/osv-scalibr/stats"
 	"github.com/google/osv-scanner/v2/internal/apiconfig"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/dependentsmatcher"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/hashmatcher"
 	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/licensematcher"
 	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/localmatcher"
 	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/osvmatcher"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/projectstatusmatcher"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/provenancematcher"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/releasematcher"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/scorecardmatcher"
+	"github.com/google/osv-scanner/v2/internal/clients/clientimpl/typosquatmatcher"
 	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
 	"github.com/google/osv-scanner/v2/internal/config"
+	internaldatasource "github.com/google/osv-scanner/v2/internal/datasource"
 	"github.com/google/osv-scanner/v2/internal/depsdev"
 	"github.com/google/osv-scanner/v2/internal/imodels"
 	"github.com/google/osv-scanner/v2/internal/imodels/results"
 	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/vexdoc"
 	"github.com/google/osv-scanner/v2/pkg/models"
 	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/imagehelpers"
 	"github.com/ossf/osv-schema/bindings/go/osvconstants"
@@ -44,27 +57,66 @@ import (
 type ScannerActions struct {
 	ExperimentalScannerActions
 
-	LockfilePaths      []string
-	DirectoryPaths     []string
-	GitCommits         []string
-	Recursive          bool
-	IncludeGitRoot     bool
-	NoIgnore           bool
-	Image              string
-	IsImageArchive     bool
+	LockfilePaths []string
+	// The content of a lockfile or manifest, such as one piped to stdin,
+	// which is scanned without being written to disk
+	StdinLockfile []byte
+	// The type of lockfile to parse StdinLockfile as (e.g. "requirements.txt"),
+	// named as in the "<type>:<path>" form of LockfilePaths
+	StdinLockfileType string
+	// Package URLs (e.g. "pkg:npm/lodash@4.17.20") of packages to scan as they
+	// are, for when the inventory is already known
+	PURLs          []string
+	DirectoryPaths []string
+	GitCommits     []string
+	// Scan DirectoryPaths, which must be git repositories, possibly bare, at
+	// this commit, tag or branch rather than what is checked out
+	GitRef         string
+	Recursive      bool
+	IncludeGitRoot bool
+	NoIgnore       bool
+	Image          string
+	IsImageArchive bool
+	// Pull Image straight from its registry rather than through the local
+	// docker daemon
+	IsImageRemote      bool
 	ConfigOverridePath string
-	CallAnalysisStates map[string]bool
-	ShowAllPackages    bool
-	ShowAllVulns       bool
+	// Fail the scan if any ignore entry in the config files has expired
+	FailOnExpiredIgnores bool
+	// Fail the scan with ErrIgnoreWithoutReason if any ignore entry in the
+	// config files does not give a reason
+	RequireIgnoreReasons bool
+	CallAnalysisStates   map[string]bool
+	// Leave out the vulnerabilities that call analysis found are not called
+	RequireReachable bool
+	ShowAllPackages  bool
+	ShowAllVulns     bool
+	// OpenVEX or CSAF VEX documents stating which vulnerabilities do not
+	// affect which packages
+	VEXPaths []string
+	// An earlier result, such as of the target branch, whose vulnerabilities
+	// are left out so that only newly introduced ones are reported, and can
+	// fail the scan
+	Baseline *models.VulnerabilityResults
+	// Called by DoScan with the results of each source as soon as they are
+	// final, in the order they are reported, so that they can be written out
+	// while the other sources are still being matched. Setting it matches the
+	// vulnerabilities of one source at a time rather than of every package at
+	// once. An error stops the scan and is returned by DoScan.
+	OnSourceResults func(models.PackageSource) error
 
 	// local databases
 	CompareOffline    bool
 	DownloadDatabases bool
 	LocalDBPath       string
+	// How old local databases can be before warning about them, 0 to never warn
+	LocalDBMaxAge time.Duration
 
 	// license scanning
 	ScanLicensesSummary   bool
 	ScanLicensesAllowlist []string
+	// Licenses that packages must not use, on top of any denied by the config
+	ScanLicensesDenylist []string
 
 	// Deprecated: in favor of LockfilePaths
 	SBOMPaths []string
@@ -87,20 +139,211 @@ type ExperimentalScannerActions struct {
 	// Report deprecated packages as findings
 	FlagDeprecatedPackages bool
 
+	// Look up why packages are deprecated, and whether their source
+	// repositories are archived, from the package registries and deps.dev
+	DeprecationReasons bool
+
 	// Allows specifying user agent
 	RequestUserAgent string
+
+	// OSV API to query for vulnerabilities instead of the default one, such
+	// as an internal mirror
+	OSVBaseURL string
+	// Headers sent with every request to the OSV API, e.g. for authentication
+	OSVHeaders map[string]string
+	// Maximum number of concurrent requests to the OSV API, 0 for the default
+	OSVMaxConcurrentRequests int
+	// Maximum number of requests made to the OSV API each second, 0 for no limit
+	OSVRequestsPerSecond float64
+
+	// Time budget shared by all enrichers, 0 for no limit
+	EnrichmentTimeout time.Duration
+
+	// Directory to cache the packages found in each file, and their
+	// vulnerabilities, in by the file's content, so that files which have not
+	// changed are not extracted and resolved again; "" to not cache
+	ScanCacheDir string
+	// How long cached results are used for before the files are scanned
+	// again, so that newly published vulnerabilities are found; 0 for forever
+	ScanCacheTTL time.Duration
+
+	// Look up the EPSS score of each vulnerability
+	EPSS bool
+	// Hide vulnerabilities with an EPSS score below this probability,
+	// 0 to keep them all; implies EPSS
+	MinEPSS float64
+	// Order packages and vulnerabilities by EPSS score; implies EPSS
+	SortByEPSS bool
+	// EPSS API to query, defaults to the FIRST EPSS API
+	EPSSBaseURL string
+
+	// Mark vulnerabilities listed in the CISA KEV catalog
+	KEV bool
+	// Only fail the scan because of vulnerabilities in the CISA KEV
+	// catalog; implies KEV
+	FailOnKEV bool
+	// KEV catalog feed to fetch, defaults to the one published by CISA
+	KEVCatalogURL string
+
+	// Fill in the severity and description of vulnerabilities that are
+	// missing them in OSV from the GitHub Advisory Database and the NVD
+	SecondaryAdvisories bool
+	// GitHub API to query, defaults to api.github.com
+	GHSABaseURL string
+	// Token for the GitHub API, which allows many more requests
+	GitHubToken string
+	// NVD CVE API to query, defaults to the one run by NIST
+	NVDBaseURL string
+	// Key for the NVD API, which allows many more requests
+	NVDAPIKey string
+
+	// Look up the OpenSSF Scorecard of the source repository of each package
+	Scorecard bool
+	// Report packages whose Scorecard has an overall score below this as
+	// findings, 0 to not report any; implies Scorecard
+	FailOnScorecardBelow float64
+
+	// Look up how many packages depend on each package version
+	Dependents bool
+	// Order packages by how many packages depend on them; implies Dependents
+	SortByDependents bool
+
+	// Look up when each package version was released and the latest release
+	// of each package
+	ReleaseInfo bool
+	// Report packages that are at least OutdatedMajorVersions major versions
+	// or OutdatedMonths months behind their latest release, with 0 disabling
+	// either check; implies ReleaseInfo
+	Outdated              bool
+	OutdatedMajorVersions int
+	OutdatedMonths        int
+
+	// Flag packages whose names are close to those of popular packages
+	Typosquats bool
+
+	// Look up whether each package version was published with verified build
+	// provenance
+	Provenance bool
+	// Report packages without verified provenance as findings; implies
+	// Provenance
+	FailOnMissingProvenance bool
+
+	// Check the hashes that requirements files pin packages to against the
+	// digests of the files published for them on PyPI
+	VerifyHashes bool
+
+	// Compare the base image of a scanned container image to this tag of it
+	// (e.g. "latest"), or to this image reference, reporting how many of the
+	// base image's vulnerabilities upgrading to it would eliminate
+	BaseImageUpgrade string
+
+	// Look up the newest tag of the base image of a scanned container image
+	// with the same major version and variant as the tag it was built on,
+	// reporting how many of the base image's vulnerabilities upgrading to it
+	// would eliminate; BaseImageUpgrade overrides the tag that is compared to
+	RecommendBaseImageUpgrade bool
+
+	// Scan the base images that the Dockerfiles in the scanned directories
+	// are built on, pulling each of them from its registry
+	DockerfileBaseImages bool
+	// Scan the container images that the Kubernetes manifests and Compose
+	// files in the scanned directories run, pulling each of them from its
+	// registry
+	ManifestImages bool
+	// Scan the container images that the Helm charts in the scanned
+	// directories run, rendering each chart with the helm command
+	HelmCharts bool
+	// The values files that Helm charts are rendered with
+	HelmValuesFiles []string
+
+	// Fill in the dependencies that are missing from the scanned SBOMs,
+	// by looking up the dependencies of their components on deps.dev
+	SBOMEnrichment bool
+
+	// Report the dependencies declared in manifests that are missing from,
+	// or inconsistent with, their lockfiles
+	LockfileDrift bool
+
+	// Compute the smallest set of version upgrades that fixes the most of
+	// the reported vulnerabilities
+	UpgradePlan bool
+
+	// The files to leave out of the scan, from the ExcludePaths of the config,
+	// which are set by DoScan
+	pathExclusions []pathExclusion
+
+	// Returns the CISA KEV catalog, which is only fetched the first time,
+	// for when the results of each source are enriched on their own
+	kevCatalog func() map[string]models.KEVEntry
 }
 
 type TransitiveScanningActions struct {
-	Disabled         bool
+	Disabled bool
+	// Only disable resolving the transitive dependencies of requirements
+	// files or of pom.xml files, rather than of both
+	PyPIDisabled     bool
+	MavenDisabled    bool
 	NativeDataSource bool
 	MavenRegistry    string
+	// Maximum number of concurrent deps.dev lookups per manifest, 0 uses the default
+	Parallelism int
+	// Use the deps.dev gRPC API instead of the REST API
+	DepsDevGRPC bool
+	// Path to an offline deps.dev snapshot to resolve from instead of the live API
+	DepsDevSnapshotPath string
+	// Limits of the in-memory deps.dev graph cache, 0 for both uses the defaults
+	CacheMaxEntries int
+	CacheMaxBytes   int64
+	// Additional headers sent with every deps.dev request
+	DepsDevHeaders map[string]string
+	// Receives deps.dev client instrumentation events, if set
+	DepsDevMetrics DepsDevMetrics
+	// Per-ecosystem deps.dev endpoints, keyed by ecosystem (e.g. "PyPI"),
+	// taking precedence over the default endpoint and DepsDevHeaders
+	DepsDevEndpoints map[string]DepsDevEndpoint
+	// Consecutive failed deps.dev lookups after which the rest of a manifest's
+	// lookups are skipped, 0 uses the default and a negative value never skips
+	CircuitBreakerThreshold int
+	// How to choose between the versions of a package that the roots of a
+	// manifest resolve it to; one of "report-all" (the default), "highest-wins"
+	// or "nearest-wins"
+	ConflictStrategy string
+	// Maximum depth of resolved dependencies, with those declared in the
+	// manifest being at depth 1; 0 resolves the whole graph
+	MaxDepth int
+	// Maven scopes (e.g. "test" or "provided") whose dependencies are excluded from the scan
+	MavenExcludedScopes []string
+	// Python version (e.g. "3.11") and sys.platform (e.g. "linux") that
+	// environment markers in requirements files are evaluated against;
+	// markers depending on an unset value are assumed to hold
+	PythonVersion  string
+	PythonPlatform string
+
+	// Collects manifests whose transitive dependencies were only partially resolved
+	degradations *depsdev.Degradations
+}
+
+// DepsDevEndpoint configures the deps.dev endpoint used for a single ecosystem.
+type DepsDevEndpoint struct {
+	BaseURL string
+	Headers map[string]string
 }
 
+// DepsDevMetrics receives instrumentation events (requests, latency and cache
+// usage) from the deps.dev clients used for transitive scanning.
+type DepsDevMetrics = depsdev.Metrics
+
 type ExternalAccessors struct {
 	// Matchers
-	VulnMatcher    clientinterfaces.VulnerabilityMatcher
-	LicenseMatcher clientinterfaces.LicenseMatcher
+	VulnMatcher          clientinterfaces.VulnerabilityMatcher
+	LicenseMatcher       clientinterfaces.LicenseMatcher
+	ScorecardMatcher     clientinterfaces.ScorecardMatcher
+	ProjectStatusMatcher clientinterfaces.ProjectStatusMatcher
+	DependentsMatcher    clientinterfaces.DependentsMatcher
+	ReleaseMatcher       clientinterfaces.ReleaseMatcher
+	TyposquatMatcher     clientinterfaces.TyposquatMatcher
+	ProvenanceMatcher    clientinterfaces.ProvenanceMatcher
+	HashMatcher          clientinterfaces.HashMatcher
 
 	// Required for vendored Extractor
 	OSVDevClient *osvdev.OSVClient
@@ -109,10 +352,21 @@ type ExternalAccessors struct {
 // ErrNoPackagesFound for when no packages are found during a scan.
 var ErrNoPackagesFound = errors.New("no packages found in scan")
 
-// ErrVulnerabilitiesFound includes vulnerabilities, license violations, and package deprecation,
-// however, will not be raised if only uncalled vulnerabilities are found.
+// ErrVulnerabilitiesFound is returned when a scan finds anything that should
+// fail it: vulnerabilities, license violations, deprecated packages, packages
+// with a low OpenSSF Scorecard score, packages missing required provenance,
+// packages pinned to hashes that do not match their published files, and
+// dependencies that have drifted from their lockfiles.
+//
+// It is not returned if the only vulnerabilities found are uncalled ones, but
+// always is for malicious packages, and for expired ignores when
+// ScannerActions.FailOnExpiredIgnores is set.
 var ErrVulnerabilitiesFound = errors.New("vulnerabilities found")
 
+// ErrIgnoreWithoutReason is returned when ScannerActions.RequireIgnoreReasons
+// is set and an ignore entry in the config files does not give a reason.
+var ErrIgnoreWithoutReason = errors.New("ignored vulnerabilities must give a reason")
+
 // ErrAPIFailed describes errors related to querying API endpoints.
 // TODO(v2): Actually use this error
 var ErrAPIFailed = errors.New("API query failed")
@@ -132,22 +386,74 @@ func initializeExternalAccessors(actions ScannerActions) (ExternalAccessors, err
 		// --- Vulnerability Matcher ---
 		externalAccessors.VulnMatcher, err =
 			localmatcher.NewLocalMatcher(actions.LocalDBPath,
-				userAgent, actions.DownloadDatabases)
+				userAgent, actions.DownloadDatabases, actions.LocalDBMaxAge)
 		if err != nil {
 			return ExternalAccessors{}, err
 		}
 
+		if actions.Scorecard || actions.FailOnScorecardBelow > 0 {
+			cmdlogger.Warnf("OpenSSF Scorecards cannot be looked up in offline mode")
+		}
+		if actions.DeprecationReasons {
+			cmdlogger.Warnf("Archived source repositories and deprecation reasons cannot be looked up in offline mode")
+		}
+		if actions.Dependents || actions.SortByDependents {
+			cmdlogger.Warnf("Dependent counts cannot be looked up in offline mode")
+		}
+		if actions.ReleaseInfo || actions.Outdated {
+			cmdlogger.Warnf("Release dates and latest versions cannot be looked up in offline mode")
+		}
+		if actions.Typosquats {
+			cmdlogger.Warnf("Possible typosquats cannot be looked up in offline mode")
+		}
+		if actions.Provenance || actions.FailOnMissingProvenance {
+			cmdlogger.Warnf("Package provenance cannot be looked up in offline mode")
+		}
+		if actions.VerifyHashes {
+			cmdlogger.Warnf("Requirement hashes cannot be verified in offline mode")
+		}
+		if actions.SecondaryAdvisories {
+			cmdlogger.Warnf("Secondary advisory databases cannot be checked in offline mode")
+		}
+		if actions.EPSS || actions.MinEPSS > 0 || actions.SortByEPSS {
+			cmdlogger.Warnf("EPSS scores cannot be looked up in offline mode")
+		}
+		if actions.KEV || actions.FailOnKEV {
+			cmdlogger.Warnf("The CISA KEV catalog cannot be checked in offline mode")
+		}
+
 		return externalAccessors, nil
 	}
 
 	// Online Mode
 	// -----------
+	osvHTTPClient := osvAPIHTTPClient(actions.HTTPClient, actions.OSVHeaders, actions.OSVRequestsPerSecond)
+	osvBaseURL := apiconfig.CodexSecurityBaseURL
+	if actions.OSVBaseURL != "" {
+		osvBaseURL = actions.OSVBaseURL
+	}
+
 	// --- Vulnerability Matcher ---
-	externalAccessors.VulnMatcher = osvmatcher.New(5*time.Minute, userAgent, actions.HTTPClient)
+	vulnMatcher := osvmatcher.New(5*time.Minute, userAgent, osvHTTPClient)
+	vulnMatcher.Client.BaseHostURL = osvBaseURL
+	if actions.OSVMaxConcurrentRequests > 0 {
+		vulnMatcher.Client.Config.MaxConcurrentBatchRequests = actions.OSVMaxConcurrentRequests
+		vulnMatcher.MaxConcurrentRequests = actions.OSVMaxConcurrentRequests
+	}
+	externalAccessors.VulnMatcher = vulnMatcher
+
+	// The deps.dev clients are shared by the matchers that need them, and only
+	// created if any of them do
+	depsDevClient := sync.OnceValues(func() (*datasource.CachedInsightsClient, error) {
+		return datasource.NewCachedInsightsClient(depsdev.DepsdevAPI, userAgent)
+	})
+	depsDevAlphaClient := sync.OnceValues(func() (depsdevalphapb.InsightsClient, error) {
+		return internaldatasource.NewInsightsAlphaClient(depsdev.DepsdevAPI, userAgent)
+	})
 
 	// --- License Matcher ---
-	if len(actions.ScanLicensesAllowlist) > 0 || actions.ScanLicensesSummary {
-		depsDevAPIClient, err := datasource.NewCachedInsightsClient(depsdev.DepsdevAPI, userAgent)
+	if len(actions.ScanLicensesAllowlist) > 0 || len(actions.ScanLicensesDenylist) > 0 || actions.ScanLicensesSummary {
+		depsDevAPIClient, err := depsDevClient()
 		if err != nil {
 			return ExternalAccessors{}, err
 		}
@@ -157,15 +463,103 @@ func initializeExternalAccessors(actions ScannerActions) (ExternalAccessors, err
 		}
 	}
 
+	// --- Scorecard Matcher ---
+	if actions.Scorecard || actions.FailOnScorecardBelow > 0 {
+		depsDevAPIClient, err := depsDevClient()
+		if err != nil {
+			return ExternalAccessors{}, err
+		}
+
+		externalAccessors.ScorecardMatcher = &scorecardmatcher.DepsDevScorecardMatcher{
+			Client: depsDevAPIClient,
+		}
+	}
+
+	// --- Project Status Matcher ---
+	if actions.DeprecationReasons {
+		depsDevAPIClient, err := depsDevClient()
+		if err != nil {
+			return ExternalAccessors{}, err
+		}
+
+		matcher := &projectstatusmatcher.RegistryProjectStatusMatcher{
+			Client: depsDevAPIClient,
+			PyPI:   depsdev.NewPyPIRegistryClient("", depsdev.ClientOptions{UserAgent: userAgent}),
+		}
+		if npm, err := datasource.NewNPMRegistryAPIClient(""); err == nil {
+			matcher.NPM = npm
+		} else {
+			cmdlogger.Warnf("Failed to load npm registry config, deprecation messages of npm packages will not be looked up: %v", err)
+		}
+		externalAccessors.ProjectStatusMatcher = matcher
+	}
+
+	// --- Release Matcher ---
+	if actions.ReleaseInfo || actions.Outdated {
+		depsDevAPIClient, err := depsDevClient()
+		if err != nil {
+			return ExternalAccessors{}, err
+		}
+
+		externalAccessors.ReleaseMatcher = &releasematcher.DepsDevReleaseMatcher{
+			Client: depsDevAPIClient,
+		}
+	}
+
+	// --- Provenance Matcher ---
+	if actions.Provenance || actions.FailOnMissingProvenance {
+		depsDevAPIClient, err := depsDevClient()
+		if err != nil {
+			return ExternalAccessors{}, err
+		}
+
+		externalAccessors.ProvenanceMatcher = &provenancematcher.DepsDevProvenanceMatcher{
+			Client: depsDevAPIClient,
+		}
+	}
+
+	// --- Hash Matcher ---
+	if actions.VerifyHashes {
+		externalAccessors.HashMatcher = &hashmatcher.PyPIHashMatcher{
+			Registry: depsdev.NewPyPIRegistryClient("", depsdev.ClientOptions{UserAgent: userAgent}),
+		}
+	}
+
+	// --- Dependents Matcher ---
+	if actions.Dependents || actions.SortByDependents {
+		// Dependent counts are only available from the v3alpha API
+		insightsAlphaClient, err := depsDevAlphaClient()
+		if err != nil {
+			return ExternalAccessors{}, err
+		}
+
+		externalAccessors.DependentsMatcher = &dependentsmatcher.DepsDevDependentsMatcher{
+			Client: insightsAlphaClient,
+		}
+	}
+
+	// --- Typosquat Matcher ---
+	if actions.Typosquats {
+		// Similarly named packages are only available from the v3alpha API
+		insightsAlphaClient, err := depsDevAlphaClient()
+		if err != nil {
+			return ExternalAccessors{}, err
+		}
+
+		externalAccessors.TyposquatMatcher = &typosquatmatcher.DepsDevTyposquatMatcher{
+			Client: insightsAlphaClient,
+		}
+	}
+
 	// --- OSV.dev Client ---
 	// We create a separate client from VulnMatcher to keep things clean.
 	// Use Codex Security endpoint instead of upstream api.osv.dev
 	config := osvdev.DefaultConfig()
 	config.UserAgent = userAgent
 	externalAccessors.OSVDevClient = &osvdev.OSVClient{
-		HTTPClient:  http.DefaultClient,
+		HTTPClient:  osvHTTPClient,
 		Config:      config,
-		BaseHostURL: apiconfig.CodexSecurityBaseURL,
+		BaseHostURL: osvBaseURL,
 	}
 	externalAccessors.OSVDevClient.Config.UserAgent = userAgent
 
@@ -202,18 +596,69 @@ func DoScan(actions ScannerActions) (models.VulnerabilityResults, error) {
 		}
 	}
 
+	if oc := scanResult.ConfigManager.OverrideConfig; oc != nil {
+		actions.TransitiveScanning.DepsDevEndpoints = mergeDepsDevEndpoints(actions.TransitiveScanning.DepsDevEndpoints, oc.DepsDev)
+		applyOSVEndpoint(&actions.ExperimentalScannerActions, oc.OSV)
+		if err := applyEnrichers(&actions.ExperimentalScannerActions, oc.Enrichers); err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+	}
+
+	exclusions, err := parsePathExclusions(&scanResult.ConfigManager, actions.DirectoryPaths)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+	actions.pathExclusions = exclusions
+
+	// --- Load VEX documents ---
+	if len(actions.VEXPaths) > 0 {
+		statements, err := vexdoc.LoadAll(actions.VEXPaths)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+		scanResult.VEXStatements = statements
+	}
+
 	// --- Setup Accessors/Clients ---
 	accessors, err := initializeExternalAccessors(actions)
 	if err != nil {
 		return models.VulnerabilityResults{}, fmt.Errorf("failed to initialize accessors: %w", err)
 	}
 
+	var cache *scanCache
+	if actions.ScanCacheDir != "" {
+		cache, err = newScanCache(actions)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+		accessors.VulnMatcher = cache.vulnMatcher(accessors.VulnMatcher)
+	}
+
 	// ----- Perform Scanning -----
-	packagesAndFindings, err := scan(accessors, actions)
+	degradations := &depsdev.Degradations{}
+	actions.TransitiveScanning.degradations = degradations
+
+	packagesAndFindings, err := scan(accessors, actions, cache)
 	if err != nil {
 		return models.VulnerabilityResults{}, err
 	}
 
+	for _, d := range degradations.List() {
+		scanResult.Degradations = append(scanResult.Degradations, models.Degradation{
+			Source:   d.Path,
+			Enricher: d.Enricher,
+			Reason:   d.Reason,
+		})
+	}
+	for _, u := range degradations.Unresolved() {
+		scanResult.UnresolvedPackages = append(scanResult.UnresolvedPackages, models.UnresolvedPackage{
+			Source:  u.Path,
+			Name:    u.Name,
+			Version: u.Version,
+			Reason:  u.Reason,
+		})
+	}
+
 	// Convert to imodels.PackageScanResult for use in the rest of osv-scanner
 	for _, pkg := range packagesAndFindings.Packages {
 		pi := imodels.FromInventory(pkg)
@@ -225,6 +670,10 @@ func DoScan(actions ScannerActions) (models.VulnerabilityResults, error) {
 	}
 	scanResult.GenericFindings = packagesAndFindings.GenericFindings
 
+	if actions.LockfileDrift {
+		scanResult.Drift = detectDrift(scanResult.PackageScanResults)
+	}
+
 	// ----- Filtering -----
 	unscannablePackages := filterUnscannablePackages(&scanResult, actions)
 	filterIgnoredPackages(&scanResult)
@@ -232,6 +681,19 @@ func DoScan(actions ScannerActions) (models.VulnerabilityResults, error) {
 	// ----- Custom Overrides -----
 	overrideGoVersion(&scanResult)
 
+	// --- Make License Requests ---
+	err = matchLicenses(&accessors, actions, &scanResult)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
+	}
+
+	// --- Make Package Detail Requests ---
+	matchPackageDetails(context.Background(), scanResult.PackageScanResults, accessors)
+
+	if actions.OnSourceResults != nil {
+		return streamScanResult(scanResult, unscannablePackages, accessors.VulnMatcher, cache, actions)
+	}
+
 	// --- Make Vulnerability Requests ---
 	if accessors.VulnMatcher != nil {
 		err = makeVulnRequestWithMatcher(scanResult.PackageScanResults, accessors.VulnMatcher)
@@ -240,13 +702,7 @@ func DoScan(actions ScannerActions) (models.VulnerabilityResults, error) {
 		}
 	}
 
-	// --- Make License Requests ---
-	if accessors.LicenseMatcher != nil {
-		err = accessors.LicenseMatcher.MatchLicenses(context.Background(), scanResult.PackageScanResults)
-		if err != nil {
-			return models.VulnerabilityResults{}, err
-		}
-	}
+	saveScanCache(cache, &scanResult)
 
 	if len(unscannablePackages) > 0 {
 		scanResult.PackageScanResults = slices.Concat(scanResult.PackageScanResults, unscannablePackages)
@@ -255,6 +711,111 @@ func DoScan(actions ScannerActions) (models.VulnerabilityResults, error) {
 	return finalizeScanResult(scanResult, actions)
 }
 
+// streamScanResult matches the vulnerabilities of the packages one source at a
+// time, in the order that the sources are reported, and passes the results of
+// each to actions.OnSourceResults as soon as they are filtered. The results
+// of every source are then finalized together, as by finalizeScanResult.
+func streamScanResult(
+	scanResult results.ScanResults,
+	unscannablePackages []imodels.PackageScanResult,
+	matcher clientinterfaces.VulnerabilityMatcher,
+	cache *scanCache,
+	actions ScannerActions,
+) (models.VulnerabilityResults, error) {
+	bySource := groupPackagesBySource(scanResult.PackageScanResults)
+	unscannableBySource := groupPackagesBySource(unscannablePackages)
+	sources := slices.Collect(maps.Keys(bySource))
+	for source := range unscannableBySource {
+		if _, ok := bySource[source]; !ok {
+			sources = append(sources, source)
+		}
+	}
+	slices.SortFunc(sources, func(a, b models.SourceInfo) int {
+		return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Type, b.Type))
+	})
+
+	actions.kevCatalog = newKEVCatalog(actions)
+	packages := make([]imodels.PackageScanResult, 0, len(scanResult.PackageScanResults)+len(unscannablePackages))
+
+	// the results of no packages, for those of each source to be added to
+	scanResult.PackageScanResults = nil
+	vulnerabilityResults := buildVulnerabilityResults(actions, &scanResult)
+	filtered := 0
+
+	for _, source := range sources {
+		scanResult.PackageScanResults = bySource[source]
+		if matcher != nil && len(scanResult.PackageScanResults) > 0 {
+			if err := makeVulnRequestWithMatcher(scanResult.PackageScanResults, matcher); err != nil {
+				return models.VulnerabilityResults{}, err
+			}
+		}
+		scanResult.PackageScanResults = append(scanResult.PackageScanResults, unscannableBySource[source]...)
+
+		sourceResults, sourceFiltered := buildFilteredResults(&scanResult, actions)
+		for _, pkgSrc := range sourceResults.Results {
+			if err := actions.OnSourceResults(pkgSrc); err != nil {
+				return models.VulnerabilityResults{}, err
+			}
+		}
+
+		vulnerabilityResults.Results = append(vulnerabilityResults.Results, sourceResults.Results...)
+		vulnerabilityResults.ExperimentalIgnored = append(vulnerabilityResults.ExperimentalIgnored, sourceResults.ExperimentalIgnored...)
+		filtered += sourceFiltered
+		packages = append(packages, scanResult.PackageScanResults...)
+	}
+	scanResult.PackageScanResults = packages
+
+	saveScanCache(cache, &scanResult)
+
+	return finishScanResult(vulnerabilityResults, filtered, scanResult, actions)
+}
+
+// groupPackagesBySource groups packages by the source they were found in,
+// keeping their order within each source.
+func groupPackagesBySource(packages []imodels.PackageScanResult) map[models.SourceInfo][]imodels.PackageScanResult {
+	bySource := make(map[models.SourceInfo][]imodels.PackageScanResult)
+	for _, psr := range packages {
+		source := packageSource(psr.PackageInfo)
+		bySource[source] = append(bySource[source], psr)
+	}
+
+	return bySource
+}
+
+// saveScanCache saves the packages and vulnerabilities matched by the scan to
+// the cache, unless the results are partial, such as of files that were only
+// partially resolved or of ecosystems whose local database failed to load,
+// which would otherwise be stuck that way until the files change.
+func saveScanCache(cache *scanCache, scanResult *results.ScanResults) {
+	if len(scanResult.Degradations) == 0 && len(scanResult.UnresolvedPackages) == 0 && !cmdlogger.HasErrored() {
+		cache.save()
+	}
+}
+
+// mergeDepsDevEndpoints adds the deps.dev endpoints configured in the config
+// file to those already set, with the latter taking precedence.
+func mergeDepsDevEndpoints(endpoints map[string]DepsDevEndpoint, fromConfig map[string]config.DepsDevEndpoint) map[string]DepsDevEndpoint {
+	if len(fromConfig) == 0 {
+		return endpoints
+	}
+
+	merged := maps.Clone(endpoints)
+	if merged == nil {
+		merged = make(map[string]DepsDevEndpoint, len(fromConfig))
+	}
+	for ecosystem, endpoint := range fromConfig {
+		if _, ok := merged[ecosystem]; ok {
+			continue
+		}
+		merged[ecosystem] = DepsDevEndpoint{
+			BaseURL: endpoint.BaseURL,
+			Headers: endpoint.ExpandedHeaders(),
+		}
+	}
+
+	return merged
+}
+
 func DoContainerScan(actions ScannerActions) (models.VulnerabilityResults, error) {
 	scanResult := results.ScanResults{
 		ConfigManager: config.Manager{
@@ -271,6 +832,22 @@ func DoContainerScan(actions ScannerActions) (models.VulnerabilityResults, error
 		}
 	}
 
+	if oc := scanResult.ConfigManager.OverrideConfig; oc != nil {
+		applyOSVEndpoint(&actions.ExperimentalScannerActions, oc.OSV)
+		if err := applyEnrichers(&actions.ExperimentalScannerActions, oc.Enrichers); err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+	}
+
+	// --- Load VEX documents ---
+	if len(actions.VEXPaths) > 0 {
+		statements, err := vexdoc.LoadAll(actions.VEXPaths)
+		if err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+		scanResult.VEXStatements = statements
+	}
+
 	// --- Setup Accessors/Clients ---
 	accessors, err := initializeExternalAccessors(actions)
 	if err != nil {
@@ -313,6 +890,16 @@ func DoContainerScan(actions ScannerActions) (models.VulnerabilityResults, error
 	if actions.IsImageArchive {
 		cmdlogger.Infof("Scanning local image tarball %q", actions.Image)
 		img, err = image.FromTarball(actions.Image, image.DefaultConfig())
+	} else if actions.Image != "" && (actions.IsImageRemote || !imagehelpers.DockerAvailable()) {
+		if !actions.IsImageRemote {
+			cmdlogger.Infof("docker could not be found, so the image will be pulled from its registry")
+		}
+		cmdlogger.Infof("Scanning image %q from its registry", actions.Image)
+		img, err = image.FromRemoteName(
+			actions.Image,
+			image.DefaultConfig(),
+			imagehelpers.RemoteOptions(ctx, actions.RequestUserAgent, actions.HTTPClient)...,
+		)
 	} else if actions.Image != "" {
 		path, exportErr := imagehelpers.ExportDockerImage(ctx, actions.Image)
 		if exportErr != nil {
@@ -397,30 +984,66 @@ func DoContainerScan(actions ScannerActions) (models.VulnerabilityResults, error
 	}
 
 	// --- Make License Requests ---
-	if accessors.LicenseMatcher != nil {
-		err = accessors.LicenseMatcher.MatchLicenses(context.Background(), scanResult.PackageScanResults)
-		if err != nil {
-			return models.VulnerabilityResults{}, err
-		}
+	err = matchLicenses(&accessors, actions, &scanResult)
+	if err != nil {
+		return models.VulnerabilityResults{}, err
 	}
 
+	// --- Make Package Detail Requests ---
+	matchPackageDetails(ctx, scanResult.PackageScanResults, accessors)
+
 	scanResult.GenericFindings = scalibrSR.Inventory.GenericFindings
 
 	if len(unscannablePackages) > 0 {
 		scanResult.PackageScanResults = slices.Concat(scanResult.PackageScanResults, unscannablePackages)
 	}
 
-	return finalizeScanResult(scanResult, actions)
+	vulnResults, err := finalizeScanResult(scanResult, actions)
+
+	if (actions.RecommendBaseImageUpgrade || actions.BaseImageUpgrade != "") && vulnResults.ImageMetadata != nil {
+		vulnResults.ImageMetadata.BaseImageUpgrade = recommendBaseImageUpgrade(ctx, actions, vulnResults)
+	}
+
+	return vulnResults, err
 }
 
 func finalizeScanResult(scanResult results.ScanResults, actions ScannerActions) (models.VulnerabilityResults, error) {
-	vulnerabilityResults := buildVulnerabilityResults(actions, &scanResult)
+	vulnerabilityResults, filtered := buildFilteredResults(&scanResult, actions)
 
+	return finishScanResult(vulnerabilityResults, filtered, scanResult, actions)
+}
+
+// buildFilteredResults builds the results of the packages, enriches them, and
+// filters out the vulnerabilities that are ignored or otherwise not reported.
+// Returns the results along with the number of vulnerabilities filtered out.
+func buildFilteredResults(scanResult *results.ScanResults, actions ScannerActions) (models.VulnerabilityResults, int) {
+	vulnerabilityResults := buildVulnerabilityResults(actions, scanResult)
+	applySecondaryAdvisories(&vulnerabilityResults, actions)
+	applyEPSS(&vulnerabilityResults, actions)
+	applyKEV(&vulnerabilityResults, actions)
+	if actions.SortByDependents {
+		sortByDependents(&vulnerabilityResults)
+	}
+
+	filtered := filterResults(&vulnerabilityResults, &scanResult.ConfigManager, actions.ShowAllPackages)
+	filtered += filterNotAffected(&vulnerabilityResults, scanResult.VEXStatements, actions.ShowAllPackages)
+	if actions.RequireReachable {
+		filtered += filterUnreachable(&vulnerabilityResults, actions.ShowAllPackages)
+	}
+	if actions.Baseline != nil {
+		filtered += filterBaseline(&vulnerabilityResults, actions.Baseline, actions.DirectoryPaths)
+	}
+
+	return vulnerabilityResults, filtered
+}
+
+// finishScanResult completes the filtered results of every package of the
+// scan, and determines the error to return with them.
+func finishScanResult(vulnerabilityResults models.VulnerabilityResults, filtered int, scanResult results.ScanResults, actions ScannerActions) (models.VulnerabilityResults, error) {
 	if actions.ScanLicensesSummary {
 		vulnerabilityResults.LicenseSummary = buildLicenseSummary(&scanResult)
 	}
 
-	filtered := filterResults(&vulnerabilityResults, &scanResult.ConfigManager, actions.ShowAllPackages)
 	if filtered > 0 {
 		cmdlogger.Infof(
 			"Filtered %d %s from output",
@@ -429,6 +1052,10 @@ func finalizeScanResult(scanResult results.ScanResults, actions ScannerActions)
 		)
 	}
 
+	if actions.UpgradePlan {
+		vulnerabilityResults.ExperimentalUpgradePlan = planUpgrades(vulnerabilityResults, actions.ShowAllVulns)
+	}
+
 	if unusedIgnoredEntries := scanResult.ConfigManager.GetUnusedIgnoreEntries(); len(unusedIgnoredEntries) != 0 {
 		configFiles := slices.Collect(maps.Keys(unusedIgnoredEntries))
 		slices.Sort(configFiles)
@@ -442,7 +1069,54 @@ func finalizeScanResult(scanResult results.ScanResults, actions ScannerActions)
 		}
 	}
 
-	return vulnerabilityResults, determineReturnErr(vulnerabilityResults, actions.ShowAllVulns)
+	if actions.RequireIgnoreReasons {
+		if err := checkIgnoreReasons(&scanResult.ConfigManager); err != nil {
+			return models.VulnerabilityResults{}, err
+		}
+	}
+
+	expiredIgnores := warnAboutExpiredIgnores(&scanResult.ConfigManager)
+
+	err := determineReturnErr(vulnerabilityResults, actions.ShowAllVulns, actions.FailOnKEV)
+	if err == nil && expiredIgnores && actions.FailOnExpiredIgnores {
+		err = ErrVulnerabilitiesFound
+	}
+
+	return vulnerabilityResults, err
+}
+
+// warnAboutExpiredIgnores warns about the ignore entries that have expired,
+// and reports whether there are any.
+func warnAboutExpiredIgnores(manager *config.Manager) bool {
+	expired := manager.GetExpiredIgnoreEntries()
+	for _, configFile := range slices.Sorted(maps.Keys(expired)) {
+		cmdlogger.Warnf("%s has expired ignores, whose vulnerabilities are reported again:", configFile)
+		for _, iv := range expired[configFile] {
+			cmdlogger.Warnf(" - %s (expired %s)", iv.ID, iv.IgnoreUntil.Format(time.DateOnly))
+		}
+	}
+
+	return len(expired) > 0
+}
+
+// checkIgnoreReasons returns ErrIgnoreWithoutReason, listing the entries of
+// each config file that do not give a reason, if there are any.
+func checkIgnoreReasons(manager *config.Manager) error {
+	reasonless := manager.GetReasonlessIgnoreEntries()
+	if len(reasonless) == 0 {
+		return nil
+	}
+
+	files := make([]string, 0, len(reasonless))
+	for _, configFile := range slices.Sorted(maps.Keys(reasonless)) {
+		ids := make([]string, 0, len(reasonless[configFile]))
+		for _, iv := range reasonless[configFile] {
+			ids = append(ids, iv.ID)
+		}
+		files = append(files, configFile+" ("+strings.Join(ids, ", ")+")")
+	}
+
+	return fmt.Errorf("%w, but some in %s do not", ErrIgnoreWithoutReason, strings.Join(files, " and "))
 }
 
 func buildLicenseSummary(scanResult *results.ScanResults) []models.LicenseCount {
@@ -489,14 +1163,27 @@ func buildLicenseSummary(scanResult *results.ScanResults) []models.LicenseCount
 
 // determineReturnErr determines whether we found a "vulnerability" or not,
 // and therefore whether we should return a ErrVulnerabilityFound error.
-func determineReturnErr(vulnResults models.VulnerabilityResults, showAllVulns bool) error {
+// When kevOnly is set, only vulnerabilities in the CISA KEV catalog count.
+func determineReturnErr(vulnResults models.VulnerabilityResults, showAllVulns bool, kevOnly bool) error {
+	if len(vulnResults.ExperimentalDrift) > 0 {
+		return ErrVulnerabilitiesFound
+	}
+
 	if len(vulnResults.Results) > 0 {
 		var vuln bool
 		onlyUnimportantVuln := true
 		var licenseViolation bool
 		deprecated := false
+		scorecardViolation := false
+		provenanceViolation := false
+		hashMismatch := false
 		for _, vf := range vulnResults.Flatten() {
-			if vf.Vulnerability != nil && vf.Vulnerability.GetId() != "" {
+			// Malicious packages fail the scan even if they would otherwise
+			// be left out by --fail-on-kev or as uncalled or unimportant.
+			if vf.GroupInfo.Malicious {
+				return ErrVulnerabilitiesFound
+			}
+			if vf.Vulnerability != nil && vf.Vulnerability.GetId() != "" && (!kevOnly || vf.GroupInfo.KEV != nil) {
 				vuln = true
 				// TODO(gongh): rewrite the logic once we support reachability analysis for container scanning.
 				if vf.GroupInfo.IsCalled() && !vf.GroupInfo.IsGroupUnimportant() {
@@ -509,13 +1196,22 @@ func determineReturnErr(vulnResults models.VulnerabilityResults, showAllVulns bo
 			if vf.Deprecated {
 				deprecated = true
 			}
+			if vf.ScorecardViolation {
+				scorecardViolation = true
+			}
+			if vf.ProvenanceViolation {
+				provenanceViolation = true
+			}
+			if len(vf.HashMismatches) > 0 {
+				hashMismatch = true
+			}
 		}
 
-		if !vuln && !licenseViolation && !deprecated {
+		if !vuln && !licenseViolation && !deprecated && !scorecardViolation && !provenanceViolation && !hashMismatch {
 			return nil
 		}
 
-		onlyUnimportantVuln = onlyUnimportantVuln && vuln && !licenseViolation && !deprecated
+		onlyUnimportantVuln = onlyUnimportantVuln && vuln && !licenseViolation && !deprecated && !scorecardViolation && !provenanceViolation && !hashMismatch
 
 		// If the user didn't enable showing all vulns and we only found unimportant ones,
 		// we should return without error.
@@ -0,0 +1,14 @@
+package osvscanner
+
+import "github.com/google/osv-scanner/v2/pkg/models"
+
+// isOutdated reports whether a package version is at least majorVersions
+// major versions or months months behind its latest release, ignoring either
+// limit when it is 0.
+func isOutdated(release models.Release, majorVersions int, months int) bool {
+	if majorVersions > 0 && release.MajorVersionsBehind >= majorVersions {
+		return true
+	}
+
+	return months > 0 && release.MonthsBehind >= months
+}
@@ -0,0 +1,71 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/osv-scanner/v2/pkg/models"
+)
+
+func Test_isOutdated(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		release       models.Release
+		majorVersions int
+		months        int
+		want          bool
+	}{
+		{
+			name:          "up_to_date",
+			release:       models.Release{LatestVersion: "5.1.0"},
+			majorVersions: 1,
+			months:        12,
+			want:          false,
+		},
+		{
+			name:          "major_versions_behind",
+			release:       models.Release{LatestVersion: "5.1.0", MajorVersionsBehind: 1, MonthsBehind: 3},
+			majorVersions: 1,
+			months:        12,
+			want:          true,
+		},
+		{
+			name:          "months_behind",
+			release:       models.Release{LatestVersion: "1.3.0", MonthsBehind: 12},
+			majorVersions: 1,
+			months:        12,
+			want:          true,
+		},
+		{
+			name:          "within_both_limits",
+			release:       models.Release{LatestVersion: "5.1.0", MajorVersionsBehind: 1, MonthsBehind: 11},
+			majorVersions: 2,
+			months:        12,
+			want:          false,
+		},
+		{
+			name:          "major_versions_not_checked",
+			release:       models.Release{LatestVersion: "5.1.0", MajorVersionsBehind: 3, MonthsBehind: 6},
+			majorVersions: 0,
+			months:        12,
+			want:          false,
+		},
+		{
+			name:          "nothing_checked",
+			release:       models.Release{LatestVersion: "5.1.0", MajorVersionsBehind: 3, MonthsBehind: 60},
+			majorVersions: 0,
+			months:        0,
+			want:          false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isOutdated(tt.release, tt.majorVersions, tt.months); got != tt.want {
+				t.Errorf("isOutdated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
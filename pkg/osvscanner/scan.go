@@ -9,6 +9,7 @@ import (
 	"runtime"
 	"slices"
 	"strings"
+	"testing/fstest"
 
 	scalibr "github.com/google/osv-scalibr"
 	cpb "github.com/google/osv-scalibr/binary/proto/config_go_proto"
@@ -26,21 +27,36 @@ import (
 	"github.com/google/osv-scalibr/plugin"
 	"github.com/google/osv-scanner/v2/internal/apiconfig"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/config"
 	depsdevpypi "github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/google/osv-scanner/v2/internal/gitfs"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/compose"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/dockerfile"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/helm"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/containers/kubernetes"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/filesystem/vendored"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/java/pomxmlenhanceable"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/sbom/purllist"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/vcs/gitcommitdirect"
 	"github.com/google/osv-scanner/v2/internal/scalibrextract/vcs/gitrepo"
 	"github.com/google/osv-scanner/v2/internal/scalibrplugin"
 	"github.com/google/osv-scanner/v2/internal/testlogger"
 	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/scanners"
+	"github.com/ossf/osv-schema/bindings/go/osvconstants"
 )
 
 var ErrExtractorNotFound = errors.New("could not determine extractor suitable to this file")
 
+const (
+	// StdinLocation is the location of the packages of ScannerActions.StdinLockfile.
+	StdinLocation = "<stdin>"
+	// PURLsLocation is the location of the packages of ScannerActions.PURLs.
+	PURLsLocation = "<purl>"
+)
+
 func configurePlugins(plugins []plugin.Plugin, accessors ExternalAccessors, actions ScannerActions) {
 	for _, plug := range plugins {
-		if !actions.TransitiveScanning.Disabled {
+		if !actions.TransitiveScanning.Disabled && !actions.TransitiveScanning.MavenDisabled {
 			err := pomxmlenhanceable.EnhanceIfPossible(plug, &cpb.PluginConfig{
 				UserAgent: actions.RequestUserAgent,
 				PluginSpecific: []*cpb.PluginSpecificConfig{
@@ -59,19 +75,38 @@ func configurePlugins(plugins []plugin.Plugin, accessors ExternalAccessors, acti
 			}
 		}
 
+		pomxmlenhanceable.Configure(plug, pomxmlenhanceable.Config{
+			ExcludedScopes: actions.TransitiveScanning.MavenExcludedScopes,
+		})
+
 		vendored.Configure(plug, vendored.Config{
 			// Only attempt to vendor check git directories if we are not skipping scanning root git directories
 			ScanGitDir: !actions.IncludeGitRoot,
 			OSVClient:  accessors.OSVDevClient,
 		})
+
+		helm.Configure(plug, helm.Config{
+			ValuesFiles: actions.HelmValuesFiles,
+		})
 	}
 }
 
+// isRequirementsExtractorEnabled reports whether the requirements extractor,
+// which the transitive enrichers require, is enabled. The requirements of
+// wheels and source distributions are only enriched along with it.
 func isRequirementsExtractorEnabled(plugins []plugin.Plugin) bool {
 	for _, plug := range plugins {
-		_, ok := plug.(*requirements.Extractor)
+		if _, ok := plug.(*requirements.Extractor); ok {
+			return true
+		}
+	}
 
-		if ok {
+	return false
+}
+
+func isSBOMExtractorEnabled(plugins []plugin.Plugin) bool {
+	for _, plug := range plugins {
+		if _, ok := scalibrplugin.ExtractorPresets["sbom"][plug.Name()]; ok {
 			return true
 		}
 	}
@@ -95,7 +130,7 @@ func getPlugins(defaultPlugins []string, accessors ExternalAccessors, actions Sc
 	plugins := scalibrplugin.Resolve(actions.PluginsEnabled, actions.PluginsDisabled)
 
 	// TODO: Use Enricher.RequiredPlugins to check this generically
-	if !actions.TransitiveScanning.Disabled && isRequirementsExtractorEnabled(plugins) {
+	if !actions.TransitiveScanning.Disabled && !actions.TransitiveScanning.PyPIDisabled && isRequirementsExtractorEnabled(plugins) {
 		var p plugin.Plugin
 		var err error
 		if actions.TransitiveScanning.NativeDataSource {
@@ -104,8 +139,19 @@ func getPlugins(defaultPlugins []string, accessors ExternalAccessors, actions Sc
 				UserAgent: actions.RequestUserAgent,
 			})
 		} else {
-			// Use deps.dev REST API for pre-computed dependency graphs (fast)
-			p, err = depsdevpypi.NewPyPIDepsDevEnricher(apiconfig.DepsDevAPIURL)
+			// Use deps.dev API for pre-computed dependency graphs (fast)
+			cfg := depsDevConfig(actions)
+			if endpoint, ok := actions.TransitiveScanning.DepsDevEndpoints[string(osvconstants.EcosystemPyPI)]; ok {
+				// A per-ecosystem endpoint is always a REST mirror
+				cfg.Transport = depsdevpypi.TransportREST
+				if endpoint.BaseURL != "" {
+					cfg.BaseURL = endpoint.BaseURL
+				}
+				if endpoint.Headers != nil {
+					cfg.Headers = endpoint.Headers
+				}
+			}
+			p, err = depsdevpypi.NewPyPIDepsDevEnricher(cfg)
 		}
 		if err != nil {
 			log.Errorf("Failed to make transitivedependencyrequirements enricher: %v", err)
@@ -114,11 +160,53 @@ func getPlugins(defaultPlugins []string, accessors ExternalAccessors, actions Sc
 		}
 	}
 
+	if actions.SBOMEnrichment && isSBOMExtractorEnabled(plugins) {
+		p, err := depsdevpypi.NewSBOMDepsDevEnricher(depsDevConfig(actions))
+		if err != nil {
+			log.Errorf("Failed to make SBOM enricher: %v", err)
+		} else {
+			plugins = append(plugins, p)
+		}
+	}
+
 	configurePlugins(plugins, accessors, actions)
 
 	return plugins
 }
 
+// depsDevConfig returns the configuration of the enrichers that resolve
+// dependencies through deps.dev.
+func depsDevConfig(actions ScannerActions) depsdevpypi.Config {
+	cfg := depsdevpypi.Config{
+		BaseURL:      apiconfig.DepsDevAPIURL,
+		SnapshotPath: actions.TransitiveScanning.DepsDevSnapshotPath,
+		CacheLimits: depsdevpypi.CacheLimits{
+			MaxEntries: actions.TransitiveScanning.CacheMaxEntries,
+			MaxBytes:   actions.TransitiveScanning.CacheMaxBytes,
+		},
+		HTTPClient:  actions.HTTPClient,
+		UserAgent:   actions.RequestUserAgent,
+		Headers:     actions.TransitiveScanning.DepsDevHeaders,
+		Metrics:     actions.TransitiveScanning.DepsDevMetrics,
+		Parallelism: actions.TransitiveScanning.Parallelism,
+
+		CircuitBreakerThreshold: actions.TransitiveScanning.CircuitBreakerThreshold,
+		ConflictStrategy:        depsdevpypi.ConflictStrategy(actions.TransitiveScanning.ConflictStrategy),
+		MaxDepth:                actions.TransitiveScanning.MaxDepth,
+		Degradations:            actions.TransitiveScanning.degradations,
+
+		PythonEnvironment: depsdevpypi.PythonEnvironment{
+			Version:  actions.TransitiveScanning.PythonVersion,
+			Platform: actions.TransitiveScanning.PythonPlatform,
+		},
+	}
+	if actions.TransitiveScanning.DepsDevGRPC {
+		cfg.Transport = depsdevpypi.TransportGRPC
+	}
+
+	return cfg
+}
+
 // countNotEnrichers counts the number of plugins that are not enricher.Enricher plugins
 func countNotEnrichers(plugins []plugin.Plugin) int {
 	count := 0
@@ -132,15 +220,82 @@ func countNotEnrichers(plugins []plugin.Plugin) int {
 	return count
 }
 
-// scan essentially converts ScannerActions into imodels.ScanResult by performing the extractions
-func scan(accessors ExternalAccessors, actions ScannerActions) (*inventory.Inventory, error) {
-	var inv inventory.Inventory
+// sourcePlugins returns the plugins that a source scan runs.
+func sourcePlugins(accessors ExternalAccessors, actions ScannerActions) []plugin.Plugin {
+	if actions.DockerfileBaseImages {
+		actions.PluginsEnabled = append(actions.PluginsEnabled, dockerfile.Name)
+	}
+	if actions.ManifestImages {
+		actions.PluginsEnabled = append(actions.PluginsEnabled, kubernetes.Name, compose.Name)
+	}
+	if actions.HelmCharts {
+		actions.PluginsEnabled = append(actions.PluginsEnabled, helm.Name)
+	}
 
-	plugins := getPlugins(
+	return getPlugins(
 		[]string{"lockfile", "sbom", "directory"},
 		accessors,
 		actions,
 	)
+}
+
+// FileRequired returns a function reporting whether a source scan with the
+// given actions would extract packages from a file, as decided by the
+// extractors it runs from the path of the file relative to the scanned
+// directory, so that watch mode only has to watch the files that can change
+// the results of a scan. The configuration files that a scan reads are
+// required too. Files are only stat'ed if an extractor needs to.
+func FileRequired(actions ScannerActions) func(path string, entry os.DirEntry) bool {
+	var extractors []filesystem.Extractor
+	for _, plug := range sourcePlugins(ExternalAccessors{}, actions) {
+		if ext, ok := plug.(filesystem.Extractor); ok {
+			extractors = append(extractors, ext)
+		}
+	}
+
+	return func(path string, entry os.DirEntry) bool {
+		api := dirEntryFileAPI{path: filepath.ToSlash(path), entry: entry}
+		if filepath.Base(api.path) == config.OSVScannerConfigName {
+			return true
+		}
+
+		for _, ext := range extractors {
+			if ext.FileRequired(api) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// dirEntryFileAPI is a filesystem.FileAPI of a file found while walking a
+// directory, which is stat'ed when asked to.
+type dirEntryFileAPI struct {
+	path  string
+	entry os.DirEntry
+}
+
+func (f dirEntryFileAPI) Path() string {
+	return f.path
+}
+
+func (f dirEntryFileAPI) Stat() (os.FileInfo, error) {
+	return f.entry.Info()
+}
+
+// scan essentially converts ScannerActions into imodels.ScanResult by performing the extractions
+func scan(accessors ExternalAccessors, actions ScannerActions, cache *scanCache) (*inventory.Inventory, error) {
+	var inv inventory.Inventory
+
+	// Collect deps.dev usage to report in debug output, unless the caller is already collecting it
+	var depsDevStats *depsdevpypi.Stats
+	if actions.TransitiveScanning.DepsDevMetrics == nil {
+		depsDevStats = &depsdevpypi.Stats{}
+		actions.TransitiveScanning.DepsDevMetrics = depsDevStats
+	}
+
+	plugins := sourcePlugins(accessors, actions)
 
 	// technically having one detector enabled would also be sufficient, but we're
 	// not mentioning them to avoid confusion since they're still in their infancy
@@ -163,6 +318,8 @@ func scan(accessors ExternalAccessors, actions ScannerActions) (*inventory.Inven
 		}
 	}
 
+	plugins = withEnrichmentBudget(plugins, actions.EnrichmentTimeout, actions.TransitiveScanning.degradations)
+
 	scanner := scalibr.New()
 
 	// Build list of paths for each root
@@ -177,16 +334,41 @@ func scan(accessors ExternalAccessors, actions ScannerActions) (*inventory.Inven
 
 	statsCollector := fileOpenedPrinter{
 		filesExtracted: make(map[string]struct{}),
+		cache:          cache,
 	}
 
+	// The filesystems of the repositories being scanned at actions.GitRef
+	gitRoots := map[string]*gitfs.FS{}
+
 	// --- Directories ---
 	for _, path := range actions.DirectoryPaths {
+		if actions.GitRef != "" {
+			absPath, err := filepath.Abs(path)
+			if err != nil {
+				return nil, err
+			}
+			gitFS, commit, err := gitfs.Open(absPath, actions.GitRef)
+			if err != nil {
+				return nil, err
+			}
+			cmdlogger.Infof("Scanning git repository %s at %s (%s)", path, actions.GitRef, commit)
+			gitRoots[absPath] = gitFS
+			// the root is listed so that it is scanned even when not recursing
+			rootMap[absPath] = []string{"."}
+
+			continue
+		}
+
 		cmdlogger.Infof("Scanning dir %s", path)
 		if _, err := pathToRootMap(rootMap, path, actions.Recursive); err != nil {
 			return nil, err
 		}
 	}
 
+	if actions.GitRef != "" && (len(actions.LockfilePaths) > 0 || len(actions.SBOMPaths) > 0 || actions.StdinLockfile != nil || len(actions.PURLs) > 0) {
+		return nil, errors.New("lockfiles cannot be given when scanning a git ref, as they are found in the repositories being scanned")
+	}
+
 	// --- Lockfiles ---
 	for _, lockfileElem := range actions.LockfilePaths {
 		parseAs, path := scanners.ParseLockfilePath(lockfileElem)
@@ -232,6 +414,43 @@ SBOMLoop:
 		return nil, fmt.Errorf("invalid SBOM filename: %s", sbomPath)
 	}
 
+	// --- Stdin and package URLs ---
+	// these are the files of a root held in memory, whose path is empty so
+	// that the paths of the files are the same relative and absolute
+	memFS := fstest.MapFS{}
+	// the locations reported for the packages of each file
+	memLocations := map[string]string{}
+	addMemFile := func(name string, data []byte, plug filesystem.Extractor, location string) {
+		memFS[name] = &fstest.MapFile{Data: data, Mode: 0o444}
+		memLocations[name] = location
+		rootMap[""] = append(rootMap[""], name)
+		overrideMap[name] = plug
+		specificPaths = append(specificPaths, name)
+	}
+
+	if actions.StdinLockfile != nil {
+		plug, err := scanners.ParseAsToPlugin(actions.StdinLockfileType, plugins)
+		if err != nil {
+			return nil, err
+		}
+
+		// extractors and enrichers can depend on the name of the file
+		addMemFile(stdinFileName(actions.StdinLockfileType), actions.StdinLockfile, plug, StdinLocation)
+		cmdlogger.Infof("Scanning %s from stdin", actions.StdinLockfileType)
+	}
+
+	if len(actions.PURLs) > 0 {
+		// check them here so that the one that is invalid can be told apart
+		for _, p := range actions.PURLs {
+			if _, err := purllist.ToPackage(p); err != nil {
+				return nil, err
+			}
+		}
+
+		addMemFile(purlsFileName, []byte(strings.Join(actions.PURLs, "\n")), purllist.Extractor{}, PURLsLocation)
+		cmdlogger.Infof("Scanning %d package URL(s)", len(actions.PURLs))
+	}
+
 	// --- Add git commits directly ---
 	gitDirectPlugin := gitcommitdirect.New(actions.GitCommits)
 
@@ -264,10 +483,28 @@ SBOMLoop:
 			capabilities.Network = plugin.NetworkOffline
 		}
 
+		scanRoots := fs.RealFSScanRoots(root)
+		gitFS, isGitRoot := gitRoots[root]
+		// the prefix of the paths of the files in the root, for the cache
+		cachePrefix := ""
+		if isGitRoot {
+			// the files are only in the repository, so cannot be read from disk
+			capabilities.DirectFS = false
+			capabilities.RunningSystem = false
+			scanRoots = []*fs.ScanRoot{{FS: gitFS}}
+			cachePrefix = root
+		}
+		isMemRoot := len(memFS) > 0 && root == ""
+		if isMemRoot {
+			capabilities.DirectFS = false
+			capabilities.RunningSystem = false
+			scanRoots = []*fs.ScanRoot{{FS: memFS}}
+		}
+
 		sr := scanner.Scan(context.Background(), &scalibr.ScanConfig{
-			Plugins:               append(plugin.FilterByCapabilities(plugins, &capabilities), gitDirectPlugin),
+			Plugins:               append(withScanCache(withPathExclusions(plugin.FilterByCapabilities(plugins, &capabilities), actions.pathExclusions, root, specificPaths), cache, cachePrefix), gitDirectPlugin),
 			Capabilities:          &capabilities,
-			ScanRoots:             fs.RealFSScanRoots(root),
+			ScanRoots:             scanRoots,
 			PathsToExtract:        paths,
 			IgnoreSubDirs:         !actions.Recursive,
 			DirsToSkip:            excludePatterns.dirsToSkip,
@@ -284,7 +521,7 @@ SBOMLoop:
 			ExtractorOverride: func(api filesystem.FileAPI) []filesystem.Extractor {
 				ext, ok := overrideMap[filepath.Join(root, filepath.FromSlash(api.Path()))]
 				if ok {
-					return []filesystem.Extractor{ext}
+					return []filesystem.Extractor{cache.extractor(ext, cachePrefix)}
 				}
 
 				return []filesystem.Extractor{}
@@ -319,6 +556,28 @@ SBOMLoop:
 			}
 		}
 
+		if isGitRoot {
+			// locations are relative to the virtual filesystem, so place them
+			// in the repository as they would be if it was checked out
+			for _, pkg := range sr.Inventory.Packages {
+				for i, loc := range pkg.Locations {
+					pkg.Locations[i] = filepath.Join(root, filepath.FromSlash(loc))
+				}
+			}
+		}
+
+		sr.Inventory.Packages = append(sr.Inventory.Packages, cache.collect(sr.Inventory.Packages)...)
+
+		if isMemRoot {
+			for _, pkg := range sr.Inventory.Packages {
+				for i, loc := range pkg.Locations {
+					if location, ok := memLocations[loc]; ok {
+						pkg.Locations[i] = location
+					}
+				}
+			}
+		}
+
 		slices.SortFunc(sr.Inventory.Packages, inventorySort)
 		invsCompact := slices.CompactFunc(sr.Inventory.Packages, func(a, b *extractor.Package) bool {
 			return inventorySort(a, b) == 0
@@ -331,6 +590,14 @@ SBOMLoop:
 
 	testlogger.EndDirScanMarker()
 
+	inv.Packages = scanReferencedImages(context.Background(), accessors, actions, inv.Packages)
+
+	if depsDevStats != nil {
+		if summary := depsDevStats.String(); summary != "" {
+			cmdlogger.Debugf("deps.dev usage during transitive scanning:\n%s", summary)
+		}
+	}
+
 	// Check if specific paths have been extracted.
 	// This allows us to error if a specific file provided by the user failed to extract, and return an error for them.
 	for _, path := range specificPaths {
@@ -346,6 +613,20 @@ SBOMLoop:
 	return &inv, nil
 }
 
+// purlsFileName is the name of the file of ScannerActions.PURLs, which cannot
+// be the name of a lockfile type read from stdin.
+const purlsFileName = "<purls>"
+
+// stdinFileName returns the name to give a lockfile of the given type, which
+// is the type itself for those named after the file they are parsed from.
+func stdinFileName(lockfileType string) string {
+	if lockfileType == "osv-scanner" {
+		return "osv-scanner.json"
+	}
+
+	return lockfileType
+}
+
 // pathToRootMap saves the absolute path into the root map, and returns the absolute path.
 // path is only saved if it does not fall under an existing path.
 // IMPORTANT: it does not remove existing paths already added to the rootMap, so add directories before specific files.
@@ -1,8 +1,11 @@
 package osvscanner
 
 import (
+	"fmt"
 	"path/filepath"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func Test_isDescendent(t *testing.T) {
@@ -98,3 +101,71 @@ func Test_isDescendent(t *testing.T) {
 		})
 	}
 }
+
+func Test_scan_Stdin(t *testing.T) {
+	t.Parallel()
+
+	inv, err := scan(ExternalAccessors{}, ScannerActions{
+		StdinLockfile:     []byte("flask==2.0.0\nrequests==2.31.0\n"),
+		StdinLockfileType: "requirements.txt",
+		ExperimentalScannerActions: ExperimentalScannerActions{
+			TransitiveScanning: TransitiveScanningActions{Disabled: true},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("scan() error = %v", err)
+	}
+
+	var got []string
+	for _, pkg := range inv.Packages {
+		got = append(got, fmt.Sprintf("%s@%s %v", pkg.Name, pkg.Version, pkg.Locations))
+	}
+	want := []string{"flask@2.0.0 [<stdin>]", "requests@2.31.0 [<stdin>]"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("scan() mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = scan(ExternalAccessors{}, ScannerActions{
+		StdinLockfile:     []byte("flask==2.0.0\n"),
+		StdinLockfileType: "not-a-lockfile",
+	}, nil)
+	if err == nil {
+		t.Errorf("scan() with an unknown lockfile type did not error")
+	}
+}
+
+func Test_scan_PURLs(t *testing.T) {
+	t.Parallel()
+
+	inv, err := scan(ExternalAccessors{}, ScannerActions{
+		PURLs:             []string{"pkg:npm/lodash@4.17.20", "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+		StdinLockfile:     []byte("pkg:pypi/flask@2.0.0\n"),
+		StdinLockfileType: "purls",
+		ExperimentalScannerActions: ExperimentalScannerActions{
+			TransitiveScanning: TransitiveScanningActions{Disabled: true},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("scan() error = %v", err)
+	}
+
+	var got []string
+	for _, pkg := range inv.Packages {
+		got = append(got, fmt.Sprintf("%s@%s %v", pkg.Name, pkg.Version, pkg.Locations))
+	}
+	want := []string{
+		"lodash@4.17.20 [<purl>]",
+		"org.apache.logging.log4j:log4j-core@2.14.1 [<purl>]",
+		"flask@2.0.0 [<stdin>]",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("scan() mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = scan(ExternalAccessors{}, ScannerActions{
+		PURLs: []string{"pkg:npm/lodash"},
+	}, nil)
+	if err == nil {
+		t.Errorf("scan() with a package URL without a version did not error")
+	}
+}
@@ -0,0 +1,703 @@
+package osvscanner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	binproto "github.com/google/osv-scalibr/binary/proto"
+	spb "github.com/google/osv-scalibr/binary/proto/scan_result_go_proto"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	scalibrosv "github.com/google/osv-scalibr/extractor/filesystem/osv"
+	scalibrfs "github.com/google/osv-scalibr/fs"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scanner/v2/internal/clients/clientinterfaces"
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/google/osv-scanner/v2/internal/scalibrextract/language/osv/osvscannerjson"
+	"github.com/google/osv-scanner/v2/internal/version"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// scanCache keeps the packages found in each file, after enrichment, and the
+// vulnerabilities affecting them on disk, keyed by the hash of the file's
+// content. Files that have not changed since an earlier scan are then not
+// extracted, resolved or matched again.
+type scanCache struct {
+	dir string
+	ttl time.Duration
+	// fingerprint is hashed into every key, so that scans that would find
+	// different packages or vulnerabilities do not share entries
+	fingerprint []byte
+
+	mu sync.Mutex
+	// hits are the entries found for each file, by path
+	hits map[string][]*scanCacheEntry
+	// misses are the keys of the files that were extracted, by path
+	misses map[string][]scanCacheMiss
+	// cachedCounts are the number of packages loaded for each file, by the
+	// path the extraction is reported with
+	cachedCounts map[string]int
+	// pending are the packages of the files that were extracted, by key
+	pending map[string]pendingScanCacheEntry
+	// vulns are the vulnerabilities of the packages loaded from the cache, and
+	// those matched for the packages of the files that were extracted
+	vulns map[*extractor.Package][]*osvschema.Vulnerability
+}
+
+type scanCacheMiss struct {
+	key   string
+	files map[string]string
+}
+
+type pendingScanCacheEntry struct {
+	path     string
+	files    map[string]string
+	packages []*extractor.Package
+}
+
+// scanCacheEntry is what is stored for a file.
+type scanCacheEntry struct {
+	Created time.Time `json:"created"`
+	// Files are the other files read when extracting, relative to the
+	// directory of the file, with the hashes of their content. Directories
+	// have a trailing slash, and the hash of their listing.
+	Files    map[string]string   `json:"files,omitempty"`
+	Packages []scanCachedPackage `json:"packages"`
+}
+
+// scanCachedPackage is an extractor.Package, with the metadata that osv-scanner
+// uses, and its vulnerabilities.
+type scanCachedPackage struct {
+	Name       string                          `json:"name"`
+	Version    string                          `json:"version"`
+	SourceCode *extractor.SourceCodeIdentifier `json:"source_code,omitempty"`
+	// Locations within the directory of the file are relative to it
+	Locations    []string        `json:"locations"`
+	PURLType     string          `json:"purl_type"`
+	Plugins      []string        `json:"plugins"`
+	Licenses     []string        `json:"licenses,omitempty"`
+	Deprecated   bool            `json:"deprecated,omitempty"`
+	MetadataType string          `json:"metadata_type,omitempty"`
+	Metadata     json.RawMessage `json:"metadata,omitempty"`
+	// Matched is false if the package was not matched against vulnerabilities,
+	// such as because it was filtered out, so that it is matched if it is not
+	// filtered out when loaded
+	Matched         bool              `json:"matched"`
+	Vulnerabilities []json.RawMessage `json:"vulnerabilities,omitempty"`
+}
+
+// The types of metadata that can be cached on top of those scalibr can store
+// as protos.
+const (
+	scanCacheProtoMetadata          = "proto"
+	scanCacheDepsDevMetadata        = "depsdev"
+	scanCacheOSVScannerJSONMetadata = "osvscannerjson"
+)
+
+// errNotCacheable is returned when the packages of a file cannot be cached.
+var errNotCacheable = errors.New("package cannot be cached")
+
+// scanCacheFingerprint holds the actions that change which packages are found
+// or which vulnerabilities affect them.
+type scanCacheFingerprint struct {
+	Version                string
+	PluginsEnabled         []string
+	PluginsDisabled        []string
+	PluginsNoDefaults      bool
+	TransitiveScanning     TransitiveScanningActions
+	CallAnalysisStates     map[string]bool
+	FlagDeprecatedPackages bool
+	CompareOffline         bool
+	LocalDBPath            string
+	OSVBaseURL             string
+	OSVHeaders             map[string]string
+}
+
+func newScanCache(actions ScannerActions) (*scanCache, error) {
+	transitive := actions.TransitiveScanning
+	transitive.DepsDevMetrics = nil
+
+	fingerprint, err := json.Marshal(scanCacheFingerprint{
+		Version:                version.OSVVersion,
+		PluginsEnabled:         actions.PluginsEnabled,
+		PluginsDisabled:        actions.PluginsDisabled,
+		PluginsNoDefaults:      actions.PluginsNoDefaults,
+		TransitiveScanning:     transitive,
+		CallAnalysisStates:     actions.CallAnalysisStates,
+		FlagDeprecatedPackages: actions.FlagDeprecatedPackages,
+		CompareOffline:         actions.CompareOffline,
+		LocalDBPath:            actions.LocalDBPath,
+		OSVBaseURL:             actions.OSVBaseURL,
+		OSVHeaders:             actions.OSVHeaders,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint scan for the cache: %w", err)
+	}
+
+	if err := os.MkdirAll(actions.ScanCacheDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create scan cache directory: %w", err)
+	}
+
+	return &scanCache{
+		dir:          actions.ScanCacheDir,
+		ttl:          actions.ScanCacheTTL,
+		fingerprint:  fingerprint,
+		hits:         map[string][]*scanCacheEntry{},
+		misses:       map[string][]scanCacheMiss{},
+		cachedCounts: map[string]int{},
+		pending:      map[string]pendingScanCacheEntry{},
+		vulns:        map[*extractor.Package][]*osvschema.Vulnerability{},
+	}, nil
+}
+
+// key returns the key of a file with the given content, as extracted by ex.
+func (c *scanCache) key(ex filesystem.Extractor, content []byte) string {
+	h := sha256.New()
+	h.Write(c.fingerprint)
+	fmt.Fprintf(h, "\x00%s\x00%d\x00", ex.Name(), ex.Version())
+	h.Write(content)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *scanCache) entryPath(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// load returns the entry stored under key, or nil if there is none that is
+// still fresh.
+func (c *scanCache) load(key string) *scanCacheEntry {
+	data, err := os.ReadFile(c.entryPath(key))
+	if err != nil {
+		return nil
+	}
+
+	var entry scanCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		cmdlogger.Warnf("Ignoring invalid scan cache entry %s: %v", c.entryPath(key), err)
+		return nil
+	}
+
+	if c.ttl > 0 && time.Since(entry.Created) > c.ttl {
+		return nil
+	}
+
+	return &entry
+}
+
+// store writes the entry under key.
+func (c *scanCache) store(key string, entry *scanCacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so that concurrent scans never read
+	// a partially written entry
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.entryPath(key))
+}
+
+// withScanCache wraps every filesystem extractor in plugins so that files
+// found in the cache are not extracted. Packages are reported at paths joined
+// onto prefix, which is only needed for roots whose paths are relative.
+func withScanCache(plugins []plugin.Plugin, cache *scanCache, prefix string) []plugin.Plugin {
+	if cache == nil {
+		return plugins
+	}
+
+	wrapped := make([]plugin.Plugin, 0, len(plugins))
+	for _, p := range plugins {
+		if ex, ok := p.(filesystem.Extractor); ok {
+			p = cache.extractor(ex, prefix)
+		}
+		wrapped = append(wrapped, p)
+	}
+
+	return wrapped
+}
+
+// extractor wraps ex so that files found in the cache are not extracted.
+func (c *scanCache) extractor(ex filesystem.Extractor, prefix string) filesystem.Extractor {
+	if c == nil {
+		return ex
+	}
+
+	return &cachedExtractor{Extractor: ex, cache: c, prefix: prefix}
+}
+
+// cachedExtractor skips extracting the files found in a scanCache, whose
+// packages are added to the inventory by scanCache.collect once the scan
+// is done, as they have already been enriched.
+type cachedExtractor struct {
+	filesystem.Extractor
+
+	cache  *scanCache
+	prefix string
+}
+
+func (e *cachedExtractor) Extract(ctx context.Context, input *filesystem.ScanInput) (inventory.Inventory, error) {
+	// directories have no content to key them by
+	if input.Reader == nil {
+		return e.Extractor.Extract(ctx, input)
+	}
+
+	content, err := io.ReadAll(input.Reader)
+	if err != nil {
+		return inventory.Inventory{}, fmt.Errorf("failed to read %s: %w", input.Path, err)
+	}
+
+	key := e.cache.key(e.Extractor, content)
+	filePath := filepath.Join(e.prefix, input.Root, filepath.FromSlash(input.Path))
+
+	if entry := e.cache.load(key); entry != nil && filesUnchanged(input.FS, path.Dir(input.Path), entry.Files) {
+		e.cache.mu.Lock()
+		e.cache.hits[filePath] = append(e.cache.hits[filePath], entry)
+		e.cache.cachedCounts[filepath.Join(input.Root, filepath.FromSlash(input.Path))] += len(entry.Packages)
+		e.cache.mu.Unlock()
+
+		return inventory.Inventory{}, nil
+	}
+
+	tracked := &trackingFS{FS: input.FS, opened: map[string]bool{}}
+	inv, err := e.Extractor.Extract(ctx, &filesystem.ScanInput{
+		FS:     tracked,
+		Path:   input.Path,
+		Root:   input.Root,
+		Info:   input.Info,
+		Reader: bytes.NewReader(content),
+	})
+	if err != nil {
+		return inv, err
+	}
+
+	files, err := hashFiles(input.FS, path.Dir(input.Path), input.Path, tracked.opened)
+	if err != nil {
+		// the files might have changed, so this is just not cached
+		return inv, nil
+	}
+
+	e.cache.mu.Lock()
+	e.cache.misses[filePath] = append(e.cache.misses[filePath], scanCacheMiss{key: key, files: files})
+	e.cache.mu.Unlock()
+
+	return inv, nil
+}
+
+// trackingFS records the files and directories opened, listed or stat-ed
+// through it, other than the root.
+type trackingFS struct {
+	scalibrfs.FS
+
+	mu     sync.Mutex
+	opened map[string]bool
+}
+
+func (t *trackingFS) track(name string, isDir bool) {
+	if name == "." {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if isDir {
+		name += "/"
+	}
+	t.opened[name] = true
+}
+
+func (t *trackingFS) Open(name string) (fs.File, error) {
+	f, err := t.FS.Open(name)
+	if err == nil {
+		info, statErr := f.Stat()
+		t.track(name, statErr == nil && info.IsDir())
+	}
+
+	return f, err
+}
+
+func (t *trackingFS) Stat(name string) (fs.FileInfo, error) {
+	info, err := t.FS.Stat(name)
+	if err == nil {
+		t.track(name, info.IsDir())
+	}
+
+	return info, err
+}
+
+func (t *trackingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := t.FS.ReadDir(name)
+	if err == nil {
+		t.track(name, true)
+	}
+
+	return entries, err
+}
+
+// hashFiles hashes the opened files other than the extracted one, keyed by
+// their paths relative to dir.
+func hashFiles(fsys scalibrfs.FS, dir, extracted string, opened map[string]bool) (map[string]string, error) {
+	files := map[string]string{}
+	for name := range opened {
+		if name == extracted {
+			continue
+		}
+
+		rel, err := filepath.Rel(filepath.FromSlash(dir), filepath.FromSlash(strings.TrimSuffix(name, "/")))
+		if err != nil {
+			return nil, err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasSuffix(name, "/") {
+			rel += "/"
+		}
+
+		hash, err := hashFile(fsys, path.Join(dir, rel))
+		if err != nil {
+			return nil, err
+		}
+		files[rel] = hash
+	}
+
+	return files, nil
+}
+
+// filesUnchanged is whether the files read when extracting a file in dir
+// still have the content they had then.
+func filesUnchanged(fsys scalibrfs.FS, dir string, files map[string]string) bool {
+	for rel, want := range files {
+		got, err := hashFile(fsys, path.Join(dir, rel))
+		if err != nil || got != want {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hashFile hashes the content of a file, or the listing of a directory if the
+// name has a trailing slash.
+func hashFile(fsys scalibrfs.FS, name string) (string, error) {
+	h := sha256.New()
+	if strings.HasSuffix(name, "/") {
+		entries, err := fsys.ReadDir(path.Clean(name))
+		if err != nil {
+			return "", err
+		}
+		for _, entry := range entries {
+			fmt.Fprintln(h, entry.Name())
+		}
+	} else {
+		f, err := fsys.Open(name)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cachedCount returns the number of packages loaded from the cache for a file,
+// by the path its extraction is reported with.
+func (c *scanCache) cachedCount(systemPath string) (int, bool) {
+	if c == nil {
+		return 0, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n, ok := c.cachedCounts[systemPath]
+
+	return n, ok
+}
+
+// collect records the packages found in the files that were extracted so that
+// they can be stored, and returns the packages of the files found in the
+// cache, located at the paths they were found at in this scan.
+func (c *scanCache) collect(packages []*extractor.Package) []*extractor.Package {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	byPath := map[string][]*extractor.Package{}
+	for _, pkg := range packages {
+		if len(pkg.Locations) > 0 {
+			byPath[pkg.Locations[0]] = append(byPath[pkg.Locations[0]], pkg)
+		}
+	}
+
+	for filePath, misses := range c.misses {
+		for _, miss := range misses {
+			c.pending[miss.key] = pendingScanCacheEntry{path: filePath, files: miss.files, packages: byPath[filePath]}
+		}
+	}
+
+	var loaded []*extractor.Package
+	for filePath, entries := range c.hits {
+		cmdlogger.Debugf("Using cached packages of %s", filePath)
+		for _, entry := range entries {
+			for _, cached := range entry.Packages {
+				pkg, vulns, err := cached.toPackage(filepath.Dir(filePath))
+				if err != nil {
+					cmdlogger.Warnf("Failed to load cached package %s of %s: %v", cached.Name, filePath, err)
+					continue
+				}
+				if cached.Matched {
+					c.vulns[pkg] = vulns
+				}
+				loaded = append(loaded, pkg)
+			}
+		}
+	}
+
+	c.misses = map[string][]scanCacheMiss{}
+	c.hits = map[string][]*scanCacheEntry{}
+
+	return loaded
+}
+
+// save stores the packages of the files that were extracted, along with the
+// vulnerabilities matched for them.
+func (c *scanCache) save() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, pending := range c.pending {
+		entry := &scanCacheEntry{Created: time.Now(), Files: pending.files}
+		cacheable := true
+		for _, pkg := range pending.packages {
+			vulns, matched := c.vulns[pkg]
+			cached, err := newScanCachedPackage(pkg, filepath.Dir(pending.path), matched, vulns)
+			if err != nil {
+				cmdlogger.Debugf("Not caching the packages of %s: %v", pending.path, err)
+				cacheable = false
+
+				break
+			}
+			entry.Packages = append(entry.Packages, cached)
+		}
+
+		if !cacheable {
+			continue
+		}
+
+		if err := c.store(key, entry); err != nil {
+			cmdlogger.Warnf("Failed to cache the packages of %s: %v", pending.path, err)
+		}
+	}
+
+	c.pending = map[string]pendingScanCacheEntry{}
+}
+
+func newScanCachedPackage(pkg *extractor.Package, dir string, matched bool, vulns []*osvschema.Vulnerability) (scanCachedPackage, error) {
+	if len(pkg.ExploitabilitySignals) > 0 || pkg.LayerMetadata != nil {
+		return scanCachedPackage{}, errNotCacheable
+	}
+
+	cached := scanCachedPackage{
+		Name:       pkg.Name,
+		Version:    pkg.Version,
+		SourceCode: pkg.SourceCode,
+		PURLType:   pkg.PURLType,
+		Plugins:    pkg.Plugins,
+		Licenses:   pkg.Licenses,
+		Deprecated: pkg.Deprecated,
+		Matched:    matched,
+	}
+
+	for _, loc := range pkg.Locations {
+		if rel, err := filepath.Rel(dir, loc); err == nil && filepath.IsLocal(rel) {
+			loc = filepath.ToSlash(rel)
+		}
+		cached.Locations = append(cached.Locations, loc)
+	}
+
+	var err error
+	switch md := pkg.Metadata.(type) {
+	case nil:
+	case *depsdev.Metadata:
+		cached.MetadataType = scanCacheDepsDevMetadata
+		cached.Metadata, err = json.Marshal(md)
+	case *osvscannerjson.Metadata:
+		cached.MetadataType = scanCacheOSVScannerJSONMetadata
+		cached.Metadata, err = json.Marshal(md)
+	case binproto.MetadataProtoSetter:
+		p := &spb.Package{}
+		md.SetProto(p)
+		cached.MetadataType = scanCacheProtoMetadata
+		cached.Metadata, err = protojson.Marshal(p)
+	default:
+		return scanCachedPackage{}, fmt.Errorf("%w: metadata of type %T", errNotCacheable, md)
+	}
+	if err != nil {
+		return scanCachedPackage{}, err
+	}
+
+	for _, vuln := range vulns {
+		data, err := protojson.Marshal(vuln)
+		if err != nil {
+			return scanCachedPackage{}, err
+		}
+		cached.Vulnerabilities = append(cached.Vulnerabilities, data)
+	}
+
+	return cached, nil
+}
+
+// toPackage returns the cached package, with relative locations placed in dir,
+// and its vulnerabilities.
+func (cached scanCachedPackage) toPackage(dir string) (*extractor.Package, []*osvschema.Vulnerability, error) {
+	pkg := &extractor.Package{
+		Name:       cached.Name,
+		Version:    cached.Version,
+		SourceCode: cached.SourceCode,
+		PURLType:   cached.PURLType,
+		Plugins:    cached.Plugins,
+		Licenses:   cached.Licenses,
+		Deprecated: cached.Deprecated,
+	}
+
+	for _, loc := range cached.Locations {
+		if !filepath.IsAbs(loc) {
+			loc = filepath.Join(dir, filepath.FromSlash(loc))
+		}
+		pkg.Locations = append(pkg.Locations, loc)
+	}
+
+	switch cached.MetadataType {
+	case "":
+	case scanCacheDepsDevMetadata:
+		md := &depsdev.Metadata{}
+		if err := json.Unmarshal(cached.Metadata, md); err != nil {
+			return nil, nil, err
+		}
+		pkg.Metadata = md
+	case scanCacheOSVScannerJSONMetadata:
+		md := &osvscannerjson.Metadata{}
+		if err := json.Unmarshal(cached.Metadata, md); err != nil {
+			return nil, nil, err
+		}
+		pkg.Metadata = md
+	case scanCacheProtoMetadata:
+		p := &spb.Package{}
+		if err := protojson.Unmarshal(cached.Metadata, p); err != nil {
+			return nil, nil, err
+		}
+		withMetadata, err := binproto.PackageToStruct(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		pkg.Metadata = withMetadata.Metadata
+		// extractors report dependency groups as values, rather than pointers
+		if md, ok := pkg.Metadata.(*scalibrosv.DepGroupMetadata); ok {
+			pkg.Metadata = *md
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown metadata type %q", cached.MetadataType)
+	}
+
+	vulns := make([]*osvschema.Vulnerability, 0, len(cached.Vulnerabilities))
+	for _, data := range cached.Vulnerabilities {
+		vuln := &osvschema.Vulnerability{}
+		if err := protojson.Unmarshal(data, vuln); err != nil {
+			return nil, nil, err
+		}
+		vulns = append(vulns, vuln)
+	}
+
+	return pkg, vulns, nil
+}
+
+// vulnMatcher wraps matcher so that the vulnerabilities of packages loaded
+// from the cache are not matched again, and those of other packages are
+// recorded to be cached.
+func (c *scanCache) vulnMatcher(matcher clientinterfaces.VulnerabilityMatcher) clientinterfaces.VulnerabilityMatcher {
+	if c == nil || matcher == nil {
+		return matcher
+	}
+
+	return &cachedVulnMatcher{matcher: matcher, cache: c}
+}
+
+type cachedVulnMatcher struct {
+	matcher clientinterfaces.VulnerabilityMatcher
+	cache   *scanCache
+}
+
+func (m *cachedVulnMatcher) MatchVulnerabilities(ctx context.Context, invs []*extractor.Package) ([][]*osvschema.Vulnerability, error) {
+	results := make([][]*osvschema.Vulnerability, len(invs))
+
+	var toMatch []*extractor.Package
+	var indexes []int
+	m.cache.mu.Lock()
+	for i, inv := range invs {
+		if vulns, ok := m.cache.vulns[inv]; ok {
+			results[i] = vulns
+			continue
+		}
+		toMatch = append(toMatch, inv)
+		indexes = append(indexes, i)
+	}
+	m.cache.mu.Unlock()
+
+	if len(toMatch) == 0 {
+		return results, nil
+	}
+
+	matched, err := m.matcher.MatchVulnerabilities(ctx, toMatch)
+	if matched == nil {
+		return nil, err
+	}
+
+	m.cache.mu.Lock()
+	defer m.cache.mu.Unlock()
+	for j, vulns := range matched {
+		results[indexes[j]] = vulns
+		// partial results are not cached
+		if err == nil {
+			m.cache.vulns[toMatch[j]] = vulns
+		}
+	}
+
+	return results, err
+}
@@ -0,0 +1,193 @@
+package osvscanner
+
+import (
+	"bufio"
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/extractor/filesystem"
+	scalibrosv "github.com/google/osv-scalibr/extractor/filesystem/osv"
+	scalibrfs "github.com/google/osv-scalibr/fs"
+	"github.com/google/osv-scalibr/inventory"
+	"github.com/google/osv-scalibr/plugin"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// lineExtractor reports a package for each "name@version" line of a file,
+// and one for the content of "extra.txt" next to it, if there is one.
+type lineExtractor struct {
+	extracted int
+}
+
+func (e *lineExtractor) Name() string                       { return "test/lines" }
+func (e *lineExtractor) Version() int                       { return 0 }
+func (e *lineExtractor) Requirements() *plugin.Capabilities { return &plugin.Capabilities{} }
+func (e *lineExtractor) FileRequired(_ filesystem.FileAPI) bool {
+	return true
+}
+
+func (e *lineExtractor) Extract(_ context.Context, input *filesystem.ScanInput) (inventory.Inventory, error) {
+	e.extracted++
+
+	var inv inventory.Inventory
+	add := func(line string, md any) {
+		name, version, _ := strings.Cut(line, "@")
+		inv.Packages = append(inv.Packages, &extractor.Package{
+			Name:      name,
+			Version:   version,
+			PURLType:  purl.TypeNPM,
+			Locations: []string{input.Path},
+			Metadata:  md,
+		})
+	}
+
+	scanner := bufio.NewScanner(input.Reader)
+	for scanner.Scan() {
+		add(scanner.Text(), scalibrosv.DepGroupMetadata{DepGroupVals: []string{"dev"}})
+	}
+
+	if extra, err := fs.ReadFile(input.FS, path.Join(path.Dir(input.Path), "extra.txt")); err == nil {
+		add(strings.TrimSpace(string(extra)), &depsdev.Metadata{IsTransitive: true, IntroducedBy: [][]string{{"a@1.0.0"}}})
+	}
+
+	return inv, scanner.Err()
+}
+
+// fakeVulnMatcher reports a vulnerability for every package.
+type fakeVulnMatcher struct {
+	matched int
+}
+
+func (m *fakeVulnMatcher) MatchVulnerabilities(_ context.Context, invs []*extractor.Package) ([][]*osvschema.Vulnerability, error) {
+	m.matched += len(invs)
+	results := make([][]*osvschema.Vulnerability, len(invs))
+	for i, inv := range invs {
+		results[i] = []*osvschema.Vulnerability{{Id: "GHSA-" + inv.Name}}
+	}
+
+	return results, nil
+}
+
+// scanWithCache extracts the file with ex through a new cache in dir, and
+// matches the packages found, as a scan would.
+func scanWithCache(t *testing.T, dir string, ttl time.Duration, root, file string, ex filesystem.Extractor, matcher *fakeVulnMatcher) ([]*extractor.Package, [][]*osvschema.Vulnerability) {
+	t.Helper()
+
+	cache, err := newScanCache(ScannerActions{
+		ExperimentalScannerActions: ExperimentalScannerActions{ScanCacheDir: dir, ScanCacheTTL: ttl},
+	})
+	if err != nil {
+		t.Fatalf("newScanCache() error = %v", err)
+	}
+
+	f, err := os.Open(filepath.Join(root, file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	inv, err := cache.extractor(ex, "").Extract(t.Context(), &filesystem.ScanInput{
+		FS:     scalibrfs.DirFS(root),
+		Path:   file,
+		Root:   root,
+		Reader: f,
+	})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	for _, pkg := range inv.Packages {
+		pkg.Locations = expandLocations(root, pkg.Locations)
+	}
+
+	packages := append(inv.Packages, cache.collect(inv.Packages)...)
+	vulns, err := cache.vulnMatcher(matcher).MatchVulnerabilities(t.Context(), packages)
+	if err != nil {
+		t.Fatalf("MatchVulnerabilities() error = %v", err)
+	}
+	cache.save()
+
+	return packages, vulns
+}
+
+func expandLocations(root string, locations []string) []string {
+	expanded := make([]string, 0, len(locations))
+	for _, loc := range locations {
+		expanded = append(expanded, filepath.Join(root, filepath.FromSlash(loc)))
+	}
+
+	return expanded
+}
+
+func Test_scanCache(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "a", "deps.txt"), "lodash@4.17.20\nexpress@4.17.1\n")
+	writeTestFile(t, filepath.Join(root, "a", "extra.txt"), "jest@29.0.0")
+	// the same content elsewhere is found in the cache
+	writeTestFile(t, filepath.Join(root, "b", "deps.txt"), "lodash@4.17.20\nexpress@4.17.1\n")
+	writeTestFile(t, filepath.Join(root, "b", "extra.txt"), "jest@29.0.0")
+
+	ex := &lineExtractor{}
+	matcher := &fakeVulnMatcher{}
+
+	wantPackages, wantVulns := scanWithCache(t, cacheDir, time.Hour, root, "a/deps.txt", ex, matcher)
+	if ex.extracted != 1 || matcher.matched != 3 {
+		t.Fatalf("first scan extracted %d times and matched %d packages, want 1 and 3", ex.extracted, matcher.matched)
+	}
+
+	gotPackages, gotVulns := scanWithCache(t, cacheDir, time.Hour, root, "b/deps.txt", ex, matcher)
+	if ex.extracted != 1 || matcher.matched != 3 {
+		t.Errorf("cached scan extracted %d times and matched %d packages, want 1 and 3", ex.extracted-1, matcher.matched-3)
+	}
+
+	for _, pkg := range wantPackages {
+		pkg.Locations = []string{filepath.Join(root, "b", "deps.txt")}
+	}
+	if diff := cmp.Diff(wantPackages, gotPackages); diff != "" {
+		t.Errorf("cached packages mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wantVulns, gotVulns, protocmp.Transform()); diff != "" {
+		t.Errorf("cached vulnerabilities mismatch (-want +got):\n%s", diff)
+	}
+
+	// changing a file that was read when extracting means extracting again
+	writeTestFile(t, filepath.Join(root, "b", "extra.txt"), "jest@29.1.0")
+	gotPackages, _ = scanWithCache(t, cacheDir, time.Hour, root, "b/deps.txt", ex, matcher)
+	if ex.extracted != 2 {
+		t.Errorf("scan after a referenced file changed did not extract again")
+	}
+	if got := gotPackages[len(gotPackages)-1].Version; got != "29.1.0" {
+		t.Errorf("scan after a referenced file changed found jest@%s, want 29.1.0", got)
+	}
+
+	// so does the entry being too old
+	time.Sleep(10 * time.Millisecond)
+	scanWithCache(t, cacheDir, time.Millisecond, root, "a/deps.txt", ex, matcher)
+	if ex.extracted != 3 {
+		t.Errorf("scan after the entry expired did not extract again")
+	}
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,193 @@
+package osvscanner
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/ghsa"
+	"github.com/google/osv-scanner/v2/internal/nvd"
+	"github.com/google/osv-scanner/v2/internal/output"
+	"github.com/google/osv-scanner/v2/internal/utility/severity"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// advisorySource looks up advisories in a vulnerability database other than
+// OSV, returning them as partial OSV records, or nil if it has no advisory
+// with the given ID.
+type advisorySource interface {
+	Advisory(ctx context.Context, id string) (*osvschema.Vulnerability, error)
+}
+
+// applySecondaryAdvisories fills in details missing from OSV records using
+// the GitHub Advisory Database and the NVD, when this was requested.
+func applySecondaryAdvisories(vulnResults *models.VulnerabilityResults, actions ScannerActions) {
+	if !actions.SecondaryAdvisories {
+		return
+	}
+	if actions.CompareOffline {
+		return
+	}
+
+	userAgent := "osv-scanner-api"
+	if actions.RequestUserAgent != "" {
+		userAgent = actions.RequestUserAgent
+	}
+	// GitHub is checked before the NVD, as its advisories are about packages
+	// rather than products, and it has no rate limit to wait on.
+	sources := []advisorySource{
+		ghsa.NewClient(actions.GHSABaseURL, actions.HTTPClient, userAgent, actions.GitHubToken),
+		nvd.NewClient(actions.NVDBaseURL, actions.HTTPClient, userAgent, actions.NVDAPIKey),
+	}
+	if err := enrichSecondaryAdvisories(context.Background(), vulnResults, sources); err != nil {
+		cmdlogger.Warnf("Failed to check secondary advisory databases: %v", err)
+	}
+}
+
+// enrichSecondaryAdvisories fills in the severity and description of the
+// vulnerabilities that are missing them, from the advisories for the aliases
+// of their group, checking the sources in order until nothing is missing.
+//
+// The details are merged into the existing OSV record, and the advisories
+// they came from are listed in the group, rather than being reported as
+// vulnerabilities of their own.
+func enrichSecondaryAdvisories(ctx context.Context, vulnResults *models.VulnerabilityResults, sources []advisorySource) error {
+	// Advisories are only looked up once even if they are aliases of
+	// vulnerabilities in many packages.
+	type cacheKey struct {
+		source int
+		id     string
+	}
+	cache := make(map[cacheKey]*osvschema.Vulnerability)
+	lookup := func(source int, id string) (*osvschema.Vulnerability, error) {
+		key := cacheKey{source, id}
+		if adv, ok := cache[key]; ok {
+			return adv, nil
+		}
+		adv, err := sources[source].Advisory(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		cache[key] = adv
+
+		return adv, nil
+	}
+
+	for i := range vulnResults.Results {
+		for j := range vulnResults.Results[i].Packages {
+			pkg := &vulnResults.Results[i].Packages[j]
+			for k := range pkg.Groups {
+				group := &pkg.Groups[k]
+				ids := slices.Clone(group.Aliases)
+				slices.SortFunc(ids, compareSecondaryIDs)
+
+				changed := false
+				for l, vuln := range pkg.Vulnerabilities {
+					if !slices.Contains(group.IDs, vuln.GetId()) || !missingDetails(vuln) {
+						continue
+					}
+
+					// The record may be shared with other packages, so it
+					// is only changed in this one.
+					vuln = proto.Clone(vuln).(*osvschema.Vulnerability)
+					merged := false
+				lookups:
+					for source := range sources {
+						for _, id := range ids {
+							if !missingDetails(vuln) {
+								break lookups
+							}
+							adv, err := lookup(source, id)
+							if err != nil {
+								return err
+							}
+							if adv != nil && mergeAdvisory(vuln, adv) {
+								group.SecondarySources = append(group.SecondarySources, advisoryURL(adv.GetId()))
+								merged = true
+							}
+						}
+					}
+					if merged {
+						pkg.Vulnerabilities[l] = vuln
+						changed = true
+					}
+				}
+				if changed {
+					slices.Sort(group.SecondarySources)
+					group.SecondarySources = slices.Compact(group.SecondarySources)
+					group.MaxSeverity = output.MaxSeverity(*group, *pkg)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// missingDetails reports whether a vulnerability has no CVSS score, or no
+// summary or details.
+func missingDetails(vuln *osvschema.Vulnerability) bool {
+	score, _ := severity.Normalize(vuln)
+
+	return score < 0 || (vuln.GetSummary() == "" && vuln.GetDetails() == "")
+}
+
+// mergeAdvisory fills in the severity and description of a vulnerability from
+// an advisory, leaving any it already has alone. It reports whether anything
+// was filled in.
+func mergeAdvisory(vuln *osvschema.Vulnerability, adv *osvschema.Vulnerability) bool {
+	merged := false
+
+	score, rating := severity.Normalize(vuln)
+	if advScore, advRating := severity.Normalize(adv); score < 0 && advScore >= 0 {
+		vuln.Severity = append(vuln.Severity, adv.GetSeverity()...)
+		merged = true
+	} else if rating == severity.UnknownRating && advRating != severity.UnknownRating {
+		if vuln.DatabaseSpecific == nil {
+			vuln.DatabaseSpecific = &structpb.Struct{Fields: map[string]*structpb.Value{}}
+		}
+		vuln.DatabaseSpecific.Fields["severity"] = structpb.NewStringValue(string(advRating))
+		merged = true
+	}
+
+	if vuln.GetSummary() == "" && vuln.GetDetails() == "" && (adv.GetSummary() != "" || adv.GetDetails() != "") {
+		vuln.Summary = adv.GetSummary()
+		vuln.Details = adv.GetDetails()
+		merged = true
+	}
+
+	return merged
+}
+
+// compareSecondaryIDs orders GHSA IDs before CVE IDs, which are the only ones
+// the secondary databases know of.
+func compareSecondaryIDs(a, b string) int {
+	rank := func(id string) int {
+		switch {
+		case strings.HasPrefix(id, "GHSA-"):
+			return 0
+		case strings.HasPrefix(id, "CVE-"):
+			return 1
+		default:
+			return 2
+		}
+	}
+	if c := rank(a) - rank(b); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a, b)
+}
+
+// advisoryURL links to the page of an advisory from a secondary database.
+func advisoryURL(id string) string {
+	if strings.HasPrefix(id, "GHSA-") {
+		return "https://github.com/advisories/" + id
+	}
+
+	return "https://nvd.nist.gov/vuln/detail/" + id
+}
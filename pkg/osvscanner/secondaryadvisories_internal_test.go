@@ -0,0 +1,113 @@
+package osvscanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// fakeAdvisorySource serves the advisories it knows of, counting lookups.
+type fakeAdvisorySource struct {
+	advisories map[string]*osvschema.Vulnerability
+	lookups    []string
+}
+
+func (s *fakeAdvisorySource) Advisory(_ context.Context, id string) (*osvschema.Vulnerability, error) {
+	s.lookups = append(s.lookups, id)
+
+	return s.advisories[id], nil
+}
+
+func Test_enrichSecondaryAdvisories(t *testing.T) {
+	t.Parallel()
+
+	github := &fakeAdvisorySource{advisories: map[string]*osvschema.Vulnerability{
+		// GitHub has no score for this one, so the NVD is checked too
+		"CVE-2024-0001": {Id: "GHSA-aaaa-bbbb-cccc", Summary: "Denial of service"},
+	}}
+	nvd := &fakeAdvisorySource{advisories: map[string]*osvschema.Vulnerability{
+		"CVE-2024-0001": {
+			Id:       "CVE-2024-0001",
+			Details:  "A long description",
+			Severity: []*osvschema.Severity{{Type: osvschema.Severity_CVSS_V3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H"}},
+		},
+		"CVE-2024-0002": {
+			Id:       "CVE-2024-0002",
+			Severity: []*osvschema.Severity{{Type: osvschema.Severity_CVSS_V3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}},
+		},
+	}}
+
+	unscored := &osvschema.Vulnerability{Id: "PYSEC-2024-1", Aliases: []string{"CVE-2024-0001"}}
+	scored := &osvschema.Vulnerability{
+		Id:       "GO-2024-2",
+		Summary:  "Already has everything",
+		Severity: []*osvschema.Severity{{Type: osvschema.Severity_CVSS_V3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:L/I:N/A:N"}},
+		Aliases:  []string{"CVE-2024-0002"},
+	}
+	vulnResults := models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source: models.SourceInfo{Path: "/path/to/requirements.txt", Type: models.SourceTypeProjectPackage},
+			Packages: []models.PackageVulns{
+				{
+					Package:         models.PackageInfo{Name: "example", Version: "1.0.0", Ecosystem: "PyPI"},
+					Vulnerabilities: []*osvschema.Vulnerability{unscored},
+					Groups: []models.GroupInfo{{
+						IDs:     []string{"PYSEC-2024-1"},
+						Aliases: []string{"CVE-2024-0001", "PYSEC-2024-1"},
+					}},
+				},
+				{
+					Package:         models.PackageInfo{Name: "example.com/module", Version: "1.0.0", Ecosystem: "Go"},
+					Vulnerabilities: []*osvschema.Vulnerability{scored},
+					Groups: []models.GroupInfo{{
+						IDs:         []string{"GO-2024-2"},
+						Aliases:     []string{"CVE-2024-0002", "GO-2024-2"},
+						MaxSeverity: "5.3",
+					}},
+				},
+			},
+		}},
+	}
+
+	if err := enrichSecondaryAdvisories(t.Context(), &vulnResults, []advisorySource{github, nvd}); err != nil {
+		t.Fatalf("enrichSecondaryAdvisories() error: %v", err)
+	}
+
+	wantVuln := &osvschema.Vulnerability{
+		Id:       "PYSEC-2024-1",
+		Summary:  "Denial of service",
+		Aliases:  []string{"CVE-2024-0001"},
+		Severity: []*osvschema.Severity{{Type: osvschema.Severity_CVSS_V3, Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H"}},
+	}
+	pkg := vulnResults.Results[0].Packages[0]
+	if diff := cmp.Diff(wantVuln, pkg.Vulnerabilities[0], protocmp.Transform()); diff != "" {
+		t.Errorf("enriched vulnerability mismatch (-want +got):\n%s", diff)
+	}
+	if unscored.GetSummary() != "" || len(unscored.GetSeverity()) != 0 {
+		t.Errorf("enrichSecondaryAdvisories() changed the original record: %v", unscored)
+	}
+	wantGroup := models.GroupInfo{
+		IDs:         []string{"PYSEC-2024-1"},
+		Aliases:     []string{"CVE-2024-0001", "PYSEC-2024-1"},
+		MaxSeverity: "7.5",
+		SecondarySources: []string{
+			"https://github.com/advisories/GHSA-aaaa-bbbb-cccc",
+			"https://nvd.nist.gov/vuln/detail/CVE-2024-0001",
+		},
+	}
+	if diff := cmp.Diff(wantGroup, pkg.Groups[0]); diff != "" {
+		t.Errorf("enriched group mismatch (-want +got):\n%s", diff)
+	}
+
+	// Vulnerabilities that are not missing anything are left alone
+	if vulnResults.Results[0].Packages[1].Vulnerabilities[0] != scored || vulnResults.Results[0].Packages[1].Groups[0].SecondarySources != nil {
+		t.Errorf("enrichSecondaryAdvisories() changed a vulnerability with a severity and summary")
+	}
+	if diff := cmp.Diff([]string{"CVE-2024-0001"}, nvd.lookups); diff != "" {
+		t.Errorf("NVD lookups mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -12,6 +12,8 @@ type fileOpenedPrinter struct {
 	stats.NoopCollector
 
 	filesExtracted map[string]struct{}
+	// cache, if any, is asked for the packages of the files it skipped extracting
+	cache *scanCache
 }
 
 var _ stats.Collector = &fileOpenedPrinter{}
@@ -28,11 +30,17 @@ func (c *fileOpenedPrinter) AfterExtractorRun(_ string, extractorstats *stats.Af
 	}
 
 	pkgsFound := len(extractorstats.Inventory.Packages)
+	cached := ""
+	if n, ok := c.cache.cachedCount(systemPath); ok {
+		pkgsFound = n
+		cached = " (cached)"
+	}
 
 	cmdlogger.Infof(
-		"Scanned %s file and found %d %s",
+		"Scanned %s file and found %d %s%s",
 		systemPath,
 		pkgsFound,
 		output.Form(pkgsFound, "package", "packages"),
+		cached,
 	)
 }
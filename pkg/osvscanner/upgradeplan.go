@@ -0,0 +1,68 @@
+package osvscanner
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/imodels"
+	"github.com/google/osv-scanner/v2/internal/remediation/minimalupgrade"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// planUpgrades computes the smallest set of version upgrades that fixes the
+// most of the reported vulnerabilities, leaving out those that are uncalled
+// or unimportant unless all vulnerabilities are being shown.
+func planUpgrades(vulnResults models.VulnerabilityResults, showAllVulns bool) []models.Upgrade {
+	var findings []minimalupgrade.Finding
+	for _, source := range vulnResults.Results {
+		for _, pkg := range source.Packages {
+			if pkg.Package.Inventory == nil {
+				continue
+			}
+
+			var reported []*osvschema.Vulnerability
+			for _, group := range pkg.Groups {
+				if !showAllVulns && (!group.IsCalled() || group.IsGroupUnimportant()) {
+					continue
+				}
+				for _, v := range pkg.Vulnerabilities {
+					if slices.Contains(group.IDs, v.GetId()) {
+						reported = append(reported, v)
+					}
+				}
+			}
+
+			findings = append(findings, minimalupgrade.Finding{
+				Package: imodels.FromInventory(pkg.Package.Inventory),
+				Vulns:   reported,
+			})
+		}
+	}
+
+	plan, err := minimalupgrade.Compute(context.Background(), findings, minimalupgrade.Options{})
+	if err != nil {
+		cmdlogger.Warnf("Failed to compute the minimal upgrades: %v", err)
+		return nil
+	}
+
+	upgrades := make([]models.Upgrade, 0, len(plan.Upgrades))
+	for _, u := range plan.Upgrades {
+		upgrade := models.Upgrade{
+			Ecosystem: u.Package.Ecosystem().String(),
+			Name:      u.Package.Name(),
+			From:      u.Package.Version(),
+			To:        u.Version,
+		}
+		for _, v := range u.Fixed {
+			upgrade.Fixed = append(upgrade.Fixed, v.GetId())
+		}
+		for _, v := range u.Remaining {
+			upgrade.Remaining = append(upgrade.Remaining, v.GetId())
+		}
+		upgrades = append(upgrades, upgrade)
+	}
+
+	return upgrades
+}
@@ -0,0 +1,97 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scalibr/extractor"
+	"github.com/google/osv-scalibr/purl"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+func Test_planUpgrades(t *testing.T) {
+	t.Parallel()
+
+	vuln := func(id, name, fixed string) *osvschema.Vulnerability {
+		return &osvschema.Vulnerability{
+			Id: id,
+			Affected: []*osvschema.Affected{{
+				Package: &osvschema.Package{Ecosystem: "npm", Name: name},
+				Ranges: []*osvschema.Range{{
+					Type:   osvschema.Range_SEMVER,
+					Events: []*osvschema.Event{{Introduced: "0"}, {Fixed: fixed}},
+				}},
+			}},
+		}
+	}
+	pkg := func(name, version string) models.PackageInfo {
+		return models.PackageInfo{
+			Name:      name,
+			Version:   version,
+			Ecosystem: "npm",
+			Inventory: &extractor.Package{Name: name, Version: version, PURLType: purl.TypeNPM},
+		}
+	}
+
+	vulnResults := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Packages: []models.PackageVulns{
+					{
+						Package:         pkg("lodash", "4.17.15"),
+						Vulnerabilities: []*osvschema.Vulnerability{vuln("VULN-1", "lodash", "4.17.19"), vuln("VULN-2", "lodash", "4.17.21")},
+						Groups:          []models.GroupInfo{{IDs: []string{"VULN-1"}}, {IDs: []string{"VULN-2"}}},
+					},
+					{
+						Package:         pkg("minimist", "1.2.0"),
+						Vulnerabilities: []*osvschema.Vulnerability{vuln("VULN-3", "minimist", "1.2.6")},
+						Groups:          []models.GroupInfo{{IDs: []string{"VULN-3"}, ExperimentalAnalysis: map[string]models.AnalysisInfo{"VULN-3": {Called: false}}}},
+					},
+				},
+			},
+			{
+				Packages: []models.PackageVulns{
+					// the same package in another lockfile is upgraded together
+					{
+						Package:         pkg("lodash", "4.17.15"),
+						Vulnerabilities: []*osvschema.Vulnerability{vuln("VULN-1", "lodash", "4.17.19")},
+						Groups:          []models.GroupInfo{{IDs: []string{"VULN-1"}}},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		showAllVulns bool
+		want         []models.Upgrade
+	}{
+		{
+			name: "reported_vulns",
+			want: []models.Upgrade{
+				{Ecosystem: "npm", Name: "lodash", From: "4.17.15", To: "4.17.21", Fixed: []string{"VULN-1", "VULN-2"}},
+			},
+		},
+		{
+			name:         "all_vulns",
+			showAllVulns: true,
+			want: []models.Upgrade{
+				{Ecosystem: "npm", Name: "lodash", From: "4.17.15", To: "4.17.21", Fixed: []string{"VULN-1", "VULN-2"}},
+				{Ecosystem: "npm", Name: "minimist", From: "1.2.0", To: "1.2.6", Fixed: []string{"VULN-3"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := planUpgrades(vulnResults, tt.showAllVulns)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("planUpgrades() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package osvscanner
+
+import (
+	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/vexdoc"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+)
+
+// filterNotAffected removes the vulnerabilities that VEX documents state do
+// not affect the packages they were found in, preserving order, and records
+// them as ignored along with the justification. Returns the number of
+// vulnerabilities removed.
+func filterNotAffected(vulnResults *models.VulnerabilityResults, statements []vexdoc.Statement, allPackages bool) int {
+	if len(statements) == 0 {
+		return 0
+	}
+
+	removedCount := 0
+	newResults := []models.PackageSource{}
+	for _, pkgSrc := range vulnResults.Results {
+		var newPackages []models.PackageVulns
+		for _, pkgVulns := range pkgSrc.Packages {
+			newVulns, ignored := filterNotAffectedPackageVulns(pkgVulns, statements)
+			for _, ig := range ignored {
+				ig.Source = pkgSrc.Source
+				vulnResults.ExperimentalIgnored = append(vulnResults.ExperimentalIgnored, ig)
+			}
+			removedCount += len(pkgVulns.Vulnerabilities) - len(newVulns.Vulnerabilities)
+			if allPackages || hasFindings(newVulns) {
+				newPackages = append(newPackages, newVulns)
+			}
+		}
+		if len(newPackages) > 0 {
+			pkgSrc.Packages = newPackages
+			newResults = append(newResults, pkgSrc)
+		}
+	}
+	vulnResults.Results = newResults
+
+	return removedCount
+}
+
+func filterNotAffectedPackageVulns(pkgVulns models.PackageVulns, statements []vexdoc.Statement) (models.PackageVulns, []models.IgnoredVulnerability) {
+	notAffected := map[string]struct{}{}
+	var ignoredGroups []models.IgnoredVulnerability
+
+	var newGroups []models.GroupInfo
+	for _, group := range pkgVulns.Groups {
+		i := -1
+		for j, statement := range statements {
+			if statement.Matches(pkgVulns.Package, group.Aliases) {
+				i = j
+				break
+			}
+		}
+		if i == -1 {
+			newGroups = append(newGroups, group)
+			continue
+		}
+
+		statement := statements[i]
+		for _, id := range group.Aliases {
+			notAffected[id] = struct{}{}
+		}
+		cmdlogger.Infof(
+			"%s in %s has been filtered out because %s states it is not affected: %s",
+			group.IDs[0], pkgVulns.Package.Name, statement.Source, statement.Reason(),
+		)
+		ignoredGroups = append(ignoredGroups, models.IgnoredVulnerability{
+			Package:       pkgVulns.Package,
+			Group:         group,
+			Reason:        statement.ImpactStatement,
+			Justification: statement.Justification,
+			VEXSource:     statement.Source,
+		})
+	}
+
+	var newVulns []*osvschema.Vulnerability
+	if len(newGroups) > 0 {
+		for _, vuln := range pkgVulns.Vulnerabilities {
+			if _, filtered := notAffected[vuln.GetId()]; !filtered {
+				newVulns = append(newVulns, vuln)
+			}
+		}
+	}
+
+	pkgVulns.Groups = newGroups
+	pkgVulns.Vulnerabilities = newVulns
+
+	return pkgVulns, ignoredGroups
+}
@@ -0,0 +1,111 @@
+package osvscanner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/v2/internal/vexdoc"
+	"github.com/google/osv-scanner/v2/pkg/models"
+	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func Test_filterNotAffected(t *testing.T) {
+	t.Parallel()
+
+	source := models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage}
+	lodash := models.PackageInfo{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"}
+	minimist := models.PackageInfo{Name: "minimist", Version: "1.2.5", Ecosystem: "npm"}
+
+	vulnResults := models.VulnerabilityResults{
+		Results: []models.PackageSource{
+			{
+				Source: source,
+				Packages: []models.PackageVulns{
+					{
+						Package: lodash,
+						Vulnerabilities: []*osvschema.Vulnerability{
+							{Id: "GHSA-35jh-r3h4-6jhm", Aliases: []string{"CVE-2021-23337"}},
+							{Id: "GHSA-p6mc-m468-83gw"},
+						},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-35jh-r3h4-6jhm"}, Aliases: []string{"CVE-2021-23337", "GHSA-35jh-r3h4-6jhm"}},
+							{IDs: []string{"GHSA-p6mc-m468-83gw"}, Aliases: []string{"GHSA-p6mc-m468-83gw"}},
+						},
+					},
+					{
+						Package: minimist,
+						Vulnerabilities: []*osvschema.Vulnerability{
+							{Id: "GHSA-xvch-5gv4-984h"},
+						},
+						Groups: []models.GroupInfo{
+							{IDs: []string{"GHSA-xvch-5gv4-984h"}, Aliases: []string{"GHSA-xvch-5gv4-984h"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	statements := []vexdoc.Statement{
+		{
+			Source:          "vex.json",
+			IDs:             []string{"CVE-2021-23337"},
+			Products:        []string{"pkg:npm/lodash@4.17.20"},
+			Justification:   "vulnerable_code_not_in_execute_path",
+			ImpactStatement: "template() is never called",
+		},
+		{
+			Source:        "vex.json",
+			IDs:           []string{"GHSA-xvch-5gv4-984h"},
+			Products:      []string{"pkg:npm/minimist"},
+			Justification: "vulnerable_code_cannot_be_controlled_by_adversary",
+		},
+	}
+
+	if removed := filterNotAffected(&vulnResults, statements, false); removed != 2 {
+		t.Errorf("filterNotAffected() = %d, want 2", removed)
+	}
+
+	// minimist has nothing left to report, so it is left out
+	want := []models.PackageSource{
+		{
+			Source: source,
+			Packages: []models.PackageVulns{
+				{
+					Package: lodash,
+					Vulnerabilities: []*osvschema.Vulnerability{
+						{Id: "GHSA-p6mc-m468-83gw"},
+					},
+					Groups: []models.GroupInfo{
+						{IDs: []string{"GHSA-p6mc-m468-83gw"}, Aliases: []string{"GHSA-p6mc-m468-83gw"}},
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, vulnResults.Results, protocmp.Transform()); diff != "" {
+		t.Errorf("filterNotAffected() results mismatch (-want +got):\n%s", diff)
+	}
+
+	wantIgnored := []models.IgnoredVulnerability{
+		{
+			Source:        source,
+			Package:       lodash,
+			Group:         models.GroupInfo{IDs: []string{"GHSA-35jh-r3h4-6jhm"}, Aliases: []string{"CVE-2021-23337", "GHSA-35jh-r3h4-6jhm"}},
+			Reason:        "template() is never called",
+			Justification: "vulnerable_code_not_in_execute_path",
+			VEXSource:     "vex.json",
+		},
+		{
+			Source:        source,
+			Package:       minimist,
+			Group:         models.GroupInfo{IDs: []string{"GHSA-xvch-5gv4-984h"}, Aliases: []string{"GHSA-xvch-5gv4-984h"}},
+			Justification: "vulnerable_code_cannot_be_controlled_by_adversary",
+			VEXSource:     "vex.json",
+		},
+	}
+	if diff := cmp.Diff(wantIgnored, vulnResults.ExperimentalIgnored); diff != "" {
+		t.Errorf("filterNotAffected() ignored mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -10,11 +10,13 @@ import (
 	cdxmeta "github.com/google/osv-scalibr/extractor/filesystem/sbom/cdx/metadata"
 	"github.com/google/osv-scalibr/inventory/vex"
 	"github.com/google/osv-scanner/v2/internal/cmdlogger"
+	"github.com/google/osv-scanner/v2/internal/depsdev"
 	"github.com/google/osv-scanner/v2/internal/grouper"
+	"github.com/google/osv-scanner/v2/internal/imodels"
 	"github.com/google/osv-scanner/v2/internal/imodels/results"
 	"github.com/google/osv-scanner/v2/internal/output"
 	"github.com/google/osv-scanner/v2/internal/sourceanalysis"
-	"github.com/google/osv-scanner/v2/internal/spdx"
+	"github.com/google/osv-scanner/v2/internal/utility/vulns"
 	"github.com/google/osv-scanner/v2/pkg/models"
 	"github.com/google/osv-scanner/v2/pkg/osvscanner/internal/imagehelpers"
 	"github.com/ossf/osv-schema/bindings/go/osvconstants"
@@ -33,6 +35,9 @@ func buildVulnerabilityResults(
 		Results:                     []models.PackageSource{},
 		ImageMetadata:               imagehelpers.BuildImageMetadata(scanResults),
 		ExperimentalGenericFindings: scanResults.GenericFindings,
+		ExperimentalDegradations:    scanResults.Degradations,
+		ExperimentalUnresolved:      scanResults.UnresolvedPackages,
+		ExperimentalDrift:           scanResults.Drift,
 	}
 
 	type packageVulnsGroup struct {
@@ -41,6 +46,8 @@ func buildVulnerabilityResults(
 	}
 
 	groupedBySource := map[models.SourceInfo]*packageVulnsGroup{}
+	// depsBySource holds every package found at each source, for call analysis
+	depsBySource := map[models.SourceInfo][]sourceanalysis.Dependency{}
 
 	for i, psr := range scanResults.PackageScanResults {
 		p := psr.PackageInfo
@@ -58,18 +65,65 @@ func buildVulnerabilityResults(
 		pkg.Package.Version = p.Version()
 		pkg.Package.Ecosystem = p.Ecosystem().String()
 		pkg.Package.OSPackageName = p.OSPackageName()
-		pkg.Package.Deprecated = p.Deprecated
+		pkg.Package.Deprecated = p.Deprecated || psr.DeprecationReason != ""
+		pkg.Package.DeprecationReason = psr.DeprecationReason
+		if declared := p.Declared(); declared != nil {
+			pkg.Package.DeclaredVersion = declared.Version
+			pkg.Package.DeclaredRequirement = declared.Requirement
+		}
 
 		if pkg.Package.Deprecated {
 			includePackage = true
 		}
 
-		if psr.PackageInfo.LayerMetadata != nil {
-			pkg.Package.ImageOrigin = &models.ImageOriginDetails{
-				Index: psr.PackageInfo.LayerMetadata.Index,
+		if psr.Scorecard != nil {
+			pkg.Scorecard = psr.Scorecard
+			if psr.Scorecard.OverallScore < actions.FailOnScorecardBelow {
+				pkg.ScorecardViolation = true
+				includePackage = true
 			}
 		}
+
+		pkg.Dependents = psr.Dependents
+
+		if psr.Release != nil {
+			pkg.Release = psr.Release
+			if actions.Outdated && isOutdated(*psr.Release, actions.OutdatedMajorVersions, actions.OutdatedMonths) {
+				pkg.Outdated = true
+				includePackage = true
+			}
+		}
+
+		if len(psr.TyposquatOf) > 0 {
+			pkg.TyposquatOf = psr.TyposquatOf
+			includePackage = true
+		}
+
+		if psr.Provenance != nil {
+			pkg.Provenance = psr.Provenance
+			if !psr.Provenance.Verified {
+				pkg.ProvenanceViolation = actions.FailOnMissingProvenance
+				includePackage = true
+			}
+		}
+
+		if len(psr.HashMismatches) > 0 {
+			pkg.HashMismatches = psr.HashMismatches
+			includePackage = true
+		}
+
+		if psr.PackageInfo.LayerMetadata != nil {
+			pkg.Package.ImageOrigin = imagehelpers.BuildImageOrigin(vulnResults.ImageMetadata, psr.PackageInfo.LayerMetadata.Index)
+		}
 		pkg.DepGroups = p.DepGroups()
+		pkg.IntroducedBy = p.IntroducedBy()
+		for _, parent := range p.Parents() {
+			pkg.Parents = append(pkg.Parents, models.PackageParent{
+				Name:        parent.Name,
+				Version:     parent.Version,
+				Requirement: parent.Requirement,
+			})
+		}
 		configToUse := scanResults.ConfigManager.Get(p.Location())
 
 		if len(psr.Vulnerabilities) > 0 {
@@ -79,6 +133,8 @@ func buildVulnerabilityResults(
 				pkg.Groups = grouper.Group(grouper.ConvertVulnerabilityToIDAliases(pkg.Vulnerabilities))
 				for i, group := range pkg.Groups {
 					pkg.Groups[i].MaxSeverity = output.MaxSeverity(group, pkg)
+					pkg.Groups[i].FixedVersion = output.FixedVersion(group, pkg)
+					pkg.Groups[i].Malicious = slices.ContainsFunc(group.Aliases, vulns.IsMalicious)
 				}
 			}
 		}
@@ -94,7 +150,8 @@ func buildVulnerabilityResults(
 			setUncalled(&pkg)
 		}
 
-		if actions.ScanLicensesSummary || len(actions.ScanLicensesAllowlist) > 0 {
+		policy := licensePolicy(actions, configToUse)
+		if actions.ScanLicensesSummary || !policy.IsEmpty() {
 			if override, entry := configToUse.ShouldOverridePackageLicense(p); override {
 				if entry.License.Ignore {
 					cmdlogger.Infof("ignoring license for package %s/%s/%s", pkg.Package.Ecosystem, pkg.Package.Name, pkg.Package.Version)
@@ -108,16 +165,16 @@ func buildVulnerabilityResults(
 					psr.Licenses = overrideLicenses
 				}
 			}
-			if len(actions.ScanLicensesAllowlist) > 0 {
+			if !policy.IsEmpty() {
 				pkg.Licenses = psr.Licenses
 				for _, license := range pkg.Licenses {
-					satisfies, err := spdx.Satisfies(license, actions.ScanLicensesAllowlist)
+					violates, err := violatesLicensePolicy(license, policy)
 
 					if err != nil {
 						cmdlogger.Errorf("license %s for package %s/%s/%s is invalid: %s", license, pkg.Package.Ecosystem, pkg.Package.Name, pkg.Package.Version, err)
 					}
 
-					if !satisfies {
+					if violates {
 						pkg.LicenseViolations = append(pkg.LicenseViolations, license)
 					}
 				}
@@ -133,19 +190,17 @@ func buildVulnerabilityResults(
 			// Make sure licenses are overridden in the scan results.
 			scanResults.PackageScanResults[i] = psr
 		}
+		source := packageSource(p)
+		dep := sourceanalysis.Dependency{
+			Name:       p.Name(),
+			Version:    p.Version(),
+			Transitive: p.IsTransitive(),
+		}
+		for _, parent := range p.Parents() {
+			dep.Parents = append(dep.Parents, parent.Name)
+		}
+		depsBySource[source] = append(depsBySource[source], dep)
 		if includePackage {
-			source := models.SourceInfo{
-				Path: filepath.ToSlash(p.Location()),
-				Type: p.SourceType(),
-			}
-
-			if slices.Contains(p.Plugins, cdx.Name) {
-				locations := p.Metadata.(*cdxmeta.Metadata).CDXLocations
-				if len(locations) > 0 {
-					source.Path = source.Path + ":" + locations[0]
-				}
-			}
-
 			if groupedBySource[source] == nil {
 				groupedBySource[source] = &packageVulnsGroup{}
 			}
@@ -158,7 +213,7 @@ func buildVulnerabilityResults(
 
 	// TODO(v2): Move source analysis out of here.
 	for source, packages := range groupedBySource {
-		sourceanalysis.Run(source, packages.pvs, actions.CallAnalysisStates)
+		sourceanalysis.Run(source, packages.pvs, depsBySource[source], actions.CallAnalysisStates)
 		vulnResults.Results = append(vulnResults.Results, models.PackageSource{
 			Source:          source,
 			ExperimentalPES: packages.annotations,
@@ -174,18 +229,48 @@ func buildVulnerabilityResults(
 		return vulnResults.Results[i].Source.Path < vulnResults.Results[j].Source.Path
 	})
 
-	if len(actions.ScanLicensesAllowlist) > 0 || actions.ScanLicensesSummary {
+	if policy := reportedLicensePolicy(actions, &scanResults.ConfigManager); actions.ScanLicensesSummary || !policy.IsEmpty() {
 		vulnResults.ExperimentalAnalysisConfig.Licenses.Summary = actions.ScanLicensesSummary
-		allowlist := make([]models.License, len(actions.ScanLicensesAllowlist))
-		for i, l := range actions.ScanLicensesAllowlist {
+		allowlist := make([]models.License, len(policy.Allowlist))
+		for i, l := range policy.Allowlist {
 			allowlist[i] = models.License(l)
 		}
 		vulnResults.ExperimentalAnalysisConfig.Licenses.Allowlist = allowlist
+		for _, l := range policy.Denylist {
+			vulnResults.ExperimentalAnalysisConfig.Licenses.Denylist = append(vulnResults.ExperimentalAnalysisConfig.Licenses.Denylist, models.License(l))
+		}
+	}
+
+	// Only document the conflict strategy when it drops versions, which report-all never does
+	transitive := actions.TransitiveScanning
+	if !transitive.Disabled && !transitive.NativeDataSource {
+		if strategy, err := depsdev.ParseConflictStrategy(transitive.ConflictStrategy); err == nil && strategy != depsdev.ConflictReportAll {
+			vulnResults.ExperimentalAnalysisConfig.TransitiveScanning = &models.ExperimentalTransitiveScanningConfig{
+				VersionConflicts: string(strategy),
+			}
+		}
 	}
 
 	return vulnResults
 }
 
+// packageSource returns where a package was found.
+func packageSource(p imodels.PackageInfo) models.SourceInfo {
+	source := models.SourceInfo{
+		Path: filepath.ToSlash(p.Location()),
+		Type: p.SourceType(),
+	}
+
+	// the dependencies that enrichment adds to an SBOM have no CycloneDX metadata
+	if md, ok := p.Metadata.(*cdxmeta.Metadata); ok && slices.Contains(p.Plugins, cdx.Name) {
+		if len(md.CDXLocations) > 0 {
+			source.Path = source.Path + ":" + md.CDXLocations[0]
+		}
+	}
+
+	return source
+}
+
 func setUncalled(pv *models.PackageVulns) {
 	// Use index to keep reference to original element in slice
 	for groupIdx := range pv.Groups {
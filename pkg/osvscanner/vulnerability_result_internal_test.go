@@ -1,8 +1,11 @@
 package osvscanner
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/osv-scalibr/extractor"
 	"github.com/google/osv-scalibr/extractor/filesystem/language/javascript/packagelockjson"
 	"github.com/google/osv-scalibr/purl"
@@ -12,6 +15,7 @@ import (
 	"github.com/google/osv-scanner/v2/internal/testutility"
 	"github.com/google/osv-scanner/v2/pkg/models"
 	"github.com/ossf/osv-schema/bindings/go/osvschema"
+	"google.golang.org/protobuf/testing/protocmp"
 )
 
 // makeScanResults returns a unique instance of results.ScanResults for use in tests,
@@ -179,3 +183,271 @@ func Test_assembleResult(t *testing.T) {
 		})
 	}
 }
+
+func Test_buildVulnerabilityResults_Malicious(t *testing.T) {
+	t.Parallel()
+
+	scanResults := makeScanResults()
+	scanResults.PackageScanResults[2].Vulnerabilities = []*osvschema.Vulnerability{
+		{Id: "MAL-2024-1234", Aliases: []string{"GHSA-456"}},
+		{Id: "GHSA-456"},
+	}
+
+	vulnResults := buildVulnerabilityResults(ScannerActions{}, scanResults)
+
+	got := make(map[string]bool)
+	for _, source := range vulnResults.Results {
+		for _, pkg := range source.Packages {
+			for _, group := range pkg.Groups {
+				got[group.IndexString()] = group.Malicious
+			}
+		}
+	}
+	want := map[string]bool{
+		"CVE-123,GHSA-123":       false,
+		"MAL-2024-1234,GHSA-456": true,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("buildVulnerabilityResults() malicious groups mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// singlePackageResults returns the results of a single package found in a
+// lockfile.
+func singlePackageResults(pkg models.PackageVulns) models.VulnerabilityResults {
+	return models.VulnerabilityResults{
+		Results: []models.PackageSource{{
+			Source:   models.SourceInfo{Path: "/path/to/package-lock.json", Type: models.SourceTypeProjectPackage},
+			Packages: []models.PackageVulns{pkg},
+		}},
+	}
+}
+
+func Test_determineReturnErr(t *testing.T) {
+	t.Parallel()
+
+	leftPad := models.PackageInfo{Name: "left-pad", Version: "1.3.0", Ecosystem: "npm"}
+
+	withKEV := kevTestResults()
+	withKEV.Results[0].Packages[0].Groups[0].KEV = &models.KEVEntry{CVE: "CVE-2021-44228"}
+
+	malicious := singlePackageResults(models.PackageVulns{
+		Package:         models.PackageInfo{Name: "evil", Version: "1.0.0", Ecosystem: "npm"},
+		Vulnerabilities: []*osvschema.Vulnerability{{Id: "MAL-2024-1234"}},
+		Groups: []models.GroupInfo{{
+			IDs:       []string{"MAL-2024-1234"},
+			Aliases:   []string{"MAL-2024-1234"},
+			Malicious: true,
+			// Malicious packages fail the scan even when marked as uncalled
+			ExperimentalAnalysis: map[string]models.AnalysisInfo{
+				"MAL-2024-1234": {Called: false},
+			},
+		}},
+	})
+
+	tests := []struct {
+		name    string
+		results models.VulnerabilityResults
+		kevOnly bool
+		wantErr bool
+	}{
+		{
+			name: "scorecard_above_threshold",
+			results: singlePackageResults(models.PackageVulns{
+				Package:   leftPad,
+				Scorecard: &models.Scorecard{Project: "github.com/left-pad/left-pad", OverallScore: 3.1},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "scorecard_below_threshold",
+			results: singlePackageResults(models.PackageVulns{
+				Package:            leftPad,
+				Scorecard:          &models.Scorecard{Project: "github.com/left-pad/left-pad", OverallScore: 3.1},
+				ScorecardViolation: true,
+			}),
+			wantErr: true,
+		},
+		{
+			name: "scorecard_below_threshold_kev_only",
+			results: singlePackageResults(models.PackageVulns{
+				Package:            leftPad,
+				Scorecard:          &models.Scorecard{Project: "github.com/left-pad/left-pad", OverallScore: 3.1},
+				ScorecardViolation: true,
+			}),
+			kevOnly: true,
+			wantErr: true,
+		},
+		{
+			name: "missing_provenance_not_required",
+			results: singlePackageResults(models.PackageVulns{
+				Package:    leftPad,
+				Provenance: &models.Provenance{},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "missing_provenance_required",
+			results: singlePackageResults(models.PackageVulns{
+				Package:             leftPad,
+				Provenance:          &models.Provenance{},
+				ProvenanceViolation: true,
+			}),
+			wantErr: true,
+		},
+		{
+			name: "hash_mismatch",
+			results: singlePackageResults(models.PackageVulns{
+				Package:        models.PackageInfo{Name: "requests", Version: "2.31.0", Ecosystem: "PyPI"},
+				HashMismatches: []string{"sha256:0000000000000000000000000000000000000000000000000000000000000000"},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "drift",
+			results: models.VulnerabilityResults{
+				ExperimentalDrift: []models.Drift{{
+					Manifest:  "/path/to/package.json",
+					Lockfile:  "/path/to/package-lock.json",
+					Ecosystem: "npm",
+					Name:      "lodash",
+					Declared:  "^4.17.21",
+					Reason:    models.DriftMissing,
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "vulnerabilities",
+			results: kevTestResults(),
+			wantErr: true,
+		},
+		{
+			name:    "vulnerabilities_without_kev_entries_kev_only",
+			results: kevTestResults(),
+			kevOnly: true,
+			wantErr: false,
+		},
+		{
+			name:    "vulnerabilities_with_kev_entry_kev_only",
+			results: withKEV,
+			kevOnly: true,
+			wantErr: true,
+		},
+		{
+			name:    "malicious",
+			results: malicious,
+			wantErr: true,
+		},
+		{
+			name:    "malicious_kev_only",
+			results: malicious,
+			kevOnly: true,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := determineReturnErr(tt.results, false, tt.kevOnly)
+			if tt.wantErr && !errors.Is(err, ErrVulnerabilitiesFound) {
+				t.Errorf("determineReturnErr() = %v, want %v", err, ErrVulnerabilitiesFound)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("determineReturnErr() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+// batchRecordingMatcher reports a vulnerability for every package, and records
+// the names of the packages matched by each call.
+type batchRecordingMatcher struct {
+	batches [][]string
+}
+
+func (m *batchRecordingMatcher) MatchVulnerabilities(_ context.Context, invs []*extractor.Package) ([][]*osvschema.Vulnerability, error) {
+	batch := make([]string, 0, len(invs))
+	results := make([][]*osvschema.Vulnerability, len(invs))
+	for i, inv := range invs {
+		batch = append(batch, inv.Name)
+		results[i] = []*osvschema.Vulnerability{{Id: "GHSA-" + inv.Name}}
+	}
+	m.batches = append(m.batches, batch)
+
+	return results, nil
+}
+
+func Test_streamScanResult(t *testing.T) {
+	t.Parallel()
+
+	unmatched := func() results.ScanResults {
+		scanResults := makeScanResults()
+		for i := range scanResults.PackageScanResults {
+			scanResults.PackageScanResults[i].Vulnerabilities = nil
+		}
+
+		return *scanResults
+	}
+
+	matcher := &batchRecordingMatcher{}
+	var streamed []string
+	actions := ScannerActions{
+		OnSourceResults: func(source models.PackageSource) error {
+			// each source is passed on before the next one is matched
+			if len(matcher.batches) != len(streamed)+1 {
+				t.Errorf("source %s streamed after %d batches were matched, want %d", source.Source.Path, len(matcher.batches), len(streamed)+1)
+			}
+			streamed = append(streamed, source.Source.Path)
+
+			return nil
+		},
+	}
+
+	got, err := streamScanResult(unmatched(), nil, matcher, nil, actions)
+	if !errors.Is(err, ErrVulnerabilitiesFound) {
+		t.Fatalf("streamScanResult() error = %v, want %v", err, ErrVulnerabilitiesFound)
+	}
+
+	wantBatches := [][]string{{"pkg-1", "pkg-2"}, {"pkg-3"}}
+	if diff := cmp.Diff(wantBatches, matcher.batches); diff != "" {
+		t.Errorf("streamScanResult() matched batches mismatch (-want +got):\n%s", diff)
+	}
+	wantStreamed := []string{"dir/package-lock.json", "other-dir/package-lock.json"}
+	if diff := cmp.Diff(wantStreamed, streamed); diff != "" {
+		t.Errorf("streamScanResult() streamed sources mismatch (-want +got):\n%s", diff)
+	}
+
+	// the results are the same as when every package is matched at once
+	scanResult := unmatched()
+	if err := makeVulnRequestWithMatcher(scanResult.PackageScanResults, &batchRecordingMatcher{}); err != nil {
+		t.Fatalf("makeVulnRequestWithMatcher() error = %v", err)
+	}
+	want, _ := finalizeScanResult(scanResult, ScannerActions{})
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("streamScanResult() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_streamScanResult_Error(t *testing.T) {
+	t.Parallel()
+
+	errWrite := errors.New("write failed")
+	calls := 0
+	actions := ScannerActions{
+		OnSourceResults: func(models.PackageSource) error {
+			calls++
+			return errWrite
+		},
+	}
+
+	_, err := streamScanResult(*makeScanResults(), nil, nil, nil, actions)
+	if !errors.Is(err, errWrite) {
+		t.Errorf("streamScanResult() error = %v, want %v", err, errWrite)
+	}
+	if calls != 1 {
+		t.Errorf("OnSourceResults called %d times, want 1", calls)
+	}
+}